@@ -10,15 +10,89 @@ SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,N
 CREATE SCHEMA IF NOT EXISTS `turnos-odontologia` DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci ;
 USE `turnos-odontologia`;
 
+-- Databases created by an earlier version of this script keep their tables
+-- as they were: run it, then `go run ./cmd/migrate` to upgrade them.
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`tenants`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`tenants` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `Name` VARCHAR(45) NOT NULL,
+  `TokenHash` CHAR(64) NOT NULL,
+  `CreatedAt` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `DisabledAt` DATETIME NULL DEFAULT NULL COMMENT 'tenants are disabled, never deleted, so their records are kept',
+  PRIMARY KEY (`Id`),
+  UNIQUE INDEX `TokenHash_UNIQUE` (`TokenHash` ASC)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- Default practice, its token is the former shared TOKEN (secret-token).
+INSERT IGNORE INTO `turnos-odontologia`.`tenants` (`Id`, `Name`, `TokenHash`)
+VALUES (1, 'Default practice', '930bbdc51b6aed5c2a5678fd6e28dee7a05e8a4b643cfc0b4427c3efb86c0d94');
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`clinics`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`clinics` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `Name` VARCHAR(45) NOT NULL,
+  `Address` VARCHAR(45) NULL DEFAULT NULL,
+  `TimeZone` VARCHAR(64) NOT NULL DEFAULT 'America/Argentina/Buenos_Aires',
+  PRIMARY KEY (`Id`),
+  INDEX `idx_clinics_tenants` (`tenants_Id` ASC),
+  CONSTRAINT `fk_clinics_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
 -- -----------------------------------------------------
 -- Table `turnos-odontologia`.`dentists`
 -- -----------------------------------------------------
 CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`dentists` (
   `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
   `FirstName` VARCHAR(45) NULL DEFAULT NULL,
   `LastName` VARCHAR(45) NULL DEFAULT NULL,
   `License` VARCHAR(45) NULL DEFAULT NULL,
-  PRIMARY KEY (`Id`)
+  PRIMARY KEY (`Id`),
+  INDEX `idx_dentists_tenants` (`tenants_Id` ASC),
+  CONSTRAINT `fk_dentists_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`dentist_schedules`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`dentist_schedules` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `dentists_Id` INT NOT NULL,
+  `clinics_Id` INT NOT NULL,
+  `Weekday` TINYINT NOT NULL,
+  `StartHour` VARCHAR(5) NOT NULL,
+  `EndHour` VARCHAR(5) NOT NULL,
+  PRIMARY KEY (`Id`),
+  INDEX `idx_dentist_schedules_tenants` (`tenants_Id` ASC),
+  INDEX `idx_dentist_schedules_dentist_weekday` (`dentists_Id` ASC, `Weekday` ASC),
+  CONSTRAINT `fk_dentist_schedules_dentists`
+    FOREIGN KEY (`dentists_Id`)
+    REFERENCES `turnos-odontologia`.`dentists` (`Id`)
+    ON DELETE CASCADE,
+  CONSTRAINT `fk_dentist_schedules_clinics`
+    FOREIGN KEY (`clinics_Id`)
+    REFERENCES `turnos-odontologia`.`clinics` (`Id`)
+    ON DELETE CASCADE,
+  CONSTRAINT `fk_dentist_schedules_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
 )
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = utf8mb4;
@@ -28,12 +102,79 @@ DEFAULT CHARACTER SET = utf8mb4;
 -- -----------------------------------------------------
 CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`patients` (
   `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
   `FirstName` VARCHAR(45) NULL DEFAULT NULL,
   `LastName` VARCHAR(45) NULL DEFAULT NULL,
   `Address` VARCHAR(45) NULL DEFAULT NULL,
   `DNI` VARCHAR(45) NULL DEFAULT NULL,
   `ReleaseDate` VARCHAR(45) NULL DEFAULT NULL,
-  PRIMARY KEY (`Id`)
+  `Email` VARCHAR(100) NOT NULL DEFAULT '',
+  `Phone` VARCHAR(20) NOT NULL DEFAULT '',
+  PRIMARY KEY (`Id`),
+  INDEX `idx_patients_tenants` (`tenants_Id` ASC),
+  INDEX `idx_patients_dni` (`tenants_Id` ASC, `DNI` ASC),
+  INDEX `idx_patients_name` (`tenants_Id` ASC, `LastName` ASC, `FirstName` ASC),
+  FULLTEXT INDEX `ft_patients_search` (`FirstName`, `LastName`, `DNI`, `Address`),
+  CONSTRAINT `fk_patients_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`specialties`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`specialties` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `Name` VARCHAR(45) NOT NULL,
+  PRIMARY KEY (`Id`),
+  UNIQUE INDEX `Name_UNIQUE` (`tenants_Id` ASC, `Name` ASC),
+  CONSTRAINT `fk_specialties_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`dentists_specialties`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`dentists_specialties` (
+  `dentists_Id` INT NOT NULL,
+  `specialties_Id` INT NOT NULL,
+  PRIMARY KEY (`dentists_Id`, `specialties_Id`),
+  CONSTRAINT `fk_dentists_specialties_dentists`
+    FOREIGN KEY (`dentists_Id`)
+    REFERENCES `turnos-odontologia`.`dentists` (`Id`)
+    ON DELETE CASCADE,
+  CONSTRAINT `fk_dentists_specialties_specialties`
+    FOREIGN KEY (`specialties_Id`)
+    REFERENCES `turnos-odontologia`.`specialties` (`Id`)
+    ON DELETE CASCADE
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`treatments`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`treatments` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `Name` VARCHAR(45) NOT NULL,
+  `specialties_Id` INT NULL DEFAULT NULL,
+  `Price` BIGINT NOT NULL DEFAULT 0 COMMENT 'cents',
+  `TaxRate` INT NOT NULL DEFAULT 0 COMMENT 'basis points',
+  PRIMARY KEY (`Id`),
+  INDEX `idx_treatments_tenants` (`tenants_Id` ASC),
+  CONSTRAINT `fk_treatments_specialties`
+    FOREIGN KEY (`specialties_Id`)
+    REFERENCES `turnos-odontologia`.`specialties` (`Id`),
+  CONSTRAINT `fk_treatments_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
 )
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = utf8mb4;
@@ -43,22 +184,791 @@ DEFAULT CHARACTER SET = utf8mb4;
 -- -----------------------------------------------------
 CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`appointments` (
   `Id` INT NOT NULL AUTO_INCREMENT,
-  `Date` VARCHAR(45) NULL DEFAULT NULL,
-  `Hour` VARCHAR(45) NULL DEFAULT NULL,
+  `tenants_Id` INT NOT NULL,
+  `StartsAt` DATETIME NOT NULL COMMENT 'UTC',
   `Description` VARCHAR(45) NULL DEFAULT NULL,
   `patients_Id` INT NOT NULL,
   `dentists_Id` INT NOT NULL,
+  `treatments_Id` INT NULL DEFAULT NULL,
+  `clinics_Id` INT NULL DEFAULT NULL,
+  `plan_steps_Id` INT NULL DEFAULT NULL,
+  `CompletedAt` DATETIME NULL DEFAULT NULL COMMENT 'UTC',
+  `CheckedInAt` DATETIME NULL DEFAULT NULL COMMENT 'UTC',
   PRIMARY KEY (`Id`),
+  INDEX `idx_appointments_tenants` (`tenants_Id` ASC),
+  INDEX `idx_appointments_starts_at` (`tenants_Id` ASC, `StartsAt` ASC),
+  INDEX `idx_appointments_plan_steps` (`plan_steps_Id` ASC),
   CONSTRAINT `fk_appointments_patients`
     FOREIGN KEY (`patients_Id`)
     REFERENCES `turnos-odontologia`.`patients` (`Id`),
   CONSTRAINT `fk_appointments_dentists1`
+    FOREIGN KEY (`dentists_Id`)
+    REFERENCES `turnos-odontologia`.`dentists` (`Id`),
+  CONSTRAINT `fk_appointments_treatments`
+    FOREIGN KEY (`treatments_Id`)
+    REFERENCES `turnos-odontologia`.`treatments` (`Id`),
+  CONSTRAINT `fk_appointments_clinics`
+    FOREIGN KEY (`clinics_Id`)
+    REFERENCES `turnos-odontologia`.`clinics` (`Id`),
+  CONSTRAINT `fk_appointments_plan_steps`
+    FOREIGN KEY (`plan_steps_Id`)
+    REFERENCES `turnos-odontologia`.`plan_steps` (`Id`),
+  CONSTRAINT `fk_appointments_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`resources`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`resources` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `Name` VARCHAR(45) NOT NULL,
+  `Kind` VARCHAR(20) NOT NULL,
+  `clinics_Id` INT NULL DEFAULT NULL,
+  PRIMARY KEY (`Id`),
+  INDEX `idx_resources_tenants` (`tenants_Id` ASC),
+  CONSTRAINT `fk_resources_clinics`
+    FOREIGN KEY (`clinics_Id`)
+    REFERENCES `turnos-odontologia`.`clinics` (`Id`),
+  CONSTRAINT `fk_resources_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`appointments_resources`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`appointments_resources` (
+  `appointments_Id` INT NOT NULL,
+  `resources_Id` INT NOT NULL,
+  PRIMARY KEY (`appointments_Id`, `resources_Id`),
+  CONSTRAINT `fk_appointments_resources_appointments`
+    FOREIGN KEY (`appointments_Id`)
+    REFERENCES `turnos-odontologia`.`appointments` (`Id`)
+    ON DELETE CASCADE,
+  CONSTRAINT `fk_appointments_resources_resources`
+    FOREIGN KEY (`resources_Id`)
+    REFERENCES `turnos-odontologia`.`resources` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`appointment_reminders`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`appointment_reminders` (
+  `tenants_Id` INT NOT NULL,
+  `appointments_Id` INT NOT NULL,
+  `OffsetMinutes` INT NOT NULL,
+  `Status` VARCHAR(10) NOT NULL,
+  `ClaimedAt` DATETIME NOT NULL,
+  `SentAt` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`appointments_Id`, `OffsetMinutes`),
+  CONSTRAINT `fk_appointment_reminders_appointments`
+    FOREIGN KEY (`appointments_Id`)
+    REFERENCES `turnos-odontologia`.`appointments` (`Id`)
+    ON DELETE CASCADE,
+  CONSTRAINT `fk_appointment_reminders_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`outbox_events`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`outbox_events` (
+  `Id` BIGINT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `AggregateType` VARCHAR(30) NOT NULL,
+  `AggregateId` INT NOT NULL,
+  `Type` VARCHAR(50) NOT NULL,
+  `Payload` JSON NOT NULL,
+  `CreatedAt` DATETIME(6) NOT NULL,
+  `Status` VARCHAR(10) NOT NULL DEFAULT 'pending',
+  `Attempts` INT NOT NULL DEFAULT 0,
+  `NextAttemptAt` DATETIME(6) NOT NULL,
+  `ProcessedAt` DATETIME(6) NULL DEFAULT NULL,
+  `LastError` TEXT NULL DEFAULT NULL,
+  PRIMARY KEY (`Id`),
+  INDEX `idx_outbox_events_pending` (`Status` ASC, `NextAttemptAt` ASC),
+  INDEX `idx_outbox_events_tenants` (`tenants_Id` ASC, `Id` ASC),
+  CONSTRAINT `fk_outbox_events_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`outbox_deliveries`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`outbox_deliveries` (
+  `events_Id` BIGINT NOT NULL,
+  `Handler` VARCHAR(50) NOT NULL,
+  `DeliveredAt` DATETIME(6) NOT NULL,
+  PRIMARY KEY (`events_Id`, `Handler`),
+  CONSTRAINT `fk_outbox_deliveries_events`
+    FOREIGN KEY (`events_Id`)
+    REFERENCES `turnos-odontologia`.`outbox_events` (`Id`)
+    ON DELETE CASCADE
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`webhooks`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`webhooks` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `URL` VARCHAR(500) NOT NULL,
+  `Secret` VARCHAR(100) NOT NULL,
+  `EventTypes` VARCHAR(500) NOT NULL,
+  `Active` TINYINT(1) NOT NULL DEFAULT 1,
+  `CreatedAt` DATETIME(6) NOT NULL,
+  PRIMARY KEY (`Id`),
+  INDEX `idx_webhooks_tenants` (`tenants_Id` ASC),
+  CONSTRAINT `fk_webhooks_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`webhook_deliveries`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`webhook_deliveries` (
+  `Id` BIGINT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `webhooks_Id` INT NOT NULL,
+  `events_Id` BIGINT NOT NULL,
+  `Status` VARCHAR(10) NOT NULL DEFAULT 'pending',
+  `Attempts` INT NOT NULL DEFAULT 0,
+  `NextAttemptAt` DATETIME(6) NOT NULL,
+  `LastStatusCode` INT NOT NULL DEFAULT 0,
+  `LastError` TEXT NULL DEFAULT NULL,
+  `DeliveredAt` DATETIME(6) NULL DEFAULT NULL,
+  `CreatedAt` DATETIME(6) NOT NULL,
+  PRIMARY KEY (`Id`),
+  UNIQUE INDEX `webhook_event_UNIQUE` (`webhooks_Id` ASC, `events_Id` ASC),
+  INDEX `idx_webhook_deliveries_pending` (`Status` ASC, `NextAttemptAt` ASC),
+  CONSTRAINT `fk_webhook_deliveries_webhooks`
+    FOREIGN KEY (`webhooks_Id`)
+    REFERENCES `turnos-odontologia`.`webhooks` (`Id`)
+    ON DELETE CASCADE,
+  CONSTRAINT `fk_webhook_deliveries_events`
+    FOREIGN KEY (`events_Id`)
+    REFERENCES `turnos-odontologia`.`outbox_events` (`Id`)
+    ON DELETE CASCADE,
+  CONSTRAINT `fk_webhook_deliveries_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`webhook_attempts`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`webhook_attempts` (
+  `Id` BIGINT NOT NULL AUTO_INCREMENT,
+  `deliveries_Id` BIGINT NOT NULL,
+  `StatusCode` INT NOT NULL DEFAULT 0,
+  `Error` TEXT NULL DEFAULT NULL,
+  `DurationMs` INT NOT NULL,
+  `CreatedAt` DATETIME(6) NOT NULL,
+  PRIMARY KEY (`Id`),
+  INDEX `idx_webhook_attempts_deliveries` (`deliveries_Id` ASC),
+  CONSTRAINT `fk_webhook_attempts_deliveries`
+    FOREIGN KEY (`deliveries_Id`)
+    REFERENCES `turnos-odontologia`.`webhook_deliveries` (`Id`)
+    ON DELETE CASCADE
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`calendar_tokens`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`calendar_tokens` (
+  `tenants_Id` INT NOT NULL,
+  `OwnerType` VARCHAR(10) NOT NULL,
+  `OwnerId` INT NOT NULL,
+  `TokenHash` CHAR(64) NOT NULL,
+  `CreatedAt` DATETIME NOT NULL,
+  PRIMARY KEY (`tenants_Id`, `OwnerType`, `OwnerId`),
+  UNIQUE INDEX `TokenHash_UNIQUE` (`TokenHash` ASC),
+  CONSTRAINT `fk_calendar_tokens_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`medical_history`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`medical_history` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `patients_Id` INT NOT NULL,
+  `Kind` VARCHAR(20) NOT NULL,
+  `Name` VARCHAR(100) NOT NULL,
+  `Severity` VARCHAR(10) NOT NULL DEFAULT '',
+  `Alert` TINYINT(1) NOT NULL DEFAULT 0,
+  `Notes` TEXT NOT NULL,
+  `StartDate` VARCHAR(10) NOT NULL DEFAULT '',
+  `EndDate` VARCHAR(10) NOT NULL DEFAULT '',
+  `RecordedBy` VARCHAR(100) NOT NULL,
+  `RecordedAt` DATETIME NOT NULL,
+  `UpdatedAt` DATETIME NOT NULL,
+  PRIMARY KEY (`Id`),
+  INDEX `idx_medical_history_patients` (`tenants_Id` ASC, `patients_Id` ASC),
+  CONSTRAINT `fk_medical_history_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_medical_history_patients`
+    FOREIGN KEY (`patients_Id`)
+    REFERENCES `turnos-odontologia`.`patients` (`Id`)
+    ON DELETE CASCADE
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`clinical_notes`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`clinical_notes` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `appointments_Id` INT NOT NULL,
+  `patients_Id` INT NOT NULL,
+  `dentists_Id` INT NOT NULL,
+  `AmendsId` INT NULL DEFAULT NULL,
+  `Text` TEXT NOT NULL,
+  `Reason` VARCHAR(255) NOT NULL DEFAULT '',
+  `CreatedAt` DATETIME NOT NULL,
+  PRIMARY KEY (`Id`),
+  INDEX `idx_clinical_notes_appointments` (`tenants_Id` ASC, `appointments_Id` ASC),
+  INDEX `idx_clinical_notes_patients` (`tenants_Id` ASC, `patients_Id` ASC),
+  CONSTRAINT `fk_clinical_notes_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_clinical_notes_appointments`
+    FOREIGN KEY (`appointments_Id`)
+    REFERENCES `turnos-odontologia`.`appointments` (`Id`),
+  CONSTRAINT `fk_clinical_notes_patients`
+    FOREIGN KEY (`patients_Id`)
+    REFERENCES `turnos-odontologia`.`patients` (`Id`),
+  CONSTRAINT `fk_clinical_notes_dentists`
+    FOREIGN KEY (`dentists_Id`)
+    REFERENCES `turnos-odontologia`.`dentists` (`Id`),
+  CONSTRAINT `fk_clinical_notes_amends`
+    FOREIGN KEY (`AmendsId`)
+    REFERENCES `turnos-odontologia`.`clinical_notes` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`tooth_findings`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`tooth_findings` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `patients_Id` INT NOT NULL,
+  `appointments_Id` INT NOT NULL,
+  `dentists_Id` INT NOT NULL,
+  `Tooth` TINYINT UNSIGNED NOT NULL,
+  `Surfaces` VARCHAR(5) NOT NULL DEFAULT '',
+  `ToothCondition` VARCHAR(16) NOT NULL,
+  `Notes` VARCHAR(255) NOT NULL DEFAULT '',
+  `ExaminedAt` DATETIME NOT NULL,
+  PRIMARY KEY (`Id`),
+  INDEX `idx_tooth_findings_patients` (`tenants_Id` ASC, `patients_Id` ASC, `ExaminedAt` ASC),
+  CONSTRAINT `fk_tooth_findings_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_tooth_findings_patients`
+    FOREIGN KEY (`patients_Id`)
+    REFERENCES `turnos-odontologia`.`patients` (`Id`),
+  CONSTRAINT `fk_tooth_findings_appointments`
+    FOREIGN KEY (`appointments_Id`)
+    REFERENCES `turnos-odontologia`.`appointments` (`Id`),
+  CONSTRAINT `fk_tooth_findings_dentists`
     FOREIGN KEY (`dentists_Id`)
     REFERENCES `turnos-odontologia`.`dentists` (`Id`)
 )
 ENGINE = InnoDB
 DEFAULT CHARACTER SET = utf8mb4;
 
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`treatment_plans`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`treatment_plans` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `patients_Id` INT NOT NULL,
+  `Title` VARCHAR(100) NOT NULL,
+  `Notes` VARCHAR(500) NOT NULL DEFAULT '',
+  `Status` VARCHAR(16) NOT NULL DEFAULT 'active',
+  `CreatedAt` DATETIME NOT NULL,
+  PRIMARY KEY (`Id`),
+  INDEX `idx_treatment_plans_patients` (`tenants_Id` ASC, `patients_Id` ASC),
+  CONSTRAINT `fk_treatment_plans_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_treatment_plans_patients`
+    FOREIGN KEY (`patients_Id`)
+    REFERENCES `turnos-odontologia`.`patients` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`plan_steps`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`plan_steps` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `treatment_plans_Id` INT NOT NULL,
+  `Position` INT NOT NULL,
+  `treatments_Id` INT NULL DEFAULT NULL,
+  `Description` VARCHAR(255) NOT NULL,
+  `EstimatedCost` BIGINT NOT NULL DEFAULT 0 COMMENT 'cents',
+  `Skipped` TINYINT(1) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`Id`),
+  UNIQUE INDEX `uq_plan_steps_position` (`treatment_plans_Id` ASC, `Position` ASC),
+  CONSTRAINT `fk_plan_steps_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_plan_steps_treatment_plans`
+    FOREIGN KEY (`treatment_plans_Id`)
+    REFERENCES `turnos-odontologia`.`treatment_plans` (`Id`),
+  CONSTRAINT `fk_plan_steps_treatments`
+    FOREIGN KEY (`treatments_Id`)
+    REFERENCES `turnos-odontologia`.`treatments` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`attachments`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`attachments` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `patients_Id` INT NOT NULL,
+  `appointments_Id` INT NULL DEFAULT NULL,
+  `Kind` VARCHAR(16) NOT NULL,
+  `FileName` VARCHAR(255) NOT NULL,
+  `ContentType` VARCHAR(100) NOT NULL,
+  `Size` BIGINT NOT NULL,
+  `Checksum` CHAR(64) NOT NULL COMMENT 'hex SHA-256',
+  `Description` VARCHAR(255) NOT NULL DEFAULT '',
+  `UploadedAt` DATETIME NOT NULL,
+  `StorageKey` VARCHAR(255) NOT NULL,
+  PRIMARY KEY (`Id`),
+  INDEX `idx_attachments_patients` (`tenants_Id` ASC, `patients_Id` ASC),
+  INDEX `idx_attachments_appointments` (`tenants_Id` ASC, `appointments_Id` ASC),
+  CONSTRAINT `fk_attachments_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_attachments_patients`
+    FOREIGN KEY (`patients_Id`)
+    REFERENCES `turnos-odontologia`.`patients` (`Id`),
+  CONSTRAINT `fk_attachments_appointments`
+    FOREIGN KEY (`appointments_Id`)
+    REFERENCES `turnos-odontologia`.`appointments` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`consent_templates`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`consent_templates` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `Title` VARCHAR(255) NOT NULL,
+  `Body` TEXT NOT NULL,
+  `Active` TINYINT(1) NOT NULL DEFAULT 1,
+  `UpdatedAt` DATETIME NOT NULL,
+  PRIMARY KEY (`Id`),
+  INDEX `idx_consent_templates_tenants` (`tenants_Id` ASC),
+  CONSTRAINT `fk_consent_templates_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`consent_template_treatments`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`consent_template_treatments` (
+  `consent_templates_Id` INT NOT NULL,
+  `treatments_Id` INT NOT NULL,
+  PRIMARY KEY (`consent_templates_Id`, `treatments_Id`),
+  INDEX `idx_consent_template_treatments_treatments` (`treatments_Id` ASC),
+  CONSTRAINT `fk_consent_template_treatments_consent_templates`
+    FOREIGN KEY (`consent_templates_Id`)
+    REFERENCES `turnos-odontologia`.`consent_templates` (`Id`),
+  CONSTRAINT `fk_consent_template_treatments_treatments`
+    FOREIGN KEY (`treatments_Id`)
+    REFERENCES `turnos-odontologia`.`treatments` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`consents`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`consents` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `appointments_Id` INT NOT NULL,
+  `patients_Id` INT NOT NULL,
+  `consent_templates_Id` INT NOT NULL,
+  `Title` VARCHAR(255) NOT NULL,
+  `Body` TEXT NOT NULL,
+  `Status` VARCHAR(16) NOT NULL,
+  `SignerName` VARCHAR(100) NOT NULL DEFAULT '',
+  `SignerRelationship` VARCHAR(45) NOT NULL DEFAULT '',
+  `SignedAt` DATETIME NULL DEFAULT NULL COMMENT 'UTC',
+  `DocumentHash` CHAR(64) NOT NULL DEFAULT '' COMMENT 'hex SHA-256 of the signed PDF',
+  `StorageKey` VARCHAR(255) NOT NULL DEFAULT '',
+  `CreatedAt` DATETIME NOT NULL,
+  PRIMARY KEY (`Id`),
+  UNIQUE INDEX `uq_consents_appointment_template` (`appointments_Id` ASC, `consent_templates_Id` ASC),
+  INDEX `idx_consents_tenants` (`tenants_Id` ASC),
+  CONSTRAINT `fk_consents_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_consents_appointments`
+    FOREIGN KEY (`appointments_Id`)
+    REFERENCES `turnos-odontologia`.`appointments` (`Id`),
+  CONSTRAINT `fk_consents_patients`
+    FOREIGN KEY (`patients_Id`)
+    REFERENCES `turnos-odontologia`.`patients` (`Id`),
+  CONSTRAINT `fk_consents_consent_templates`
+    FOREIGN KEY (`consent_templates_Id`)
+    REFERENCES `turnos-odontologia`.`consent_templates` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`invoices`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`invoices` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `patients_Id` INT NOT NULL,
+  `Number` INT NULL DEFAULT NULL COMMENT 'assigned on issue',
+  `Status` VARCHAR(16) NOT NULL,
+  `Subtotal` BIGINT NOT NULL COMMENT 'cents',
+  `Tax` BIGINT NOT NULL COMMENT 'cents',
+  `Total` BIGINT NOT NULL COMMENT 'cents',
+  `patient_coverages_Id` INT NULL DEFAULT NULL,
+  `Covered` BIGINT NOT NULL DEFAULT 0 COMMENT 'cents, the share of the insurer',
+  `Notes` VARCHAR(255) NOT NULL DEFAULT '',
+  `VoidReason` VARCHAR(255) NOT NULL DEFAULT '',
+  `CreatedAt` DATETIME NOT NULL,
+  `IssuedAt` DATETIME NULL DEFAULT NULL,
+  `PaidAt` DATETIME NULL DEFAULT NULL,
+  `VoidedAt` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`Id`),
+  UNIQUE INDEX `uq_invoices_number` (`tenants_Id` ASC, `Number` ASC),
+  INDEX `idx_invoices_patients` (`tenants_Id` ASC, `patients_Id` ASC),
+  CONSTRAINT `fk_invoices_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_invoices_patients`
+    FOREIGN KEY (`patients_Id`)
+    REFERENCES `turnos-odontologia`.`patients` (`Id`),
+  CONSTRAINT `fk_invoices_patient_coverages`
+    FOREIGN KEY (`patient_coverages_Id`)
+    REFERENCES `turnos-odontologia`.`patient_coverages` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`invoice_lines`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`invoice_lines` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `invoices_Id` INT NOT NULL,
+  `appointments_Id` INT NULL DEFAULT NULL,
+  `treatments_Id` INT NULL DEFAULT NULL,
+  `Description` VARCHAR(255) NOT NULL,
+  `Quantity` INT NOT NULL,
+  `UnitPrice` BIGINT NOT NULL COMMENT 'cents',
+  `TaxRate` INT NOT NULL COMMENT 'basis points',
+  `Subtotal` BIGINT NOT NULL COMMENT 'cents',
+  `Tax` BIGINT NOT NULL COMMENT 'cents',
+  `Total` BIGINT NOT NULL COMMENT 'cents',
+  `Covered` BIGINT NOT NULL DEFAULT 0 COMMENT 'cents, the share of the insurer',
+  PRIMARY KEY (`Id`),
+  INDEX `idx_invoice_lines_invoices` (`invoices_Id` ASC),
+  INDEX `idx_invoice_lines_appointments` (`appointments_Id` ASC),
+  CONSTRAINT `fk_invoice_lines_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_invoice_lines_invoices`
+    FOREIGN KEY (`invoices_Id`)
+    REFERENCES `turnos-odontologia`.`invoices` (`Id`),
+  CONSTRAINT `fk_invoice_lines_appointments`
+    FOREIGN KEY (`appointments_Id`)
+    REFERENCES `turnos-odontologia`.`appointments` (`Id`),
+  CONSTRAINT `fk_invoice_lines_treatments`
+    FOREIGN KEY (`treatments_Id`)
+    REFERENCES `turnos-odontologia`.`treatments` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`invoice_sequences`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`invoice_sequences` (
+  `tenants_Id` INT NOT NULL,
+  `LastNumber` INT NOT NULL,
+  PRIMARY KEY (`tenants_Id`),
+  CONSTRAINT `fk_invoice_sequences_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`payments`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`payments` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `patients_Id` INT NOT NULL,
+  `invoices_Id` INT NOT NULL,
+  `clinics_Id` INT NOT NULL,
+  `Kind` VARCHAR(16) NOT NULL,
+  `Method` VARCHAR(16) NOT NULL,
+  `Amount` BIGINT NOT NULL COMMENT 'cents, positive for refunds too',
+  `Provider` VARCHAR(32) NOT NULL DEFAULT '',
+  `Reference` VARCHAR(128) NOT NULL DEFAULT '',
+  `payments_Id` INT NULL DEFAULT NULL COMMENT 'the payment a refund gives back',
+  `Notes` VARCHAR(255) NOT NULL DEFAULT '',
+  `ReceivedAt` DATETIME NOT NULL,
+  `Status` VARCHAR(16) NOT NULL DEFAULT 'completed' COMMENT 'card refunds are pending while the provider gives the money back',
+  PRIMARY KEY (`Id`),
+  INDEX `idx_payments_invoices` (`invoices_Id` ASC),
+  INDEX `idx_payments_patients` (`tenants_Id` ASC, `patients_Id` ASC),
+  INDEX `idx_payments_clinics` (`tenants_Id` ASC, `clinics_Id` ASC, `ReceivedAt` ASC),
+  CONSTRAINT `fk_payments_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_payments_patients`
+    FOREIGN KEY (`patients_Id`)
+    REFERENCES `turnos-odontologia`.`patients` (`Id`),
+  CONSTRAINT `fk_payments_invoices`
+    FOREIGN KEY (`invoices_Id`)
+    REFERENCES `turnos-odontologia`.`invoices` (`Id`),
+  CONSTRAINT `fk_payments_clinics`
+    FOREIGN KEY (`clinics_Id`)
+    REFERENCES `turnos-odontologia`.`clinics` (`Id`),
+  CONSTRAINT `fk_payments_payments`
+    FOREIGN KEY (`payments_Id`)
+    REFERENCES `turnos-odontologia`.`payments` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`insurance_providers`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`insurance_providers` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `Name` VARCHAR(100) NOT NULL,
+  `Code` VARCHAR(32) NOT NULL DEFAULT '',
+  `Phone` VARCHAR(32) NOT NULL DEFAULT '',
+  `Email` VARCHAR(255) NOT NULL DEFAULT '',
+  `Active` TINYINT(1) NOT NULL DEFAULT 1,
+  PRIMARY KEY (`Id`),
+  UNIQUE INDEX `uq_insurance_providers_name` (`tenants_Id` ASC, `Name` ASC),
+  CONSTRAINT `fk_insurance_providers_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`coverage_rules`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`coverage_rules` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `insurance_providers_Id` INT NOT NULL,
+  `Plan` VARCHAR(32) NOT NULL DEFAULT '' COMMENT 'empty for every plan',
+  `treatments_Id` INT NOT NULL,
+  `Rate` INT NOT NULL COMMENT 'basis points',
+  `MaxAmount` BIGINT NOT NULL DEFAULT 0 COMMENT 'cents per unit, 0 for no limit',
+  PRIMARY KEY (`Id`),
+  UNIQUE INDEX `uq_coverage_rules` (`insurance_providers_Id` ASC, `Plan` ASC, `treatments_Id` ASC),
+  CONSTRAINT `fk_coverage_rules_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_coverage_rules_insurance_providers`
+    FOREIGN KEY (`insurance_providers_Id`)
+    REFERENCES `turnos-odontologia`.`insurance_providers` (`Id`),
+  CONSTRAINT `fk_coverage_rules_treatments`
+    FOREIGN KEY (`treatments_Id`)
+    REFERENCES `turnos-odontologia`.`treatments` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`patient_coverages`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`patient_coverages` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `patients_Id` INT NOT NULL,
+  `insurance_providers_Id` INT NOT NULL,
+  `Plan` VARCHAR(32) NOT NULL DEFAULT '',
+  `MemberNumber` VARCHAR(64) NOT NULL,
+  `ValidFrom` VARCHAR(10) NOT NULL,
+  `ValidTo` VARCHAR(10) NOT NULL DEFAULT '',
+  PRIMARY KEY (`Id`),
+  INDEX `idx_patient_coverages_patients` (`tenants_Id` ASC, `patients_Id` ASC),
+  CONSTRAINT `fk_patient_coverages_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_patient_coverages_patients`
+    FOREIGN KEY (`patients_Id`)
+    REFERENCES `turnos-odontologia`.`patients` (`Id`),
+  CONSTRAINT `fk_patient_coverages_insurance_providers`
+    FOREIGN KEY (`insurance_providers_Id`)
+    REFERENCES `turnos-odontologia`.`insurance_providers` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`insurance_claims`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`insurance_claims` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `invoices_Id` INT NOT NULL,
+  `patient_coverages_Id` INT NOT NULL,
+  `insurance_providers_Id` INT NOT NULL,
+  `patients_Id` INT NOT NULL,
+  `Amount` BIGINT NOT NULL COMMENT 'cents',
+  `Status` VARCHAR(16) NOT NULL,
+  `Reference` VARCHAR(64) NOT NULL DEFAULT '',
+  `Reason` VARCHAR(255) NOT NULL DEFAULT '',
+  `PaidAmount` BIGINT NOT NULL DEFAULT 0 COMMENT 'cents',
+  `SubmittedAt` DATETIME NOT NULL,
+  `ResolvedAt` DATETIME NULL DEFAULT NULL,
+  `PaidAt` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`Id`),
+  INDEX `idx_insurance_claims_invoices` (`invoices_Id` ASC),
+  INDEX `idx_insurance_claims_status` (`tenants_Id` ASC, `Status` ASC),
+  CONSTRAINT `fk_insurance_claims_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_insurance_claims_invoices`
+    FOREIGN KEY (`invoices_Id`)
+    REFERENCES `turnos-odontologia`.`invoices` (`Id`),
+  CONSTRAINT `fk_insurance_claims_patient_coverages`
+    FOREIGN KEY (`patient_coverages_Id`)
+    REFERENCES `turnos-odontologia`.`patient_coverages` (`Id`),
+  CONSTRAINT `fk_insurance_claims_insurance_providers`
+    FOREIGN KEY (`insurance_providers_Id`)
+    REFERENCES `turnos-odontologia`.`insurance_providers` (`Id`),
+  CONSTRAINT `fk_insurance_claims_patients`
+    FOREIGN KEY (`patients_Id`)
+    REFERENCES `turnos-odontologia`.`patients` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`prescriptions`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`prescriptions` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `appointments_Id` INT NOT NULL,
+  `patients_Id` INT NOT NULL,
+  `dentists_Id` INT NOT NULL,
+  `DentistName` VARCHAR(100) NOT NULL,
+  `License` VARCHAR(45) NOT NULL,
+  `Diagnosis` VARCHAR(255) NOT NULL DEFAULT '',
+  `Notes` VARCHAR(500) NOT NULL DEFAULT '',
+  `Status` VARCHAR(16) NOT NULL,
+  `Code` CHAR(14) NOT NULL,
+  `IssuedAt` DATETIME NOT NULL,
+  `VoidReason` VARCHAR(255) NOT NULL DEFAULT '',
+  `VoidedAt` DATETIME NULL DEFAULT NULL,
+  PRIMARY KEY (`Id`),
+  UNIQUE INDEX `uq_prescriptions_code` (`Code` ASC),
+  INDEX `idx_prescriptions_patients` (`tenants_Id` ASC, `patients_Id` ASC),
+  INDEX `idx_prescriptions_appointments` (`appointments_Id` ASC),
+  CONSTRAINT `fk_prescriptions_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_prescriptions_appointments`
+    FOREIGN KEY (`appointments_Id`)
+    REFERENCES `turnos-odontologia`.`appointments` (`Id`),
+  CONSTRAINT `fk_prescriptions_patients`
+    FOREIGN KEY (`patients_Id`)
+    REFERENCES `turnos-odontologia`.`patients` (`Id`),
+  CONSTRAINT `fk_prescriptions_dentists`
+    FOREIGN KEY (`dentists_Id`)
+    REFERENCES `turnos-odontologia`.`dentists` (`Id`)
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
+-- -----------------------------------------------------
+-- Table `turnos-odontologia`.`prescription_items`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `turnos-odontologia`.`prescription_items` (
+  `Id` INT NOT NULL AUTO_INCREMENT,
+  `tenants_Id` INT NOT NULL,
+  `prescriptions_Id` INT NOT NULL,
+  `Medication` VARCHAR(255) NOT NULL,
+  `Dose` VARCHAR(255) NOT NULL,
+  `Quantity` INT NOT NULL DEFAULT 1,
+  `Instructions` VARCHAR(500) NOT NULL DEFAULT '',
+  PRIMARY KEY (`Id`),
+  INDEX `idx_prescription_items_prescriptions` (`prescriptions_Id` ASC),
+  CONSTRAINT `fk_prescription_items_tenants`
+    FOREIGN KEY (`tenants_Id`)
+    REFERENCES `turnos-odontologia`.`tenants` (`Id`),
+  CONSTRAINT `fk_prescription_items_prescriptions`
+    FOREIGN KEY (`prescriptions_Id`)
+    REFERENCES `turnos-odontologia`.`prescriptions` (`Id`)
+    ON DELETE CASCADE
+)
+ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4;
+
 SET SQL_MODE=@OLD_SQL_MODE;
 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
 SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
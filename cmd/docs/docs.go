@@ -18,16 +18,267 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/appointments": {
+        "/admin/tenants": {
+            "get": {
+                "description": "This endpoint allows you to retrieve all provisioned tenants.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get all tenants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ADMIN_TOKEN",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tenants",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Tenant"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid admin token"
+                    },
+                    "500": {
+                        "description": "Failed to retrieve tenants"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint creates a new dental practice and returns its API token. The token is shown only once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Provision a new tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ADMIN_TOKEN",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Tenant",
+                        "name": "tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Tenant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Tenant and its token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TenantCredentials"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tenant data or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid admin token"
+                    },
+                    "500": {
+                        "description": "Failed to create tenant"
+                    }
+                }
+            }
+        },
+        "/admin/tenants/{id}": {
             "get": {
-                "description": "This endpoint allows you to retrieve all appointments.",
+                "description": "This endpoint allows you to retrieve a tenant by its ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get a tenant by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ADMIN_TOKEN",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tenant",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid admin token"
+                    },
+                    "404": {
+                        "description": "Tenant not found"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint disables a tenant: its token and calendar feeds stop working and no more reminders are sent. Its records are kept, since clinical records have to be retained, and the tenant is still listed with DisabledAt set.",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete a tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ADMIN_TOKEN",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Tenant disabled successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid admin token"
+                    },
+                    "404": {
+                        "description": "Tenant not found or already disabled"
+                    }
+                }
+            }
+        },
+        "/admin/tenants/{id}/token": {
+            "post": {
+                "description": "This endpoint issues a new API token for the tenant. The previous token stops working immediately. Disabled tenants can not get a new token.",
                 "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Rotate a tenant token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ADMIN_TOKEN",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tenant and its new token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TenantCredentials"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid admin token"
+                    },
+                    "404": {
+                        "description": "Tenant not found or disabled"
+                    }
+                }
+            }
+        },
+        "/appointments": {
+            "get": {
+                "description": "This endpoint allows you to retrieve all appointments, optionally filtered by clinic, dentist or date, as JSON or, with format=csv|xlsx or an Accept header of text/csv or the XLSX type, as a spreadsheet streamed from the database.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
                 "tags": [
                     "Appointments"
                 ],
                 "summary": "Get all appointments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Clinic ID",
+                        "name": "clinic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "dentist",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (dd/MM/YYYY) in the requested or clinic time zone",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time zone to render Date and Hour in (IANA name), defaults to the clinic time zone",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json, csv or xlsx",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Appointments",
@@ -38,6 +289,9 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Invalid filter or format"
+                    },
                     "500": {
                         "description": "Failed to retrieve appointments"
                     }
@@ -89,7 +343,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "This endpoint allows you to create a new appointment with the provided data.",
+                "description": "This endpoint allows you to create a new appointment with the provided data. A free chair is reserved automatically when no chair is requested. Set plan_steps_Id to book it for a step of a treatment plan of the patient; the step treatment is used when none is given.",
                 "produces": [
                     "application/json"
                 ],
@@ -183,6 +437,18 @@ const docTemplate = `{
                         "name": "description",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Treatment ID",
+                        "name": "treatment_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time zone of date and hour (IANA name), defaults to the clinic time zone",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -215,12 +481,25 @@ const docTemplate = `{
                 ],
                 "summary": "Get appointments by patient DNI",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Patient DNI",
                         "name": "dni",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time zone to render Date and Hour in (IANA name), defaults to the clinic time zone",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -244,7 +523,7 @@ const docTemplate = `{
         },
         "/appointments/{id}": {
             "get": {
-                "description": "This endpoint allows you to retrieve an appointment by its ID.",
+                "description": "This endpoint allows you to retrieve an appointment by its ID. MedicalAlert and Alerts tell about the allergies and conditions of the patient to check before treating them.",
                 "produces": [
                     "application/json"
                 ],
@@ -253,12 +532,25 @@ const docTemplate = `{
                 ],
                 "summary": "Get an appointment by ID",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "type": "integer",
                         "description": "Appointment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time zone to render Date and Hour in (IANA name), defaults to the clinic time zone",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -269,7 +561,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid ID"
+                        "description": "Invalid ID or time zone"
                     },
                     "404": {
                         "description": "Appointment not found"
@@ -277,7 +569,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "This endpoint allows you to delete an appointment by its ID.",
+                "description": "This endpoint allows you to delete an appointment by its ID. Appointments with clinical notes, odontogram findings, attachments, signed consents, invoices or prescriptions are kept, as those records must be.",
                 "tags": [
                     "Appointments"
                 ],
@@ -308,6 +600,9 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized access due to missing or invalid token"
                     },
+                    "409": {
+                        "description": "The appointment has records of the visit"
+                    },
                     "500": {
                         "description": "Failed to delete appointment"
                     }
@@ -366,40 +661,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/dentists": {
+        "/appointments/{id}/attachments": {
             "get": {
-                "description": "This endpoint allows you to retrieve all patients.",
+                "description": "This endpoint lists the files attached to the appointment, the most recent first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Patients"
+                    "Attachments"
+                ],
+                "summary": "Get the attachments of an appointment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Appointment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all patients",
                 "responses": {
                     "200": {
-                        "description": "Patients",
+                        "description": "Attachments",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/domain.Patient"
+                                "$ref": "#/definitions/domain.Attachment"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Failed to retrieve patients"
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Appointment not found"
                     }
                 }
             },
-            "put": {
-                "description": "This endpoint allows you to update a dentist with the provided data.",
+            "post": {
+                "description": "This endpoint uploads a file taken during the appointment, e.g. an X-ray, as multipart/form-data. It is also listed with the files of the patient.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dentists"
+                    "Attachments"
                 ],
-                "summary": "Update a dentist",
+                "summary": "Attach a file to an appointment",
                 "parameters": [
                     {
                         "type": "string",
@@ -409,42 +729,61 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated dentist information",
-                        "name": "dentist",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.Dentist"
-                        }
+                        "type": "integer",
+                        "description": "Appointment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "xray, consent, photo or document (default)",
+                        "name": "kind",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description of the file",
+                        "name": "description",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Updated dentist",
+                    "201": {
+                        "description": "Attachment",
                         "schema": {
-                            "$ref": "#/definitions/domain.Dentist"
+                            "$ref": "#/definitions/domain.Attachment"
                         }
                     },
                     "400": {
-                        "description": "Invalid dentist data or missing required fields"
+                        "description": "Missing file, unsupported type or unknown appointment"
                     },
                     "401": {
                         "description": "Unauthorized access due to missing or invalid token"
                     },
-                    "500": {
-                        "description": "Failed to update dentist"
+                    "413": {
+                        "description": "File too large"
                     }
                 }
-            },
+            }
+        },
+        "/appointments/{id}/check-in": {
             "post": {
-                "description": "This endpoint allows you to create a new dentist with the provided data.",
+                "description": "This endpoint records the arrival of the patient on the day of the appointment. When the treatment requires consent forms, check-in is refused until every one of them is signed for the appointment.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dentists"
+                    "Appointments"
                 ],
-                "summary": "Create a new dentist",
+                "summary": "Check in the patient of an appointment",
                 "parameters": [
                     {
                         "type": "string",
@@ -454,45 +793,53 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Dentist",
-                        "name": "dentist",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.Dentist"
-                        }
+                        "type": "integer",
+                        "description": "Appointment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Dentist created successfully"
+                    "200": {
+                        "description": "Checked in appointment",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Appointment"
+                        }
                     },
                     "400": {
-                        "description": "Invalid dentist data or missing required fields"
+                        "description": "Invalid ID, not the day of the appointment, already checked in or consent not signed"
                     },
                     "401": {
                         "description": "Unauthorized access due to missing or invalid token"
                     },
-                    "500": {
-                        "description": "Failed to create dentist"
+                    "404": {
+                        "description": "Appointment not found"
                     }
                 }
             }
         },
-        "/dentists/{id}": {
-            "get": {
-                "description": "This endpoint allows you to retrieve a dentist by their ID.",
+        "/appointments/{id}/complete": {
+            "post": {
+                "description": "This endpoint marks an appointment that already started as completed. When it was booked for a treatment plan step, the step is completed and the plan progress updated.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dentists"
+                    "Appointments"
                 ],
-                "summary": "Get a dentist by ID",
+                "summary": "Complete an appointment",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "description": "Dentist ID",
+                        "description": "Appointment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -500,25 +847,33 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Dentist",
+                        "description": "Completed appointment",
                         "schema": {
-                            "$ref": "#/definitions/domain.Dentist"
+                            "$ref": "#/definitions/domain.Appointment"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID"
+                        "description": "Invalid ID, appointment not started yet or already completed"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
                     },
                     "404": {
-                        "description": "Dentist not found"
+                        "description": "Appointment not found"
                     }
                 }
-            },
-            "delete": {
-                "description": "This endpoint allows you to delete a dentist by their ID.",
+            }
+        },
+        "/appointments/{id}/consents": {
+            "get": {
+                "description": "This endpoint lists the consents of the appointment, so they can be presented to the patient and signed. The consents its treatment requires are prepared when the appointment is booked or changed.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Dentists"
+                    "Consents"
                 ],
-                "summary": "Delete a dentist",
+                "summary": "Get the consents of an appointment",
                 "parameters": [
                     {
                         "type": "string",
@@ -529,15 +884,21 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Dentist ID",
+                        "description": "Appointment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Dentist deleted successfully"
+                    "200": {
+                        "description": "Consents",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Consent"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Invalid ID"
@@ -545,20 +906,23 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized access due to missing or invalid token"
                     },
-                    "500": {
-                        "description": "Failed to delete dentist"
+                    "404": {
+                        "description": "Appointment not found"
                     }
                 }
             },
-            "patch": {
-                "description": "This endpoint allows you to update a dentist's license with the provided data.",
+            "post": {
+                "description": "This endpoint adds the consent form of an active template to the appointment, also when its treatment does not require it.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dentists"
+                    "Consents"
                 ],
-                "summary": "Update a dentist's license",
+                "summary": "Prepare a consent for an appointment",
                 "parameters": [
                     {
                         "type": "string",
@@ -569,50 +933,50 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Dentist ID",
+                        "description": "Appointment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated license information",
-                        "name": "license",
+                        "description": "Template of the consent: {\\",
+                        "name": "consent",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "string"
+                            "type": "object"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "License updated successfully"
+                    "201": {
+                        "description": "Prepared consent",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Consent"
+                        }
                     },
                     "400": {
-                        "description": "Invalid request or missing required fields"
+                        "description": "Invalid ID, unknown or inactive template, already prepared or appointment completed"
                     },
                     "401": {
                         "description": "Unauthorized access due to missing or invalid token"
                     },
                     "404": {
-                        "description": "Dentist not found"
-                    },
-                    "500": {
-                        "description": "Failed to update License"
+                        "description": "Appointment not found"
                     }
                 }
             }
         },
-        "/patients": {
-            "put": {
-                "description": "This endpoint allows you to update a patient with the provided data.",
+        "/appointments/{id}/notes": {
+            "get": {
+                "description": "This endpoint lists the notes of the appointment in the order they were written, each with its amendments.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Patients"
+                    "Clinical notes"
                 ],
-                "summary": "Update a patient",
+                "summary": "Get the clinical notes of an appointment",
                 "parameters": [
                     {
                         "type": "string",
@@ -622,42 +986,46 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated patient information",
-                        "name": "patient",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.Patient"
-                        }
+                        "type": "integer",
+                        "description": "Appointment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated patient",
+                        "description": "Clinical notes",
                         "schema": {
-                            "$ref": "#/definitions/domain.Patient"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ClinicalNote"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid patient data or missing required fields"
+                        "description": "Invalid ID"
                     },
                     "401": {
                         "description": "Unauthorized access due to missing or invalid token"
                     },
-                    "500": {
-                        "description": "Failed to update patient"
+                    "404": {
+                        "description": "Appointment not found"
                     }
                 }
             },
             "post": {
-                "description": "This endpoint allows you to create a new patient with the provided data.",
+                "description": "This endpoint records what was found and done in the visit. The note is authored by the dentist of the appointment and can be written once the appointment has started. Notes can not be edited nor deleted, only amended.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Patients"
+                    "Clinical notes"
                 ],
-                "summary": "Create a new patient",
+                "summary": "Write a clinical note for an appointment",
                 "parameters": [
                     {
                         "type": "string",
@@ -667,71 +1035,51 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Patient",
-                        "name": "patient",
+                        "type": "integer",
+                        "description": "Appointment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Clinical note",
+                        "name": "note",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.Patient"
+                            "$ref": "#/definitions/domain.ClinicalNote"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Patient created successfully"
+                        "description": "Created note",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ClinicalNote"
+                        }
                     },
                     "400": {
-                        "description": "Invalid patient data or missing required fields"
+                        "description": "Invalid note, not the dentist of the appointment or appointment not started yet"
                     },
                     "401": {
                         "description": "Unauthorized access due to missing or invalid token"
-                    },
-                    "500": {
-                        "description": "Failed to create patient"
                     }
                 }
             }
         },
-        "/patients/{id}": {
-            "get": {
-                "description": "This endpoint allows you to retrieve a patient by their ID.",
-                "produces": [
+        "/appointments/{id}/notes/{noteId}/amendments": {
+            "post": {
+                "description": "This endpoint corrects a note of the appointment. The original text is kept and the amendment, with the reason for it, is listed under the note. Amending an amendment amends the original note.",
+                "consumes": [
                     "application/json"
                 ],
-                "tags": [
-                    "Patients"
-                ],
-                "summary": "Get a patient by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Patient ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                "produces": [
+                    "application/json"
                 ],
-                "responses": {
-                    "200": {
-                        "description": "Patient",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Patient"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid ID"
-                    },
-                    "404": {
-                        "description": "Patient not found"
-                    }
-                }
-            },
-            "delete": {
-                "description": "This endpoint allows you to delete a patient by their ID.",
                 "tags": [
-                    "Patients"
+                    "Clinical notes"
                 ],
-                "summary": "Delete a patient",
+                "summary": "Amend a clinical note",
                 "parameters": [
                     {
                         "type": "string",
@@ -742,36 +1090,60 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Patient ID",
+                        "description": "Appointment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Note ID",
+                        "name": "noteId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Amendment, Text and Reason are required",
+                        "name": "note",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ClinicalNote"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Patient deleted successfully"
+                    "201": {
+                        "description": "Created amendment",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ClinicalNote"
+                        }
                     },
                     "400": {
-                        "description": "Invalid ID"
+                        "description": "Invalid amendment, missing reason or not the dentist of the appointment"
                     },
                     "401": {
                         "description": "Unauthorized access due to missing or invalid token"
                     },
-                    "500": {
-                        "description": "Failed to delete patient"
+                    "404": {
+                        "description": "Clinical note not found"
                     }
                 }
-            },
-            "patch": {
-                "description": "This endpoint allows you to update a patient's address with the provided data.",
+            }
+        },
+        "/appointments/{id}/odontogram": {
+            "post": {
+                "description": "This endpoint records what the dentist found on each tooth during the visit, in FDI numbering. Caries and fillings are recorded on surfaces (M, D, O, V, L), crowns and extractions on the whole tooth, and healthy clears what was recorded before. All the findings are recorded or none.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Patients"
+                    "Odontogram"
                 ],
-                "summary": "Update a patient's address",
+                "summary": "Record the dental findings of an appointment",
                 "parameters": [
                     {
                         "type": "string",
@@ -782,145 +1154,7398 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Patient ID",
+                        "description": "Appointment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated address information",
-                        "name": "address",
+                        "description": "Findings of the visit",
+                        "name": "findings",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ToothFinding"
+                            }
                         }
                     }
                 ],
+                "responses": {
+                    "201": {
+                        "description": "Recorded findings",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ToothFinding"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid finding, extracted tooth or appointment not started yet"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/appointments/{id}/prescriptions": {
+            "get": {
+                "description": "This endpoint lists the prescriptions issued at the appointment, voided or not.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Get the prescriptions of an appointment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Appointment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Address updated successfully"
+                        "description": "Prescriptions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Prescription"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Invalid request or missing required fields"
+                        "description": "Invalid ID"
                     },
                     "401": {
                         "description": "Unauthorized access due to missing or invalid token"
                     },
                     "404": {
-                        "description": "Patient not found"
+                        "description": "Appointment not found"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint issues a prescription of one or more medications, signed by the dentist of the appointment with their license, once the patient checked in. It gets a code printed on it that anyone can check.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Issue a prescription at an appointment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Appointment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Diagnosis, medications and notes",
+                        "name": "prescription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Prescription"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Issued prescription",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Prescription"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid prescription, patient not checked in or dentist without license"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Appointment not found"
+                    }
+                }
+            }
+        },
+        "/appointments/{id}/slip.pdf": {
+            "get": {
+                "description": "This endpoint returns a PDF confirmation of the appointment to hand to the patient, with the date and hour, the dentist, the treatment and the reserved rooms and equipment.",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Appointments"
+                ],
+                "summary": "Print an appointment slip",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Appointment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time zone to print the appointment in (IANA name), defaults to the clinic time zone",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Appointment slip",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or time zone"
+                    },
+                    "404": {
+                        "description": "Appointment not found"
+                    }
+                }
+            }
+        },
+        "/attachments/{id}": {
+            "get": {
+                "description": "This endpoint returns the details of an attached file, without its content.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Get an attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Attachment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attachment",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Attachment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Attachment not found"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint removes an attached file and its content.",
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Delete an attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Attachment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Attachment deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Attachment not found"
+                    }
+                }
+            }
+        },
+        "/attachments/{id}/content": {
+            "get": {
+                "description": "This endpoint streams the content of an attached file, checked against the checksum taken on upload as it is sent: a corrupted file ends the response before its last byte. The checksum is also sent as the ETag.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Download an attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Attachment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Attachment not found"
                     },
                     "500": {
-                        "description": "Failed to update Address"
+                        "description": "The stored file is missing or corrupted"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.Appointment": {
+        },
+        "/claims": {
+            "get": {
+                "description": "This endpoint lists the claims of the practice, the most recent first, optionally only those in a status or to a provider.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Get all claims",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "submitted, accepted, rejected or paid",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Provider ID",
+                        "name": "provider",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Claims",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Claim"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status or provider"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/claims/{id}": {
+            "get": {
+                "description": "This endpoint returns a claim with its status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Get a claim",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Claim ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Claim",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Claim"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Claim not found"
+                    }
+                }
+            },
+            "patch": {
+                "description": "This endpoint accepts a submitted claim, rejects a submitted or accepted claim with a Reason, or records that the provider paid it, by default the amount claimed. The Reference given replaces the one of the claim. Once rejected, the invoice can be claimed again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Record the answer of the provider to a claim",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Claim ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status: {\\",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated claim",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Claim"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID, status or transition"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Claim not found"
+                    }
+                }
+            }
+        },
+        "/clinics": {
+            "get": {
+                "description": "This endpoint allows you to retrieve all clinics.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinics"
+                ],
+                "summary": "Get all clinics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Clinics",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Clinic"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve clinics"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint allows you to create a new clinic (office location).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinics"
+                ],
+                "summary": "Create a new clinic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Clinic",
+                        "name": "clinic",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Clinic"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Clinic created successfully"
+                    },
+                    "400": {
+                        "description": "Invalid clinic data or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to create clinic"
+                    }
+                }
+            }
+        },
+        "/clinics/{id}": {
+            "get": {
+                "description": "This endpoint allows you to retrieve a clinic by its ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinics"
+                ],
+                "summary": "Get a clinic by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Clinic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Clinic",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Clinic"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "404": {
+                        "description": "Clinic not found"
+                    }
+                }
+            },
+            "put": {
+                "description": "This endpoint allows you to update a clinic with the provided data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinics"
+                ],
+                "summary": "Update a clinic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated clinic information",
+                        "name": "clinic",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Clinic"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated clinic",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Clinic"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid clinic data or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to update clinic"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint allows you to delete a clinic by its ID.",
+                "tags": [
+                    "Clinics"
+                ],
+                "summary": "Delete a clinic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Clinic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Clinic deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to delete clinic"
+                    }
+                }
+            }
+        },
+        "/clinics/{id}/cash-close": {
+            "get": {
+                "description": "This endpoint sums the payments and refunds recorded at the clinic on a day in its time zone, by method, and lists them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Get the cash close of a clinic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Clinic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The day to close (dd/MM/yyyy), today by default",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cash close",
+                        "schema": {
+                            "$ref": "#/definitions/domain.CashClose"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or date"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Clinic not found"
+                    }
+                }
+            }
+        },
+        "/consent-templates": {
+            "get": {
+                "description": "This endpoint lists the consent forms of the practice, active or not, with the treatments that require them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Get all consent form templates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Consent templates",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ConsentTemplate"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint creates a consent form and the treatments that require it. Appointments for those treatments can not be checked in until the form is signed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Create a consent form template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Consent template",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ConsentTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created template",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ConsentTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template, missing required fields or unknown treatment"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/consent-templates/{id}": {
+            "get": {
+                "description": "This endpoint returns a consent form with the treatments that require it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Get a consent form template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Consent template",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ConsentTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Consent template not found"
+                    }
+                }
+            },
+            "put": {
+                "description": "This endpoint replaces the text, the treatments and the Active flag of a consent form. Consents already prepared for an appointment keep the text they were prepared with; inactive forms are no longer required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Update a consent form template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Consent template",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ConsentTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated template",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ConsentTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template, missing required fields or unknown treatment"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Consent template not found"
+                    }
+                }
+            }
+        },
+        "/consents/{id}": {
+            "get": {
+                "description": "This endpoint returns a consent with the text presented to the patient and, once signed, the signer, when it was signed and the hash of the signed document.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Get a consent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Consent ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Consent",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Consent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Consent not found"
+                    }
+                }
+            }
+        },
+        "/consents/{id}/document": {
+            "get": {
+                "description": "This endpoint returns the signed PDF of a consent, once checked against the hash taken when it was signed. The hash is also sent as the ETag.",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Download a signed consent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Consent ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Signed consent",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or consent not signed yet"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Consent not found"
+                    },
+                    "500": {
+                        "description": "The signed document is missing or corrupted"
+                    }
+                }
+            }
+        },
+        "/consents/{id}/sign": {
+            "post": {
+                "description": "This endpoint signs a pending consent with the strokes captured on a signature pad. The form is rendered as a PDF with the signature drawn on it, stored, and its SHA-256 recorded with the signer and the time of signature. Signed consents can not be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Sign a consent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Consent ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Signature",
+                        "name": "signature",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ConsentSignature"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Signed consent",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Consent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid signature, already signed or appointment completed"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Consent not found"
+                    }
+                }
+            }
+        },
+        "/coverages/{id}": {
+            "get": {
+                "description": "This endpoint returns a coverage of a patient.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Get a coverage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Coverage ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Coverage",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Coverage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Coverage not found"
+                    }
+                }
+            },
+            "put": {
+                "description": "This endpoint replaces the provider, plan, member number and validity of a coverage, e.g. to end it when the patient changes provider. Invoices already drafted keep their split.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Update a coverage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Coverage ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Coverage",
+                        "name": "coverage",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Coverage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated coverage",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Coverage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid coverage, unknown provider or overlapping validity"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Coverage not found"
+                    }
+                }
+            }
+        },
+        "/dentists": {
+            "get": {
+                "description": "This endpoint allows you to retrieve all dentists, optionally filtered by specialty or by the clinic they work at, as JSON or, with format=csv|xlsx or an Accept header of text/csv or the XLSX type, as a spreadsheet streamed from the database.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Get all dentists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Specialty name",
+                        "name": "specialty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Clinic ID",
+                        "name": "clinic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json, csv or xlsx",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dentists",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Dentist"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid clinic ID or format"
+                    },
+                    "500": {
+                        "description": "Failed to retrieve dentists"
+                    }
+                }
+            },
+            "put": {
+                "description": "This endpoint allows you to update a dentist with the provided data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Update a dentist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated dentist information",
+                        "name": "dentist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Dentist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated dentist",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Dentist"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dentist data or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to update dentist"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint allows you to create a new dentist with the provided data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Create a new dentist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Dentist",
+                        "name": "dentist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Dentist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Dentist created successfully"
+                    },
+                    "400": {
+                        "description": "Invalid dentist data or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to create dentist"
+                    }
+                }
+            }
+        },
+        "/dentists/import": {
+            "post": {
+                "description": "This endpoint creates dentists from a CSV file with a header row, or a JSON Lines file, sent as the \"file\" form field or as the body. Columns are matched to fields (FirstName, LastName, License) by name unless mapped, e.g. map=License=matricula. Rows whose license already exists or repeats an earlier row are skipped. With dryRun nothing is created; with atomic either every valid row is created in one transaction or, if any row is invalid, none is.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Imports"
+                ],
+                "summary": "Bulk import dentists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV or JSON Lines file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or jsonl, guessed from the file name or content type when missing",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to column mapping, e.g. License=matricula",
+                        "name": "map",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the rows",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create all rows or none",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import report",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file, format or options"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/dentists/{id}": {
+            "get": {
+                "description": "This endpoint allows you to retrieve a dentist by their ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Get a dentist by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dentist",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Dentist"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "404": {
+                        "description": "Dentist not found"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint allows you to delete a dentist by their ID.",
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Delete a dentist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Dentist deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to delete dentist"
+                    }
+                }
+            },
+            "patch": {
+                "description": "This endpoint allows you to update a dentist's license with the provided data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Update a dentist's license",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated license information",
+                        "name": "license",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "License updated successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Dentist not found"
+                    },
+                    "500": {
+                        "description": "Failed to update License"
+                    }
+                }
+            }
+        },
+        "/dentists/{id}/availability": {
+            "get": {
+                "description": "This endpoint allows you to retrieve the free appointment slots of a dentist on a date, optionally at a single clinic.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Get the free slots of a dentist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (dd/MM/YYYY)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Clinic ID",
+                        "name": "clinic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time zone to render the slots in (IANA name), defaults to the clinic time zone",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Free slots",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Slot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID, date, clinic or time zone"
+                    }
+                }
+            }
+        },
+        "/dentists/{id}/calendar.ics": {
+            "get": {
+                "description": "This endpoint returns the appointments of a dentist or patient as an RFC 5545 calendar that calendar apps can subscribe to. It includes the last 90 days and every upcoming appointment; cancelled appointments are kept with STATUS:CANCELLED so subscribed calendars remove them. It is authenticated with the feed token instead of the TOKEN header.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Calendars"
+                ],
+                "summary": "Get an iCalendar feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Dentist or patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Feed token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Missing or invalid feed token"
+                    },
+                    "404": {
+                        "description": "Dentist or patient not found"
+                    }
+                }
+            }
+        },
+        "/dentists/{id}/calendar/import": {
+            "post": {
+                "description": "This endpoint books the events of an .ics file, sent as the \"file\" form field or as a text/calendar body, as appointments of the dentist. Attendees are matched to patients by DNI (X-DNI attendee parameter or event property, or \"DNI 12345678\" in the event text); unknown attendees get a placeholder patient. Every event goes through the usual booking checks, and the report tells which events were imported, skipped or conflicting.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Imports"
+                ],
+                "summary": "Import an iCalendar file into a dentist's appointments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "iCalendar file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time zone of the event times without a zone (IANA name), defaults to America/Argentina/Buenos_Aires",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import report",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or file"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/dentists/{id}/calendar/token": {
+            "post": {
+                "description": "This endpoint creates the secret token of the iCalendar feed of a dentist or patient and returns the URL to subscribe to. Issuing a new token revokes the previous one. The token is shown only once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Calendars"
+                ],
+                "summary": "Issue a calendar feed token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist or patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Feed URL and token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.CalendarFeed"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Dentist or patient not found"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint disables the iCalendar feed of a dentist or patient until a new token is issued.",
+                "tags": [
+                    "Calendars"
+                ],
+                "summary": "Revoke a calendar feed token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist or patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Feed token revoked"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Dentist or patient not found"
+                    }
+                }
+            }
+        },
+        "/dentists/{id}/schedule.pdf": {
+            "get": {
+                "description": "This endpoint returns a PDF with the appointments of the dentist on a date, or in the week (Monday to Sunday) that includes it, grouped by day.",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Print the schedule of a dentist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (dd/MM/YYYY)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "day or week, defaults to day",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time zone of the date and hours (IANA name), defaults to America/Argentina/Buenos_Aires",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dentist schedule",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID, date, period or time zone"
+                    }
+                }
+            }
+        },
+        "/dentists/{id}/schedules": {
+            "get": {
+                "description": "This endpoint allows you to retrieve the weekly schedules of a dentist at every clinic.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Get the schedules of a dentist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schedules",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Schedule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "500": {
+                        "description": "Failed to retrieve schedules"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint allows you to register the weekday and hours a dentist works at a clinic. A dentist cannot work at two clinics at the same time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Add a working schedule to a dentist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Schedule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Schedule created successfully"
+                    },
+                    "400": {
+                        "description": "Invalid schedule data, missing required fields or overlapping schedule"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/dentists/{id}/schedules/{scheduleId}": {
+            "delete": {
+                "description": "This endpoint allows you to delete one of the schedules of a dentist.",
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Delete a schedule of a dentist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Schedule deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Schedule not found"
+                    }
+                }
+            }
+        },
+        "/dentists/{id}/specialties": {
+            "post": {
+                "description": "This endpoint allows you to register a specialty held by a dentist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Assign a specialty to a dentist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Specialty (only the Id is used)",
+                        "name": "specialty",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Specialty"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Specialty assigned successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Dentist or specialty not found"
+                    }
+                }
+            }
+        },
+        "/dentists/{id}/specialties/{specialtyId}": {
+            "delete": {
+                "description": "This endpoint allows you to remove a specialty from a dentist.",
+                "tags": [
+                    "Dentists"
+                ],
+                "summary": "Remove a specialty from a dentist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Specialty ID",
+                        "name": "specialtyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Specialty removed successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Dentist does not hold this specialty"
+                    }
+                }
+            }
+        },
+        "/events/stream": {
+            "get": {
+                "description": "This endpoint keeps the connection open and pushes appointment.created, appointment.updated, appointment.rescheduled and appointment.cancelled events as Server-Sent Events. Each message carries the event id, so a reconnecting client sends it back in the Last-Event-ID header and receives the events it missed. The token may be passed in the token query parameter for EventSource clients. Idle streams get a keep-alive event every 15 seconds. Events show up about two seconds after they happen, so that none committed out of order is skipped.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Stream live appointment changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "TOKEN, for clients that cannot set headers",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist ID",
+                        "name": "dentist",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (dd/MM/YYYY) of the appointments, rescheduled appointments match the old and the new date",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time zone of date (IANA name), defaults to America/Argentina/Buenos_Aires",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id of the last event received, to resume the stream",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or Last-Event-ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/insurance-providers": {
+            "get": {
+                "description": "This endpoint lists the providers of the practice, active or not, with their coverage rules.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Get all insurance providers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Insurance providers",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.InsuranceProvider"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint adds an obra social or prepaid plan company the practice accepts. What it covers of each treatment is set with its rules.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Create an insurance provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Insurance provider",
+                        "name": "provider",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.InsuranceProvider"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created provider",
+                        "schema": {
+                            "$ref": "#/definitions/domain.InsuranceProvider"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid provider or name already in use"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/insurance-providers/{id}": {
+            "get": {
+                "description": "This endpoint returns a provider with its coverage rules.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Get an insurance provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Insurance provider",
+                        "schema": {
+                            "$ref": "#/definitions/domain.InsuranceProvider"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Insurance provider not found"
+                    }
+                }
+            },
+            "put": {
+                "description": "This endpoint replaces the details and the Active flag of a provider. Invoices drafted while the provider is inactive are not covered. Rules sent are ignored, they are changed on their own.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Update an insurance provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Insurance provider",
+                        "name": "provider",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.InsuranceProvider"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated provider",
+                        "schema": {
+                            "$ref": "#/definitions/domain.InsuranceProvider"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid provider or name already in use"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Insurance provider not found"
+                    }
+                }
+            }
+        },
+        "/insurance-providers/{id}/rules": {
+            "post": {
+                "description": "This endpoint sets what the provider pays of a treatment: a Rate of the price in basis points, up to MaxAmount per unit in cents. A rule with a Plan applies to that plan only and wins over the rule for every plan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Add a coverage rule to an insurance provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Coverage rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CoverageRule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Provider with its rules",
+                        "schema": {
+                            "$ref": "#/definitions/domain.InsuranceProvider"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule, unknown treatment or rule already set for the treatment and plan"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Insurance provider not found"
+                    }
+                }
+            }
+        },
+        "/insurance-providers/{id}/rules/{ruleId}": {
+            "put": {
+                "description": "This endpoint replaces the plan, treatment, rate and limit of a rule. Invoices already drafted keep their split.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Update a coverage rule of an insurance provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Coverage rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CoverageRule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Provider with its rules",
+                        "schema": {
+                            "$ref": "#/definitions/domain.InsuranceProvider"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule, unknown treatment or rule already set for the treatment and plan"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Insurance provider not found"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint removes a rule; the provider no longer covers the treatment for the plan. Invoices already drafted keep their split.",
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Delete a coverage rule of an insurance provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Rule deleted"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Coverage rule not found"
+                    }
+                }
+            }
+        },
+        "/invoices": {
+            "get": {
+                "description": "This endpoint lists the invoices of the practice, the most recent first, optionally only those in a status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Get all invoices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "draft, issued, paid or void",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invoices",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Invoice"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/invoices/{id}": {
+            "get": {
+                "description": "This endpoint returns an invoice with its lines and totals.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Get an invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invoice",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Invoice not found"
+                    }
+                }
+            },
+            "patch": {
+                "description": "This endpoint issues a draft, which gives it the next invoice number of the practice, or voids a draft or issued invoice so its appointments can be billed again. Voiding an issued invoice requires a Reason, its payments to be refunded and its claims to be rejected. Invoices are paid by recording payments.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Issue or void an invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status: {\\",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated invoice",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID, status or transition"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Invoice not found"
+                    }
+                }
+            }
+        },
+        "/invoices/{id}/claims": {
+            "get": {
+                "description": "This endpoint lists the claims of the invoice in the order they were submitted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Get the claims of an invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Claims",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Claim"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Invoice not found"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint records that the share of an issued invoice covered by the insurer was claimed to it. An invoice is claimed again only when its previous claims were rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Submit the claim of an invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The number the provider gave the claim (optional): {\\",
+                        "name": "claim",
+                        "in": "body",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Submitted claim",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Claim"
+                        }
+                    },
+                    "400": {
+                        "description": "Invoice not issued, without covered share or already claimed"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Invoice not found"
+                    }
+                }
+            }
+        },
+        "/invoices/{id}/payments": {
+            "get": {
+                "description": "This endpoint lists the payments and refunds of the invoice in the order they were recorded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Get the payments of an invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payments and refunds",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Payment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Invoice not found"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint records a cash, card or transfer payment of an issued invoice, for up to what is due; several partial payments can be recorded. Card payments go through the payment provider: a Token from the checkout or the voucher number of the terminal as Reference. Their amount is reserved before the card is charged, so concurrent payments can not cover more than is due, and a payment the provider declines is kept as voided. The invoice is paid once its payments cover the total. The clinic defaults to the one of the first appointment billed. Amounts are in cents.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Record a payment of an invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment: {\\",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Payment recorded",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment, invoice not issued, amount over what is due or card declined"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Invoice not found"
+                    }
+                }
+            }
+        },
+        "/patients": {
+            "get": {
+                "description": "This endpoint allows you to retrieve all patients, as JSON or, with format=csv|xlsx or an Accept header of text/csv or the XLSX type, as a spreadsheet streamed from the database.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "Get all patients",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json, csv or xlsx",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Patients",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Patient"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format"
+                    },
+                    "500": {
+                        "description": "Failed to retrieve patients"
+                    }
+                }
+            },
+            "put": {
+                "description": "This endpoint allows you to update a patient with the provided data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "Update a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated patient information",
+                        "name": "patient",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Patient"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated patient",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Patient"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient data or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to update patient"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint allows you to create a new patient with the provided data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "Create a new patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Patient",
+                        "name": "patient",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Patient"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Patient created successfully"
+                    },
+                    "400": {
+                        "description": "Invalid patient data or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to create patient"
+                    }
+                }
+            }
+        },
+        "/patients/import": {
+            "post": {
+                "description": "This endpoint creates patients from a CSV file with a header row, or a JSON Lines file, sent as the \"file\" form field or as the body. Columns are matched to fields by name unless mapped, e.g. map=FirstName=nombre,DNI=documento. Rows whose DNI already exists or repeats an earlier row are skipped. With dryRun nothing is created; with atomic either every valid row is created in one transaction or, if any row is invalid, none is.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Imports"
+                ],
+                "summary": "Bulk import patients",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV or JSON Lines file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or jsonl, guessed from the file name or content type when missing",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to column mapping, e.g. FirstName=nombre,LastName=apellido",
+                        "name": "map",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the rows",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create all rows or none",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import report",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file, format or options"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/patients/search": {
+            "get": {
+                "description": "This endpoint finds the patients with words of their first name, last name, DNI or address starting with every word of the query, ignoring case and accents (\"rodriguez\" finds \"Rodríguez\"). Exact DNI matches come first, then last and first names starting with the query.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "Search patients",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Words to look for, e.g. rodr maria or 30.123.456",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of patients, defaults to 20, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Patients, best matches first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Patient"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing query, invalid limit or failed search"
+                    }
+                }
+            }
+        },
+        "/patients/{id}": {
+            "get": {
+                "description": "This endpoint allows you to retrieve a patient by their ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "Get a patient by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Patient",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Patient"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "404": {
+                        "description": "Patient not found"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint allows you to delete a patient by their ID.",
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "Delete a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Patient deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to delete patient"
+                    }
+                }
+            },
+            "patch": {
+                "description": "This endpoint allows you to update a patient's address with the provided data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "Update a patient's address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated address information",
+                        "name": "address",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Address updated successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Patient not found"
+                    },
+                    "500": {
+                        "description": "Failed to update Address"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/attachments": {
+            "get": {
+                "description": "This endpoint lists the files of the patient, including those of their appointments, the most recent first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Get the attachments of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attachments",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Attachment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Patient not found"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint uploads an X-ray, a signed consent form, a photo or any other document of the patient as multipart/form-data. JPEG, PNG, WebP, PDF and DICOM files are accepted, detected from their content.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Attach a file to a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "xray, consent, photo or document (default)",
+                        "name": "kind",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description of the file",
+                        "name": "description",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Attachment",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Attachment"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing file, unsupported type or unknown patient"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "413": {
+                        "description": "File too large"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/balance": {
+            "get": {
+                "description": "This endpoint returns what the patient was invoiced, what their insurers cover, what they paid net of refunds and still owe in cents, the issued invoices not fully paid yet, and the completed appointments not invoiced yet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Get the balance of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Balance",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PatientBalance"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Patient not found"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/calendar.ics": {
+            "get": {
+                "description": "This endpoint returns the appointments of a dentist or patient as an RFC 5545 calendar that calendar apps can subscribe to. It includes the last 90 days and every upcoming appointment; cancelled appointments are kept with STATUS:CANCELLED so subscribed calendars remove them. It is authenticated with the feed token instead of the TOKEN header.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Calendars"
+                ],
+                "summary": "Get an iCalendar feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Dentist or patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Feed token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Missing or invalid feed token"
+                    },
+                    "404": {
+                        "description": "Dentist or patient not found"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/calendar/token": {
+            "post": {
+                "description": "This endpoint creates the secret token of the iCalendar feed of a dentist or patient and returns the URL to subscribe to. Issuing a new token revokes the previous one. The token is shown only once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Calendars"
+                ],
+                "summary": "Issue a calendar feed token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist or patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Feed URL and token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.CalendarFeed"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Dentist or patient not found"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint disables the iCalendar feed of a dentist or patient until a new token is issued.",
+                "tags": [
+                    "Calendars"
+                ],
+                "summary": "Revoke a calendar feed token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dentist or patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Feed token revoked"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Dentist or patient not found"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/coverages": {
+            "get": {
+                "description": "This endpoint lists the coverages of the patient, past, current and future, in the order they were added.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Get the coverages of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Coverages",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Coverage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Patient not found"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint records the provider, plan and member number of the patient, valid from ValidFrom until ValidTo, or without end when empty. A patient has a single coverage on any day, so the validity can not overlap the other coverages of the patient.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Add a coverage to a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Coverage",
+                        "name": "coverage",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Coverage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created coverage",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Coverage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid coverage, unknown patient or provider, or overlapping validity"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/history": {
+            "get": {
+                "description": "This endpoint lists the allergies, medications and conditions of the patient, grouped by kind and most recent first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Medical history"
+                ],
+                "summary": "Get the medical history of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries of this kind: allergy, medication or condition",
+                        "name": "kind",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "History entries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.HistoryEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or kind"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint records an allergy, medication or condition of the patient. Active allergies, severe entries and entries with Alert set are shown as alerts in the appointment detail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Medical history"
+                ],
+                "summary": "Add a medical history entry to a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "History entry",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.HistoryEntry"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created entry",
+                        "schema": {
+                            "$ref": "#/definitions/domain.HistoryEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid entry, missing required fields or unknown patient"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/history/{entryId}": {
+            "get": {
+                "description": "This endpoint returns one entry of the medical history of the patient.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Medical history"
+                ],
+                "summary": "Get a medical history entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "entryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "History entry",
+                        "schema": {
+                            "$ref": "#/definitions/domain.HistoryEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "History entry not found"
+                    }
+                }
+            },
+            "put": {
+                "description": "This endpoint replaces an entry of the medical history of the patient, e.g. to set the EndDate of a medication. When it was first recorded is kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Medical history"
+                ],
+                "summary": "Update a medical history entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "entryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "History entry",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.HistoryEntry"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated entry",
+                        "schema": {
+                            "$ref": "#/definitions/domain.HistoryEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid entry or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "History entry not found"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint removes an entry recorded by mistake. Entries that no longer apply should get an EndDate instead.",
+                "tags": [
+                    "Medical history"
+                ],
+                "summary": "Delete a medical history entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "entryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "History entry deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "History entry not found"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/invoices": {
+            "get": {
+                "description": "This endpoint lists the invoices of the patient, in any status, the most recent first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Get the invoices of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invoices",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Invoice"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Patient not found"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint drafts an invoice with a line for the treatment of each completed appointment in AppointmentIds, or of every completed appointment not invoiced yet when empty, at the price and tax rate of the treatment. Lines can also be added by hand. The lines are split between the patient and the coverage in patient_coverages_Id, or else the coverage of the patient valid today, by the rules of the provider. Amounts are in cents and tax rates in basis points.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Generate an invoice for a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Appointments to bill, extra lines, coverage and notes: {\\",
+                        "name": "invoice",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Draft invoice",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, unknown patient or coverage, appointment not completed or already invoiced, or nothing to invoice"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/odontogram": {
+            "get": {
+                "description": "This endpoint returns the state of each examined tooth of the patient, built from the findings recorded up to the end of the given date, or up to now.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Odontogram"
+                ],
+                "summary": "Get the odontogram of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of the chart (dd/MM/YYYY), defaults to now",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time zone of the date (IANA name), defaults to America/Argentina/Buenos_Aires",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Odontogram",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Odontogram"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID, date, time zone or unknown patient"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/odontogram/findings": {
+            "get": {
+                "description": "This endpoint lists the findings recorded for the patient in the order they were made, to follow how a tooth evolved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Odontogram"
+                ],
+                "summary": "Get the dental findings of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only findings of this tooth (FDI number)",
+                        "name": "tooth",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Findings",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ToothFinding"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID, tooth or unknown patient"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/payments": {
+            "get": {
+                "description": "This endpoint lists the payments and refunds of the patient, the most recent first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Get the payments of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payments and refunds",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Payment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Patient not found"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/plans": {
+            "get": {
+                "description": "This endpoint lists the plans of the patient, the most recent first, with the status of each step and the progress of the plan.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Treatment plans"
+                ],
+                "summary": "Get the treatment plans of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Treatment plans",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.TreatmentPlan"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Patient not found"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint creates a plan of ordered steps, each with an optional treatment and its estimated cost in cents. Appointments are booked for a step with plan_steps_Id, and the plan progresses as they are completed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Treatment plans"
+                ],
+                "summary": "Create a treatment plan for a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Treatment plan",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.TreatmentPlan"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created plan",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TreatmentPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid plan, missing required fields, unknown patient or treatment"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/prescriptions": {
+            "get": {
+                "description": "This endpoint lists the prescriptions of the patient, voided or not, the most recent first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Get the prescriptions of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Prescriptions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Prescription"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Patient not found"
+                    }
+                }
+            }
+        },
+        "/patients/{id}/timeline": {
+            "get": {
+                "description": "This endpoint lists the appointments of the patient in chronological order, each with its clinical notes and their amendments.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinical notes"
+                ],
+                "summary": "Get the clinical timeline of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Clinical timeline",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.TimelineEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Patient not found"
+                    }
+                }
+            }
+        },
+        "/payments/{id}": {
+            "get": {
+                "description": "This endpoint returns a payment or refund.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Get a payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Payment not found"
+                    }
+                }
+            }
+        },
+        "/payments/{id}/refunds": {
+            "post": {
+                "description": "This endpoint gives back part or all of a payment with the method it was paid with; Amount defaults to what is left of the payment. Card refunds go through the payment provider; with a terminal, Reference is the voucher number of the refund. Their amount is reserved before the provider is called, so concurrent refunds can not give back more than was paid, and a refund the provider rejects is kept as voided. A paid invoice is open again when a refund leaves something due.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Refund a payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund: {\\",
+                        "name": "refund",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Refund recorded",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid refund or amount over what is left of the payment"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Payment not found"
+                    }
+                }
+            }
+        },
+        "/plans/{id}": {
+            "get": {
+                "description": "This endpoint returns a plan with its steps, the appointments booked for each step and the progress of the plan.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Treatment plans"
+                ],
+                "summary": "Get a treatment plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Treatment plan",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TreatmentPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Treatment plan not found"
+                    }
+                }
+            },
+            "patch": {
+                "description": "This endpoint sets the status of a plan to cancelled or back to active. No more appointments can be booked for the steps of a cancelled plan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Treatment plans"
+                ],
+                "summary": "Cancel or reactivate a treatment plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status: {\\",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated plan",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TreatmentPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or status"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Treatment plan not found"
+                    }
+                }
+            }
+        },
+        "/plans/{id}/steps": {
+            "post": {
+                "description": "This endpoint appends a step at the end of a plan that is not cancelled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Treatment plans"
+                ],
+                "summary": "Add a step to a treatment plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Plan step",
+                        "name": "step",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.PlanStep"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Updated plan",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TreatmentPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid step, unknown treatment or cancelled plan"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Treatment plan not found"
+                    }
+                }
+            }
+        },
+        "/plans/{id}/steps/{stepId}": {
+            "put": {
+                "description": "This endpoint replaces the treatment, description and estimated cost of a step, and skips it, or brings it back, with Skipped. Completed steps can not be skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Treatment plans"
+                ],
+                "summary": "Update a step of a treatment plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Step ID",
+                        "name": "stepId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Plan step",
+                        "name": "step",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.PlanStep"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated plan",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TreatmentPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid step, unknown treatment or completed step skipped"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Treatment plan not found"
+                    }
+                }
+            }
+        },
+        "/prescriptions/check/{code}": {
+            "get": {
+                "description": "This endpoint tells whoever holds a prescription, such as a pharmacy, whether its code is genuine and it can be dispensed, with the dentist who issued it and the medications. It needs no token; the patient is only shown by their initials. Dashes, spaces and case in the code are ignored. Each client IP can check 10 codes at once and then one every 6 seconds.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Check a prescription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Verification code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Prescription check",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PrescriptionCheck"
+                        }
+                    },
+                    "404": {
+                        "description": "Prescription not found"
+                    },
+                    "429": {
+                        "description": "Too many requests, retry after the seconds in Retry-After"
+                    }
+                }
+            }
+        },
+        "/prescriptions/{id}": {
+            "get": {
+                "description": "This endpoint returns a prescription with its medications, verification code and status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Get a prescription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Prescription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Prescription",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Prescription"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Prescription not found"
+                    }
+                }
+            }
+        },
+        "/prescriptions/{id}/prescription.pdf": {
+            "get": {
+                "description": "This endpoint returns the prescription as a PDF to hand to the patient, signed by the dentist, with its verification code and the address where it can be checked.",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Print a prescription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Prescription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Prescription",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Prescription not found"
+                    }
+                }
+            }
+        },
+        "/prescriptions/{id}/void": {
+            "post": {
+                "description": "This endpoint cancels a prescription issued by mistake. It is kept, and checking its code tells it must not be dispensed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Void a prescription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Prescription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why it is voided: {\\",
+                        "name": "void",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Voided prescription",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Prescription"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID, missing reason or already voided"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Prescription not found"
+                    }
+                }
+            }
+        },
+        "/resources": {
+            "get": {
+                "description": "This endpoint allows you to retrieve all resources, optionally filtered by kind or clinic.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Get all resources",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource kind (chair, room or equipment)",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Clinic ID",
+                        "name": "clinic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resources",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid clinic ID"
+                    },
+                    "500": {
+                        "description": "Failed to retrieve resources"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint allows you to create a new bookable resource (chair, room or equipment).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Create a new resource",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Resource",
+                        "name": "resource",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Resource"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Resource created successfully"
+                    },
+                    "400": {
+                        "description": "Invalid resource data or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to create resource"
+                    }
+                }
+            }
+        },
+        "/resources/{id}": {
+            "get": {
+                "description": "This endpoint allows you to retrieve a resource by its ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Get a resource by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resource",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Resource"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "404": {
+                        "description": "Resource not found"
+                    }
+                }
+            },
+            "put": {
+                "description": "This endpoint allows you to update a resource with the provided data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Update a resource",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated resource information",
+                        "name": "resource",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Resource"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated resource",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Resource"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid resource data or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to update resource"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint allows you to delete a resource by its ID.",
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Delete a resource",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Resource deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to delete resource"
+                    }
+                }
+            }
+        },
+        "/specialties": {
+            "get": {
+                "description": "This endpoint allows you to retrieve all specialties.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Specialties"
+                ],
+                "summary": "Get all specialties",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Specialties",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Specialty"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve specialties"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint allows you to create a new specialty with the provided data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Specialties"
+                ],
+                "summary": "Create a new specialty",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Specialty",
+                        "name": "specialty",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Specialty"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Specialty created successfully"
+                    },
+                    "400": {
+                        "description": "Invalid specialty data or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to create specialty"
+                    }
+                }
+            }
+        },
+        "/specialties/{id}": {
+            "get": {
+                "description": "This endpoint allows you to retrieve a specialty by its ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Specialties"
+                ],
+                "summary": "Get a specialty by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Specialty ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Specialty",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Specialty"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "404": {
+                        "description": "Specialty not found"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint allows you to delete a specialty by its ID.",
+                "tags": [
+                    "Specialties"
+                ],
+                "summary": "Delete a specialty",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Specialty ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Specialty deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to delete specialty"
+                    }
+                }
+            }
+        },
+        "/treatments": {
+            "get": {
+                "description": "This endpoint allows you to retrieve all treatments.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Treatments"
+                ],
+                "summary": "Get all treatments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Treatments",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Treatment"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve treatments"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint allows you to create a new treatment, optionally requiring a specialty.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Treatments"
+                ],
+                "summary": "Create a new treatment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Treatment",
+                        "name": "treatment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Treatment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Treatment created successfully"
+                    },
+                    "400": {
+                        "description": "Invalid treatment data or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to create treatment"
+                    }
+                }
+            }
+        },
+        "/treatments/{id}": {
+            "get": {
+                "description": "This endpoint allows you to retrieve a treatment by its ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Treatments"
+                ],
+                "summary": "Get a treatment by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Treatment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Treatment",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Treatment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "404": {
+                        "description": "Treatment not found"
+                    }
+                }
+            },
+            "put": {
+                "description": "This endpoint allows you to update a treatment with the provided data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Treatments"
+                ],
+                "summary": "Update a treatment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated treatment information",
+                        "name": "treatment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Treatment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated treatment",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Treatment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid treatment data or missing required fields"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to update treatment"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint allows you to delete a treatment by its ID.",
+                "tags": [
+                    "Treatments"
+                ],
+                "summary": "Delete a treatment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Treatment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Treatment deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "500": {
+                        "description": "Failed to delete treatment"
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "This endpoint allows you to retrieve the webhooks of the practice. Secrets are not returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get all webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhooks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Webhook"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve webhooks"
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint registers a URL that receives the selected events as signed JSON POST requests. Every request carries an X-Webhook-Signature header \"t=\u003cunix time\u003e,v1=\u003chex HMAC-SHA256 of \"\u003cunix time\u003e.\u003cbody\u003e\"\u003e\" keyed with the secret. The secret is returned only here.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Subscribe a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook and its secret",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook data, URL or event types"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "description": "This endpoint allows you to retrieve a webhook by its ID. The secret is not returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get a webhook by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "404": {
+                        "description": "Webhook not found"
+                    }
+                }
+            },
+            "put": {
+                "description": "This endpoint replaces the URL, event types and active flag of a webhook. The secret is kept unless a new one is sent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Update a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated webhook information",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated webhook",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook data, URL or event types"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Webhook not found"
+                    }
+                }
+            },
+            "delete": {
+                "description": "This endpoint removes a webhook together with its delivery log.",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Webhook not found"
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "This endpoint returns the latest 100 deliveries of a webhook, newest first, with every attempt made.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get the delivery log of a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deliveries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Webhook not found"
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryId}/replay": {
+            "post": {
+                "description": "This endpoint queues a delivery to be sent again, whether it was delivered or failed, with a fresh retry budget. The receiver gets the same X-Webhook-Delivery id and should deduplicate on it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Replay a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TOKEN",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Queued delivery",
+                        "schema": {
+                            "$ref": "#/definitions/domain.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID"
+                    },
+                    "401": {
+                        "description": "Unauthorized access due to missing or invalid token"
+                    },
+                    "404": {
+                        "description": "Delivery not found"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.Appointment": {
+            "type": "object",
+            "required": [
+                "Date",
+                "Description",
+                "Hour",
+                "dentists_Id",
+                "patients_Id"
+            ],
+            "properties": {
+                "Alerts": {
+                    "description": "@Description The medical history entries behind MedicalAlert\n@Example [\"Allergy: Penicillin (severe)\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "CheckedInAt": {
+                    "description": "@Description When the patient checked in at the clinic, empty if they did not",
+                    "type": "string"
+                },
+                "CompletedAt": {
+                    "description": "@Description When the appointment was completed, empty if it was not",
+                    "type": "string"
+                },
+                "Date": {
+                    "description": "@Description The local date of the appointment (dd/MM/YYYY) in TimeZone\n@Example \"30/03/2024\"",
+                    "type": "string"
+                },
+                "Description": {
+                    "description": "@Description The description of the appointment\n@Example \"Routine checkup\"",
+                    "type": "string"
+                },
+                "Hour": {
+                    "description": "@Description The local time of the appointment in 24-hour format in TimeZone\n@Example \"09:00\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the appointment\n@Example 1",
+                    "type": "integer"
+                },
+                "MedicalAlert": {
+                    "description": "@Description Whether the patient has allergies or conditions to check before treating them. Only set when reading a single appointment\n@Example true",
+                    "type": "boolean"
+                },
+                "Resources": {
+                    "description": "@Description The rooms, chairs and equipment reserved by the appointment",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Resource"
+                    }
+                },
+                "StartsAt": {
+                    "description": "@Description The start of the appointment in UTC, derived from Date, Hour and TimeZone\n@Example \"2024-03-30T12:00:00Z\"",
+                    "type": "string"
+                },
+                "TimeZone": {
+                    "description": "@Description The time zone of Date and Hour (optional, defaults to the clinic time zone)\n@Example \"America/Argentina/Buenos_Aires\"",
+                    "type": "string"
+                },
+                "clinics_Id": {
+                    "description": "@Description The clinic where the appointment takes place (optional)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Clinic"
+                        }
+                    ]
+                },
+                "dentists_Id": {
+                    "description": "@Description Information related to the patient",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Dentist"
+                        }
+                    ]
+                },
+                "patients_Id": {
+                    "description": "@Description Information related to the patient",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Patient"
+                        }
+                    ]
+                },
+                "plan_steps_Id": {
+                    "description": "@Description The treatment plan step the appointment is booked for (optional)\n@Example 0",
+                    "type": "integer"
+                },
+                "treatments_Id": {
+                    "description": "@Description The treatment to be performed (optional)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Treatment"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.Attachment": {
+            "type": "object",
+            "properties": {
+                "Checksum": {
+                    "description": "@Description The SHA-256 of the content, checked on every download\n@Example \"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08\"",
+                    "type": "string"
+                },
+                "ContentType": {
+                    "description": "@Description The type of the content, detected from the file itself\n@Example \"image/jpeg\"",
+                    "type": "string"
+                },
+                "Description": {
+                    "description": "@Description Any detail about the file (optional)\n@Example \"Panoramic before the implant\"",
+                    "type": "string"
+                },
+                "FileName": {
+                    "description": "@Description The name of the uploaded file\n@Example \"panoramic-2024.jpg\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the attachment\n@Example 1",
+                    "type": "integer"
+                },
+                "Kind": {
+                    "description": "@Description What the file is (xray, consent, photo or document)\n@Example \"xray\"",
+                    "type": "string"
+                },
+                "Size": {
+                    "description": "@Description The size of the file in bytes\n@Example 482113",
+                    "type": "integer"
+                },
+                "UploadedAt": {
+                    "description": "@Description When the file was uploaded",
+                    "type": "string"
+                },
+                "appointments_Id": {
+                    "description": "@Description The appointment the file was taken in, 0 when attached to the patient only\n@Example 0",
+                    "type": "integer"
+                },
+                "patients_Id": {
+                    "description": "@Description The patient the file belongs to\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.CalendarFeed": {
+            "type": "object",
+            "properties": {
+                "Token": {
+                    "description": "@Description The secret feed token, issuing a new one revokes the previous\n@Example \"3f0c8e4a...\"",
+                    "type": "string"
+                },
+                "URL": {
+                    "description": "@Description The URL to subscribe to from a calendar app\n@Example \"https://api.example.com/dentists/1/calendar.ics?token=3f0c8e4a...\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CashClose": {
+            "type": "object",
+            "properties": {
+                "Date": {
+                    "description": "@Description The day closed, in the time zone of the clinic (dd/MM/yyyy)\n@Example \"30/03/2024\"",
+                    "type": "string"
+                },
+                "Net": {
+                    "description": "@Description What the clinic took in, net of refunds, in cents\n@Example 5000000",
+                    "type": "integer"
+                },
+                "Payments": {
+                    "description": "@Description The entries of the day, in the order they were recorded",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Payment"
+                    }
+                },
+                "TimeZone": {
+                    "description": "@Description The time zone of the clinic\n@Example \"America/Argentina/Buenos_Aires\"",
+                    "type": "string"
+                },
+                "Totals": {
+                    "description": "@Description The totals of every method",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CashCloseTotal"
+                    }
+                },
+                "clinics_Id": {
+                    "description": "@Description The clinic\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.CashCloseTotal": {
+            "type": "object",
+            "properties": {
+                "Count": {
+                    "description": "@Description How many entries were recorded\n@Example 3",
+                    "type": "integer"
+                },
+                "Method": {
+                    "description": "@Description cash, card or transfer\n@Example \"cash\"",
+                    "type": "string"
+                },
+                "Net": {
+                    "description": "@Description Payments minus refunds, in cents\n@Example 5000000",
+                    "type": "integer"
+                },
+                "Payments": {
+                    "description": "@Description The payments received, in cents\n@Example 6000000",
+                    "type": "integer"
+                },
+                "Refunds": {
+                    "description": "@Description The refunds given back, in cents\n@Example 1000000",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Claim": {
+            "type": "object",
+            "properties": {
+                "Amount": {
+                    "description": "@Description The share of the invoice claimed, in cents\n@Example 3600000",
+                    "type": "integer"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the claim\n@Example 1",
+                    "type": "integer"
+                },
+                "PaidAmount": {
+                    "description": "@Description What the provider paid, in cents\n@Example 0",
+                    "type": "integer"
+                },
+                "PaidAt": {
+                    "description": "@Description When the provider paid the claim",
+                    "type": "string"
+                },
+                "Reason": {
+                    "description": "@Description Why the claim was rejected\n@Example \"\"",
+                    "type": "string"
+                },
+                "Reference": {
+                    "description": "@Description The number the provider gave the claim or its authorization (optional)\n@Example \"AUT-2024-000123\"",
+                    "type": "string"
+                },
+                "ResolvedAt": {
+                    "description": "@Description When the provider accepted or rejected the claim",
+                    "type": "string"
+                },
+                "Status": {
+                    "description": "@Description submitted, accepted, rejected or paid\n@Example \"submitted\"",
+                    "type": "string"
+                },
+                "SubmittedAt": {
+                    "description": "@Description When the claim was submitted",
+                    "type": "string"
+                },
+                "insurance_providers_Id": {
+                    "description": "@Description The provider the claim is submitted to\n@Example 1",
+                    "type": "integer"
+                },
+                "invoices_Id": {
+                    "description": "@Description The invoice claimed\n@Example 1",
+                    "type": "integer"
+                },
+                "patient_coverages_Id": {
+                    "description": "@Description The coverage the invoice was split with\n@Example 1",
+                    "type": "integer"
+                },
+                "patients_Id": {
+                    "description": "@Description The patient of the invoice\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Clinic": {
+            "type": "object",
+            "required": [
+                "Address",
+                "Name"
+            ],
+            "properties": {
+                "Address": {
+                    "description": "@Description The address of the clinic\n@Example \"Av. 22 # 40\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the clinic\n@Example 1",
+                    "type": "integer"
+                },
+                "Name": {
+                    "description": "@Description The name of the clinic\n@Example \"Downtown office\"",
+                    "type": "string"
+                },
+                "TimeZone": {
+                    "description": "@Description The IANA time zone of the clinic, schedules and appointments are expressed in it\n@Example \"America/Argentina/Cordoba\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ClinicalNote": {
+            "type": "object",
+            "required": [
+                "Text"
+            ],
+            "properties": {
+                "Amendments": {
+                    "description": "@Description The amendments of the note, oldest first; the last one is the current text",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ClinicalNote"
+                    }
+                },
+                "AmendsId": {
+                    "description": "@Description The note this one amends, 0 for an original note\n@Example 0",
+                    "type": "integer"
+                },
+                "CreatedAt": {
+                    "description": "@Description When the note was written",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the note\n@Example 1",
+                    "type": "integer"
+                },
+                "Reason": {
+                    "description": "@Description Why the note was amended (amendments only)\n@Example \"Wrong tooth number\"",
+                    "type": "string"
+                },
+                "Text": {
+                    "description": "@Description What was found and done in the visit\n@Example \"Composite filling on 36, occlusal. Patient tolerated well.\"",
+                    "type": "string"
+                },
+                "appointments_Id": {
+                    "description": "@Description The appointment the note was written for\n@Example 1",
+                    "type": "integer"
+                },
+                "dentists_Id": {
+                    "description": "@Description The dentist who wrote the note, the one treating the patient\n@Example 1",
+                    "type": "integer"
+                },
+                "patients_Id": {
+                    "description": "@Description The patient seen in the appointment\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Consent": {
+            "type": "object",
+            "properties": {
+                "Body": {
+                    "description": "@Description The text of the form, as presented to the patient",
+                    "type": "string"
+                },
+                "CreatedAt": {
+                    "description": "@Description When the consent was prepared for the appointment",
+                    "type": "string"
+                },
+                "DocumentHash": {
+                    "description": "@Description The SHA-256 of the signed document, checked on every download\n@Example \"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the consent\n@Example 1",
+                    "type": "integer"
+                },
+                "SignedAt": {
+                    "description": "@Description When the consent was signed, empty while pending",
+                    "type": "string"
+                },
+                "SignerName": {
+                    "description": "@Description The name of the person who signed\n@Example \"Juan Perez\"",
+                    "type": "string"
+                },
+                "SignerRelationship": {
+                    "description": "@Description Who the signer is to the patient\n@Example \"Patient\"",
+                    "type": "string"
+                },
+                "Status": {
+                    "description": "@Description pending or signed\n@Example \"signed\"",
+                    "type": "string"
+                },
+                "Title": {
+                    "description": "@Description The title of the form, as presented to the patient\n@Example \"Informed consent for tooth extraction\"",
+                    "type": "string"
+                },
+                "appointments_Id": {
+                    "description": "@Description The appointment the consent is given for\n@Example 1",
+                    "type": "integer"
+                },
+                "consent_templates_Id": {
+                    "description": "@Description The template the consent was prepared from\n@Example 1",
+                    "type": "integer"
+                },
+                "patients_Id": {
+                    "description": "@Description The patient giving the consent\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ConsentSignature": {
+            "type": "object",
+            "required": [
+                "Height",
+                "SignerName",
+                "Strokes",
+                "Width"
+            ],
+            "properties": {
+                "Height": {
+                    "description": "@Description The height of the signature pad\n@Example 150",
+                    "type": "number"
+                },
+                "SignerName": {
+                    "description": "@Description The name of the person signing\n@Example \"Juan Perez\"",
+                    "type": "string"
+                },
+                "SignerRelationship": {
+                    "description": "@Description Who the signer is to the patient (optional, defaults to Patient)\n@Example \"Patient\"",
+                    "type": "string"
+                },
+                "Strokes": {
+                    "description": "@Description The strokes of the signature, each a list of points drawn without lifting the pen",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/domain.SignaturePoint"
+                        }
+                    }
+                },
+                "Width": {
+                    "description": "@Description The width of the signature pad\n@Example 400",
+                    "type": "number"
+                }
+            }
+        },
+        "domain.ConsentTemplate": {
+            "type": "object",
+            "required": [
+                "Body",
+                "Title"
+            ],
+            "properties": {
+                "Active": {
+                    "description": "@Description Whether the form is still required. Inactive forms are kept for the consents already signed\n@Example true",
+                    "type": "boolean"
+                },
+                "Body": {
+                    "description": "@Description The text the patient agrees to. Blank lines separate paragraphs\n@Example \"I have been informed of the risks of the extraction...\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the template\n@Example 1",
+                    "type": "integer"
+                },
+                "Title": {
+                    "description": "@Description The title of the consent form\n@Example \"Informed consent for tooth extraction\"",
+                    "type": "string"
+                },
+                "Treatments": {
+                    "description": "@Description The treatments that can not start until the form is signed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Treatment"
+                    }
+                },
+                "UpdatedAt": {
+                    "description": "@Description When the template was last changed",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Coverage": {
+            "type": "object",
+            "required": [
+                "MemberNumber",
+                "ValidFrom",
+                "insurance_providers_Id"
+            ],
+            "properties": {
+                "Id": {
+                    "description": "@Description The unique identifier of the coverage\n@Example 1",
+                    "type": "integer"
+                },
+                "MemberNumber": {
+                    "description": "@Description The member number on the card of the patient\n@Example \"61 234567 8 01\"",
+                    "type": "string"
+                },
+                "Plan": {
+                    "description": "@Description The plan of the patient (optional)\n@Example \"310\"",
+                    "type": "string"
+                },
+                "ProviderName": {
+                    "description": "@Description The name of the provider\n@Example \"OSDE\"",
+                    "type": "string"
+                },
+                "ValidFrom": {
+                    "description": "@Description The first day covered (dd/MM/yyyy)\n@Example \"01/01/2024\"",
+                    "type": "string"
+                },
+                "ValidTo": {
+                    "description": "@Description The last day covered (dd/MM/yyyy), empty while it does not end\n@Example \"31/12/2024\"",
+                    "type": "string"
+                },
+                "insurance_providers_Id": {
+                    "description": "@Description The provider\n@Example 1",
+                    "type": "integer"
+                },
+                "patients_Id": {
+                    "description": "@Description The patient covered\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.CoverageRule": {
+            "type": "object",
+            "required": [
+                "treatments_Id"
+            ],
+            "properties": {
+                "Id": {
+                    "description": "@Description The unique identifier of the rule\n@Example 1",
+                    "type": "integer"
+                },
+                "MaxAmount": {
+                    "description": "@Description The most the provider pays per unit, in cents, 0 for no limit\n@Example 3000000",
+                    "type": "integer"
+                },
+                "Plan": {
+                    "description": "@Description The plan the rule applies to, empty for every plan of the provider. Rules of the plan win\n@Example \"310\"",
+                    "type": "string"
+                },
+                "Rate": {
+                    "description": "@Description The share of the price the provider pays, in basis points (8000 = 80%)\n@Example 8000",
+                    "type": "integer"
+                },
+                "insurance_providers_Id": {
+                    "description": "@Description The provider covering the treatment\n@Example 1",
+                    "type": "integer"
+                },
+                "treatments_Id": {
+                    "description": "@Description The treatment covered\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Dentist": {
+            "type": "object",
+            "required": [
+                "FirstName",
+                "LastName",
+                "License"
+            ],
+            "properties": {
+                "FirstName": {
+                    "description": "@Description The first name of the dentist\n@Example \"Daniel\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the dentist\n@Example 1",
+                    "type": "integer"
+                },
+                "LastName": {
+                    "description": "@Description The last name of the dentist\n@Example \"Rodríguez\"",
+                    "type": "string"
+                },
+                "License": {
+                    "description": "@Description The license number of the dentist\n@Example \"AXMER\"",
+                    "type": "string"
+                },
+                "Specialties": {
+                    "description": "@Description The specialties held by the dentist",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Specialty"
+                    }
+                }
+            }
+        },
+        "domain.Event": {
+            "type": "object",
+            "properties": {
+                "AggregateId": {
+                    "description": "@Description The identifier of the entity that changed\n@Example 1",
+                    "type": "integer"
+                },
+                "AggregateType": {
+                    "description": "@Description The kind of entity that changed\n@Example \"appointment\"",
+                    "type": "string"
+                },
+                "CreatedAt": {
+                    "description": "@Description When the change happened, in UTC\n@Example \"2024-03-28T15:04:05Z\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique, increasing identifier of the event\n@Example 42",
+                    "type": "integer"
+                },
+                "Payload": {
+                    "description": "@Description The event data, its shape depends on the type",
+                    "type": "object"
+                },
+                "Type": {
+                    "description": "@Description The event type\n@Example \"appointment.created\"",
+                    "type": "string"
+                },
+                "tenants_Id": {
+                    "description": "@Description The tenant the event belongs to",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.HistoryEntry": {
+            "type": "object",
+            "required": [
+                "Kind",
+                "Name",
+                "RecordedBy"
+            ],
+            "properties": {
+                "Alert": {
+                    "description": "@Description Whether the dentist must be warned before treating the patient. Allergies always warn\n@Example false",
+                    "type": "boolean"
+                },
+                "EndDate": {
+                    "description": "@Description The date it ended, e.g. a finished medication (dd/MM/YYYY, optional)\n@Example \"\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the entry\n@Example 1",
+                    "type": "integer"
+                },
+                "Kind": {
+                    "description": "@Description The kind of entry (allergy, medication or condition)\n@Example \"allergy\"",
+                    "type": "string"
+                },
+                "Name": {
+                    "description": "@Description What the patient is allergic to, takes or suffers from\n@Example \"Penicillin\"",
+                    "type": "string"
+                },
+                "Notes": {
+                    "description": "@Description Dosage, reactions or any other detail (optional)\n@Example \"Anaphylaxis in 2019\"",
+                    "type": "string"
+                },
+                "RecordedAt": {
+                    "description": "@Description When the entry was recorded",
+                    "type": "string"
+                },
+                "RecordedBy": {
+                    "description": "@Description Who recorded the entry\n@Example \"Dr. Daniel Rodríguez\"",
+                    "type": "string"
+                },
+                "Severity": {
+                    "description": "@Description How serious it is (mild, moderate or severe, optional)\n@Example \"severe\"",
+                    "type": "string"
+                },
+                "StartDate": {
+                    "description": "@Description The date it started or was diagnosed (dd/MM/YYYY, optional)\n@Example \"15/04/2019\"",
+                    "type": "string"
+                },
+                "UpdatedAt": {
+                    "description": "@Description When the entry was last changed",
+                    "type": "string"
+                },
+                "patients_Id": {
+                    "description": "@Description The patient the entry belongs to\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ImportItem": {
+            "type": "object",
+            "properties": {
+                "Date": {
+                    "description": "@Description The local date of the appointment (dd/MM/YYYY)\n@Example \"30/03/2024\"",
+                    "type": "string"
+                },
+                "Hour": {
+                    "description": "@Description The local time of the appointment\n@Example \"09:00\"",
+                    "type": "string"
+                },
+                "PatientId": {
+                    "description": "@Description The matched or created patient\n@Example 4",
+                    "type": "integer"
+                },
+                "Reason": {
+                    "description": "@Description Why the item was skipped or could not be booked\n@Example \"Dentist does not work at the requested date and time\"",
+                    "type": "string"
+                },
+                "Ref": {
+                    "description": "@Description The event UID or the line the item comes from\n@Example \"line 4\"",
+                    "type": "string"
+                },
+                "Status": {
+                    "description": "@Description The outcome: imported, skipped, conflict or invalid\n@Example \"invalid\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ImportReport": {
+            "type": "object",
+            "properties": {
+                "Conflicts": {
+                    "description": "@Description How many items failed the booking checks\n@Example 1",
+                    "type": "integer"
+                },
+                "CreatedPatients": {
+                    "description": "@Description How many placeholder patients were created for unknown attendees\n@Example 3",
+                    "type": "integer"
+                },
+                "DryRun": {
+                    "description": "@Description Whether nothing was written; imported items are the ones that would have been created\n@Example false",
+                    "type": "boolean"
+                },
+                "Imported": {
+                    "description": "@Description How many appointments, patients or dentists were created\n@Example 12",
+                    "type": "integer"
+                },
+                "Invalid": {
+                    "description": "@Description How many rows failed validation\n@Example 1",
+                    "type": "integer"
+                },
+                "Items": {
+                    "description": "@Description The outcome of every item, in the order they were read",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ImportItem"
+                    }
+                },
+                "Skipped": {
+                    "description": "@Description How many items were ignored, e.g. cancelled, already booked or duplicated\n@Example 2",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.InsuranceProvider": {
+            "type": "object",
+            "required": [
+                "Name"
+            ],
+            "properties": {
+                "Active": {
+                    "description": "@Description Whether the practice still accepts the provider. Invoices of inactive providers are not covered\n@Example true",
+                    "type": "boolean"
+                },
+                "Code": {
+                    "description": "@Description The code of the provider, e.g. its RNOS number (optional)\n@Example \"4-0080-0\"",
+                    "type": "string"
+                },
+                "Email": {
+                    "description": "@Description The email claims are sent to (optional)\n@Example \"prestadores@osde.com.ar\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the provider\n@Example 1",
+                    "type": "integer"
+                },
+                "Name": {
+                    "description": "@Description The name of the obra social or prepaid plan company\n@Example \"OSDE\"",
+                    "type": "string"
+                },
+                "Phone": {
+                    "description": "@Description The phone for authorizations (optional)\n@Example \"0810-555-6733\"",
+                    "type": "string"
+                },
+                "Rules": {
+                    "description": "@Description What the provider covers of each treatment",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CoverageRule"
+                    }
+                }
+            }
+        },
+        "domain.Invoice": {
+            "type": "object",
+            "properties": {
+                "AmountDue": {
+                    "description": "@Description What is left for the patient to pay of an issued invoice, in cents\n@Example 400000",
+                    "type": "integer"
+                },
+                "AmountPaid": {
+                    "description": "@Description What the patient paid, net of refunds, in cents\n@Example 2000000",
+                    "type": "integer"
+                },
+                "Covered": {
+                    "description": "@Description The share of the insurer, claimed to it, in cents\n@Example 3600000",
+                    "type": "integer"
+                },
+                "CreatedAt": {
+                    "description": "@Description When the draft was created",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the invoice\n@Example 1",
+                    "type": "integer"
+                },
+                "IssuedAt": {
+                    "description": "@Description When the invoice was issued, empty while draft",
+                    "type": "string"
+                },
+                "Lines": {
+                    "description": "@Description The items billed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.InvoiceLine"
+                    }
+                },
+                "Notes": {
+                    "description": "@Description Any note printed on the invoice (optional)\n@Example \"Root canal, first stage\"",
+                    "type": "string"
+                },
+                "Number": {
+                    "description": "@Description The number of the invoice, consecutive per practice and assigned when issued. 0 while draft\n@Example 42",
+                    "type": "integer"
+                },
+                "PaidAt": {
+                    "description": "@Description When the payments of the patient covered their share",
+                    "type": "string"
+                },
+                "PatientTotal": {
+                    "description": "@Description The share of the patient, Total minus Covered, in cents\n@Example 900000",
+                    "type": "integer"
+                },
+                "Status": {
+                    "description": "@Description draft, issued, paid or void\n@Example \"issued\"",
+                    "type": "string"
+                },
+                "Subtotal": {
+                    "description": "@Description The sum of the lines before taxes, in cents\n@Example 4500000",
+                    "type": "integer"
+                },
+                "Tax": {
+                    "description": "@Description The taxes of the lines, in cents\n@Example 0",
+                    "type": "integer"
+                },
+                "Total": {
+                    "description": "@Description The amount to pay, in cents\n@Example 4500000",
+                    "type": "integer"
+                },
+                "VoidReason": {
+                    "description": "@Description Why the invoice was voided\n@Example \"\"",
+                    "type": "string"
+                },
+                "VoidedAt": {
+                    "description": "@Description When the invoice was voided",
+                    "type": "string"
+                },
+                "patient_coverages_Id": {
+                    "description": "@Description The coverage the invoice is split with, 0 when the patient pays it all\n@Example 1",
+                    "type": "integer"
+                },
+                "patients_Id": {
+                    "description": "@Description The patient billed\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.InvoiceLine": {
+            "type": "object",
+            "required": [
+                "Description"
+            ],
+            "properties": {
+                "Covered": {
+                    "description": "@Description The share of the insurer, in cents\n@Example 3600000",
+                    "type": "integer"
+                },
+                "Description": {
+                    "description": "@Description What is billed\n@Example \"Root canal (30/03/2024)\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the line\n@Example 1",
+                    "type": "integer"
+                },
+                "Quantity": {
+                    "description": "@Description How many units are billed (defaults to 1)\n@Example 1",
+                    "type": "integer"
+                },
+                "Subtotal": {
+                    "description": "@Description Quantity times UnitPrice, in cents\n@Example 4500000",
+                    "type": "integer"
+                },
+                "Tax": {
+                    "description": "@Description The tax of the line, in cents\n@Example 0",
+                    "type": "integer"
+                },
+                "TaxRate": {
+                    "description": "@Description The tax rate in basis points (2100 = 21%)\n@Example 0",
+                    "type": "integer"
+                },
+                "Total": {
+                    "description": "@Description Subtotal plus Tax, in cents\n@Example 4500000",
+                    "type": "integer"
+                },
+                "UnitPrice": {
+                    "description": "@Description The price of a unit in cents\n@Example 4500000",
+                    "type": "integer"
+                },
+                "appointments_Id": {
+                    "description": "@Description The completed appointment billed, 0 for lines added by hand\n@Example 1",
+                    "type": "integer"
+                },
+                "invoices_Id": {
+                    "description": "@Description The invoice the line belongs to\n@Example 1",
+                    "type": "integer"
+                },
+                "treatments_Id": {
+                    "description": "@Description The treatment billed (optional)\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Odontogram": {
+            "type": "object",
+            "properties": {
+                "AsOf": {
+                    "description": "@Description The time the chart was built at; findings after it are not included",
+                    "type": "string"
+                },
+                "Teeth": {
+                    "description": "@Description The examined teeth, in FDI order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ToothState"
+                    }
+                },
+                "patients_Id": {
+                    "description": "@Description The patient of the chart\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Patient": {
+            "type": "object",
+            "required": [
+                "Address",
+                "DNI",
+                "FirstName",
+                "LastName",
+                "ReleaseDate"
+            ],
+            "properties": {
+                "Address": {
+                    "description": "@Description The address of the patient\n@Example \"Av. 22 # 40\"",
+                    "type": "string"
+                },
+                "DNI": {
+                    "description": "@Description The DNI of the patient\n@Example \"538434\"",
+                    "type": "string"
+                },
+                "Email": {
+                    "description": "@Description The email address used for appointment reminders (optional)\n@Example \"daniel@example.com\"",
+                    "type": "string"
+                },
+                "FirstName": {
+                    "description": "@Description The first name of the patient\n@Example \"Daniel\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the patient\n@Example 1",
+                    "type": "integer"
+                },
+                "LastName": {
+                    "description": "@Description The last name of the patient\n@Example \"Rodríguez\"",
+                    "type": "string"
+                },
+                "Phone": {
+                    "description": "@Description The mobile phone used for SMS reminders in international format (optional)\n@Example \"+5491155550000\"",
+                    "type": "string"
+                },
+                "ReleaseDate": {
+                    "description": "@Description The release date of the patient (dd/MM/YYYY)\n@Example \"30/03/2024\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.PatientBalance": {
+            "type": "object",
+            "properties": {
+                "Covered": {
+                    "description": "@Description The share of the insurers in those invoices, in cents\n@Example 3600000",
+                    "type": "integer"
+                },
+                "Invoiced": {
+                    "description": "@Description The total of the issued and paid invoices, in cents\n@Example 9000000",
+                    "type": "integer"
+                },
+                "OpenInvoices": {
+                    "description": "@Description The issued invoices not fully paid yet",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Invoice"
+                    }
+                },
+                "Outstanding": {
+                    "description": "@Description What the patient still owes, in cents\n@Example 4500000",
+                    "type": "integer"
+                },
+                "Paid": {
+                    "description": "@Description The payments of those invoices, net of refunds, in cents\n@Example 4500000",
+                    "type": "integer"
+                },
+                "UnbilledAppointments": {
+                    "description": "@Description The completed appointments with a treatment that were not invoiced yet\n@Example [12, 15]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "patients_Id": {
+                    "description": "@Description The patient\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Payment": {
+            "type": "object",
+            "properties": {
+                "Amount": {
+                    "description": "@Description The amount received or given back, in cents\n@Example 2000000",
+                    "type": "integer"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the entry\n@Example 1",
+                    "type": "integer"
+                },
+                "Kind": {
+                    "description": "@Description payment or refund\n@Example \"payment\"",
+                    "type": "string"
+                },
+                "Method": {
+                    "description": "@Description cash, card or transfer\n@Example \"card\"",
+                    "type": "string"
+                },
+                "Notes": {
+                    "description": "@Description Any note on the entry (optional)\n@Example \"First installment\"",
+                    "type": "string"
+                },
+                "Provider": {
+                    "description": "@Description The provider that processed a card payment\n@Example \"terminal\"",
+                    "type": "string"
+                },
+                "ReceivedAt": {
+                    "description": "@Description When the money was received or given back",
+                    "type": "string"
+                },
+                "Reference": {
+                    "description": "@Description The voucher, transaction or transfer number\n@Example \"000123\"",
+                    "type": "string"
+                },
+                "RefundOf": {
+                    "description": "@Description The payment a refund gives back, 0 for payments\n@Example 0",
+                    "type": "integer"
+                },
+                "Status": {
+                    "description": "@Description completed, or for card payments and refunds pending while the provider moves the money and voided when it could not\n@Example \"completed\"",
+                    "type": "string"
+                },
+                "clinics_Id": {
+                    "description": "@Description The clinic where the money was received or given back\n@Example 1",
+                    "type": "integer"
+                },
+                "invoices_Id": {
+                    "description": "@Description The invoice paid\n@Example 1",
+                    "type": "integer"
+                },
+                "patients_Id": {
+                    "description": "@Description The patient who paid\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.PlanStep": {
+            "type": "object",
+            "required": [
+                "Description"
+            ],
+            "properties": {
+                "Appointments": {
+                    "description": "@Description The appointments booked for the step",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.StepAppointment"
+                    }
+                },
+                "Description": {
+                    "description": "@Description What is done in the step\n@Example \"Implant placement\"",
+                    "type": "string"
+                },
+                "EstimatedCost": {
+                    "description": "@Description The estimated cost of the step, in cents\n@Example 90000",
+                    "type": "integer"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the step\n@Example 1",
+                    "type": "integer"
+                },
+                "Position": {
+                    "description": "@Description The position of the step in the plan, starting at 1\n@Example 1",
+                    "type": "integer"
+                },
+                "Skipped": {
+                    "description": "@Description Whether the step was dropped from the plan\n@Example false",
+                    "type": "boolean"
+                },
+                "Status": {
+                    "description": "@Description The status of the step (pending, scheduled, completed or skipped)\n@Example \"scheduled\"",
+                    "type": "string"
+                },
+                "treatment_plans_Id": {
+                    "description": "@Description The plan the step belongs to\n@Example 1",
+                    "type": "integer"
+                },
+                "treatments_Id": {
+                    "description": "@Description The treatment performed in the step (optional)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Treatment"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.Prescription": {
+            "type": "object",
+            "required": [
+                "Items"
+            ],
+            "properties": {
+                "Code": {
+                    "description": "@Description The code printed on the prescription to check it is genuine\n@Example \"K7QM-2XHP-9TRD\"",
+                    "type": "string"
+                },
+                "DentistName": {
+                    "description": "@Description The full name of the dentist, as it was when issued\n@Example \"Daniel Rodríguez\"",
+                    "type": "string"
+                },
+                "Diagnosis": {
+                    "description": "@Description What the medication is prescribed for (optional)\n@Example \"Acute apical abscess on 36\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the prescription\n@Example 1",
+                    "type": "integer"
+                },
+                "IssuedAt": {
+                    "description": "@Description When the prescription was issued",
+                    "type": "string"
+                },
+                "Items": {
+                    "description": "@Description The medications prescribed",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/domain.PrescriptionItem"
+                    }
+                },
+                "License": {
+                    "description": "@Description The license of the dentist, as it was when issued\n@Example \"AXMER\"",
+                    "type": "string"
+                },
+                "Notes": {
+                    "description": "@Description Any indication for the patient or the pharmacy (optional)\n@Example \"Generic substitution allowed\"",
+                    "type": "string"
+                },
+                "PatientName": {
+                    "description": "@Description The full name of the patient\n@Example \"Juan Perez\"",
+                    "type": "string"
+                },
+                "Status": {
+                    "description": "@Description The status of the prescription (issued or voided)\n@Example \"issued\"",
+                    "type": "string"
+                },
+                "VoidReason": {
+                    "description": "@Description Why the prescription was voided\n@Example \"\"",
+                    "type": "string"
+                },
+                "VoidedAt": {
+                    "description": "@Description When the prescription was voided",
+                    "type": "string"
+                },
+                "appointments_Id": {
+                    "description": "@Description The appointment the prescription was issued at\n@Example 1",
+                    "type": "integer"
+                },
+                "dentists_Id": {
+                    "description": "@Description The dentist who issued the prescription\n@Example 1",
+                    "type": "integer"
+                },
+                "patients_Id": {
+                    "description": "@Description The patient the prescription is for\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.PrescriptionCheck": {
+            "type": "object",
+            "properties": {
+                "Code": {
+                    "description": "@Description The code checked\n@Example \"K7QM-2XHP-9TRD\"",
+                    "type": "string"
+                },
+                "DentistName": {
+                    "description": "@Description The full name of the dentist who issued it\n@Example \"Daniel Rodríguez\"",
+                    "type": "string"
+                },
+                "IssuedAt": {
+                    "description": "@Description When the prescription was issued",
+                    "type": "string"
+                },
+                "Items": {
+                    "description": "@Description The medications prescribed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PrescriptionItem"
+                    }
+                },
+                "License": {
+                    "description": "@Description The license of the dentist who issued it\n@Example \"AXMER\"",
+                    "type": "string"
+                },
+                "Patient": {
+                    "description": "@Description The initials of the patient\n@Example \"J. P.\"",
+                    "type": "string"
+                },
+                "Status": {
+                    "description": "@Description The status of the prescription (issued or voided)\n@Example \"issued\"",
+                    "type": "string"
+                },
+                "Valid": {
+                    "description": "@Description Whether the prescription can be dispensed\n@Example true",
+                    "type": "boolean"
+                },
+                "VoidedAt": {
+                    "description": "@Description When the prescription was voided",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.PrescriptionItem": {
+            "type": "object",
+            "required": [
+                "Dose",
+                "Medication"
+            ],
+            "properties": {
+                "Dose": {
+                    "description": "@Description How much to take each time\n@Example \"1 tablet every 8 hours\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the item\n@Example 1",
+                    "type": "integer"
+                },
+                "Instructions": {
+                    "description": "@Description How and for how long to take it (optional)\n@Example \"Take with food for 7 days\"",
+                    "type": "string"
+                },
+                "Medication": {
+                    "description": "@Description The drug, with its strength and presentation\n@Example \"Amoxicillin 500 mg tablets\"",
+                    "type": "string"
+                },
+                "Quantity": {
+                    "description": "@Description The number of units or boxes to dispense (optional, defaults to 1)\n@Example 1",
+                    "type": "integer"
+                },
+                "prescriptions_Id": {
+                    "description": "@Description The prescription the item belongs to\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Resource": {
+            "type": "object",
+            "required": [
+                "Kind",
+                "Name"
+            ],
+            "properties": {
+                "Id": {
+                    "description": "@Description The unique identifier of the resource\n@Example 1",
+                    "type": "integer"
+                },
+                "Kind": {
+                    "description": "@Description The kind of resource (chair, room or equipment)\n@Example \"chair\"",
+                    "type": "string"
+                },
+                "Name": {
+                    "description": "@Description The name of the resource\n@Example \"Chair 1\"",
+                    "type": "string"
+                },
+                "clinics_Id": {
+                    "description": "@Description The clinic the resource belongs to",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Clinic"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.Schedule": {
             "type": "object",
             "required": [
-                "Date",
-                "Description",
-                "Hour",
-                "dentists_Id",
-                "patients_Id"
+                "EndHour",
+                "StartHour",
+                "clinics_Id"
             ],
             "properties": {
-                "Date": {
-                    "description": "@Description The release date of the patient (dd/MM/YYYY)\n@Example \"30/03/2024\"",
+                "EndHour": {
+                    "description": "@Description The end of the schedule in 24-hour format\n@Example \"13:00\"",
                     "type": "string"
                 },
-                "Description": {
-                    "description": "@Description The description of the appointment\n@Example \"Routine checkup\"",
+                "Id": {
+                    "description": "@Description The unique identifier of the schedule\n@Example 1",
+                    "type": "integer"
+                },
+                "StartHour": {
+                    "description": "@Description The start of the schedule in 24-hour format\n@Example \"09:00\"",
+                    "type": "string"
+                },
+                "Weekday": {
+                    "description": "@Description The day of the week (0 = Sunday ... 6 = Saturday)\n@Example 1",
+                    "type": "integer"
+                },
+                "clinics_Id": {
+                    "description": "@Description The clinic where the dentist works in this schedule",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Clinic"
+                        }
+                    ]
+                },
+                "dentists_Id": {
+                    "description": "@Description The dentist working in this schedule",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.SignaturePoint": {
+            "type": "object",
+            "properties": {
+                "X": {
+                    "type": "number"
+                },
+                "Y": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.Slot": {
+            "type": "object",
+            "properties": {
+                "Date": {
+                    "description": "@Description The local date of the slot (dd/MM/YYYY)\n@Example \"30/03/2024\"",
                     "type": "string"
                 },
                 "Hour": {
-                    "description": "@Description The time of the appointment in 24-hour format\n@Example \"09:00\"",
+                    "description": "@Description The local time of the slot in 24-hour format\n@Example \"09:00\"",
+                    "type": "string"
+                },
+                "StartsAt": {
+                    "description": "@Description The start of the slot in UTC\n@Example \"2024-03-30T12:00:00Z\"",
+                    "type": "string"
+                },
+                "TimeZone": {
+                    "description": "@Description The time zone Date and Hour are expressed in\n@Example \"America/Argentina/Buenos_Aires\"",
+                    "type": "string"
+                },
+                "clinics_Id": {
+                    "description": "@Description The clinic where the slot is available",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Clinic"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.Specialty": {
+            "type": "object",
+            "required": [
+                "Name"
+            ],
+            "properties": {
+                "Id": {
+                    "description": "@Description The unique identifier of the specialty\n@Example 1",
+                    "type": "integer"
+                },
+                "Name": {
+                    "description": "@Description The name of the specialty\n@Example \"orthodontics\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.StepAppointment": {
+            "type": "object",
+            "properties": {
+                "CompletedAt": {
+                    "description": "@Description When the appointment was completed, empty if it was not",
                     "type": "string"
                 },
                 "Id": {
-                    "description": "@Description The unique identifier of the appointment\n@Example 1",
+                    "description": "@Description The appointment\n@Example 1",
                     "type": "integer"
                 },
-                "dentists_Id": {
-                    "description": "@Description Information related to the patient",
+                "StartsAt": {
+                    "description": "@Description The start of the appointment in UTC",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Tenant": {
+            "type": "object",
+            "required": [
+                "Name"
+            ],
+            "properties": {
+                "CreatedAt": {
+                    "description": "@Description The date the tenant was provisioned\n@Example \"2024-01-02T15:04:05Z\"",
+                    "type": "string"
+                },
+                "DisabledAt": {
+                    "description": "@Description When the tenant was disabled, empty while active. A disabled tenant keeps its records but its token no longer works\n@Example \"\"",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the tenant (dental practice)\n@Example 1",
+                    "type": "integer"
+                },
+                "Name": {
+                    "description": "@Description The name of the dental practice\n@Example \"Sonrisas Dental\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TenantCredentials": {
+            "type": "object",
+            "properties": {
+                "Tenant": {
+                    "description": "@Description The tenant the token belongs to",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.Dentist"
+                            "$ref": "#/definitions/domain.Tenant"
+                        }
+                    ]
+                },
+                "Token": {
+                    "description": "@Description The token to send in the TOKEN header\n@Example \"3f0c8e4a...\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TimelineEntry": {
+            "type": "object",
+            "properties": {
+                "Appointment": {
+                    "description": "@Description The appointment of the visit, rendered in the clinic time zone",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Appointment"
                         }
                     ]
                 },
+                "Notes": {
+                    "description": "@Description The clinical notes of the visit, oldest first, with their amendments",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ClinicalNote"
+                    }
+                }
+            }
+        },
+        "domain.ToothFinding": {
+            "type": "object",
+            "required": [
+                "Condition",
+                "Tooth"
+            ],
+            "properties": {
+                "Condition": {
+                    "description": "@Description What was found (healthy, caries, filling, crown or extracted)\n@Example \"caries\"",
+                    "type": "string"
+                },
+                "ExaminedAt": {
+                    "description": "@Description When the finding was made, the start of the appointment",
+                    "type": "string"
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the finding\n@Example 1",
+                    "type": "integer"
+                },
+                "Notes": {
+                    "description": "@Description Any detail about the finding (optional)\n@Example \"Deep lesion, close to the pulp\"",
+                    "type": "string"
+                },
+                "Surfaces": {
+                    "description": "@Description The affected surfaces (M, D, O, V, L), empty for the whole tooth\n@Example \"MO\"",
+                    "type": "string"
+                },
+                "Tooth": {
+                    "description": "@Description The tooth, in FDI two-digit notation (11-48 permanent, 51-85 deciduous)\n@Example 36",
+                    "type": "integer"
+                },
+                "appointments_Id": {
+                    "description": "@Description The appointment the finding was recorded in\n@Example 1",
+                    "type": "integer"
+                },
+                "dentists_Id": {
+                    "description": "@Description The dentist who recorded the finding\n@Example 1",
+                    "type": "integer"
+                },
                 "patients_Id": {
-                    "description": "@Description Information related to the patient",
+                    "description": "@Description The patient the finding belongs to\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ToothState": {
+            "type": "object",
+            "properties": {
+                "Condition": {
+                    "description": "@Description The condition of the whole tooth (crown or extracted), empty when it is recorded per surface\n@Example \"\"",
+                    "type": "string"
+                },
+                "Surfaces": {
+                    "description": "@Description The condition of each affected surface",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "Tooth": {
+                    "description": "@Description The tooth, in FDI two-digit notation\n@Example 36",
+                    "type": "integer"
+                },
+                "UpdatedAt": {
+                    "description": "@Description When the tooth was last examined",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Treatment": {
+            "type": "object",
+            "required": [
+                "Name"
+            ],
+            "properties": {
+                "Id": {
+                    "description": "@Description The unique identifier of the treatment\n@Example 1",
+                    "type": "integer"
+                },
+                "Name": {
+                    "description": "@Description The name of the treatment\n@Example \"Root canal\"",
+                    "type": "string"
+                },
+                "Price": {
+                    "description": "@Description The price of the treatment in cents, billed when an appointment for it is completed\n@Example 4500000",
+                    "type": "integer"
+                },
+                "TaxRate": {
+                    "description": "@Description The tax rate applied to the price, in basis points (2100 = 21%)\n@Example 0",
+                    "type": "integer"
+                },
+                "specialties_Id": {
+                    "description": "@Description The specialty a dentist must hold to perform the treatment (empty if any dentist can)",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.Patient"
+                            "$ref": "#/definitions/domain.Specialty"
                         }
                     ]
                 }
             }
         },
-        "domain.Dentist": {
+        "domain.TreatmentPlan": {
             "type": "object",
             "required": [
-                "FirstName",
-                "LastName",
-                "License"
+                "Steps",
+                "Title"
             ],
             "properties": {
-                "FirstName": {
-                    "description": "@Description The first name of the dentist\n@Example \"Daniel\"",
+                "CompletedSteps": {
+                    "description": "@Description The number of completed steps\n@Example 1",
+                    "type": "integer"
+                },
+                "CreatedAt": {
+                    "description": "@Description When the plan was created",
                     "type": "string"
                 },
+                "EstimatedTotal": {
+                    "description": "@Description The estimated cost of the steps not skipped, in cents\n@Example 150000",
+                    "type": "integer"
+                },
                 "Id": {
-                    "description": "@Description The unique identifier of the dentist\n@Example 1",
+                    "description": "@Description The unique identifier of the plan\n@Example 1",
                     "type": "integer"
                 },
-                "LastName": {
-                    "description": "@Description The last name of the dentist\n@Example \"Rodríguez\"",
+                "Notes": {
+                    "description": "@Description Any detail about the plan (optional)\n@Example \"Bone graft may be needed\"",
                     "type": "string"
                 },
-                "License": {
-                    "description": "@Description The license number of the dentist\n@Example \"AXMER\"",
+                "Progress": {
+                    "description": "@Description The percentage of the steps not skipped that are completed\n@Example 33",
+                    "type": "integer"
+                },
+                "Status": {
+                    "description": "@Description The status of the plan (active, completed or cancelled)\n@Example \"active\"",
+                    "type": "string"
+                },
+                "Steps": {
+                    "description": "@Description The ordered steps of the plan",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PlanStep"
+                    }
+                },
+                "Title": {
+                    "description": "@Description A short name for the plan\n@Example \"Implant on 46\"",
                     "type": "string"
+                },
+                "patients_Id": {
+                    "description": "@Description The patient the plan is for\n@Example 1",
+                    "type": "integer"
                 }
             }
         },
-        "domain.Patient": {
+        "domain.Webhook": {
             "type": "object",
             "required": [
-                "Address",
-                "DNI",
-                "FirstName",
-                "LastName",
-                "ReleaseDate"
+                "EventTypes",
+                "URL"
             ],
             "properties": {
-                "Address": {
-                    "description": "@Description The address of the patient\n@Example \"Av. 22 # 40\"",
+                "Active": {
+                    "description": "@Description Whether events are delivered to the webhook\n@Example true",
+                    "type": "boolean"
+                },
+                "CreatedAt": {
+                    "description": "@Description When the webhook was created, in UTC\n@Example \"2024-03-28T15:04:05Z\"",
                     "type": "string"
                 },
-                "DNI": {
-                    "description": "@Description The DNI of the patient\n@Example \"538434\"",
+                "EventTypes": {
+                    "description": "@Description The event types delivered to the webhook, \"*\" subscribes to every event\n@Example [\"appointment.created\",\"appointment.cancelled\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "Id": {
+                    "description": "@Description The unique identifier of the webhook\n@Example 1",
+                    "type": "integer"
+                },
+                "Secret": {
+                    "description": "@Description The secret used to sign the deliveries, generated when empty and only returned on creation\n@Example \"whsec_3f0c8e4a...\"",
                     "type": "string"
                 },
-                "FirstName": {
-                    "description": "@Description The first name of the patient\n@Example \"Daniel\"",
+                "URL": {
+                    "description": "@Description The URL the events are posted to\n@Example \"https://example.com/hooks/dental\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.WebhookAttempt": {
+            "type": "object",
+            "properties": {
+                "CreatedAt": {
+                    "description": "@Description When the attempt was made, in UTC",
+                    "type": "string"
+                },
+                "DurationMs": {
+                    "description": "@Description How long the request took, in milliseconds\n@Example 120",
+                    "type": "integer"
+                },
+                "Error": {
+                    "description": "@Description Why the attempt failed\n@Example \"unexpected status 500\"",
+                    "type": "string"
+                },
+                "StatusCode": {
+                    "description": "@Description The HTTP status code of the response, 0 if no response was received\n@Example 500",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "AttemptLog": {
+                    "description": "@Description The attempts made, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.WebhookAttempt"
+                    }
+                },
+                "Attempts": {
+                    "description": "@Description How many times the delivery was attempted\n@Example 1",
+                    "type": "integer"
+                },
+                "DeliveredAt": {
+                    "description": "@Description When the event was delivered, in UTC",
                     "type": "string"
                 },
+                "Event": {
+                    "description": "@Description The delivered event",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Event"
+                        }
+                    ]
+                },
                 "Id": {
-                    "description": "@Description The unique identifier of the patient\n@Example 1",
+                    "description": "@Description The unique identifier of the delivery, sent in the X-Webhook-Delivery header\n@Example 7",
                     "type": "integer"
                 },
-                "LastName": {
-                    "description": "@Description The last name of the patient\n@Example \"Rodríguez\"",
+                "LastError": {
+                    "description": "@Description The error of the last attempt",
                     "type": "string"
                 },
-                "ReleaseDate": {
-                    "description": "@Description The release date of the patient (dd/MM/YYYY)\n@Example \"30/03/2024\"",
+                "LastStatusCode": {
+                    "description": "@Description The HTTP status code of the last attempt, 0 if no response was received\n@Example 200",
+                    "type": "integer"
+                },
+                "NextAttemptAt": {
+                    "description": "@Description When the delivery is attempted next, in UTC",
+                    "type": "string"
+                },
+                "Status": {
+                    "description": "@Description The status of the delivery: pending, delivered or failed\n@Example \"delivered\"",
                     "type": "string"
+                },
+                "WebhookId": {
+                    "description": "@Description The webhook the event is delivered to\n@Example 1",
+                    "type": "integer"
+                },
+                "tenants_Id": {
+                    "description": "@Description The tenant the delivery belongs to",
+                    "type": "integer"
                 }
             }
         }
@@ -3,11 +3,12 @@ package handler
 import (
 	"errors"
 	"net/http"
-	"os"
 	"strconv"
+	"time"
 
 	"proyecto_final_go/internal/domain"
 	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,7 +23,7 @@ func NewAppointmentHandler(appointmentService service.AppointmentService) *appoi
 
 // Post godoc
 // @Summary Create a new appointment
-// @Description This endpoint allows you to create a new appointment with the provided data.
+// @Description This endpoint allows you to create a new appointment with the provided data. A free chair is reserved automatically when no chair is requested. Set plan_steps_Id to book it for a step of a treatment plan of the patient; the step treatment is used when none is given.
 // @Tags Appointments
 // @Produce json
 // @Param token header string true "TOKEN"
@@ -34,31 +35,23 @@ func NewAppointmentHandler(appointmentService service.AppointmentService) *appoi
 // @Router /appointments [post]
 func (h *appointmentHandler) Post() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.GetHeader("TOKEN")
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "token not found"})
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			return
-		}
+		tenantID := middleware.TenantID(c)
 		var appointment domain.Appointment
 		if err := c.ShouldBindJSON(&appointment); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Error processing appointment data. Please ensure you send valid JSON with all required fields."})
 			return
 		}
 		if appointment.Patient == (domain.Patient{}) ||
-			appointment.Dentist == (domain.Dentist{}) ||
+			appointment.Dentist.Id == 0 ||
 			appointment.Date == "" ||
 			appointment.Hour == "" ||
 			appointment.Description == "" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "missing required fields"})
 			return
 		}
-		err := h.appointmentService.Create(appointment)
+		err := h.appointmentService.Create(tenantID, appointment)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "error creating appointment"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error creating appointment: " + err.Error()})
 			return
 		}
 
@@ -77,6 +70,8 @@ func (h *appointmentHandler) Post() gin.HandlerFunc {
 // @Param        date query string true "Appointment date"
 // @Param        hour query string true "Appointment hour"
 // @Param        description query string true "Appointment description"
+// @Param        treatment_id query int false "Treatment ID"
+// @Param        tz query string false "Time zone of date and hour (IANA name), defaults to the clinic time zone"
 // @Success      201 {object} domain.Appointment "Appointment created successfully"
 // @Failure      400 "Invalid parameters or missing required fields"
 // @Failure      401 "Token not found or invalid token"
@@ -84,26 +79,42 @@ func (h *appointmentHandler) Post() gin.HandlerFunc {
 // @Router       /appointments/dnilicense [post]
 func (h *appointmentHandler) PostByDNIAndLicense() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.GetHeader("TOKEN")
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "token not found"})
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			return
-		}
+		tenantID := middleware.TenantID(c)
 		patientDNI := c.Query("patient_dni")
 		license := c.Query("license")
 		date := c.Query("date")
 		hour := c.Query("hour")
 		description := c.Query("description")
+		treatmentID := 0
+		if treatmentParam := c.Query("treatment_id"); treatmentParam != "" {
+			id, err := strconv.Atoi(treatmentParam)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid treatment id"})
+				return
+			}
+			treatmentID = id
+		}
+
+		loc, err := requestedZone(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		appointment := domain.Appointment{
+			Date:        date,
+			Hour:        hour,
+			TimeZone:    c.Query("tz"),
+			Description: description,
+			Treatment:   domain.Treatment{Id: treatmentID},
+		}
 
-		appointments, err := h.appointmentService.CreateByPatientDNIAndDentistLicense(patientDNI, license, date, hour, description)
+		appointments, err := h.appointmentService.CreateByPatientDNIAndDentistLicense(tenantID, patientDNI, license, appointment)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		localize(appointments, loc)
 
 		c.JSON(http.StatusCreated, appointments)
 	}
@@ -111,47 +122,170 @@ func (h *appointmentHandler) PostByDNIAndLicense() gin.HandlerFunc {
 
 // GetByID godoc
 // @Summary Get an appointment by ID
-// @Description This endpoint allows you to retrieve an appointment by its ID.
+// @Description This endpoint allows you to retrieve an appointment by its ID. MedicalAlert and Alerts tell about the allergies and conditions of the patient to check before treating them.
 // @Tags Appointments
 // @Produce json
+// @Param token header string true "TOKEN"
 // @Param id path int true "Appointment ID"
+// @Param tz query string false "Time zone to render Date and Hour in (IANA name), defaults to the clinic time zone"
 // @Success 200 {object} domain.Appointment "Appointment"
-// @Failure 400 "Invalid ID"
+// @Failure 400 "Invalid ID or time zone"
 // @Failure 404 "Appointment not found"
 // @Router /appointments/{id} [get]
 func (h *appointmentHandler) GetByID() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		tenantID := middleware.TenantID(c)
 		idParam := c.Param("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, errors.New("invalid appointment id"))
 			return
 		}
-		appointment, err := h.appointmentService.GetByID(id)
+		loc, err := requestedZone(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		appointment, err := h.appointmentService.GetByID(tenantID, id)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "appointment not found"})
+			return
+		}
+		if loc != nil {
+			appointment.Localize(loc)
+		}
+
+		c.JSON(http.StatusOK, appointment)
+	}
+}
+
+// CheckIn godoc
+// @Summary Check in the patient of an appointment
+// @Description This endpoint records the arrival of the patient on the day of the appointment. When the treatment requires consent forms, check-in is refused until every one of them is signed for the appointment.
+// @Tags Appointments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Success 200 {object} domain.Appointment "Checked in appointment"
+// @Failure 400 "Invalid ID, not the day of the appointment, already checked in or consent not signed"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Appointment not found"
+// @Router /appointments/{id}/check-in [post]
+func (h *appointmentHandler) CheckIn() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := middleware.TenantID(c)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment id"})
+			return
+		}
+		if _, err := h.appointmentService.GetByID(tenantID, id); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "appointment not found"})
+			return
+		}
+
+		appointment, err := h.appointmentService.CheckIn(tenantID, id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, appointment)
+	}
+}
+
+// Complete godoc
+// @Summary Complete an appointment
+// @Description This endpoint marks an appointment that already started as completed. When it was booked for a treatment plan step, the step is completed and the plan progress updated.
+// @Tags Appointments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Success 200 {object} domain.Appointment "Completed appointment"
+// @Failure 400 "Invalid ID, appointment not started yet or already completed"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Appointment not found"
+// @Router /appointments/{id}/complete [post]
+func (h *appointmentHandler) Complete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := middleware.TenantID(c)
+		id, err := strconv.Atoi(c.Param("id"))
 		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment id"})
+			return
+		}
+		if _, err := h.appointmentService.GetByID(tenantID, id); err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "appointment not found"})
 			return
 		}
 
+		appointment, err := h.appointmentService.Complete(tenantID, id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		c.JSON(http.StatusOK, appointment)
 	}
 }
 
 // GetAll godoc
 // @Summary Get all appointments
-// @Description This endpoint allows you to retrieve all appointments.
+// @Description This endpoint allows you to retrieve all appointments, optionally filtered by clinic, dentist or date, as JSON or, with format=csv|xlsx or an Accept header of text/csv or the XLSX type, as a spreadsheet streamed from the database.
 // @Tags Appointments
 // @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param token header string true "TOKEN"
+// @Param clinic query int false "Clinic ID"
+// @Param dentist query int false "Dentist ID"
+// @Param date query string false "Date (dd/MM/YYYY) in the requested or clinic time zone"
+// @Param tz query string false "Time zone to render Date and Hour in (IANA name), defaults to the clinic time zone"
+// @Param format query string false "json, csv or xlsx"
 // @Success 200 {array} domain.Appointment "Appointments"
+// @Failure 400 "Invalid filter or format"
 // @Failure 500 "Failed to retrieve appointments"
 // @Router /appointments [get]
 func (h *appointmentHandler) GetAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		appointments, err := h.appointmentService.GetAll()
+		tenantID := middleware.TenantID(c)
+		filter, err := appointmentFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		loc, err := requestedZone(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		format, err := exportFormat(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if format != "" {
+			streamExport(c, format, "appointments", appointmentColumns, func(write func([]string) error) error {
+				return h.appointmentService.Each(tenantID, filter, func(a domain.Appointment) error {
+					if loc != nil {
+						a.Localize(loc)
+					}
+					return write(appointmentRecord(a))
+				})
+			})
+			return
+		}
+		var appointments []domain.Appointment
+		if filter != (domain.AppointmentFilter{}) {
+			appointments, err = h.appointmentService.Search(tenantID, filter)
+		} else {
+			appointments, err = h.appointmentService.GetAll(tenantID)
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve all appointments."})
 			return
 		}
+		localize(appointments, loc)
 
 		c.JSON(http.StatusOK, appointments)
 	}
@@ -171,16 +305,7 @@ func (h *appointmentHandler) GetAll() gin.HandlerFunc {
 // @Router /appointments [put]
 func (h *appointmentHandler) Put() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.GetHeader("TOKEN")
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, errors.New("token not found"))
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			c.JSON(http.StatusUnauthorized, errors.New("invalid token"))
-			return
-		}
-
+		tenantID := middleware.TenantID(c)
 		var appointment domain.Appointment
 		if err := c.ShouldBindJSON(&appointment); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment data"})
@@ -192,7 +317,7 @@ func (h *appointmentHandler) Put() gin.HandlerFunc {
 			return
 		}
 
-		err := h.appointmentService.Update(appointment)
+		err := h.appointmentService.Update(tenantID, appointment)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update appointment"})
 			return
@@ -204,33 +329,30 @@ func (h *appointmentHandler) Put() gin.HandlerFunc {
 
 // Delete godoc
 // @Summary Delete an appointment
-// @Description This endpoint allows you to delete an appointment by its ID.
+// @Description This endpoint allows you to delete an appointment by its ID. Appointments with clinical notes, odontogram findings, attachments, signed consents, invoices or prescriptions are kept, as those records must be.
 // @Tags Appointments
 // @Param token header string true "TOKEN"
 // @Param id path int true "Appointment ID"
 // @Success 204 "Appointment deleted successfully"
 // @Failure 400 "Invalid ID"
 // @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 409 "The appointment has records of the visit"
 // @Failure 500 "Failed to delete appointment"
 // @Router /appointments/{id} [delete]
 func (h *appointmentHandler) Delete() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.GetHeader("TOKEN")
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, errors.New("token not found"))
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			c.JSON(http.StatusUnauthorized, errors.New("invalid token"))
-			return
-		}
+		tenantID := middleware.TenantID(c)
 		idParam := c.Param("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, errors.New("invalid appointment id"))
 			return
 		}
-		err = h.appointmentService.Delete(id)
+		err = h.appointmentService.Delete(tenantID, id)
+		if errors.Is(err, domain.ErrAppointmentHasRecords) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusBadRequest, errors.New("failed to delete appointment"))
 			return
@@ -244,7 +366,9 @@ func (h *appointmentHandler) Delete() gin.HandlerFunc {
 // @Summary Get appointments by patient DNI
 // @Description This endpoint allows you to retrieve appointments for a patient by their DNI.
 // @Tags Appointments
+// @Param token header string true "TOKEN"
 // @Param dni query string true "Patient DNI"
+// @Param tz query string false "Time zone to render Date and Hour in (IANA name), defaults to the clinic time zone"
 // @Produce json
 // @Success 200 {array} domain.Appointment "Appointments"
 // @Failure 400 "DNI parameter is required"
@@ -252,16 +376,23 @@ func (h *appointmentHandler) Delete() gin.HandlerFunc {
 // @Router /appointments/patient [get]
 func (h *appointmentHandler) GetByPatientDNI() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		tenantID := middleware.TenantID(c)
 		dni := c.Query("dni")
 		if dni == "" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "DNI parameter is required"})
 			return
 		}
-		appointments, err := h.appointmentService.GetByPatientDNI(dni)
+		loc, err := requestedZone(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		appointments, err := h.appointmentService.GetByPatientDNI(tenantID, dni)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "no appointments found for this patient DNI"})
 			return
 		}
+		localize(appointments, loc)
 		c.JSON(http.StatusOK, appointments)
 	}
 }
@@ -286,16 +417,7 @@ func (h *appointmentHandler) PatchDescription() gin.HandlerFunc {
 	}
 
 	return func(c *gin.Context) {
-		token := c.GetHeader("TOKEN")
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, errors.New("token not found"))
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			c.JSON(http.StatusUnauthorized, errors.New("invalid token"))
-			return
-		}
-
+		tenantID := middleware.TenantID(c)
 		var r Request
 		idParam := c.Param("id")
 		id, err := strconv.Atoi(idParam)
@@ -313,14 +435,14 @@ func (h *appointmentHandler) PatchDescription() gin.HandlerFunc {
 			return
 		}
 
-		oldAppointment, err := h.appointmentService.GetByID(id)
+		oldAppointment, err := h.appointmentService.GetByID(tenantID, id)
 		if err != nil {
 			c.JSON(http.StatusNotFound, errors.New("appointment not found"))
 			return
 		}
 
 		if r.Description != oldAppointment.Description {
-			err = h.appointmentService.PatchDescription(id, r.Description)
+			err = h.appointmentService.PatchDescription(tenantID, id, r.Description)
 			if err != nil {
 				c.JSON(http.StatusBadRequest, errors.New("failed to update description"))
 				return
@@ -332,3 +454,47 @@ func (h *appointmentHandler) PatchDescription() gin.HandlerFunc {
 		}
 	}
 }
+
+// appointmentFilter reads the clinic, dentist and date filters from the query string.
+func appointmentFilter(c *gin.Context) (domain.AppointmentFilter, error) {
+	filter := domain.AppointmentFilter{Date: c.Query("date"), TimeZone: c.Query("tz")}
+	if clinicParam := c.Query("clinic"); clinicParam != "" {
+		clinicID, err := strconv.Atoi(clinicParam)
+		if err != nil {
+			return domain.AppointmentFilter{}, errors.New("invalid clinic id")
+		}
+		filter.ClinicId = clinicID
+	}
+	if dentistParam := c.Query("dentist"); dentistParam != "" {
+		dentistID, err := strconv.Atoi(dentistParam)
+		if err != nil {
+			return domain.AppointmentFilter{}, errors.New("invalid dentist id")
+		}
+		filter.DentistId = dentistID
+	}
+	return filter, nil
+}
+
+// requestedZone returns the time zone requested with ?tz=, or nil to keep
+// rendering appointments in the time zone of their clinic.
+func requestedZone(c *gin.Context) (*time.Location, error) {
+	tz := c.Query("tz")
+	if tz == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, errors.New("invalid time zone")
+	}
+	return loc, nil
+}
+
+// localize renders the appointments in loc when a time zone was requested.
+func localize(appointments []domain.Appointment, loc *time.Location) {
+	if loc == nil {
+		return
+	}
+	for i := range appointments {
+		appointments[i].Localize(loc)
+	}
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type recordsAppointmentService struct {
+	service.AppointmentService
+}
+
+func (s *recordsAppointmentService) Delete(tenantID int, id int) error {
+	return fmt.Errorf("%w: clinical notes, invoices", domain.ErrAppointmentHasRecords)
+}
+
+func TestCancellingAnAppointmentWithRecordsIsAConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.DELETE("/appointments/:id", NewAppointmentHandler(&recordsAppointmentService{}).Delete())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/appointments/3", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("DELETE /appointments/3 = %d, want 409", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "clinical notes, invoices") {
+		t.Errorf("body = %s, want the records that are kept", w.Body.String())
+	}
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type attachmentHandler struct {
+	s service.AttachmentService
+}
+
+func NewAttachmentHandler(s service.AttachmentService) *attachmentHandler {
+	return &attachmentHandler{
+		s: s,
+	}
+}
+
+// PostToPatient godoc
+// @Summary Attach a file to a patient
+// @Description This endpoint uploads an X-ray, a signed consent form, a photo or any other document of the patient as multipart/form-data. JPEG, PNG, WebP, PDF and DICOM files are accepted, detected from their content.
+// @Tags Attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Param file formData file true "File to attach"
+// @Param kind formData string false "xray, consent, photo or document (default)"
+// @Param description formData string false "Description of the file"
+// @Success 201 {object} domain.Attachment "Attachment"
+// @Failure 400 "Missing file, unsupported type or unknown patient"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 413 "File too large"
+// @Router /patients/{id}/attachments [post]
+func (h *attachmentHandler) PostToPatient() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		h.upload(ctx, domain.Attachment{PatientId: patientID})
+	}
+}
+
+// PostToAppointment godoc
+// @Summary Attach a file to an appointment
+// @Description This endpoint uploads a file taken during the appointment, e.g. an X-ray, as multipart/form-data. It is also listed with the files of the patient.
+// @Tags Attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Param file formData file true "File to attach"
+// @Param kind formData string false "xray, consent, photo or document (default)"
+// @Param description formData string false "Description of the file"
+// @Success 201 {object} domain.Attachment "Attachment"
+// @Failure 400 "Missing file, unsupported type or unknown appointment"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 413 "File too large"
+// @Router /appointments/{id}/attachments [post]
+func (h *attachmentHandler) PostToAppointment() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		appointmentID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		h.upload(ctx, domain.Attachment{AppointmentId: appointmentID})
+	}
+}
+
+func (h *attachmentHandler) upload(ctx *gin.Context, attachment domain.Attachment) {
+	tenantID := middleware.TenantID(ctx)
+	// The form fields and the multipart framing get some room over the file.
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.s.MaxSize()+64<<10)
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if header.Size > h.s.MaxSize() {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file"})
+		return
+	}
+	defer file.Close()
+
+	attachment.FileName = filepath.Base(filepath.Clean("/" + header.Filename))
+	if len(attachment.FileName) > 255 {
+		attachment.FileName = attachment.FileName[len(attachment.FileName)-255:]
+	}
+	attachment.Kind = ctx.PostForm("kind")
+	attachment.Description = ctx.PostForm("description")
+
+	created, err := h.s.Upload(ctx.Request.Context(), tenantID, attachment, file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// GetByPatient godoc
+// @Summary Get the attachments of a patient
+// @Description This endpoint lists the files of the patient, including those of their appointments, the most recent first.
+// @Tags Attachments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Success 200 {array} domain.Attachment "Attachments"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Patient not found"
+// @Router /patients/{id}/attachments [get]
+func (h *attachmentHandler) GetByPatient() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		attachments, err := h.s.GetByPatient(tenantID, patientID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, attachments)
+	}
+}
+
+// GetByAppointment godoc
+// @Summary Get the attachments of an appointment
+// @Description This endpoint lists the files attached to the appointment, the most recent first.
+// @Tags Attachments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Success 200 {array} domain.Attachment "Attachments"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Appointment not found"
+// @Router /appointments/{id}/attachments [get]
+func (h *attachmentHandler) GetByAppointment() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		appointmentID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		attachments, err := h.s.GetByAppointment(tenantID, appointmentID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "appointment not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, attachments)
+	}
+}
+
+// GetByID godoc
+// @Summary Get an attachment
+// @Description This endpoint returns the details of an attached file, without its content.
+// @Tags Attachments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Attachment ID"
+// @Success 200 {object} domain.Attachment "Attachment"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Attachment not found"
+// @Router /attachments/{id} [get]
+func (h *attachmentHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		attachment, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "attachment not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, attachment)
+	}
+}
+
+// Download godoc
+// @Summary Download an attachment
+// @Description This endpoint streams the content of an attached file, checked against the checksum taken on upload as it is sent: a corrupted file ends the response before its last byte. The checksum is also sent as the ETag.
+// @Tags Attachments
+// @Produce octet-stream
+// @Param token header string true "TOKEN"
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} file "File content"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Attachment not found"
+// @Failure 500 "The stored file is missing or corrupted"
+// @Router /attachments/{id}/content [get]
+func (h *attachmentHandler) Download() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		if _, err := h.s.GetByID(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "attachment not found"})
+			return
+		}
+
+		attachment, content, err := h.s.Download(ctx.Request.Context(), tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		defer content.Close()
+
+		ctx.Header("X-Content-Type-Options", "nosniff")
+		ctx.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+			"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+			"ETag":                `"` + attachment.Checksum + `"`,
+		})
+	}
+}
+
+// Delete godoc
+// @Summary Delete an attachment
+// @Description This endpoint removes an attached file and its content.
+// @Tags Attachments
+// @Param token header string true "TOKEN"
+// @Param id path int true "Attachment ID"
+// @Success 204 "Attachment deleted successfully"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Attachment not found"
+// @Router /attachments/{id} [delete]
+func (h *attachmentHandler) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		if _, err := h.s.GetByID(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "attachment not found"})
+			return
+		}
+		if err := h.s.Delete(ctx.Request.Context(), tenantID, id); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
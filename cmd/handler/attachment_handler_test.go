@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/blob"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeAttachmentRepository struct {
+	repository.AttachmentRepository
+	attachment domain.Attachment
+}
+
+func (r *fakeAttachmentRepository) GetByID(tenantID int, id int) (domain.Attachment, error) {
+	return r.attachment, nil
+}
+
+// newDownloadServer serves the attachment download of a file stored with
+// the content "original", replaced afterwards by stored.
+func newDownloadServer(t *testing.T, stored string) *httptest.Server {
+	dir := t.TempDir()
+	blobs, err := blob.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checksum, size, err := blob.Checksum(strings.NewReader("original"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := "1/7/" + checksum
+	if err := blobs.Put(context.Background(), key, strings.NewReader("original"), size, checksum); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, key), []byte(stored), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	attachments := &fakeAttachmentRepository{attachment: domain.Attachment{Id: 1, FileName: "scan.png", ContentType: "image/png", Size: size, Checksum: checksum, StorageKey: key}}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/attachments/:id/content", NewAttachmentHandler(service.NewAttachmentService(attachments, nil, nil, blobs, 0)).Download())
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownloadStreamsTheVerifiedContent(t *testing.T) {
+	server := newDownloadServer(t, "original")
+	res, err := http.Get(server.URL + "/attachments/1/content")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil || string(body) != "original" {
+		t.Fatalf("body = %q, %v; want the original content", body, err)
+	}
+	if got := res.Header.Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+	}
+	if got := res.Header.Get("Content-Type"); got != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", got)
+	}
+}
+
+func TestDownloadOfACorruptedFileNeverArrivesComplete(t *testing.T) {
+	for _, stored := range []string{"corrupte", "origina", "original and more"} {
+		server := newDownloadServer(t, stored)
+		res, err := http.Get(server.URL + "/attachments/1/content")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err == nil && string(body) != "original" {
+			t.Errorf("stored %q: body = %q read without an error, want the download cut short", stored, body)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type calendarHandler struct {
+	s service.CalendarService
+}
+
+func NewCalendarHandler(s service.CalendarService) *calendarHandler {
+	return &calendarHandler{
+		s: s,
+	}
+}
+
+// IssueToken godoc
+// @Summary Issue a calendar feed token
+// @Description This endpoint creates the secret token of the iCalendar feed of a dentist or patient and returns the URL to subscribe to. Issuing a new token revokes the previous one. The token is shown only once.
+// @Tags Calendars
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Dentist or patient ID"
+// @Success 201 {object} domain.CalendarFeed "Feed URL and token"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Dentist or patient not found"
+// @Router /dentists/{id}/calendar/token [post]
+// @Router /patients/{id}/calendar/token [post]
+func (h *calendarHandler) IssueToken(ownerType string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		token, err := h.s.IssueToken(tenantID, ownerType, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": ownerType + " not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, domain.CalendarFeed{
+			URL:   feedURL(ctx, ownerType, id, token),
+			Token: token,
+		})
+	}
+}
+
+// RevokeToken godoc
+// @Summary Revoke a calendar feed token
+// @Description This endpoint disables the iCalendar feed of a dentist or patient until a new token is issued.
+// @Tags Calendars
+// @Param token header string true "TOKEN"
+// @Param id path int true "Dentist or patient ID"
+// @Success 204 "Feed token revoked"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Dentist or patient not found"
+// @Router /dentists/{id}/calendar/token [delete]
+// @Router /patients/{id}/calendar/token [delete]
+func (h *calendarHandler) RevokeToken(ownerType string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		if err := h.s.RevokeToken(tenantID, ownerType, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": ownerType + " not found"})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// Feed godoc
+// @Summary Get an iCalendar feed
+// @Description This endpoint returns the appointments of a dentist or patient as an RFC 5545 calendar that calendar apps can subscribe to. It includes the last 90 days and every upcoming appointment; cancelled appointments are kept with STATUS:CANCELLED so subscribed calendars remove them. It is authenticated with the feed token instead of the TOKEN header.
+// @Tags Calendars
+// @Produce text/calendar
+// @Param id path int true "Dentist or patient ID"
+// @Param token query string true "Feed token"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Missing or invalid feed token"
+// @Failure 404 "Dentist or patient not found"
+// @Router /dentists/{id}/calendar.ics [get]
+// @Router /patients/{id}/calendar.ics [get]
+func (h *calendarHandler) Feed(ownerType string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		token := ctx.Query("token")
+		if token == "" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "token not found"})
+			return
+		}
+
+		calendar, err := h.s.Feed(token, ownerType, id)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidFeedToken) {
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+				return
+			}
+			ctx.JSON(http.StatusNotFound, gin.H{"error": ownerType + " not found"})
+			return
+		}
+
+		ctx.Header("Content-Type", "text/calendar; charset=utf-8")
+		ctx.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+		ctx.Header("Cache-Control", "private, max-age=300")
+		ctx.Status(http.StatusOK)
+		calendar.Write(ctx.Writer)
+	}
+}
+
+// feedURL is the absolute URL of a feed as seen by the client that asked for
+// the token.
+func feedURL(ctx *gin.Context, ownerType string, id int, token string) string {
+	scheme := "http"
+	if ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     ctx.Request.Host,
+		Path:     "/" + ownerType + "s/" + strconv.Itoa(id) + "/calendar.ics",
+		RawQuery: url.Values{"token": {token}}.Encode(),
+	}
+	return u.String()
+}
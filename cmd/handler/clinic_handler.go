@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type clinicHandler struct {
+	s service.ClinicService
+}
+
+func NewClinicHandler(s service.ClinicService) *clinicHandler {
+	return &clinicHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Create a new clinic
+// @Description This endpoint allows you to create a new clinic (office location).
+// @Tags Clinics
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param clinic body domain.Clinic true "Clinic"
+// @Success 201 "Clinic created successfully"
+// @Response 400 "Invalid clinic data or missing required fields"
+// @Response 401 "Unauthorized access due to missing or invalid token"
+// @Response 500 "Failed to create clinic"
+// @Router /clinics [post]
+func (h *clinicHandler) Post() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		var clinic domain.Clinic
+		if err := ctx.ShouldBindJSON(&clinic); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid clinic data"})
+			return
+		}
+		if strings.TrimSpace(clinic.Name) == "" ||
+			strings.TrimSpace(clinic.Address) == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing required fields"})
+			return
+		}
+		err := h.s.Create(tenantID, clinic)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create clinic: " + err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{"message": "clinic created successfully"})
+	}
+}
+
+// GetByID godoc
+// @Summary Get a clinic by ID
+// @Description This endpoint allows you to retrieve a clinic by its ID.
+// @Tags Clinics
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Clinic ID"
+// @Success 200 {object} domain.Clinic "Clinic"
+// @Failure 400 "Invalid ID"
+// @Failure 404 "Clinic not found"
+// @Router /clinics/{id} [get]
+func (h *clinicHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		idParam := ctx.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		clinic, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "clinic not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, clinic)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all clinics
+// @Description This endpoint allows you to retrieve all clinics.
+// @Tags Clinics
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Success 200 {array} domain.Clinic "Clinics"
+// @Failure 500 "Failed to retrieve clinics"
+// @Router /clinics [get]
+func (h *clinicHandler) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		clinics, err := h.s.GetAll(tenantID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve clinics"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, clinics)
+	}
+}
+
+// Put godoc
+// @Summary Update a clinic
+// @Description This endpoint allows you to update a clinic with the provided data.
+// @Tags Clinics
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param clinic body domain.Clinic true "Updated clinic information"
+// @Success 200 {object} domain.Clinic "Updated clinic"
+// @Failure 400 "Invalid clinic data or missing required fields"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 500 "Failed to update clinic"
+// @Router /clinics/{id} [put]
+func (h *clinicHandler) Put() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+
+		var clinic domain.Clinic
+		err := ctx.ShouldBindJSON(&clinic)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid clinic"})
+			return
+		}
+
+		if clinic.Id == 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "clinic id is required"})
+			return
+		}
+
+		err = h.s.Update(tenantID, clinic)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update clinic: " + err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, clinic)
+	}
+}
+
+// Delete godoc
+// @Summary Delete a clinic
+// @Description This endpoint allows you to delete a clinic by its ID.
+// @Tags Clinics
+// @Param token header string true "TOKEN"
+// @Param id path int true "Clinic ID"
+// @Success 204 "Clinic deleted successfully"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 500 "Failed to delete clinic"
+// @Router /clinics/{id} [delete]
+func (h *clinicHandler) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+
+		idParam := ctx.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		err = h.s.Delete(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to delete clinic"})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type consentHandler struct {
+	s service.ConsentService
+}
+
+func NewConsentHandler(s service.ConsentService) *consentHandler {
+	return &consentHandler{
+		s: s,
+	}
+}
+
+// PostTemplate godoc
+// @Summary Create a consent form template
+// @Description This endpoint creates a consent form and the treatments that require it. Appointments for those treatments can not be checked in until the form is signed.
+// @Tags Consents
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param template body domain.ConsentTemplate true "Consent template"
+// @Success 201 {object} domain.ConsentTemplate "Created template"
+// @Failure 400 "Invalid template, missing required fields or unknown treatment"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /consent-templates [post]
+func (h *consentHandler) PostTemplate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		var template domain.ConsentTemplate
+		if err := ctx.ShouldBindJSON(&template); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid consent template"})
+			return
+		}
+
+		created, err := h.s.CreateTemplate(tenantID, template)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// GetTemplates godoc
+// @Summary Get all consent form templates
+// @Description This endpoint lists the consent forms of the practice, active or not, with the treatments that require them.
+// @Tags Consents
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Success 200 {array} domain.ConsentTemplate "Consent templates"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /consent-templates [get]
+func (h *consentHandler) GetTemplates() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		templates, err := h.s.GetTemplates(tenantID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, templates)
+	}
+}
+
+// GetTemplate godoc
+// @Summary Get a consent form template
+// @Description This endpoint returns a consent form with the treatments that require it.
+// @Tags Consents
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Template ID"
+// @Success 200 {object} domain.ConsentTemplate "Consent template"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Consent template not found"
+// @Router /consent-templates/{id} [get]
+func (h *consentHandler) GetTemplate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		template, err := h.s.GetTemplate(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "consent template not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, template)
+	}
+}
+
+// PutTemplate godoc
+// @Summary Update a consent form template
+// @Description This endpoint replaces the text, the treatments and the Active flag of a consent form. Consents already prepared for an appointment keep the text they were prepared with; inactive forms are no longer required.
+// @Tags Consents
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Template ID"
+// @Param template body domain.ConsentTemplate true "Consent template"
+// @Success 200 {object} domain.ConsentTemplate "Updated template"
+// @Failure 400 "Invalid template, missing required fields or unknown treatment"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Consent template not found"
+// @Router /consent-templates/{id} [put]
+func (h *consentHandler) PutTemplate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var template domain.ConsentTemplate
+		if err := ctx.ShouldBindJSON(&template); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid consent template"})
+			return
+		}
+		if _, err := h.s.GetTemplate(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "consent template not found"})
+			return
+		}
+		template.Id = id
+
+		updated, err := h.s.UpdateTemplate(tenantID, template)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, updated)
+	}
+}
+
+// GetByAppointment godoc
+// @Summary Get the consents of an appointment
+// @Description This endpoint lists the consents of the appointment, so they can be presented to the patient and signed. The consents its treatment requires are prepared when the appointment is booked or changed.
+// @Tags Consents
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Success 200 {array} domain.Consent "Consents"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Appointment not found"
+// @Router /appointments/{id}/consents [get]
+func (h *consentHandler) GetByAppointment() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		appointmentID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		consents, err := h.s.GetByAppointment(tenantID, appointmentID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, consents)
+	}
+}
+
+// Post godoc
+// @Summary Prepare a consent for an appointment
+// @Description This endpoint adds the consent form of an active template to the appointment, also when its treatment does not require it.
+// @Tags Consents
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Param consent body object true "Template of the consent: {\"consent_templates_Id\": 1}"
+// @Success 201 {object} domain.Consent "Prepared consent"
+// @Failure 400 "Invalid ID, unknown or inactive template, already prepared or appointment completed"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Appointment not found"
+// @Router /appointments/{id}/consents [post]
+func (h *consentHandler) Post() gin.HandlerFunc {
+	type Request struct {
+		TemplateId int `json:"consent_templates_Id" binding:"required"`
+	}
+
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		appointmentID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var r Request
+		if err := ctx.ShouldBindJSON(&r); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "consent_templates_Id is required"})
+			return
+		}
+
+		consent, err := h.s.Prepare(tenantID, appointmentID, r.TemplateId)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, consent)
+	}
+}
+
+// GetByID godoc
+// @Summary Get a consent
+// @Description This endpoint returns a consent with the text presented to the patient and, once signed, the signer, when it was signed and the hash of the signed document.
+// @Tags Consents
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Consent ID"
+// @Success 200 {object} domain.Consent "Consent"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Consent not found"
+// @Router /consents/{id} [get]
+func (h *consentHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		consent, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "consent not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, consent)
+	}
+}
+
+// Sign godoc
+// @Summary Sign a consent
+// @Description This endpoint signs a pending consent with the strokes captured on a signature pad. The form is rendered as a PDF with the signature drawn on it, stored, and its SHA-256 recorded with the signer and the time of signature. Signed consents can not be changed.
+// @Tags Consents
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Consent ID"
+// @Param signature body domain.ConsentSignature true "Signature"
+// @Success 200 {object} domain.Consent "Signed consent"
+// @Failure 400 "Invalid signature, already signed or appointment completed"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Consent not found"
+// @Router /consents/{id}/sign [post]
+func (h *consentHandler) Sign() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var signature domain.ConsentSignature
+		if err := ctx.ShouldBindJSON(&signature); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature"})
+			return
+		}
+		if _, err := h.s.GetByID(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "consent not found"})
+			return
+		}
+
+		consent, err := h.s.Sign(ctx.Request.Context(), tenantID, id, signature)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, consent)
+	}
+}
+
+// Document godoc
+// @Summary Download a signed consent
+// @Description This endpoint returns the signed PDF of a consent, once checked against the hash taken when it was signed. The hash is also sent as the ETag.
+// @Tags Consents
+// @Produce application/pdf
+// @Param token header string true "TOKEN"
+// @Param id path int true "Consent ID"
+// @Success 200 {file} file "Signed consent"
+// @Failure 400 "Invalid ID or consent not signed yet"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Consent not found"
+// @Failure 500 "The signed document is missing or corrupted"
+// @Router /consents/{id}/document [get]
+func (h *consentHandler) Document() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		consent, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "consent not found"})
+			return
+		}
+		if consent.Status != domain.ConsentSigned {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "the consent is not signed yet"})
+			return
+		}
+
+		consent, document, err := h.s.Document(ctx.Request.Context(), tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.Header("Content-Disposition", `inline; filename="consent-`+strconv.Itoa(consent.Id)+`.pdf"`)
+		ctx.Header("ETag", `"`+consent.DocumentHash+`"`)
+		ctx.Data(http.StatusOK, "application/pdf", document)
+	}
+}
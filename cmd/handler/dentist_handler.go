@@ -3,9 +3,9 @@ package handler
 import (
 	"errors"
 	"net/http"
-	"os"
 	"proyecto_final_go/internal/domain"
 	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
 	"strings"
 
 	"strconv"
@@ -37,16 +37,8 @@ func NewDentistHandler(s service.DentistService) *dentistHandler {
 // @Router /dentists [post]
 func (h *dentistHandler) Post() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
 		var dentist domain.Dentist
-		token := ctx.GetHeader("TOKEN")
-		if token == "" {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "token not found"})
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			return
-		}
 		if err := ctx.ShouldBindJSON(&dentist); err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid dentist data"})
 			return
@@ -57,7 +49,7 @@ func (h *dentistHandler) Post() gin.HandlerFunc {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing required fields"})
 			return
 		}
-		err := h.s.Create(dentist)
+		err := h.s.Create(tenantID, dentist)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create dentist"})
 			return
@@ -72,6 +64,7 @@ func (h *dentistHandler) Post() gin.HandlerFunc {
 // @Description This endpoint allows you to retrieve a dentist by their ID.
 // @Tags Dentists
 // @Produce json
+// @Param token header string true "TOKEN"
 // @Param id path int true "Dentist ID"
 // @Success 200 {object} domain.Dentist "Dentist"
 // @Failure 400 "Invalid ID"
@@ -79,6 +72,7 @@ func (h *dentistHandler) Post() gin.HandlerFunc {
 // @Router /dentists/{id} [get]
 func (h *dentistHandler) GetByID() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
 		idParam := ctx.Param("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
@@ -86,7 +80,7 @@ func (h *dentistHandler) GetByID() gin.HandlerFunc {
 			return
 		}
 
-		dentist, err := h.s.GetByID(id)
+		dentist, err := h.s.GetByID(tenantID, id)
 		if err != nil {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "dentist not found"})
 			return
@@ -98,15 +92,49 @@ func (h *dentistHandler) GetByID() gin.HandlerFunc {
 
 // GetAll godoc
 // @Summary Get all dentists
-// @Description This endpoint allows you to retrieve all dentists.
+// @Description This endpoint allows you to retrieve all dentists, optionally filtered by specialty or by the clinic they work at, as JSON or, with format=csv|xlsx or an Accept header of text/csv or the XLSX type, as a spreadsheet streamed from the database.
 // @Tags Dentists
 // @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param token header string true "TOKEN"
+// @Param specialty query string false "Specialty name"
+// @Param clinic query int false "Clinic ID"
+// @Param format query string false "json, csv or xlsx"
 // @Success 200 {array} domain.Dentist "Dentists"
+// @Failure 400 "Invalid clinic ID or format"
 // @Failure 500 "Failed to retrieve dentists"
 // @Router /dentists [get]
 func (h *dentistHandler) GetAll() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		dentists, err := h.s.GetAll()
+		tenantID := middleware.TenantID(ctx)
+		filter := domain.DentistFilter{Specialty: ctx.Query("specialty")}
+		if clinicParam := ctx.Query("clinic"); clinicParam != "" {
+			clinicID, err := strconv.Atoi(clinicParam)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid clinic id"})
+				return
+			}
+			filter.ClinicId = clinicID
+		}
+		format, err := exportFormat(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if format != "" {
+			streamExport(ctx, format, "dentists", dentistColumns, func(write func([]string) error) error {
+				return h.s.Each(tenantID, filter, func(d domain.Dentist) error { return write(dentistRecord(d)) })
+			})
+			return
+		}
+
+		var dentists []domain.Dentist
+		if filter != (domain.DentistFilter{}) {
+			dentists, err = h.s.Search(tenantID, filter)
+		} else {
+			dentists, err = h.s.GetAll(tenantID)
+		}
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve dentists"})
 			return
@@ -130,16 +158,7 @@ func (h *dentistHandler) GetAll() gin.HandlerFunc {
 // @Router /dentists [put]
 func (h *dentistHandler) Put() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-
-		token := ctx.GetHeader("TOKEN")
-		if token == "" {
-			ctx.JSON(http.StatusUnauthorized, errors.New("token not found"))
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			ctx.JSON(http.StatusUnauthorized, errors.New("invalid token"))
-			return
-		}
+		tenantID := middleware.TenantID(ctx)
 
 		var dentist domain.Dentist
 		err := ctx.ShouldBindJSON(&dentist)
@@ -153,7 +172,7 @@ func (h *dentistHandler) Put() gin.HandlerFunc {
 			return
 		}
 
-		err = h.s.Update(dentist)
+		err = h.s.Update(tenantID, dentist)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update dentist"})
 			return
@@ -183,16 +202,7 @@ func (h *dentistHandler) Patch() gin.HandlerFunc {
 	}
 
 	return func(ctx *gin.Context) {
-		token := ctx.GetHeader("TOKEN")
-		if token == "" {
-			ctx.JSON(http.StatusUnauthorized, errors.New("token not found"))
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			ctx.JSON(http.StatusUnauthorized, errors.New("invalid token"))
-			return
-		}
-
+		tenantID := middleware.TenantID(ctx)
 		var r Request
 		idParam := ctx.Param("id")
 		id, err := strconv.Atoi(idParam)
@@ -210,14 +220,14 @@ func (h *dentistHandler) Patch() gin.HandlerFunc {
 			return
 		}
 
-		oldDentist, err := h.s.GetByID(id)
+		oldDentist, err := h.s.GetByID(tenantID, id)
 		if err != nil {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "dentist not found"})
 			return
 		}
 
 		if r.License != oldDentist.License {
-			err = h.s.PatchLicense(id, r.License)
+			err = h.s.PatchLicense(tenantID, id, r.License)
 			if err != nil {
 				ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to update License"})
 				return
@@ -243,16 +253,7 @@ func (h *dentistHandler) Patch() gin.HandlerFunc {
 // @Router /dentists/{id} [delete]
 func (h *dentistHandler) Delete() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-
-		token := ctx.GetHeader("TOKEN")
-		if token == "" {
-			ctx.JSON(http.StatusUnauthorized, errors.New("token not found"))
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			ctx.JSON(http.StatusUnauthorized, errors.New("invalid token"))
-			return
-		}
+		tenantID := middleware.TenantID(ctx)
 
 		idParam := ctx.Param("id")
 		id, err := strconv.Atoi(idParam)
@@ -260,7 +261,7 @@ func (h *dentistHandler) Delete() gin.HandlerFunc {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 			return
 		}
-		err = h.s.Delete(id)
+		err = h.s.Delete(tenantID, id)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to delete dentist"})
 			return
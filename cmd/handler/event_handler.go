@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// keepAliveInterval keeps idle streams from being closed by proxies.
+	keepAliveInterval = 15 * time.Second
+	// reconnectDelay is how long, in milliseconds, clients wait before
+	// reconnecting a dropped stream.
+	reconnectDelay = 3000
+)
+
+type eventHandler struct {
+	s service.EventService
+}
+
+func NewEventHandler(s service.EventService) *eventHandler {
+	return &eventHandler{
+		s: s,
+	}
+}
+
+// Stream godoc
+// @Summary Stream live appointment changes
+// @Description This endpoint keeps the connection open and pushes appointment.created, appointment.updated, appointment.rescheduled and appointment.cancelled events as Server-Sent Events. Each message carries the event id, so a reconnecting client sends it back in the Last-Event-ID header and receives the events it missed. The token may be passed in the token query parameter for EventSource clients. Idle streams get a keep-alive event every 15 seconds. Events show up about two seconds after they happen, so that none committed out of order is skipped.
+// @Tags Events
+// @Produce text/event-stream
+// @Param token header string false "TOKEN"
+// @Param token query string false "TOKEN, for clients that cannot set headers"
+// @Param dentist query int false "Dentist ID"
+// @Param date query string false "Date (dd/MM/YYYY) of the appointments, rescheduled appointments match the old and the new date"
+// @Param tz query string false "Time zone of date (IANA name), defaults to America/Argentina/Buenos_Aires"
+// @Param Last-Event-ID header int false "Id of the last event received, to resume the stream"
+// @Success 200 {object} domain.Event "Stream of events"
+// @Failure 400 "Invalid filter or Last-Event-ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /events/stream [get]
+func (h *eventHandler) Stream() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := middleware.TenantID(c)
+		filter := domain.EventFilter{Date: c.Query("date"), TimeZone: c.Query("tz")}
+		if dentist := c.Query("dentist"); dentist != "" {
+			id, err := strconv.Atoi(dentist)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dentist id"})
+				return
+			}
+			filter.DentistId = id
+		}
+		var lastEventID int64
+		if header := c.GetHeader("Last-Event-ID"); header != "" {
+			id, err := strconv.ParseInt(header, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Last-Event-ID"})
+				return
+			}
+			lastEventID = id
+		}
+
+		events, err := h.s.Subscribe(c.Request.Context(), tenantID, filter, lastEventID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Header("Content-Type", sse.ContentType)
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		keepAlive := time.NewTicker(keepAliveInterval)
+		defer keepAlive.Stop()
+		c.Stream(func(w io.Writer) bool {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return false
+				}
+				c.Render(-1, sse.Event{
+					Id:    strconv.FormatInt(event.Id, 10),
+					Event: event.Type,
+					Retry: reconnectDelay,
+					Data:  event,
+				})
+				return true
+			case <-keepAlive.C:
+				c.SSEvent("keep-alive", "")
+				return true
+			case <-c.Request.Context().Done():
+				return false
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"proyecto_final_go/internal/domain"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeEventService struct {
+	events []domain.Event
+}
+
+func (s *fakeEventService) Subscribe(ctx context.Context, tenantID int, filter domain.EventFilter, lastEventID int64) (<-chan domain.Event, error) {
+	out := make(chan domain.Event, len(s.events))
+	for _, event := range s.events {
+		if event.Id > lastEventID {
+			out <- event
+		}
+	}
+	close(out)
+	return out, nil
+}
+
+func TestStreamSendsEventsAsServerSentEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &fakeEventService{events: []domain.Event{
+		{Id: 6, TenantId: 1, AggregateType: "appointment", AggregateId: 3, Type: domain.EventAppointmentCreated, Payload: []byte(`{"AppointmentId":3}`)},
+		{Id: 7, TenantId: 1, AggregateType: "appointment", AggregateId: 3, Type: domain.EventAppointmentCancelled, Payload: []byte(`{"AppointmentId":3}`)},
+	}}
+	r := gin.New()
+	r.GET("/events/stream", NewEventHandler(s).Stream())
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/events/stream", nil)
+	req.Header.Set("Last-Event-ID", "6")
+	res, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := res.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+	want := "id:7\nevent:appointment.cancelled\nretry:3000\ndata:{\"Id\":7,"
+	if !strings.HasPrefix(string(body), want) || !strings.HasSuffix(string(body), "}\n\n") {
+		t.Fatalf("stream = %q, want a single frame starting with %q", body, want)
+	}
+	if strings.Contains(string(body), "id:6") {
+		t.Fatalf("stream = %q, want the events after Last-Event-ID only", body)
+	}
+}
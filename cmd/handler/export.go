@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/pkg/export"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	appointmentColumns = []string{"Id", "Date", "Hour", "TimeZone", "Dentist", "License", "Patient", "DNI", "Treatment", "Clinic", "Description"}
+	patientColumns     = []string{"Id", "FirstName", "LastName", "Address", "DNI", "ReleaseDate", "Email", "Phone"}
+	dentistColumns     = []string{"Id", "FirstName", "LastName", "License", "Specialties"}
+)
+
+// exportFormat returns the spreadsheet format requested with ?format= or the
+// Accept header, or "" when the listing is wanted as JSON.
+func exportFormat(ctx *gin.Context) (string, error) {
+	switch format := strings.ToLower(ctx.Query("format")); format {
+	case export.FormatCSV, export.FormatXLSX:
+		return format, nil
+	case "json":
+		return "", nil
+	case "":
+	default:
+		return "", errors.New("invalid format, expected json, csv or xlsx")
+	}
+	accept := ctx.GetHeader("Accept")
+	switch {
+	case strings.Contains(accept, "text/csv"):
+		return export.FormatCSV, nil
+	case strings.Contains(accept, export.ContentType(export.FormatXLSX)):
+		return export.FormatXLSX, nil
+	}
+	return "", nil
+}
+
+// streamExport writes a spreadsheet named after the listing. each is called
+// with the function writing a row and streams the rows from the store. An
+// error before anything reached the client is answered as usual; later the
+// response can only be cut short.
+func streamExport(ctx *gin.Context, format string, name string, columns []string, each func(write func([]string) error) error) {
+	ctx.Header("Content-Type", export.ContentType(format))
+	ctx.Header("Content-Disposition", `attachment; filename="`+name+"-"+time.Now().Format("20060102")+"."+format+`"`)
+
+	writer, err := export.New(format, ctx.Writer, name)
+	if err == nil {
+		err = writer.Write(columns)
+	}
+	if err == nil {
+		err = each(writer.Write)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		return
+	}
+	if !ctx.Writer.Written() {
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Writer.Header().Del("Content-Disposition")
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export " + name + ": " + err.Error()})
+		return
+	}
+	ctx.Error(err)
+	ctx.Abort()
+}
+
+func appointmentRecord(a domain.Appointment) []string {
+	return []string{
+		strconv.Itoa(a.Id), a.Date, a.Hour, a.TimeZone,
+		a.Dentist.FirstName + " " + a.Dentist.LastName, a.Dentist.License,
+		a.Patient.FirstName + " " + a.Patient.LastName, a.Patient.DNI,
+		a.Treatment.Name, a.Clinic.Name, a.Description,
+	}
+}
+
+func patientRecord(p domain.Patient) []string {
+	return []string{strconv.Itoa(p.Id), p.FirstName, p.LastName, p.Address, p.DNI, p.ReleaseDate, p.Email, p.Phone}
+}
+
+func dentistRecord(d domain.Dentist) []string {
+	names := make([]string, len(d.Specialties))
+	for i, specialty := range d.Specialties {
+		names[i] = specialty.Name
+	}
+	return []string{strconv.Itoa(d.Id), d.FirstName, d.LastName, d.License, strings.Join(names, ", ")}
+}
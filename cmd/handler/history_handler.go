@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type historyHandler struct {
+	s service.HistoryService
+}
+
+func NewHistoryHandler(s service.HistoryService) *historyHandler {
+	return &historyHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Add a medical history entry to a patient
+// @Description This endpoint records an allergy, medication or condition of the patient. Active allergies, severe entries and entries with Alert set are shown as alerts in the appointment detail.
+// @Tags Medical history
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Param entry body domain.HistoryEntry true "History entry"
+// @Success 201 {object} domain.HistoryEntry "Created entry"
+// @Failure 400 "Invalid entry, missing required fields or unknown patient"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /patients/{id}/history [post]
+func (h *historyHandler) Post() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var entry domain.HistoryEntry
+		if err := ctx.ShouldBindJSON(&entry); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid history entry"})
+			return
+		}
+		entry.PatientId = patientID
+
+		created, err := h.s.Create(tenantID, entry)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// GetByPatient godoc
+// @Summary Get the medical history of a patient
+// @Description This endpoint lists the allergies, medications and conditions of the patient, grouped by kind and most recent first.
+// @Tags Medical history
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Param kind query string false "Only entries of this kind: allergy, medication or condition"
+// @Success 200 {array} domain.HistoryEntry "History entries"
+// @Failure 400 "Invalid ID or kind"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /patients/{id}/history [get]
+func (h *historyHandler) GetByPatient() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		entries, err := h.s.GetByPatient(tenantID, patientID, ctx.Query("kind"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, entries)
+	}
+}
+
+// GetByID godoc
+// @Summary Get a medical history entry
+// @Description This endpoint returns one entry of the medical history of the patient.
+// @Tags Medical history
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Param entryId path int true "Entry ID"
+// @Success 200 {object} domain.HistoryEntry "History entry"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "History entry not found"
+// @Router /patients/{id}/history/{entryId} [get]
+func (h *historyHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, entryID, ok := historyIDs(ctx)
+		if !ok {
+			return
+		}
+
+		entry, err := h.s.GetByID(tenantID, patientID, entryID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "history entry not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, entry)
+	}
+}
+
+// Put godoc
+// @Summary Update a medical history entry
+// @Description This endpoint replaces an entry of the medical history of the patient, e.g. to set the EndDate of a medication. When it was first recorded is kept.
+// @Tags Medical history
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Param entryId path int true "Entry ID"
+// @Param entry body domain.HistoryEntry true "History entry"
+// @Success 200 {object} domain.HistoryEntry "Updated entry"
+// @Failure 400 "Invalid entry or missing required fields"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "History entry not found"
+// @Router /patients/{id}/history/{entryId} [put]
+func (h *historyHandler) Put() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, entryID, ok := historyIDs(ctx)
+		if !ok {
+			return
+		}
+		var entry domain.HistoryEntry
+		if err := ctx.ShouldBindJSON(&entry); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid history entry"})
+			return
+		}
+		if _, err := h.s.GetByID(tenantID, patientID, entryID); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "history entry not found"})
+			return
+		}
+		entry.Id = entryID
+		entry.PatientId = patientID
+
+		updated, err := h.s.Update(tenantID, entry)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, updated)
+	}
+}
+
+// Delete godoc
+// @Summary Delete a medical history entry
+// @Description This endpoint removes an entry recorded by mistake. Entries that no longer apply should get an EndDate instead.
+// @Tags Medical history
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Param entryId path int true "Entry ID"
+// @Success 204 "History entry deleted successfully"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "History entry not found"
+// @Router /patients/{id}/history/{entryId} [delete]
+func (h *historyHandler) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, entryID, ok := historyIDs(ctx)
+		if !ok {
+			return
+		}
+		if err := h.s.Delete(tenantID, patientID, entryID); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "history entry not found"})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// historyIDs reads the patient and entry IDs of the path, answering with an
+// error when they are invalid.
+func historyIDs(ctx *gin.Context) (int, int, bool) {
+	patientID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, 0, false
+	}
+	entryID, err := strconv.Atoi(ctx.Param("entryId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid entry id"})
+		return 0, 0, false
+	}
+	return patientID, entryID, true
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize bounds the uploaded import files.
+const maxImportSize = 10 << 20
+
+type importHandler struct {
+	s service.ImportService
+}
+
+func NewImportHandler(s service.ImportService) *importHandler {
+	return &importHandler{
+		s: s,
+	}
+}
+
+// ImportCalendar godoc
+// @Summary Import an iCalendar file into a dentist's appointments
+// @Description This endpoint books the events of an .ics file, sent as the "file" form field or as a text/calendar body, as appointments of the dentist. Attendees are matched to patients by DNI (X-DNI attendee parameter or event property, or "DNI 12345678" in the event text); unknown attendees get a placeholder patient. Every event goes through the usual booking checks, and the report tells which events were imported, skipped or conflicting.
+// @Tags Imports
+// @Accept multipart/form-data
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Dentist ID"
+// @Param file formData file true "iCalendar file"
+// @Param tz query string false "Time zone of the event times without a zone (IANA name), defaults to America/Argentina/Buenos_Aires"
+// @Success 200 {object} domain.ImportReport "Import report"
+// @Failure 400 "Invalid ID or file"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /dentists/{id}/calendar/import [post]
+func (h *importHandler) ImportCalendar() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		file, err := uploadedFile(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing import file"})
+			return
+		}
+		defer file.Close()
+
+		report, err := h.s.ImportCalendar(tenantID, id, file, ctx.Query("tz"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to import calendar: " + err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, report)
+	}
+}
+
+// ImportPatients godoc
+// @Summary Bulk import patients
+// @Description This endpoint creates patients from a CSV file with a header row, or a JSON Lines file, sent as the "file" form field or as the body. Columns are matched to fields by name unless mapped, e.g. map=FirstName=nombre,DNI=documento. Rows whose DNI already exists or repeats an earlier row are skipped. With dryRun nothing is created; with atomic either every valid row is created in one transaction or, if any row is invalid, none is.
+// @Tags Imports
+// @Accept multipart/form-data
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param file formData file true "CSV or JSON Lines file"
+// @Param format query string false "csv or jsonl, guessed from the file name or content type when missing"
+// @Param map query string false "Field to column mapping, e.g. FirstName=nombre,LastName=apellido"
+// @Param dryRun query bool false "Only validate the rows"
+// @Param atomic query bool false "Create all rows or none"
+// @Success 200 {object} domain.ImportReport "Import report"
+// @Failure 400 "Invalid file, format or options"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /patients/import [post]
+func (h *importHandler) ImportPatients() gin.HandlerFunc {
+	return h.bulkImport(h.s.ImportPatients)
+}
+
+// ImportDentists godoc
+// @Summary Bulk import dentists
+// @Description This endpoint creates dentists from a CSV file with a header row, or a JSON Lines file, sent as the "file" form field or as the body. Columns are matched to fields (FirstName, LastName, License) by name unless mapped, e.g. map=License=matricula. Rows whose license already exists or repeats an earlier row are skipped. With dryRun nothing is created; with atomic either every valid row is created in one transaction or, if any row is invalid, none is.
+// @Tags Imports
+// @Accept multipart/form-data
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param file formData file true "CSV or JSON Lines file"
+// @Param format query string false "csv or jsonl, guessed from the file name or content type when missing"
+// @Param map query string false "Field to column mapping, e.g. License=matricula"
+// @Param dryRun query bool false "Only validate the rows"
+// @Param atomic query bool false "Create all rows or none"
+// @Success 200 {object} domain.ImportReport "Import report"
+// @Failure 400 "Invalid file, format or options"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /dentists/import [post]
+func (h *importHandler) ImportDentists() gin.HandlerFunc {
+	return h.bulkImport(h.s.ImportDentists)
+}
+
+type bulkImporter func(tenantID int, r io.Reader, options domain.BulkImportOptions) (domain.ImportReport, error)
+
+func (h *importHandler) bulkImport(importer bulkImporter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		file, err := uploadedFile(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing import file"})
+			return
+		}
+		defer file.Close()
+
+		options, err := bulkImportOptions(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := importer(tenantID, file, options)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to import: " + err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, report)
+	}
+}
+
+func bulkImportOptions(ctx *gin.Context) (domain.BulkImportOptions, error) {
+	options := domain.BulkImportOptions{Format: strings.ToLower(ctx.Query("format"))}
+	if options.Format == "" {
+		options.Format = guessFormat(ctx)
+	}
+	var err error
+	if value := ctx.Query("dryRun"); value != "" {
+		if options.DryRun, err = strconv.ParseBool(value); err != nil {
+			return domain.BulkImportOptions{}, errors.New("invalid dryRun")
+		}
+	}
+	if value := ctx.Query("atomic"); value != "" {
+		if options.Atomic, err = strconv.ParseBool(value); err != nil {
+			return domain.BulkImportOptions{}, errors.New("invalid atomic")
+		}
+	}
+	if value := ctx.Query("map"); value != "" {
+		options.Mapping = make(map[string]string)
+		for _, pair := range strings.Split(value, ",") {
+			field, column, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(field) == "" || strings.TrimSpace(column) == "" {
+				return domain.BulkImportOptions{}, errors.New("invalid map, expected Field=column pairs separated by commas")
+			}
+			options.Mapping[strings.TrimSpace(field)] = column
+		}
+	}
+	return options, nil
+}
+
+// guessFormat recognizes JSON Lines uploads by file name or content type.
+func guessFormat(ctx *gin.Context) string {
+	name := ""
+	if header, err := ctx.FormFile("file"); err == nil {
+		name = strings.ToLower(header.Filename)
+	}
+	contentType := ctx.ContentType()
+	if strings.HasSuffix(name, ".jsonl") || strings.HasSuffix(name, ".ndjson") ||
+		contentType == "application/x-ndjson" || contentType == "application/jsonl" {
+		return domain.FormatJSONL
+	}
+	return domain.FormatCSV
+}
+
+// uploadedFile returns the "file" form field of a multipart request, or the
+// request body otherwise.
+func uploadedFile(ctx *gin.Context) (io.ReadCloser, error) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize)
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		header, err := ctx.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		return header.Open()
+	}
+	return ctx.Request.Body, nil
+}
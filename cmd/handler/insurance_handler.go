@@ -0,0 +1,614 @@
+package handler
+
+import (
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type insuranceHandler struct {
+	s service.InsuranceService
+}
+
+func NewInsuranceHandler(s service.InsuranceService) *insuranceHandler {
+	return &insuranceHandler{
+		s: s,
+	}
+}
+
+// PostProvider godoc
+// @Summary Create an insurance provider
+// @Description This endpoint adds an obra social or prepaid plan company the practice accepts. What it covers of each treatment is set with its rules.
+// @Tags Insurance
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param provider body domain.InsuranceProvider true "Insurance provider"
+// @Success 201 {object} domain.InsuranceProvider "Created provider"
+// @Failure 400 "Invalid provider or name already in use"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /insurance-providers [post]
+func (h *insuranceHandler) PostProvider() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		var provider domain.InsuranceProvider
+		if err := ctx.ShouldBindJSON(&provider); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid insurance provider"})
+			return
+		}
+
+		created, err := h.s.CreateProvider(tenantID, provider)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// GetProviders godoc
+// @Summary Get all insurance providers
+// @Description This endpoint lists the providers of the practice, active or not, with their coverage rules.
+// @Tags Insurance
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Success 200 {array} domain.InsuranceProvider "Insurance providers"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /insurance-providers [get]
+func (h *insuranceHandler) GetProviders() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		providers, err := h.s.GetProviders(tenantID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, providers)
+	}
+}
+
+// GetProvider godoc
+// @Summary Get an insurance provider
+// @Description This endpoint returns a provider with its coverage rules.
+// @Tags Insurance
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Provider ID"
+// @Success 200 {object} domain.InsuranceProvider "Insurance provider"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Insurance provider not found"
+// @Router /insurance-providers/{id} [get]
+func (h *insuranceHandler) GetProvider() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		provider, err := h.s.GetProvider(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "insurance provider not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, provider)
+	}
+}
+
+// PutProvider godoc
+// @Summary Update an insurance provider
+// @Description This endpoint replaces the details and the Active flag of a provider. Invoices drafted while the provider is inactive are not covered. Rules sent are ignored, they are changed on their own.
+// @Tags Insurance
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Provider ID"
+// @Param provider body domain.InsuranceProvider true "Insurance provider"
+// @Success 200 {object} domain.InsuranceProvider "Updated provider"
+// @Failure 400 "Invalid provider or name already in use"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Insurance provider not found"
+// @Router /insurance-providers/{id} [put]
+func (h *insuranceHandler) PutProvider() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var provider domain.InsuranceProvider
+		if err := ctx.ShouldBindJSON(&provider); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid insurance provider"})
+			return
+		}
+		if _, err := h.s.GetProvider(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "insurance provider not found"})
+			return
+		}
+		provider.Id = id
+
+		updated, err := h.s.UpdateProvider(tenantID, provider)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, updated)
+	}
+}
+
+// PostRule godoc
+// @Summary Add a coverage rule to an insurance provider
+// @Description This endpoint sets what the provider pays of a treatment: a Rate of the price in basis points, up to MaxAmount per unit in cents. A rule with a Plan applies to that plan only and wins over the rule for every plan.
+// @Tags Insurance
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Provider ID"
+// @Param rule body domain.CoverageRule true "Coverage rule"
+// @Success 201 {object} domain.InsuranceProvider "Provider with its rules"
+// @Failure 400 "Invalid rule, unknown treatment or rule already set for the treatment and plan"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Insurance provider not found"
+// @Router /insurance-providers/{id}/rules [post]
+func (h *insuranceHandler) PostRule() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var rule domain.CoverageRule
+		if err := ctx.ShouldBindJSON(&rule); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid coverage rule"})
+			return
+		}
+		if _, err := h.s.GetProvider(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "insurance provider not found"})
+			return
+		}
+		rule.Id = 0
+		rule.ProviderId = id
+
+		provider, err := h.s.CreateRule(tenantID, rule)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, provider)
+	}
+}
+
+// PutRule godoc
+// @Summary Update a coverage rule of an insurance provider
+// @Description This endpoint replaces the plan, treatment, rate and limit of a rule. Invoices already drafted keep their split.
+// @Tags Insurance
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Provider ID"
+// @Param ruleId path int true "Rule ID"
+// @Param rule body domain.CoverageRule true "Coverage rule"
+// @Success 200 {object} domain.InsuranceProvider "Provider with its rules"
+// @Failure 400 "Invalid rule, unknown treatment or rule already set for the treatment and plan"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Insurance provider not found"
+// @Router /insurance-providers/{id}/rules/{ruleId} [put]
+func (h *insuranceHandler) PutRule() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		ruleID, err := strconv.Atoi(ctx.Param("ruleId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid rule id"})
+			return
+		}
+		var rule domain.CoverageRule
+		if err := ctx.ShouldBindJSON(&rule); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid coverage rule"})
+			return
+		}
+		if _, err := h.s.GetProvider(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "insurance provider not found"})
+			return
+		}
+		rule.Id = ruleID
+		rule.ProviderId = id
+
+		provider, err := h.s.UpdateRule(tenantID, rule)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, provider)
+	}
+}
+
+// DeleteRule godoc
+// @Summary Delete a coverage rule of an insurance provider
+// @Description This endpoint removes a rule; the provider no longer covers the treatment for the plan. Invoices already drafted keep their split.
+// @Tags Insurance
+// @Param token header string true "TOKEN"
+// @Param id path int true "Provider ID"
+// @Param ruleId path int true "Rule ID"
+// @Success 204 "Rule deleted"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Coverage rule not found"
+// @Router /insurance-providers/{id}/rules/{ruleId} [delete]
+func (h *insuranceHandler) DeleteRule() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		ruleID, err := strconv.Atoi(ctx.Param("ruleId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid rule id"})
+			return
+		}
+
+		if err := h.s.DeleteRule(tenantID, id, ruleID); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// PostCoverage godoc
+// @Summary Add a coverage to a patient
+// @Description This endpoint records the provider, plan and member number of the patient, valid from ValidFrom until ValidTo, or without end when empty. A patient has a single coverage on any day, so the validity can not overlap the other coverages of the patient.
+// @Tags Insurance
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Param coverage body domain.Coverage true "Coverage"
+// @Success 201 {object} domain.Coverage "Created coverage"
+// @Failure 400 "Invalid coverage, unknown patient or provider, or overlapping validity"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /patients/{id}/coverages [post]
+func (h *insuranceHandler) PostCoverage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var coverage domain.Coverage
+		if err := ctx.ShouldBindJSON(&coverage); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid coverage"})
+			return
+		}
+		coverage.PatientId = patientID
+
+		created, err := h.s.CreateCoverage(tenantID, coverage)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// GetCoverages godoc
+// @Summary Get the coverages of a patient
+// @Description This endpoint lists the coverages of the patient, past, current and future, in the order they were added.
+// @Tags Insurance
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Success 200 {array} domain.Coverage "Coverages"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Patient not found"
+// @Router /patients/{id}/coverages [get]
+func (h *insuranceHandler) GetCoverages() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		coverages, err := h.s.GetCoverages(tenantID, patientID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, coverages)
+	}
+}
+
+// GetCoverage godoc
+// @Summary Get a coverage
+// @Description This endpoint returns a coverage of a patient.
+// @Tags Insurance
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Coverage ID"
+// @Success 200 {object} domain.Coverage "Coverage"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Coverage not found"
+// @Router /coverages/{id} [get]
+func (h *insuranceHandler) GetCoverage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		coverage, err := h.s.GetCoverage(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "coverage not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, coverage)
+	}
+}
+
+// PutCoverage godoc
+// @Summary Update a coverage
+// @Description This endpoint replaces the provider, plan, member number and validity of a coverage, e.g. to end it when the patient changes provider. Invoices already drafted keep their split.
+// @Tags Insurance
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Coverage ID"
+// @Param coverage body domain.Coverage true "Coverage"
+// @Success 200 {object} domain.Coverage "Updated coverage"
+// @Failure 400 "Invalid coverage, unknown provider or overlapping validity"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Coverage not found"
+// @Router /coverages/{id} [put]
+func (h *insuranceHandler) PutCoverage() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var coverage domain.Coverage
+		if err := ctx.ShouldBindJSON(&coverage); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid coverage"})
+			return
+		}
+		if _, err := h.s.GetCoverage(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "coverage not found"})
+			return
+		}
+		coverage.Id = id
+
+		updated, err := h.s.UpdateCoverage(tenantID, coverage)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, updated)
+	}
+}
+
+// PostClaim godoc
+// @Summary Submit the claim of an invoice
+// @Description This endpoint records that the share of an issued invoice covered by the insurer was claimed to it. An invoice is claimed again only when its previous claims were rejected.
+// @Tags Insurance
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Invoice ID"
+// @Param claim body object false "The number the provider gave the claim (optional): {\"Reference\": \"AUT-2024-000123\"}"
+// @Success 201 {object} domain.Claim "Submitted claim"
+// @Failure 400 "Invoice not issued, without covered share or already claimed"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Invoice not found"
+// @Router /invoices/{id}/claims [post]
+func (h *insuranceHandler) PostClaim() gin.HandlerFunc {
+	type Request struct {
+		Reference string `json:"Reference"`
+	}
+
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		invoiceID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var r Request
+		if ctx.Request.ContentLength != 0 {
+			if err := ctx.ShouldBindJSON(&r); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid claim"})
+				return
+			}
+		}
+
+		claim, err := h.s.Submit(tenantID, invoiceID, r.Reference)
+		if err != nil {
+			if err.Error() == "Invoice not found" {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, claim)
+	}
+}
+
+// GetClaimsByInvoice godoc
+// @Summary Get the claims of an invoice
+// @Description This endpoint lists the claims of the invoice in the order they were submitted.
+// @Tags Insurance
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Invoice ID"
+// @Success 200 {array} domain.Claim "Claims"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Invoice not found"
+// @Router /invoices/{id}/claims [get]
+func (h *insuranceHandler) GetClaimsByInvoice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		invoiceID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		claims, err := h.s.GetClaimsByInvoice(tenantID, invoiceID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, claims)
+	}
+}
+
+// GetClaims godoc
+// @Summary Get all claims
+// @Description This endpoint lists the claims of the practice, the most recent first, optionally only those in a status or to a provider.
+// @Tags Insurance
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param status query string false "submitted, accepted, rejected or paid"
+// @Param provider query int false "Provider ID"
+// @Success 200 {array} domain.Claim "Claims"
+// @Failure 400 "Invalid status or provider"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /claims [get]
+func (h *insuranceHandler) GetClaims() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		var providerID int
+		if provider := ctx.Query("provider"); provider != "" {
+			var err error
+			providerID, err = strconv.Atoi(provider)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid provider"})
+				return
+			}
+		}
+
+		claims, err := h.s.GetClaims(tenantID, ctx.Query("status"), providerID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, claims)
+	}
+}
+
+// GetClaim godoc
+// @Summary Get a claim
+// @Description This endpoint returns a claim with its status.
+// @Tags Insurance
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Claim ID"
+// @Success 200 {object} domain.Claim "Claim"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Claim not found"
+// @Router /claims/{id} [get]
+func (h *insuranceHandler) GetClaim() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		claim, err := h.s.GetClaim(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "claim not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, claim)
+	}
+}
+
+// PatchClaim godoc
+// @Summary Record the answer of the provider to a claim
+// @Description This endpoint accepts a submitted claim, rejects a submitted or accepted claim with a Reason, or records that the provider paid it, by default the amount claimed. The Reference given replaces the one of the claim. Once rejected, the invoice can be claimed again.
+// @Tags Insurance
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Claim ID"
+// @Param status body object true "New status: {\"Status\": \"paid\", \"PaidAmount\": 3600000, \"Reference\": \"\", \"Reason\": \"\"}"
+// @Success 200 {object} domain.Claim "Updated claim"
+// @Failure 400 "Invalid ID, status or transition"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Claim not found"
+// @Router /claims/{id} [patch]
+func (h *insuranceHandler) PatchClaim() gin.HandlerFunc {
+	type Request struct {
+		Status     string `json:"Status" binding:"required"`
+		Reference  string `json:"Reference"`
+		Reason     string `json:"Reason"`
+		PaidAmount int64  `json:"PaidAmount"`
+	}
+
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var r Request
+		if err := ctx.ShouldBindJSON(&r); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+			return
+		}
+		if _, err := h.s.GetClaim(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "claim not found"})
+			return
+		}
+
+		update := domain.Claim{Status: r.Status, Reference: r.Reference, Reason: r.Reason, PaidAmount: r.PaidAmount}
+		claim, err := h.s.SetClaimStatus(tenantID, id, update)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, claim)
+	}
+}
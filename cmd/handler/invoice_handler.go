@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type invoiceHandler struct {
+	s service.InvoiceService
+}
+
+func NewInvoiceHandler(s service.InvoiceService) *invoiceHandler {
+	return &invoiceHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Generate an invoice for a patient
+// @Description This endpoint drafts an invoice with a line for the treatment of each completed appointment in AppointmentIds, or of every completed appointment not invoiced yet when empty, at the price and tax rate of the treatment. Lines can also be added by hand. The lines are split between the patient and the coverage in patient_coverages_Id, or else the coverage of the patient valid today, by the rules of the provider. Amounts are in cents and tax rates in basis points.
+// @Tags Invoices
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Param invoice body object true "Appointments to bill, extra lines, coverage and notes: {\"AppointmentIds\": [12], \"Lines\": [], \"patient_coverages_Id\": 0, \"Notes\": \"\"}"
+// @Success 201 {object} domain.Invoice "Draft invoice"
+// @Failure 400 "Invalid request, unknown patient or coverage, appointment not completed or already invoiced, or nothing to invoice"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /patients/{id}/invoices [post]
+func (h *invoiceHandler) Post() gin.HandlerFunc {
+	type Request struct {
+		AppointmentIds []int                `json:"AppointmentIds"`
+		Lines          []domain.InvoiceLine `json:"Lines" binding:"dive"`
+		CoverageId     int                  `json:"patient_coverages_Id"`
+		Notes          string               `json:"Notes"`
+	}
+
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var r Request
+		if err := ctx.ShouldBindJSON(&r); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid invoice"})
+			return
+		}
+
+		invoice := domain.Invoice{PatientId: patientID, Lines: r.Lines, CoverageId: r.CoverageId, Notes: r.Notes}
+		created, err := h.s.Generate(tenantID, invoice, r.AppointmentIds)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// GetByPatient godoc
+// @Summary Get the invoices of a patient
+// @Description This endpoint lists the invoices of the patient, in any status, the most recent first.
+// @Tags Invoices
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Success 200 {array} domain.Invoice "Invoices"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Patient not found"
+// @Router /patients/{id}/invoices [get]
+func (h *invoiceHandler) GetByPatient() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		invoices, err := h.s.GetByPatient(tenantID, patientID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, invoices)
+	}
+}
+
+// Balance godoc
+// @Summary Get the balance of a patient
+// @Description This endpoint returns what the patient was invoiced, what their insurers cover, what they paid net of refunds and still owe in cents, the issued invoices not fully paid yet, and the completed appointments not invoiced yet.
+// @Tags Invoices
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Success 200 {object} domain.PatientBalance "Balance"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Patient not found"
+// @Router /patients/{id}/balance [get]
+func (h *invoiceHandler) Balance() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		balance, err := h.s.Balance(tenantID, patientID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, balance)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all invoices
+// @Description This endpoint lists the invoices of the practice, the most recent first, optionally only those in a status.
+// @Tags Invoices
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param status query string false "draft, issued, paid or void"
+// @Success 200 {array} domain.Invoice "Invoices"
+// @Failure 400 "Invalid status"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /invoices [get]
+func (h *invoiceHandler) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		invoices, err := h.s.GetAll(tenantID, ctx.Query("status"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, invoices)
+	}
+}
+
+// GetByID godoc
+// @Summary Get an invoice
+// @Description This endpoint returns an invoice with its lines and totals.
+// @Tags Invoices
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} domain.Invoice "Invoice"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Invoice not found"
+// @Router /invoices/{id} [get]
+func (h *invoiceHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		invoice, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "invoice not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, invoice)
+	}
+}
+
+// PatchStatus godoc
+// @Summary Issue or void an invoice
+// @Description This endpoint issues a draft, which gives it the next invoice number of the practice, or voids a draft or issued invoice so its appointments can be billed again. Voiding an issued invoice requires a Reason, its payments to be refunded and its claims to be rejected. Invoices are paid by recording payments.
+// @Tags Invoices
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Invoice ID"
+// @Param status body object true "New status: {\"Status\": \"void\", \"Reason\": \"Wrong patient\"}"
+// @Success 200 {object} domain.Invoice "Updated invoice"
+// @Failure 400 "Invalid ID, status or transition"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Invoice not found"
+// @Router /invoices/{id} [patch]
+func (h *invoiceHandler) PatchStatus() gin.HandlerFunc {
+	type Request struct {
+		Status string `json:"Status" binding:"required"`
+		Reason string `json:"Reason"`
+	}
+
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var r Request
+		if err := ctx.ShouldBindJSON(&r); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+			return
+		}
+		if _, err := h.s.GetByID(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "invoice not found"})
+			return
+		}
+
+		invoice, err := h.s.SetStatus(tenantID, id, r.Status, r.Reason)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, invoice)
+	}
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type noteHandler struct {
+	s service.NoteService
+}
+
+func NewNoteHandler(s service.NoteService) *noteHandler {
+	return &noteHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Write a clinical note for an appointment
+// @Description This endpoint records what was found and done in the visit. The note is authored by the dentist of the appointment and can be written once the appointment has started. Notes can not be edited nor deleted, only amended.
+// @Tags Clinical notes
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Param note body domain.ClinicalNote true "Clinical note"
+// @Success 201 {object} domain.ClinicalNote "Created note"
+// @Failure 400 "Invalid note, not the dentist of the appointment or appointment not started yet"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /appointments/{id}/notes [post]
+func (h *noteHandler) Post() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		appointmentID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var note domain.ClinicalNote
+		if err := ctx.ShouldBindJSON(&note); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid clinical note"})
+			return
+		}
+
+		created, err := h.s.Create(tenantID, appointmentID, note)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// Amend godoc
+// @Summary Amend a clinical note
+// @Description This endpoint corrects a note of the appointment. The original text is kept and the amendment, with the reason for it, is listed under the note. Amending an amendment amends the original note.
+// @Tags Clinical notes
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Param noteId path int true "Note ID"
+// @Param note body domain.ClinicalNote true "Amendment, Text and Reason are required"
+// @Success 201 {object} domain.ClinicalNote "Created amendment"
+// @Failure 400 "Invalid amendment, missing reason or not the dentist of the appointment"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Clinical note not found"
+// @Router /appointments/{id}/notes/{noteId}/amendments [post]
+func (h *noteHandler) Amend() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		appointmentID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		noteID, err := strconv.Atoi(ctx.Param("noteId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid note id"})
+			return
+		}
+		var amendment domain.ClinicalNote
+		if err := ctx.ShouldBindJSON(&amendment); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid amendment"})
+			return
+		}
+
+		created, err := h.s.Amend(tenantID, appointmentID, noteID, amendment)
+		if err != nil {
+			if err.Error() == "Clinical note not found" {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "clinical note not found"})
+				return
+			}
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// GetByAppointment godoc
+// @Summary Get the clinical notes of an appointment
+// @Description This endpoint lists the notes of the appointment in the order they were written, each with its amendments.
+// @Tags Clinical notes
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Success 200 {array} domain.ClinicalNote "Clinical notes"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Appointment not found"
+// @Router /appointments/{id}/notes [get]
+func (h *noteHandler) GetByAppointment() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		appointmentID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		notes, err := h.s.GetByAppointment(tenantID, appointmentID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "appointment not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, notes)
+	}
+}
+
+// Timeline godoc
+// @Summary Get the clinical timeline of a patient
+// @Description This endpoint lists the appointments of the patient in chronological order, each with its clinical notes and their amendments.
+// @Tags Clinical notes
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Success 200 {array} domain.TimelineEntry "Clinical timeline"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Patient not found"
+// @Router /patients/{id}/timeline [get]
+func (h *noteHandler) Timeline() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		timeline, err := h.s.Timeline(tenantID, patientID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, timeline)
+	}
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type odontogramHandler struct {
+	s service.OdontogramService
+}
+
+func NewOdontogramHandler(s service.OdontogramService) *odontogramHandler {
+	return &odontogramHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Record the dental findings of an appointment
+// @Description This endpoint records what the dentist found on each tooth during the visit, in FDI numbering. Caries and fillings are recorded on surfaces (M, D, O, V, L), crowns and extractions on the whole tooth, and healthy clears what was recorded before. All the findings are recorded or none.
+// @Tags Odontogram
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Param findings body []domain.ToothFinding true "Findings of the visit"
+// @Success 201 {array} domain.ToothFinding "Recorded findings"
+// @Failure 400 "Invalid finding, extracted tooth or appointment not started yet"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /appointments/{id}/odontogram [post]
+func (h *odontogramHandler) Post() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		appointmentID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var findings []domain.ToothFinding
+		if err := ctx.ShouldBindJSON(&findings); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid findings"})
+			return
+		}
+
+		recorded, err := h.s.Record(tenantID, appointmentID, findings)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, recorded)
+	}
+}
+
+// GetChart godoc
+// @Summary Get the odontogram of a patient
+// @Description This endpoint returns the state of each examined tooth of the patient, built from the findings recorded up to the end of the given date, or up to now.
+// @Tags Odontogram
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Param date query string false "Date of the chart (dd/MM/YYYY), defaults to now"
+// @Param tz query string false "Time zone of the date (IANA name), defaults to America/Argentina/Buenos_Aires"
+// @Success 200 {object} domain.Odontogram "Odontogram"
+// @Failure 400 "Invalid ID, date, time zone or unknown patient"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /patients/{id}/odontogram [get]
+func (h *odontogramHandler) GetChart() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		chart, err := h.s.Chart(tenantID, patientID, ctx.Query("date"), ctx.Query("tz"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, chart)
+	}
+}
+
+// GetFindings godoc
+// @Summary Get the dental findings of a patient
+// @Description This endpoint lists the findings recorded for the patient in the order they were made, to follow how a tooth evolved.
+// @Tags Odontogram
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Param tooth query int false "Only findings of this tooth (FDI number)"
+// @Success 200 {array} domain.ToothFinding "Findings"
+// @Failure 400 "Invalid ID, tooth or unknown patient"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /patients/{id}/odontogram/findings [get]
+func (h *odontogramHandler) GetFindings() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		tooth := 0
+		if value := ctx.Query("tooth"); value != "" {
+			if tooth, err = strconv.Atoi(value); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid tooth"})
+				return
+			}
+		}
+
+		findings, err := h.s.Findings(tenantID, patientID, tooth)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, findings)
+	}
+}
@@ -3,9 +3,9 @@ package handler
 import (
 	"errors"
 	"net/http"
-	"os"
 	"proyecto_final_go/internal/domain"
 	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
 	"strconv"
 	"strings"
 
@@ -39,15 +39,7 @@ func NewPatientHandler(s service.PatientService) *patientHandler {
 // @Router /patients [post]
 func (h *patientHandler) Post() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		token := ctx.GetHeader("TOKEN")
-		if token == "" {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "token not found"})
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			return
-		}
+		tenantID := middleware.TenantID(ctx)
 		var patient domain.Patient
 		if err := ctx.ShouldBindJSON(&patient); err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid patient data"})
@@ -63,9 +55,9 @@ func (h *patientHandler) Post() gin.HandlerFunc {
 			return
 		}
 
-		err := h.s.Create(patient)
+		err := h.s.Create(tenantID, patient)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create patient"})
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create patient: " + err.Error()})
 			return
 		}
 
@@ -78,6 +70,7 @@ func (h *patientHandler) Post() gin.HandlerFunc {
 // @Description This endpoint allows you to retrieve a patient by their ID.
 // @Tags Patients
 // @Produce json
+// @Param token header string true "TOKEN"
 // @Param id path int true "Patient ID"
 // @Success 200 {object} domain.Patient "Patient"
 // @Failure 400 "Invalid ID"
@@ -85,6 +78,7 @@ func (h *patientHandler) Post() gin.HandlerFunc {
 // @Router /patients/{id} [get]
 func (h *patientHandler) GetByID() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
 		idParam := ctx.Param("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
@@ -92,7 +86,7 @@ func (h *patientHandler) GetByID() gin.HandlerFunc {
 			return
 		}
 
-		patient, err := h.s.GetByID(id)
+		patient, err := h.s.GetByID(tenantID, id)
 		if err != nil {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "patient not found"})
 			return
@@ -104,16 +98,33 @@ func (h *patientHandler) GetByID() gin.HandlerFunc {
 
 // GetAll godoc
 // @Summary Get all patients
-// @Description This endpoint allows you to retrieve all patients.
+// @Description This endpoint allows you to retrieve all patients, as JSON or, with format=csv|xlsx or an Accept header of text/csv or the XLSX type, as a spreadsheet streamed from the database.
 // @Tags Patients
 // @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param token header string true "TOKEN"
+// @Param format query string false "json, csv or xlsx"
 // @Success 200 {array} domain.Patient "Patients"
+// @Failure 400 "Invalid format"
 // @Failure 500 "Failed to retrieve patients"
-// @Router /dentists [get]
+// @Router /patients [get]
 func (h *patientHandler) GetAll() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		format, err := exportFormat(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if format != "" {
+			streamExport(ctx, format, "patients", patientColumns, func(write func([]string) error) error {
+				return h.s.Each(tenantID, func(p domain.Patient) error { return write(patientRecord(p)) })
+			})
+			return
+		}
 
-		patients, err := h.s.GetAll()
+		patients, err := h.s.GetAll(tenantID)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve patients"})
 			return
@@ -123,6 +134,45 @@ func (h *patientHandler) GetAll() gin.HandlerFunc {
 	}
 }
 
+// Search godoc
+// @Summary Search patients
+// @Description This endpoint finds the patients with words of their first name, last name, DNI or address starting with every word of the query, ignoring case and accents ("rodriguez" finds "Rodríguez"). Exact DNI matches come first, then last and first names starting with the query.
+// @Tags Patients
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param q query string true "Words to look for, e.g. rodr maria or 30.123.456"
+// @Param limit query int false "Maximum number of patients, defaults to 20, at most 100"
+// @Success 200 {array} domain.Patient "Patients, best matches first"
+// @Failure 400 "Missing query, invalid limit or failed search"
+// @Router /patients/search [get]
+func (h *patientHandler) Search() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		q := strings.TrimSpace(ctx.Query("q"))
+		if q == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "q parameter is required"})
+			return
+		}
+		limit := 0
+		if limitParam := ctx.Query("limit"); limitParam != "" {
+			var err error
+			limit, err = strconv.Atoi(limitParam)
+			if err != nil || limit < 1 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+		}
+
+		patients, err := h.s.Search(tenantID, q, limit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, patients)
+	}
+}
+
 // Put godoc
 // @Summary Update a patient
 // @Description This endpoint allows you to update a patient with the provided data.
@@ -137,16 +187,7 @@ func (h *patientHandler) GetAll() gin.HandlerFunc {
 // @Router /patients [put]
 func (h *patientHandler) Put() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-
-		token := ctx.GetHeader("TOKEN")
-		if token == "" {
-			ctx.JSON(http.StatusUnauthorized, errors.New("token not found"))
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			ctx.JSON(http.StatusUnauthorized, errors.New("invalid token"))
-			return
-		}
+		tenantID := middleware.TenantID(ctx)
 
 		var patient domain.Patient
 		err := ctx.ShouldBindJSON(&patient)
@@ -155,9 +196,9 @@ func (h *patientHandler) Put() gin.HandlerFunc {
 			return
 		}
 
-		err = h.s.Update(patient)
+		err = h.s.Update(tenantID, patient)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to update patient"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to update patient: " + err.Error()})
 			return
 		}
 
@@ -185,16 +226,7 @@ func (h *patientHandler) Patch() gin.HandlerFunc {
 	}
 
 	return func(ctx *gin.Context) {
-		token := ctx.GetHeader("TOKEN")
-		if token == "" {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "token not found"})
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			return
-		}
-
+		tenantID := middleware.TenantID(ctx)
 		var r Request
 		idParam := ctx.Param("id")
 		id, err := strconv.Atoi(idParam)
@@ -213,14 +245,14 @@ func (h *patientHandler) Patch() gin.HandlerFunc {
 			return
 		}
 
-		oldPatient, err := h.s.GetByID(id)
+		oldPatient, err := h.s.GetByID(tenantID, id)
 		if err != nil {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "patient not found"})
 			return
 		}
 
 		if r.Address != oldPatient.Address {
-			err = h.s.PatchAddress(id, r.Address)
+			err = h.s.PatchAddress(tenantID, id, r.Address)
 			if err != nil {
 				ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to update Address"})
 				return
@@ -245,16 +277,7 @@ func (h *patientHandler) Patch() gin.HandlerFunc {
 // @Router /patients/{id} [delete]
 func (h *patientHandler) Delete() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-
-		token := ctx.GetHeader("TOKEN")
-		if token == "" {
-			ctx.JSON(http.StatusUnauthorized, errors.New("token not found"))
-			return
-		}
-		if token != os.Getenv("TOKEN") {
-			ctx.JSON(http.StatusUnauthorized, errors.New("invalid token"))
-			return
-		}
+		tenantID := middleware.TenantID(ctx)
 
 		idParam := ctx.Param("id")
 		id, err := strconv.Atoi(idParam)
@@ -262,7 +285,7 @@ func (h *patientHandler) Delete() gin.HandlerFunc {
 			ctx.JSON(http.StatusBadRequest, errors.New("invalid id"))
 			return
 		}
-		err = h.s.Delete(id)
+		err = h.s.Delete(tenantID, id)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, errors.New("failed to delete patient"))
 			return
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type paymentHandler struct {
+	s service.PaymentService
+}
+
+func NewPaymentHandler(s service.PaymentService) *paymentHandler {
+	return &paymentHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Record a payment of an invoice
+// @Description This endpoint records a cash, card or transfer payment of an issued invoice, for up to what is due; several partial payments can be recorded. Card payments go through the payment provider: a Token from the checkout or the voucher number of the terminal as Reference. Their amount is reserved before the card is charged, so concurrent payments can not cover more than is due, and a payment the provider declines is kept as voided. The invoice is paid once its payments cover the total. The clinic defaults to the one of the first appointment billed. Amounts are in cents.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Invoice ID"
+// @Param payment body object true "Payment: {\"Method\": \"card\", \"Amount\": 2000000, \"Reference\": \"000123\", \"Token\": \"\", \"clinics_Id\": 1, \"Notes\": \"\"}"
+// @Success 201 {object} domain.Payment "Payment recorded"
+// @Failure 400 "Invalid payment, invoice not issued, amount over what is due or card declined"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Invoice not found"
+// @Router /invoices/{id}/payments [post]
+func (h *paymentHandler) Post() gin.HandlerFunc {
+	type Request struct {
+		Method    string `json:"Method" binding:"required"`
+		Amount    int64  `json:"Amount" binding:"required"`
+		Reference string `json:"Reference"`
+		Token     string `json:"Token"`
+		ClinicId  int    `json:"clinics_Id"`
+		Notes     string `json:"Notes"`
+	}
+
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		invoiceID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var r Request
+		if err := ctx.ShouldBindJSON(&r); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid payment"})
+			return
+		}
+
+		payment := domain.Payment{
+			Method:    r.Method,
+			Amount:    r.Amount,
+			Reference: r.Reference,
+			Token:     r.Token,
+			ClinicId:  r.ClinicId,
+			Notes:     r.Notes,
+		}
+		created, err := h.s.Record(ctx.Request.Context(), tenantID, invoiceID, payment)
+		if err != nil {
+			if err.Error() == "Invoice not found" {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// GetByInvoice godoc
+// @Summary Get the payments of an invoice
+// @Description This endpoint lists the payments and refunds of the invoice in the order they were recorded.
+// @Tags Payments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Invoice ID"
+// @Success 200 {array} domain.Payment "Payments and refunds"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Invoice not found"
+// @Router /invoices/{id}/payments [get]
+func (h *paymentHandler) GetByInvoice() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		invoiceID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		payments, err := h.s.GetByInvoice(tenantID, invoiceID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, payments)
+	}
+}
+
+// GetByPatient godoc
+// @Summary Get the payments of a patient
+// @Description This endpoint lists the payments and refunds of the patient, the most recent first.
+// @Tags Payments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Success 200 {array} domain.Payment "Payments and refunds"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Patient not found"
+// @Router /patients/{id}/payments [get]
+func (h *paymentHandler) GetByPatient() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		payments, err := h.s.GetByPatient(tenantID, patientID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, payments)
+	}
+}
+
+// GetByID godoc
+// @Summary Get a payment
+// @Description This endpoint returns a payment or refund.
+// @Tags Payments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Payment ID"
+// @Success 200 {object} domain.Payment "Payment"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Payment not found"
+// @Router /payments/{id} [get]
+func (h *paymentHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		payment, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "payment not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, payment)
+	}
+}
+
+// Refund godoc
+// @Summary Refund a payment
+// @Description This endpoint gives back part or all of a payment with the method it was paid with; Amount defaults to what is left of the payment. Card refunds go through the payment provider; with a terminal, Reference is the voucher number of the refund. Their amount is reserved before the provider is called, so concurrent refunds can not give back more than was paid, and a refund the provider rejects is kept as voided. A paid invoice is open again when a refund leaves something due.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Payment ID"
+// @Param refund body object true "Refund: {\"Amount\": 500000, \"Reference\": \"\", \"clinics_Id\": 1, \"Notes\": \"Treatment not done\"}"
+// @Success 201 {object} domain.Payment "Refund recorded"
+// @Failure 400 "Invalid refund or amount over what is left of the payment"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Payment not found"
+// @Router /payments/{id}/refunds [post]
+func (h *paymentHandler) Refund() gin.HandlerFunc {
+	type Request struct {
+		Amount    int64  `json:"Amount"`
+		Reference string `json:"Reference"`
+		ClinicId  int    `json:"clinics_Id"`
+		Notes     string `json:"Notes"`
+	}
+
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var r Request
+		if err := ctx.ShouldBindJSON(&r); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid refund"})
+			return
+		}
+		if _, err := h.s.GetByID(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "payment not found"})
+			return
+		}
+
+		refund := domain.Payment{Amount: r.Amount, Reference: r.Reference, ClinicId: r.ClinicId, Notes: r.Notes}
+		created, err := h.s.Refund(ctx.Request.Context(), tenantID, id, refund)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// CashClose godoc
+// @Summary Get the cash close of a clinic
+// @Description This endpoint sums the payments and refunds recorded at the clinic on a day in its time zone, by method, and lists them.
+// @Tags Payments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Clinic ID"
+// @Param date query string false "The day to close (dd/MM/yyyy), today by default"
+// @Success 200 {object} domain.CashClose "Cash close"
+// @Failure 400 "Invalid ID or date"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Clinic not found"
+// @Router /clinics/{id}/cash-close [get]
+func (h *paymentHandler) CashClose() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		clinicID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		cashClose, err := h.s.CashClose(tenantID, clinicID, ctx.Query("date"))
+		if err != nil {
+			if err.Error() == "Clinic not found" {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, cashClose)
+	}
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type planHandler struct {
+	s service.PlanService
+}
+
+func NewPlanHandler(s service.PlanService) *planHandler {
+	return &planHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Create a treatment plan for a patient
+// @Description This endpoint creates a plan of ordered steps, each with an optional treatment and its estimated cost in cents. Appointments are booked for a step with plan_steps_Id, and the plan progresses as they are completed.
+// @Tags Treatment plans
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Param plan body domain.TreatmentPlan true "Treatment plan"
+// @Success 201 {object} domain.TreatmentPlan "Created plan"
+// @Failure 400 "Invalid plan, missing required fields, unknown patient or treatment"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Router /patients/{id}/plans [post]
+func (h *planHandler) Post() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var plan domain.TreatmentPlan
+		if err := ctx.ShouldBindJSON(&plan); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid treatment plan"})
+			return
+		}
+		plan.PatientId = patientID
+
+		created, err := h.s.Create(tenantID, plan)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// GetByPatient godoc
+// @Summary Get the treatment plans of a patient
+// @Description This endpoint lists the plans of the patient, the most recent first, with the status of each step and the progress of the plan.
+// @Tags Treatment plans
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Success 200 {array} domain.TreatmentPlan "Treatment plans"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Patient not found"
+// @Router /patients/{id}/plans [get]
+func (h *planHandler) GetByPatient() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		plans, err := h.s.GetByPatient(tenantID, patientID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, plans)
+	}
+}
+
+// GetByID godoc
+// @Summary Get a treatment plan
+// @Description This endpoint returns a plan with its steps, the appointments booked for each step and the progress of the plan.
+// @Tags Treatment plans
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Plan ID"
+// @Success 200 {object} domain.TreatmentPlan "Treatment plan"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Treatment plan not found"
+// @Router /plans/{id} [get]
+func (h *planHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		plan, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "treatment plan not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, plan)
+	}
+}
+
+// PatchStatus godoc
+// @Summary Cancel or reactivate a treatment plan
+// @Description This endpoint sets the status of a plan to cancelled or back to active. No more appointments can be booked for the steps of a cancelled plan.
+// @Tags Treatment plans
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Plan ID"
+// @Param status body object true "New status: {\"Status\": \"cancelled\"}"
+// @Success 200 {object} domain.TreatmentPlan "Updated plan"
+// @Failure 400 "Invalid ID or status"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Treatment plan not found"
+// @Router /plans/{id} [patch]
+func (h *planHandler) PatchStatus() gin.HandlerFunc {
+	type Request struct {
+		Status string `json:"Status" binding:"required"`
+	}
+
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var r Request
+		if err := ctx.ShouldBindJSON(&r); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+			return
+		}
+		if _, err := h.s.GetByID(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "treatment plan not found"})
+			return
+		}
+
+		plan, err := h.s.SetStatus(tenantID, id, r.Status)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, plan)
+	}
+}
+
+// PostStep godoc
+// @Summary Add a step to a treatment plan
+// @Description This endpoint appends a step at the end of a plan that is not cancelled.
+// @Tags Treatment plans
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Plan ID"
+// @Param step body domain.PlanStep true "Plan step"
+// @Success 201 {object} domain.TreatmentPlan "Updated plan"
+// @Failure 400 "Invalid step, unknown treatment or cancelled plan"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Treatment plan not found"
+// @Router /plans/{id}/steps [post]
+func (h *planHandler) PostStep() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var step domain.PlanStep
+		if err := ctx.ShouldBindJSON(&step); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid plan step"})
+			return
+		}
+		if _, err := h.s.GetByID(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "treatment plan not found"})
+			return
+		}
+
+		plan, err := h.s.AddStep(tenantID, id, step)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, plan)
+	}
+}
+
+// PutStep godoc
+// @Summary Update a step of a treatment plan
+// @Description This endpoint replaces the treatment, description and estimated cost of a step, and skips it, or brings it back, with Skipped. Completed steps can not be skipped.
+// @Tags Treatment plans
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Plan ID"
+// @Param stepId path int true "Step ID"
+// @Param step body domain.PlanStep true "Plan step"
+// @Success 200 {object} domain.TreatmentPlan "Updated plan"
+// @Failure 400 "Invalid step, unknown treatment or completed step skipped"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Treatment plan not found"
+// @Router /plans/{id}/steps/{stepId} [put]
+func (h *planHandler) PutStep() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		stepID, err := strconv.Atoi(ctx.Param("stepId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid step id"})
+			return
+		}
+		var step domain.PlanStep
+		if err := ctx.ShouldBindJSON(&step); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid plan step"})
+			return
+		}
+		if _, err := h.s.GetByID(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "treatment plan not found"})
+			return
+		}
+		step.Id = stepID
+		step.PlanId = id
+
+		plan, err := h.s.UpdateStep(tenantID, id, step)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, plan)
+	}
+}
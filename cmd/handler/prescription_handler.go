@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type prescriptionHandler struct {
+	s service.PrescriptionService
+}
+
+func NewPrescriptionHandler(s service.PrescriptionService) *prescriptionHandler {
+	return &prescriptionHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Issue a prescription at an appointment
+// @Description This endpoint issues a prescription of one or more medications, signed by the dentist of the appointment with their license, once the patient checked in. It gets a code printed on it that anyone can check.
+// @Tags Prescriptions
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Param prescription body domain.Prescription true "Diagnosis, medications and notes"
+// @Success 201 {object} domain.Prescription "Issued prescription"
+// @Failure 400 "Invalid prescription, patient not checked in or dentist without license"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Appointment not found"
+// @Router /appointments/{id}/prescriptions [post]
+func (h *prescriptionHandler) Post() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		appointmentID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var prescription domain.Prescription
+		if err := ctx.ShouldBindJSON(&prescription); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid prescription"})
+			return
+		}
+
+		issued, err := h.s.Issue(tenantID, appointmentID, prescription)
+		if err != nil {
+			if err.Error() == "Appointment not found" {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, issued)
+	}
+}
+
+// GetByAppointment godoc
+// @Summary Get the prescriptions of an appointment
+// @Description This endpoint lists the prescriptions issued at the appointment, voided or not.
+// @Tags Prescriptions
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Success 200 {array} domain.Prescription "Prescriptions"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Appointment not found"
+// @Router /appointments/{id}/prescriptions [get]
+func (h *prescriptionHandler) GetByAppointment() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		appointmentID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		prescriptions, err := h.s.GetByAppointment(tenantID, appointmentID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, prescriptions)
+	}
+}
+
+// GetByPatient godoc
+// @Summary Get the prescriptions of a patient
+// @Description This endpoint lists the prescriptions of the patient, voided or not, the most recent first.
+// @Tags Prescriptions
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Patient ID"
+// @Success 200 {array} domain.Prescription "Prescriptions"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Patient not found"
+// @Router /patients/{id}/prescriptions [get]
+func (h *prescriptionHandler) GetByPatient() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		patientID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		prescriptions, err := h.s.GetByPatient(tenantID, patientID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, prescriptions)
+	}
+}
+
+// GetByID godoc
+// @Summary Get a prescription
+// @Description This endpoint returns a prescription with its medications, verification code and status.
+// @Tags Prescriptions
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Prescription ID"
+// @Success 200 {object} domain.Prescription "Prescription"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Prescription not found"
+// @Router /prescriptions/{id} [get]
+func (h *prescriptionHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		prescription, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "prescription not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, prescription)
+	}
+}
+
+// Void godoc
+// @Summary Void a prescription
+// @Description This endpoint cancels a prescription issued by mistake. It is kept, and checking its code tells it must not be dispensed.
+// @Tags Prescriptions
+// @Accept json
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Prescription ID"
+// @Param void body object true "Why it is voided: {\"Reason\": \"Wrong dose\"}"
+// @Success 200 {object} domain.Prescription "Voided prescription"
+// @Failure 400 "Invalid ID, missing reason or already voided"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Prescription not found"
+// @Router /prescriptions/{id}/void [post]
+func (h *prescriptionHandler) Void() gin.HandlerFunc {
+	type Request struct {
+		Reason string `json:"Reason" binding:"required"`
+	}
+
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var r Request
+		if err := ctx.ShouldBindJSON(&r); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Reason is required"})
+			return
+		}
+		if _, err := h.s.GetByID(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "prescription not found"})
+			return
+		}
+
+		prescription, err := h.s.Void(tenantID, id, r.Reason)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, prescription)
+	}
+}
+
+// Document godoc
+// @Summary Print a prescription
+// @Description This endpoint returns the prescription as a PDF to hand to the patient, signed by the dentist, with its verification code and the address where it can be checked.
+// @Tags Prescriptions
+// @Produce application/pdf
+// @Param token header string true "TOKEN"
+// @Param id path int true "Prescription ID"
+// @Success 200 {file} file "Prescription"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Prescription not found"
+// @Router /prescriptions/{id}/prescription.pdf [get]
+func (h *prescriptionHandler) Document() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		prescription, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "prescription not found"})
+			return
+		}
+
+		doc, err := h.s.Document(tenantID, id, checkURL(ctx, prescription.Code))
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		writePDF(ctx, doc, "prescription-"+strconv.Itoa(id)+".pdf")
+	}
+}
+
+// Check godoc
+// @Summary Check a prescription
+// @Description This endpoint tells whoever holds a prescription, such as a pharmacy, whether its code is genuine and it can be dispensed, with the dentist who issued it and the medications. It needs no token; the patient is only shown by their initials. Dashes, spaces and case in the code are ignored. Each client IP can check 10 codes at once and then one every 6 seconds.
+// @Tags Prescriptions
+// @Produce json
+// @Param code path string true "Verification code"
+// @Success 200 {object} domain.PrescriptionCheck "Prescription check"
+// @Failure 404 "Prescription not found"
+// @Failure 429 "Too many requests, retry after the seconds in Retry-After"
+// @Router /prescriptions/check/{code} [get]
+func (h *prescriptionHandler) Check() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		check, err := h.s.Check(ctx.Param("code"))
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "prescription not found"})
+			return
+		}
+
+		ctx.Header("Cache-Control", "no-store")
+		ctx.JSON(http.StatusOK, check)
+	}
+}
+
+// checkURL is the absolute URL where a prescription code is checked, as seen
+// by the client printing it.
+func checkURL(ctx *gin.Context, code string) string {
+	scheme := "http"
+	if ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	u := url.URL{
+		Scheme: scheme,
+		Host:   ctx.Request.Host,
+		Path:   "/prescriptions/check/" + code,
+	}
+	return u.String()
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"proyecto_final_go/pkg/pdf"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type printHandler struct {
+	s service.PrintService
+}
+
+func NewPrintHandler(s service.PrintService) *printHandler {
+	return &printHandler{
+		s: s,
+	}
+}
+
+// Slip godoc
+// @Summary Print an appointment slip
+// @Description This endpoint returns a PDF confirmation of the appointment to hand to the patient, with the date and hour, the dentist, the treatment and the reserved rooms and equipment.
+// @Tags Appointments
+// @Produce application/pdf
+// @Param token header string true "TOKEN"
+// @Param id path int true "Appointment ID"
+// @Param tz query string false "Time zone to print the appointment in (IANA name), defaults to the clinic time zone"
+// @Success 200 {file} file "Appointment slip"
+// @Failure 400 "Invalid ID or time zone"
+// @Failure 404 "Appointment not found"
+// @Router /appointments/{id}/slip.pdf [get]
+func (h *printHandler) Slip() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		doc, err := h.s.AppointmentSlip(tenantID, id, ctx.Query("tz"))
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		writePDF(ctx, doc, "appointment-"+strconv.Itoa(id)+".pdf")
+	}
+}
+
+// Schedule godoc
+// @Summary Print the schedule of a dentist
+// @Description This endpoint returns a PDF with the appointments of the dentist on a date, or in the week (Monday to Sunday) that includes it, grouped by day.
+// @Tags Dentists
+// @Produce application/pdf
+// @Param token header string true "TOKEN"
+// @Param id path int true "Dentist ID"
+// @Param date query string true "Date (dd/MM/YYYY)"
+// @Param period query string false "day or week, defaults to day"
+// @Param tz query string false "Time zone of the date and hours (IANA name), defaults to America/Argentina/Buenos_Aires"
+// @Success 200 {file} file "Dentist schedule"
+// @Failure 400 "Invalid ID, date, period or time zone"
+// @Router /dentists/{id}/schedule.pdf [get]
+func (h *printHandler) Schedule() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		date := ctx.Query("date")
+		if date == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "date parameter is required"})
+			return
+		}
+
+		doc, err := h.s.DentistSchedule(tenantID, id, date, ctx.Query("period"), ctx.Query("tz"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		writePDF(ctx, doc, "schedule-"+strconv.Itoa(id)+".pdf")
+	}
+}
+
+// writePDF renders the document before answering, so a failure can still be
+// reported with an error status.
+func writePDF(ctx *gin.Context, doc *pdf.Document, filename string) {
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render pdf"})
+		return
+	}
+	ctx.Header("Content-Disposition", `inline; filename="`+filename+`"`)
+	ctx.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type resourceHandler struct {
+	s service.ResourceService
+}
+
+func NewResourceHandler(s service.ResourceService) *resourceHandler {
+	return &resourceHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Create a new resource
+// @Description This endpoint allows you to create a new bookable resource (chair, room or equipment).
+// @Tags Resources
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param resource body domain.Resource true "Resource"
+// @Success 201 "Resource created successfully"
+// @Response 400 "Invalid resource data or missing required fields"
+// @Response 401 "Unauthorized access due to missing or invalid token"
+// @Response 500 "Failed to create resource"
+// @Router /resources [post]
+func (h *resourceHandler) Post() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		var resource domain.Resource
+		if err := ctx.ShouldBindJSON(&resource); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid resource data"})
+			return
+		}
+		if strings.TrimSpace(resource.Name) == "" ||
+			strings.TrimSpace(resource.Kind) == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing required fields"})
+			return
+		}
+		if !domain.ValidResourceKind(resource.Kind) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid resource kind"})
+			return
+		}
+		err := h.s.Create(tenantID, resource)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create resource"})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{"message": "resource created successfully"})
+	}
+}
+
+// GetByID godoc
+// @Summary Get a resource by ID
+// @Description This endpoint allows you to retrieve a resource by its ID.
+// @Tags Resources
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Resource ID"
+// @Success 200 {object} domain.Resource "Resource"
+// @Failure 400 "Invalid ID"
+// @Failure 404 "Resource not found"
+// @Router /resources/{id} [get]
+func (h *resourceHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		idParam := ctx.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		resource, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, resource)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all resources
+// @Description This endpoint allows you to retrieve all resources, optionally filtered by kind or clinic.
+// @Tags Resources
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param kind query string false "Resource kind (chair, room or equipment)"
+// @Param clinic query int false "Clinic ID"
+// @Success 200 {array} domain.Resource "Resources"
+// @Failure 400 "Invalid clinic ID"
+// @Failure 500 "Failed to retrieve resources"
+// @Router /resources [get]
+func (h *resourceHandler) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		filter := domain.ResourceFilter{Kind: ctx.Query("kind")}
+		if clinicParam := ctx.Query("clinic"); clinicParam != "" {
+			clinicID, err := strconv.Atoi(clinicParam)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid clinic id"})
+				return
+			}
+			filter.ClinicId = clinicID
+		}
+
+		var resources []domain.Resource
+		var err error
+		if filter != (domain.ResourceFilter{}) {
+			resources, err = h.s.Search(tenantID, filter)
+		} else {
+			resources, err = h.s.GetAll(tenantID)
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve resources"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, resources)
+	}
+}
+
+// Put godoc
+// @Summary Update a resource
+// @Description This endpoint allows you to update a resource with the provided data.
+// @Tags Resources
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param resource body domain.Resource true "Updated resource information"
+// @Success 200 {object} domain.Resource "Updated resource"
+// @Failure 400 "Invalid resource data or missing required fields"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 500 "Failed to update resource"
+// @Router /resources/{id} [put]
+func (h *resourceHandler) Put() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+
+		var resource domain.Resource
+		err := ctx.ShouldBindJSON(&resource)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid resource"})
+			return
+		}
+
+		if resource.Id == 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "resource id is required"})
+			return
+		}
+
+		err = h.s.Update(tenantID, resource)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update resource"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, resource)
+	}
+}
+
+// Delete godoc
+// @Summary Delete a resource
+// @Description This endpoint allows you to delete a resource by its ID.
+// @Tags Resources
+// @Param token header string true "TOKEN"
+// @Param id path int true "Resource ID"
+// @Success 204 "Resource deleted successfully"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 500 "Failed to delete resource"
+// @Router /resources/{id} [delete]
+func (h *resourceHandler) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+
+		idParam := ctx.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		err = h.s.Delete(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to delete resource"})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type scheduleHandler struct {
+	s service.ScheduleService
+}
+
+func NewScheduleHandler(s service.ScheduleService) *scheduleHandler {
+	return &scheduleHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Add a working schedule to a dentist
+// @Description This endpoint allows you to register the weekday and hours a dentist works at a clinic. A dentist cannot work at two clinics at the same time.
+// @Tags Dentists
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Dentist ID"
+// @Param schedule body domain.Schedule true "Schedule"
+// @Success 201 "Schedule created successfully"
+// @Response 400 "Invalid schedule data, missing required fields or overlapping schedule"
+// @Response 401 "Unauthorized access due to missing or invalid token"
+// @Router /dentists/{id}/schedules [post]
+func (h *scheduleHandler) Post() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		dentistID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var schedule domain.Schedule
+		if err := ctx.ShouldBindJSON(&schedule); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule data"})
+			return
+		}
+		if schedule.Clinic.Id == 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing required fields"})
+			return
+		}
+		schedule.DentistId = dentistID
+		err = h.s.Create(tenantID, schedule)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{"message": "schedule created successfully"})
+	}
+}
+
+// GetByDentist godoc
+// @Summary Get the schedules of a dentist
+// @Description This endpoint allows you to retrieve the weekly schedules of a dentist at every clinic.
+// @Tags Dentists
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Dentist ID"
+// @Success 200 {array} domain.Schedule "Schedules"
+// @Failure 400 "Invalid ID"
+// @Failure 500 "Failed to retrieve schedules"
+// @Router /dentists/{id}/schedules [get]
+func (h *scheduleHandler) GetByDentist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		dentistID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		schedules, err := h.s.GetByDentist(tenantID, dentistID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve schedules"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, schedules)
+	}
+}
+
+// Delete godoc
+// @Summary Delete a schedule of a dentist
+// @Description This endpoint allows you to delete one of the schedules of a dentist.
+// @Tags Dentists
+// @Param token header string true "TOKEN"
+// @Param id path int true "Dentist ID"
+// @Param scheduleId path int true "Schedule ID"
+// @Success 204 "Schedule deleted successfully"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Schedule not found"
+// @Router /dentists/{id}/schedules/{scheduleId} [delete]
+func (h *scheduleHandler) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+
+		dentistID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		scheduleID, err := strconv.Atoi(ctx.Param("scheduleId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule id"})
+			return
+		}
+		err = h.s.Delete(tenantID, dentistID, scheduleID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "schedule not found"})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// Availability godoc
+// @Summary Get the free slots of a dentist
+// @Description This endpoint allows you to retrieve the free appointment slots of a dentist on a date, optionally at a single clinic.
+// @Tags Dentists
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Dentist ID"
+// @Param date query string true "Date (dd/MM/YYYY)"
+// @Param clinic query int false "Clinic ID"
+// @Param tz query string false "Time zone to render the slots in (IANA name), defaults to the clinic time zone"
+// @Success 200 {array} domain.Slot "Free slots"
+// @Failure 400 "Invalid ID, date, clinic or time zone"
+// @Router /dentists/{id}/availability [get]
+func (h *scheduleHandler) Availability() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		dentistID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		date := ctx.Query("date")
+		if date == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "date parameter is required"})
+			return
+		}
+		clinicID := 0
+		if clinicParam := ctx.Query("clinic"); clinicParam != "" {
+			clinicID, err = strconv.Atoi(clinicParam)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid clinic id"})
+				return
+			}
+		}
+
+		loc, err := requestedZone(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		slots, err := h.s.Availability(tenantID, dentistID, date, clinicID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if loc != nil {
+			for i := range slots {
+				slots[i].Localize(loc)
+			}
+		}
+
+		ctx.JSON(http.StatusOK, slots)
+	}
+}
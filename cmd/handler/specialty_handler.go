@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type specialtyHandler struct {
+	s service.SpecialtyService
+}
+
+func NewSpecialtyHandler(s service.SpecialtyService) *specialtyHandler {
+	return &specialtyHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Create a new specialty
+// @Description This endpoint allows you to create a new specialty with the provided data.
+// @Tags Specialties
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param specialty body domain.Specialty true "Specialty"
+// @Success 201 "Specialty created successfully"
+// @Response 400 "Invalid specialty data or missing required fields"
+// @Response 401 "Unauthorized access due to missing or invalid token"
+// @Response 500 "Failed to create specialty"
+// @Router /specialties [post]
+func (h *specialtyHandler) Post() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		var specialty domain.Specialty
+		if err := ctx.ShouldBindJSON(&specialty); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid specialty data"})
+			return
+		}
+		if strings.TrimSpace(specialty.Name) == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing required fields"})
+			return
+		}
+		err := h.s.Create(tenantID, specialty)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create specialty"})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{"message": "specialty created successfully"})
+	}
+}
+
+// GetByID godoc
+// @Summary Get a specialty by ID
+// @Description This endpoint allows you to retrieve a specialty by its ID.
+// @Tags Specialties
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Specialty ID"
+// @Success 200 {object} domain.Specialty "Specialty"
+// @Failure 400 "Invalid ID"
+// @Failure 404 "Specialty not found"
+// @Router /specialties/{id} [get]
+func (h *specialtyHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		idParam := ctx.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		specialty, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "specialty not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, specialty)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all specialties
+// @Description This endpoint allows you to retrieve all specialties.
+// @Tags Specialties
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Success 200 {array} domain.Specialty "Specialties"
+// @Failure 500 "Failed to retrieve specialties"
+// @Router /specialties [get]
+func (h *specialtyHandler) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		specialties, err := h.s.GetAll(tenantID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve specialties"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, specialties)
+	}
+}
+
+// Delete godoc
+// @Summary Delete a specialty
+// @Description This endpoint allows you to delete a specialty by its ID.
+// @Tags Specialties
+// @Param token header string true "TOKEN"
+// @Param id path int true "Specialty ID"
+// @Success 204 "Specialty deleted successfully"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 500 "Failed to delete specialty"
+// @Router /specialties/{id} [delete]
+func (h *specialtyHandler) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+
+		idParam := ctx.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		err = h.s.Delete(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to delete specialty"})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// AssignToDentist godoc
+// @Summary Assign a specialty to a dentist
+// @Description This endpoint allows you to register a specialty held by a dentist.
+// @Tags Dentists
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Dentist ID"
+// @Param specialty body domain.Specialty true "Specialty (only the Id is used)"
+// @Success 200 "Specialty assigned successfully"
+// @Failure 400 "Invalid request or missing required fields"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Dentist or specialty not found"
+// @Router /dentists/{id}/specialties [post]
+func (h *specialtyHandler) AssignToDentist() gin.HandlerFunc {
+	type Request struct {
+		Id int `json:"Id"`
+	}
+
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		var r Request
+		idParam := ctx.Param("id")
+		dentistID, err := strconv.Atoi(idParam)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		if err := ctx.ShouldBindJSON(&r); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+			return
+		}
+		if r.Id == 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "specialty id is required"})
+			return
+		}
+
+		err = h.s.AssignToDentist(tenantID, dentistID, r.Id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Specialty assigned successfully"})
+	}
+}
+
+// RemoveFromDentist godoc
+// @Summary Remove a specialty from a dentist
+// @Description This endpoint allows you to remove a specialty from a dentist.
+// @Tags Dentists
+// @Param token header string true "TOKEN"
+// @Param id path int true "Dentist ID"
+// @Param specialtyId path int true "Specialty ID"
+// @Success 204 "Specialty removed successfully"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Dentist does not hold this specialty"
+// @Router /dentists/{id}/specialties/{specialtyId} [delete]
+func (h *specialtyHandler) RemoveFromDentist() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+
+		dentistID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		specialtyID, err := strconv.Atoi(ctx.Param("specialtyId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid specialty id"})
+			return
+		}
+		err = h.s.RemoveFromDentist(tenantID, dentistID, specialtyID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
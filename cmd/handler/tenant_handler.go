@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type tenantHandler struct {
+	s service.TenantService
+}
+
+func NewTenantHandler(s service.TenantService) *tenantHandler {
+	return &tenantHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Provision a new tenant
+// @Description This endpoint creates a new dental practice and returns its API token. The token is shown only once.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "ADMIN_TOKEN"
+// @Param tenant body domain.Tenant true "Tenant"
+// @Success 201 {object} domain.TenantCredentials "Tenant and its token"
+// @Response 400 "Invalid tenant data or missing required fields"
+// @Response 401 "Unauthorized access due to missing or invalid admin token"
+// @Response 500 "Failed to create tenant"
+// @Router /admin/tenants [post]
+func (h *tenantHandler) Post() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var tenant domain.Tenant
+		if err := ctx.ShouldBindJSON(&tenant); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid tenant data"})
+			return
+		}
+		if strings.TrimSpace(tenant.Name) == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing required fields"})
+			return
+		}
+		credentials, err := h.s.Create(tenant)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create tenant"})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, credentials)
+	}
+}
+
+// GetByID godoc
+// @Summary Get a tenant by ID
+// @Description This endpoint allows you to retrieve a tenant by its ID.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "ADMIN_TOKEN"
+// @Param id path int true "Tenant ID"
+// @Success 200 {object} domain.Tenant "Tenant"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid admin token"
+// @Failure 404 "Tenant not found"
+// @Router /admin/tenants/{id} [get]
+func (h *tenantHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		tenant, err := h.s.GetByID(id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "tenant not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, tenant)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all tenants
+// @Description This endpoint allows you to retrieve all provisioned tenants.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "ADMIN_TOKEN"
+// @Success 200 {array} domain.Tenant "Tenants"
+// @Failure 401 "Unauthorized access due to missing or invalid admin token"
+// @Failure 500 "Failed to retrieve tenants"
+// @Router /admin/tenants [get]
+func (h *tenantHandler) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenants, err := h.s.GetAll()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve tenants"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, tenants)
+	}
+}
+
+// RotateToken godoc
+// @Summary Rotate a tenant token
+// @Description This endpoint issues a new API token for the tenant. The previous token stops working immediately. Disabled tenants can not get a new token.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "ADMIN_TOKEN"
+// @Param id path int true "Tenant ID"
+// @Success 200 {object} domain.TenantCredentials "Tenant and its new token"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid admin token"
+// @Failure 404 "Tenant not found or disabled"
+// @Router /admin/tenants/{id}/token [post]
+func (h *tenantHandler) RotateToken() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		credentials, err := h.s.RotateToken(id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, credentials)
+	}
+}
+
+// Delete godoc
+// @Summary Delete a tenant
+// @Description This endpoint disables a tenant: its token and calendar feeds stop working and no more reminders are sent. Its records are kept, since clinical records have to be retained, and the tenant is still listed with DisabledAt set.
+// @Tags Admin
+// @Param X-Admin-Token header string true "ADMIN_TOKEN"
+// @Param id path int true "Tenant ID"
+// @Success 204 "Tenant disabled successfully"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid admin token"
+// @Failure 404 "Tenant not found or already disabled"
+// @Router /admin/tenants/{id} [delete]
+func (h *tenantHandler) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		err = h.s.Delete(id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "failed to delete tenant: " + err.Error()})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	storeAppointment "proyecto_final_go/pkg/store/appointment"
+	storeDentist "proyecto_final_go/pkg/store/dentist"
+	storeHistory "proyecto_final_go/pkg/store/history"
+	storePatient "proyecto_final_go/pkg/store/patient"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	tenantA = 1
+	tenantB = 2
+)
+
+// tenantRows keeps the rows of a table by tenant and id, the way every query
+// of the SQL stores is scoped by tenants_Id.
+type tenantRows[T any] map[[2]int]T
+
+func (t tenantRows[T]) read(tenantID int, id int) (T, error) {
+	row, ok := t[[2]int{tenantID, id}]
+	if !ok {
+		return row, sql.ErrNoRows
+	}
+	return row, nil
+}
+
+func (t tenantRows[T]) update(tenantID int, id int, row T) error {
+	if _, ok := t[[2]int{tenantID, id}]; !ok {
+		return sql.ErrNoRows
+	}
+	t[[2]int{tenantID, id}] = row
+	return nil
+}
+
+func (t tenantRows[T]) delete(tenantID int, id int) error {
+	if _, ok := t[[2]int{tenantID, id}]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(t, [2]int{tenantID, id})
+	return nil
+}
+
+func (t tenantRows[T]) all(tenantID int) []T {
+	rows := []T{}
+	for key, row := range t {
+		if key[0] == tenantID {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// The fake stores implement the methods the read, update and delete
+// endpoints reach; any other method panics through the nil interface.
+type fakeDentistStore struct {
+	storeDentist.DentistStoreInterface
+	rows tenantRows[domain.Dentist]
+}
+
+func (s *fakeDentistStore) Read(tenantID int, id int) (domain.Dentist, error) {
+	return s.rows.read(tenantID, id)
+}
+
+func (s *fakeDentistStore) Update(tenantID int, dentist domain.Dentist) error {
+	return s.rows.update(tenantID, dentist.Id, dentist)
+}
+
+func (s *fakeDentistStore) PatchLicense(tenantID int, id int, license string) error {
+	dentist, err := s.rows.read(tenantID, id)
+	if err != nil {
+		return err
+	}
+	dentist.License = license
+	return s.rows.update(tenantID, id, dentist)
+}
+
+func (s *fakeDentistStore) Delete(tenantID int, id int) error {
+	return s.rows.delete(tenantID, id)
+}
+
+func (s *fakeDentistStore) Exists(tenantID int, license string) (bool, error) {
+	for _, dentist := range s.rows.all(tenantID) {
+		if dentist.License == license {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakePatientStore struct {
+	storePatient.PatientStoreInterface
+	rows tenantRows[domain.Patient]
+}
+
+func (s *fakePatientStore) Read(tenantID int, id int) (domain.Patient, error) {
+	return s.rows.read(tenantID, id)
+}
+
+func (s *fakePatientStore) Update(tenantID int, patient domain.Patient) error {
+	return s.rows.update(tenantID, patient.Id, patient)
+}
+
+func (s *fakePatientStore) PatchAddress(tenantID int, id int, address string) error {
+	patient, err := s.rows.read(tenantID, id)
+	if err != nil {
+		return err
+	}
+	patient.Address = address
+	return s.rows.update(tenantID, id, patient)
+}
+
+func (s *fakePatientStore) Delete(tenantID int, id int) error {
+	return s.rows.delete(tenantID, id)
+}
+
+func (s *fakePatientStore) Exists(tenantID int, dni string) (bool, error) {
+	for _, patient := range s.rows.all(tenantID) {
+		if patient.DNI == dni {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeAppointmentStore struct {
+	storeAppointment.AppointmentStoreInterface
+	rows tenantRows[domain.Appointment]
+}
+
+func (s *fakeAppointmentStore) Read(tenantID int, id int) (domain.Appointment, error) {
+	return s.rows.read(tenantID, id)
+}
+
+func (s *fakeAppointmentStore) GetAll(tenantID int) ([]domain.Appointment, error) {
+	return s.rows.all(tenantID), nil
+}
+
+func (s *fakeAppointmentStore) Update(tenantID int, appointment domain.Appointment) error {
+	return s.rows.update(tenantID, appointment.Id, appointment)
+}
+
+func (s *fakeAppointmentStore) Delete(tenantID int, id int) error {
+	return s.rows.delete(tenantID, id)
+}
+
+type fakeHistoryStore struct {
+	storeHistory.HistoryStoreInterface
+}
+
+func (s *fakeHistoryStore) ReadByPatient(tenantID int, patientID int) ([]domain.HistoryEntry, error) {
+	return []domain.HistoryEntry{}, nil
+}
+
+type fakeResolver map[string]int
+
+func (r fakeResolver) Authenticate(token string) (domain.Tenant, error) {
+	id, ok := r[token]
+	if !ok {
+		return domain.Tenant{}, errors.New("invalid token")
+	}
+	return domain.Tenant{Id: id}, nil
+}
+
+type isolationFixture struct {
+	router       *gin.Engine
+	dentists     tenantRows[domain.Dentist]
+	patients     tenantRows[domain.Patient]
+	appointments tenantRows[domain.Appointment]
+}
+
+// newIsolationFixture routes the dentist, patient and appointment endpoints
+// through the real handlers, services and repositories. Tenant B owns
+// dentist, patient and appointment 2; tenant A owns the ones with id 1.
+func newIsolationFixture() isolationFixture {
+	gin.SetMode(gin.TestMode)
+	f := isolationFixture{
+		dentists:     tenantRows[domain.Dentist]{},
+		patients:     tenantRows[domain.Patient]{},
+		appointments: tenantRows[domain.Appointment]{},
+	}
+	for tenantID, id := range map[int]int{tenantA: 1, tenantB: 2} {
+		dentist := domain.Dentist{Id: id, FirstName: "Daniel", LastName: "Rodríguez", License: "LIC-" + strconv.Itoa(id)}
+		patient := domain.Patient{Id: id, FirstName: "Juan", LastName: "Perez", Address: "Calle " + strconv.Itoa(id), DNI: "DNI-" + strconv.Itoa(id), ReleaseDate: "01/01/2024"}
+		f.dentists[[2]int{tenantID, id}] = dentist
+		f.patients[[2]int{tenantID, id}] = patient
+		f.appointments[[2]int{tenantID, id}] = domain.Appointment{Id: id, Patient: patient, Dentist: dentist, Date: "30/03/2024", Hour: "09:00",
+			TimeZone: domain.DefaultTimeZone, Description: "Checkup of tenant " + strconv.Itoa(tenantID)}
+	}
+
+	repoDentists := repository.NewDentistRepository(&fakeDentistStore{rows: f.dentists})
+	repoPatients := repository.NewPatientRepository(&fakePatientStore{rows: f.patients})
+	repoAppointments := repository.NewAppointmentRepository(&fakeAppointmentStore{rows: f.appointments})
+	repoHistory := repository.NewHistoryRepository(&fakeHistoryStore{})
+	dentists := NewDentistHandler(service.NewDentistService(repoDentists))
+	patients := NewPatientHandler(service.NewPatientService(repoPatients))
+	appointments := NewAppointmentHandler(service.NewAppointmentService(repoAppointments, repoDentists, nil, nil, nil, nil, repoHistory, nil, nil))
+
+	r := gin.New()
+	authentication := middleware.Authentication(fakeResolver{"token-a": tenantA, "token-b": tenantB})
+	d := r.Group("/dentists", authentication)
+	d.GET(":id", dentists.GetByID())
+	d.PUT(":id", dentists.Put())
+	d.PATCH(":id", dentists.Patch())
+	d.DELETE(":id", dentists.Delete())
+	p := r.Group("/patients", authentication)
+	p.GET(":id", patients.GetByID())
+	p.PUT(":id", patients.Put())
+	p.PATCH(":id", patients.Patch())
+	p.DELETE(":id", patients.Delete())
+	a := r.Group("/appointments", authentication)
+	a.GET(":id", appointments.GetByID())
+	a.PUT(":id", appointments.Put())
+	a.PATCH(":id/description", appointments.PatchDescription())
+	a.DELETE(":id", appointments.Delete())
+	f.router = r
+	return f
+}
+
+func (f isolationFixture) do(token string, method string, path string, body any) int {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("TOKEN", token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestTenantCanNotReachAnotherTenantsRecords(t *testing.T) {
+	f := newIsolationFixture()
+	dentistB := f.dentists[[2]int{tenantB, 2}]
+	patientB := f.patients[[2]int{tenantB, 2}]
+	appointmentB := f.appointments[[2]int{tenantB, 2}]
+
+	// Tenant B reaches its own records, so the failures below come from the
+	// tenant scoping and not from the fixture.
+	for _, path := range []string{"/dentists/2", "/patients/2", "/appointments/2"} {
+		if code := f.do("token-b", http.MethodGet, path, nil); code != http.StatusOK {
+			t.Fatalf("tenant B GET %s = %d, want 200", path, code)
+		}
+	}
+
+	changedDentist := dentistB
+	changedDentist.FirstName = "Changed"
+	changedPatient := patientB
+	changedPatient.FirstName = "Changed"
+	changedAppointment := appointmentB
+	changedAppointment.Description = "Changed"
+
+	requests := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{http.MethodGet, "/dentists/2", nil},
+		{http.MethodPut, "/dentists/2", changedDentist},
+		{http.MethodPatch, "/dentists/2", gin.H{"license": "STOLEN"}},
+		{http.MethodDelete, "/dentists/2", nil},
+		{http.MethodGet, "/patients/2", nil},
+		{http.MethodPut, "/patients/2", changedPatient},
+		{http.MethodPatch, "/patients/2", gin.H{"address": "Stolen 1"}},
+		{http.MethodDelete, "/patients/2", nil},
+		{http.MethodGet, "/appointments/2", nil},
+		{http.MethodPut, "/appointments/2", changedAppointment},
+		{http.MethodPatch, "/appointments/2/description", gin.H{"description": "Changed"}},
+		{http.MethodDelete, "/appointments/2", nil},
+	}
+	for _, r := range requests {
+		code := f.do("token-a", r.method, r.path, r.body)
+		if code >= 200 && code < 300 {
+			t.Errorf("tenant A %s %s = %d, want an error", r.method, r.path, code)
+		}
+	}
+
+	if got, err := f.dentists.read(tenantB, 2); err != nil || got.FirstName != dentistB.FirstName || got.License != dentistB.License {
+		t.Errorf("dentist of tenant B = %+v, %v; want it unchanged", got, err)
+	}
+	if got, err := f.patients.read(tenantB, 2); err != nil || got.FirstName != patientB.FirstName || got.Address != patientB.Address {
+		t.Errorf("patient of tenant B = %+v, %v; want it unchanged", got, err)
+	}
+	if got, err := f.appointments.read(tenantB, 2); err != nil || got.Description != appointmentB.Description {
+		t.Errorf("appointment of tenant B = %+v, %v; want it unchanged", got, err)
+	}
+}
+
+func TestTenantRequestsRequireAToken(t *testing.T) {
+	f := newIsolationFixture()
+	for _, token := range []string{"", "token-c"} {
+		if code := f.do(token, http.MethodGet, "/patients/1", nil); code != http.StatusUnauthorized {
+			t.Errorf("GET /patients/1 with token %q = %d, want 401", token, code)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type treatmentHandler struct {
+	s service.TreatmentService
+}
+
+func NewTreatmentHandler(s service.TreatmentService) *treatmentHandler {
+	return &treatmentHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Create a new treatment
+// @Description This endpoint allows you to create a new treatment, optionally requiring a specialty.
+// @Tags Treatments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param treatment body domain.Treatment true "Treatment"
+// @Success 201 "Treatment created successfully"
+// @Response 400 "Invalid treatment data or missing required fields"
+// @Response 401 "Unauthorized access due to missing or invalid token"
+// @Response 500 "Failed to create treatment"
+// @Router /treatments [post]
+func (h *treatmentHandler) Post() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		var treatment domain.Treatment
+		if err := ctx.ShouldBindJSON(&treatment); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid treatment data"})
+			return
+		}
+		if strings.TrimSpace(treatment.Name) == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing required fields"})
+			return
+		}
+		err := h.s.Create(tenantID, treatment)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create treatment"})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{"message": "treatment created successfully"})
+	}
+}
+
+// GetByID godoc
+// @Summary Get a treatment by ID
+// @Description This endpoint allows you to retrieve a treatment by its ID.
+// @Tags Treatments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Treatment ID"
+// @Success 200 {object} domain.Treatment "Treatment"
+// @Failure 400 "Invalid ID"
+// @Failure 404 "Treatment not found"
+// @Router /treatments/{id} [get]
+func (h *treatmentHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		idParam := ctx.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		treatment, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "treatment not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, treatment)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all treatments
+// @Description This endpoint allows you to retrieve all treatments.
+// @Tags Treatments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Success 200 {array} domain.Treatment "Treatments"
+// @Failure 500 "Failed to retrieve treatments"
+// @Router /treatments [get]
+func (h *treatmentHandler) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		treatments, err := h.s.GetAll(tenantID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve treatments"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, treatments)
+	}
+}
+
+// Put godoc
+// @Summary Update a treatment
+// @Description This endpoint allows you to update a treatment with the provided data.
+// @Tags Treatments
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param treatment body domain.Treatment true "Updated treatment information"
+// @Success 200 {object} domain.Treatment "Updated treatment"
+// @Failure 400 "Invalid treatment data or missing required fields"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 500 "Failed to update treatment"
+// @Router /treatments/{id} [put]
+func (h *treatmentHandler) Put() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+
+		var treatment domain.Treatment
+		err := ctx.ShouldBindJSON(&treatment)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid treatment"})
+			return
+		}
+
+		if treatment.Id == 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "treatment id is required"})
+			return
+		}
+
+		err = h.s.Update(tenantID, treatment)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update treatment"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, treatment)
+	}
+}
+
+// Delete godoc
+// @Summary Delete a treatment
+// @Description This endpoint allows you to delete a treatment by its ID.
+// @Tags Treatments
+// @Param token header string true "TOKEN"
+// @Param id path int true "Treatment ID"
+// @Success 204 "Treatment deleted successfully"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 500 "Failed to delete treatment"
+// @Router /treatments/{id} [delete]
+func (h *treatmentHandler) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+
+		idParam := ctx.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		err = h.s.Delete(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to delete treatment"})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
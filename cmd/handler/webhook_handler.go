@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type webhookHandler struct {
+	s service.WebhookService
+}
+
+func NewWebhookHandler(s service.WebhookService) *webhookHandler {
+	return &webhookHandler{
+		s: s,
+	}
+}
+
+// Post godoc
+// @Summary Subscribe a webhook
+// @Description This endpoint registers a URL that receives the selected events as signed JSON POST requests. Every request carries an X-Webhook-Signature header "t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">" keyed with the secret. The secret is returned only here.
+// @Tags Webhooks
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param webhook body domain.Webhook true "Webhook"
+// @Success 201 {object} domain.Webhook "Webhook and its secret"
+// @Response 400 "Invalid webhook data, URL or event types"
+// @Response 401 "Unauthorized access due to missing or invalid token"
+// @Router /webhooks [post]
+func (h *webhookHandler) Post() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		var webhook domain.Webhook
+		if err := ctx.ShouldBindJSON(&webhook); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook data"})
+			return
+		}
+		webhook, err := h.s.Create(tenantID, webhook)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to create webhook: " + err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, webhook)
+	}
+}
+
+// GetByID godoc
+// @Summary Get a webhook by ID
+// @Description This endpoint allows you to retrieve a webhook by its ID. The secret is not returned.
+// @Tags Webhooks
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} domain.Webhook "Webhook"
+// @Failure 400 "Invalid ID"
+// @Failure 404 "Webhook not found"
+// @Router /webhooks/{id} [get]
+func (h *webhookHandler) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		webhook, err := h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, webhook)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all webhooks
+// @Description This endpoint allows you to retrieve the webhooks of the practice. Secrets are not returned.
+// @Tags Webhooks
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Success 200 {array} domain.Webhook "Webhooks"
+// @Failure 500 "Failed to retrieve webhooks"
+// @Router /webhooks [get]
+func (h *webhookHandler) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		webhooks, err := h.s.GetAll(tenantID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve webhooks"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, webhooks)
+	}
+}
+
+// Put godoc
+// @Summary Update a webhook
+// @Description This endpoint replaces the URL, event types and active flag of a webhook. The secret is kept unless a new one is sent.
+// @Tags Webhooks
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Webhook ID"
+// @Param webhook body domain.Webhook true "Updated webhook information"
+// @Success 200 {object} domain.Webhook "Updated webhook"
+// @Failure 400 "Invalid webhook data, URL or event types"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Webhook not found"
+// @Router /webhooks/{id} [put]
+func (h *webhookHandler) Put() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		var webhook domain.Webhook
+		if err := ctx.ShouldBindJSON(&webhook); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook"})
+			return
+		}
+		webhook.Id = id
+
+		if _, err := h.s.GetByID(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+			return
+		}
+		if err := h.s.Update(tenantID, webhook); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to update webhook: " + err.Error()})
+			return
+		}
+
+		webhook, err = h.s.GetByID(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve webhook"})
+			return
+		}
+		ctx.JSON(http.StatusOK, webhook)
+	}
+}
+
+// Delete godoc
+// @Summary Delete a webhook
+// @Description This endpoint removes a webhook together with its delivery log.
+// @Tags Webhooks
+// @Param token header string true "TOKEN"
+// @Param id path int true "Webhook ID"
+// @Success 204 "Webhook deleted successfully"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Webhook not found"
+// @Router /webhooks/{id} [delete]
+func (h *webhookHandler) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		if err := h.s.Delete(tenantID, id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// GetDeliveries godoc
+// @Summary Get the delivery log of a webhook
+// @Description This endpoint returns the latest 100 deliveries of a webhook, newest first, with every attempt made.
+// @Tags Webhooks
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Webhook ID"
+// @Success 200 {array} domain.WebhookDelivery "Deliveries"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Webhook not found"
+// @Router /webhooks/{id}/deliveries [get]
+func (h *webhookHandler) GetDeliveries() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		deliveries, err := h.s.GetDeliveries(tenantID, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, deliveries)
+	}
+}
+
+// Replay godoc
+// @Summary Replay a webhook delivery
+// @Description This endpoint queues a delivery to be sent again, whether it was delivered or failed, with a fresh retry budget. The receiver gets the same X-Webhook-Delivery id and should deduplicate on it.
+// @Tags Webhooks
+// @Produce json
+// @Param token header string true "TOKEN"
+// @Param id path int true "Webhook ID"
+// @Param deliveryId path int true "Delivery ID"
+// @Success 202 {object} domain.WebhookDelivery "Queued delivery"
+// @Failure 400 "Invalid ID"
+// @Failure 401 "Unauthorized access due to missing or invalid token"
+// @Failure 404 "Delivery not found"
+// @Router /webhooks/{id}/deliveries/{deliveryId}/replay [post]
+func (h *webhookHandler) Replay() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID := middleware.TenantID(ctx)
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		deliveryID, err := strconv.ParseInt(ctx.Param("deliveryId"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery id"})
+			return
+		}
+
+		delivery, err := h.s.Replay(tenantID, id, deliveryID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "delivery not found"})
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, delivery)
+	}
+}
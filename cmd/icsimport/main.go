@@ -0,0 +1,86 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/internal/service"
+	storeAppointment "proyecto_final_go/pkg/store/appointment"
+	storeClinic "proyecto_final_go/pkg/store/clinic"
+	storeConsent "proyecto_final_go/pkg/store/consent"
+	storeDentist "proyecto_final_go/pkg/store/dentist"
+	storeHistory "proyecto_final_go/pkg/store/history"
+	storePatient "proyecto_final_go/pkg/store/patient"
+	storePlan "proyecto_final_go/pkg/store/plan"
+	storeResource "proyecto_final_go/pkg/store/resource"
+	storeSchedule "proyecto_final_go/pkg/store/schedule"
+	storeTreatment "proyecto_final_go/pkg/store/treatment"
+	_ "time/tzdata"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// icsimport books the events of an iCalendar file as appointments of a
+// dentist and prints the import report as JSON.
+//
+//	go run ./cmd/icsimport -tenant 1 -dentist 3 -file agenda.ics
+func main() {
+	dsn := flag.String("dsn", "root:root@tcp(localhost:3306)/turnos-odontologia?parseTime=true&loc=UTC", "MySQL data source name")
+	tenantID := flag.Int("tenant", 1, "Tenant ID")
+	dentistID := flag.Int("dentist", 0, "Dentist ID")
+	path := flag.String("file", "", "iCalendar file to import")
+	tz := flag.String("tz", "", "Time zone of the event times without a zone, defaults to America/Argentina/Buenos_Aires")
+	flag.Parse()
+	if *dentistID == 0 || *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	db, err := sql.Open("mysql", *dsn)
+	if err != nil {
+		fail(err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		fail(err)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		fail(err)
+	}
+	defer file.Close()
+
+	repoDentists := repository.NewDentistRepository(storeDentist.NewSqlStore(db))
+	repoPatients := repository.NewPatientRepository(storePatient.NewSqlStore(db))
+	repoAppointments := repository.NewAppointmentRepository(storeAppointment.NewSqlAppointmentStore(db))
+	repoTreatments := repository.NewTreatmentRepository(storeTreatment.NewSqlStore(db))
+	repoResources := repository.NewResourceRepository(storeResource.NewSqlStore(db))
+	repoClinics := repository.NewClinicRepository(storeClinic.NewSqlStore(db))
+	repoSchedules := repository.NewScheduleRepository(storeSchedule.NewSqlStore(db))
+	repoHistory := repository.NewHistoryRepository(storeHistory.NewSqlStore(db))
+	repoPlans := repository.NewPlanRepository(storePlan.NewSqlStore(db))
+	repoConsents := repository.NewConsentRepository(storeConsent.NewSqlStore(db))
+	serviceAppointments := service.NewAppointmentService(repoAppointments, repoDentists, repoTreatments, repoResources, repoClinics, repoSchedules, repoHistory, repoPlans, repoConsents)
+	serviceImports := service.NewImportService(serviceAppointments, repoAppointments, repoPatients, repoDentists)
+
+	report, err := serviceImports.ImportCalendar(*tenantID, *dentistID, file, *tz)
+	if err != nil {
+		fail(err)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+	fmt.Fprintf(os.Stderr, "imported %d, skipped %d, conflicts %d, new patients %d\n", report.Imported, report.Skipped, report.Conflicts, report.CreatedPatients)
+	if report.Conflicts > 0 {
+		os.Exit(1)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "icsimport:", err)
+	os.Exit(1)
+}
@@ -1,16 +1,47 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"proyecto_final_go/cmd/handler"
-	_ "proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/domain"
 	"proyecto_final_go/internal/repository"
 	"proyecto_final_go/internal/service"
+	"proyecto_final_go/pkg/blob"
 	"proyecto_final_go/pkg/middleware"
+	"proyecto_final_go/pkg/notifier"
+	"proyecto_final_go/pkg/outbox"
+	"proyecto_final_go/pkg/payment"
 	storeAppointment "proyecto_final_go/pkg/store/appointment"
+	storeAttachment "proyecto_final_go/pkg/store/attachment"
+	storeCalendar "proyecto_final_go/pkg/store/calendar"
+	storeClinic "proyecto_final_go/pkg/store/clinic"
+	storeConsent "proyecto_final_go/pkg/store/consent"
 	storeDentist "proyecto_final_go/pkg/store/dentist"
+	storeEvent "proyecto_final_go/pkg/store/event"
+	storeHistory "proyecto_final_go/pkg/store/history"
+	storeInsurance "proyecto_final_go/pkg/store/insurance"
+	storeInvoice "proyecto_final_go/pkg/store/invoice"
+	storeNote "proyecto_final_go/pkg/store/note"
+	storeOdontogram "proyecto_final_go/pkg/store/odontogram"
 	storePatient "proyecto_final_go/pkg/store/patient"
+	storePayment "proyecto_final_go/pkg/store/payment"
+	storePlan "proyecto_final_go/pkg/store/plan"
+	storePrescription "proyecto_final_go/pkg/store/prescription"
+	storeReminder "proyecto_final_go/pkg/store/reminder"
+	storeResource "proyecto_final_go/pkg/store/resource"
+	storeSchedule "proyecto_final_go/pkg/store/schedule"
+	storeSpecialty "proyecto_final_go/pkg/store/specialty"
+	storeTenant "proyecto_final_go/pkg/store/tenant"
+	storeTreatment "proyecto_final_go/pkg/store/treatment"
+	storeWebhook "proyecto_final_go/pkg/store/webhook"
+	"proyecto_final_go/pkg/stream"
+	"proyecto_final_go/pkg/webhook"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
 
 	docs "proyecto_final_go/cmd/docs"
 
@@ -31,7 +62,7 @@ func main() {
 	if err := godotenv.Load(".env"); err != nil {
 		panic("Error loading .env file: " + err.Error())
 	}
-	db, err := sql.Open("mysql", "root:root@tcp(localhost:3306)/turnos-odontologia")
+	db, err := sql.Open("mysql", "root:root@tcp(localhost:3306)/turnos-odontologia?parseTime=true&loc=UTC")
 	if err != nil {
 		panic(err.Error())
 	}
@@ -44,6 +75,30 @@ func main() {
 	storageDentists := storeDentist.NewSqlStore(db)
 	storagePatients := storePatient.NewSqlStore(db)
 	storageAppointments := storeAppointment.NewSqlAppointmentStore(db)
+	storageSpecialties := storeSpecialty.NewSqlStore(db)
+	storageTreatments := storeTreatment.NewSqlStore(db)
+	storageResources := storeResource.NewSqlStore(db)
+	storageClinics := storeClinic.NewSqlStore(db)
+	storageSchedules := storeSchedule.NewSqlStore(db)
+	storageTenants := storeTenant.NewSqlStore(db)
+	storageReminders := storeReminder.NewSqlStore(db)
+	storageWebhooks := storeWebhook.NewSqlStore(db)
+	storageEvents := storeEvent.NewSqlStore(db)
+	storageCalendars := storeCalendar.NewSqlStore(db)
+	storageHistory := storeHistory.NewSqlStore(db)
+	storageNotes := storeNote.NewSqlStore(db)
+	storageOdontograms := storeOdontogram.NewSqlStore(db)
+	storagePlans := storePlan.NewSqlStore(db)
+	storageAttachments := storeAttachment.NewSqlStore(db)
+	storageConsents := storeConsent.NewSqlStore(db)
+	storageInvoices := storeInvoice.NewSqlStore(db)
+	storagePayments := storePayment.NewSqlStore(db)
+	storageInsurance := storeInsurance.NewSqlStore(db)
+	storagePrescriptions := storePrescription.NewSqlStore(db)
+
+	repoTenants := repository.NewTenantRepository(storageTenants)
+	serviceTenants := service.NewTenantService(repoTenants)
+	handlerTenants := handler.NewTenantHandler(serviceTenants)
 
 	repoDentists := repository.NewDentistRepository(storageDentists)
 	serviceDentists := service.NewDentistService(repoDentists)
@@ -53,11 +108,141 @@ func main() {
 	servicePatients := service.NewPatientService(repoPatients)
 	handlerPatients := handler.NewPatientHandler(servicePatients)
 
+	repoSpecialties := repository.NewSpecialtyRepository(storageSpecialties)
+	serviceSpecialties := service.NewSpecialtyService(repoSpecialties, repoDentists)
+	handlerSpecialties := handler.NewSpecialtyHandler(serviceSpecialties)
+
+	repoTreatments := repository.NewTreatmentRepository(storageTreatments)
+	serviceTreatments := service.NewTreatmentService(repoTreatments, repoSpecialties)
+	handlerTreatments := handler.NewTreatmentHandler(serviceTreatments)
+
+	repoClinics := repository.NewClinicRepository(storageClinics)
+	serviceClinics := service.NewClinicService(repoClinics)
+	handlerClinics := handler.NewClinicHandler(serviceClinics)
+
+	repoResources := repository.NewResourceRepository(storageResources)
+	serviceResources := service.NewResourceService(repoResources, repoClinics)
+	handlerResources := handler.NewResourceHandler(serviceResources)
+
+	repoSchedules := repository.NewScheduleRepository(storageSchedules)
+	repoHistory := repository.NewHistoryRepository(storageHistory)
+	repoPlans := repository.NewPlanRepository(storagePlans)
+	repoConsents := repository.NewConsentRepository(storageConsents)
+
 	repoAppointments := repository.NewAppointmentRepository(storageAppointments)
-	serviceAppointments := service.NewAppointmentService(repoAppointments)
+	serviceAppointments := service.NewAppointmentService(repoAppointments, repoDentists, repoTreatments, repoResources, repoClinics, repoSchedules, repoHistory, repoPlans, repoConsents)
 	handlerAppointments := handler.NewAppointmentHandler(serviceAppointments)
 
+	serviceSchedules := service.NewScheduleService(repoSchedules, repoDentists, repoClinics, repoAppointments)
+	handlerSchedules := handler.NewScheduleHandler(serviceSchedules)
+
+	notifications, err := notifier.FromEnv()
+	if err != nil {
+		panic(err.Error())
+	}
+	reminderOffsets, err := service.ParseReminderOffsets(os.Getenv("REMINDER_OFFSETS"))
+	if err != nil {
+		panic(err.Error())
+	}
+	reminderInterval := time.Minute
+	if interval := os.Getenv("REMINDER_INTERVAL"); interval != "" {
+		reminderInterval, err = time.ParseDuration(interval)
+		if err != nil {
+			panic("Invalid REMINDER_INTERVAL: " + err.Error())
+		}
+	}
+	repoReminders := repository.NewReminderRepository(storageReminders)
+	serviceReminders := service.NewReminderService(repoTenants, repoAppointments, repoReminders, notifications, reminderOffsets)
+	go serviceReminders.Run(context.Background(), reminderInterval)
+
+	repoWebhooks := repository.NewWebhookRepository(storageWebhooks)
+	serviceWebhooks := service.NewWebhookService(repoWebhooks, repoTenants, webhook.NewSender(10*time.Second))
+	handlerWebhooks := handler.NewWebhookHandler(serviceWebhooks)
+	go serviceWebhooks.Run(context.Background(), 5*time.Second)
+
+	dispatcher := outbox.NewDispatcher(db, time.Second)
+	dispatcher.Register(outbox.AllEvents, "log", outbox.LogHandler)
+	dispatcher.Register(outbox.AllEvents, "webhooks", serviceWebhooks.HandleEvent)
+	go dispatcher.Run(context.Background())
+
+	repoEvents := repository.NewEventRepository(storageEvents)
+	broker := stream.NewBroker(repoEvents, 500*time.Millisecond)
+	go broker.Run(context.Background())
+	serviceEvents := service.NewEventService(repoEvents, broker)
+	handlerEvents := handler.NewEventHandler(serviceEvents)
+
+	repoCalendars := repository.NewCalendarRepository(storageCalendars)
+	serviceCalendars := service.NewCalendarService(repoCalendars, repoAppointments, repoDentists, repoPatients, repoEvents)
+	handlerCalendars := handler.NewCalendarHandler(serviceCalendars)
+
+	serviceHistory := service.NewHistoryService(repoHistory, repoPatients)
+	handlerHistory := handler.NewHistoryHandler(serviceHistory)
+
+	repoNotes := repository.NewNoteRepository(storageNotes)
+	serviceNotes := service.NewNoteService(repoNotes, repoAppointments, repoPatients)
+	handlerNotes := handler.NewNoteHandler(serviceNotes)
+
+	repoOdontograms := repository.NewOdontogramRepository(storageOdontograms)
+	serviceOdontograms := service.NewOdontogramService(repoOdontograms, repoAppointments, repoPatients)
+	handlerOdontograms := handler.NewOdontogramHandler(serviceOdontograms)
+
+	servicePlans := service.NewPlanService(repoPlans, repoPatients, repoTreatments)
+	handlerPlans := handler.NewPlanHandler(servicePlans)
+
+	blobs, err := blob.FromEnv()
+	if err != nil {
+		panic(err.Error())
+	}
+	var attachmentMaxSize int64
+	if size := os.Getenv("ATTACHMENT_MAX_SIZE"); size != "" {
+		attachmentMaxSize, err = strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			panic("Invalid ATTACHMENT_MAX_SIZE: " + err.Error())
+		}
+	}
+	repoAttachments := repository.NewAttachmentRepository(storageAttachments)
+	serviceAttachments := service.NewAttachmentService(repoAttachments, repoPatients, repoAppointments, blobs, attachmentMaxSize)
+	handlerAttachments := handler.NewAttachmentHandler(serviceAttachments)
+
+	serviceConsents := service.NewConsentService(repoConsents, repoAppointments, repoTreatments, repoTenants, blobs)
+	handlerConsents := handler.NewConsentHandler(serviceConsents)
+
+	repoInsurance := repository.NewInsuranceRepository(storageInsurance)
+	repoInvoices := repository.NewInvoiceRepository(storageInvoices)
+	serviceInvoices := service.NewInvoiceService(repoInvoices, repoPatients, repoAppointments, repoTreatments, repoInsurance)
+	handlerInvoices := handler.NewInvoiceHandler(serviceInvoices)
+
+	serviceInsurance := service.NewInsuranceService(repoInsurance, repoInvoices, repoPatients, repoTreatments)
+	handlerInsurance := handler.NewInsuranceHandler(serviceInsurance)
+
+	paymentProvider, err := payment.FromEnv()
+	if err != nil {
+		panic(err.Error())
+	}
+	repoPayments := repository.NewPaymentRepository(storagePayments)
+	servicePayments := service.NewPaymentService(repoPayments, repoInvoices, repoPatients, repoAppointments, repoClinics, paymentProvider)
+	handlerPayments := handler.NewPaymentHandler(servicePayments)
+
+	repoPrescriptions := repository.NewPrescriptionRepository(storagePrescriptions)
+	servicePrescriptions := service.NewPrescriptionService(repoPrescriptions, repoAppointments, repoPatients, repoTenants)
+	handlerPrescriptions := handler.NewPrescriptionHandler(servicePrescriptions)
+
+	serviceImports := service.NewImportService(serviceAppointments, repoAppointments, repoPatients, repoDentists)
+	handlerImports := handler.NewImportHandler(serviceImports)
+
+	servicePrints := service.NewPrintService(repoTenants, repoAppointments, repoDentists)
+	handlerPrints := handler.NewPrintHandler(servicePrints)
+
 	r := gin.New()
+	// Client IPs, which rate limits are kept by, are only taken from
+	// X-Forwarded-For when the request comes through a trusted proxy.
+	var trustedProxies []string
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		trustedProxies = strings.Split(proxies, ",")
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		panic(err.Error())
+	}
 	r.Use(gin.Recovery())
 	r.Use(middleware.Logger())
 
@@ -66,38 +251,235 @@ func main() {
 	docs.SwaggerInfo.Host = os.Getenv("HOST")
 	r.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	dentists := r.Group("/dentists")
+	admin := r.Group("/admin", middleware.AdminAuthentication())
+	{
+		admin.POST("/tenants", handlerTenants.Post())
+		admin.GET("/tenants/:id", handlerTenants.GetByID())
+		admin.POST("/tenants/:id/token", handlerTenants.RotateToken())
+		admin.DELETE("/tenants/:id", handlerTenants.Delete())
+		admin.GET("/tenants", handlerTenants.GetAll())
+	}
+
+	// Calendar apps cannot send headers, the feeds are authenticated with
+	// their own secret token.
+	r.GET("/dentists/:id/calendar.ics", handlerCalendars.Feed(domain.CalendarOwnerDentist))
+	r.GET("/patients/:id/calendar.ics", handlerCalendars.Feed(domain.CalendarOwnerPatient))
+
+	// Pharmacies check the code printed on a prescription without a token.
+	r.GET("/prescriptions/check/:code", middleware.RateLimit(6*time.Second, 10), handlerPrescriptions.Check())
+
+	// Every practice endpoint is scoped to the tenant owning the token.
+	authentication := middleware.Authentication(serviceTenants)
+
+	dentists := r.Group("/dentists", authentication)
 	{
-		dentists.POST("", middleware.Authentication(), handlerDentists.Post())
+		dentists.POST("", handlerDentists.Post())
+		dentists.POST("/import", handlerImports.ImportDentists())
 		dentists.GET(":id", handlerDentists.GetByID())
-		dentists.PUT(":id", middleware.Authentication(), handlerDentists.Put())
-		dentists.PATCH(":id", middleware.Authentication(), handlerDentists.Patch())
-		dentists.DELETE(":id", middleware.Authentication(), handlerDentists.Delete())
+		dentists.PUT(":id", handlerDentists.Put())
+		dentists.PATCH(":id", handlerDentists.Patch())
+		dentists.DELETE(":id", handlerDentists.Delete())
 		dentists.GET("", handlerDentists.GetAll())
+		dentists.POST(":id/specialties", handlerSpecialties.AssignToDentist())
+		dentists.DELETE(":id/specialties/:specialtyId", handlerSpecialties.RemoveFromDentist())
+		dentists.POST(":id/schedules", handlerSchedules.Post())
+		dentists.GET(":id/schedules", handlerSchedules.GetByDentist())
+		dentists.DELETE(":id/schedules/:scheduleId", handlerSchedules.Delete())
+		dentists.GET(":id/availability", handlerSchedules.Availability())
+		dentists.GET(":id/schedule.pdf", handlerPrints.Schedule())
+		dentists.POST(":id/calendar/token", handlerCalendars.IssueToken(domain.CalendarOwnerDentist))
+		dentists.DELETE(":id/calendar/token", handlerCalendars.RevokeToken(domain.CalendarOwnerDentist))
+		dentists.POST(":id/calendar/import", handlerImports.ImportCalendar())
 	}
 
-	patients := r.Group("/patients")
+	clinics := r.Group("/clinics", authentication)
 	{
-		patients.POST("", middleware.Authentication(), handlerPatients.Post())
+		clinics.POST("", handlerClinics.Post())
+		clinics.GET(":id", handlerClinics.GetByID())
+		clinics.PUT(":id", handlerClinics.Put())
+		clinics.DELETE(":id", handlerClinics.Delete())
+		clinics.GET("", handlerClinics.GetAll())
+		clinics.GET(":id/cash-close", handlerPayments.CashClose())
+	}
+
+	specialties := r.Group("/specialties", authentication)
+	{
+		specialties.POST("", handlerSpecialties.Post())
+		specialties.GET(":id", handlerSpecialties.GetByID())
+		specialties.DELETE(":id", handlerSpecialties.Delete())
+		specialties.GET("", handlerSpecialties.GetAll())
+	}
+
+	treatments := r.Group("/treatments", authentication)
+	{
+		treatments.POST("", handlerTreatments.Post())
+		treatments.GET(":id", handlerTreatments.GetByID())
+		treatments.PUT(":id", handlerTreatments.Put())
+		treatments.DELETE(":id", handlerTreatments.Delete())
+		treatments.GET("", handlerTreatments.GetAll())
+	}
+
+	consentTemplates := r.Group("/consent-templates", authentication)
+	{
+		consentTemplates.POST("", handlerConsents.PostTemplate())
+		consentTemplates.GET(":id", handlerConsents.GetTemplate())
+		consentTemplates.PUT(":id", handlerConsents.PutTemplate())
+		consentTemplates.GET("", handlerConsents.GetTemplates())
+	}
+
+	patients := r.Group("/patients", authentication)
+	{
+		patients.POST("", handlerPatients.Post())
+		patients.POST("/import", handlerImports.ImportPatients())
+		patients.GET("/search", handlerPatients.Search())
 		patients.GET(":id", handlerPatients.GetByID())
-		patients.PUT(":id", middleware.Authentication(), handlerPatients.Put())
-		patients.PATCH(":id", middleware.Authentication(), handlerPatients.Patch())
-		patients.DELETE(":id", middleware.Authentication(), handlerPatients.Delete())
+		patients.GET(":id/history", handlerHistory.GetByPatient())
+		patients.POST(":id/history", handlerHistory.Post())
+		patients.GET(":id/history/:entryId", handlerHistory.GetByID())
+		patients.PUT(":id/history/:entryId", handlerHistory.Put())
+		patients.DELETE(":id/history/:entryId", handlerHistory.Delete())
+		patients.GET(":id/timeline", handlerNotes.Timeline())
+		patients.GET(":id/odontogram", handlerOdontograms.GetChart())
+		patients.GET(":id/odontogram/findings", handlerOdontograms.GetFindings())
+		patients.GET(":id/plans", handlerPlans.GetByPatient())
+		patients.POST(":id/plans", handlerPlans.Post())
+		patients.GET(":id/attachments", handlerAttachments.GetByPatient())
+		patients.POST(":id/attachments", handlerAttachments.PostToPatient())
+		patients.GET(":id/invoices", handlerInvoices.GetByPatient())
+		patients.POST(":id/invoices", handlerInvoices.Post())
+		patients.GET(":id/balance", handlerInvoices.Balance())
+		patients.GET(":id/payments", handlerPayments.GetByPatient())
+		patients.GET(":id/coverages", handlerInsurance.GetCoverages())
+		patients.POST(":id/coverages", handlerInsurance.PostCoverage())
+		patients.GET(":id/prescriptions", handlerPrescriptions.GetByPatient())
+		patients.PUT(":id", handlerPatients.Put())
+		patients.PATCH(":id", handlerPatients.Patch())
+		patients.DELETE(":id", handlerPatients.Delete())
 		patients.GET("", handlerPatients.GetAll())
+		patients.POST(":id/calendar/token", handlerCalendars.IssueToken(domain.CalendarOwnerPatient))
+		patients.DELETE(":id/calendar/token", handlerCalendars.RevokeToken(domain.CalendarOwnerPatient))
+	}
+
+	resources := r.Group("/resources", authentication)
+	{
+		resources.POST("", handlerResources.Post())
+		resources.GET(":id", handlerResources.GetByID())
+		resources.PUT(":id", handlerResources.Put())
+		resources.DELETE(":id", handlerResources.Delete())
+		resources.GET("", handlerResources.GetAll())
 	}
 
-	appointments := r.Group("/appointments")
+	appointments := r.Group("/appointments", authentication)
 	{
-		appointments.POST("", middleware.Authentication(), handlerAppointments.Post())
+		appointments.POST("", handlerAppointments.Post())
 		appointments.GET(":id", handlerAppointments.GetByID())
-		appointments.PUT(":id", middleware.Authentication(), handlerAppointments.Put())
-		appointments.PATCH(":id/description", middleware.Authentication(), handlerAppointments.PatchDescription())
-		appointments.DELETE(":id", middleware.Authentication(), handlerAppointments.Delete())
-		appointments.POST("/dnilicense", middleware.Authentication(), handlerAppointments.PostByDNIAndLicense())
+		appointments.GET(":id/slip.pdf", handlerPrints.Slip())
+		appointments.GET(":id/notes", handlerNotes.GetByAppointment())
+		appointments.POST(":id/notes", handlerNotes.Post())
+		appointments.POST(":id/notes/:noteId/amendments", handlerNotes.Amend())
+		appointments.POST(":id/odontogram", handlerOdontograms.Post())
+		appointments.GET(":id/consents", handlerConsents.GetByAppointment())
+		appointments.POST(":id/consents", handlerConsents.Post())
+		appointments.POST(":id/check-in", handlerAppointments.CheckIn())
+		appointments.POST(":id/complete", handlerAppointments.Complete())
+		appointments.GET(":id/attachments", handlerAttachments.GetByAppointment())
+		appointments.POST(":id/attachments", handlerAttachments.PostToAppointment())
+		appointments.GET(":id/prescriptions", handlerPrescriptions.GetByAppointment())
+		appointments.POST(":id/prescriptions", handlerPrescriptions.Post())
+		appointments.PUT(":id", handlerAppointments.Put())
+		appointments.PATCH(":id/description", handlerAppointments.PatchDescription())
+		appointments.DELETE(":id", handlerAppointments.Delete())
+		appointments.POST("/dnilicense", handlerAppointments.PostByDNIAndLicense())
 		appointments.GET("/patient", handlerAppointments.GetByPatientDNI())
 		appointments.GET("", handlerAppointments.GetAll())
 	}
 
+	plans := r.Group("/plans", authentication)
+	{
+		plans.GET(":id", handlerPlans.GetByID())
+		plans.PATCH(":id", handlerPlans.PatchStatus())
+		plans.POST(":id/steps", handlerPlans.PostStep())
+		plans.PUT(":id/steps/:stepId", handlerPlans.PutStep())
+	}
+
+	consents := r.Group("/consents", authentication)
+	{
+		consents.GET(":id", handlerConsents.GetByID())
+		consents.POST(":id/sign", handlerConsents.Sign())
+		consents.GET(":id/document", handlerConsents.Document())
+	}
+
+	invoices := r.Group("/invoices", authentication)
+	{
+		invoices.GET(":id", handlerInvoices.GetByID())
+		invoices.PATCH(":id", handlerInvoices.PatchStatus())
+		invoices.GET("", handlerInvoices.GetAll())
+		invoices.GET(":id/payments", handlerPayments.GetByInvoice())
+		invoices.POST(":id/payments", handlerPayments.Post())
+		invoices.GET(":id/claims", handlerInsurance.GetClaimsByInvoice())
+		invoices.POST(":id/claims", handlerInsurance.PostClaim())
+	}
+
+	payments := r.Group("/payments", authentication)
+	{
+		payments.GET(":id", handlerPayments.GetByID())
+		payments.POST(":id/refunds", handlerPayments.Refund())
+	}
+
+	insuranceProviders := r.Group("/insurance-providers", authentication)
+	{
+		insuranceProviders.POST("", handlerInsurance.PostProvider())
+		insuranceProviders.GET(":id", handlerInsurance.GetProvider())
+		insuranceProviders.PUT(":id", handlerInsurance.PutProvider())
+		insuranceProviders.GET("", handlerInsurance.GetProviders())
+		insuranceProviders.POST(":id/rules", handlerInsurance.PostRule())
+		insuranceProviders.PUT(":id/rules/:ruleId", handlerInsurance.PutRule())
+		insuranceProviders.DELETE(":id/rules/:ruleId", handlerInsurance.DeleteRule())
+	}
+
+	coverages := r.Group("/coverages", authentication)
+	{
+		coverages.GET(":id", handlerInsurance.GetCoverage())
+		coverages.PUT(":id", handlerInsurance.PutCoverage())
+	}
+
+	claims := r.Group("/claims", authentication)
+	{
+		claims.GET("", handlerInsurance.GetClaims())
+		claims.GET(":id", handlerInsurance.GetClaim())
+		claims.PATCH(":id", handlerInsurance.PatchClaim())
+	}
+
+	prescriptions := r.Group("/prescriptions", authentication)
+	{
+		prescriptions.GET(":id", handlerPrescriptions.GetByID())
+		prescriptions.POST(":id/void", handlerPrescriptions.Void())
+		prescriptions.GET(":id/prescription.pdf", handlerPrescriptions.Document())
+	}
+
+	attachments := r.Group("/attachments", authentication)
+	{
+		attachments.GET(":id", handlerAttachments.GetByID())
+		attachments.GET(":id/content", handlerAttachments.Download())
+		attachments.DELETE(":id", handlerAttachments.Delete())
+	}
+
+	events := r.Group("/events", middleware.StreamAuthentication(serviceTenants))
+	{
+		events.GET("/stream", handlerEvents.Stream())
+	}
+
+	webhooks := r.Group("/webhooks", authentication)
+	{
+		webhooks.POST("", handlerWebhooks.Post())
+		webhooks.GET(":id", handlerWebhooks.GetByID())
+		webhooks.PUT(":id", handlerWebhooks.Put())
+		webhooks.DELETE(":id", handlerWebhooks.Delete())
+		webhooks.GET("", handlerWebhooks.GetAll())
+		webhooks.GET(":id/deliveries", handlerWebhooks.GetDeliveries())
+		webhooks.POST(":id/deliveries/:deliveryId/replay", handlerWebhooks.Replay())
+	}
+
 	r.Run()
 
 }
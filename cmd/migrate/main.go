@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"proyecto_final_go/pkg/migrate"
+	_ "time/tzdata"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// migrate upgrades a database created by an earlier build-database.sql.
+// Run build-database.sql first to create the new tables, then:
+//
+//	go run ./cmd/migrate
+func main() {
+	dsn := flag.String("dsn", "root:root@tcp(localhost:3306)/turnos-odontologia?parseTime=true&loc=UTC", "MySQL data source name")
+	flag.Parse()
+
+	db, err := sql.Open("mysql", *dsn)
+	if err != nil {
+		fail(err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		fail(err)
+	}
+
+	applied, err := migrate.Run(context.Background(), db)
+	for _, name := range applied {
+		fmt.Println("applied", name)
+	}
+	if err != nil {
+		fail(err)
+	}
+	if len(applied) == 0 {
+		fmt.Println("the database is up to date")
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "migrate:", err)
+	os.Exit(1)
+}
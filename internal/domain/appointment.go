@@ -1,5 +1,14 @@
 package domain
 
+import (
+	"errors"
+	"time"
+)
+
+// ErrAppointmentHasRecords is returned when cancelling an appointment that
+// records of the visit refer to; those records must be kept.
+var ErrAppointmentHasRecords = errors.New("The appointment can not be cancelled, records of the visit refer to it")
+
 type Appointment struct {
 	// @Description The unique identifier of the appointment
 	// @Example 1
@@ -8,13 +17,51 @@ type Appointment struct {
 	Patient Patient `json:"patients_Id" binding:"required"`
 	// @Description Information related to the patient
 	Dentist Dentist `json:"dentists_Id" binding:"required"`
-	// @Description The release date of the patient (dd/MM/YYYY)
+	// @Description The treatment to be performed (optional)
+	Treatment Treatment `json:"treatments_Id"`
+	// @Description The rooms, chairs and equipment reserved by the appointment
+	Resources []Resource `json:"Resources"`
+	// @Description The clinic where the appointment takes place (optional)
+	Clinic Clinic `json:"clinics_Id"`
+	// @Description The local date of the appointment (dd/MM/YYYY) in TimeZone
 	// @Example "30/03/2024"
 	Date string `json:"Date" binding:"required"`
-	// @Description The time of the appointment in 24-hour format
+	// @Description The local time of the appointment in 24-hour format in TimeZone
 	// @Example "09:00"
 	Hour string `json:"Hour" binding:"required"`
+	// @Description The start of the appointment in UTC, derived from Date, Hour and TimeZone
+	// @Example "2024-03-30T12:00:00Z"
+	StartsAt time.Time `json:"StartsAt"`
+	// @Description The time zone of Date and Hour (optional, defaults to the clinic time zone)
+	// @Example "America/Argentina/Buenos_Aires"
+	TimeZone string `json:"TimeZone"`
 	// @Description The description of the appointment
 	// @Example "Routine checkup"
 	Description string `json:"Description" binding:"required"`
+	// @Description The treatment plan step the appointment is booked for (optional)
+	// @Example 0
+	PlanStepId int `json:"plan_steps_Id"`
+	// @Description When the appointment was completed, empty if it was not
+	CompletedAt *time.Time `json:"CompletedAt"`
+	// @Description When the patient checked in at the clinic, empty if they did not
+	CheckedInAt *time.Time `json:"CheckedInAt"`
+	// @Description Whether the patient has allergies or conditions to check before treating them. Only set when reading a single appointment
+	// @Example true
+	MedicalAlert bool `json:"MedicalAlert"`
+	// @Description The medical history entries behind MedicalAlert
+	// @Example ["Allergy: Penicillin (severe)"]
+	Alerts []string `json:"Alerts,omitempty"`
+}
+
+// Localize sets Date, Hour and TimeZone from StartsAt in the given location.
+func (a *Appointment) Localize(loc *time.Location) {
+	local := a.StartsAt.In(loc)
+	a.Date = local.Format(DateLayout)
+	a.Hour = local.Format(HourLayout)
+	a.TimeZone = loc.String()
+}
+
+// Overlaps reports whether both appointments take place at the same time.
+func (a Appointment) Overlaps(other Appointment) bool {
+	return SlotsOverlap(a.StartsAt, other.StartsAt)
 }
@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// Kinds of attachments.
+const (
+	AttachmentXRay     = "xray"
+	AttachmentConsent  = "consent"
+	AttachmentPhoto    = "photo"
+	AttachmentDocument = "document"
+)
+
+type Attachment struct {
+	// @Description The unique identifier of the attachment
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The patient the file belongs to
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description The appointment the file was taken in, 0 when attached to the patient only
+	// @Example 0
+	AppointmentId int `json:"appointments_Id"`
+	// @Description What the file is (xray, consent, photo or document)
+	// @Example "xray"
+	Kind string `json:"Kind"`
+	// @Description The name of the uploaded file
+	// @Example "panoramic-2024.jpg"
+	FileName string `json:"FileName"`
+	// @Description The type of the content, detected from the file itself
+	// @Example "image/jpeg"
+	ContentType string `json:"ContentType"`
+	// @Description The size of the file in bytes
+	// @Example 482113
+	Size int64 `json:"Size"`
+	// @Description The SHA-256 of the content, checked on every download
+	// @Example "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	Checksum string `json:"Checksum"`
+	// @Description Any detail about the file (optional)
+	// @Example "Panoramic before the implant"
+	Description string `json:"Description"`
+	// @Description When the file was uploaded
+	UploadedAt time.Time `json:"UploadedAt"`
+	// The key of the content in the blob storage.
+	StorageKey string `json:"-"`
+}
+
+// ValidAttachmentKind reports whether kind is one of the known kinds.
+func ValidAttachmentKind(kind string) bool {
+	switch kind {
+	case AttachmentXRay, AttachmentConsent, AttachmentPhoto, AttachmentDocument:
+		return true
+	}
+	return false
+}
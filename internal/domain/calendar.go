@@ -0,0 +1,26 @@
+package domain
+
+// Owners of a calendar feed.
+const (
+	CalendarOwnerDentist = "dentist"
+	CalendarOwnerPatient = "patient"
+)
+
+// CalendarToken grants read access to the calendar feed of a dentist or a
+// patient. Only the hash of the token is stored.
+type CalendarToken struct {
+	TenantId  int
+	OwnerType string
+	OwnerId   int
+}
+
+// CalendarFeed is returned when a feed token is issued; the token is shown
+// only once.
+type CalendarFeed struct {
+	// @Description The URL to subscribe to from a calendar app
+	// @Example "https://api.example.com/dentists/1/calendar.ics?token=3f0c8e4a..."
+	URL string `json:"URL"`
+	// @Description The secret feed token, issuing a new one revokes the previous
+	// @Example "3f0c8e4a..."
+	Token string `json:"Token"`
+}
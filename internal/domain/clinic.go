@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// DefaultTimeZone is used for clinics without a time zone and for
+// appointments that are not bound to a clinic.
+const DefaultTimeZone = "America/Argentina/Buenos_Aires"
+
+type Clinic struct {
+	// @Description The unique identifier of the clinic
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The name of the clinic
+	// @Example "Downtown office"
+	Name string `json:"Name" binding:"required"`
+	// @Description The address of the clinic
+	// @Example "Av. 22 # 40"
+	Address string `json:"Address" binding:"required"`
+	// @Description The IANA time zone of the clinic, schedules and appointments are expressed in it
+	// @Example "America/Argentina/Cordoba"
+	TimeZone string `json:"TimeZone"`
+}
+
+// Location returns the time zone of the clinic.
+func (c Clinic) Location() (*time.Location, error) {
+	return LoadLocation(c.TimeZone)
+}
+
+// LoadLocation loads an IANA time zone, an empty name means DefaultTimeZone.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultTimeZone
+	}
+	return time.LoadLocation(name)
+}
@@ -0,0 +1,121 @@
+package domain
+
+import "time"
+
+// Statuses of a consent.
+const (
+	ConsentPending = "pending"
+	ConsentSigned  = "signed"
+)
+
+type ConsentTemplate struct {
+	// @Description The unique identifier of the template
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The title of the consent form
+	// @Example "Informed consent for tooth extraction"
+	Title string `json:"Title" binding:"required"`
+	// @Description The text the patient agrees to. Blank lines separate paragraphs
+	// @Example "I have been informed of the risks of the extraction..."
+	Body string `json:"Body" binding:"required"`
+	// @Description The treatments that can not start until the form is signed
+	Treatments []Treatment `json:"Treatments"`
+	// @Description Whether the form is still required. Inactive forms are kept for the consents already signed
+	// @Example true
+	Active bool `json:"Active"`
+	// @Description When the template was last changed
+	UpdatedAt time.Time `json:"UpdatedAt"`
+}
+
+// Requires reports whether the template has to be signed for the treatment.
+func (t ConsentTemplate) Requires(treatmentID int) bool {
+	if !t.Active || treatmentID == 0 {
+		return false
+	}
+	for _, treatment := range t.Treatments {
+		if treatment.Id == treatmentID {
+			return true
+		}
+	}
+	return false
+}
+
+type Consent struct {
+	// @Description The unique identifier of the consent
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The appointment the consent is given for
+	// @Example 1
+	AppointmentId int `json:"appointments_Id"`
+	// @Description The patient giving the consent
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description The template the consent was prepared from
+	// @Example 1
+	TemplateId int `json:"consent_templates_Id"`
+	// @Description The title of the form, as presented to the patient
+	// @Example "Informed consent for tooth extraction"
+	Title string `json:"Title"`
+	// @Description The text of the form, as presented to the patient
+	Body string `json:"Body"`
+	// @Description pending or signed
+	// @Example "signed"
+	Status string `json:"Status"`
+	// @Description The name of the person who signed
+	// @Example "Juan Perez"
+	SignerName string `json:"SignerName"`
+	// @Description Who the signer is to the patient
+	// @Example "Patient"
+	SignerRelationship string `json:"SignerRelationship"`
+	// @Description When the consent was signed, empty while pending
+	SignedAt *time.Time `json:"SignedAt"`
+	// @Description The SHA-256 of the signed document, checked on every download
+	// @Example "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	DocumentHash string `json:"DocumentHash"`
+	// @Description When the consent was prepared for the appointment
+	CreatedAt time.Time `json:"CreatedAt"`
+	// The key of the signed document in the blob storage.
+	StorageKey string `json:"-"`
+}
+
+// SignaturePoint is a point of a signature stroke, in the coordinates of the
+// pad it was drawn on, from its top left corner.
+type SignaturePoint struct {
+	X float64 `json:"X"`
+	Y float64 `json:"Y"`
+}
+
+type ConsentSignature struct {
+	// @Description The name of the person signing
+	// @Example "Juan Perez"
+	SignerName string `json:"SignerName" binding:"required"`
+	// @Description Who the signer is to the patient (optional, defaults to Patient)
+	// @Example "Patient"
+	SignerRelationship string `json:"SignerRelationship"`
+	// @Description The width of the signature pad
+	// @Example 400
+	Width float64 `json:"Width" binding:"required"`
+	// @Description The height of the signature pad
+	// @Example 150
+	Height float64 `json:"Height" binding:"required"`
+	// @Description The strokes of the signature, each a list of points drawn without lifting the pen
+	Strokes [][]SignaturePoint `json:"Strokes" binding:"required"`
+}
+
+// MissingConsents returns the templates the treatment requires that have no
+// signed consent among consents.
+func MissingConsents(templates []ConsentTemplate, treatmentID int, consents []Consent) []ConsentTemplate {
+	signed := map[int]bool{}
+	for _, consent := range consents {
+		if consent.Status == ConsentSigned {
+			signed[consent.TemplateId] = true
+		}
+	}
+	missing := []ConsentTemplate{}
+	for _, template := range templates {
+		if template.Requires(treatmentID) && !signed[template.Id] {
+			missing = append(missing, template)
+		}
+	}
+	return missing
+}
@@ -13,4 +13,6 @@ type Dentist struct {
 	// @Description The license number of the dentist
 	// @Example "AXMER"
 	License string `json:"License" binding:"required"`
+	// @Description The specialties held by the dentist
+	Specialties []Specialty `json:"Specialties"`
 }
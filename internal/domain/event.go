@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event types written to the outbox.
+const (
+	EventAppointmentCreated     = "appointment.created"
+	EventAppointmentRescheduled = "appointment.rescheduled"
+	EventAppointmentUpdated     = "appointment.updated"
+	EventAppointmentCancelled   = "appointment.cancelled"
+	EventAppointmentCheckedIn   = "appointment.checked_in"
+	EventAppointmentCompleted   = "appointment.completed"
+	EventPatientCreated         = "patient.created"
+	EventPatientUpdated         = "patient.updated"
+	EventPatientDeleted         = "patient.deleted"
+	EventDentistCreated         = "dentist.created"
+	EventDentistUpdated         = "dentist.updated"
+	EventDentistDeleted         = "dentist.deleted"
+)
+
+// EventTypes lists every event type that can be subscribed to.
+var EventTypes = []string{
+	EventAppointmentCreated, EventAppointmentRescheduled, EventAppointmentUpdated, EventAppointmentCancelled, EventAppointmentCheckedIn, EventAppointmentCompleted,
+	EventPatientCreated, EventPatientUpdated, EventPatientDeleted,
+	EventDentistCreated, EventDentistUpdated, EventDentistDeleted,
+}
+
+type Event struct {
+	// @Description The unique, increasing identifier of the event
+	// @Example 42
+	Id int64 `json:"Id"`
+	// @Description The tenant the event belongs to
+	TenantId int `json:"tenants_Id"`
+	// @Description The kind of entity that changed
+	// @Example "appointment"
+	AggregateType string `json:"AggregateType"`
+	// @Description The identifier of the entity that changed
+	// @Example 1
+	AggregateId int `json:"AggregateId"`
+	// @Description The event type
+	// @Example "appointment.created"
+	Type string `json:"Type"`
+	// @Description The event data, its shape depends on the type
+	Payload json.RawMessage `json:"Payload" swaggertype:"object"`
+	// @Description When the change happened, in UTC
+	// @Example "2024-03-28T15:04:05Z"
+	CreatedAt time.Time `json:"CreatedAt"`
+}
+
+// AppointmentEvent is the payload of the appointment events.
+type AppointmentEvent struct {
+	AppointmentId    int        `json:"AppointmentId"`
+	PatientId        int        `json:"PatientId"`
+	DentistId        int        `json:"DentistId"`
+	ClinicId         int        `json:"ClinicId"`
+	TreatmentId      int        `json:"TreatmentId"`
+	StartsAt         time.Time  `json:"StartsAt"`
+	PreviousStartsAt *time.Time `json:"PreviousStartsAt,omitempty"`
+	Description      string     `json:"Description"`
+}
+
+// NewAppointmentEvent builds the event payload of an appointment.
+func NewAppointmentEvent(appointment Appointment) AppointmentEvent {
+	return AppointmentEvent{
+		AppointmentId: appointment.Id,
+		PatientId:     appointment.Patient.Id,
+		DentistId:     appointment.Dentist.Id,
+		ClinicId:      appointment.Clinic.Id,
+		TreatmentId:   appointment.Treatment.Id,
+		StartsAt:      appointment.StartsAt.UTC(),
+		Description:   appointment.Description,
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// DentistFilter narrows dentist listings. Zero values are ignored.
+type DentistFilter struct {
+	Specialty string
+	ClinicId  int
+}
+
+// AppointmentFilter narrows appointment listings. Zero values are ignored.
+// Date is a local date in TimeZone (or the clinic time zone) that the service
+// turns into the From/To range of UTC instants used by the store.
+type AppointmentFilter struct {
+	ClinicId  int
+	DentistId int
+	PatientId int
+	Date      string
+	TimeZone  string
+	From      time.Time
+	To        time.Time
+}
+
+// ResourceFilter narrows resource listings. Zero values are ignored.
+type ResourceFilter struct {
+	Kind     string
+	ClinicId int
+}
+
+// EventFilter narrows the live appointment events. Zero values are ignored.
+// Date is a local date in TimeZone, or DefaultTimeZone, that the service
+// turns into the From/To range matched against the appointment start.
+type EventFilter struct {
+	DentistId int
+	Date      string
+	TimeZone  string
+	From      time.Time
+	To        time.Time
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Kinds of medical history entries.
+const (
+	HistoryAllergy    = "allergy"
+	HistoryMedication = "medication"
+	HistoryCondition  = "condition"
+)
+
+// Severities of a medical history entry.
+const (
+	SeverityMild     = "mild"
+	SeverityModerate = "moderate"
+	SeveritySevere   = "severe"
+)
+
+type HistoryEntry struct {
+	// @Description The unique identifier of the entry
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The patient the entry belongs to
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description The kind of entry (allergy, medication or condition)
+	// @Example "allergy"
+	Kind string `json:"Kind" binding:"required"`
+	// @Description What the patient is allergic to, takes or suffers from
+	// @Example "Penicillin"
+	Name string `json:"Name" binding:"required"`
+	// @Description How serious it is (mild, moderate or severe, optional)
+	// @Example "severe"
+	Severity string `json:"Severity"`
+	// @Description Whether the dentist must be warned before treating the patient. Allergies always warn
+	// @Example false
+	Alert bool `json:"Alert"`
+	// @Description Dosage, reactions or any other detail (optional)
+	// @Example "Anaphylaxis in 2019"
+	Notes string `json:"Notes"`
+	// @Description The date it started or was diagnosed (dd/MM/YYYY, optional)
+	// @Example "15/04/2019"
+	StartDate string `json:"StartDate"`
+	// @Description The date it ended, e.g. a finished medication (dd/MM/YYYY, optional)
+	// @Example ""
+	EndDate string `json:"EndDate"`
+	// @Description Who recorded the entry
+	// @Example "Dr. Daniel Rodríguez"
+	RecordedBy string `json:"RecordedBy" binding:"required"`
+	// @Description When the entry was recorded
+	RecordedAt time.Time `json:"RecordedAt"`
+	// @Description When the entry was last changed
+	UpdatedAt time.Time `json:"UpdatedAt"`
+}
+
+// ValidHistoryKind reports whether kind is one of the known entry kinds.
+func ValidHistoryKind(kind string) bool {
+	switch kind {
+	case HistoryAllergy, HistoryMedication, HistoryCondition:
+		return true
+	}
+	return false
+}
+
+// ValidSeverity reports whether severity is empty or one of the known ones.
+func ValidSeverity(severity string) bool {
+	switch severity {
+	case "", SeverityMild, SeverityModerate, SeveritySevere:
+		return true
+	}
+	return false
+}
+
+// Active reports whether the entry has not ended by the given day.
+func (h HistoryEntry) Active(now time.Time) bool {
+	if h.EndDate == "" {
+		return true
+	}
+	end, err := time.ParseInLocation(DateLayout, h.EndDate, now.Location())
+	if err != nil {
+		return true
+	}
+	return !end.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+}
+
+// Warns reports whether the entry must be shown as an alert before treating
+// the patient: active allergies, severe entries and entries flagged as alerts.
+func (h HistoryEntry) Warns(now time.Time) bool {
+	return h.Active(now) && (h.Kind == HistoryAllergy || h.Severity == SeveritySevere || h.Alert)
+}
+
+// Summary describes the entry in a few words, e.g. "Allergy: Penicillin
+// (severe)".
+func (h HistoryEntry) Summary() string {
+	summary := strings.ToUpper(h.Kind[:1]) + h.Kind[1:] + ": " + h.Name
+	if h.Severity != "" {
+		summary += " (" + h.Severity + ")"
+	}
+	return summary
+}
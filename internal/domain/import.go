@@ -0,0 +1,91 @@
+package domain
+
+// Outcomes of an imported item.
+const (
+	ImportImported = "imported"
+	ImportSkipped  = "skipped"
+	ImportConflict = "conflict"
+	ImportInvalid  = "invalid"
+)
+
+// Formats accepted by the bulk imports.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// BulkImportOptions controls a bulk import of patients or dentists.
+type BulkImportOptions struct {
+	// Format is FormatCSV or FormatJSONL.
+	Format string
+	// Mapping maps a field name to the column (or JSON key) holding it.
+	// Unmapped fields are read from the column with the same name, ignoring
+	// case.
+	Mapping map[string]string
+	// DryRun validates every row without creating anything.
+	DryRun bool
+	// Atomic creates every row or, if any row is invalid, none of them.
+	Atomic bool
+}
+
+// ImportReport summarizes an import of existing bookings, patients or
+// dentists.
+type ImportReport struct {
+	// @Description Whether nothing was written; imported items are the ones that would have been created
+	// @Example false
+	DryRun bool `json:"DryRun"`
+	// @Description How many appointments, patients or dentists were created
+	// @Example 12
+	Imported int `json:"Imported"`
+	// @Description How many items were ignored, e.g. cancelled, already booked or duplicated
+	// @Example 2
+	Skipped int `json:"Skipped"`
+	// @Description How many items failed the booking checks
+	// @Example 1
+	Conflicts int `json:"Conflicts"`
+	// @Description How many rows failed validation
+	// @Example 1
+	Invalid int `json:"Invalid"`
+	// @Description How many placeholder patients were created for unknown attendees
+	// @Example 3
+	CreatedPatients int `json:"CreatedPatients"`
+	// @Description The outcome of every item, in the order they were read
+	Items []ImportItem `json:"Items"`
+}
+
+// ImportItem is the outcome of one imported event or row.
+type ImportItem struct {
+	// @Description The event UID or the line the item comes from
+	// @Example "line 4"
+	Ref string `json:"Ref"`
+	// @Description The outcome: imported, skipped, conflict or invalid
+	// @Example "invalid"
+	Status string `json:"Status"`
+	// @Description Why the item was skipped or could not be booked
+	// @Example "Dentist does not work at the requested date and time"
+	Reason string `json:"Reason,omitempty"`
+	// @Description The local date of the appointment (dd/MM/YYYY)
+	// @Example "30/03/2024"
+	Date string `json:"Date,omitempty"`
+	// @Description The local time of the appointment
+	// @Example "09:00"
+	Hour string `json:"Hour,omitempty"`
+	// @Description The matched or created patient
+	// @Example 4
+	PatientId int `json:"PatientId,omitempty"`
+}
+
+// Add records the outcome of an item and updates the counters.
+func (r *ImportReport) Add(item ImportItem) {
+	switch item.Status {
+	case ImportImported:
+		r.Imported++
+	case ImportSkipped:
+		r.Skipped++
+	case ImportConflict:
+		r.Conflicts++
+	case ImportInvalid:
+		r.Invalid++
+	}
+	r.Items = append(r.Items, item)
+}
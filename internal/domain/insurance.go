@@ -0,0 +1,189 @@
+package domain
+
+import "time"
+
+// Statuses of an insurance claim. Claims are submitted to the insurer, which
+// accepts or rejects them, and submitted or accepted claims are paid.
+const (
+	ClaimSubmitted = "submitted"
+	ClaimAccepted  = "accepted"
+	ClaimRejected  = "rejected"
+	ClaimPaid      = "paid"
+)
+
+type InsuranceProvider struct {
+	// @Description The unique identifier of the provider
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The name of the obra social or prepaid plan company
+	// @Example "OSDE"
+	Name string `json:"Name" binding:"required"`
+	// @Description The code of the provider, e.g. its RNOS number (optional)
+	// @Example "4-0080-0"
+	Code string `json:"Code"`
+	// @Description The phone for authorizations (optional)
+	// @Example "0810-555-6733"
+	Phone string `json:"Phone"`
+	// @Description The email claims are sent to (optional)
+	// @Example "prestadores@osde.com.ar"
+	Email string `json:"Email"`
+	// @Description Whether the practice still accepts the provider. Invoices of inactive providers are not covered
+	// @Example true
+	Active bool `json:"Active"`
+	// @Description What the provider covers of each treatment
+	Rules []CoverageRule `json:"Rules"`
+}
+
+type CoverageRule struct {
+	// @Description The unique identifier of the rule
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The provider covering the treatment
+	// @Example 1
+	ProviderId int `json:"insurance_providers_Id"`
+	// @Description The plan the rule applies to, empty for every plan of the provider. Rules of the plan win
+	// @Example "310"
+	Plan string `json:"Plan"`
+	// @Description The treatment covered
+	// @Example 1
+	TreatmentId int `json:"treatments_Id" binding:"required"`
+	// @Description The share of the price the provider pays, in basis points (8000 = 80%)
+	// @Example 8000
+	Rate int `json:"Rate"`
+	// @Description The most the provider pays per unit, in cents, 0 for no limit
+	// @Example 3000000
+	MaxAmount int64 `json:"MaxAmount"`
+}
+
+// Cover returns the share of the line the rule pays, rounded half up to the
+// cent and capped by MaxAmount per unit.
+func (r CoverageRule) Cover(line InvoiceLine) int64 {
+	covered := (line.Total*int64(r.Rate) + 5000) / 10000
+	if r.MaxAmount > 0 && covered > r.MaxAmount*int64(line.Quantity) {
+		covered = r.MaxAmount * int64(line.Quantity)
+	}
+	return covered
+}
+
+// RuleFor returns the rule covering the treatment for the plan: the one of
+// the plan or else the one for every plan.
+func RuleFor(rules []CoverageRule, plan string, treatmentID int) (CoverageRule, bool) {
+	var found CoverageRule
+	ok := false
+	for _, rule := range rules {
+		if rule.TreatmentId != treatmentID {
+			continue
+		}
+		if rule.Plan == plan {
+			return rule, true
+		}
+		if rule.Plan == "" {
+			found, ok = rule, true
+		}
+	}
+	return found, ok
+}
+
+type Coverage struct {
+	// @Description The unique identifier of the coverage
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The patient covered
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description The provider
+	// @Example 1
+	ProviderId int `json:"insurance_providers_Id" binding:"required"`
+	// @Description The name of the provider
+	// @Example "OSDE"
+	ProviderName string `json:"ProviderName"`
+	// @Description The plan of the patient (optional)
+	// @Example "310"
+	Plan string `json:"Plan"`
+	// @Description The member number on the card of the patient
+	// @Example "61 234567 8 01"
+	MemberNumber string `json:"MemberNumber" binding:"required"`
+	// @Description The first day covered (dd/MM/yyyy)
+	// @Example "01/01/2024"
+	ValidFrom string `json:"ValidFrom" binding:"required"`
+	// @Description The last day covered (dd/MM/yyyy), empty while it does not end
+	// @Example "31/12/2024"
+	ValidTo string `json:"ValidTo"`
+}
+
+// ValidOn reports whether the coverage includes the calendar day of t in
+// the location of t.
+func (c Coverage) ValidOn(t time.Time) bool {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	from, err := time.Parse(DateLayout, c.ValidFrom)
+	if err != nil || day.Before(from) {
+		return false
+	}
+	if c.ValidTo == "" {
+		return true
+	}
+	to, err := time.Parse(DateLayout, c.ValidTo)
+	return err == nil && !day.After(to)
+}
+
+// Overlaps reports whether both coverages include a common day.
+func (c Coverage) Overlaps(other Coverage) bool {
+	from, _ := time.Parse(DateLayout, c.ValidFrom)
+	otherFrom, _ := time.Parse(DateLayout, other.ValidFrom)
+	if c.ValidTo != "" {
+		if to, err := time.Parse(DateLayout, c.ValidTo); err == nil && to.Before(otherFrom) {
+			return false
+		}
+	}
+	if other.ValidTo != "" {
+		if otherTo, err := time.Parse(DateLayout, other.ValidTo); err == nil && otherTo.Before(from) {
+			return false
+		}
+	}
+	return true
+}
+
+type Claim struct {
+	// @Description The unique identifier of the claim
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The invoice claimed
+	// @Example 1
+	InvoiceId int `json:"invoices_Id"`
+	// @Description The coverage the invoice was split with
+	// @Example 1
+	CoverageId int `json:"patient_coverages_Id"`
+	// @Description The provider the claim is submitted to
+	// @Example 1
+	ProviderId int `json:"insurance_providers_Id"`
+	// @Description The patient of the invoice
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description The share of the invoice claimed, in cents
+	// @Example 3600000
+	Amount int64 `json:"Amount"`
+	// @Description submitted, accepted, rejected or paid
+	// @Example "submitted"
+	Status string `json:"Status"`
+	// @Description The number the provider gave the claim or its authorization (optional)
+	// @Example "AUT-2024-000123"
+	Reference string `json:"Reference"`
+	// @Description Why the claim was rejected
+	// @Example ""
+	Reason string `json:"Reason"`
+	// @Description What the provider paid, in cents
+	// @Example 0
+	PaidAmount int64 `json:"PaidAmount"`
+	// @Description When the claim was submitted
+	SubmittedAt time.Time `json:"SubmittedAt"`
+	// @Description When the provider accepted or rejected the claim
+	ResolvedAt *time.Time `json:"ResolvedAt"`
+	// @Description When the provider paid the claim
+	PaidAt *time.Time `json:"PaidAt"`
+}
+
+// Open reports whether the claim still stands, so the invoice can not be
+// claimed again.
+func (c Claim) Open() bool {
+	return c.Status != ClaimRejected
+}
@@ -0,0 +1,168 @@
+package domain
+
+import "time"
+
+// Statuses of an invoice. Drafts are issued, which numbers them, and issued
+// invoices are paid once their payments cover the total; drafts and issued
+// invoices without payments can be voided.
+const (
+	InvoiceDraft  = "draft"
+	InvoiceIssued = "issued"
+	InvoicePaid   = "paid"
+	InvoiceVoid   = "void"
+)
+
+type Invoice struct {
+	// @Description The unique identifier of the invoice
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The number of the invoice, consecutive per practice and assigned when issued. 0 while draft
+	// @Example 42
+	Number int `json:"Number"`
+	// @Description The patient billed
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description draft, issued, paid or void
+	// @Example "issued"
+	Status string `json:"Status"`
+	// @Description The items billed
+	Lines []InvoiceLine `json:"Lines" binding:"dive"`
+	// @Description The sum of the lines before taxes, in cents
+	// @Example 4500000
+	Subtotal int64 `json:"Subtotal"`
+	// @Description The taxes of the lines, in cents
+	// @Example 0
+	Tax int64 `json:"Tax"`
+	// @Description The amount to pay, in cents
+	// @Example 4500000
+	Total int64 `json:"Total"`
+	// @Description The coverage the invoice is split with, 0 when the patient pays it all
+	// @Example 1
+	CoverageId int `json:"patient_coverages_Id"`
+	// @Description The share of the insurer, claimed to it, in cents
+	// @Example 3600000
+	Covered int64 `json:"Covered"`
+	// @Description The share of the patient, Total minus Covered, in cents
+	// @Example 900000
+	PatientTotal int64 `json:"PatientTotal"`
+	// @Description What the patient paid, net of refunds, in cents
+	// @Example 2000000
+	AmountPaid int64 `json:"AmountPaid"`
+	// @Description What is left for the patient to pay of an issued invoice, in cents
+	// @Example 400000
+	AmountDue int64 `json:"AmountDue"`
+	// @Description Any note printed on the invoice (optional)
+	// @Example "Root canal, first stage"
+	Notes string `json:"Notes"`
+	// @Description Why the invoice was voided
+	// @Example ""
+	VoidReason string `json:"VoidReason"`
+	// @Description When the draft was created
+	CreatedAt time.Time `json:"CreatedAt"`
+	// @Description When the invoice was issued, empty while draft
+	IssuedAt *time.Time `json:"IssuedAt"`
+	// @Description When the payments of the patient covered their share
+	PaidAt *time.Time `json:"PaidAt"`
+	// @Description When the invoice was voided
+	VoidedAt *time.Time `json:"VoidedAt"`
+}
+
+type InvoiceLine struct {
+	// @Description The unique identifier of the line
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The invoice the line belongs to
+	// @Example 1
+	InvoiceId int `json:"invoices_Id"`
+	// @Description The completed appointment billed, 0 for lines added by hand
+	// @Example 1
+	AppointmentId int `json:"appointments_Id"`
+	// @Description The treatment billed (optional)
+	// @Example 1
+	TreatmentId int `json:"treatments_Id"`
+	// @Description What is billed
+	// @Example "Root canal (30/03/2024)"
+	Description string `json:"Description" binding:"required"`
+	// @Description How many units are billed (defaults to 1)
+	// @Example 1
+	Quantity int `json:"Quantity"`
+	// @Description The price of a unit in cents
+	// @Example 4500000
+	UnitPrice int64 `json:"UnitPrice"`
+	// @Description The tax rate in basis points (2100 = 21%)
+	// @Example 0
+	TaxRate int `json:"TaxRate"`
+	// @Description Quantity times UnitPrice, in cents
+	// @Example 4500000
+	Subtotal int64 `json:"Subtotal"`
+	// @Description The tax of the line, in cents
+	// @Example 0
+	Tax int64 `json:"Tax"`
+	// @Description Subtotal plus Tax, in cents
+	// @Example 4500000
+	Total int64 `json:"Total"`
+	// @Description The share of the insurer, in cents
+	// @Example 3600000
+	Covered int64 `json:"Covered"`
+}
+
+// Compute sets the amounts of the line. Taxes are rounded half up to the
+// cent, and the insurer never covers more than the total.
+func (l *InvoiceLine) Compute() {
+	l.Subtotal = int64(l.Quantity) * l.UnitPrice
+	l.Tax = (l.Subtotal*int64(l.TaxRate) + 5000) / 10000
+	l.Total = l.Subtotal + l.Tax
+	if l.Covered < 0 {
+		l.Covered = 0
+	}
+	if l.Covered > l.Total {
+		l.Covered = l.Total
+	}
+}
+
+// Compute sets the amounts of every line and the totals of the invoice.
+func (i *Invoice) Compute() {
+	i.Subtotal, i.Tax, i.Total, i.Covered = 0, 0, 0, 0
+	for j := range i.Lines {
+		i.Lines[j].Compute()
+		i.Subtotal += i.Lines[j].Subtotal
+		i.Tax += i.Lines[j].Tax
+		i.Total += i.Lines[j].Total
+		i.Covered += i.Lines[j].Covered
+	}
+	i.PatientTotal = i.Total - i.Covered
+}
+
+// Settle sets what the patient paid of the invoice and what is left to pay.
+// Only issued invoices are due.
+func (i *Invoice) Settle(paid int64) {
+	i.PatientTotal = i.Total - i.Covered
+	i.AmountPaid = paid
+	i.AmountDue = 0
+	if i.Status == InvoiceIssued && paid < i.PatientTotal {
+		i.AmountDue = i.PatientTotal - paid
+	}
+}
+
+type PatientBalance struct {
+	// @Description The patient
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description The total of the issued and paid invoices, in cents
+	// @Example 9000000
+	Invoiced int64 `json:"Invoiced"`
+	// @Description The share of the insurers in those invoices, in cents
+	// @Example 3600000
+	Covered int64 `json:"Covered"`
+	// @Description The payments of those invoices, net of refunds, in cents
+	// @Example 4500000
+	Paid int64 `json:"Paid"`
+	// @Description What the patient still owes, in cents
+	// @Example 4500000
+	Outstanding int64 `json:"Outstanding"`
+	// @Description The issued invoices not fully paid yet
+	OpenInvoices []Invoice `json:"OpenInvoices"`
+	// @Description The completed appointments with a treatment that were not invoiced yet
+	// @Example [12, 15]
+	UnbilledAppointments []int `json:"UnbilledAppointments"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+type ClinicalNote struct {
+	// @Description The unique identifier of the note
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The appointment the note was written for
+	// @Example 1
+	AppointmentId int `json:"appointments_Id"`
+	// @Description The patient seen in the appointment
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description The dentist who wrote the note, the one treating the patient
+	// @Example 1
+	DentistId int `json:"dentists_Id"`
+	// @Description The note this one amends, 0 for an original note
+	// @Example 0
+	AmendsId int `json:"AmendsId"`
+	// @Description What was found and done in the visit
+	// @Example "Composite filling on 36, occlusal. Patient tolerated well."
+	Text string `json:"Text" binding:"required"`
+	// @Description Why the note was amended (amendments only)
+	// @Example "Wrong tooth number"
+	Reason string `json:"Reason"`
+	// @Description When the note was written
+	CreatedAt time.Time `json:"CreatedAt"`
+	// @Description The amendments of the note, oldest first; the last one is the current text
+	Amendments []ClinicalNote `json:"Amendments,omitempty"`
+}
+
+// TimelineEntry is a visit of the clinical timeline of a patient.
+type TimelineEntry struct {
+	// @Description The appointment of the visit, rendered in the clinic time zone
+	Appointment Appointment `json:"Appointment"`
+	// @Description The clinical notes of the visit, oldest first, with their amendments
+	Notes []ClinicalNote `json:"Notes"`
+}
@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Conditions of a tooth or of its surfaces. Caries and fillings are recorded
+// on surfaces, crowns and extractions on the whole tooth. Healthy clears what
+// was recorded before, on the given surfaces or on the whole tooth.
+const (
+	ToothHealthy   = "healthy"
+	ToothCaries    = "caries"
+	ToothFilling   = "filling"
+	ToothCrown     = "crown"
+	ToothExtracted = "extracted"
+)
+
+// ToothSurfaces are the surfaces of a tooth: mesial, distal, occlusal (or
+// incisal), vestibular (buccal) and lingual (or palatal).
+const ToothSurfaces = "MDOVL"
+
+type ToothFinding struct {
+	// @Description The unique identifier of the finding
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The patient the finding belongs to
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description The appointment the finding was recorded in
+	// @Example 1
+	AppointmentId int `json:"appointments_Id"`
+	// @Description The dentist who recorded the finding
+	// @Example 1
+	DentistId int `json:"dentists_Id"`
+	// @Description The tooth, in FDI two-digit notation (11-48 permanent, 51-85 deciduous)
+	// @Example 36
+	Tooth int `json:"Tooth" binding:"required"`
+	// @Description The affected surfaces (M, D, O, V, L), empty for the whole tooth
+	// @Example "MO"
+	Surfaces string `json:"Surfaces"`
+	// @Description What was found (healthy, caries, filling, crown or extracted)
+	// @Example "caries"
+	Condition string `json:"Condition" binding:"required"`
+	// @Description Any detail about the finding (optional)
+	// @Example "Deep lesion, close to the pulp"
+	Notes string `json:"Notes"`
+	// @Description When the finding was made, the start of the appointment
+	ExaminedAt time.Time `json:"ExaminedAt"`
+}
+
+// ToothState is the state of a tooth in the odontogram.
+type ToothState struct {
+	// @Description The tooth, in FDI two-digit notation
+	// @Example 36
+	Tooth int `json:"Tooth"`
+	// @Description The condition of the whole tooth (crown or extracted), empty when it is recorded per surface
+	// @Example ""
+	Condition string `json:"Condition"`
+	// @Description The condition of each affected surface
+	Surfaces map[string]string `json:"Surfaces"`
+	// @Description When the tooth was last examined
+	UpdatedAt time.Time `json:"UpdatedAt"`
+}
+
+// Odontogram is the dental chart of a patient at a given time.
+type Odontogram struct {
+	// @Description The patient of the chart
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description The time the chart was built at; findings after it are not included
+	AsOf time.Time `json:"AsOf"`
+	// @Description The examined teeth, in FDI order
+	Teeth []ToothState `json:"Teeth"`
+}
+
+// ValidTooth reports whether tooth is a FDI tooth number, permanent (11-48)
+// or deciduous (51-85).
+func ValidTooth(tooth int) bool {
+	quadrant, position := tooth/10, tooth%10
+	switch {
+	case quadrant >= 1 && quadrant <= 4:
+		return position >= 1 && position <= 8
+	case quadrant >= 5 && quadrant <= 8:
+		return position >= 1 && position <= 5
+	}
+	return false
+}
+
+// ValidToothCondition reports whether condition is one of the known ones.
+func ValidToothCondition(condition string) bool {
+	switch condition {
+	case ToothHealthy, ToothCaries, ToothFilling, ToothCrown, ToothExtracted:
+		return true
+	}
+	return false
+}
+
+// NormalizeSurfaces upper-cases the surfaces, drops repeated ones and sorts
+// them in ToothSurfaces order. ok is false for unknown surfaces.
+func NormalizeSurfaces(surfaces string) (string, bool) {
+	surfaces = strings.ToUpper(surfaces)
+	for _, surface := range surfaces {
+		if !strings.ContainsRune(ToothSurfaces+" ,", surface) {
+			return "", false
+		}
+	}
+	var normalized strings.Builder
+	for _, surface := range ToothSurfaces {
+		if strings.ContainsRune(surfaces, surface) {
+			normalized.WriteRune(surface)
+		}
+	}
+	return normalized.String(), true
+}
+
+// Apply updates the tooth with a later finding.
+func (t *ToothState) Apply(finding ToothFinding) {
+	t.Tooth = finding.Tooth
+	t.UpdatedAt = finding.ExaminedAt
+	if t.Surfaces == nil {
+		t.Surfaces = map[string]string{}
+	}
+	switch {
+	case finding.Condition == ToothCrown || finding.Condition == ToothExtracted:
+		t.Condition = finding.Condition
+		t.Surfaces = map[string]string{}
+	case finding.Surfaces == "":
+		// Healthy whole tooth, e.g. a crown that was removed.
+		t.Condition = ""
+		t.Surfaces = map[string]string{}
+	default:
+		for _, surface := range finding.Surfaces {
+			if finding.Condition == ToothHealthy {
+				delete(t.Surfaces, string(surface))
+			} else {
+				t.Surfaces[string(surface)] = finding.Condition
+			}
+		}
+	}
+}
+
+// BuildOdontogram replays the findings, oldest first, into the chart of the
+// patient. Findings after asOf are ignored.
+func BuildOdontogram(patientID int, findings []ToothFinding, asOf time.Time) Odontogram {
+	teeth := map[int]*ToothState{}
+	for _, finding := range findings {
+		if finding.ExaminedAt.After(asOf) {
+			continue
+		}
+		if teeth[finding.Tooth] == nil {
+			teeth[finding.Tooth] = &ToothState{}
+		}
+		teeth[finding.Tooth].Apply(finding)
+	}
+
+	chart := Odontogram{PatientId: patientID, AsOf: asOf, Teeth: []ToothState{}}
+	for _, tooth := range teeth {
+		chart.Teeth = append(chart.Teeth, *tooth)
+	}
+	sort.Slice(chart.Teeth, func(i, j int) bool {
+		return chart.Teeth[i].Tooth < chart.Teeth[j].Tooth
+	})
+	return chart
+}
@@ -19,4 +19,10 @@ type Patient struct {
 	// @Description The release date of the patient (dd/MM/YYYY)
 	// @Example "30/03/2024"
 	ReleaseDate string `json:"ReleaseDate" binding:"required"`
+	// @Description The email address used for appointment reminders (optional)
+	// @Example "daniel@example.com"
+	Email string `json:"Email"`
+	// @Description The mobile phone used for SMS reminders in international format (optional)
+	// @Example "+5491155550000"
+	Phone string `json:"Phone"`
 }
@@ -0,0 +1,145 @@
+package domain
+
+import "time"
+
+// Methods of a payment.
+const (
+	PaymentCash     = "cash"
+	PaymentCard     = "card"
+	PaymentTransfer = "transfer"
+)
+
+// PaymentMethods lists the methods in the order cash closes report them.
+var PaymentMethods = []string{PaymentCash, PaymentCard, PaymentTransfer}
+
+// Kinds of ledger entries. Refunds give back part or all of a payment and
+// subtract from what was paid.
+const (
+	PaymentKindPayment = "payment"
+	PaymentKindRefund  = "refund"
+)
+
+// Statuses of a ledger entry. Card payments and refunds are recorded as
+// pending before the provider is asked to move the money, which reserves
+// their amount, and are completed or voided with the answer; every other
+// entry is completed when recorded. Only completed entries count as paid or
+// refunded.
+const (
+	PaymentCompleted = "completed"
+	PaymentPending   = "pending"
+	PaymentVoided    = "voided"
+)
+
+type Payment struct {
+	// @Description The unique identifier of the entry
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The patient who paid
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description The invoice paid
+	// @Example 1
+	InvoiceId int `json:"invoices_Id"`
+	// @Description The clinic where the money was received or given back
+	// @Example 1
+	ClinicId int `json:"clinics_Id"`
+	// @Description payment or refund
+	// @Example "payment"
+	Kind string `json:"Kind"`
+	// @Description cash, card or transfer
+	// @Example "card"
+	Method string `json:"Method"`
+	// @Description The amount received or given back, in cents
+	// @Example 2000000
+	Amount int64 `json:"Amount"`
+	// @Description The provider that processed a card payment
+	// @Example "terminal"
+	Provider string `json:"Provider"`
+	// @Description The voucher, transaction or transfer number
+	// @Example "000123"
+	Reference string `json:"Reference"`
+	// @Description The payment a refund gives back, 0 for payments
+	// @Example 0
+	RefundOf int `json:"RefundOf"`
+	// @Description Any note on the entry (optional)
+	// @Example "First installment"
+	Notes string `json:"Notes"`
+	// @Description When the money was received or given back
+	ReceivedAt time.Time `json:"ReceivedAt"`
+	// @Description completed, or for card payments and refunds pending while the provider moves the money and voided when it could not
+	// @Example "completed"
+	Status string `json:"Status"`
+	// The card token for the provider, never stored.
+	Token string `json:"-"`
+}
+
+// Signed returns the amount with the sign it adds to what was paid.
+func (p Payment) Signed() int64 {
+	if p.Kind == PaymentKindRefund {
+		return -p.Amount
+	}
+	return p.Amount
+}
+
+type CashCloseTotal struct {
+	// @Description cash, card or transfer
+	// @Example "cash"
+	Method string `json:"Method"`
+	// @Description How many entries were recorded
+	// @Example 3
+	Count int `json:"Count"`
+	// @Description The payments received, in cents
+	// @Example 6000000
+	Payments int64 `json:"Payments"`
+	// @Description The refunds given back, in cents
+	// @Example 1000000
+	Refunds int64 `json:"Refunds"`
+	// @Description Payments minus refunds, in cents
+	// @Example 5000000
+	Net int64 `json:"Net"`
+}
+
+type CashClose struct {
+	// @Description The clinic
+	// @Example 1
+	ClinicId int `json:"clinics_Id"`
+	// @Description The day closed, in the time zone of the clinic (dd/MM/yyyy)
+	// @Example "30/03/2024"
+	Date string `json:"Date"`
+	// @Description The time zone of the clinic
+	// @Example "America/Argentina/Buenos_Aires"
+	TimeZone string `json:"TimeZone"`
+	// @Description The totals of every method
+	Totals []CashCloseTotal `json:"Totals"`
+	// @Description What the clinic took in, net of refunds, in cents
+	// @Example 5000000
+	Net int64 `json:"Net"`
+	// @Description The entries of the day, in the order they were recorded
+	Payments []Payment `json:"Payments"`
+}
+
+// NewCashClose sums the entries of a day by method.
+func NewCashClose(clinic Clinic, date string, payments []Payment) CashClose {
+	cashClose := CashClose{ClinicId: clinic.Id, Date: date, TimeZone: clinic.TimeZone, Payments: payments}
+	if cashClose.TimeZone == "" {
+		cashClose.TimeZone = DefaultTimeZone
+	}
+	index := make(map[string]int, len(PaymentMethods))
+	cashClose.Totals = make([]CashCloseTotal, len(PaymentMethods))
+	for i, method := range PaymentMethods {
+		index[method] = i
+		cashClose.Totals[i].Method = method
+	}
+	for _, payment := range payments {
+		total := &cashClose.Totals[index[payment.Method]]
+		total.Count++
+		if payment.Kind == PaymentKindRefund {
+			total.Refunds += payment.Amount
+		} else {
+			total.Payments += payment.Amount
+		}
+		total.Net += payment.Signed()
+		cashClose.Net += payment.Signed()
+	}
+	return cashClose
+}
@@ -0,0 +1,132 @@
+package domain
+
+import "time"
+
+// Statuses of a treatment plan. Completed is derived from its steps.
+const (
+	PlanActive    = "active"
+	PlanCompleted = "completed"
+	PlanCancelled = "cancelled"
+)
+
+// Statuses of a plan step, derived from its appointments.
+const (
+	StepPending   = "pending"
+	StepScheduled = "scheduled"
+	StepCompleted = "completed"
+	StepSkipped   = "skipped"
+)
+
+type TreatmentPlan struct {
+	// @Description The unique identifier of the plan
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The patient the plan is for
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description A short name for the plan
+	// @Example "Implant on 46"
+	Title string `json:"Title" binding:"required"`
+	// @Description Any detail about the plan (optional)
+	// @Example "Bone graft may be needed"
+	Notes string `json:"Notes"`
+	// @Description The status of the plan (active, completed or cancelled)
+	// @Example "active"
+	Status string `json:"Status"`
+	// @Description The ordered steps of the plan
+	Steps []PlanStep `json:"Steps" binding:"required,dive"`
+	// @Description The estimated cost of the steps not skipped, in cents
+	// @Example 150000
+	EstimatedTotal int64 `json:"EstimatedTotal"`
+	// @Description The number of completed steps
+	// @Example 1
+	CompletedSteps int `json:"CompletedSteps"`
+	// @Description The percentage of the steps not skipped that are completed
+	// @Example 33
+	Progress int `json:"Progress"`
+	// @Description When the plan was created
+	CreatedAt time.Time `json:"CreatedAt"`
+}
+
+type PlanStep struct {
+	// @Description The unique identifier of the step
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The plan the step belongs to
+	// @Example 1
+	PlanId int `json:"treatment_plans_Id"`
+	// @Description The position of the step in the plan, starting at 1
+	// @Example 1
+	Position int `json:"Position"`
+	// @Description The treatment performed in the step (optional)
+	Treatment Treatment `json:"treatments_Id"`
+	// @Description What is done in the step
+	// @Example "Implant placement"
+	Description string `json:"Description" binding:"required"`
+	// @Description The estimated cost of the step, in cents
+	// @Example 90000
+	EstimatedCost int64 `json:"EstimatedCost"`
+	// @Description Whether the step was dropped from the plan
+	// @Example false
+	Skipped bool `json:"Skipped"`
+	// @Description The status of the step (pending, scheduled, completed or skipped)
+	// @Example "scheduled"
+	Status string `json:"Status"`
+	// @Description The appointments booked for the step
+	Appointments []StepAppointment `json:"Appointments"`
+}
+
+// StepAppointment is an appointment booked for a plan step.
+type StepAppointment struct {
+	// @Description The appointment
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The start of the appointment in UTC
+	StartsAt time.Time `json:"StartsAt"`
+	// @Description When the appointment was completed, empty if it was not
+	CompletedAt *time.Time `json:"CompletedAt"`
+}
+
+// Track derives the status of the steps and the progress of the plan. A
+// step is completed once one of its appointments is, and the plan once all
+// the steps not skipped are.
+func (p *TreatmentPlan) Track() {
+	p.EstimatedTotal, p.CompletedSteps, p.Progress = 0, 0, 0
+	planned := 0
+	for i := range p.Steps {
+		step := &p.Steps[i]
+		step.Status = StepPending
+		for _, appointment := range step.Appointments {
+			if appointment.CompletedAt != nil {
+				step.Status = StepCompleted
+				break
+			}
+			step.Status = StepScheduled
+		}
+		if step.Skipped && step.Status != StepCompleted {
+			step.Status = StepSkipped
+			continue
+		}
+		planned++
+		p.EstimatedTotal += step.EstimatedCost
+		if step.Status == StepCompleted {
+			p.CompletedSteps++
+		}
+	}
+	if planned > 0 {
+		p.Progress = p.CompletedSteps * 100 / planned
+	}
+	if p.Status == PlanActive && p.CompletedSteps > 0 && p.CompletedSteps == planned {
+		p.Status = PlanCompleted
+	}
+}
+
+// Step returns the step of the plan with the given id.
+func (p TreatmentPlan) Step(id int) (PlanStep, bool) {
+	for _, step := range p.Steps {
+		if step.Id == id {
+			return step, true
+		}
+	}
+	return PlanStep{}, false
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Statuses of a prescription.
+const (
+	PrescriptionIssued = "issued"
+	PrescriptionVoided = "voided"
+)
+
+type Prescription struct {
+	// @Description The unique identifier of the prescription
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The appointment the prescription was issued at
+	// @Example 1
+	AppointmentId int `json:"appointments_Id"`
+	// @Description The patient the prescription is for
+	// @Example 1
+	PatientId int `json:"patients_Id"`
+	// @Description The full name of the patient
+	// @Example "Juan Perez"
+	PatientName string `json:"PatientName"`
+	// @Description The dentist who issued the prescription
+	// @Example 1
+	DentistId int `json:"dentists_Id"`
+	// @Description The full name of the dentist, as it was when issued
+	// @Example "Daniel Rodríguez"
+	DentistName string `json:"DentistName"`
+	// @Description The license of the dentist, as it was when issued
+	// @Example "AXMER"
+	License string `json:"License"`
+	// @Description What the medication is prescribed for (optional)
+	// @Example "Acute apical abscess on 36"
+	Diagnosis string `json:"Diagnosis"`
+	// @Description The medications prescribed
+	Items []PrescriptionItem `json:"Items" binding:"required,min=1,dive"`
+	// @Description Any indication for the patient or the pharmacy (optional)
+	// @Example "Generic substitution allowed"
+	Notes string `json:"Notes"`
+	// @Description The status of the prescription (issued or voided)
+	// @Example "issued"
+	Status string `json:"Status"`
+	// @Description The code printed on the prescription to check it is genuine
+	// @Example "K7QM-2XHP-9TRD"
+	Code string `json:"Code"`
+	// @Description When the prescription was issued
+	IssuedAt time.Time `json:"IssuedAt"`
+	// @Description Why the prescription was voided
+	// @Example ""
+	VoidReason string `json:"VoidReason"`
+	// @Description When the prescription was voided
+	VoidedAt *time.Time `json:"VoidedAt"`
+}
+
+type PrescriptionItem struct {
+	// @Description The unique identifier of the item
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The prescription the item belongs to
+	// @Example 1
+	PrescriptionId int `json:"prescriptions_Id"`
+	// @Description The drug, with its strength and presentation
+	// @Example "Amoxicillin 500 mg tablets"
+	Medication string `json:"Medication" binding:"required"`
+	// @Description How much to take each time
+	// @Example "1 tablet every 8 hours"
+	Dose string `json:"Dose" binding:"required"`
+	// @Description The number of units or boxes to dispense (optional, defaults to 1)
+	// @Example 1
+	Quantity int `json:"Quantity"`
+	// @Description How and for how long to take it (optional)
+	// @Example "Take with food for 7 days"
+	Instructions string `json:"Instructions"`
+}
+
+// PrescriptionCheck is what anyone holding a prescription, such as a
+// pharmacy, learns by checking its code.
+type PrescriptionCheck struct {
+	// @Description The code checked
+	// @Example "K7QM-2XHP-9TRD"
+	Code string `json:"Code"`
+	// @Description Whether the prescription can be dispensed
+	// @Example true
+	Valid bool `json:"Valid"`
+	// @Description The status of the prescription (issued or voided)
+	// @Example "issued"
+	Status string `json:"Status"`
+	// @Description The initials of the patient
+	// @Example "J. P."
+	Patient string `json:"Patient"`
+	// @Description The full name of the dentist who issued it
+	// @Example "Daniel Rodríguez"
+	DentistName string `json:"DentistName"`
+	// @Description The license of the dentist who issued it
+	// @Example "AXMER"
+	License string `json:"License"`
+	// @Description The medications prescribed
+	Items []PrescriptionItem `json:"Items"`
+	// @Description When the prescription was issued
+	IssuedAt time.Time `json:"IssuedAt"`
+	// @Description When the prescription was voided
+	VoidedAt *time.Time `json:"VoidedAt"`
+}
+
+// Check returns what the code of the prescription tells about it, without
+// the details of the patient.
+func (p Prescription) Check() PrescriptionCheck {
+	var initials []string
+	for _, name := range strings.Fields(p.PatientName) {
+		initials = append(initials, string([]rune(name)[:1])+".")
+	}
+	return PrescriptionCheck{
+		Code:        p.Code,
+		Valid:       p.Status == PrescriptionIssued,
+		Status:      p.Status,
+		Patient:     strings.Join(initials, " "),
+		DentistName: p.DentistName,
+		License:     p.License,
+		Items:       p.Items,
+		IssuedAt:    p.IssuedAt,
+		VoidedAt:    p.VoidedAt,
+	}
+}
+
+// NormalizeCode puts a verification code as typed by a person in the form it
+// is printed: upper case, in groups of four separated by dashes. Characters
+// other than letters and digits are dropped. No character is mapped to
+// another, so a code with a misread character does not match.
+func NormalizeCode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			if b.Len() > 0 && (b.Len()+1)%5 == 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package domain
+
+// Kinds of bookable resources.
+const (
+	ResourceChair     = "chair"
+	ResourceRoom      = "room"
+	ResourceEquipment = "equipment"
+)
+
+type Resource struct {
+	// @Description The unique identifier of the resource
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The name of the resource
+	// @Example "Chair 1"
+	Name string `json:"Name" binding:"required"`
+	// @Description The kind of resource (chair, room or equipment)
+	// @Example "chair"
+	Kind string `json:"Kind" binding:"required"`
+	// @Description The clinic the resource belongs to
+	Clinic Clinic `json:"clinics_Id"`
+}
+
+// ValidResourceKind reports whether kind is one of the known resource kinds.
+func ValidResourceKind(kind string) bool {
+	switch kind {
+	case ResourceChair, ResourceRoom, ResourceEquipment:
+		return true
+	}
+	return false
+}
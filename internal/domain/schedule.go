@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// SlotDuration is the length of a bookable appointment slot.
+const SlotDuration = time.Hour
+
+// Date and hour layouts used by appointments and schedules.
+const (
+	DateLayout = "02/01/2006"
+	HourLayout = "15:04"
+)
+
+// Periods of a printed dentist schedule. A week starts on Monday.
+const (
+	PeriodDay  = "day"
+	PeriodWeek = "week"
+)
+
+type Schedule struct {
+	// @Description The unique identifier of the schedule
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The dentist working in this schedule
+	DentistId int `json:"dentists_Id"`
+	// @Description The clinic where the dentist works in this schedule
+	Clinic Clinic `json:"clinics_Id" binding:"required"`
+	// @Description The day of the week (0 = Sunday ... 6 = Saturday)
+	// @Example 1
+	Weekday int `json:"Weekday"`
+	// @Description The start of the schedule in 24-hour format
+	// @Example "09:00"
+	StartHour string `json:"StartHour" binding:"required"`
+	// @Description The end of the schedule in 24-hour format
+	// @Example "13:00"
+	EndHour string `json:"EndHour" binding:"required"`
+}
+
+// ParseLocal converts a date (dd/MM/yyyy) and hour (HH:MM) given in loc into
+// a UTC instant. Hours skipped by a daylight saving change do not exist and
+// are rejected.
+func ParseLocal(date string, hour string, loc *time.Location) (time.Time, error) {
+	t, err := time.ParseInLocation(DateLayout+" "+HourLayout, date+" "+hour, loc)
+	if err != nil {
+		return time.Time{}, errors.New("Invalid date or hour, expected dd/MM/yyyy and HH:MM")
+	}
+	if t.Format(HourLayout) != hour {
+		return time.Time{}, errors.New("The hour " + hour + " does not exist on " + date + " in " + loc.String())
+	}
+	return t.UTC(), nil
+}
+
+// SlotsOverlap reports whether the slots starting at a and b share any time.
+func SlotsOverlap(a time.Time, b time.Time) bool {
+	return a.Before(b.Add(SlotDuration)) && b.Before(a.Add(SlotDuration))
+}
+
+// CoversSlot reports whether the whole slot starting at start, given in the
+// local time of the clinic, falls within the schedule: it starts on the
+// weekday no earlier than StartHour and ends by EndHour of the same day.
+func (s Schedule) CoversSlot(start time.Time) bool {
+	end := start.Add(SlotDuration)
+	if s.Weekday != int(start.Weekday()) || end.YearDay() != start.YearDay() {
+		return false
+	}
+	return start.Format(HourLayout) >= s.StartHour && end.Format(HourLayout) <= s.EndHour
+}
+
+// Overlaps reports whether both schedules share part of the same weekday.
+func (s Schedule) Overlaps(other Schedule) bool {
+	return s.Weekday == other.Weekday && s.StartHour < other.EndHour && other.StartHour < s.EndHour
+}
+
+type Slot struct {
+	// @Description The clinic where the slot is available
+	Clinic Clinic `json:"clinics_Id"`
+	// @Description The local date of the slot (dd/MM/YYYY)
+	// @Example "30/03/2024"
+	Date string `json:"Date"`
+	// @Description The local time of the slot in 24-hour format
+	// @Example "09:00"
+	Hour string `json:"Hour"`
+	// @Description The start of the slot in UTC
+	// @Example "2024-03-30T12:00:00Z"
+	StartsAt time.Time `json:"StartsAt"`
+	// @Description The time zone Date and Hour are expressed in
+	// @Example "America/Argentina/Buenos_Aires"
+	TimeZone string `json:"TimeZone"`
+}
+
+// Localize sets Date, Hour and TimeZone from StartsAt in the given location.
+func (s *Slot) Localize(loc *time.Location) {
+	local := s.StartsAt.In(loc)
+	s.Date = local.Format(DateLayout)
+	s.Hour = local.Format(HourLayout)
+	s.TimeZone = loc.String()
+}
@@ -0,0 +1,10 @@
+package domain
+
+type Specialty struct {
+	// @Description The unique identifier of the specialty
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The name of the specialty
+	// @Example "orthodontics"
+	Name string `json:"Name" binding:"required"`
+}
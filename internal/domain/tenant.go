@@ -0,0 +1,26 @@
+package domain
+
+type Tenant struct {
+	// @Description The unique identifier of the tenant (dental practice)
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The name of the dental practice
+	// @Example "Sonrisas Dental"
+	Name string `json:"Name" binding:"required"`
+	// @Description The date the tenant was provisioned
+	// @Example "2024-01-02T15:04:05Z"
+	CreatedAt string `json:"CreatedAt"`
+	// @Description When the tenant was disabled, empty while active. A disabled tenant keeps its records but its token no longer works
+	// @Example ""
+	DisabledAt string `json:"DisabledAt"`
+}
+
+// TenantCredentials is returned only when a tenant is provisioned or its token
+// is rotated; the plain token is never stored.
+type TenantCredentials struct {
+	// @Description The tenant the token belongs to
+	Tenant Tenant `json:"Tenant"`
+	// @Description The token to send in the TOKEN header
+	// @Example "3f0c8e4a..."
+	Token string `json:"Token"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+type Treatment struct {
+	// @Description The unique identifier of the treatment
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The name of the treatment
+	// @Example "Root canal"
+	Name string `json:"Name" binding:"required"`
+	// @Description The specialty a dentist must hold to perform the treatment (empty if any dentist can)
+	Specialty Specialty `json:"specialties_Id"`
+	// @Description The price of the treatment in cents, billed when an appointment for it is completed
+	// @Example 4500000
+	Price int64 `json:"Price"`
+	// @Description The tax rate applied to the price, in basis points (2100 = 21%)
+	// @Example 0
+	TaxRate int `json:"TaxRate"`
+}
@@ -0,0 +1,87 @@
+package domain
+
+import "time"
+
+// Delivery statuses of a webhook delivery.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	// @Description The unique identifier of the webhook
+	// @Example 1
+	Id int `json:"Id"`
+	// @Description The URL the events are posted to
+	// @Example "https://example.com/hooks/dental"
+	URL string `json:"URL" binding:"required"`
+	// @Description The secret used to sign the deliveries, generated when empty and only returned on creation
+	// @Example "whsec_3f0c8e4a..."
+	Secret string `json:"Secret,omitempty"`
+	// @Description The event types delivered to the webhook, "*" subscribes to every event
+	// @Example ["appointment.created","appointment.cancelled"]
+	EventTypes []string `json:"EventTypes" binding:"required"`
+	// @Description Whether events are delivered to the webhook
+	// @Example true
+	Active bool `json:"Active"`
+	// @Description When the webhook was created, in UTC
+	// @Example "2024-03-28T15:04:05Z"
+	CreatedAt time.Time `json:"CreatedAt"`
+}
+
+// Subscribes reports whether the webhook receives events of the given type.
+func (w Webhook) Subscribes(eventType string) bool {
+	for _, t := range w.EventTypes {
+		if t == "*" || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event sent, or to be sent, to one webhook.
+type WebhookDelivery struct {
+	// @Description The unique identifier of the delivery, sent in the X-Webhook-Delivery header
+	// @Example 7
+	Id int64 `json:"Id"`
+	// @Description The tenant the delivery belongs to
+	TenantId int `json:"tenants_Id"`
+	// @Description The webhook the event is delivered to
+	// @Example 1
+	WebhookId int `json:"WebhookId"`
+	// @Description The delivered event
+	Event Event `json:"Event"`
+	// @Description The status of the delivery: pending, delivered or failed
+	// @Example "delivered"
+	Status string `json:"Status"`
+	// @Description How many times the delivery was attempted
+	// @Example 1
+	Attempts int `json:"Attempts"`
+	// @Description When the delivery is attempted next, in UTC
+	NextAttemptAt time.Time `json:"NextAttemptAt"`
+	// @Description The HTTP status code of the last attempt, 0 if no response was received
+	// @Example 200
+	LastStatusCode int `json:"LastStatusCode"`
+	// @Description The error of the last attempt
+	LastError string `json:"LastError,omitempty"`
+	// @Description When the event was delivered, in UTC
+	DeliveredAt *time.Time `json:"DeliveredAt,omitempty"`
+	// @Description The attempts made, oldest first
+	AttemptLog []WebhookAttempt `json:"AttemptLog"`
+}
+
+// WebhookAttempt records one HTTP request made for a delivery.
+type WebhookAttempt struct {
+	// @Description The HTTP status code of the response, 0 if no response was received
+	// @Example 500
+	StatusCode int `json:"StatusCode"`
+	// @Description Why the attempt failed
+	// @Example "unexpected status 500"
+	Error string `json:"Error,omitempty"`
+	// @Description How long the request took, in milliseconds
+	// @Example 120
+	DurationMs int64 `json:"DurationMs"`
+	// @Description When the attempt was made, in UTC
+	CreatedAt time.Time `json:"CreatedAt"`
+}
@@ -4,17 +4,23 @@ import (
 	"errors"
 	"proyecto_final_go/internal/domain"
 	store "proyecto_final_go/pkg/store/appointment"
+	"time"
 )
 
 type AppointmentRepository interface {
-	Create(appointment domain.Appointment) error
-	CreateByPatientDNIAndDentistLicense(patientDNI string, license string, date string, hour string, description string) ([]domain.Appointment, error)
-	GetByID(id int) (domain.Appointment, error)
-	GetByPatientDNI(patientDNI string) ([]domain.Appointment, error)
-	GetAll() ([]domain.Appointment, error)
-	Update(appointment domain.Appointment) error
-	PatchDescription(id int, description string) error
-	Delete(id int) error
+	Create(tenantID int, appointment domain.Appointment) error
+	CreateWithPatient(tenantID int, patient domain.Patient, appointment domain.Appointment) error
+	CreateByPatientDNIAndDentistLicense(tenantID int, patientDNI string, license string, appointment domain.Appointment) ([]domain.Appointment, error)
+	GetByID(tenantID int, id int) (domain.Appointment, error)
+	GetByPatientDNI(tenantID int, patientDNI string) ([]domain.Appointment, error)
+	GetAll(tenantID int) ([]domain.Appointment, error)
+	Search(tenantID int, filter domain.AppointmentFilter) ([]domain.Appointment, error)
+	Each(tenantID int, filter domain.AppointmentFilter, fn func(domain.Appointment) error) error
+	Update(tenantID int, appointment domain.Appointment) error
+	PatchDescription(tenantID int, id int, description string) error
+	Delete(tenantID int, id int) error
+	CheckIn(tenantID int, id int, at time.Time) error
+	Complete(tenantID int, id int, at time.Time) error
 }
 
 // ----------------------------------
@@ -27,28 +33,32 @@ func NewAppointmentRepository(storage store.AppointmentStoreInterface) Appointme
 }
 
 // ----------------------------------
-func (r *appointmentRepository) Create(appointment domain.Appointment) error {
-	existingAppointments, err := r.storage.GetAll()
+func (r *appointmentRepository) Create(tenantID int, appointment domain.Appointment) error {
+	existingAppointments, err := r.storage.GetAll(tenantID)
 	if err != nil {
 		return err
 	}
 	for _, existingAppointment := range existingAppointments {
-		if existingAppointment.Patient.Id == appointment.Patient.Id && existingAppointment.Date == appointment.Date && existingAppointment.Hour == appointment.Hour {
+		if existingAppointment.Patient.Id == appointment.Patient.Id && existingAppointment.Overlaps(appointment) {
 			return errors.New("Patient already has an appointment at the same date and time")
 		}
-		if existingAppointment.Dentist.Id == appointment.Dentist.Id && existingAppointment.Date == appointment.Date && existingAppointment.Hour == appointment.Hour {
+		if existingAppointment.Dentist.Id == appointment.Dentist.Id && existingAppointment.Overlaps(appointment) {
 			return errors.New("Dentist already has an appointment at the same date and time")
 		}
 	}
-	err = r.storage.Create(appointment)
+	err = r.storage.Create(tenantID, appointment)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *appointmentRepository) CreateByPatientDNIAndDentistLicense(patientDNI string, license string, date string, hour string, description string) ([]domain.Appointment, error) {
-	appointments, err := r.storage.CreateByPatientDNIAndDentistLicense(patientDNI, license, date, hour, description)
+func (r *appointmentRepository) CreateWithPatient(tenantID int, patient domain.Patient, appointment domain.Appointment) error {
+	return r.storage.CreateWithPatient(tenantID, patient, appointment)
+}
+
+func (r *appointmentRepository) CreateByPatientDNIAndDentistLicense(tenantID int, patientDNI string, license string, appointment domain.Appointment) ([]domain.Appointment, error) {
+	appointments, err := r.storage.CreateByPatientDNIAndDentistLicense(tenantID, patientDNI, license, appointment)
 	if err != nil {
 		return nil, err
 	}
@@ -56,53 +66,81 @@ func (r *appointmentRepository) CreateByPatientDNIAndDentistLicense(patientDNI s
 
 }
 
-func (r *appointmentRepository) GetByID(id int) (domain.Appointment, error) {
-	appointment, err := r.storage.Read(id)
+func (r *appointmentRepository) GetByID(tenantID int, id int) (domain.Appointment, error) {
+	appointment, err := r.storage.Read(tenantID, id)
 	if err != nil {
 		return domain.Appointment{}, errors.New("Appointment not found")
 	}
 	return appointment, nil
 }
 
-func (r *appointmentRepository) GetByPatientDNI(patientDNI string) ([]domain.Appointment, error) {
-	appointments, err := r.storage.ReadByPatientDNI(patientDNI)
+func (r *appointmentRepository) GetByPatientDNI(tenantID int, patientDNI string) ([]domain.Appointment, error) {
+	appointments, err := r.storage.ReadByPatientDNI(tenantID, patientDNI)
 	if err != nil {
 		return nil, err
 	}
 	return appointments, nil
 }
 
-func (r *appointmentRepository) GetAll() ([]domain.Appointment, error) {
-	appointments, err := r.storage.GetAll()
+func (r *appointmentRepository) GetAll(tenantID int) ([]domain.Appointment, error) {
+	appointments, err := r.storage.GetAll(tenantID)
 	if err != nil {
 		return nil, err
 	}
 	return appointments, nil
 }
 
-func (r *appointmentRepository) Update(appointment domain.Appointment) error {
-	err := r.storage.Update(appointment)
+func (r *appointmentRepository) Search(tenantID int, filter domain.AppointmentFilter) ([]domain.Appointment, error) {
+	appointments, err := r.storage.Search(tenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+func (r *appointmentRepository) Each(tenantID int, filter domain.AppointmentFilter, fn func(domain.Appointment) error) error {
+	return r.storage.Each(tenantID, filter, fn)
+}
+
+func (r *appointmentRepository) Update(tenantID int, appointment domain.Appointment) error {
+	err := r.storage.Update(tenantID, appointment)
 	if err != nil {
 		return errors.New("Error updating appointment")
 	}
 	return nil
 }
 
-func (r *appointmentRepository) PatchDescription(id int, description string) error {
-	appointment, err := r.GetByID(id)
+func (r *appointmentRepository) PatchDescription(tenantID int, id int, description string) error {
+	appointment, err := r.GetByID(tenantID, id)
 	if err != nil {
 		return err
 	}
 	appointment.Description = description
-	err = r.storage.Update(appointment)
+	err = r.storage.Update(tenantID, appointment)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *appointmentRepository) Delete(tenantID int, id int) error {
+	err := r.storage.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *appointmentRepository) CheckIn(tenantID int, id int, at time.Time) error {
+	err := r.storage.CheckIn(tenantID, id, at)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *appointmentRepository) Delete(id int) error {
-	err := r.storage.Delete(id)
+func (r *appointmentRepository) Complete(tenantID int, id int, at time.Time) error {
+	err := r.storage.Complete(tenantID, id, at)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/attachment"
+)
+
+// ----------------------------------
+type AttachmentRepository interface {
+	Create(tenantID int, attachment domain.Attachment) (int, error)
+	GetByID(tenantID int, id int) (domain.Attachment, error)
+	GetByPatient(tenantID int, patientID int) ([]domain.Attachment, error)
+	GetByAppointment(tenantID int, appointmentID int) ([]domain.Attachment, error)
+	Delete(tenantID int, id int) error
+}
+
+// ----------------------------------
+type attachmentRepository struct {
+	storage store.AttachmentStoreInterface
+}
+
+func NewAttachmentRepository(storage store.AttachmentStoreInterface) AttachmentRepository {
+	return &attachmentRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *attachmentRepository) Create(tenantID int, attachment domain.Attachment) (int, error) {
+	id, err := r.storage.Create(tenantID, attachment)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *attachmentRepository) GetByID(tenantID int, id int) (domain.Attachment, error) {
+	attachment, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.Attachment{}, errors.New("Attachment not found")
+	}
+	return attachment, nil
+}
+
+func (r *attachmentRepository) GetByPatient(tenantID int, patientID int) ([]domain.Attachment, error) {
+	attachments, err := r.storage.ReadByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *attachmentRepository) GetByAppointment(tenantID int, appointmentID int) ([]domain.Attachment, error) {
+	attachments, err := r.storage.ReadByAppointment(tenantID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *attachmentRepository) Delete(tenantID int, id int) error {
+	err := r.storage.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/calendar"
+)
+
+// ----------------------------------
+type CalendarRepository interface {
+	GetByTokenHash(tokenHash string) (domain.CalendarToken, error)
+	Save(token domain.CalendarToken, tokenHash string) error
+	Delete(token domain.CalendarToken) error
+}
+
+// ----------------------------------
+type calendarRepository struct {
+	storage store.CalendarStoreInterface
+}
+
+func NewCalendarRepository(storage store.CalendarStoreInterface) CalendarRepository {
+	return &calendarRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *calendarRepository) GetByTokenHash(tokenHash string) (domain.CalendarToken, error) {
+	token, err := r.storage.ReadByTokenHash(tokenHash)
+	if err != nil {
+		return domain.CalendarToken{}, errors.New("Calendar token not found")
+	}
+	return token, nil
+}
+
+func (r *calendarRepository) Save(token domain.CalendarToken, tokenHash string) error {
+	err := r.storage.Save(token, tokenHash)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *calendarRepository) Delete(token domain.CalendarToken) error {
+	err := r.storage.Delete(token)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/clinic"
+)
+
+// ----------------------------------
+type ClinicRepository interface {
+	Create(tenantID int, clinic domain.Clinic) error
+	GetByID(tenantID int, id int) (domain.Clinic, error)
+	GetAll(tenantID int) ([]domain.Clinic, error)
+	Update(tenantID int, clinic domain.Clinic) error
+	Delete(tenantID int, id int) error
+}
+
+// ----------------------------------
+type clinicRepository struct {
+	storage store.ClinicStoreInterface
+}
+
+func NewClinicRepository(storage store.ClinicStoreInterface) ClinicRepository {
+	return &clinicRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *clinicRepository) Create(tenantID int, clinic domain.Clinic) error {
+	err := r.storage.Create(tenantID, clinic)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *clinicRepository) GetByID(tenantID int, id int) (domain.Clinic, error) {
+	clinic, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.Clinic{}, errors.New("Clinic not found")
+	}
+	return clinic, nil
+}
+
+func (r *clinicRepository) GetAll(tenantID int) ([]domain.Clinic, error) {
+	clinics, err := r.storage.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return clinics, nil
+}
+
+func (r *clinicRepository) Update(tenantID int, clinic domain.Clinic) error {
+	err := r.storage.Update(tenantID, clinic)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *clinicRepository) Delete(tenantID int, id int) error {
+	err := r.storage.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
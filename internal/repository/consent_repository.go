@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/consent"
+)
+
+// ----------------------------------
+type ConsentRepository interface {
+	GetTemplate(tenantID int, id int) (domain.ConsentTemplate, error)
+	GetTemplates(tenantID int) ([]domain.ConsentTemplate, error)
+	GetTemplatesByTreatment(tenantID int, treatmentID int) ([]domain.ConsentTemplate, error)
+	CreateTemplate(tenantID int, template domain.ConsentTemplate) (int, error)
+	UpdateTemplate(tenantID int, template domain.ConsentTemplate) error
+	GetByID(tenantID int, id int) (domain.Consent, error)
+	GetByAppointment(tenantID int, appointmentID int) ([]domain.Consent, error)
+	Create(tenantID int, consent domain.Consent) (int, error)
+	Sign(tenantID int, consent domain.Consent) error
+}
+
+// ----------------------------------
+type consentRepository struct {
+	storage store.ConsentStoreInterface
+}
+
+func NewConsentRepository(storage store.ConsentStoreInterface) ConsentRepository {
+	return &consentRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *consentRepository) GetTemplate(tenantID int, id int) (domain.ConsentTemplate, error) {
+	template, err := r.storage.ReadTemplate(tenantID, id)
+	if err != nil {
+		return domain.ConsentTemplate{}, errors.New("Consent template not found")
+	}
+	return template, nil
+}
+
+func (r *consentRepository) GetTemplates(tenantID int) ([]domain.ConsentTemplate, error) {
+	templates, err := r.storage.ReadTemplates(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *consentRepository) GetTemplatesByTreatment(tenantID int, treatmentID int) ([]domain.ConsentTemplate, error) {
+	templates, err := r.storage.ReadTemplatesByTreatment(tenantID, treatmentID)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *consentRepository) CreateTemplate(tenantID int, template domain.ConsentTemplate) (int, error) {
+	id, err := r.storage.CreateTemplate(tenantID, template)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *consentRepository) UpdateTemplate(tenantID int, template domain.ConsentTemplate) error {
+	err := r.storage.UpdateTemplate(tenantID, template)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *consentRepository) GetByID(tenantID int, id int) (domain.Consent, error) {
+	consent, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.Consent{}, errors.New("Consent not found")
+	}
+	return consent, nil
+}
+
+func (r *consentRepository) GetByAppointment(tenantID int, appointmentID int) ([]domain.Consent, error) {
+	consents, err := r.storage.ReadByAppointment(tenantID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	return consents, nil
+}
+
+// Create prepares a consent for an appointment, which has at most one consent
+// of each template.
+func (r *consentRepository) Create(tenantID int, consent domain.Consent) (int, error) {
+	existing, err := r.storage.ReadByAppointment(tenantID, consent.AppointmentId)
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range existing {
+		if e.TemplateId == consent.TemplateId {
+			return 0, errors.New("The consent was already prepared for the appointment")
+		}
+	}
+	id, err := r.storage.Create(tenantID, consent)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *consentRepository) Sign(tenantID int, consent domain.Consent) error {
+	err := r.storage.Sign(tenantID, consent)
+	if err != nil {
+		return err
+	}
+	return nil
+}
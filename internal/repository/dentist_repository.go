@@ -9,12 +9,17 @@ import (
 
 // ----------------------------------
 type DentistRepository interface {
-	Create(dentist domain.Dentist) error
-	GetByID(id int) (domain.Dentist, error)
-	GetAll() ([]domain.Dentist, error)
-	Update(dentist domain.Dentist) error
-	PatchLicense(id int, license string) error
-	Delete(id int) error
+	Create(tenantID int, dentist domain.Dentist) error
+	CreateBatch(tenantID int, dentists []domain.Dentist) error
+	Exists(tenantID int, license string) (bool, error)
+	GetByID(tenantID int, id int) (domain.Dentist, error)
+	GetByLicense(tenantID int, license string) (domain.Dentist, error)
+	GetAll(tenantID int) ([]domain.Dentist, error)
+	Search(tenantID int, filter domain.DentistFilter) ([]domain.Dentist, error)
+	Each(tenantID int, filter domain.DentistFilter, fn func(domain.Dentist) error) error
+	Update(tenantID int, dentist domain.Dentist) error
+	PatchLicense(tenantID int, id int, license string) error
+	Delete(tenantID int, id int) error
 }
 
 // ----------------------------------
@@ -28,8 +33,8 @@ func NewDentistRepository(storage store.DentistStoreInterface) DentistRepository
 
 // ----------------------------------
 
-func (r *dentistRepository) Create(dentist domain.Dentist) error {
-	exists, err := r.storage.Exists(dentist.License)
+func (r *dentistRepository) Create(tenantID int, dentist domain.Dentist) error {
+	exists, err := r.storage.Exists(tenantID, dentist.License)
 	if err != nil {
 		return err
 	}
@@ -37,31 +42,51 @@ func (r *dentistRepository) Create(dentist domain.Dentist) error {
 		return errors.New("License already exists")
 	}
 
-	err = r.storage.Create(dentist)
+	err = r.storage.Create(tenantID, dentist)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
-func (r *dentistRepository) GetByID(id int) (domain.Dentist, error) {
-	dentist, err := r.storage.Read(id)
+func (r *dentistRepository) GetByID(tenantID int, id int) (domain.Dentist, error) {
+	dentist, err := r.storage.Read(tenantID, id)
 	if err != nil {
 		return domain.Dentist{}, errors.New("Dentist not found")
 	}
 	return dentist, nil
 }
 
-func (r *dentistRepository) GetAll() ([]domain.Dentist, error) {
-	dentists, err := r.storage.GetAll()
+func (r *dentistRepository) GetByLicense(tenantID int, license string) (domain.Dentist, error) {
+	dentist, err := r.storage.ReadByLicense(tenantID, license)
+	if err != nil {
+		return domain.Dentist{}, errors.New("Dentist not found")
+	}
+	return dentist, nil
+}
+
+func (r *dentistRepository) GetAll(tenantID int) ([]domain.Dentist, error) {
+	dentists, err := r.storage.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return dentists, nil
+}
+
+func (r *dentistRepository) Search(tenantID int, filter domain.DentistFilter) ([]domain.Dentist, error) {
+	dentists, err := r.storage.Search(tenantID, filter)
 	if err != nil {
 		return nil, err
 	}
 	return dentists, nil
 }
 
-func (r *dentistRepository) Update(dentist domain.Dentist) error {
-	exists, err := r.storage.Exists(dentist.License)
+func (r *dentistRepository) Each(tenantID int, filter domain.DentistFilter, fn func(domain.Dentist) error) error {
+	return r.storage.Each(tenantID, filter, fn)
+}
+
+func (r *dentistRepository) Update(tenantID int, dentist domain.Dentist) error {
+	exists, err := r.storage.Exists(tenantID, dentist.License)
 	if err != nil {
 		return err
 	}
@@ -69,7 +94,7 @@ func (r *dentistRepository) Update(dentist domain.Dentist) error {
 		return errors.New("License already exists")
 	}
 
-	err = r.storage.Update(dentist)
+	err = r.storage.Update(tenantID, dentist)
 	if err != nil {
 		return err
 	}
@@ -77,13 +102,13 @@ func (r *dentistRepository) Update(dentist domain.Dentist) error {
 	return nil
 }
 
-func (r *dentistRepository) PatchLicense(id int, license string) error {
-	dentist, err := r.storage.Read(id)
+func (r *dentistRepository) PatchLicense(tenantID int, id int, license string) error {
+	dentist, err := r.storage.Read(tenantID, id)
 	if err != nil {
 		return errors.New("Dentist not found")
 	}
 	dentist.License = license
-	err = r.storage.PatchLicense(dentist.Id, dentist.License)
+	err = r.storage.PatchLicense(tenantID, dentist.Id, dentist.License)
 	if err != nil {
 		return err
 	}
@@ -91,10 +116,27 @@ func (r *dentistRepository) PatchLicense(id int, license string) error {
 
 }
 
-func (r *dentistRepository) Delete(id int) error {
-	err := r.storage.Delete(id)
+func (r *dentistRepository) Delete(tenantID int, id int) error {
+	err := r.storage.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateBatch creates all the dentists or none of them.
+func (r *dentistRepository) CreateBatch(tenantID int, dentists []domain.Dentist) error {
+	err := r.storage.CreateBatch(tenantID, dentists)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (r *dentistRepository) Exists(tenantID int, license string) (bool, error) {
+	exists, err := r.storage.Exists(tenantID, license)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
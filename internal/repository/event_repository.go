@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"proyecto_final_go/internal/domain"
+	"time"
+
+	store "proyecto_final_go/pkg/store/event"
+)
+
+// ----------------------------------
+type EventRepository interface {
+	LastID() (int64, error)
+	GetAfter(afterID int64, limit int) ([]domain.Event, error)
+	GetTenantAfter(tenantID int, afterID int64, aggregateType string, limit int) ([]domain.Event, error)
+	GetByType(tenantID int, eventType string, since time.Time) ([]domain.Event, error)
+	CountByAggregate(tenantID int, aggregateType string, ids []int) (map[int]int, error)
+}
+
+// ----------------------------------
+type eventRepository struct {
+	storage store.EventStoreInterface
+}
+
+func NewEventRepository(storage store.EventStoreInterface) EventRepository {
+	return &eventRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *eventRepository) LastID() (int64, error) {
+	id, err := r.storage.LastID()
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *eventRepository) GetAfter(afterID int64, limit int) ([]domain.Event, error) {
+	events, err := r.storage.ReadAfter(afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *eventRepository) GetTenantAfter(tenantID int, afterID int64, aggregateType string, limit int) ([]domain.Event, error) {
+	events, err := r.storage.ReadTenantAfter(tenantID, afterID, aggregateType, limit)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *eventRepository) GetByType(tenantID int, eventType string, since time.Time) ([]domain.Event, error) {
+	events, err := r.storage.ReadByType(tenantID, eventType, since)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *eventRepository) CountByAggregate(tenantID int, aggregateType string, ids []int) (map[int]int, error) {
+	counts, err := r.storage.CountByAggregate(tenantID, aggregateType, ids)
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/history"
+)
+
+// ----------------------------------
+type HistoryRepository interface {
+	Create(tenantID int, entry domain.HistoryEntry) (int, error)
+	GetByID(tenantID int, id int) (domain.HistoryEntry, error)
+	GetByPatient(tenantID int, patientID int) ([]domain.HistoryEntry, error)
+	Update(tenantID int, entry domain.HistoryEntry) error
+	Delete(tenantID int, id int) error
+}
+
+// ----------------------------------
+type historyRepository struct {
+	storage store.HistoryStoreInterface
+}
+
+func NewHistoryRepository(storage store.HistoryStoreInterface) HistoryRepository {
+	return &historyRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *historyRepository) Create(tenantID int, entry domain.HistoryEntry) (int, error) {
+	id, err := r.storage.Create(tenantID, entry)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *historyRepository) GetByID(tenantID int, id int) (domain.HistoryEntry, error) {
+	entry, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.HistoryEntry{}, errors.New("History entry not found")
+	}
+	return entry, nil
+}
+
+func (r *historyRepository) GetByPatient(tenantID int, patientID int) ([]domain.HistoryEntry, error) {
+	entries, err := r.storage.ReadByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *historyRepository) Update(tenantID int, entry domain.HistoryEntry) error {
+	err := r.storage.Update(tenantID, entry)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *historyRepository) Delete(tenantID int, id int) error {
+	err := r.storage.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
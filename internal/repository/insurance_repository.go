@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"strings"
+
+	store "proyecto_final_go/pkg/store/insurance"
+)
+
+// ----------------------------------
+type InsuranceRepository interface {
+	GetProvider(tenantID int, id int) (domain.InsuranceProvider, error)
+	GetProviders(tenantID int) ([]domain.InsuranceProvider, error)
+	CreateProvider(tenantID int, provider domain.InsuranceProvider) (int, error)
+	UpdateProvider(tenantID int, provider domain.InsuranceProvider) error
+	GetRule(tenantID int, id int) (domain.CoverageRule, error)
+	CreateRule(tenantID int, rule domain.CoverageRule) (int, error)
+	UpdateRule(tenantID int, rule domain.CoverageRule) error
+	DeleteRule(tenantID int, id int) error
+	GetCoverage(tenantID int, id int) (domain.Coverage, error)
+	GetCoverages(tenantID int, patientID int) ([]domain.Coverage, error)
+	CreateCoverage(tenantID int, coverage domain.Coverage) (int, error)
+	UpdateCoverage(tenantID int, coverage domain.Coverage) error
+	GetClaim(tenantID int, id int) (domain.Claim, error)
+	GetClaims(tenantID int, status string, providerID int) ([]domain.Claim, error)
+	GetClaimsByInvoice(tenantID int, invoiceID int) ([]domain.Claim, error)
+	CreateClaim(tenantID int, claim domain.Claim) (int, error)
+	UpdateClaim(tenantID int, claim domain.Claim, from string) error
+}
+
+// ----------------------------------
+type insuranceRepository struct {
+	storage store.InsuranceStoreInterface
+}
+
+func NewInsuranceRepository(storage store.InsuranceStoreInterface) InsuranceRepository {
+	return &insuranceRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *insuranceRepository) GetProvider(tenantID int, id int) (domain.InsuranceProvider, error) {
+	provider, err := r.storage.ReadProvider(tenantID, id)
+	if err != nil {
+		return domain.InsuranceProvider{}, errors.New("Insurance provider not found")
+	}
+	return provider, nil
+}
+
+func (r *insuranceRepository) GetProviders(tenantID int) ([]domain.InsuranceProvider, error) {
+	providers, err := r.storage.ReadProviders(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+func (r *insuranceRepository) CreateProvider(tenantID int, provider domain.InsuranceProvider) (int, error) {
+	if err := r.checkProviderName(tenantID, provider); err != nil {
+		return 0, err
+	}
+	id, err := r.storage.CreateProvider(tenantID, provider)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *insuranceRepository) UpdateProvider(tenantID int, provider domain.InsuranceProvider) error {
+	if err := r.checkProviderName(tenantID, provider); err != nil {
+		return err
+	}
+	err := r.storage.UpdateProvider(tenantID, provider)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkProviderName rejects a name another provider of the practice has.
+func (r *insuranceRepository) checkProviderName(tenantID int, provider domain.InsuranceProvider) error {
+	providers, err := r.storage.ReadProviders(tenantID)
+	if err != nil {
+		return err
+	}
+	for _, other := range providers {
+		if other.Id != provider.Id && strings.EqualFold(other.Name, provider.Name) {
+			return errors.New("Insurance provider already exists")
+		}
+	}
+	return nil
+}
+
+func (r *insuranceRepository) GetRule(tenantID int, id int) (domain.CoverageRule, error) {
+	rule, err := r.storage.ReadRule(tenantID, id)
+	if err != nil {
+		return domain.CoverageRule{}, errors.New("Coverage rule not found")
+	}
+	return rule, nil
+}
+
+func (r *insuranceRepository) CreateRule(tenantID int, rule domain.CoverageRule) (int, error) {
+	if err := r.checkRule(tenantID, rule); err != nil {
+		return 0, err
+	}
+	id, err := r.storage.CreateRule(tenantID, rule)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *insuranceRepository) UpdateRule(tenantID int, rule domain.CoverageRule) error {
+	if err := r.checkRule(tenantID, rule); err != nil {
+		return err
+	}
+	err := r.storage.UpdateRule(tenantID, rule)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkRule rejects a second rule of the provider for the same plan and
+// treatment.
+func (r *insuranceRepository) checkRule(tenantID int, rule domain.CoverageRule) error {
+	provider, err := r.storage.ReadProvider(tenantID, rule.ProviderId)
+	if err != nil {
+		return errors.New("Insurance provider not found")
+	}
+	for _, other := range provider.Rules {
+		if other.Id != rule.Id && other.Plan == rule.Plan && other.TreatmentId == rule.TreatmentId {
+			return errors.New("The provider already has a rule for the treatment and plan")
+		}
+	}
+	return nil
+}
+
+func (r *insuranceRepository) DeleteRule(tenantID int, id int) error {
+	err := r.storage.DeleteRule(tenantID, id)
+	if err != nil {
+		return errors.New("Coverage rule not found")
+	}
+	return nil
+}
+
+func (r *insuranceRepository) GetCoverage(tenantID int, id int) (domain.Coverage, error) {
+	coverage, err := r.storage.ReadCoverage(tenantID, id)
+	if err != nil {
+		return domain.Coverage{}, errors.New("Coverage not found")
+	}
+	return coverage, nil
+}
+
+func (r *insuranceRepository) GetCoverages(tenantID int, patientID int) ([]domain.Coverage, error) {
+	coverages, err := r.storage.ReadCoverages(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+func (r *insuranceRepository) CreateCoverage(tenantID int, coverage domain.Coverage) (int, error) {
+	id, err := r.storage.CreateCoverage(tenantID, coverage)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *insuranceRepository) UpdateCoverage(tenantID int, coverage domain.Coverage) error {
+	err := r.storage.UpdateCoverage(tenantID, coverage)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *insuranceRepository) GetClaim(tenantID int, id int) (domain.Claim, error) {
+	claim, err := r.storage.ReadClaim(tenantID, id)
+	if err != nil {
+		return domain.Claim{}, errors.New("Claim not found")
+	}
+	return claim, nil
+}
+
+func (r *insuranceRepository) GetClaims(tenantID int, status string, providerID int) ([]domain.Claim, error) {
+	claims, err := r.storage.ReadClaims(tenantID, status, providerID)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (r *insuranceRepository) GetClaimsByInvoice(tenantID int, invoiceID int) ([]domain.Claim, error) {
+	claims, err := r.storage.ReadClaimsByInvoice(tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (r *insuranceRepository) CreateClaim(tenantID int, claim domain.Claim) (int, error) {
+	id, err := r.storage.CreateClaim(tenantID, claim)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *insuranceRepository) UpdateClaim(tenantID int, claim domain.Claim, from string) error {
+	err := r.storage.UpdateClaim(tenantID, claim, from)
+	if err != nil {
+		return err
+	}
+	return nil
+}
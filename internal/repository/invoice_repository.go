@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"time"
+
+	store "proyecto_final_go/pkg/store/invoice"
+)
+
+// ----------------------------------
+type InvoiceRepository interface {
+	Create(tenantID int, invoice domain.Invoice) (int, error)
+	GetByID(tenantID int, id int) (domain.Invoice, error)
+	GetByPatient(tenantID int, patientID int) ([]domain.Invoice, error)
+	GetAll(tenantID int, status string) ([]domain.Invoice, error)
+	GetBilledAppointments(tenantID int, patientID int) ([]int, error)
+	Issue(tenantID int, id int, at time.Time) error
+	Void(tenantID int, id int, at time.Time, reason string) error
+}
+
+// ----------------------------------
+type invoiceRepository struct {
+	storage store.InvoiceStoreInterface
+}
+
+func NewInvoiceRepository(storage store.InvoiceStoreInterface) InvoiceRepository {
+	return &invoiceRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *invoiceRepository) Create(tenantID int, invoice domain.Invoice) (int, error) {
+	id, err := r.storage.Create(tenantID, invoice)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *invoiceRepository) GetByID(tenantID int, id int) (domain.Invoice, error) {
+	invoice, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.Invoice{}, errors.New("Invoice not found")
+	}
+	return invoice, nil
+}
+
+func (r *invoiceRepository) GetByPatient(tenantID int, patientID int) ([]domain.Invoice, error) {
+	invoices, err := r.storage.ReadByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (r *invoiceRepository) GetAll(tenantID int, status string) ([]domain.Invoice, error) {
+	invoices, err := r.storage.ReadAll(tenantID, status)
+	if err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (r *invoiceRepository) GetBilledAppointments(tenantID int, patientID int) ([]int, error) {
+	ids, err := r.storage.ReadBilledAppointments(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *invoiceRepository) Issue(tenantID int, id int, at time.Time) error {
+	err := r.storage.Issue(tenantID, id, at)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *invoiceRepository) Void(tenantID int, id int, at time.Time, reason string) error {
+	err := r.storage.Void(tenantID, id, at, reason)
+	if err != nil {
+		return err
+	}
+	return nil
+}
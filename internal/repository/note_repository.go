@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/note"
+)
+
+// ----------------------------------
+type NoteRepository interface {
+	Create(tenantID int, note domain.ClinicalNote) (int, error)
+	GetByID(tenantID int, id int) (domain.ClinicalNote, error)
+	GetByAppointment(tenantID int, appointmentID int) ([]domain.ClinicalNote, error)
+	GetByPatient(tenantID int, patientID int) ([]domain.ClinicalNote, error)
+}
+
+// ----------------------------------
+type noteRepository struct {
+	storage store.NoteStoreInterface
+}
+
+func NewNoteRepository(storage store.NoteStoreInterface) NoteRepository {
+	return &noteRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *noteRepository) Create(tenantID int, note domain.ClinicalNote) (int, error) {
+	id, err := r.storage.Create(tenantID, note)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *noteRepository) GetByID(tenantID int, id int) (domain.ClinicalNote, error) {
+	note, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.ClinicalNote{}, errors.New("Clinical note not found")
+	}
+	return note, nil
+}
+
+func (r *noteRepository) GetByAppointment(tenantID int, appointmentID int) ([]domain.ClinicalNote, error) {
+	notes, err := r.storage.ReadByAppointment(tenantID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (r *noteRepository) GetByPatient(tenantID int, patientID int) ([]domain.ClinicalNote, error) {
+	notes, err := r.storage.ReadByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
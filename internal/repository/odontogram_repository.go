@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/odontogram"
+)
+
+// ----------------------------------
+type OdontogramRepository interface {
+	GetByPatient(tenantID int, patientID int) ([]domain.ToothFinding, error)
+	CreateBatch(tenantID int, findings []domain.ToothFinding) ([]int, error)
+}
+
+// ----------------------------------
+type odontogramRepository struct {
+	storage store.OdontogramStoreInterface
+}
+
+func NewOdontogramRepository(storage store.OdontogramStoreInterface) OdontogramRepository {
+	return &odontogramRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *odontogramRepository) GetByPatient(tenantID int, patientID int) ([]domain.ToothFinding, error) {
+	findings, err := r.storage.ReadByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+func (r *odontogramRepository) CreateBatch(tenantID int, findings []domain.ToothFinding) ([]int, error) {
+	ids, err := r.storage.CreateBatch(tenantID, findings)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -8,12 +8,17 @@ import (
 
 // ----------------------------------
 type PatientRepository interface {
-	Create(patient domain.Patient) error
-	GetByID(id int) (domain.Patient, error)
-	GetAll() ([]domain.Patient, error)
-	Update(patient domain.Patient) error
-	PatchAddress(id int, address string) error
-	Delete(id int) error
+	Create(tenantID int, patient domain.Patient) error
+	CreateBatch(tenantID int, patients []domain.Patient) error
+	Exists(tenantID int, dni string) (bool, error)
+	GetByID(tenantID int, id int) (domain.Patient, error)
+	GetByDNI(tenantID int, dni string) (domain.Patient, error)
+	GetAll(tenantID int) ([]domain.Patient, error)
+	Each(tenantID int, fn func(domain.Patient) error) error
+	Search(tenantID int, terms []string, limit int) ([]domain.Patient, error)
+	Update(tenantID int, patient domain.Patient) error
+	PatchAddress(tenantID int, id int, address string) error
+	Delete(tenantID int, id int) error
 }
 
 // ----------------------------------
@@ -27,8 +32,8 @@ func NewPatientRepository(storage store.PatientStoreInterface) PatientRepository
 
 //------------------------------------
 
-func (r *patientRepository) Create(p domain.Patient) error {
-	exists, err := r.storage.Exists(p.DNI)
+func (r *patientRepository) Create(tenantID int, p domain.Patient) error {
+	exists, err := r.storage.Exists(tenantID, p.DNI)
 	if err != nil {
 		return err
 	}
@@ -36,7 +41,7 @@ func (r *patientRepository) Create(p domain.Patient) error {
 		return errors.New("DNI already exists")
 	}
 
-	err = r.storage.Create(p)
+	err = r.storage.Create(tenantID, p)
 	if err != nil {
 		return err
 	}
@@ -44,8 +49,8 @@ func (r *patientRepository) Create(p domain.Patient) error {
 	return nil
 }
 
-func (r *patientRepository) GetByID(id int) (domain.Patient, error) {
-	patient, err := r.storage.Read(id)
+func (r *patientRepository) GetByID(tenantID int, id int) (domain.Patient, error) {
+	patient, err := r.storage.Read(tenantID, id)
 	if err != nil {
 		return domain.Patient{}, errors.New("Patient not found")
 	}
@@ -53,16 +58,36 @@ func (r *patientRepository) GetByID(id int) (domain.Patient, error) {
 
 }
 
-func (r *patientRepository) GetAll() ([]domain.Patient, error) {
-	patients, err := r.storage.GetAll()
+func (r *patientRepository) GetByDNI(tenantID int, dni string) (domain.Patient, error) {
+	patient, err := r.storage.ReadByDNI(tenantID, dni)
+	if err != nil {
+		return domain.Patient{}, errors.New("Patient not found")
+	}
+	return patient, nil
+}
+
+func (r *patientRepository) GetAll(tenantID int) ([]domain.Patient, error) {
+	patients, err := r.storage.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return patients, nil
+}
+
+func (r *patientRepository) Each(tenantID int, fn func(domain.Patient) error) error {
+	return r.storage.Each(tenantID, fn)
+}
+
+func (r *patientRepository) Search(tenantID int, terms []string, limit int) ([]domain.Patient, error) {
+	patients, err := r.storage.Search(tenantID, terms, limit)
 	if err != nil {
 		return nil, err
 	}
 	return patients, nil
 }
 
-func (r *patientRepository) Update(p domain.Patient) error {
-	exists, err := r.storage.Exists(p.DNI)
+func (r *patientRepository) Update(tenantID int, p domain.Patient) error {
+	exists, err := r.storage.Exists(tenantID, p.DNI)
 	if err != nil {
 		return err
 	}
@@ -70,7 +95,7 @@ func (r *patientRepository) Update(p domain.Patient) error {
 		return errors.New("DNI already exists")
 	}
 
-	err = r.storage.Update(p)
+	err = r.storage.Update(tenantID, p)
 	if err != nil {
 		return err
 	}
@@ -78,23 +103,40 @@ func (r *patientRepository) Update(p domain.Patient) error {
 	return nil
 }
 
-func (r *patientRepository) PatchAddress(id int, address string) error {
-	patient, err := r.storage.Read(id)
+func (r *patientRepository) PatchAddress(tenantID int, id int, address string) error {
+	patient, err := r.storage.Read(tenantID, id)
 	if err != nil {
 		return errors.New("Patient not found")
 	}
 	patient.Address = address
-	err = r.storage.PatchAddress(patient.Id, patient.Address)
+	err = r.storage.PatchAddress(tenantID, patient.Id, patient.Address)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *patientRepository) Delete(tenantID int, id int) error {
+	err := r.storage.Delete(tenantID, id)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *patientRepository) Delete(id int) error {
-	err := r.storage.Delete(id)
+// CreateBatch creates all the patients or none of them.
+func (r *patientRepository) CreateBatch(tenantID int, patients []domain.Patient) error {
+	err := r.storage.CreateBatch(tenantID, patients)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (r *patientRepository) Exists(tenantID int, dni string) (bool, error) {
+	exists, err := r.storage.Exists(tenantID, dni)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"time"
+
+	store "proyecto_final_go/pkg/store/payment"
+)
+
+// ----------------------------------
+type PaymentRepository interface {
+	GetByID(tenantID int, id int) (domain.Payment, error)
+	GetByInvoice(tenantID int, invoiceID int) ([]domain.Payment, error)
+	GetByPatient(tenantID int, patientID int) ([]domain.Payment, error)
+	GetByClinic(tenantID int, clinicID int, from time.Time, to time.Time) ([]domain.Payment, error)
+	Create(tenantID int, payment domain.Payment) (int, error)
+	Complete(tenantID int, id int, provider string, reference string) error
+	Void(tenantID int, id int) error
+}
+
+// ----------------------------------
+type paymentRepository struct {
+	storage store.PaymentStoreInterface
+}
+
+func NewPaymentRepository(storage store.PaymentStoreInterface) PaymentRepository {
+	return &paymentRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *paymentRepository) GetByID(tenantID int, id int) (domain.Payment, error) {
+	payment, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.Payment{}, errors.New("Payment not found")
+	}
+	return payment, nil
+}
+
+func (r *paymentRepository) GetByInvoice(tenantID int, invoiceID int) ([]domain.Payment, error) {
+	payments, err := r.storage.ReadByInvoice(tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *paymentRepository) GetByPatient(tenantID int, patientID int) ([]domain.Payment, error) {
+	payments, err := r.storage.ReadByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *paymentRepository) GetByClinic(tenantID int, clinicID int, from time.Time, to time.Time) ([]domain.Payment, error) {
+	payments, err := r.storage.ReadByClinic(tenantID, clinicID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *paymentRepository) Create(tenantID int, payment domain.Payment) (int, error) {
+	id, err := r.storage.Create(tenantID, payment)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *paymentRepository) Complete(tenantID int, id int, provider string, reference string) error {
+	err := r.storage.Complete(tenantID, id, provider, reference)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *paymentRepository) Void(tenantID int, id int) error {
+	err := r.storage.Void(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
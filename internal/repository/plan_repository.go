@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/plan"
+)
+
+// ----------------------------------
+type PlanRepository interface {
+	Create(tenantID int, plan domain.TreatmentPlan) (int, error)
+	GetByID(tenantID int, id int) (domain.TreatmentPlan, error)
+	GetByPatient(tenantID int, patientID int) ([]domain.TreatmentPlan, error)
+	GetByStep(tenantID int, stepID int) (domain.TreatmentPlan, error)
+	UpdateStatus(tenantID int, id int, status string) error
+	CreateStep(tenantID int, step domain.PlanStep) (int, error)
+	UpdateStep(tenantID int, step domain.PlanStep) error
+}
+
+// ----------------------------------
+type planRepository struct {
+	storage store.PlanStoreInterface
+}
+
+func NewPlanRepository(storage store.PlanStoreInterface) PlanRepository {
+	return &planRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *planRepository) Create(tenantID int, plan domain.TreatmentPlan) (int, error) {
+	id, err := r.storage.Create(tenantID, plan)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *planRepository) GetByID(tenantID int, id int) (domain.TreatmentPlan, error) {
+	plan, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.TreatmentPlan{}, errors.New("Treatment plan not found")
+	}
+	return plan, nil
+}
+
+func (r *planRepository) GetByPatient(tenantID int, patientID int) ([]domain.TreatmentPlan, error) {
+	plans, err := r.storage.ReadByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+func (r *planRepository) GetByStep(tenantID int, stepID int) (domain.TreatmentPlan, error) {
+	plan, err := r.storage.ReadByStep(tenantID, stepID)
+	if err != nil {
+		return domain.TreatmentPlan{}, errors.New("Plan step not found")
+	}
+	return plan, nil
+}
+
+func (r *planRepository) UpdateStatus(tenantID int, id int, status string) error {
+	err := r.storage.UpdateStatus(tenantID, id, status)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *planRepository) CreateStep(tenantID int, step domain.PlanStep) (int, error) {
+	id, err := r.storage.CreateStep(tenantID, step)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *planRepository) UpdateStep(tenantID int, step domain.PlanStep) error {
+	err := r.storage.UpdateStep(tenantID, step)
+	if err != nil {
+		return err
+	}
+	return nil
+}
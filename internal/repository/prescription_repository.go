@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"time"
+
+	store "proyecto_final_go/pkg/store/prescription"
+)
+
+// ----------------------------------
+type PrescriptionRepository interface {
+	Create(tenantID int, prescription domain.Prescription) (int, error)
+	GetByID(tenantID int, id int) (domain.Prescription, error)
+	GetByCode(code string) (domain.Prescription, error)
+	GetByPatient(tenantID int, patientID int) ([]domain.Prescription, error)
+	GetByAppointment(tenantID int, appointmentID int) ([]domain.Prescription, error)
+	Void(tenantID int, id int, at time.Time, reason string) error
+}
+
+// ----------------------------------
+type prescriptionRepository struct {
+	storage store.PrescriptionStoreInterface
+}
+
+func NewPrescriptionRepository(storage store.PrescriptionStoreInterface) PrescriptionRepository {
+	return &prescriptionRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *prescriptionRepository) Create(tenantID int, prescription domain.Prescription) (int, error) {
+	id, err := r.storage.Create(tenantID, prescription)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *prescriptionRepository) GetByID(tenantID int, id int) (domain.Prescription, error) {
+	prescription, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.Prescription{}, errors.New("Prescription not found")
+	}
+	return prescription, nil
+}
+
+func (r *prescriptionRepository) GetByCode(code string) (domain.Prescription, error) {
+	prescription, err := r.storage.ReadByCode(code)
+	if err != nil {
+		return domain.Prescription{}, errors.New("Prescription not found")
+	}
+	return prescription, nil
+}
+
+func (r *prescriptionRepository) GetByPatient(tenantID int, patientID int) ([]domain.Prescription, error) {
+	prescriptions, err := r.storage.ReadByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return prescriptions, nil
+}
+
+func (r *prescriptionRepository) GetByAppointment(tenantID int, appointmentID int) ([]domain.Prescription, error) {
+	prescriptions, err := r.storage.ReadByAppointment(tenantID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	return prescriptions, nil
+}
+
+func (r *prescriptionRepository) Void(tenantID int, id int, at time.Time, reason string) error {
+	err := r.storage.Void(tenantID, id, at, reason)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"time"
+
+	store "proyecto_final_go/pkg/store/reminder"
+)
+
+// ----------------------------------
+type ReminderRepository interface {
+	Claim(tenantID int, appointmentID int, offset time.Duration, now time.Time, expiry time.Duration) (bool, error)
+	MarkSent(tenantID int, appointmentID int, offset time.Duration) error
+	Release(tenantID int, appointmentID int, offset time.Duration) error
+}
+
+// ----------------------------------
+type reminderRepository struct {
+	storage store.ReminderStoreInterface
+}
+
+func NewReminderRepository(storage store.ReminderStoreInterface) ReminderRepository {
+	return &reminderRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *reminderRepository) Claim(tenantID int, appointmentID int, offset time.Duration, now time.Time, expiry time.Duration) (bool, error) {
+	claimed, err := r.storage.Claim(tenantID, appointmentID, offset, now, expiry)
+	if err != nil {
+		return false, err
+	}
+	return claimed, nil
+}
+
+func (r *reminderRepository) MarkSent(tenantID int, appointmentID int, offset time.Duration) error {
+	err := r.storage.MarkSent(tenantID, appointmentID, offset)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *reminderRepository) Release(tenantID int, appointmentID int, offset time.Duration) error {
+	err := r.storage.Release(tenantID, appointmentID, offset)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/resource"
+)
+
+// ----------------------------------
+type ResourceRepository interface {
+	Create(tenantID int, resource domain.Resource) error
+	GetByID(tenantID int, id int) (domain.Resource, error)
+	GetAll(tenantID int) ([]domain.Resource, error)
+	Search(tenantID int, filter domain.ResourceFilter) ([]domain.Resource, error)
+	Update(tenantID int, resource domain.Resource) error
+	Delete(tenantID int, id int) error
+}
+
+// ----------------------------------
+type resourceRepository struct {
+	storage store.ResourceStoreInterface
+}
+
+func NewResourceRepository(storage store.ResourceStoreInterface) ResourceRepository {
+	return &resourceRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *resourceRepository) Create(tenantID int, resource domain.Resource) error {
+	err := r.storage.Create(tenantID, resource)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *resourceRepository) GetByID(tenantID int, id int) (domain.Resource, error) {
+	resource, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.Resource{}, errors.New("Resource not found")
+	}
+	return resource, nil
+}
+
+func (r *resourceRepository) GetAll(tenantID int) ([]domain.Resource, error) {
+	resources, err := r.storage.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+func (r *resourceRepository) Search(tenantID int, filter domain.ResourceFilter) ([]domain.Resource, error) {
+	resources, err := r.storage.Search(tenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+func (r *resourceRepository) Update(tenantID int, resource domain.Resource) error {
+	err := r.storage.Update(tenantID, resource)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *resourceRepository) Delete(tenantID int, id int) error {
+	err := r.storage.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/schedule"
+)
+
+// ----------------------------------
+type ScheduleRepository interface {
+	Create(tenantID int, schedule domain.Schedule) error
+	GetByID(tenantID int, id int) (domain.Schedule, error)
+	GetByDentist(tenantID int, dentistID int) ([]domain.Schedule, error)
+	Delete(tenantID int, id int) error
+}
+
+// ----------------------------------
+type scheduleRepository struct {
+	storage store.ScheduleStoreInterface
+}
+
+func NewScheduleRepository(storage store.ScheduleStoreInterface) ScheduleRepository {
+	return &scheduleRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *scheduleRepository) Create(tenantID int, schedule domain.Schedule) error {
+	existingSchedules, err := r.storage.GetByDentist(tenantID, schedule.DentistId)
+	if err != nil {
+		return err
+	}
+	for _, existing := range existingSchedules {
+		if existing.Overlaps(schedule) {
+			return errors.New("Dentist already works at " + existing.Clinic.Name + " during this time")
+		}
+	}
+
+	err = r.storage.Create(tenantID, schedule)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *scheduleRepository) GetByID(tenantID int, id int) (domain.Schedule, error) {
+	schedule, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.Schedule{}, errors.New("Schedule not found")
+	}
+	return schedule, nil
+}
+
+func (r *scheduleRepository) GetByDentist(tenantID int, dentistID int) ([]domain.Schedule, error) {
+	schedules, err := r.storage.GetByDentist(tenantID, dentistID)
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *scheduleRepository) Delete(tenantID int, id int) error {
+	err := r.storage.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
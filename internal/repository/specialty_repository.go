@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/specialty"
+)
+
+// ----------------------------------
+type SpecialtyRepository interface {
+	Create(tenantID int, specialty domain.Specialty) error
+	GetByID(tenantID int, id int) (domain.Specialty, error)
+	GetByName(tenantID int, name string) (domain.Specialty, error)
+	GetAll(tenantID int) ([]domain.Specialty, error)
+	Delete(tenantID int, id int) error
+	AddToDentist(tenantID int, dentistID int, specialtyID int) error
+	RemoveFromDentist(tenantID int, dentistID int, specialtyID int) error
+}
+
+// ----------------------------------
+type specialtyRepository struct {
+	storage store.SpecialtyStoreInterface
+}
+
+func NewSpecialtyRepository(storage store.SpecialtyStoreInterface) SpecialtyRepository {
+	return &specialtyRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *specialtyRepository) Create(tenantID int, specialty domain.Specialty) error {
+	exists, err := r.storage.Exists(tenantID, specialty.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("Specialty already exists")
+	}
+
+	err = r.storage.Create(tenantID, specialty)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *specialtyRepository) GetByID(tenantID int, id int) (domain.Specialty, error) {
+	specialty, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.Specialty{}, errors.New("Specialty not found")
+	}
+	return specialty, nil
+}
+
+func (r *specialtyRepository) GetByName(tenantID int, name string) (domain.Specialty, error) {
+	specialty, err := r.storage.ReadByName(tenantID, name)
+	if err != nil {
+		return domain.Specialty{}, errors.New("Specialty not found")
+	}
+	return specialty, nil
+}
+
+func (r *specialtyRepository) GetAll(tenantID int) ([]domain.Specialty, error) {
+	specialties, err := r.storage.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return specialties, nil
+}
+
+func (r *specialtyRepository) Delete(tenantID int, id int) error {
+	err := r.storage.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *specialtyRepository) AddToDentist(tenantID int, dentistID int, specialtyID int) error {
+	err := r.storage.AddToDentist(tenantID, dentistID, specialtyID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *specialtyRepository) RemoveFromDentist(tenantID int, dentistID int, specialtyID int) error {
+	err := r.storage.RemoveFromDentist(tenantID, dentistID, specialtyID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
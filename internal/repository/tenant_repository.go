@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/tenant"
+)
+
+// ----------------------------------
+type TenantRepository interface {
+	Create(tenant domain.Tenant, tokenHash string) (int, error)
+	GetByID(id int) (domain.Tenant, error)
+	GetByTokenHash(tokenHash string) (domain.Tenant, error)
+	GetAll() ([]domain.Tenant, error)
+	UpdateTokenHash(id int, tokenHash string) error
+	Disable(id int) error
+}
+
+// ----------------------------------
+type tenantRepository struct {
+	storage store.TenantStoreInterface
+}
+
+func NewTenantRepository(storage store.TenantStoreInterface) TenantRepository {
+	return &tenantRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *tenantRepository) Create(tenant domain.Tenant, tokenHash string) (int, error) {
+	id, err := r.storage.Create(tenant, tokenHash)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *tenantRepository) GetByID(id int) (domain.Tenant, error) {
+	tenant, err := r.storage.Read(id)
+	if err != nil {
+		return domain.Tenant{}, errors.New("Tenant not found")
+	}
+	return tenant, nil
+}
+
+func (r *tenantRepository) GetByTokenHash(tokenHash string) (domain.Tenant, error) {
+	tenant, err := r.storage.ReadByTokenHash(tokenHash)
+	if err != nil {
+		return domain.Tenant{}, errors.New("Tenant not found")
+	}
+	return tenant, nil
+}
+
+func (r *tenantRepository) GetAll() ([]domain.Tenant, error) {
+	tenants, err := r.storage.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+func (r *tenantRepository) UpdateTokenHash(id int, tokenHash string) error {
+	err := r.storage.UpdateTokenHash(id, tokenHash)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *tenantRepository) Disable(id int) error {
+	err := r.storage.Disable(id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+
+	store "proyecto_final_go/pkg/store/treatment"
+)
+
+// ----------------------------------
+type TreatmentRepository interface {
+	Create(tenantID int, treatment domain.Treatment) error
+	GetByID(tenantID int, id int) (domain.Treatment, error)
+	GetAll(tenantID int) ([]domain.Treatment, error)
+	Update(tenantID int, treatment domain.Treatment) error
+	Delete(tenantID int, id int) error
+}
+
+// ----------------------------------
+type treatmentRepository struct {
+	storage store.TreatmentStoreInterface
+}
+
+func NewTreatmentRepository(storage store.TreatmentStoreInterface) TreatmentRepository {
+	return &treatmentRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *treatmentRepository) Create(tenantID int, treatment domain.Treatment) error {
+	err := r.storage.Create(tenantID, treatment)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *treatmentRepository) GetByID(tenantID int, id int) (domain.Treatment, error) {
+	treatment, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.Treatment{}, errors.New("Treatment not found")
+	}
+	return treatment, nil
+}
+
+func (r *treatmentRepository) GetAll(tenantID int) ([]domain.Treatment, error) {
+	treatments, err := r.storage.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return treatments, nil
+}
+
+func (r *treatmentRepository) Update(tenantID int, treatment domain.Treatment) error {
+	err := r.storage.Update(tenantID, treatment)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *treatmentRepository) Delete(tenantID int, id int) error {
+	err := r.storage.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
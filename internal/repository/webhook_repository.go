@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"time"
+
+	store "proyecto_final_go/pkg/store/webhook"
+)
+
+// ----------------------------------
+type WebhookRepository interface {
+	Create(tenantID int, webhook domain.Webhook) (int, error)
+	GetByID(tenantID int, id int) (domain.Webhook, error)
+	GetAll(tenantID int) ([]domain.Webhook, error)
+	Update(tenantID int, webhook domain.Webhook) error
+	Delete(tenantID int, id int) error
+	CreateDelivery(tenantID int, webhookID int, eventID int64) error
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error)
+	RecordAttempt(delivery domain.WebhookDelivery, attempt domain.WebhookAttempt) error
+	GetDelivery(tenantID int, webhookID int, id int64) (domain.WebhookDelivery, error)
+	GetDeliveries(tenantID int, webhookID int) ([]domain.WebhookDelivery, error)
+	ResetDelivery(tenantID int, webhookID int, id int64, now time.Time) error
+}
+
+// ----------------------------------
+type webhookRepository struct {
+	storage store.WebhookStoreInterface
+}
+
+func NewWebhookRepository(storage store.WebhookStoreInterface) WebhookRepository {
+	return &webhookRepository{storage}
+}
+
+// ----------------------------------
+
+func (r *webhookRepository) Create(tenantID int, webhook domain.Webhook) (int, error) {
+	id, err := r.storage.Create(tenantID, webhook)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *webhookRepository) GetByID(tenantID int, id int) (domain.Webhook, error) {
+	webhook, err := r.storage.Read(tenantID, id)
+	if err != nil {
+		return domain.Webhook{}, errors.New("Webhook not found")
+	}
+	return webhook, nil
+}
+
+func (r *webhookRepository) GetAll(tenantID int) ([]domain.Webhook, error) {
+	webhooks, err := r.storage.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) Update(tenantID int, webhook domain.Webhook) error {
+	err := r.storage.Update(tenantID, webhook)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *webhookRepository) Delete(tenantID int, id int) error {
+	err := r.storage.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *webhookRepository) CreateDelivery(tenantID int, webhookID int, eventID int64) error {
+	err := r.storage.CreateDelivery(tenantID, webhookID, eventID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *webhookRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	deliveries, err := r.storage.ClaimDue(now, lease, limit)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) RecordAttempt(delivery domain.WebhookDelivery, attempt domain.WebhookAttempt) error {
+	err := r.storage.RecordAttempt(delivery, attempt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *webhookRepository) GetDelivery(tenantID int, webhookID int, id int64) (domain.WebhookDelivery, error) {
+	delivery, err := r.storage.ReadDelivery(tenantID, webhookID, id)
+	if err != nil {
+		return domain.WebhookDelivery{}, errors.New("Delivery not found")
+	}
+	return delivery, nil
+}
+
+func (r *webhookRepository) GetDeliveries(tenantID int, webhookID int) ([]domain.WebhookDelivery, error) {
+	deliveries, err := r.storage.GetDeliveries(tenantID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) ResetDelivery(tenantID int, webhookID int, id int64, now time.Time) error {
+	err := r.storage.ResetDelivery(tenantID, webhookID, id, now)
+	if err != nil {
+		return err
+	}
+	return nil
+}
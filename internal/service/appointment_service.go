@@ -4,99 +4,263 @@ import (
 	"errors"
 	"proyecto_final_go/internal/domain"
 	"proyecto_final_go/internal/repository"
+	"strings"
+	"time"
 )
 
 type AppointmentService interface {
-	Create(appointment domain.Appointment) error
-	CreateByPatientDNIAndDentistLicense(patientDNI string, license string, date string, hour string, description string) ([]domain.Appointment, error)
-	GetByID(id int) (domain.Appointment, error)
-	GetByPatientDNI(patientDNI string) ([]domain.Appointment, error)
-	GetAll() ([]domain.Appointment, error)
-	Update(appointment domain.Appointment) error
-	PatchDescription(id int, description string) error
-	Delete(id int) error
+	Create(tenantID int, appointment domain.Appointment) error
+	CreateWithPatient(tenantID int, patient domain.Patient, appointment domain.Appointment) error
+	CreateByPatientDNIAndDentistLicense(tenantID int, patientDNI string, license string, appointment domain.Appointment) ([]domain.Appointment, error)
+	GetByID(tenantID int, id int) (domain.Appointment, error)
+	GetByPatientDNI(tenantID int, patientDNI string) ([]domain.Appointment, error)
+	GetAll(tenantID int) ([]domain.Appointment, error)
+	Search(tenantID int, filter domain.AppointmentFilter) ([]domain.Appointment, error)
+	Each(tenantID int, filter domain.AppointmentFilter, fn func(domain.Appointment) error) error
+	Update(tenantID int, appointment domain.Appointment) error
+	PatchDescription(tenantID int, id int, description string) error
+	Delete(tenantID int, id int) error
+	CheckIn(tenantID int, id int) (domain.Appointment, error)
+	Complete(tenantID int, id int) (domain.Appointment, error)
 }
 
 // -------------------------------------------
 type appointmentService struct {
 	appointmentRepo repository.AppointmentRepository
+	dentistRepo     repository.DentistRepository
+	treatmentRepo   repository.TreatmentRepository
+	resourceRepo    repository.ResourceRepository
+	clinicRepo      repository.ClinicRepository
+	scheduleRepo    repository.ScheduleRepository
+	historyRepo     repository.HistoryRepository
+	planRepo        repository.PlanRepository
+	consentRepo     repository.ConsentRepository
 }
 
-func NewAppointmentService(appointmentRepo repository.AppointmentRepository) AppointmentService {
-	return &appointmentService{appointmentRepo}
+func NewAppointmentService(appointmentRepo repository.AppointmentRepository, dentistRepo repository.DentistRepository, treatmentRepo repository.TreatmentRepository, resourceRepo repository.ResourceRepository, clinicRepo repository.ClinicRepository, scheduleRepo repository.ScheduleRepository, historyRepo repository.HistoryRepository, planRepo repository.PlanRepository, consentRepo repository.ConsentRepository) AppointmentService {
+	return &appointmentService{appointmentRepo, dentistRepo, treatmentRepo, resourceRepo, clinicRepo, scheduleRepo, historyRepo, planRepo, consentRepo}
 }
 
 // -------------------------------------------
-func (s *appointmentService) Create(appointment domain.Appointment) error {
-	existingAppointments, err := s.appointmentRepo.GetAll()
+func (s *appointmentService) Create(tenantID int, appointment domain.Appointment) error {
+	if err := s.prepareBooking(tenantID, &appointment); err != nil {
+		return err
+	}
+	return s.appointmentRepo.Create(tenantID, appointment)
+}
+
+// CreateWithPatient books an appointment for a patient that does not exist
+// yet. The appointment goes through the same checks as Create, with the
+// patient matched by DNI, and only then the patient and the appointment are
+// created together.
+func (s *appointmentService) CreateWithPatient(tenantID int, patient domain.Patient, appointment domain.Appointment) error {
+	patient.Id = 0
+	appointment.Patient = patient
+	if err := s.prepareBooking(tenantID, &appointment); err != nil {
+		return err
+	}
+	return s.appointmentRepo.CreateWithPatient(tenantID, patient, appointment)
+}
+
+// prepareBooking checks a new appointment and fills in its clinic, start and
+// resources.
+func (s *appointmentService) prepareBooking(tenantID int, appointment *domain.Appointment) error {
+	dentist, err := s.dentistRepo.GetByID(tenantID, appointment.Dentist.Id)
 	if err != nil {
 		return err
 	}
-	for _, existingAppointment := range existingAppointments {
-		if existingAppointment.Patient.Id == appointment.Patient.Id && existingAppointment.Date == appointment.Date && existingAppointment.Hour == appointment.Hour {
-			return errors.New("Patient already has an appointment at the same date and time")
-		}
-		if existingAppointment.Dentist.Id == appointment.Dentist.Id && existingAppointment.Date == appointment.Date && existingAppointment.Hour == appointment.Hour {
-			return errors.New("Dentist already has an appointment at the same date and time")
-		}
+	if err := s.checkPlanStep(tenantID, appointment); err != nil {
+		return err
 	}
-	return s.appointmentRepo.Create(appointment)
+	if err := s.checkSpecialty(tenantID, dentist, appointment.Treatment.Id); err != nil {
+		return err
+	}
+	appointment.Clinic, appointment.StartsAt, err = s.resolveClinic(tenantID, *appointment, dentist)
+	if err != nil {
+		return err
+	}
+	existingAppointments, err := s.appointmentRepo.GetAll(tenantID)
+	if err != nil {
+		return err
+	}
+	if err := checkConflicts(*appointment, existingAppointments); err != nil {
+		return err
+	}
+	appointment.Resources, err = s.assignResources(tenantID, *appointment, existingAppointments)
+	return err
 }
 
-func (s *appointmentService) CreateByPatientDNIAndDentistLicense(patientDNI string, license string, date string, hour string, description string) ([]domain.Appointment, error) {
-	appointments, err := s.appointmentRepo.CreateByPatientDNIAndDentistLicense(patientDNI, license, date, hour, description)
+func (s *appointmentService) CreateByPatientDNIAndDentistLicense(tenantID int, patientDNI string, license string, appointment domain.Appointment) ([]domain.Appointment, error) {
+	dentist, err := s.dentistRepo.GetByLicense(tenantID, license)
+	if err != nil {
+		return nil, err
+	}
+	appointment.Patient.DNI = patientDNI
+	appointment.Dentist = dentist
+	if appointment.PlanStepId != 0 {
+		return nil, errors.New("Plan steps are booked with the patient ID, not the DNI")
+	}
+
+	if err := s.checkSpecialty(tenantID, dentist, appointment.Treatment.Id); err != nil {
+		return nil, err
+	}
+	appointment.Clinic, appointment.StartsAt, err = s.resolveClinic(tenantID, appointment, dentist)
+	if err != nil {
+		return nil, err
+	}
+	existingAppointments, err := s.appointmentRepo.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConflicts(appointment, existingAppointments); err != nil {
+		return nil, err
+	}
+	appointment.Resources, err = s.assignResources(tenantID, appointment, existingAppointments)
+	if err != nil {
+		return nil, err
+	}
+	appointments, err := s.appointmentRepo.CreateByPatientDNIAndDentistLicense(tenantID, patientDNI, license, appointment)
 	if err != nil {
 		return nil, err
 	}
 	return appointments, nil
 }
 
-func (s *appointmentService) GetByID(id int) (domain.Appointment, error) {
-	appointment, err := s.appointmentRepo.GetByID(id)
+// GetByID returns an appointment with the medical alerts of its patient that
+// apply on the day of the appointment.
+func (s *appointmentService) GetByID(tenantID int, id int) (domain.Appointment, error) {
+	appointment, err := s.appointmentRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	history, err := s.historyRepo.GetByPatient(tenantID, appointment.Patient.Id)
 	if err != nil {
 		return domain.Appointment{}, err
 	}
+	loc, err := domain.LoadLocation(appointment.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	appointment.Alerts = historyAlerts(history, appointment.StartsAt.In(loc))
+	appointment.MedicalAlert = len(appointment.Alerts) > 0
 	return appointment, nil
 }
 
-func (s *appointmentService) GetByPatientDNI(patientDNI string) ([]domain.Appointment, error) {
-	appointments, err := s.appointmentRepo.GetByPatientDNI(patientDNI)
+// checkPlanStep makes sure the plan step the appointment is booked for is
+// part of an active plan of the same patient and not done yet. The treatment
+// of the step is used when the appointment has none.
+func (s *appointmentService) checkPlanStep(tenantID int, appointment *domain.Appointment) error {
+	if appointment.PlanStepId == 0 {
+		return nil
+	}
+	plan, err := s.planRepo.GetByStep(tenantID, appointment.PlanStepId)
+	if err != nil {
+		return err
+	}
+	if plan.PatientId != appointment.Patient.Id {
+		return errors.New("The plan step belongs to another patient")
+	}
+	plan.Track()
+	if plan.Status != domain.PlanActive {
+		return errors.New("The treatment plan is " + plan.Status)
+	}
+	step, _ := plan.Step(appointment.PlanStepId)
+	if step.Status == domain.StepCompleted || step.Status == domain.StepSkipped {
+		return errors.New("The plan step is already " + step.Status)
+	}
+	if appointment.Treatment.Id == 0 {
+		appointment.Treatment = step.Treatment
+	}
+	return nil
+}
+
+func (s *appointmentService) GetByPatientDNI(tenantID int, patientDNI string) ([]domain.Appointment, error) {
+	appointments, err := s.appointmentRepo.GetByPatientDNI(tenantID, patientDNI)
 	if err != nil {
 		return nil, err
 	}
 	return appointments, nil
 }
 
-func (s *appointmentService) GetAll() ([]domain.Appointment, error) {
-	appointments, err := s.appointmentRepo.GetAll()
+func (s *appointmentService) GetAll(tenantID int) ([]domain.Appointment, error) {
+	appointments, err := s.appointmentRepo.GetAll(tenantID)
 	if err != nil {
 		return nil, err
 	}
 	return appointments, nil
 }
 
-func (s *appointmentService) Update(appointment domain.Appointment) error {
-	existingAppointment, err := s.appointmentRepo.GetByID(appointment.Id)
+// Search lists the appointments matching the filter. The filter date is a
+// local date in the filter time zone, the clinic time zone when filtering by
+// clinic, or DefaultTimeZone otherwise.
+func (s *appointmentService) Search(tenantID int, filter domain.AppointmentFilter) ([]domain.Appointment, error) {
+	filter, err := s.dateRange(tenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+	appointments, err := s.appointmentRepo.Search(tenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// Each calls fn with the appointments matching the filter, streaming them
+// from the store. The filter is read as in Search.
+func (s *appointmentService) Each(tenantID int, filter domain.AppointmentFilter, fn func(domain.Appointment) error) error {
+	filter, err := s.dateRange(tenantID, filter)
 	if err != nil {
 		return err
 	}
-	existingAppointments, err := s.appointmentRepo.GetAll()
+	return s.appointmentRepo.Each(tenantID, filter, fn)
+}
+
+// dateRange turns the local date of the filter into its From/To range.
+func (s *appointmentService) dateRange(tenantID int, filter domain.AppointmentFilter) (domain.AppointmentFilter, error) {
+	if filter.Date == "" {
+		return filter, nil
+	}
+	zone := filter.TimeZone
+	if zone == "" && filter.ClinicId != 0 {
+		clinic, err := s.clinicRepo.GetByID(tenantID, filter.ClinicId)
+		if err != nil {
+			return filter, err
+		}
+		zone = clinic.TimeZone
+	}
+	loc, err := domain.LoadLocation(zone)
+	if err != nil {
+		return filter, errors.New("Invalid time zone: " + zone)
+	}
+	day, err := time.ParseInLocation(domain.DateLayout, filter.Date, loc)
+	if err != nil {
+		return filter, errors.New("Invalid date, expected dd/MM/yyyy")
+	}
+	filter.From = day
+	filter.To = day.AddDate(0, 0, 1)
+	return filter, nil
+}
+
+func (s *appointmentService) Update(tenantID int, appointment domain.Appointment) error {
+	existingAppointment, err := s.appointmentRepo.GetByID(tenantID, appointment.Id)
 	if err != nil {
 		return err
 	}
-	for _, existing := range existingAppointments {
-		if existing.Id != appointment.Id {
-			if existing.Patient.Id == appointment.Patient.Id &&
-				existing.Date == appointment.Date &&
-				existing.Hour == appointment.Hour {
-				return errors.New("Patient already has an appointment at the same date and time")
-			}
-			if existing.Dentist.Id == appointment.Dentist.Id &&
-				existing.Date == appointment.Date &&
-				existing.Hour == appointment.Hour {
-				return errors.New("Dentist already has an appointment at the same date and time")
-			}
+	original := existingAppointment
+
+	// Date and Hour are read in the requested time zone, or in the one the
+	// appointment is currently shown in.
+	if appointment.TimeZone != "" {
+		loc, err := domain.LoadLocation(appointment.TimeZone)
+		if err != nil {
+			return errors.New("Invalid time zone: " + appointment.TimeZone)
 		}
+		existingAppointment.Localize(loc)
+	}
+	moved := (appointment.Date != "" && appointment.Date != existingAppointment.Date) ||
+		(appointment.Hour != "" && appointment.Hour != existingAppointment.Hour)
+	if moved && existingAppointment.CompletedAt != nil {
+		return errors.New("Completed appointments can not be rescheduled")
 	}
 
 	if appointment.Date != "" {
@@ -108,7 +272,47 @@ func (s *appointmentService) Update(appointment domain.Appointment) error {
 	if appointment.Description != "" {
 		existingAppointment.Description = appointment.Description
 	}
-	err = s.appointmentRepo.Update(existingAppointment)
+	if appointment.Treatment.Id != 0 {
+		existingAppointment.Treatment = appointment.Treatment
+	}
+	if appointment.Clinic.Id != 0 {
+		existingAppointment.Clinic = appointment.Clinic
+	} else if moved {
+		existingAppointment.Clinic = domain.Clinic{}
+	}
+	if appointment.Resources != nil {
+		existingAppointment.Resources = appointment.Resources
+	}
+
+	dentist, err := s.dentistRepo.GetByID(tenantID, existingAppointment.Dentist.Id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkSpecialty(tenantID, dentist, existingAppointment.Treatment.Id); err != nil {
+		return err
+	}
+	existingAppointment.Clinic, existingAppointment.StartsAt, err = s.resolveClinic(tenantID, existingAppointment, dentist)
+	if err != nil {
+		return err
+	}
+	if existingAppointment.Clinic.Id == 0 {
+		existingAppointment.Clinic = original.Clinic
+	}
+	existingAppointments, err := s.appointmentRepo.GetAll(tenantID)
+	if err != nil {
+		return err
+	}
+	if err := checkConflicts(existingAppointment, existingAppointments); err != nil {
+		return err
+	}
+	if existingAppointment.Clinic.Id != original.Clinic.Id && appointment.Resources == nil {
+		existingAppointment.Resources = nil
+	}
+	existingAppointment.Resources, err = s.assignResources(tenantID, existingAppointment, existingAppointments)
+	if err != nil {
+		return err
+	}
+	err = s.appointmentRepo.Update(tenantID, existingAppointment)
 	if err != nil {
 		return err
 	}
@@ -116,18 +320,259 @@ func (s *appointmentService) Update(appointment domain.Appointment) error {
 	return nil
 }
 
-func (s *appointmentService) PatchDescription(id int, description string) error {
-	err := s.appointmentRepo.PatchDescription(id, description)
+func (s *appointmentService) PatchDescription(tenantID int, id int, description string) error {
+	err := s.appointmentRepo.PatchDescription(tenantID, id, description)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *appointmentService) Delete(id int) error {
-	err := s.appointmentRepo.Delete(id)
+func (s *appointmentService) Delete(tenantID int, id int) error {
+	err := s.appointmentRepo.Delete(tenantID, id)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// checkSpecialty verifies that the dentist holds the specialty required by the
+// treatment, if any. A zero treatment id means no treatment was requested.
+func (s *appointmentService) checkSpecialty(tenantID int, dentist domain.Dentist, treatmentID int) error {
+	if treatmentID == 0 {
+		return nil
+	}
+	treatment, err := s.treatmentRepo.GetByID(tenantID, treatmentID)
+	if err != nil {
+		return err
+	}
+	if treatment.Specialty.Id == 0 {
+		return nil
+	}
+	for _, specialty := range dentist.Specialties {
+		if specialty.Id == treatment.Specialty.Id {
+			return nil
+		}
+	}
+	return errors.New("Dentist does not hold the specialty required by the treatment: " + treatment.Specialty.Name)
+}
+
+// checkConflicts verifies that neither the patient, the dentist nor any of the
+// reserved resources already have another appointment at the same time. Start
+// instants are compared, so appointments booked in different time zones are
+// checked correctly. The patient is matched by DNI when its id is not known yet.
+func checkConflicts(appointment domain.Appointment, existingAppointments []domain.Appointment) error {
+	for _, existing := range existingAppointments {
+		if existing.Id == appointment.Id || !existing.Overlaps(appointment) {
+			continue
+		}
+		if (appointment.Patient.Id != 0 && existing.Patient.Id == appointment.Patient.Id) ||
+			(appointment.Patient.Id == 0 && existing.Patient.DNI == appointment.Patient.DNI) {
+			return errors.New("Patient already has an appointment at the same date and time")
+		}
+		if existing.Dentist.Id == appointment.Dentist.Id {
+			return errors.New("Dentist already has an appointment at the same date and time")
+		}
+		for _, reserved := range existing.Resources {
+			for _, resource := range appointment.Resources {
+				if reserved.Id == resource.Id {
+					return errors.New("Resource " + reserved.Name + " is already reserved at the same date and time")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolveClinic checks that the dentist works at the appointment clinic for
+// the whole slot starting at the requested date and time, and returns the
+// clinic with the start of the appointment in UTC. Date and Hour are read in
+// the appointment time zone or, when none was given, in the time zone of each
+// candidate clinic; schedules are always compared in the local time of their
+// clinic. When the dentist has schedules and no clinic was requested, the
+// clinic of the matching schedule is used. Dentists without any schedule can
+// be booked at any clinic.
+func (s *appointmentService) resolveClinic(tenantID int, appointment domain.Appointment, dentist domain.Dentist) (domain.Clinic, time.Time, error) {
+	clinic := appointment.Clinic
+	if clinic.Id != 0 {
+		var err error
+		clinic, err = s.clinicRepo.GetByID(tenantID, clinic.Id)
+		if err != nil {
+			return domain.Clinic{}, time.Time{}, err
+		}
+	}
+
+	schedules, err := s.scheduleRepo.GetByDentist(tenantID, dentist.Id)
+	if err != nil {
+		return domain.Clinic{}, time.Time{}, err
+	}
+	if len(schedules) == 0 {
+		start, err := startsAt(appointment, clinic)
+		if err != nil {
+			return domain.Clinic{}, time.Time{}, err
+		}
+		return clinic, start, nil
+	}
+
+	for _, schedule := range schedules {
+		start, err := startsAt(appointment, schedule.Clinic)
+		if err != nil {
+			return domain.Clinic{}, time.Time{}, err
+		}
+		loc, err := schedule.Clinic.Location()
+		if err != nil {
+			return domain.Clinic{}, time.Time{}, err
+		}
+		if !schedule.CoversSlot(start.In(loc)) {
+			continue
+		}
+		if clinic.Id == 0 || schedule.Clinic.Id == clinic.Id {
+			return schedule.Clinic, start, nil
+		}
+		return domain.Clinic{}, time.Time{}, errors.New("Dentist works at " + schedule.Clinic.Name + " at the requested date and time")
+	}
+	return domain.Clinic{}, time.Time{}, errors.New("Dentist does not work at the requested date and time")
+}
+
+// startsAt converts the local date and hour of the appointment into UTC. They
+// are read in the appointment time zone when given, otherwise in the clinic one.
+func startsAt(appointment domain.Appointment, clinic domain.Clinic) (time.Time, error) {
+	zone := appointment.TimeZone
+	if zone == "" {
+		zone = clinic.TimeZone
+	}
+	loc, err := domain.LoadLocation(zone)
+	if err != nil {
+		return time.Time{}, errors.New("Invalid time zone: " + zone)
+	}
+	return domain.ParseLocal(appointment.Date, appointment.Hour, loc)
+}
+
+// assignResources loads the requested resources and, when no chair was
+// requested, reserves the first chair of the appointment clinic that is free
+// at the appointment time.
+func (s *appointmentService) assignResources(tenantID int, appointment domain.Appointment, existingAppointments []domain.Appointment) ([]domain.Resource, error) {
+	resources := []domain.Resource{}
+	hasChair := false
+	for _, requested := range appointment.Resources {
+		resource, err := s.resourceRepo.GetByID(tenantID, requested.Id)
+		if err != nil {
+			return nil, err
+		}
+		if resource.Clinic.Id != 0 && resource.Clinic.Id != appointment.Clinic.Id {
+			return nil, errors.New("Resource " + resource.Name + " does not belong to the appointment clinic")
+		}
+		if resource.Kind == domain.ResourceChair {
+			hasChair = true
+		}
+		resources = append(resources, resource)
+	}
+	if hasChair {
+		return resources, nil
+	}
+
+	allChairs, err := s.resourceRepo.Search(tenantID, domain.ResourceFilter{Kind: domain.ResourceChair, ClinicId: appointment.Clinic.Id})
+	if err != nil {
+		return nil, err
+	}
+	var chairs []domain.Resource
+	for _, chair := range allChairs {
+		if chair.Clinic.Id == appointment.Clinic.Id {
+			chairs = append(chairs, chair)
+		}
+	}
+	if len(chairs) == 0 {
+		return resources, nil
+	}
+
+	reserved := make(map[int]bool)
+	for _, existing := range existingAppointments {
+		if existing.Id == appointment.Id || !existing.Overlaps(appointment) {
+			continue
+		}
+		for _, resource := range existing.Resources {
+			reserved[resource.Id] = true
+		}
+	}
+	for _, chair := range chairs {
+		if !reserved[chair.Id] {
+			return append(resources, chair), nil
+		}
+	}
+	return nil, errors.New("No chair available at the same date and time")
+}
+
+// CheckIn records the arrival of the patient on the day of the appointment.
+// When the treatment requires consent, every required form has to be signed
+// first.
+func (s *appointmentService) CheckIn(tenantID int, id int) (domain.Appointment, error) {
+	appointment, err := s.appointmentRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	if appointment.CheckedInAt != nil {
+		return domain.Appointment{}, errors.New("Appointment already checked in")
+	}
+	if appointment.CompletedAt != nil {
+		return domain.Appointment{}, errors.New("Appointment already completed")
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	loc := appointmentLocation(appointment.TimeZone)
+	if now.In(loc).Format(domain.DateLayout) != appointment.StartsAt.In(loc).Format(domain.DateLayout) {
+		return domain.Appointment{}, errors.New("Appointments can only be checked in on their day")
+	}
+	if err := s.checkConsents(tenantID, appointment); err != nil {
+		return domain.Appointment{}, err
+	}
+	if err := s.appointmentRepo.CheckIn(tenantID, id, now); err != nil {
+		return domain.Appointment{}, err
+	}
+	appointment.CheckedInAt = &now
+	return appointment, nil
+}
+
+// checkConsents fails when a consent form the treatment requires is not
+// signed for the appointment.
+func (s *appointmentService) checkConsents(tenantID int, appointment domain.Appointment) error {
+	if appointment.Treatment.Id == 0 {
+		return nil
+	}
+	templates, err := s.consentRepo.GetTemplatesByTreatment(tenantID, appointment.Treatment.Id)
+	if err != nil || len(templates) == 0 {
+		return err
+	}
+	consents, err := s.consentRepo.GetByAppointment(tenantID, appointment.Id)
+	if err != nil {
+		return err
+	}
+	missing := domain.MissingConsents(templates, appointment.Treatment.Id, consents)
+	if len(missing) == 0 {
+		return nil
+	}
+	titles := make([]string, len(missing))
+	for i, template := range missing {
+		titles[i] = `"` + template.Title + `"`
+	}
+	return errors.New(appointment.Treatment.Name + " requires signed consent before check-in: " + strings.Join(titles, ", "))
+}
+
+// Complete marks an appointment that already started as completed, which
+// completes the plan step it was booked for.
+func (s *appointmentService) Complete(tenantID int, id int) (domain.Appointment, error) {
+	appointment, err := s.appointmentRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	if appointment.CompletedAt != nil {
+		return domain.Appointment{}, errors.New("Appointment already completed")
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	if now.Before(appointment.StartsAt) {
+		return domain.Appointment{}, errors.New("Appointments can not be completed before they start")
+	}
+	if err := s.appointmentRepo.Complete(tenantID, id, now); err != nil {
+		return domain.Appointment{}, err
+	}
+	appointment.CompletedAt = &now
+	return appointment, nil
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"testing"
+	"time"
+)
+
+type fakeScheduleRepository struct {
+	repository.ScheduleRepository
+	schedules []domain.Schedule
+}
+
+func (r *fakeScheduleRepository) GetByDentist(tenantID int, dentistID int) ([]domain.Schedule, error) {
+	return r.schedules, nil
+}
+
+func TestResolveClinicRequiresTheWholeSlotInTheSchedule(t *testing.T) {
+	clinic := domain.Clinic{Id: 1, Name: "Centro", TimeZone: domain.DefaultTimeZone}
+	// Tuesday from 09:00 to 13:00 in Buenos Aires.
+	schedules := &fakeScheduleRepository{schedules: []domain.Schedule{{Id: 1, Clinic: clinic, Weekday: 2, StartHour: "09:00", EndHour: "13:00"}}}
+	s := &appointmentService{scheduleRepo: schedules}
+
+	// Buenos Aires is three hours behind UTC.
+	tests := []struct {
+		hour string
+		want string
+	}{
+		{"08:59", ""},
+		{"09:00", "12:00"},
+		{"12:00", "15:00"},
+		{"12:01", ""},
+		{"12:30", ""},
+		{"13:00", ""},
+	}
+	for _, tt := range tests {
+		appointment := domain.Appointment{Date: "02/04/2024", Hour: tt.hour}
+		got, start, err := s.resolveClinic(1, appointment, domain.Dentist{Id: 1})
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("resolveClinic at %s booked a slot ending after 13:00", tt.hour)
+			}
+			continue
+		}
+		if err != nil || got.Id != clinic.Id || start.Format(domain.HourLayout) != tt.want {
+			t.Errorf("resolveClinic at %s = clinic %d at %s UTC, %v; want clinic 1 at %s", tt.hour, got.Id, start.Format(domain.HourLayout), err, tt.want)
+		}
+	}
+}
+
+func TestCheckSpecialtyRequiresTheDentistToHoldIt(t *testing.T) {
+	orthodontics := domain.Specialty{Id: 2, Name: "Ortodoncia"}
+	s := &appointmentService{treatmentRepo: &fakeTreatmentRepository{specialty: orthodontics}}
+	general := &appointmentService{treatmentRepo: &fakeTreatmentRepository{}}
+
+	specialist := domain.Dentist{Id: 1, Specialties: []domain.Specialty{{Id: 1, Name: "Endodoncia"}, orthodontics}}
+	generalist := domain.Dentist{Id: 2, Specialties: []domain.Specialty{{Id: 1, Name: "Endodoncia"}}}
+
+	if err := s.checkSpecialty(1, specialist, 5); err != nil {
+		t.Errorf("dentist holding the specialty: %v", err)
+	}
+	if err := s.checkSpecialty(1, generalist, 5); err == nil {
+		t.Error("dentist without the specialty was booked for the treatment")
+	}
+	if err := s.checkSpecialty(1, generalist, 0); err != nil {
+		t.Errorf("appointment without treatment: %v", err)
+	}
+	if err := general.checkSpecialty(1, generalist, 5); err != nil {
+		t.Errorf("treatment requiring no specialty: %v", err)
+	}
+}
+
+type fakeResourceRepository struct {
+	repository.ResourceRepository
+	resources []domain.Resource
+}
+
+func (r *fakeResourceRepository) GetByID(tenantID int, id int) (domain.Resource, error) {
+	for _, resource := range r.resources {
+		if resource.Id == id {
+			return resource, nil
+		}
+	}
+	return domain.Resource{}, errors.New("Resource not found")
+}
+
+func (r *fakeResourceRepository) Search(tenantID int, filter domain.ResourceFilter) ([]domain.Resource, error) {
+	var found []domain.Resource
+	for _, resource := range r.resources {
+		if resource.Kind == filter.Kind {
+			found = append(found, resource)
+		}
+	}
+	return found, nil
+}
+
+func TestAssignResourcesReservesAFreeChairOfTheClinic(t *testing.T) {
+	centro := domain.Clinic{Id: 1}
+	chair1 := domain.Resource{Id: 1, Name: "Sillón 1", Kind: domain.ResourceChair, Clinic: centro}
+	chair2 := domain.Resource{Id: 2, Name: "Sillón 2", Kind: domain.ResourceChair, Clinic: centro}
+	otherChair := domain.Resource{Id: 3, Name: "Sillón Norte", Kind: domain.ResourceChair, Clinic: domain.Clinic{Id: 2}}
+	xray := domain.Resource{Id: 4, Name: "Rayos X", Kind: domain.ResourceEquipment, Clinic: centro}
+	s := &appointmentService{resourceRepo: &fakeResourceRepository{resources: []domain.Resource{chair1, chair2, otherChair, xray}}}
+
+	start := time.Date(2024, 4, 2, 12, 0, 0, 0, time.UTC)
+	appointment := domain.Appointment{Clinic: centro, StartsAt: start, Resources: []domain.Resource{{Id: xray.Id}}}
+	booked := []domain.Appointment{{Id: 9, StartsAt: start.Add(30 * time.Minute), Resources: []domain.Resource{chair1}}}
+
+	got, err := s.assignResources(1, appointment, booked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Id != xray.Id || got[1].Id != chair2.Id {
+		t.Errorf("resources = %+v, want the X-ray and the free Sillón 2", got)
+	}
+
+	booked = append(booked, domain.Appointment{Id: 10, StartsAt: start, Resources: []domain.Resource{chair2}})
+	if _, err := s.assignResources(1, appointment, booked); err == nil {
+		t.Error("appointment booked with every chair of the clinic taken")
+	}
+	// Once the other appointments end, the chairs are free again.
+	appointment.StartsAt = start.Add(domain.SlotDuration + 30*time.Minute)
+	if got, err := s.assignResources(1, appointment, booked); err != nil || got[1].Id != chair1.Id {
+		t.Errorf("resources after the other appointments = %+v, %v; want Sillón 1", got, err)
+	}
+
+	appointment.Resources = []domain.Resource{{Id: otherChair.Id}}
+	if _, err := s.assignResources(1, appointment, nil); err == nil {
+		t.Error("appointment booked with a chair of another clinic")
+	}
+}
+
+func TestCheckConflictsOnAReservedResource(t *testing.T) {
+	start := time.Date(2024, 4, 2, 12, 0, 0, 0, time.UTC)
+	chair := domain.Resource{Id: 1, Name: "Sillón 1"}
+	existing := []domain.Appointment{{Id: 9, Patient: domain.Patient{Id: 1}, Dentist: domain.Dentist{Id: 1}, StartsAt: start, Resources: []domain.Resource{chair}}}
+
+	appointment := domain.Appointment{Patient: domain.Patient{Id: 2}, Dentist: domain.Dentist{Id: 2}, StartsAt: start.Add(59 * time.Minute), Resources: []domain.Resource{chair}}
+	if err := checkConflicts(appointment, existing); err == nil || err.Error() != "Resource Sillón 1 is already reserved at the same date and time" {
+		t.Errorf("checkConflicts = %v, want the chair reported", err)
+	}
+	appointment.StartsAt = start.Add(time.Hour)
+	if err := checkConflicts(appointment, existing); err != nil {
+		t.Errorf("checkConflicts of the next slot = %v", err)
+	}
+}
+
+func TestResolveClinicPicksTheClinicOfTheSchedule(t *testing.T) {
+	centro := domain.Clinic{Id: 1, Name: "Centro", TimeZone: domain.DefaultTimeZone}
+	norte := domain.Clinic{Id: 2, Name: "Norte", TimeZone: domain.DefaultTimeZone}
+	// Tuesday mornings at Centro and afternoons at Norte.
+	schedules := &fakeScheduleRepository{schedules: []domain.Schedule{
+		{Id: 1, Clinic: centro, Weekday: 2, StartHour: "09:00", EndHour: "13:00"},
+		{Id: 2, Clinic: norte, Weekday: 2, StartHour: "14:00", EndHour: "18:00"},
+	}}
+	s := &appointmentService{scheduleRepo: schedules, clinicRepo: &fakeClinicRepository{}}
+	dentist := domain.Dentist{Id: 1}
+
+	if got, _, err := s.resolveClinic(1, domain.Appointment{Date: "02/04/2024", Hour: "15:00"}, dentist); err != nil || got.Id != norte.Id {
+		t.Errorf("afternoon without a clinic = %+v, %v; want Norte", got, err)
+	}
+	if got, _, err := s.resolveClinic(1, domain.Appointment{Date: "02/04/2024", Hour: "10:00", Clinic: domain.Clinic{Id: 1}}, dentist); err != nil || got.Id != centro.Id {
+		t.Errorf("morning at Centro = %+v, %v; want Centro", got, err)
+	}
+	if _, _, err := s.resolveClinic(1, domain.Appointment{Date: "02/04/2024", Hour: "10:00", Clinic: domain.Clinic{Id: 2}}, dentist); err == nil || err.Error() != "Dentist works at Centro at the requested date and time" {
+		t.Errorf("morning at Norte = %v, want the dentist reported at Centro", err)
+	}
+	if _, _, err := s.resolveClinic(1, domain.Appointment{Date: "03/04/2024", Hour: "10:00"}, dentist); err == nil {
+		t.Error("booked on a Wednesday the dentist does not work")
+	}
+
+	// Dentists without schedules are booked at the clinic requested.
+	s.scheduleRepo = &fakeScheduleRepository{}
+	if got, _, err := s.resolveClinic(1, domain.Appointment{Date: "03/04/2024", Hour: "10:00", Clinic: domain.Clinic{Id: 2}}, dentist); err != nil || got.Id != norte.Id {
+		t.Errorf("dentist without schedules = %+v, %v; want Norte", got, err)
+	}
+}
+
+func TestResolveClinicComparesSchedulesInTheClinicTimeZone(t *testing.T) {
+	madrid := domain.Clinic{Id: 3, Name: "Madrid", TimeZone: "Europe/Madrid"}
+	schedules := &fakeScheduleRepository{schedules: []domain.Schedule{
+		{Id: 1, Clinic: madrid, Weekday: 2, StartHour: "09:00", EndHour: "13:00"},
+		{Id: 2, Clinic: madrid, Weekday: 0, StartHour: "01:00", EndHour: "06:00"},
+	}}
+	s := &appointmentService{scheduleRepo: schedules}
+	dentist := domain.Dentist{Id: 1}
+
+	// 05:00 in Buenos Aires is 10:00 in Madrid, in summer time on April 2.
+	appointment := domain.Appointment{Date: "02/04/2024", Hour: "05:00", TimeZone: domain.DefaultTimeZone}
+	got, start, err := s.resolveClinic(1, appointment, dentist)
+	if err != nil || got.Id != madrid.Id || !start.Equal(time.Date(2024, 4, 2, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("resolveClinic = %+v at %v, %v; want Madrid at 08:00 UTC", got, start, err)
+	}
+	// 09:00 in Buenos Aires is 14:00 in Madrid, after the schedule.
+	appointment.Hour = "09:00"
+	if _, _, err := s.resolveClinic(1, appointment, dentist); err == nil {
+		t.Error("booked at 14:00 in Madrid, after the schedule")
+	}
+
+	// Without a time zone the hour is read in the one of the clinic. Clocks
+	// in Madrid jump from 02:00 to 03:00 on March 31, 2024.
+	if _, start, err := s.resolveClinic(1, domain.Appointment{Date: "31/03/2024", Hour: "03:00"}, dentist); err != nil || !start.Equal(time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("03:00 after the change = %v, %v; want 01:00 UTC", start, err)
+	}
+	if _, _, err := s.resolveClinic(1, domain.Appointment{Date: "31/03/2024", Hour: "02:30"}, dentist); err == nil {
+		t.Error("booked at 02:30 on March 31, an hour that does not exist in Madrid")
+	}
+}
+
+func TestCheckConflictsComparesAppointmentsBookedInDifferentTimeZones(t *testing.T) {
+	buenosAires, err := domain.LoadLocation(domain.DefaultTimeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	madrid, err := domain.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 10:00 in Buenos Aires and 15:00 in Madrid are both 13:00 UTC.
+	booked, _ := domain.ParseLocal("02/04/2024", "10:00", buenosAires)
+	existing := []domain.Appointment{{Id: 9, Patient: domain.Patient{Id: 1}, Dentist: domain.Dentist{Id: 1}, StartsAt: booked}}
+
+	tests := []struct {
+		hour     string
+		conflict bool
+	}{
+		{"14:01", true},
+		{"15:00", true},
+		{"15:59", true},
+		{"14:00", false},
+		{"16:00", false},
+	}
+	for _, tt := range tests {
+		start, err := domain.ParseLocal("02/04/2024", tt.hour, madrid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := domain.SlotsOverlap(booked, start); got != tt.conflict {
+			t.Errorf("SlotsOverlap at %s in Madrid = %v, want %v", tt.hour, got, tt.conflict)
+		}
+		appointment := domain.Appointment{Patient: domain.Patient{Id: 2}, Dentist: domain.Dentist{Id: 1}, StartsAt: start}
+		if err := checkConflicts(appointment, existing); (err != nil) != tt.conflict {
+			t.Errorf("checkConflicts at %s in Madrid = %v, want a conflict %v", tt.hour, err, tt.conflict)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/blob"
+	"strings"
+	"time"
+)
+
+// DefaultAttachmentMaxSize is the largest file that can be attached unless
+// configured otherwise.
+const DefaultAttachmentMaxSize = 20 << 20
+
+// attachmentTypes are the content types that can be attached.
+var attachmentTypes = map[string]bool{
+	"image/jpeg":        true,
+	"image/png":         true,
+	"image/webp":        true,
+	"application/pdf":   true,
+	"application/dicom": true,
+}
+
+type AttachmentService interface {
+	Upload(ctx context.Context, tenantID int, attachment domain.Attachment, file io.ReadSeeker) (domain.Attachment, error)
+	GetByID(tenantID int, id int) (domain.Attachment, error)
+	GetByPatient(tenantID int, patientID int) ([]domain.Attachment, error)
+	GetByAppointment(tenantID int, appointmentID int) ([]domain.Attachment, error)
+	Download(ctx context.Context, tenantID int, id int) (domain.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, tenantID int, id int) error
+	MaxSize() int64
+}
+
+// -------------------------------------------
+type attachmentService struct {
+	attachmentRepo  repository.AttachmentRepository
+	patientRepo     repository.PatientRepository
+	appointmentRepo repository.AppointmentRepository
+	blobs           blob.Store
+	maxSize         int64
+}
+
+func NewAttachmentService(attachmentRepo repository.AttachmentRepository, patientRepo repository.PatientRepository, appointmentRepo repository.AppointmentRepository, blobs blob.Store, maxSize int64) AttachmentService {
+	if maxSize <= 0 {
+		maxSize = DefaultAttachmentMaxSize
+	}
+	return &attachmentService{attachmentRepo, patientRepo, appointmentRepo, blobs, maxSize}
+}
+
+//-------------------------------------------
+
+// Upload stores the file of a patient, or of an appointment when
+// AppointmentId is set. The content type is detected from the file, and its
+// checksum taken to verify it on download.
+func (s *attachmentService) Upload(ctx context.Context, tenantID int, attachment domain.Attachment, file io.ReadSeeker) (domain.Attachment, error) {
+	if attachment.AppointmentId != 0 {
+		appointment, err := s.appointmentRepo.GetByID(tenantID, attachment.AppointmentId)
+		if err != nil {
+			return domain.Attachment{}, err
+		}
+		attachment.PatientId = appointment.Patient.Id
+	} else if _, err := s.patientRepo.GetByID(tenantID, attachment.PatientId); err != nil {
+		return domain.Attachment{}, err
+	}
+	attachment.Kind = strings.ToLower(strings.TrimSpace(attachment.Kind))
+	if attachment.Kind == "" {
+		attachment.Kind = domain.AttachmentDocument
+	}
+	if !domain.ValidAttachmentKind(attachment.Kind) {
+		return domain.Attachment{}, errors.New("Invalid kind, expected xray, consent, photo or document")
+	}
+	attachment.Description = strings.TrimSpace(attachment.Description)
+
+	var err error
+	attachment.Checksum, attachment.Size, err = blob.Checksum(file)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if attachment.Size == 0 {
+		return domain.Attachment{}, errors.New("The file is empty")
+	}
+	if attachment.Size > s.maxSize {
+		return domain.Attachment{}, fmt.Errorf("The file is larger than %d bytes", s.maxSize)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return domain.Attachment{}, err
+	}
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return domain.Attachment{}, err
+	}
+	attachment.ContentType = sniffContentType(head[:n])
+	if !attachmentTypes[attachment.ContentType] {
+		return domain.Attachment{}, errors.New("Unsupported file type " + attachment.ContentType + ", expected JPEG, PNG, WebP, PDF or DICOM")
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return domain.Attachment{}, err
+	}
+
+	attachment.StorageKey, err = attachmentKey(tenantID, attachment.PatientId)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if err := s.blobs.Put(ctx, attachment.StorageKey, file, attachment.Size, attachment.Checksum); err != nil {
+		return domain.Attachment{}, err
+	}
+	attachment.UploadedAt = time.Now().UTC().Truncate(time.Second)
+	id, err := s.attachmentRepo.Create(tenantID, attachment)
+	if err != nil {
+		s.blobs.Delete(ctx, attachment.StorageKey)
+		return domain.Attachment{}, err
+	}
+	attachment.Id = id
+	return attachment, nil
+}
+
+func (s *attachmentService) GetByID(tenantID int, id int) (domain.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	return attachment, nil
+}
+
+func (s *attachmentService) GetByPatient(tenantID int, patientID int) ([]domain.Attachment, error) {
+	if _, err := s.patientRepo.GetByID(tenantID, patientID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.GetByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (s *attachmentService) GetByAppointment(tenantID int, appointmentID int) ([]domain.Attachment, error) {
+	if _, err := s.appointmentRepo.GetByID(tenantID, appointmentID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.GetByAppointment(tenantID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// Download opens the content of the attachment, to be streamed to the client
+// and closed. The content is checked against the size and checksum taken on
+// upload as it is read: a corrupted file fails with blob.ErrChecksum before
+// its last byte.
+func (s *attachmentService) Download(ctx context.Context, tenantID int, id int) (domain.Attachment, io.ReadCloser, error) {
+	attachment, err := s.attachmentRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+	r, err := s.blobs.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, blob.ErrNotFound) {
+		return domain.Attachment{}, nil, errors.New("The attachment content is missing")
+	}
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+	// Past Size the content can not match the checksum anyway, and
+	// clients would not read it.
+	limited := struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(r, attachment.Size), r}
+	return attachment, blob.Verify(limited, attachment.Checksum), nil
+}
+
+// Delete removes the attachment and then its content. Content left behind
+// when the storage fails is not referenced anymore.
+func (s *attachmentService) Delete(ctx context.Context, tenantID int, id int) error {
+	attachment, err := s.attachmentRepo.GetByID(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.attachmentRepo.Delete(tenantID, id); err != nil {
+		return err
+	}
+	return s.blobs.Delete(ctx, attachment.StorageKey)
+}
+
+func (s *attachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// sniffContentType detects the type of a file from its first bytes. DICOM
+// files, common for X-rays, start with a 128 byte preamble and "DICM".
+func sniffContentType(head []byte) string {
+	if len(head) >= 132 && bytes.Equal(head[128:132], []byte("DICM")) {
+		return "application/dicom"
+	}
+	contentType := http.DetectContentType(head)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return contentType
+}
+
+// attachmentKey builds a random, unguessable storage key grouped by tenant
+// and patient.
+func attachmentKey(tenantID int, patientID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d/%d/%s", tenantID, patientID, hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"proyecto_final_go/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	patientImportFields = []string{"FirstName", "LastName", "Address", "DNI", "ReleaseDate", "Email", "Phone"}
+	dentistImportFields = []string{"FirstName", "LastName", "License"}
+)
+
+// importRow is a CSV record or JSON Lines object keyed by lower case column
+// name.
+type importRow struct {
+	line   int
+	fields map[string]string
+	err    error
+}
+
+// ImportPatients creates patients from a CSV or JSON Lines file. Rows whose
+// DNI already exists, or repeats an earlier row, are skipped.
+func (s *importService) ImportPatients(tenantID int, r io.Reader, options domain.BulkImportOptions) (domain.ImportReport, error) {
+	mapping, err := importMapping(options.Mapping, patientImportFields)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	rows, err := readImportRows(r, options.Format)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+
+	seen := make(map[string]int)
+	check := func(row importRow) (domain.Patient, string, error) {
+		p := domain.Patient{
+			FirstName:   mapping.value(row, "FirstName"),
+			LastName:    mapping.value(row, "LastName"),
+			Address:     mapping.value(row, "Address"),
+			DNI:         mapping.value(row, "DNI"),
+			ReleaseDate: mapping.value(row, "ReleaseDate"),
+			Email:       mapping.value(row, "Email"),
+			Phone:       mapping.value(row, "Phone"),
+		}
+		var problems []string
+		problems = append(problems, missingFields(map[string]string{
+			"FirstName": p.FirstName, "LastName": p.LastName, "Address": p.Address, "DNI": p.DNI, "ReleaseDate": p.ReleaseDate,
+		}, patientImportFields[:5])...)
+		problems = append(problems, longFields(map[string]string{
+			"FirstName": p.FirstName, "LastName": p.LastName, "Address": p.Address, "DNI": p.DNI, "ReleaseDate": p.ReleaseDate,
+		}, patientImportFields[:5], maxNameLength)...)
+		problems = append(problems, longFields(map[string]string{"Email": p.Email}, []string{"Email"}, maxEmailLength)...)
+		if p.ReleaseDate != "" {
+			if _, err := time.Parse(domain.DateLayout, p.ReleaseDate); err != nil {
+				problems = append(problems, "Invalid ReleaseDate, expected dd/MM/yyyy")
+			}
+		}
+		if err := validateContact(p); err != nil {
+			problems = append(problems, err.Error())
+		}
+		if len(problems) > 0 {
+			return p, "", errors.New(strings.Join(problems, "; "))
+		}
+		if line, ok := seen[p.DNI]; ok {
+			return p, "DNI repeated from line " + strconv.Itoa(line), nil
+		}
+		seen[p.DNI] = row.line
+		exists, err := s.patientRepo.Exists(tenantID, p.DNI)
+		if err != nil {
+			return p, "", err
+		}
+		if exists {
+			return p, "DNI already exists", nil
+		}
+		return p, "", nil
+	}
+	create := func(p domain.Patient) error { return s.patientRepo.Create(tenantID, p) }
+	createBatch := func(patients []domain.Patient) error { return s.patientRepo.CreateBatch(tenantID, patients) }
+	return bulkImport(rows, options, check, create, createBatch), nil
+}
+
+// ImportDentists creates dentists from a CSV or JSON Lines file. Rows whose
+// license already exists, or repeats an earlier row, are skipped.
+func (s *importService) ImportDentists(tenantID int, r io.Reader, options domain.BulkImportOptions) (domain.ImportReport, error) {
+	mapping, err := importMapping(options.Mapping, dentistImportFields)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	rows, err := readImportRows(r, options.Format)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+
+	seen := make(map[string]int)
+	check := func(row importRow) (domain.Dentist, string, error) {
+		d := domain.Dentist{
+			FirstName: mapping.value(row, "FirstName"),
+			LastName:  mapping.value(row, "LastName"),
+			License:   mapping.value(row, "License"),
+		}
+		problems := missingFields(map[string]string{
+			"FirstName": d.FirstName, "LastName": d.LastName, "License": d.License,
+		}, dentistImportFields)
+		problems = append(problems, longFields(map[string]string{
+			"FirstName": d.FirstName, "LastName": d.LastName, "License": d.License,
+		}, dentistImportFields, maxNameLength)...)
+		if len(problems) > 0 {
+			return d, "", errors.New(strings.Join(problems, "; "))
+		}
+		if line, ok := seen[d.License]; ok {
+			return d, "License repeated from line " + strconv.Itoa(line), nil
+		}
+		seen[d.License] = row.line
+		exists, err := s.dentistRepo.Exists(tenantID, d.License)
+		if err != nil {
+			return d, "", err
+		}
+		if exists {
+			return d, "License already exists", nil
+		}
+		return d, "", nil
+	}
+	create := func(d domain.Dentist) error { return s.dentistRepo.Create(tenantID, d) }
+	createBatch := func(dentists []domain.Dentist) error { return s.dentistRepo.CreateBatch(tenantID, dentists) }
+	return bulkImport(rows, options, check, create, createBatch), nil
+}
+
+// bulkImport validates every row with check, which returns the value to
+// create, or why the row is skipped, or why it is invalid, and creates the
+// valid rows one by one or, in atomic mode, in a single transaction.
+func bulkImport[T any](rows []importRow, options domain.BulkImportOptions, check func(importRow) (T, string, error), create func(T) error, createBatch func([]T) error) domain.ImportReport {
+	items := make([]domain.ImportItem, len(rows))
+	var values []T
+	var pending []int
+	invalid := false
+	for i, row := range rows {
+		items[i] = domain.ImportItem{Ref: "line " + strconv.Itoa(row.line)}
+		if row.err != nil {
+			items[i].Status, items[i].Reason = domain.ImportInvalid, row.err.Error()
+			invalid = true
+			continue
+		}
+		value, skip, err := check(row)
+		switch {
+		case err != nil:
+			items[i].Status, items[i].Reason = domain.ImportInvalid, err.Error()
+			invalid = true
+		case skip != "":
+			items[i].Status, items[i].Reason = domain.ImportSkipped, skip
+		default:
+			items[i].Status = domain.ImportImported
+			values = append(values, value)
+			pending = append(pending, i)
+		}
+	}
+
+	switch {
+	case options.DryRun:
+	case options.Atomic && invalid:
+		for _, i := range pending {
+			items[i].Status, items[i].Reason = domain.ImportSkipped, "Not imported, the file has invalid rows"
+		}
+	case options.Atomic && len(values) > 0:
+		if err := createBatch(values); err != nil {
+			for _, i := range pending {
+				items[i].Status, items[i].Reason = domain.ImportConflict, err.Error()
+			}
+		}
+	default:
+		for n, i := range pending {
+			if err := create(values[n]); err != nil {
+				items[i].Status, items[i].Reason = domain.ImportConflict, err.Error()
+			}
+		}
+	}
+
+	report := domain.ImportReport{DryRun: options.DryRun, Items: []domain.ImportItem{}}
+	for _, item := range items {
+		report.Add(item)
+	}
+	return report
+}
+
+// columnMapping resolves the column of each field.
+type columnMapping map[string]string
+
+func importMapping(mapping map[string]string, fields []string) (columnMapping, error) {
+	columns := make(columnMapping)
+	for _, field := range fields {
+		columns[field] = strings.ToLower(field)
+	}
+	for field, column := range mapping {
+		known := false
+		for _, f := range fields {
+			if strings.EqualFold(f, field) {
+				columns[f] = strings.ToLower(strings.TrimSpace(column))
+				known = true
+			}
+		}
+		if !known {
+			return nil, errors.New("Unknown field in mapping: " + field + ", expected one of " + strings.Join(fields, ", "))
+		}
+	}
+	return columns, nil
+}
+
+func (m columnMapping) value(row importRow, field string) string {
+	return strings.TrimSpace(row.fields[m[field]])
+}
+
+func missingFields(values map[string]string, required []string) []string {
+	var problems []string
+	for _, field := range required {
+		if values[field] == "" {
+			problems = append(problems, "Missing "+field)
+		}
+	}
+	return problems
+}
+
+// longFields reports the fields longer than their column, limit characters.
+func longFields(values map[string]string, fields []string, limit int) []string {
+	var problems []string
+	for _, field := range fields {
+		if utf8.RuneCountInString(values[field]) > limit {
+			problems = append(problems, field+" is longer than "+strconv.Itoa(limit)+" characters")
+		}
+	}
+	return problems
+}
+
+// readImportRows reads a CSV file with a header row or a JSON Lines file.
+// Malformed rows are returned with their error so they are reported, not
+// fatal.
+func readImportRows(r io.Reader, format string) ([]importRow, error) {
+	switch format {
+	case domain.FormatJSONL:
+		return readJSONLines(r)
+	case domain.FormatCSV, "":
+		return readCSV(r)
+	}
+	return nil, errors.New("Unknown import format " + format + ", expected csv or jsonl")
+}
+
+func readCSV(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("Invalid CSV header: " + err.Error())
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, importRow{line: parseErr.StartLine, err: errors.New("Invalid CSV: " + parseErr.Err.Error())})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		row := importRow{line: line, fields: make(map[string]string)}
+		for i, value := range record {
+			if i < len(header) {
+				row.fields[header[i]] = value
+			}
+		}
+		if len(record) != len(header) {
+			row.err = fmt.Errorf("Expected %d columns, got %d", len(header), len(record))
+		}
+		rows = append(rows, row)
+	}
+}
+
+func readJSONLines(r io.Reader) ([]importRow, error) {
+	var rows []importRow
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var object map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.UseNumber()
+		if err := decoder.Decode(&object); err != nil {
+			rows = append(rows, importRow{line: line, err: errors.New("Invalid JSON: " + err.Error())})
+			continue
+		}
+		row := importRow{line: line, fields: make(map[string]string)}
+		for key, value := range object {
+			if value != nil {
+				row.fields[strings.ToLower(key)] = fmt.Sprint(value)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
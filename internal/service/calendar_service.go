@@ -0,0 +1,215 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/ical"
+	"time"
+)
+
+// calendarHistory is how far back the feeds include past appointments.
+const calendarHistory = 90 * 24 * time.Hour
+
+// ErrInvalidFeedToken is returned when a feed token does not grant access to
+// the requested calendar.
+var ErrInvalidFeedToken = errors.New("Invalid calendar token")
+
+type CalendarService interface {
+	IssueToken(tenantID int, ownerType string, ownerID int) (string, error)
+	RevokeToken(tenantID int, ownerType string, ownerID int) error
+	Feed(token string, ownerType string, ownerID int) (ical.Calendar, error)
+}
+
+// -------------------------------------------
+type calendarService struct {
+	calendarRepo    repository.CalendarRepository
+	appointmentRepo repository.AppointmentRepository
+	dentistRepo     repository.DentistRepository
+	patientRepo     repository.PatientRepository
+	eventRepo       repository.EventRepository
+}
+
+func NewCalendarService(calendarRepo repository.CalendarRepository, appointmentRepo repository.AppointmentRepository, dentistRepo repository.DentistRepository, patientRepo repository.PatientRepository, eventRepo repository.EventRepository) CalendarService {
+	return &calendarService{calendarRepo, appointmentRepo, dentistRepo, patientRepo, eventRepo}
+}
+
+//-------------------------------------------
+
+// IssueToken creates the secret token of a dentist or patient feed. Issuing a
+// new token revokes the previous one.
+func (s *calendarService) IssueToken(tenantID int, ownerType string, ownerID int) (string, error) {
+	if _, err := s.ownerName(tenantID, ownerType, ownerID); err != nil {
+		return "", err
+	}
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	owner := domain.CalendarToken{TenantId: tenantID, OwnerType: ownerType, OwnerId: ownerID}
+	if err := s.calendarRepo.Save(owner, hashToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *calendarService) RevokeToken(tenantID int, ownerType string, ownerID int) error {
+	if _, err := s.ownerName(tenantID, ownerType, ownerID); err != nil {
+		return err
+	}
+	return s.calendarRepo.Delete(domain.CalendarToken{TenantId: tenantID, OwnerType: ownerType, OwnerId: ownerID})
+}
+
+// Feed builds the calendar of a dentist or patient: the appointments of the
+// last days and the upcoming ones, plus the appointments cancelled in that
+// period so subscribed clients remove them.
+func (s *calendarService) Feed(token string, ownerType string, ownerID int) (ical.Calendar, error) {
+	owner, err := s.calendarRepo.GetByTokenHash(hashToken(token))
+	if err != nil || owner.OwnerType != ownerType || owner.OwnerId != ownerID {
+		return ical.Calendar{}, ErrInvalidFeedToken
+	}
+	tenantID := owner.TenantId
+	name, err := s.ownerName(tenantID, ownerType, ownerID)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+
+	now := time.Now().UTC()
+	from := now.Add(-calendarHistory)
+	filter := domain.AppointmentFilter{From: from}
+	if ownerType == domain.CalendarOwnerDentist {
+		filter.DentistId = ownerID
+	} else {
+		filter.PatientId = ownerID
+	}
+	appointments, err := s.appointmentRepo.Search(tenantID, filter)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	cancelled, err := s.cancelledAppointments(tenantID, ownerType, ownerID, from)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+
+	ids := make([]int, 0, len(appointments)+len(cancelled))
+	for _, appointment := range appointments {
+		ids = append(ids, appointment.Id)
+	}
+	for _, event := range cancelled {
+		ids = append(ids, event.AppointmentId)
+	}
+	// Every change of an appointment is an outbox event, so the number of
+	// events is a stable, increasing SEQUENCE.
+	changes, err := s.eventRepo.CountByAggregate(tenantID, "appointment", ids)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	sequence := func(id int) int {
+		if changes[id] > 1 {
+			return changes[id] - 1
+		}
+		return 0
+	}
+
+	calendar := ical.Calendar{
+		ProdID:          "-//Proyecto Final Go//Appointments//EN",
+		Name:            name,
+		RefreshInterval: 15 * time.Minute,
+	}
+	for _, appointment := range appointments {
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         calendarUID(tenantID, appointment.Id),
+			Sequence:    sequence(appointment.Id),
+			Stamp:       now,
+			Start:       appointment.StartsAt,
+			End:         appointment.StartsAt.Add(domain.SlotDuration),
+			Summary:     calendarSummary(ownerType, appointment),
+			Description: appointment.Description,
+			Location:    calendarLocation(appointment.Clinic),
+			Status:      ical.StatusConfirmed,
+		})
+	}
+	for _, event := range cancelled {
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         calendarUID(tenantID, event.AppointmentId),
+			Sequence:    sequence(event.AppointmentId),
+			Stamp:       now,
+			Start:       event.StartsAt,
+			End:         event.StartsAt.Add(domain.SlotDuration),
+			Summary:     "Cancelled appointment",
+			Description: event.Description,
+			Status:      ical.StatusCancelled,
+		})
+	}
+	return calendar, nil
+}
+
+// cancelledAppointments reads the appointments of the owner starting after
+// from that were cancelled, from the outbox since they are deleted from the
+// appointment store.
+func (s *calendarService) cancelledAppointments(tenantID int, ownerType string, ownerID int, from time.Time) ([]domain.AppointmentEvent, error) {
+	events, err := s.eventRepo.GetByType(tenantID, domain.EventAppointmentCancelled, from)
+	if err != nil {
+		return nil, err
+	}
+	var cancelled []domain.AppointmentEvent
+	for _, event := range events {
+		var payload domain.AppointmentEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, err
+		}
+		owned := payload.DentistId == ownerID
+		if ownerType == domain.CalendarOwnerPatient {
+			owned = payload.PatientId == ownerID
+		}
+		if owned && !payload.StartsAt.Before(from) {
+			cancelled = append(cancelled, payload)
+		}
+	}
+	return cancelled, nil
+}
+
+func (s *calendarService) ownerName(tenantID int, ownerType string, ownerID int) (string, error) {
+	switch ownerType {
+	case domain.CalendarOwnerDentist:
+		dentist, err := s.dentistRepo.GetByID(tenantID, ownerID)
+		if err != nil {
+			return "", err
+		}
+		return "Dr. " + dentist.FirstName + " " + dentist.LastName, nil
+	case domain.CalendarOwnerPatient:
+		patient, err := s.patientRepo.GetByID(tenantID, ownerID)
+		if err != nil {
+			return "", err
+		}
+		return patient.FirstName + " " + patient.LastName + " - dental appointments", nil
+	}
+	return "", errors.New("Unknown calendar owner " + ownerType)
+}
+
+// calendarUID identifies an appointment across feeds and updates.
+func calendarUID(tenantID int, appointmentID int) string {
+	return fmt.Sprintf("appointment-%d-%d@proyecto-final-go", tenantID, appointmentID)
+}
+
+func calendarSummary(ownerType string, appointment domain.Appointment) string {
+	var summary string
+	if ownerType == domain.CalendarOwnerDentist {
+		summary = appointment.Patient.FirstName + " " + appointment.Patient.LastName
+	} else {
+		summary = "Dentist: Dr. " + appointment.Dentist.FirstName + " " + appointment.Dentist.LastName
+	}
+	if appointment.Treatment.Name != "" {
+		summary += " - " + appointment.Treatment.Name
+	}
+	return summary
+}
+
+func calendarLocation(clinic domain.Clinic) string {
+	if clinic.Id == 0 {
+		return ""
+	}
+	return clinic.Name + ", " + clinic.Address
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"encoding/json"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/ical"
+	"testing"
+	"time"
+)
+
+type fakeCalendarRepository struct {
+	repository.CalendarRepository
+	tokens map[string]domain.CalendarToken
+}
+
+func (r *fakeCalendarRepository) Save(token domain.CalendarToken, tokenHash string) error {
+	for hash, saved := range r.tokens {
+		if saved == token {
+			delete(r.tokens, hash)
+		}
+	}
+	r.tokens[tokenHash] = token
+	return nil
+}
+
+func (r *fakeCalendarRepository) GetByTokenHash(tokenHash string) (domain.CalendarToken, error) {
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return domain.CalendarToken{}, ErrInvalidFeedToken
+	}
+	return token, nil
+}
+
+func appointmentEvent(id int64, eventType string, payload domain.AppointmentEvent) domain.Event {
+	data, _ := json.Marshal(payload)
+	return domain.Event{Id: id, TenantId: 1, AggregateType: "appointment", AggregateId: payload.AppointmentId, Type: eventType, Payload: data, CreatedAt: time.Now().UTC()}
+}
+
+func TestFeedListsTheAppointmentsOfTheOwnerAndTheCancelledOnes(t *testing.T) {
+	tomorrow := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	booked := domain.Appointment{Id: 3, Patient: domain.Patient{Id: 1, FirstName: "Juan", LastName: "Perez"}, Dentist: domain.Dentist{Id: 1}, StartsAt: tomorrow}
+	rescheduled := domain.AppointmentEvent{AppointmentId: 3, PatientId: 1, DentistId: 1, StartsAt: tomorrow}
+	cancelled := domain.AppointmentEvent{AppointmentId: 4, PatientId: 2, DentistId: 1, StartsAt: tomorrow.Add(time.Hour)}
+	otherDentist := domain.AppointmentEvent{AppointmentId: 5, PatientId: 2, DentistId: 2, StartsAt: tomorrow}
+	events := &fakeEventRepository{events: []domain.Event{
+		appointmentEvent(1, domain.EventAppointmentCreated, rescheduled),
+		appointmentEvent(2, domain.EventAppointmentRescheduled, rescheduled),
+		appointmentEvent(3, domain.EventAppointmentCreated, cancelled),
+		appointmentEvent(4, domain.EventAppointmentCancelled, cancelled),
+		appointmentEvent(5, domain.EventAppointmentCancelled, otherDentist),
+	}}
+	events.commit(len(events.events))
+	s := NewCalendarService(&fakeCalendarRepository{tokens: map[string]domain.CalendarToken{}},
+		&fakeAppointmentRepository{appointments: []domain.Appointment{booked}}, &fakeDentistRepository{}, nil, events)
+
+	token, err := s.IssueToken(1, domain.CalendarOwnerDentist, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calendar, err := s.Feed(token, domain.CalendarOwnerDentist, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calendar.Events) != 2 {
+		t.Fatalf("events = %+v, want the booked and the cancelled appointment", calendar.Events)
+	}
+	first, second := calendar.Events[0], calendar.Events[1]
+	if first.UID != calendarUID(1, 3) || first.Status != ical.StatusConfirmed || first.Sequence != 1 || !first.End.Equal(tomorrow.Add(domain.SlotDuration)) {
+		t.Errorf("booked event = %+v, want appointment 3 confirmed at sequence 1", first)
+	}
+	if second.UID != calendarUID(1, 4) || second.Status != ical.StatusCancelled || second.Sequence != 1 {
+		t.Errorf("cancelled event = %+v, want appointment 4 cancelled at sequence 1", second)
+	}
+
+	if _, err := s.Feed(token, domain.CalendarOwnerDentist, 2); err != ErrInvalidFeedToken {
+		t.Errorf("feed of another dentist = %v, want ErrInvalidFeedToken", err)
+	}
+	renewed, err := s.IssueToken(1, domain.CalendarOwnerDentist, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Feed(token, domain.CalendarOwnerDentist, 1); err != ErrInvalidFeedToken {
+		t.Errorf("feed with the revoked token = %v, want ErrInvalidFeedToken", err)
+	}
+	if _, err := s.Feed(renewed, domain.CalendarOwnerDentist, 1); err != nil {
+		t.Errorf("feed with the new token = %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+)
+
+type ClinicService interface {
+	Create(tenantID int, clinic domain.Clinic) error
+	GetByID(tenantID int, id int) (domain.Clinic, error)
+	GetAll(tenantID int) ([]domain.Clinic, error)
+	Update(tenantID int, clinic domain.Clinic) error
+	Delete(tenantID int, id int) error
+}
+
+// -------------------------------------------
+type clinicService struct {
+	clinicRepo repository.ClinicRepository
+}
+
+func NewClinicService(clinicRepo repository.ClinicRepository) ClinicService {
+	return &clinicService{clinicRepo}
+}
+
+// -------------------------------------------
+func (s *clinicService) Create(tenantID int, clinic domain.Clinic) error {
+	if clinic.TimeZone == "" {
+		clinic.TimeZone = domain.DefaultTimeZone
+	}
+	if _, err := clinic.Location(); err != nil {
+		return errors.New("Invalid time zone: " + clinic.TimeZone)
+	}
+	err := s.clinicRepo.Create(tenantID, clinic)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *clinicService) GetByID(tenantID int, id int) (domain.Clinic, error) {
+	clinic, err := s.clinicRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Clinic{}, err
+	}
+	return clinic, nil
+}
+
+func (s *clinicService) GetAll(tenantID int) ([]domain.Clinic, error) {
+	clinics, err := s.clinicRepo.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return clinics, nil
+}
+
+func (s *clinicService) Update(tenantID int, clinic domain.Clinic) error {
+	existingClinic, err := s.clinicRepo.GetByID(tenantID, clinic.Id)
+	if err != nil {
+		return err
+	}
+	if clinic.Name != "" {
+		existingClinic.Name = clinic.Name
+	}
+	if clinic.Address != "" {
+		existingClinic.Address = clinic.Address
+	}
+	if clinic.TimeZone != "" {
+		if _, err := clinic.Location(); err != nil {
+			return errors.New("Invalid time zone: " + clinic.TimeZone)
+		}
+		existingClinic.TimeZone = clinic.TimeZone
+	}
+	err = s.clinicRepo.Update(tenantID, existingClinic)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *clinicService) Delete(tenantID int, id int) error {
+	err := s.clinicRepo.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/blob"
+	"proyecto_final_go/pkg/pdf"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSignaturePoints bounds the size of a signature, and of the document
+// it is drawn on.
+const maxSignaturePoints = 5000
+
+// ErrConsentCorrupted is returned when a signed document no longer matches
+// the hash taken when it was signed.
+var ErrConsentCorrupted = errors.New("The signed consent document is corrupted")
+
+type ConsentService interface {
+	CreateTemplate(tenantID int, template domain.ConsentTemplate) (domain.ConsentTemplate, error)
+	GetTemplate(tenantID int, id int) (domain.ConsentTemplate, error)
+	GetTemplates(tenantID int) ([]domain.ConsentTemplate, error)
+	UpdateTemplate(tenantID int, template domain.ConsentTemplate) (domain.ConsentTemplate, error)
+	GetByID(tenantID int, id int) (domain.Consent, error)
+	GetByAppointment(tenantID int, appointmentID int) ([]domain.Consent, error)
+	Prepare(tenantID int, appointmentID int, templateID int) (domain.Consent, error)
+	Sign(ctx context.Context, tenantID int, id int, signature domain.ConsentSignature) (domain.Consent, error)
+	Document(ctx context.Context, tenantID int, id int) (domain.Consent, []byte, error)
+}
+
+// -------------------------------------------
+type consentService struct {
+	consentRepo     repository.ConsentRepository
+	appointmentRepo repository.AppointmentRepository
+	treatmentRepo   repository.TreatmentRepository
+	tenantRepo      repository.TenantRepository
+	blobs           blob.Store
+}
+
+func NewConsentService(consentRepo repository.ConsentRepository, appointmentRepo repository.AppointmentRepository, treatmentRepo repository.TreatmentRepository, tenantRepo repository.TenantRepository, blobs blob.Store) ConsentService {
+	return &consentService{consentRepo, appointmentRepo, treatmentRepo, tenantRepo, blobs}
+}
+
+//-------------------------------------------
+
+func (s *consentService) CreateTemplate(tenantID int, template domain.ConsentTemplate) (domain.ConsentTemplate, error) {
+	if err := s.validateTemplate(tenantID, &template); err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	template.Active = true
+	template.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	id, err := s.consentRepo.CreateTemplate(tenantID, template)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	return s.consentRepo.GetTemplate(tenantID, id)
+}
+
+func (s *consentService) GetTemplate(tenantID int, id int) (domain.ConsentTemplate, error) {
+	template, err := s.consentRepo.GetTemplate(tenantID, id)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	return template, nil
+}
+
+func (s *consentService) GetTemplates(tenantID int) ([]domain.ConsentTemplate, error) {
+	templates, err := s.consentRepo.GetTemplates(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// UpdateTemplate replaces a template. Consents already prepared keep the text
+// they were prepared with.
+func (s *consentService) UpdateTemplate(tenantID int, template domain.ConsentTemplate) (domain.ConsentTemplate, error) {
+	if _, err := s.consentRepo.GetTemplate(tenantID, template.Id); err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	if err := s.validateTemplate(tenantID, &template); err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	template.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	if err := s.consentRepo.UpdateTemplate(tenantID, template); err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	return s.consentRepo.GetTemplate(tenantID, template.Id)
+}
+
+func (s *consentService) validateTemplate(tenantID int, template *domain.ConsentTemplate) error {
+	template.Title = strings.TrimSpace(template.Title)
+	template.Body = strings.TrimSpace(template.Body)
+	if template.Title == "" || template.Body == "" {
+		return errors.New("Title and Body are required")
+	}
+	if len(template.Title) > 255 {
+		return errors.New("Title can not be longer than 255 characters")
+	}
+	seen := map[int]bool{}
+	treatments := []domain.Treatment{}
+	for _, t := range template.Treatments {
+		if seen[t.Id] {
+			continue
+		}
+		seen[t.Id] = true
+		treatment, err := s.treatmentRepo.GetByID(tenantID, t.Id)
+		if err != nil {
+			return err
+		}
+		treatments = append(treatments, treatment)
+	}
+	template.Treatments = treatments
+	return nil
+}
+
+func (s *consentService) GetByID(tenantID int, id int) (domain.Consent, error) {
+	consent, err := s.consentRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	return consent, nil
+}
+
+// GetByAppointment lists the consents of an appointment. Those its treatment
+// requires are prepared when the appointment is booked or changed; others are
+// added with Prepare.
+func (s *consentService) GetByAppointment(tenantID int, appointmentID int) ([]domain.Consent, error) {
+	if _, err := s.appointmentRepo.GetByID(tenantID, appointmentID); err != nil {
+		return nil, err
+	}
+	consents, err := s.consentRepo.GetByAppointment(tenantID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	return consents, nil
+}
+
+// Prepare adds the consent of a template to an appointment, whether or not
+// its treatment requires it.
+func (s *consentService) Prepare(tenantID int, appointmentID int, templateID int) (domain.Consent, error) {
+	appointment, err := s.appointmentRepo.GetByID(tenantID, appointmentID)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	if appointment.CompletedAt != nil {
+		return domain.Consent{}, errors.New("The appointment is already completed")
+	}
+	template, err := s.consentRepo.GetTemplate(tenantID, templateID)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	if !template.Active {
+		return domain.Consent{}, errors.New("The consent template is not active")
+	}
+	return s.prepare(tenantID, appointment, template)
+}
+
+// prepare copies the text of the template, which is what the patient signs
+// even if the template changes later.
+func (s *consentService) prepare(tenantID int, appointment domain.Appointment, template domain.ConsentTemplate) (domain.Consent, error) {
+	consent := domain.Consent{
+		AppointmentId: appointment.Id,
+		PatientId:     appointment.Patient.Id,
+		TemplateId:    template.Id,
+		Title:         template.Title,
+		Body:          template.Body,
+		Status:        domain.ConsentPending,
+		CreatedAt:     time.Now().UTC().Truncate(time.Second),
+	}
+	id, err := s.consentRepo.Create(tenantID, consent)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	consent.Id = id
+	return consent, nil
+}
+
+// Sign renders the consent with the signature drawn on it as a PDF, stores
+// the document and records who signed it, when, and the hash of the document.
+func (s *consentService) Sign(ctx context.Context, tenantID int, id int, signature domain.ConsentSignature) (domain.Consent, error) {
+	consent, err := s.consentRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	if consent.Status == domain.ConsentSigned {
+		return domain.Consent{}, errors.New("Consent already signed")
+	}
+	appointment, err := s.appointmentRepo.GetByID(tenantID, consent.AppointmentId)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	if appointment.CompletedAt != nil {
+		return domain.Consent{}, errors.New("Consents can not be signed once the appointment is completed")
+	}
+	if err := validateSignature(&signature); err != nil {
+		return domain.Consent{}, err
+	}
+	header := appointment.Clinic.Name
+	if header == "" {
+		tenant, err := s.tenantRepo.GetByID(tenantID)
+		if err != nil {
+			return domain.Consent{}, err
+		}
+		header = tenant.Name
+	}
+
+	signedAt := time.Now().UTC().Truncate(time.Second)
+	consent.SignerName = signature.SignerName
+	consent.SignerRelationship = signature.SignerRelationship
+	consent.SignedAt = &signedAt
+	doc := renderConsent(header, appointment, consent, signature)
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		return domain.Consent{}, err
+	}
+	document := buf.Bytes()
+	hash, size, err := blob.Checksum(bytes.NewReader(document))
+	if err != nil {
+		return domain.Consent{}, err
+	}
+
+	key, err := attachmentKey(tenantID, consent.PatientId)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	if err := s.blobs.Put(ctx, key, bytes.NewReader(document), size, hash); err != nil {
+		return domain.Consent{}, err
+	}
+	consent.Status = domain.ConsentSigned
+	consent.DocumentHash = hash
+	consent.StorageKey = key
+	if err := s.consentRepo.Sign(tenantID, consent); err != nil {
+		s.blobs.Delete(ctx, key)
+		return domain.Consent{}, err
+	}
+	return consent, nil
+}
+
+// Document reads the signed document of a consent, checking it still matches
+// the hash taken when it was signed.
+func (s *consentService) Document(ctx context.Context, tenantID int, id int) (domain.Consent, []byte, error) {
+	consent, err := s.consentRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Consent{}, nil, err
+	}
+	if consent.Status != domain.ConsentSigned {
+		return domain.Consent{}, nil, errors.New("The consent is not signed yet")
+	}
+	r, err := s.blobs.Get(ctx, consent.StorageKey)
+	if errors.Is(err, blob.ErrNotFound) {
+		return domain.Consent{}, nil, errors.New("The signed consent document is missing")
+	}
+	if err != nil {
+		return domain.Consent{}, nil, err
+	}
+	verified := blob.Verify(r, consent.DocumentHash)
+	defer verified.Close()
+
+	document, err := io.ReadAll(verified)
+	if errors.Is(err, blob.ErrChecksum) {
+		return domain.Consent{}, nil, ErrConsentCorrupted
+	}
+	if err != nil {
+		return domain.Consent{}, nil, err
+	}
+	return consent, document, nil
+}
+
+// validateSignature checks the signer and keeps the points of the signature
+// within its pad.
+func validateSignature(signature *domain.ConsentSignature) error {
+	signature.SignerName = strings.TrimSpace(signature.SignerName)
+	signature.SignerRelationship = strings.TrimSpace(signature.SignerRelationship)
+	if signature.SignerName == "" {
+		return errors.New("SignerName is required")
+	}
+	if len(signature.SignerName) > 100 || len(signature.SignerRelationship) > 45 {
+		return errors.New("SignerName or SignerRelationship is too long")
+	}
+	if signature.SignerRelationship == "" {
+		signature.SignerRelationship = "Patient"
+	}
+	if !(signature.Width > 0 && signature.Height > 0) || math.IsInf(signature.Width, 0) || math.IsInf(signature.Height, 0) {
+		return errors.New("Width and Height of the signature pad must be positive")
+	}
+	points := 0
+	strokes := [][]domain.SignaturePoint{}
+	for _, stroke := range signature.Strokes {
+		if len(stroke) == 0 {
+			continue
+		}
+		points += len(stroke)
+		if points > maxSignaturePoints {
+			return errors.New("The signature has more than " + strconv.Itoa(maxSignaturePoints) + " points")
+		}
+		for i := range stroke {
+			stroke[i].X = math.Max(0, math.Min(signature.Width, stroke[i].X))
+			stroke[i].Y = math.Max(0, math.Min(signature.Height, stroke[i].Y))
+		}
+		strokes = append(strokes, stroke)
+	}
+	if points < 2 {
+		return errors.New("The signature is empty")
+	}
+	signature.Strokes = strokes
+	return nil
+}
+
+// renderConsent lays out the consent as signed: the appointment, the text
+// presented to the patient and the signature drawn on the pad.
+func renderConsent(header string, appointment domain.Appointment, consent domain.Consent, signature domain.ConsentSignature) *pdf.Document {
+	loc := appointmentLocation(appointment.TimeZone)
+	doc := pdf.New(pdf.A4)
+	doc.Title = consent.Title
+	doc.Author = header
+	width := doc.Size().Width - 2*printMargin
+	bottom := doc.Size().Height - printMargin - 24
+
+	y := printMargin + 16
+	doc.Text(printMargin, y, 16, true, pdf.Truncate(header, 16, true, width))
+	if appointment.Clinic.Address != "" {
+		y += 14
+		doc.Text(printMargin, y, 9, false, pdf.Truncate(appointment.Clinic.Address, 9, false, width))
+	}
+	y += 10
+	doc.Line(printMargin, y, printMargin+width, y, 0.5)
+
+	y += 12
+	for _, line := range pdf.Wrap(consent.Title, 14, true, width) {
+		y += 18
+		doc.Text(printMargin, y, 14, true, line)
+	}
+	y += 8
+	details := []struct{ label, value string }{
+		{"Patient", appointment.Patient.FirstName + " " + appointment.Patient.LastName + ", DNI " + appointment.Patient.DNI},
+		{"Dentist", appointment.Dentist.FirstName + " " + appointment.Dentist.LastName + ", license " + appointment.Dentist.License},
+		{"Treatment", appointment.Treatment.Name},
+		{"Appointment", appointment.StartsAt.In(loc).Format(domain.DateLayout+" "+domain.HourLayout) + " (" + loc.String() + ")"},
+	}
+	for _, detail := range details {
+		y += rowHeight
+		doc.Text(printMargin, y, 10, true, detail.label)
+		doc.Text(printMargin+80, y, 10, false, pdf.Truncate(detail.value, 10, false, width-80))
+	}
+	y += 14
+
+	for _, line := range pdf.Wrap(consent.Body, 10, false, width) {
+		if y+13 > bottom {
+			doc.AddPage()
+			y = printMargin
+		}
+		y += 13
+		doc.Text(printMargin, y, 10, false, line)
+	}
+
+	// The signature keeps the proportions of the pad in a box of at most
+	// 240x100 points, with the signer below it.
+	scale := math.Min(240/signature.Width, 100/signature.Height)
+	boxHeight := signature.Height * scale
+	if y+30+boxHeight+48 > bottom {
+		doc.AddPage()
+		y = printMargin
+	}
+	y += 30
+	for _, stroke := range signature.Strokes {
+		points := make([]pdf.Point, len(stroke))
+		for i, p := range stroke {
+			points[i] = pdf.Point{X: printMargin + p.X*scale, Y: y + p.Y*scale}
+		}
+		doc.Polyline(points, 1.2)
+	}
+	y += boxHeight + 4
+	doc.Line(printMargin, y, printMargin+240, y, 0.5)
+	y += 14
+	doc.Text(printMargin, y, 10, true, pdf.Truncate(consent.SignerName+" ("+consent.SignerRelationship+")", 10, true, width))
+	y += 13
+	doc.Text(printMargin, y, 9, false, "Signed on "+consent.SignedAt.In(loc).Format(domain.DateLayout+" "+domain.HourLayout)+" ("+loc.String()+"), "+
+		consent.SignedAt.UTC().Format(time.RFC3339))
+
+	pages := doc.PageCount()
+	for n := 1; n <= pages; n++ {
+		doc.SetPage(n)
+		footer := doc.Size().Height - printMargin
+		doc.Line(printMargin, footer-12, printMargin+width, footer-12, 0.5)
+		doc.Text(printMargin, footer, 8, false, "Consent #"+strconv.Itoa(consent.Id)+" - Appointment #"+strconv.Itoa(appointment.Id))
+		doc.TextRight(printMargin+width, footer, 8, false, "Page "+strconv.Itoa(n)+" of "+strconv.Itoa(pages))
+	}
+	return doc
+}
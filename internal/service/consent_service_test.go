@@ -0,0 +1,151 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/blob"
+	"testing"
+	"time"
+)
+
+type fakeConsentRepository struct {
+	repository.ConsentRepository
+	templates []domain.ConsentTemplate
+	consents  []domain.Consent
+	created   int
+}
+
+func (r *fakeConsentRepository) GetTemplatesByTreatment(tenantID int, treatmentID int) ([]domain.ConsentTemplate, error) {
+	return r.templates, nil
+}
+
+func (r *fakeConsentRepository) GetByAppointment(tenantID int, appointmentID int) ([]domain.Consent, error) {
+	return r.consents, nil
+}
+
+func (r *fakeConsentRepository) Create(tenantID int, consent domain.Consent) (int, error) {
+	r.created++
+	return r.created, nil
+}
+
+func (r *fakeConsentRepository) GetByID(tenantID int, id int) (domain.Consent, error) {
+	for _, consent := range r.consents {
+		if consent.Id == id {
+			return consent, nil
+		}
+	}
+	return domain.Consent{}, errors.New("Consent not found")
+}
+
+func (r *fakeConsentRepository) Sign(tenantID int, consent domain.Consent) error {
+	for i := range r.consents {
+		if r.consents[i].Id == consent.Id {
+			r.consents[i] = consent
+		}
+	}
+	return nil
+}
+
+func TestGetConsentsOfAnAppointmentCreatesNone(t *testing.T) {
+	treatment := domain.Treatment{Id: 4, Name: "Extracción"}
+	appointment := domain.Appointment{Id: 3, Patient: domain.Patient{Id: 1}, Treatment: treatment}
+	consents := &fakeConsentRepository{templates: []domain.ConsentTemplate{{Id: 2, Title: "Extracción", Body: "...", Active: true, Treatments: []domain.Treatment{treatment}}}}
+	s := NewConsentService(consents, &fakeAppointmentRepository{appointments: []domain.Appointment{appointment}}, nil, nil, nil)
+
+	got, err := s.GetByAppointment(1, appointment.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 || consents.created != 0 {
+		t.Errorf("GetByAppointment = %+v after creating %d consents, want a read of none", got, consents.created)
+	}
+	if _, err := s.GetByAppointment(1, 99); err == nil {
+		t.Error("GetByAppointment of a missing appointment succeeded")
+	}
+}
+
+func TestSignStoresTheDocumentAndDetectsTampering(t *testing.T) {
+	dir := t.TempDir()
+	blobs, err := blob.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appointment := domain.Appointment{Id: 3, Patient: domain.Patient{Id: 1}, Clinic: domain.Clinic{Name: "Sede Centro"}, StartsAt: time.Now()}
+	consents := &fakeConsentRepository{consents: []domain.Consent{{Id: 5, AppointmentId: 3, PatientId: 1, TemplateId: 2, Title: "Extracción", Body: "...", Status: domain.ConsentPending}}}
+	s := NewConsentService(consents, &fakeAppointmentRepository{appointments: []domain.Appointment{appointment}}, nil, nil, blobs)
+	signature := domain.ConsentSignature{
+		SignerName: " Juan Perez ",
+		Width:      400,
+		Height:     150,
+		Strokes:    [][]domain.SignaturePoint{{}, {{X: -10, Y: 20}, {X: 120, Y: 900}}},
+	}
+
+	signed, err := s.Sign(context.Background(), 1, 5, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed.Status != domain.ConsentSigned || signed.SignerName != "Juan Perez" || signed.SignerRelationship != "Patient" || signed.SignedAt == nil {
+		t.Errorf("signed %+v, want it signed by Juan Perez as the patient", signed)
+	}
+	if _, err := s.Sign(context.Background(), 1, 5, signature); err == nil {
+		t.Error("consent was signed twice")
+	}
+
+	_, document, err := s.Document(context.Background(), 1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash, _, _ := blob.Checksum(bytes.NewReader(document)); hash != signed.DocumentHash {
+		t.Errorf("document hash = %s, want %s", hash, signed.DocumentHash)
+	}
+	if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(signed.StorageKey)), append(document, ' '), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Document(context.Background(), 1, 5); !errors.Is(err, ErrConsentCorrupted) {
+		t.Errorf("Document of a tampered file returned %v, want ErrConsentCorrupted", err)
+	}
+}
+
+func TestValidateSignatureRejectsEmptyAndOversizedSignatures(t *testing.T) {
+	stroke := func(n int) []domain.SignaturePoint {
+		return make([]domain.SignaturePoint, n)
+	}
+	invalid := []domain.ConsentSignature{
+		{SignerName: " ", Width: 400, Height: 150, Strokes: [][]domain.SignaturePoint{stroke(2)}},
+		{SignerName: "Juan Perez", Width: 0, Height: 150, Strokes: [][]domain.SignaturePoint{stroke(2)}},
+		{SignerName: "Juan Perez", Width: 400, Height: 150, Strokes: [][]domain.SignaturePoint{stroke(1), {}}},
+		{SignerName: "Juan Perez", Width: 400, Height: 150, Strokes: [][]domain.SignaturePoint{stroke(maxSignaturePoints), stroke(1)}},
+	}
+	for _, signature := range invalid {
+		if err := validateSignature(&signature); err == nil {
+			t.Errorf("validateSignature(%q, %vx%v, %d strokes) succeeded, want an error", signature.SignerName, signature.Width, signature.Height, len(signature.Strokes))
+		}
+	}
+}
+
+func TestMissingConsentsOfTheTreatment(t *testing.T) {
+	extraction := domain.Treatment{Id: 4}
+	templates := []domain.ConsentTemplate{
+		{Id: 1, Title: "Extracción", Active: true, Treatments: []domain.Treatment{extraction}},
+		{Id: 2, Title: "Anestesia", Active: true, Treatments: []domain.Treatment{{Id: 5}, extraction}},
+		{Id: 3, Title: "Blanqueamiento", Active: true, Treatments: []domain.Treatment{{Id: 6}}},
+		{Id: 4, Title: "Anterior", Active: false, Treatments: []domain.Treatment{extraction}},
+	}
+	consents := []domain.Consent{
+		{TemplateId: 1, Status: domain.ConsentSigned},
+		{TemplateId: 2, Status: domain.ConsentPending},
+	}
+
+	missing := domain.MissingConsents(templates, extraction.Id, consents)
+	if len(missing) != 1 || missing[0].Id != 2 {
+		t.Errorf("missing %+v, want only template 2", missing)
+	}
+	if missing := domain.MissingConsents(templates, 0, nil); len(missing) != 0 {
+		t.Errorf("appointment without treatment misses %+v, want none", missing)
+	}
+}
@@ -6,12 +6,14 @@ import (
 )
 
 type DentistService interface {
-	Create(dentist domain.Dentist) error
-	GetByID(id int) (domain.Dentist, error)
-	GetAll() ([]domain.Dentist, error)
-	Update(dentist domain.Dentist) error
-	PatchLicense(id int, license string) error
-	Delete(id int) error
+	Create(tenantID int, dentist domain.Dentist) error
+	GetByID(tenantID int, id int) (domain.Dentist, error)
+	GetAll(tenantID int) ([]domain.Dentist, error)
+	Search(tenantID int, filter domain.DentistFilter) ([]domain.Dentist, error)
+	Each(tenantID int, filter domain.DentistFilter, fn func(domain.Dentist) error) error
+	Update(tenantID int, dentist domain.Dentist) error
+	PatchLicense(tenantID int, id int, license string) error
+	Delete(tenantID int, id int) error
 }
 
 // -------------------------------------------
@@ -24,32 +26,46 @@ func NewDentistService(dentistRepo repository.DentistRepository) DentistService
 }
 
 // -------------------------------------------
-func (s *dentistService) Create(dentist domain.Dentist) error {
-	err := s.dentistRepo.Create(dentist)
+func (s *dentistService) Create(tenantID int, dentist domain.Dentist) error {
+	err := s.dentistRepo.Create(tenantID, dentist)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *dentistService) GetByID(id int) (domain.Dentist, error) {
-	dentist, err := s.dentistRepo.GetByID(id)
+func (s *dentistService) GetByID(tenantID int, id int) (domain.Dentist, error) {
+	dentist, err := s.dentistRepo.GetByID(tenantID, id)
 	if err != nil {
 		return domain.Dentist{}, err
 	}
 	return dentist, nil
 }
 
-func (s *dentistService) GetAll() ([]domain.Dentist, error) {
-	dentists, err := s.dentistRepo.GetAll()
+func (s *dentistService) GetAll(tenantID int) ([]domain.Dentist, error) {
+	dentists, err := s.dentistRepo.GetAll(tenantID)
 	if err != nil {
 		return nil, err
 	}
 	return dentists, nil
 }
 
-func (s *dentistService) Update(dentist domain.Dentist) error {
-	existingDentist, err := s.dentistRepo.GetByID(dentist.Id)
+func (s *dentistService) Search(tenantID int, filter domain.DentistFilter) ([]domain.Dentist, error) {
+	dentists, err := s.dentistRepo.Search(tenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return dentists, nil
+}
+
+// Each calls fn with the dentists matching the filter, streaming them from
+// the store.
+func (s *dentistService) Each(tenantID int, filter domain.DentistFilter, fn func(domain.Dentist) error) error {
+	return s.dentistRepo.Each(tenantID, filter, fn)
+}
+
+func (s *dentistService) Update(tenantID int, dentist domain.Dentist) error {
+	existingDentist, err := s.dentistRepo.GetByID(tenantID, dentist.Id)
 	if err != nil {
 		return err
 	}
@@ -62,7 +78,7 @@ func (s *dentistService) Update(dentist domain.Dentist) error {
 	if dentist.License != "" {
 		existingDentist.License = dentist.License
 	}
-	err = s.dentistRepo.Update(existingDentist)
+	err = s.dentistRepo.Update(tenantID, existingDentist)
 	if err != nil {
 
 		return err
@@ -71,16 +87,16 @@ func (s *dentistService) Update(dentist domain.Dentist) error {
 	return nil
 }
 
-func (s *dentistService) PatchLicense(id int, license string) error {
-	err := s.dentistRepo.PatchLicense(id, license)
+func (s *dentistService) PatchLicense(tenantID int, id int, license string) error {
+	err := s.dentistRepo.PatchLicense(tenantID, id, license)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *dentistService) Delete(id int) error {
-	err := s.dentistRepo.Delete(id)
+func (s *dentistService) Delete(tenantID int, id int) error {
+	err := s.dentistRepo.Delete(tenantID, id)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/stream"
+	"time"
+)
+
+const replayPageSize = 500
+
+type EventService interface {
+	Subscribe(ctx context.Context, tenantID int, filter domain.EventFilter, lastEventID int64) (<-chan domain.Event, error)
+}
+
+// -------------------------------------------
+type eventService struct {
+	r      repository.EventRepository
+	broker *stream.Broker
+}
+
+func NewEventService(r repository.EventRepository, broker *stream.Broker) EventService {
+	return &eventService{r, broker}
+}
+
+//-------------------------------------------
+
+// Subscribe streams the appointment events of a tenant matching the filter.
+// When lastEventID is set the events the client missed since then are sent
+// first. The channel is closed when ctx is done or the client falls behind,
+// in which case it should reconnect with the id of the last event received.
+func (s *eventService) Subscribe(ctx context.Context, tenantID int, filter domain.EventFilter, lastEventID int64) (<-chan domain.Event, error) {
+	filter, err := resolveEventFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Subscribe before reading the missed events so nothing published in
+	// between is lost: the events up to published are read from the outbox,
+	// the later ones come from the broker.
+	live, published, unsubscribe := s.broker.Subscribe(tenantID)
+	var missed []domain.Event
+	if lastEventID > 0 {
+		missed, err = s.missedEvents(tenantID, lastEventID, published)
+		if err != nil {
+			unsubscribe()
+			return nil, err
+		}
+	}
+
+	out := make(chan domain.Event)
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+		sent := lastEventID
+		forward := func(event domain.Event) bool {
+			if event.Id <= sent {
+				return true
+			}
+			sent = event.Id
+			if !matchesEventFilter(event, filter) {
+				return true
+			}
+			select {
+			case out <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for _, event := range missed {
+			if !forward(event) {
+				return
+			}
+		}
+		for {
+			select {
+			case event, ok := <-live:
+				if !ok || !forward(event) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// missedEvents reads the events after afterID that the broker already
+// published, up to upTo.
+func (s *eventService) missedEvents(tenantID int, afterID int64, upTo int64) ([]domain.Event, error) {
+	var missed []domain.Event
+	for afterID < upTo {
+		events, err := s.r.GetTenantAfter(tenantID, afterID, "appointment", replayPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for i, event := range events {
+			if event.Id > upTo {
+				return append(missed, events[:i]...), nil
+			}
+		}
+		missed = append(missed, events...)
+		if len(events) < replayPageSize {
+			return missed, nil
+		}
+		afterID = events[len(events)-1].Id
+	}
+	return missed, nil
+}
+
+func resolveEventFilter(filter domain.EventFilter) (domain.EventFilter, error) {
+	if filter.Date == "" {
+		return filter, nil
+	}
+	loc, err := domain.LoadLocation(filter.TimeZone)
+	if err != nil {
+		return domain.EventFilter{}, errors.New("Invalid time zone: " + filter.TimeZone)
+	}
+	day, err := time.ParseInLocation(domain.DateLayout, filter.Date, loc)
+	if err != nil {
+		return domain.EventFilter{}, errors.New("Invalid date, expected dd/MM/yyyy")
+	}
+	filter.From = day
+	filter.To = day.AddDate(0, 0, 1)
+	return filter, nil
+}
+
+// matchesEventFilter reports whether an appointment event concerns the
+// filtered dentist and day. A rescheduled appointment matches both the day it
+// left and the day it moved to.
+func matchesEventFilter(event domain.Event, filter domain.EventFilter) bool {
+	if event.AggregateType != "appointment" {
+		return false
+	}
+	if filter.DentistId == 0 && filter.From.IsZero() {
+		return true
+	}
+	var payload domain.AppointmentEvent
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return false
+	}
+	if filter.DentistId != 0 && payload.DentistId != filter.DentistId {
+		return false
+	}
+	if !filter.From.IsZero() {
+		within := func(t time.Time) bool {
+			return !t.Before(filter.From) && t.Before(filter.To)
+		}
+		if !within(payload.StartsAt) && (payload.PreviousStartsAt == nil || !within(*payload.PreviousStartsAt)) {
+			return false
+		}
+	}
+	return true
+}
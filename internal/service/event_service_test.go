@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/stream"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeEventRepository holds the outbox of one tenant in id order and serves
+// both the replay and, as the broker source, the live tail.
+type fakeEventRepository struct {
+	repository.EventRepository
+	events  []domain.Event
+	lastID  int64
+	mu      sync.Mutex
+	visible int
+}
+
+func (r *fakeEventRepository) commit(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.visible = n
+}
+
+func (r *fakeEventRepository) LastID() (int64, error) {
+	return r.lastID, nil
+}
+
+func (r *fakeEventRepository) GetAfter(afterID int64, limit int) ([]domain.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []domain.Event
+	for _, event := range r.events[:r.visible] {
+		if event.Id > afterID && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r *fakeEventRepository) GetTenantAfter(tenantID int, afterID int64, aggregateType string, limit int) ([]domain.Event, error) {
+	return r.GetAfter(afterID, limit)
+}
+
+func (r *fakeEventRepository) GetByType(tenantID int, eventType string, since time.Time) ([]domain.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []domain.Event
+	for _, event := range r.events[:r.visible] {
+		if event.Type == eventType && !event.CreatedAt.Before(since) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r *fakeEventRepository) CountByAggregate(tenantID int, aggregateType string, ids []int) (map[int]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := map[int]int{}
+	for _, event := range r.events[:r.visible] {
+		if event.AggregateType == aggregateType {
+			counts[event.AggregateId]++
+		}
+	}
+	return counts, nil
+}
+
+func TestSubscribeResumesWithoutLosingOrRepeatingEvents(t *testing.T) {
+	r := &fakeEventRepository{lastID: 5}
+	for id := int64(1); id <= 8; id++ {
+		r.events = append(r.events, domain.Event{Id: id, TenantId: 1, AggregateType: "appointment"})
+	}
+	r.commit(6)
+	broker := stream.NewBroker(r, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go broker.Run(ctx)
+
+	// Wait for the broker to publish event 6, then commit 7 and 8.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, published, unsubscribe := broker.Subscribe(1)
+		unsubscribe()
+		if published == 6 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("broker published up to %d, want 6", published)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	s := NewEventService(r, broker)
+	events, err := s.Subscribe(ctx, 1, domain.EventFilter{}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.commit(8)
+	var ids []int64
+	for len(ids) < 5 {
+		select {
+		case event := <-events:
+			ids = append(ids, event.Id)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %v, want [4 5 6 7 8]", ids)
+		}
+	}
+	for i, id := range ids {
+		if id != int64(i+4) {
+			t.Fatalf("received %v, want [4 5 6 7 8]", ids)
+		}
+	}
+}
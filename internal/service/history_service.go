@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"strings"
+	"time"
+)
+
+type HistoryService interface {
+	Create(tenantID int, entry domain.HistoryEntry) (domain.HistoryEntry, error)
+	GetByID(tenantID int, patientID int, id int) (domain.HistoryEntry, error)
+	GetByPatient(tenantID int, patientID int, kind string) ([]domain.HistoryEntry, error)
+	Update(tenantID int, entry domain.HistoryEntry) (domain.HistoryEntry, error)
+	Delete(tenantID int, patientID int, id int) error
+}
+
+// -------------------------------------------
+type historyService struct {
+	historyRepo repository.HistoryRepository
+	patientRepo repository.PatientRepository
+}
+
+func NewHistoryService(historyRepo repository.HistoryRepository, patientRepo repository.PatientRepository) HistoryService {
+	return &historyService{historyRepo, patientRepo}
+}
+
+//-------------------------------------------
+
+func (s *historyService) Create(tenantID int, entry domain.HistoryEntry) (domain.HistoryEntry, error) {
+	if _, err := s.patientRepo.GetByID(tenantID, entry.PatientId); err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	if err := validateHistoryEntry(&entry); err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	entry.RecordedAt = time.Now().UTC().Truncate(time.Second)
+	entry.UpdatedAt = entry.RecordedAt
+
+	id, err := s.historyRepo.Create(tenantID, entry)
+	if err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	entry.Id = id
+	return entry, nil
+}
+
+func (s *historyService) GetByID(tenantID int, patientID int, id int) (domain.HistoryEntry, error) {
+	entry, err := s.historyRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	if entry.PatientId != patientID {
+		return domain.HistoryEntry{}, errors.New("History entry not found")
+	}
+	return entry, nil
+}
+
+// GetByPatient lists the history of a patient, only the entries of the given
+// kind when not empty.
+func (s *historyService) GetByPatient(tenantID int, patientID int, kind string) ([]domain.HistoryEntry, error) {
+	if kind != "" && !domain.ValidHistoryKind(kind) {
+		return nil, errors.New("Invalid kind, expected allergy, medication or condition")
+	}
+	entries, err := s.historyRepo.GetByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "" {
+		return entries, nil
+	}
+	filtered := []domain.HistoryEntry{}
+	for _, entry := range entries {
+		if entry.Kind == kind {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered, nil
+}
+
+// Update replaces an entry of the patient. Who recorded it and when is kept.
+func (s *historyService) Update(tenantID int, entry domain.HistoryEntry) (domain.HistoryEntry, error) {
+	existing, err := s.GetByID(tenantID, entry.PatientId, entry.Id)
+	if err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	if err := validateHistoryEntry(&entry); err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	entry.RecordedAt = existing.RecordedAt
+	entry.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+
+	if err := s.historyRepo.Update(tenantID, entry); err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	return entry, nil
+}
+
+func (s *historyService) Delete(tenantID int, patientID int, id int) error {
+	if _, err := s.GetByID(tenantID, patientID, id); err != nil {
+		return err
+	}
+	err := s.historyRepo.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// historyAlerts describes the entries to check before treating the patient
+// at the given time.
+func historyAlerts(entries []domain.HistoryEntry, now time.Time) []string {
+	var alerts []string
+	for _, entry := range entries {
+		if entry.Warns(now) {
+			alerts = append(alerts, entry.Summary())
+		}
+	}
+	return alerts
+}
+
+func validateHistoryEntry(entry *domain.HistoryEntry) error {
+	entry.Kind = strings.ToLower(strings.TrimSpace(entry.Kind))
+	entry.Severity = strings.ToLower(strings.TrimSpace(entry.Severity))
+	entry.Name = strings.TrimSpace(entry.Name)
+	entry.RecordedBy = strings.TrimSpace(entry.RecordedBy)
+	if !domain.ValidHistoryKind(entry.Kind) {
+		return errors.New("Invalid kind, expected allergy, medication or condition")
+	}
+	if !domain.ValidSeverity(entry.Severity) {
+		return errors.New("Invalid severity, expected mild, moderate or severe")
+	}
+	if entry.Name == "" || entry.RecordedBy == "" {
+		return errors.New("Name and RecordedBy are required")
+	}
+	var start, end time.Time
+	var err error
+	if entry.StartDate != "" {
+		if start, err = time.Parse(domain.DateLayout, entry.StartDate); err != nil {
+			return errors.New("Invalid StartDate, expected dd/MM/yyyy")
+		}
+	}
+	if entry.EndDate != "" {
+		if end, err = time.Parse(domain.DateLayout, entry.EndDate); err != nil {
+			return errors.New("Invalid EndDate, expected dd/MM/yyyy")
+		}
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return errors.New("EndDate must not be before StartDate")
+	}
+	return nil
+}
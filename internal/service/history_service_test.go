@@ -0,0 +1,54 @@
+package service
+
+import (
+	"proyecto_final_go/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistoryAlertsWarnOfActiveAllergiesAndSevereEntries(t *testing.T) {
+	entries := []domain.HistoryEntry{
+		{Kind: domain.HistoryAllergy, Name: "Penicillin", Severity: domain.SeveritySevere},
+		{Kind: domain.HistoryAllergy, Name: "Latex", EndDate: "31/12/2024"},
+		{Kind: domain.HistoryMedication, Name: "Ibuprofen", Severity: domain.SeverityMild},
+		{Kind: domain.HistoryMedication, Name: "Warfarin", Alert: true, EndDate: "20/10/2025"},
+		{Kind: domain.HistoryCondition, Name: "Diabetes", Severity: domain.SeveritySevere},
+		{Kind: domain.HistoryCondition, Name: "Hypertension", Severity: domain.SeveritySevere, EndDate: "19/10/2025"},
+	}
+	// Entries end on the day of the clinic: at 22:30 of 19/10 in Buenos
+	// Aires it is already 20/10 in UTC, yet Hypertension still warns.
+	loc, err := domain.LoadLocation(domain.DefaultTimeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 10, 19, 22, 30, 0, 0, loc)
+
+	want := []string{"Allergy: Penicillin (severe)", "Medication: Warfarin", "Condition: Diabetes (severe)", "Condition: Hypertension (severe)"}
+	if got := historyAlerts(entries, now); !reflect.DeepEqual(got, want) {
+		t.Errorf("historyAlerts = %q, want %q", got, want)
+	}
+}
+
+func TestValidateHistoryEntry(t *testing.T) {
+	entry := domain.HistoryEntry{Kind: " Allergy ", Severity: "SEVERE", Name: " Penicillin ", RecordedBy: " Dr. Rodríguez "}
+	if err := validateHistoryEntry(&entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Kind != domain.HistoryAllergy || entry.Severity != domain.SeveritySevere || entry.Name != "Penicillin" || entry.RecordedBy != "Dr. Rodríguez" {
+		t.Errorf("validateHistoryEntry left %+v, want it normalized", entry)
+	}
+
+	invalid := []domain.HistoryEntry{
+		{Kind: "surgery", Name: "Extraction", RecordedBy: "Dr. Rodríguez"},
+		{Kind: domain.HistoryCondition, Severity: "critical", Name: "Asthma", RecordedBy: "Dr. Rodríguez"},
+		{Kind: domain.HistoryCondition, Name: " ", RecordedBy: "Dr. Rodríguez"},
+		{Kind: domain.HistoryMedication, Name: "Amoxicillin", RecordedBy: "Dr. Rodríguez", StartDate: "2025-10-01"},
+		{Kind: domain.HistoryMedication, Name: "Amoxicillin", RecordedBy: "Dr. Rodríguez", StartDate: "10/10/2025", EndDate: "01/10/2025"},
+	}
+	for _, entry := range invalid {
+		if err := validateHistoryEntry(&entry); err == nil {
+			t.Errorf("validateHistoryEntry(%+v) succeeded, want an error", entry)
+		}
+	}
+}
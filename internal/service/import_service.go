@@ -0,0 +1,256 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/mail"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/ical"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// dniPattern finds a DNI written in the summary or description of an event,
+// e.g. "Control - DNI 30.123.456".
+var dniPattern = regexp.MustCompile(`(?i)\bDNI\W{0,3}([0-9][0-9.]{4,})`)
+
+// Sizes of the patient, dentist and appointment columns an import writes.
+const (
+	maxNameLength        = 45
+	maxEmailLength       = 100
+	maxDescriptionLength = 45
+)
+
+type ImportService interface {
+	ImportCalendar(tenantID int, dentistID int, r io.Reader, timeZone string) (domain.ImportReport, error)
+	ImportPatients(tenantID int, r io.Reader, options domain.BulkImportOptions) (domain.ImportReport, error)
+	ImportDentists(tenantID int, r io.Reader, options domain.BulkImportOptions) (domain.ImportReport, error)
+}
+
+// -------------------------------------------
+type importService struct {
+	appointmentService AppointmentService
+	appointmentRepo    repository.AppointmentRepository
+	patientRepo        repository.PatientRepository
+	dentistRepo        repository.DentistRepository
+}
+
+// NewImportService books the imported appointments through the appointment
+// service, so they go through the same checks as the ones made from the API.
+func NewImportService(appointmentService AppointmentService, appointmentRepo repository.AppointmentRepository, patientRepo repository.PatientRepository, dentistRepo repository.DentistRepository) ImportService {
+	return &importService{appointmentService, appointmentRepo, patientRepo, dentistRepo}
+}
+
+//-------------------------------------------
+
+// ImportCalendar books the events of an iCalendar file as appointments of a
+// dentist. Attendees are matched to patients by DNI, taken from an X-DNI
+// parameter or property or from the event text; unknown attendees get a
+// placeholder patient to be completed later. Times without a zone are read in
+// timeZone, DefaultTimeZone when empty.
+func (s *importService) ImportCalendar(tenantID int, dentistID int, r io.Reader, timeZone string) (domain.ImportReport, error) {
+	if _, err := s.dentistRepo.GetByID(tenantID, dentistID); err != nil {
+		return domain.ImportReport{}, err
+	}
+	loc, err := domain.LoadLocation(timeZone)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	calendar, err := ical.Parse(r, loc)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+
+	report := domain.ImportReport{Items: []domain.ImportItem{}}
+	for i, event := range calendar.Events {
+		if event.UID == "" {
+			event.UID = "event " + strconv.Itoa(i+1)
+		}
+		item, created := s.importEvent(tenantID, dentistID, event, loc)
+		if created {
+			report.CreatedPatients++
+		}
+		report.Add(item)
+	}
+	return report, nil
+}
+
+func (s *importService) importEvent(tenantID int, dentistID int, event ical.Event, loc *time.Location) (domain.ImportItem, bool) {
+	item := domain.ImportItem{Ref: event.UID, Status: domain.ImportSkipped}
+	if event.Status == ical.StatusCancelled {
+		item.Reason = "Event is cancelled"
+		return item, false
+	}
+	if event.Start.IsZero() || event.AllDay {
+		item.Reason = "Event has no start time"
+		return item, false
+	}
+	local := event.Start.In(loc)
+	item.Date = local.Format(domain.DateLayout)
+	item.Hour = local.Format(domain.HourLayout)
+
+	patient, placeholder, err := s.matchPatient(tenantID, event)
+	if err != nil {
+		item.Reason = err.Error()
+		return item, false
+	}
+
+	if !placeholder {
+		item.PatientId = patient.Id
+		booked, err := s.appointmentRepo.Search(tenantID, domain.AppointmentFilter{
+			DentistId: dentistID,
+			From:      event.Start,
+			To:        event.Start.Add(time.Second),
+		})
+		if err != nil {
+			item.Status = domain.ImportConflict
+			item.Reason = err.Error()
+			return item, false
+		}
+		for _, appointment := range booked {
+			if appointment.Patient.Id == patient.Id {
+				item.Reason = "Appointment already booked"
+				return item, false
+			}
+		}
+	}
+
+	// Calendar events often carry long notes; they are cut to fit the
+	// description column rather than failing the import.
+	description := strings.TrimSpace(event.Summary)
+	if event.Description != "" {
+		description = strings.TrimSpace(description + "\n" + event.Description)
+	}
+	description = truncate(description, maxDescriptionLength)
+	appointment := domain.Appointment{
+		Patient:     domain.Patient{Id: patient.Id},
+		Dentist:     domain.Dentist{Id: dentistID},
+		Date:        item.Date,
+		Hour:        item.Hour,
+		TimeZone:    loc.String(),
+		Description: description,
+	}
+	if placeholder {
+		// The placeholder is created with the appointment, so an event
+		// that can not be booked leaves no patient behind.
+		err = s.appointmentService.CreateWithPatient(tenantID, patient, appointment)
+	} else {
+		err = s.appointmentService.Create(tenantID, appointment)
+	}
+	if err != nil {
+		item.Status = domain.ImportConflict
+		item.Reason = err.Error()
+		return item, false
+	}
+	if placeholder {
+		if created, err := s.patientRepo.GetByDNI(tenantID, patient.DNI); err == nil {
+			item.PatientId = created.Id
+		}
+	}
+	item.Status = domain.ImportImported
+	return item, placeholder
+}
+
+// matchPatient finds the patient of an event by DNI or else returns a
+// placeholder patient to be created with the appointment, reporting which.
+// Placeholders of attendees without a DNI get a DNI derived from their email
+// or name, so importing the same file again reuses them.
+func (s *importService) matchPatient(tenantID int, event ical.Event) (domain.Patient, bool, error) {
+	attendee := eventAttendee(event)
+	dni := eventDNI(event, attendee)
+	name := strings.TrimSpace(attendee.Name)
+	if name == "" {
+		name = strings.TrimSpace(event.Summary)
+	}
+	if dni == "" {
+		key := strings.ToLower(attendee.Email)
+		if key == "" {
+			key = strings.ToLower(name)
+		}
+		if key == "" {
+			return domain.Patient{}, false, errors.New("Event has no attendee to match a patient")
+		}
+		sum := sha256.Sum256([]byte(key))
+		dni = "ICS-" + hex.EncodeToString(sum[:])[:10]
+	}
+
+	if utf8.RuneCountInString(dni) > maxNameLength {
+		return domain.Patient{}, false, errors.New("DNI " + dni + " is too long")
+	}
+
+	if patient, err := s.patientRepo.GetByDNI(tenantID, dni); err == nil {
+		return patient, false, nil
+	}
+	placeholder := placeholderPatient(dni, name, attendee.Email)
+	if err := validateContact(placeholder); err != nil {
+		return domain.Patient{}, false, err
+	}
+	return placeholder, true, nil
+}
+
+// eventAttendee returns the attendee carrying a DNI or else the first one.
+func eventAttendee(event ical.Event) ical.Attendee {
+	for _, attendee := range event.Attendees {
+		if attendee.Params["X-DNI"] != "" {
+			return attendee
+		}
+	}
+	if len(event.Attendees) > 0 {
+		return event.Attendees[0]
+	}
+	return ical.Attendee{}
+}
+
+func eventDNI(event ical.Event, attendee ical.Attendee) string {
+	dni := attendee.Params["X-DNI"]
+	if dni == "" {
+		dni = event.Extra["X-DNI"]
+	}
+	if dni == "" {
+		if match := dniPattern.FindStringSubmatch(event.Summary + "\n" + event.Description); match != nil {
+			dni = match[1]
+		}
+	}
+	return strings.ReplaceAll(strings.TrimSpace(dni), ".", "")
+}
+
+// placeholderPatient builds a patient with the little an import knows about
+// them; the missing details are completed at the first visit. Names are cut
+// to fit their columns, and an email that does not fit is left out.
+func placeholderPatient(dni string, name string, email string) domain.Patient {
+	firstName, lastName, _ := strings.Cut(name, " ")
+	if last, first, ok := strings.Cut(name, ","); ok {
+		firstName, lastName = strings.TrimSpace(first), strings.TrimSpace(last)
+	}
+	if firstName == "" {
+		firstName = "Unknown"
+	}
+	if lastName == "" {
+		lastName = "(imported)"
+	}
+	if _, err := mail.ParseAddress(email); err != nil || len(email) > maxEmailLength {
+		email = ""
+	}
+	return domain.Patient{
+		FirstName:   truncate(firstName, maxNameLength),
+		LastName:    truncate(strings.TrimSpace(lastName), maxNameLength),
+		Address:     "Pending",
+		DNI:         dni,
+		ReleaseDate: time.Now().Format(domain.DateLayout),
+		Email:       email,
+	}
+}
+
+// truncate cuts s to at most n characters.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return strings.TrimSpace(string([]rune(s)[:n]))
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+type fakeDentistRepository struct {
+	repository.DentistRepository
+	created []domain.Dentist
+}
+
+func (r *fakeDentistRepository) GetByID(tenantID int, id int) (domain.Dentist, error) {
+	return domain.Dentist{Id: id}, nil
+}
+
+func (r *fakeDentistRepository) Exists(tenantID int, license string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeDentistRepository) Create(tenantID int, dentist domain.Dentist) error {
+	r.created = append(r.created, dentist)
+	return nil
+}
+
+type fakePatientRepository struct {
+	repository.PatientRepository
+	patients []domain.Patient
+}
+
+func (r *fakePatientRepository) GetByDNI(tenantID int, dni string) (domain.Patient, error) {
+	for _, patient := range r.patients {
+		if patient.DNI == dni {
+			return patient, nil
+		}
+	}
+	return domain.Patient{}, sql.ErrNoRows
+}
+
+func (r *fakePatientRepository) Create(tenantID int, patient domain.Patient) error {
+	patient.Id = len(r.patients) + 1
+	r.patients = append(r.patients, patient)
+	return nil
+}
+
+func (r *fakePatientRepository) CreateBatch(tenantID int, patients []domain.Patient) error {
+	for _, patient := range patients {
+		r.Create(tenantID, patient)
+	}
+	return nil
+}
+
+func (r *fakePatientRepository) Exists(tenantID int, dni string) (bool, error) {
+	_, err := r.GetByDNI(tenantID, dni)
+	return err == nil, nil
+}
+
+// fakeAppointmentService books every appointment unless err is set, and
+// creates the patients booked with CreateWithPatient in patients.
+type fakeAppointmentService struct {
+	AppointmentService
+	patients *fakePatientRepository
+	created  []domain.Appointment
+	err      error
+}
+
+func (s *fakeAppointmentService) Create(tenantID int, appointment domain.Appointment) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.created = append(s.created, appointment)
+	return nil
+}
+
+func (s *fakeAppointmentService) CreateWithPatient(tenantID int, patient domain.Patient, appointment domain.Appointment) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.patients.Create(tenantID, patient)
+	appointment.Patient, _ = s.patients.GetByDNI(tenantID, patient.DNI)
+	s.created = append(s.created, appointment)
+	return nil
+}
+
+func TestImportCalendarFitsLongTextInTheColumns(t *testing.T) {
+	patients := &fakePatientRepository{}
+	appointments := &fakeAppointmentService{patients: patients}
+	s := NewImportService(appointments, &fakeAppointmentRepository{}, patients, &fakeDentistRepository{})
+
+	name := strings.Repeat("Maximiliano ", 5) + strings.Repeat("Fernández", 6)
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:long-event",
+		"DTSTART:20240402T130000Z",
+		"SUMMARY:Control",
+		"DESCRIPTION:" + strings.Repeat("Revisar la pieza 26 y evaluar la corona. ", 3),
+		"ATTENDEE;CN=" + name + ":mailto:max@example.com",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	report, err := s.ImportCalendar(1, 1, strings.NewReader(calendar), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Imported != 1 || len(appointments.created) != 1 {
+		t.Fatalf("report = %+v, want the event imported", report)
+	}
+	description := appointments.created[0].Description
+	if n := utf8.RuneCountInString(description); n > maxDescriptionLength || !strings.HasPrefix(description, "Control\nRevisar") {
+		t.Errorf("description = %q (%d characters), want it cut to %d", description, n, maxDescriptionLength)
+	}
+	patient := patients.patients[0]
+	if utf8.RuneCountInString(patient.FirstName) > maxNameLength || utf8.RuneCountInString(patient.LastName) > maxNameLength {
+		t.Errorf("placeholder patient %q %q does not fit the name columns", patient.FirstName, patient.LastName)
+	}
+}
+
+func TestImportCalendarCreatesNoPlaceholderForAnEventNotBooked(t *testing.T) {
+	patients := &fakePatientRepository{}
+	appointments := &fakeAppointmentService{patients: patients, err: errors.New("Dentist already has an appointment at the same date and time")}
+	s := NewImportService(appointments, &fakeAppointmentRepository{}, patients, &fakeDentistRepository{})
+
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:taken",
+		"DTSTART:20240402T130000Z",
+		"SUMMARY:Control",
+		"ATTENDEE;CN=Juan Perez;X-DNI=30123456:mailto:juan@example.com",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	report, err := s.ImportCalendar(1, 1, strings.NewReader(calendar), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Conflicts != 1 || report.CreatedPatients != 0 {
+		t.Errorf("report = %+v, want one conflict and no patient created", report)
+	}
+	if len(patients.patients) != 0 {
+		t.Errorf("patients = %+v, want no placeholder left behind", patients.patients)
+	}
+}
+
+func TestImportDentistsReportsNamesLongerThanTheColumn(t *testing.T) {
+	dentists := &fakeDentistRepository{}
+	s := NewImportService(&fakeAppointmentService{}, &fakeAppointmentRepository{}, &fakePatientRepository{}, dentists)
+
+	file := "FirstName,LastName,License\n" +
+		"Daniel,Rodríguez,MN-1\n" +
+		"Daniel," + strings.Repeat("Rodríguez", 6) + ",MN-2\n"
+	report, err := s.ImportDentists(1, strings.NewReader(file), domain.BulkImportOptions{Format: "csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Imported != 1 || report.Invalid != 1 || len(dentists.created) != 1 {
+		t.Fatalf("report = %+v, want one dentist imported and one invalid", report)
+	}
+	if reason := report.Items[1].Reason; reason != "LastName is longer than 45 characters" {
+		t.Errorf("reason = %q, want the long LastName reported", reason)
+	}
+}
+
+// patientsFile has a valid row, a row repeating its DNI, one with a DNI
+// already registered, one with an invalid email and a row without DNI.
+const patientsFile = "Nombre,Apellido,Domicilio,Documento,Alta,Email\n" +
+	"Juan,Perez,Calle 1,30123456,01/03/2024,juan@example.com\n" +
+	"Juan,Perez,Calle 1,30123456,01/03/2024,\n" +
+	"Ana,Gomez,Calle 2,28999000,01/03/2024,\n" +
+	"Luis,Diaz,Calle 3,31000111,01/03/2024,luis-at-example\n" +
+	"Eva,Sosa,Calle 4,,2024-03-01,\n"
+
+var patientsMapping = map[string]string{"FirstName": "Nombre", "LastName": "Apellido", "Address": "Domicilio", "DNI": "Documento", "ReleaseDate": "Alta"}
+
+func TestImportPatientsReportsEveryRow(t *testing.T) {
+	patients := &fakePatientRepository{patients: []domain.Patient{{Id: 1, DNI: "28999000"}}}
+	s := NewImportService(&fakeAppointmentService{}, &fakeAppointmentRepository{}, patients, &fakeDentistRepository{})
+
+	report, err := s.ImportPatients(1, strings.NewReader(patientsFile), domain.BulkImportOptions{Format: "csv", Mapping: patientsMapping})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Imported != 1 || report.Skipped != 2 || report.Invalid != 2 {
+		t.Fatalf("report = %+v, want 1 imported, 2 skipped and 2 invalid", report)
+	}
+	want := []struct{ ref, status, reason string }{
+		{"line 2", domain.ImportImported, ""},
+		{"line 3", domain.ImportSkipped, "DNI repeated from line 2"},
+		{"line 4", domain.ImportSkipped, "DNI already exists"},
+		{"line 5", domain.ImportInvalid, "Invalid email address"},
+		{"line 6", domain.ImportInvalid, "Missing DNI; Invalid ReleaseDate, expected dd/MM/yyyy"},
+	}
+	for i, w := range want {
+		item := report.Items[i]
+		if item.Ref != w.ref || item.Status != w.status || item.Reason != w.reason {
+			t.Errorf("item %d = %+v, want %s %s %q", i, item, w.ref, w.status, w.reason)
+		}
+	}
+	if len(patients.patients) != 2 || patients.patients[1].Email != "juan@example.com" {
+		t.Errorf("patients = %+v, want Juan Perez added", patients.patients)
+	}
+}
+
+func TestImportPatientsDryRunAndAtomicCreateNothingWithInvalidRows(t *testing.T) {
+	for _, options := range []domain.BulkImportOptions{{DryRun: true}, {Atomic: true}} {
+		options.Mapping = patientsMapping
+		patients := &fakePatientRepository{}
+		s := NewImportService(&fakeAppointmentService{}, &fakeAppointmentRepository{}, patients, &fakeDentistRepository{})
+
+		report, err := s.ImportPatients(1, strings.NewReader(patientsFile), options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(patients.patients) != 0 {
+			t.Errorf("%+v created %d patients, want none", options, len(patients.patients))
+		}
+		if options.DryRun && (!report.DryRun || report.Imported != 2) {
+			t.Errorf("dry run report = %+v, want the 2 rows that would be imported", report)
+		}
+		if options.Atomic && (report.Imported != 0 || report.Items[0].Reason != "Not imported, the file has invalid rows") {
+			t.Errorf("atomic report = %+v, want nothing imported", report)
+		}
+	}
+}
+
+func TestImportPatientsReadsJSONLines(t *testing.T) {
+	patients := &fakePatientRepository{}
+	s := NewImportService(&fakeAppointmentService{}, &fakeAppointmentRepository{}, patients, &fakeDentistRepository{})
+
+	file := `{"firstName":"Juan","LastName":"Perez","Address":"Calle 1","DNI":30123456,"ReleaseDate":"01/03/2024"}` + "\n\n" +
+		`{"FirstName":"Ana",` + "\n"
+	report, err := s.ImportPatients(1, strings.NewReader(file), domain.BulkImportOptions{Format: "jsonl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Imported != 1 || report.Invalid != 1 || report.Items[1].Ref != "line 3" {
+		t.Fatalf("report = %+v, want line 1 imported and line 3 invalid", report)
+	}
+	if patients.patients[0].DNI != "30123456" {
+		t.Errorf("DNI = %q, want the number read as written", patients.patients[0].DNI)
+	}
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"strings"
+	"time"
+)
+
+type InsuranceService interface {
+	CreateProvider(tenantID int, provider domain.InsuranceProvider) (domain.InsuranceProvider, error)
+	GetProvider(tenantID int, id int) (domain.InsuranceProvider, error)
+	GetProviders(tenantID int) ([]domain.InsuranceProvider, error)
+	UpdateProvider(tenantID int, provider domain.InsuranceProvider) (domain.InsuranceProvider, error)
+	CreateRule(tenantID int, rule domain.CoverageRule) (domain.InsuranceProvider, error)
+	UpdateRule(tenantID int, rule domain.CoverageRule) (domain.InsuranceProvider, error)
+	DeleteRule(tenantID int, providerID int, id int) error
+	CreateCoverage(tenantID int, coverage domain.Coverage) (domain.Coverage, error)
+	GetCoverage(tenantID int, id int) (domain.Coverage, error)
+	GetCoverages(tenantID int, patientID int) ([]domain.Coverage, error)
+	UpdateCoverage(tenantID int, coverage domain.Coverage) (domain.Coverage, error)
+	Submit(tenantID int, invoiceID int, reference string) (domain.Claim, error)
+	GetClaim(tenantID int, id int) (domain.Claim, error)
+	GetClaims(tenantID int, status string, providerID int) ([]domain.Claim, error)
+	GetClaimsByInvoice(tenantID int, invoiceID int) ([]domain.Claim, error)
+	SetClaimStatus(tenantID int, id int, update domain.Claim) (domain.Claim, error)
+}
+
+// -------------------------------------------
+type insuranceService struct {
+	insuranceRepo repository.InsuranceRepository
+	invoiceRepo   repository.InvoiceRepository
+	patientRepo   repository.PatientRepository
+	treatmentRepo repository.TreatmentRepository
+}
+
+func NewInsuranceService(insuranceRepo repository.InsuranceRepository, invoiceRepo repository.InvoiceRepository, patientRepo repository.PatientRepository, treatmentRepo repository.TreatmentRepository) InsuranceService {
+	return &insuranceService{insuranceRepo, invoiceRepo, patientRepo, treatmentRepo}
+}
+
+//-------------------------------------------
+
+// CreateProvider adds a provider the practice accepts.
+func (s *insuranceService) CreateProvider(tenantID int, provider domain.InsuranceProvider) (domain.InsuranceProvider, error) {
+	if err := validateProvider(&provider); err != nil {
+		return domain.InsuranceProvider{}, err
+	}
+	provider.Active = true
+	id, err := s.insuranceRepo.CreateProvider(tenantID, provider)
+	if err != nil {
+		return domain.InsuranceProvider{}, err
+	}
+	return s.insuranceRepo.GetProvider(tenantID, id)
+}
+
+func (s *insuranceService) GetProvider(tenantID int, id int) (domain.InsuranceProvider, error) {
+	provider, err := s.insuranceRepo.GetProvider(tenantID, id)
+	if err != nil {
+		return domain.InsuranceProvider{}, err
+	}
+	return provider, nil
+}
+
+func (s *insuranceService) GetProviders(tenantID int) ([]domain.InsuranceProvider, error) {
+	providers, err := s.insuranceRepo.GetProviders(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+// UpdateProvider replaces the details and the Active flag of a provider. Its
+// rules are changed on their own.
+func (s *insuranceService) UpdateProvider(tenantID int, provider domain.InsuranceProvider) (domain.InsuranceProvider, error) {
+	if _, err := s.insuranceRepo.GetProvider(tenantID, provider.Id); err != nil {
+		return domain.InsuranceProvider{}, err
+	}
+	if err := validateProvider(&provider); err != nil {
+		return domain.InsuranceProvider{}, err
+	}
+	if err := s.insuranceRepo.UpdateProvider(tenantID, provider); err != nil {
+		return domain.InsuranceProvider{}, err
+	}
+	return s.insuranceRepo.GetProvider(tenantID, provider.Id)
+}
+
+func validateProvider(provider *domain.InsuranceProvider) error {
+	provider.Name = strings.TrimSpace(provider.Name)
+	provider.Code = strings.TrimSpace(provider.Code)
+	provider.Phone = strings.TrimSpace(provider.Phone)
+	provider.Email = strings.TrimSpace(provider.Email)
+	if provider.Name == "" {
+		return errors.New("Name is required")
+	}
+	if len(provider.Name) > 100 {
+		return errors.New("Name can not be longer than 100 characters")
+	}
+	if len(provider.Code) > 32 || len(provider.Phone) > 32 {
+		return errors.New("Code and Phone can not be longer than 32 characters")
+	}
+	if len(provider.Email) > 255 {
+		return errors.New("Email can not be longer than 255 characters")
+	}
+	return nil
+}
+
+// CreateRule adds a rule to the provider and returns the provider with its
+// rules.
+func (s *insuranceService) CreateRule(tenantID int, rule domain.CoverageRule) (domain.InsuranceProvider, error) {
+	if err := s.validateRule(tenantID, &rule); err != nil {
+		return domain.InsuranceProvider{}, err
+	}
+	if _, err := s.insuranceRepo.CreateRule(tenantID, rule); err != nil {
+		return domain.InsuranceProvider{}, err
+	}
+	return s.insuranceRepo.GetProvider(tenantID, rule.ProviderId)
+}
+
+// UpdateRule replaces a rule of the provider. Invoices already drafted keep
+// the split they were drafted with.
+func (s *insuranceService) UpdateRule(tenantID int, rule domain.CoverageRule) (domain.InsuranceProvider, error) {
+	existing, err := s.insuranceRepo.GetRule(tenantID, rule.Id)
+	if err != nil || existing.ProviderId != rule.ProviderId {
+		return domain.InsuranceProvider{}, errors.New("Coverage rule not found")
+	}
+	if err := s.validateRule(tenantID, &rule); err != nil {
+		return domain.InsuranceProvider{}, err
+	}
+	if err := s.insuranceRepo.UpdateRule(tenantID, rule); err != nil {
+		return domain.InsuranceProvider{}, err
+	}
+	return s.insuranceRepo.GetProvider(tenantID, rule.ProviderId)
+}
+
+func (s *insuranceService) DeleteRule(tenantID int, providerID int, id int) error {
+	existing, err := s.insuranceRepo.GetRule(tenantID, id)
+	if err != nil || existing.ProviderId != providerID {
+		return errors.New("Coverage rule not found")
+	}
+	return s.insuranceRepo.DeleteRule(tenantID, id)
+}
+
+func (s *insuranceService) validateRule(tenantID int, rule *domain.CoverageRule) error {
+	rule.Plan = strings.TrimSpace(rule.Plan)
+	if len(rule.Plan) > 32 {
+		return errors.New("Plan can not be longer than 32 characters")
+	}
+	if rule.Rate < 0 || rule.Rate > 10000 {
+		return errors.New("Rate must be between 0 and 10000 basis points")
+	}
+	if rule.MaxAmount < 0 {
+		return errors.New("MaxAmount can not be negative")
+	}
+	if _, err := s.treatmentRepo.GetByID(tenantID, rule.TreatmentId); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateCoverage adds a coverage to the patient. A patient has at most one
+// coverage on any day, so its validity must not overlap the others.
+func (s *insuranceService) CreateCoverage(tenantID int, coverage domain.Coverage) (domain.Coverage, error) {
+	if _, err := s.patientRepo.GetByID(tenantID, coverage.PatientId); err != nil {
+		return domain.Coverage{}, err
+	}
+	coverage.Id = 0
+	if err := s.validateCoverage(tenantID, &coverage); err != nil {
+		return domain.Coverage{}, err
+	}
+	id, err := s.insuranceRepo.CreateCoverage(tenantID, coverage)
+	if err != nil {
+		return domain.Coverage{}, err
+	}
+	return s.insuranceRepo.GetCoverage(tenantID, id)
+}
+
+func (s *insuranceService) GetCoverage(tenantID int, id int) (domain.Coverage, error) {
+	coverage, err := s.insuranceRepo.GetCoverage(tenantID, id)
+	if err != nil {
+		return domain.Coverage{}, err
+	}
+	return coverage, nil
+}
+
+func (s *insuranceService) GetCoverages(tenantID int, patientID int) ([]domain.Coverage, error) {
+	if _, err := s.patientRepo.GetByID(tenantID, patientID); err != nil {
+		return nil, err
+	}
+	coverages, err := s.insuranceRepo.GetCoverages(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+// UpdateCoverage replaces a coverage, e.g. to end it when the patient changes
+// provider. Invoices already drafted keep their split.
+func (s *insuranceService) UpdateCoverage(tenantID int, coverage domain.Coverage) (domain.Coverage, error) {
+	existing, err := s.insuranceRepo.GetCoverage(tenantID, coverage.Id)
+	if err != nil {
+		return domain.Coverage{}, err
+	}
+	coverage.PatientId = existing.PatientId
+	if err := s.validateCoverage(tenantID, &coverage); err != nil {
+		return domain.Coverage{}, err
+	}
+	if err := s.insuranceRepo.UpdateCoverage(tenantID, coverage); err != nil {
+		return domain.Coverage{}, err
+	}
+	return s.insuranceRepo.GetCoverage(tenantID, coverage.Id)
+}
+
+func (s *insuranceService) validateCoverage(tenantID int, coverage *domain.Coverage) error {
+	coverage.Plan = strings.TrimSpace(coverage.Plan)
+	coverage.MemberNumber = strings.TrimSpace(coverage.MemberNumber)
+	if coverage.MemberNumber == "" {
+		return errors.New("MemberNumber is required")
+	}
+	if len(coverage.Plan) > 32 || len(coverage.MemberNumber) > 64 {
+		return errors.New("Plan can not be longer than 32 characters nor MemberNumber than 64")
+	}
+	from, err := time.Parse(domain.DateLayout, coverage.ValidFrom)
+	if err != nil {
+		return errors.New("Invalid ValidFrom, expected dd/MM/yyyy")
+	}
+	if coverage.ValidTo != "" {
+		to, err := time.Parse(domain.DateLayout, coverage.ValidTo)
+		if err != nil {
+			return errors.New("Invalid ValidTo, expected dd/MM/yyyy")
+		}
+		if to.Before(from) {
+			return errors.New("ValidTo must not be before ValidFrom")
+		}
+	}
+	if _, err := s.insuranceRepo.GetProvider(tenantID, coverage.ProviderId); err != nil {
+		return err
+	}
+
+	coverages, err := s.insuranceRepo.GetCoverages(tenantID, coverage.PatientId)
+	if err != nil {
+		return err
+	}
+	for _, other := range coverages {
+		if other.Id != coverage.Id && coverage.Overlaps(other) {
+			return errors.New("The patient is already covered by " + other.ProviderName + " from " + other.ValidFrom)
+		}
+	}
+	return nil
+}
+
+// Submit records the claim of the share of an issued invoice the insurer
+// covers.
+func (s *insuranceService) Submit(tenantID int, invoiceID int, reference string) (domain.Claim, error) {
+	invoice, err := s.invoiceRepo.GetByID(tenantID, invoiceID)
+	if err != nil {
+		return domain.Claim{}, err
+	}
+	if invoice.Status != domain.InvoiceIssued && invoice.Status != domain.InvoicePaid {
+		return domain.Claim{}, errors.New("Only issued or paid invoices can be claimed")
+	}
+	if invoice.CoverageId == 0 || invoice.Covered == 0 {
+		return domain.Claim{}, errors.New("The invoice has no share covered by an insurer")
+	}
+	coverage, err := s.insuranceRepo.GetCoverage(tenantID, invoice.CoverageId)
+	if err != nil {
+		return domain.Claim{}, err
+	}
+	reference = strings.TrimSpace(reference)
+	if len(reference) > 64 {
+		return domain.Claim{}, errors.New("Reference can not be longer than 64 characters")
+	}
+
+	claim := domain.Claim{
+		InvoiceId:   invoice.Id,
+		CoverageId:  coverage.Id,
+		ProviderId:  coverage.ProviderId,
+		PatientId:   invoice.PatientId,
+		Amount:      invoice.Covered,
+		Status:      domain.ClaimSubmitted,
+		Reference:   reference,
+		SubmittedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	id, err := s.insuranceRepo.CreateClaim(tenantID, claim)
+	if err != nil {
+		return domain.Claim{}, err
+	}
+	return s.insuranceRepo.GetClaim(tenantID, id)
+}
+
+func (s *insuranceService) GetClaim(tenantID int, id int) (domain.Claim, error) {
+	claim, err := s.insuranceRepo.GetClaim(tenantID, id)
+	if err != nil {
+		return domain.Claim{}, err
+	}
+	return claim, nil
+}
+
+func (s *insuranceService) GetClaims(tenantID int, status string, providerID int) ([]domain.Claim, error) {
+	switch status {
+	case "", domain.ClaimSubmitted, domain.ClaimAccepted, domain.ClaimRejected, domain.ClaimPaid:
+	default:
+		return nil, errors.New("Invalid status, expected submitted, accepted, rejected or paid")
+	}
+	claims, err := s.insuranceRepo.GetClaims(tenantID, status, providerID)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (s *insuranceService) GetClaimsByInvoice(tenantID int, invoiceID int) ([]domain.Claim, error) {
+	if _, err := s.invoiceRepo.GetByID(tenantID, invoiceID); err != nil {
+		return nil, err
+	}
+	claims, err := s.insuranceRepo.GetClaimsByInvoice(tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// SetClaimStatus records the answer of the provider: a submitted claim is
+// accepted, submitted or accepted claims are rejected with a reason or paid,
+// by default the amount claimed. The reference given replaces the one of the
+// claim.
+func (s *insuranceService) SetClaimStatus(tenantID int, id int, update domain.Claim) (domain.Claim, error) {
+	claim, err := s.insuranceRepo.GetClaim(tenantID, id)
+	if err != nil {
+		return domain.Claim{}, err
+	}
+	from := claim.Status
+	pending := from == domain.ClaimSubmitted || from == domain.ClaimAccepted
+	now := time.Now().UTC().Truncate(time.Second)
+	switch update.Status {
+	case domain.ClaimAccepted:
+		if from != domain.ClaimSubmitted {
+			return domain.Claim{}, errors.New("Only submitted claims can be accepted")
+		}
+		claim.ResolvedAt = &now
+	case domain.ClaimRejected:
+		if !pending {
+			return domain.Claim{}, errors.New("Only submitted or accepted claims can be rejected")
+		}
+		claim.Reason = strings.TrimSpace(update.Reason)
+		if claim.Reason == "" {
+			return domain.Claim{}, errors.New("A reason is required to reject a claim")
+		}
+		if len(claim.Reason) > 255 {
+			return domain.Claim{}, errors.New("Reason can not be longer than 255 characters")
+		}
+		claim.ResolvedAt = &now
+	case domain.ClaimPaid:
+		if !pending {
+			return domain.Claim{}, errors.New("Only submitted or accepted claims can be paid")
+		}
+		claim.PaidAmount = update.PaidAmount
+		if claim.PaidAmount == 0 {
+			claim.PaidAmount = claim.Amount
+		}
+		if claim.PaidAmount < 0 || claim.PaidAmount > claim.Amount {
+			return domain.Claim{}, errors.New("PaidAmount must be positive and at most the amount claimed")
+		}
+		if claim.ResolvedAt == nil {
+			claim.ResolvedAt = &now
+		}
+		claim.PaidAt = &now
+	default:
+		return domain.Claim{}, errors.New("Invalid status, expected accepted, rejected or paid")
+	}
+	if reference := strings.TrimSpace(update.Reference); reference != "" {
+		if len(reference) > 64 {
+			return domain.Claim{}, errors.New("Reference can not be longer than 64 characters")
+		}
+		claim.Reference = reference
+	}
+	claim.Status = update.Status
+
+	if err := s.insuranceRepo.UpdateClaim(tenantID, claim, from); err != nil {
+		return domain.Claim{}, err
+	}
+	return s.insuranceRepo.GetClaim(tenantID, id)
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type InvoiceService interface {
+	Generate(tenantID int, invoice domain.Invoice, appointmentIDs []int) (domain.Invoice, error)
+	GetByID(tenantID int, id int) (domain.Invoice, error)
+	GetByPatient(tenantID int, patientID int) ([]domain.Invoice, error)
+	GetAll(tenantID int, status string) ([]domain.Invoice, error)
+	SetStatus(tenantID int, id int, status string, reason string) (domain.Invoice, error)
+	Balance(tenantID int, patientID int) (domain.PatientBalance, error)
+}
+
+// -------------------------------------------
+type invoiceService struct {
+	invoiceRepo     repository.InvoiceRepository
+	patientRepo     repository.PatientRepository
+	appointmentRepo repository.AppointmentRepository
+	treatmentRepo   repository.TreatmentRepository
+	insuranceRepo   repository.InsuranceRepository
+}
+
+func NewInvoiceService(invoiceRepo repository.InvoiceRepository, patientRepo repository.PatientRepository, appointmentRepo repository.AppointmentRepository, treatmentRepo repository.TreatmentRepository, insuranceRepo repository.InsuranceRepository) InvoiceService {
+	return &invoiceService{invoiceRepo, patientRepo, appointmentRepo, treatmentRepo, insuranceRepo}
+}
+
+//-------------------------------------------
+
+// Generate drafts an invoice for the patient with a line for the treatment of
+// each appointment in appointmentIDs, or of every completed appointment not
+// invoiced yet when empty, followed by the lines of the invoice given. The
+// lines are split with the coverage of the invoice, see applyCoverage.
+func (s *invoiceService) Generate(tenantID int, invoice domain.Invoice, appointmentIDs []int) (domain.Invoice, error) {
+	if _, err := s.patientRepo.GetByID(tenantID, invoice.PatientId); err != nil {
+		return domain.Invoice{}, err
+	}
+	appointments, err := s.billableAppointments(tenantID, invoice.PatientId, appointmentIDs)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	lines := []domain.InvoiceLine{}
+	treatedOn := map[int]time.Time{}
+	for _, appointment := range appointments {
+		treatment, err := s.treatmentRepo.GetByID(tenantID, appointment.Treatment.Id)
+		if err != nil {
+			return domain.Invoice{}, err
+		}
+		loc, err := appointment.Clinic.Location()
+		if err != nil {
+			return domain.Invoice{}, err
+		}
+		treatedOn[appointment.Id] = appointment.StartsAt.In(loc)
+		lines = append(lines, domain.InvoiceLine{
+			AppointmentId: appointment.Id,
+			TreatmentId:   treatment.Id,
+			Description:   treatment.Name + " (" + appointment.Date + ")",
+			Quantity:      1,
+			UnitPrice:     treatment.Price,
+			TaxRate:       treatment.TaxRate,
+		})
+	}
+	for _, line := range invoice.Lines {
+		if err := s.validateLine(tenantID, &line); err != nil {
+			return domain.Invoice{}, err
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return domain.Invoice{}, errors.New("Nothing to invoice, the patient has no completed appointments left to bill")
+	}
+
+	invoice.Lines = lines
+	invoice.Notes = strings.TrimSpace(invoice.Notes)
+	if len(invoice.Notes) > 255 {
+		return domain.Invoice{}, errors.New("Notes can not be longer than 255 characters")
+	}
+	invoice.Status = domain.InvoiceDraft
+	invoice.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	if err := s.applyCoverage(tenantID, &invoice, treatedOn); err != nil {
+		return domain.Invoice{}, err
+	}
+	invoice.Compute()
+	id, err := s.invoiceRepo.Create(tenantID, invoice)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	return s.invoiceRepo.GetByID(tenantID, id)
+}
+
+// billableAppointments returns the appointments to bill: those requested,
+// which must be completed, have a treatment and not be invoiced yet, or
+// every appointment of the patient meeting these conditions.
+func (s *invoiceService) billableAppointments(tenantID int, patientID int, appointmentIDs []int) ([]domain.Appointment, error) {
+	appointments, err := s.appointmentRepo.Search(tenantID, domain.AppointmentFilter{PatientId: patientID})
+	if err != nil {
+		return nil, err
+	}
+	billed, err := s.billedAppointments(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	if len(appointmentIDs) == 0 {
+		billable := []domain.Appointment{}
+		for _, appointment := range appointments {
+			if appointment.CompletedAt != nil && appointment.Treatment.Id != 0 && !billed[appointment.Id] {
+				billable = append(billable, appointment)
+			}
+		}
+		return billable, nil
+	}
+
+	byID := map[int]domain.Appointment{}
+	for _, appointment := range appointments {
+		byID[appointment.Id] = appointment
+	}
+	billable := []domain.Appointment{}
+	seen := map[int]bool{}
+	for _, id := range appointmentIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		appointment, ok := byID[id]
+		prefix := "Appointment " + strconv.Itoa(id)
+		switch {
+		case !ok:
+			return nil, errors.New(prefix + " does not belong to the patient")
+		case appointment.CompletedAt == nil:
+			return nil, errors.New(prefix + " is not completed")
+		case appointment.Treatment.Id == 0:
+			return nil, errors.New(prefix + " has no treatment to bill")
+		case billed[id]:
+			return nil, errors.New(prefix + " is already invoiced")
+		}
+		billable = append(billable, appointment)
+	}
+	return billable, nil
+}
+
+func (s *invoiceService) billedAppointments(tenantID int, patientID int) (map[int]bool, error) {
+	ids, err := s.invoiceRepo.GetBilledAppointments(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	billed := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		billed[id] = true
+	}
+	return billed, nil
+}
+
+// applyCoverage splits the lines between the patient and the coverage
+// requested in CoverageId, or else the coverage of the patient valid on the
+// day of the first treatment. A line is covered only when the coverage was in
+// force on the day of its appointment in the time zone of the clinic, given in
+// treatedOn, or on the day the invoice is drafted for the lines added by hand.
+// Each line is covered by the rule of the provider for its treatment and the
+// plan of the patient; the patient pays the rest. Providers no longer accepted
+// cover nothing.
+func (s *invoiceService) applyCoverage(tenantID int, invoice *domain.Invoice, treatedOn map[int]time.Time) error {
+	for i := range invoice.Lines {
+		invoice.Lines[i].Covered = 0
+	}
+	coverages, err := s.insuranceRepo.GetCoverages(tenantID, invoice.PatientId)
+	if err != nil {
+		return err
+	}
+	loc, err := domain.LoadLocation("")
+	if err != nil {
+		return err
+	}
+	today := invoice.CreatedAt.In(loc)
+	days := make([]time.Time, len(invoice.Lines))
+	for i, line := range invoice.Lines {
+		days[i] = today
+		if day, ok := treatedOn[line.AppointmentId]; ok {
+			days[i] = day
+		}
+	}
+
+	var coverage domain.Coverage
+	found := false
+	for _, c := range coverages {
+		if invoice.CoverageId != 0 && c.Id == invoice.CoverageId {
+			coverage, found = c, true
+			break
+		}
+	}
+	for _, day := range days {
+		if invoice.CoverageId != 0 || found {
+			break
+		}
+		for _, c := range coverages {
+			if c.ValidOn(day) {
+				coverage, found = c, true
+			}
+		}
+	}
+	if !found {
+		if invoice.CoverageId != 0 {
+			return errors.New("Coverage not found for the patient")
+		}
+		return nil
+	}
+	provider, err := s.insuranceRepo.GetProvider(tenantID, coverage.ProviderId)
+	if err != nil {
+		return err
+	}
+	if !provider.Active {
+		if invoice.CoverageId != 0 {
+			return errors.New(provider.Name + " is no longer accepted")
+		}
+		return nil
+	}
+
+	invoice.CoverageId = coverage.Id
+	for i := range invoice.Lines {
+		line := &invoice.Lines[i]
+		if line.TreatmentId == 0 || !coverage.ValidOn(days[i]) {
+			continue
+		}
+		if rule, ok := domain.RuleFor(provider.Rules, coverage.Plan, line.TreatmentId); ok {
+			line.Compute()
+			line.Covered = rule.Cover(*line)
+		}
+	}
+	return nil
+}
+
+// validateLine checks a line added by hand. Appointments are only billed
+// through appointmentIDs.
+func (s *invoiceService) validateLine(tenantID int, line *domain.InvoiceLine) error {
+	line.Id = 0
+	line.AppointmentId = 0
+	line.Description = strings.TrimSpace(line.Description)
+	if line.Description == "" {
+		return errors.New("Description is required for every line")
+	}
+	if len(line.Description) > 255 {
+		return errors.New("Description can not be longer than 255 characters")
+	}
+	if line.Quantity == 0 {
+		line.Quantity = 1
+	}
+	if line.Quantity < 0 || line.UnitPrice < 0 {
+		return errors.New("Quantity and UnitPrice can not be negative")
+	}
+	if line.TaxRate < 0 || line.TaxRate > 10000 {
+		return errors.New("TaxRate must be between 0 and 10000 basis points")
+	}
+	if line.TreatmentId != 0 {
+		if _, err := s.treatmentRepo.GetByID(tenantID, line.TreatmentId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *invoiceService) GetByID(tenantID int, id int) (domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	return invoice, nil
+}
+
+func (s *invoiceService) GetByPatient(tenantID int, patientID int) ([]domain.Invoice, error) {
+	if _, err := s.patientRepo.GetByID(tenantID, patientID); err != nil {
+		return nil, err
+	}
+	invoices, err := s.invoiceRepo.GetByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (s *invoiceService) GetAll(tenantID int, status string) ([]domain.Invoice, error) {
+	switch status {
+	case "", domain.InvoiceDraft, domain.InvoiceIssued, domain.InvoicePaid, domain.InvoiceVoid:
+	default:
+		return nil, errors.New("Invalid status, expected draft, issued, paid or void")
+	}
+	invoices, err := s.invoiceRepo.GetAll(tenantID, status)
+	if err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// SetStatus issues a draft or voids a draft or issued invoice. Voiding an
+// issued invoice requires a reason, its payments must be refunded first and
+// its claims rejected.
+// Invoices are paid by recording payments.
+func (s *invoiceService) SetStatus(tenantID int, id int, status string, reason string) (domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	switch status {
+	case domain.InvoiceIssued:
+		err = s.invoiceRepo.Issue(tenantID, id, now)
+	case domain.InvoicePaid:
+		return domain.Invoice{}, errors.New("Invoices are paid by recording their payments")
+	case domain.InvoiceVoid:
+		reason = strings.TrimSpace(reason)
+		if invoice.Status == domain.InvoiceIssued && reason == "" {
+			return domain.Invoice{}, errors.New("A reason is required to void an issued invoice")
+		}
+		if len(reason) > 255 {
+			return domain.Invoice{}, errors.New("Reason can not be longer than 255 characters")
+		}
+		if invoice.AmountPaid != 0 {
+			return domain.Invoice{}, errors.New("The payments of the invoice must be refunded before voiding it")
+		}
+		claims, err := s.insuranceRepo.GetClaimsByInvoice(tenantID, id)
+		if err != nil {
+			return domain.Invoice{}, err
+		}
+		for _, claim := range claims {
+			if claim.Open() {
+				return domain.Invoice{}, errors.New("The invoice was claimed to the insurer and can only be voided once the claim is rejected")
+			}
+		}
+		err = s.invoiceRepo.Void(tenantID, id, now, reason)
+	default:
+		return domain.Invoice{}, errors.New("Invalid status, expected issued or void")
+	}
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	return s.invoiceRepo.GetByID(tenantID, id)
+}
+
+// Balance sums what the patient was invoiced and paid, net of refunds, and
+// lists the completed appointments still to be billed. Drafts and void
+// invoices are not owed.
+func (s *invoiceService) Balance(tenantID int, patientID int) (domain.PatientBalance, error) {
+	invoices, err := s.GetByPatient(tenantID, patientID)
+	if err != nil {
+		return domain.PatientBalance{}, err
+	}
+	balance := domain.PatientBalance{PatientId: patientID, OpenInvoices: []domain.Invoice{}}
+	for _, invoice := range invoices {
+		switch invoice.Status {
+		case domain.InvoiceIssued:
+			balance.Invoiced += invoice.Total
+			balance.Covered += invoice.Covered
+			balance.Paid += invoice.AmountPaid
+			balance.OpenInvoices = append(balance.OpenInvoices, invoice)
+		case domain.InvoicePaid:
+			balance.Invoiced += invoice.Total
+			balance.Covered += invoice.Covered
+			balance.Paid += invoice.AmountPaid
+		}
+	}
+	balance.Outstanding = balance.Invoiced - balance.Covered - balance.Paid
+
+	unbilled, err := s.billableAppointments(tenantID, patientID, nil)
+	if err != nil {
+		return domain.PatientBalance{}, err
+	}
+	balance.UnbilledAppointments = make([]int, len(unbilled))
+	for i, appointment := range unbilled {
+		balance.UnbilledAppointments[i] = appointment.Id
+	}
+	return balance, nil
+}
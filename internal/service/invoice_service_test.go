@@ -0,0 +1,223 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeInvoiceRepository refuses, like the store, lines of appointments that
+// are already billed.
+type fakeInvoiceRepository struct {
+	repository.InvoiceRepository
+	created domain.Invoice
+	billed  []int
+}
+
+func (r *fakeInvoiceRepository) GetBilledAppointments(tenantID int, patientID int) ([]int, error) {
+	return r.billed, nil
+}
+
+func (r *fakeInvoiceRepository) Create(tenantID int, invoice domain.Invoice) (int, error) {
+	for _, line := range invoice.Lines {
+		for _, id := range r.billed {
+			if line.AppointmentId == id {
+				return 0, errors.New("Appointment " + strconv.Itoa(id) + " is already invoiced")
+			}
+		}
+	}
+	for _, line := range invoice.Lines {
+		if line.AppointmentId != 0 {
+			r.billed = append(r.billed, line.AppointmentId)
+		}
+	}
+	invoice.Id = r.created.Id + 1
+	r.created = invoice
+	return invoice.Id, nil
+}
+
+func (r *fakeInvoiceRepository) GetByID(tenantID int, id int) (domain.Invoice, error) {
+	return r.created, nil
+}
+
+type fakeTreatmentRepository struct {
+	repository.TreatmentRepository
+	specialty domain.Specialty
+}
+
+func (r *fakeTreatmentRepository) GetByID(tenantID int, id int) (domain.Treatment, error) {
+	return domain.Treatment{Id: id, Name: "Limpieza", Price: 10000, Specialty: r.specialty}, nil
+}
+
+type fakeInsuranceRepository struct {
+	repository.InsuranceRepository
+	coverages []domain.Coverage
+	providers []domain.InsuranceProvider
+}
+
+func (r *fakeInsuranceRepository) GetCoverages(tenantID int, patientID int) ([]domain.Coverage, error) {
+	return r.coverages, nil
+}
+
+func (r *fakeInsuranceRepository) GetProvider(tenantID int, id int) (domain.InsuranceProvider, error) {
+	return r.providers[id-1], nil
+}
+
+type invoicePatientRepository struct {
+	repository.PatientRepository
+}
+
+func (r *invoicePatientRepository) GetByID(tenantID int, id int) (domain.Patient, error) {
+	return domain.Patient{Id: id}, nil
+}
+
+func TestGenerateCoversWithThePolicyInForceOnTheTreatmentDate(t *testing.T) {
+	// 23:30 of March 31 in Buenos Aires is already April 1 in UTC, the day the
+	// patient changed provider.
+	startsAt := time.Date(2024, time.April, 1, 2, 30, 0, 0, time.UTC)
+	completed := startsAt.Add(time.Hour)
+	late := domain.Appointment{Id: 7, StartsAt: startsAt, CompletedAt: &completed,
+		Clinic: domain.Clinic{Id: 1, TimeZone: "America/Argentina/Buenos_Aires"}, Treatment: domain.Treatment{Id: 3}}
+	insurance := &fakeInsuranceRepository{
+		coverages: []domain.Coverage{
+			{Id: 1, PatientId: 1, ProviderId: 1, ValidFrom: "01/01/2023", ValidTo: "31/03/2024"},
+			{Id: 2, PatientId: 1, ProviderId: 2, ValidFrom: "01/04/2024"},
+		},
+		providers: []domain.InsuranceProvider{
+			{Id: 1, Name: "OSDE", Active: true, Rules: []domain.CoverageRule{{TreatmentId: 3, Rate: 5000}}},
+			{Id: 2, Name: "Swiss Medical", Active: true, Rules: []domain.CoverageRule{{TreatmentId: 3, Rate: 8000}}},
+		},
+	}
+	s := NewInvoiceService(&fakeInvoiceRepository{}, &invoicePatientRepository{},
+		&fakeAppointmentRepository{appointments: []domain.Appointment{late}}, &fakeTreatmentRepository{}, insurance)
+
+	invoice, err := s.Generate(1, domain.Invoice{PatientId: 1, Lines: []domain.InvoiceLine{
+		{Description: "Radiografía", Quantity: 1, UnitPrice: 2000, TreatmentId: 3},
+	}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if invoice.CoverageId != 1 {
+		t.Fatalf("CoverageId = %d, want the coverage in force on March 31", invoice.CoverageId)
+	}
+	if covered := invoice.Lines[0].Covered; covered != 5000 {
+		t.Errorf("appointment line covered %d, want 5000", covered)
+	}
+	if covered := invoice.Lines[1].Covered; covered != 0 {
+		t.Errorf("line added today covered %d by a coverage that has ended, want 0", covered)
+	}
+}
+
+func TestInvoiceComputeRoundsTaxHalfUpAndCapsTheCoverage(t *testing.T) {
+	invoice := domain.Invoice{Lines: []domain.InvoiceLine{
+		{Quantity: 1, UnitPrice: 5, TaxRate: 1000},
+		{Quantity: 1, UnitPrice: 4, TaxRate: 1000, Covered: -3},
+		{Quantity: 2, UnitPrice: 10000, TaxRate: 2100, Covered: 30000},
+		{Quantity: 3, UnitPrice: 3333, Covered: 5000},
+	}}
+	invoice.Compute()
+
+	want := []struct{ tax, total, covered int64 }{{1, 6, 0}, {0, 4, 0}, {4200, 24200, 24200}, {0, 9999, 5000}}
+	for i, line := range invoice.Lines {
+		if line.Tax != want[i].tax || line.Total != want[i].total || line.Covered != want[i].covered {
+			t.Errorf("line %d has tax %d, total %d and covered %d, want %d, %d and %d", i+1, line.Tax, line.Total, line.Covered, want[i].tax, want[i].total, want[i].covered)
+		}
+	}
+	if invoice.Subtotal != 30008 || invoice.Tax != 4201 || invoice.Total != 34209 || invoice.Covered != 29200 || invoice.PatientTotal != 5009 {
+		t.Errorf("invoice totals %d + %d = %d, covered %d, patient %d, want 30008 + 4201 = 34209, covered 29200, patient 5009",
+			invoice.Subtotal, invoice.Tax, invoice.Total, invoice.Covered, invoice.PatientTotal)
+	}
+}
+
+func TestGenerateSplitsEveryLineWithTheRuleOfThePlan(t *testing.T) {
+	completed := time.Date(2025, 10, 1, 15, 0, 0, 0, time.UTC)
+	appointments := []domain.Appointment{
+		{Id: 7, StartsAt: completed.Add(-time.Hour), CompletedAt: &completed, Date: "01/10/2025",
+			Clinic: domain.Clinic{Id: 1, TimeZone: domain.DefaultTimeZone}, Treatment: domain.Treatment{Id: 3}},
+		{Id: 8, StartsAt: completed.Add(24 * time.Hour), Clinic: domain.Clinic{Id: 1, TimeZone: domain.DefaultTimeZone}, Treatment: domain.Treatment{Id: 3}},
+	}
+	insurance := &fakeInsuranceRepository{
+		coverages: []domain.Coverage{{Id: 4, PatientId: 1, ProviderId: 1, Plan: "210", ValidFrom: "01/01/2024"}},
+		providers: []domain.InsuranceProvider{{Id: 1, Name: "OSDE", Active: true, Rules: []domain.CoverageRule{
+			{TreatmentId: 3, Rate: 8000},
+			{TreatmentId: 3, Plan: "210", Rate: 5000, MaxAmount: 3000},
+		}}},
+	}
+	s := NewInvoiceService(&fakeInvoiceRepository{}, &invoicePatientRepository{},
+		&fakeAppointmentRepository{appointments: appointments}, &fakeTreatmentRepository{}, insurance)
+
+	invoice, err := s.Generate(1, domain.Invoice{PatientId: 1, Lines: []domain.InvoiceLine{
+		{Description: "Sellador", Quantity: 3, UnitPrice: 3333, TaxRate: 2100, TreatmentId: 3},
+		{Description: "Kit de higiene", UnitPrice: 1500, TaxRate: 2100},
+	}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invoice.Lines) != 3 || invoice.Lines[0].AppointmentId != 7 || invoice.Lines[0].Description != "Limpieza (01/10/2025)" {
+		t.Fatalf("lines = %+v, want the completed appointment 7 first", invoice.Lines)
+	}
+	// The plan rule covers half of each line up to 3000 a unit.
+	want := []int64{3000, 6050, 0}
+	for i, line := range invoice.Lines {
+		if line.Covered != want[i] {
+			t.Errorf("line %d covered %d, want %d", i+1, line.Covered, want[i])
+		}
+	}
+	if invoice.Lines[2].Quantity != 1 {
+		t.Errorf("line without quantity bills %d units, want 1", invoice.Lines[2].Quantity)
+	}
+	if invoice.CoverageId != 4 || invoice.Status != domain.InvoiceDraft {
+		t.Errorf("invoice is a %s with coverage %d, want a draft with coverage 4", invoice.Status, invoice.CoverageId)
+	}
+	if invoice.Subtotal != 21499 || invoice.Tax != 2415 || invoice.Total != 23914 || invoice.Covered != 9050 || invoice.PatientTotal != 14864 {
+		t.Errorf("invoice totals %d + %d = %d, covered %d, patient %d, want 21499 + 2415 = 23914, covered 9050, patient 14864",
+			invoice.Subtotal, invoice.Tax, invoice.Total, invoice.Covered, invoice.PatientTotal)
+	}
+}
+
+func TestGenerateBillsEveryAppointmentOnce(t *testing.T) {
+	completed := time.Date(2025, 10, 1, 15, 0, 0, 0, time.UTC)
+	clinic := domain.Clinic{Id: 1, TimeZone: domain.DefaultTimeZone}
+	appointments := &fakeAppointmentRepository{appointments: []domain.Appointment{
+		{Id: 7, CompletedAt: &completed, Clinic: clinic, Treatment: domain.Treatment{Id: 3}},
+		{Id: 8, CompletedAt: &completed, Clinic: clinic},
+		{Id: 9, Clinic: clinic, Treatment: domain.Treatment{Id: 3}},
+	}}
+	invoices := &fakeInvoiceRepository{}
+	s := NewInvoiceService(invoices, &invoicePatientRepository{}, appointments, &fakeTreatmentRepository{}, &fakeInsuranceRepository{})
+
+	invoice, err := s.Generate(1, domain.Invoice{PatientId: 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invoice.Lines) != 1 || invoice.Lines[0].AppointmentId != 7 {
+		t.Fatalf("lines = %+v, want only the completed appointment 7", invoice.Lines)
+	}
+	if _, err := s.Generate(1, domain.Invoice{PatientId: 1}, nil); err == nil {
+		t.Error("second invoice billed appointment 7 again")
+	}
+	for _, id := range []int{7, 8, 9, 10} {
+		if _, err := s.Generate(1, domain.Invoice{PatientId: 1}, []int{id}); err == nil {
+			t.Errorf("appointment %d was billed", id)
+		}
+	}
+	// A draft that read the billed appointments before the first invoice was
+	// saved is refused when saved.
+	stale := NewInvoiceService(&staleInvoiceRepository{invoices}, &invoicePatientRepository{}, appointments, &fakeTreatmentRepository{}, &fakeInsuranceRepository{})
+	if _, err := stale.Generate(1, domain.Invoice{PatientId: 1}, []int{7}); err == nil {
+		t.Error("draft read before the first invoice billed appointment 7 again")
+	}
+}
+
+// staleInvoiceRepository reads the billed appointments as they were before
+// another invoice was saved.
+type staleInvoiceRepository struct {
+	*fakeInvoiceRepository
+}
+
+func (r *staleInvoiceRepository) GetBilledAppointments(tenantID int, patientID int) ([]int, error) {
+	return nil, nil
+}
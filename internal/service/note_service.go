@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"strings"
+	"time"
+)
+
+type NoteService interface {
+	Create(tenantID int, appointmentID int, note domain.ClinicalNote) (domain.ClinicalNote, error)
+	Amend(tenantID int, appointmentID int, noteID int, amendment domain.ClinicalNote) (domain.ClinicalNote, error)
+	GetByAppointment(tenantID int, appointmentID int) ([]domain.ClinicalNote, error)
+	Timeline(tenantID int, patientID int) ([]domain.TimelineEntry, error)
+}
+
+// -------------------------------------------
+type noteService struct {
+	noteRepo        repository.NoteRepository
+	appointmentRepo repository.AppointmentRepository
+	patientRepo     repository.PatientRepository
+}
+
+func NewNoteService(noteRepo repository.NoteRepository, appointmentRepo repository.AppointmentRepository, patientRepo repository.PatientRepository) NoteService {
+	return &noteService{noteRepo, appointmentRepo, patientRepo}
+}
+
+//-------------------------------------------
+
+// Create writes a note for the appointment. The note is authored by the
+// dentist treating the patient and can only be written once the appointment
+// has started.
+func (s *noteService) Create(tenantID int, appointmentID int, note domain.ClinicalNote) (domain.ClinicalNote, error) {
+	appointment, err := s.appointmentRepo.GetByID(tenantID, appointmentID)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	if err := authorNote(&note, appointment); err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	note.AmendsId = 0
+	note.Reason = ""
+	return s.save(tenantID, note)
+}
+
+// Amend corrects a note of the appointment. The original note is kept as
+// written; amending an amendment amends the original note.
+func (s *noteService) Amend(tenantID int, appointmentID int, noteID int, amendment domain.ClinicalNote) (domain.ClinicalNote, error) {
+	original, err := s.noteRepo.GetByID(tenantID, noteID)
+	if err != nil || original.AppointmentId != appointmentID {
+		return domain.ClinicalNote{}, errors.New("Clinical note not found")
+	}
+	appointment, err := s.appointmentRepo.GetByID(tenantID, appointmentID)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	if err := authorNote(&amendment, appointment); err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	amendment.Reason = strings.TrimSpace(amendment.Reason)
+	if amendment.Reason == "" {
+		return domain.ClinicalNote{}, errors.New("Reason is required to amend a note")
+	}
+	amendment.AmendsId = original.Id
+	if original.AmendsId != 0 {
+		amendment.AmendsId = original.AmendsId
+	}
+	return s.save(tenantID, amendment)
+}
+
+func (s *noteService) GetByAppointment(tenantID int, appointmentID int) ([]domain.ClinicalNote, error) {
+	if _, err := s.appointmentRepo.GetByID(tenantID, appointmentID); err != nil {
+		return nil, err
+	}
+	notes, err := s.noteRepo.GetByAppointment(tenantID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	nested := nestAmendments(notes)[appointmentID]
+	if nested == nil {
+		nested = []domain.ClinicalNote{}
+	}
+	return nested, nil
+}
+
+// Timeline lists the appointments of the patient, oldest first, each with its
+// clinical notes.
+func (s *noteService) Timeline(tenantID int, patientID int) ([]domain.TimelineEntry, error) {
+	if _, err := s.patientRepo.GetByID(tenantID, patientID); err != nil {
+		return nil, err
+	}
+	appointments, err := s.appointmentRepo.Search(tenantID, domain.AppointmentFilter{PatientId: patientID})
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.noteRepo.GetByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	byAppointment := nestAmendments(notes)
+
+	timeline := []domain.TimelineEntry{}
+	for _, appointment := range appointments {
+		entry := domain.TimelineEntry{Appointment: appointment, Notes: byAppointment[appointment.Id]}
+		if entry.Notes == nil {
+			entry.Notes = []domain.ClinicalNote{}
+		}
+		timeline = append(timeline, entry)
+	}
+	return timeline, nil
+}
+
+func (s *noteService) save(tenantID int, note domain.ClinicalNote) (domain.ClinicalNote, error) {
+	note.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	id, err := s.noteRepo.Create(tenantID, note)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	note.Id = id
+	return note, nil
+}
+
+// authorNote fills the note with the appointment it belongs to, checking it
+// is written by the treating dentist after the appointment started.
+func authorNote(note *domain.ClinicalNote, appointment domain.Appointment) error {
+	note.Text = strings.TrimSpace(note.Text)
+	if note.Text == "" {
+		return errors.New("Text is required")
+	}
+	if note.DentistId != 0 && note.DentistId != appointment.Dentist.Id {
+		return errors.New("Only the dentist of the appointment can write its notes")
+	}
+	if time.Now().Before(appointment.StartsAt) {
+		return errors.New("Notes can not be written before the appointment starts")
+	}
+	note.AppointmentId = appointment.Id
+	note.PatientId = appointment.Patient.Id
+	note.DentistId = appointment.Dentist.Id
+	note.Amendments = nil
+	return nil
+}
+
+// nestAmendments groups the notes by appointment, the amendments of each note
+// nested under it. The notes are expected in the order they were written.
+func nestAmendments(notes []domain.ClinicalNote) map[int][]domain.ClinicalNote {
+	amendments := map[int][]domain.ClinicalNote{}
+	for _, note := range notes {
+		if note.AmendsId != 0 {
+			amendments[note.AmendsId] = append(amendments[note.AmendsId], note)
+		}
+	}
+	byAppointment := map[int][]domain.ClinicalNote{}
+	for _, note := range notes {
+		if note.AmendsId != 0 {
+			continue
+		}
+		note.Amendments = amendments[note.Id]
+		byAppointment[note.AppointmentId] = append(byAppointment[note.AppointmentId], note)
+	}
+	return byAppointment
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"testing"
+	"time"
+)
+
+// fakeNoteRepository keeps the notes in the order they were written.
+type fakeNoteRepository struct {
+	repository.NoteRepository
+	notes []domain.ClinicalNote
+}
+
+func (r *fakeNoteRepository) Create(tenantID int, note domain.ClinicalNote) (int, error) {
+	note.Id = len(r.notes) + 1
+	r.notes = append(r.notes, note)
+	return note.Id, nil
+}
+
+func (r *fakeNoteRepository) GetByID(tenantID int, id int) (domain.ClinicalNote, error) {
+	if id < 1 || id > len(r.notes) {
+		return domain.ClinicalNote{}, errors.New("Clinical note not found")
+	}
+	return r.notes[id-1], nil
+}
+
+func (r *fakeNoteRepository) GetByAppointment(tenantID int, appointmentID int) ([]domain.ClinicalNote, error) {
+	var notes []domain.ClinicalNote
+	for _, note := range r.notes {
+		if note.AppointmentId == appointmentID {
+			notes = append(notes, note)
+		}
+	}
+	return notes, nil
+}
+
+func newNoteFixture(startsAt time.Time) (NoteService, *fakeNoteRepository) {
+	appointment := domain.Appointment{
+		Id:       7,
+		StartsAt: startsAt,
+		Patient:  domain.Patient{Id: 3},
+		Dentist:  domain.Dentist{Id: 2},
+	}
+	notes := &fakeNoteRepository{}
+	s := NewNoteService(notes, &fakeAppointmentRepository{appointments: []domain.Appointment{appointment}}, &fakePatientRepository{})
+	return s, notes
+}
+
+func TestCreateNoteIsAuthoredByTheDentistAfterTheAppointmentStarts(t *testing.T) {
+	s, notes := newNoteFixture(time.Now().Add(-time.Hour))
+
+	note, err := s.Create(1, 7, domain.ClinicalNote{Text: " Caries in 36 ", AmendsId: 1, Reason: "typo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Id != 1 || note.AppointmentId != 7 || note.PatientId != 3 || note.DentistId != 2 || note.Text != "Caries in 36" {
+		t.Errorf("created %+v, want note 1 of appointment 7, patient 3 and dentist 2", note)
+	}
+	if note.AmendsId != 0 || note.Reason != "" {
+		t.Errorf("created note amends %d for %q, want a new note", note.AmendsId, note.Reason)
+	}
+
+	if _, err := s.Create(1, 7, domain.ClinicalNote{DentistId: 5, Text: "Caries in 36"}); err == nil {
+		t.Error("note by another dentist was created")
+	}
+	if _, err := s.Create(1, 7, domain.ClinicalNote{Text: "  "}); err == nil {
+		t.Error("empty note was created")
+	}
+	if len(notes.notes) != 1 {
+		t.Errorf("stored %d notes, want 1", len(notes.notes))
+	}
+
+	s, _ = newNoteFixture(time.Now().Add(time.Hour))
+	if _, err := s.Create(1, 7, domain.ClinicalNote{Text: "Caries in 36"}); err == nil {
+		t.Error("note was created before the appointment started")
+	}
+}
+
+func TestAmendKeepsTheOriginalAndNestsAmendmentsUnderIt(t *testing.T) {
+	s, _ := newNoteFixture(time.Now().Add(-time.Hour))
+	original, err := s.Create(1, 7, domain.ClinicalNote{Text: "Caries in 36"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Amend(1, 7, original.Id, domain.ClinicalNote{Text: "Caries in 46"}); err == nil {
+		t.Error("amendment without a reason was saved")
+	}
+	if _, err := s.Amend(1, 8, original.Id, domain.ClinicalNote{Text: "Caries in 46", Reason: "Wrong tooth"}); err == nil {
+		t.Error("note was amended through another appointment")
+	}
+	first, err := s.Amend(1, 7, original.Id, domain.ClinicalNote{Text: "Caries in 46", Reason: "Wrong tooth"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.Amend(1, 7, first.Id, domain.ClinicalNote{Text: "Caries in 46, distal", Reason: "Missing surface"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.AmendsId != original.Id || second.AmendsId != original.Id {
+		t.Errorf("amendments amend %d and %d, want both %d", first.AmendsId, second.AmendsId, original.Id)
+	}
+
+	notes, err := s.GetByAppointment(1, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 1 || notes[0].Text != "Caries in 36" {
+		t.Fatalf("listed %+v, want only the original note", notes)
+	}
+	if len(notes[0].Amendments) != 2 || notes[0].Amendments[0].Id != first.Id || notes[0].Amendments[1].Id != second.Id {
+		t.Errorf("original note has amendments %+v, want %d and %d", notes[0].Amendments, first.Id, second.Id)
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"strings"
+	"time"
+)
+
+type OdontogramService interface {
+	Record(tenantID int, appointmentID int, findings []domain.ToothFinding) ([]domain.ToothFinding, error)
+	Chart(tenantID int, patientID int, date string, timeZone string) (domain.Odontogram, error)
+	Findings(tenantID int, patientID int, tooth int) ([]domain.ToothFinding, error)
+}
+
+// -------------------------------------------
+type odontogramService struct {
+	odontogramRepo  repository.OdontogramRepository
+	appointmentRepo repository.AppointmentRepository
+	patientRepo     repository.PatientRepository
+}
+
+func NewOdontogramService(odontogramRepo repository.OdontogramRepository, appointmentRepo repository.AppointmentRepository, patientRepo repository.PatientRepository) OdontogramService {
+	return &odontogramService{odontogramRepo, appointmentRepo, patientRepo}
+}
+
+//-------------------------------------------
+
+// Record stores the findings of a visit. They are made by the dentist of the
+// appointment at its start, so they can only be recorded once it started.
+func (s *odontogramService) Record(tenantID int, appointmentID int, findings []domain.ToothFinding) ([]domain.ToothFinding, error) {
+	if len(findings) == 0 {
+		return nil, errors.New("At least one finding is required")
+	}
+	appointment, err := s.appointmentRepo.GetByID(tenantID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().Before(appointment.StartsAt) {
+		return nil, errors.New("Findings can not be recorded before the appointment starts")
+	}
+	previous, err := s.odontogramRepo.GetByPatient(tenantID, appointment.Patient.Id)
+	if err != nil {
+		return nil, err
+	}
+	chart := domain.BuildOdontogram(appointment.Patient.Id, previous, appointment.StartsAt.Add(-time.Second))
+	extracted := map[int]bool{}
+	for _, tooth := range chart.Teeth {
+		extracted[tooth.Tooth] = tooth.Condition == domain.ToothExtracted
+	}
+
+	for i := range findings {
+		if err := validateToothFinding(&findings[i]); err != nil {
+			return nil, fmt.Errorf("Finding %d: %w", i+1, err)
+		}
+		if extracted[findings[i].Tooth] {
+			return nil, fmt.Errorf("Finding %d: tooth %d was extracted", i+1, findings[i].Tooth)
+		}
+		findings[i].PatientId = appointment.Patient.Id
+		findings[i].AppointmentId = appointment.Id
+		findings[i].DentistId = appointment.Dentist.Id
+		findings[i].ExaminedAt = appointment.StartsAt.UTC()
+	}
+
+	ids, err := s.odontogramRepo.CreateBatch(tenantID, findings)
+	if err != nil {
+		return nil, err
+	}
+	for i := range findings {
+		findings[i].Id = ids[i]
+	}
+	return findings, nil
+}
+
+// Chart builds the odontogram of the patient as of the end of date, a local
+// date in timeZone (DefaultTimeZone when empty). An empty date builds the
+// current chart.
+func (s *odontogramService) Chart(tenantID int, patientID int, date string, timeZone string) (domain.Odontogram, error) {
+	asOf := time.Now().UTC()
+	if date != "" {
+		loc, err := domain.LoadLocation(timeZone)
+		if err != nil {
+			return domain.Odontogram{}, errors.New("Invalid time zone: " + timeZone)
+		}
+		day, err := time.ParseInLocation(domain.DateLayout, date, loc)
+		if err != nil {
+			return domain.Odontogram{}, errors.New("Invalid date, expected dd/MM/yyyy")
+		}
+		asOf = day.AddDate(0, 0, 1).Add(-time.Second).UTC()
+	}
+	findings, err := s.Findings(tenantID, patientID, 0)
+	if err != nil {
+		return domain.Odontogram{}, err
+	}
+	return domain.BuildOdontogram(patientID, findings, asOf), nil
+}
+
+// Findings lists the findings of the patient in the order they were made,
+// only those of the given tooth when not 0.
+func (s *odontogramService) Findings(tenantID int, patientID int, tooth int) ([]domain.ToothFinding, error) {
+	if tooth != 0 && !domain.ValidTooth(tooth) {
+		return nil, errors.New("Invalid tooth, expected a FDI tooth number")
+	}
+	if _, err := s.patientRepo.GetByID(tenantID, patientID); err != nil {
+		return nil, err
+	}
+	findings, err := s.odontogramRepo.GetByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	if tooth == 0 {
+		return findings, nil
+	}
+	filtered := []domain.ToothFinding{}
+	for _, finding := range findings {
+		if finding.Tooth == tooth {
+			filtered = append(filtered, finding)
+		}
+	}
+	return filtered, nil
+}
+
+func validateToothFinding(finding *domain.ToothFinding) error {
+	finding.Condition = strings.ToLower(strings.TrimSpace(finding.Condition))
+	finding.Notes = strings.TrimSpace(finding.Notes)
+	if !domain.ValidTooth(finding.Tooth) {
+		return errors.New("invalid tooth, expected a FDI tooth number")
+	}
+	if !domain.ValidToothCondition(finding.Condition) {
+		return errors.New("invalid condition, expected healthy, caries, filling, crown or extracted")
+	}
+	surfaces, ok := domain.NormalizeSurfaces(finding.Surfaces)
+	if !ok {
+		return errors.New("invalid surfaces, expected M, D, O, V or L")
+	}
+	finding.Surfaces = surfaces
+	switch finding.Condition {
+	case domain.ToothCaries, domain.ToothFilling:
+		if surfaces == "" {
+			return errors.New(finding.Condition + " must be recorded on surfaces")
+		}
+	case domain.ToothCrown, domain.ToothExtracted:
+		if surfaces != "" {
+			return errors.New(finding.Condition + " is recorded on the whole tooth, without surfaces")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"testing"
+	"time"
+)
+
+// fakeOdontogramRepository keeps the findings in the order they were made.
+type fakeOdontogramRepository struct {
+	repository.OdontogramRepository
+	findings []domain.ToothFinding
+}
+
+func (r *fakeOdontogramRepository) GetByPatient(tenantID int, patientID int) ([]domain.ToothFinding, error) {
+	var findings []domain.ToothFinding
+	for _, finding := range r.findings {
+		if finding.PatientId == patientID {
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+func (r *fakeOdontogramRepository) CreateBatch(tenantID int, findings []domain.ToothFinding) ([]int, error) {
+	ids := make([]int, len(findings))
+	for i, finding := range findings {
+		finding.Id = len(r.findings) + 1
+		r.findings = append(r.findings, finding)
+		ids[i] = finding.Id
+	}
+	return ids, nil
+}
+
+func TestRecordFindingsOfTheVisit(t *testing.T) {
+	startsAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	odontogram := &fakeOdontogramRepository{findings: []domain.ToothFinding{
+		{Id: 1, PatientId: 3, Tooth: 18, Condition: domain.ToothExtracted, ExaminedAt: startsAt.AddDate(-1, 0, 0)},
+	}}
+	appointments := &fakeAppointmentRepository{appointments: []domain.Appointment{
+		{Id: 7, StartsAt: startsAt, Patient: domain.Patient{Id: 3}, Dentist: domain.Dentist{Id: 2}},
+		{Id: 8, StartsAt: time.Now().Add(time.Hour), Patient: domain.Patient{Id: 3}, Dentist: domain.Dentist{Id: 2}},
+	}}
+	s := NewOdontogramService(odontogram, appointments, &invoicePatientRepository{})
+
+	findings, err := s.Record(1, 7, []domain.ToothFinding{
+		{Tooth: 36, Surfaces: "om", Condition: " Caries "},
+		{Tooth: 11, Condition: domain.ToothCrown},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, finding := range findings {
+		if finding.PatientId != 3 || finding.AppointmentId != 7 || finding.DentistId != 2 || !finding.ExaminedAt.Equal(startsAt) {
+			t.Errorf("recorded %+v, want it made by dentist 2 on patient 3 at %v", finding, startsAt)
+		}
+	}
+	if findings[0].Id != 2 || findings[0].Surfaces != "MO" || findings[0].Condition != domain.ToothCaries {
+		t.Errorf("recorded %+v, want finding 2 of caries on MO", findings[0])
+	}
+
+	invalid := [][]domain.ToothFinding{
+		nil,
+		{{Tooth: 19, Condition: domain.ToothHealthy}},
+		{{Tooth: 36, Condition: "fractured"}},
+		{{Tooth: 36, Surfaces: "X", Condition: domain.ToothCaries}},
+		{{Tooth: 36, Condition: domain.ToothFilling}},
+		{{Tooth: 36, Surfaces: "O", Condition: domain.ToothCrown}},
+		{{Tooth: 18, Surfaces: "O", Condition: domain.ToothCaries}},
+	}
+	for _, batch := range invalid {
+		if _, err := s.Record(1, 7, batch); err == nil {
+			t.Errorf("Record(%+v) succeeded, want an error", batch)
+		}
+	}
+	if _, err := s.Record(1, 8, []domain.ToothFinding{{Tooth: 36, Surfaces: "O", Condition: domain.ToothCaries}}); err == nil {
+		t.Error("findings were recorded before the appointment started")
+	}
+	if len(odontogram.findings) != 3 {
+		t.Errorf("stored %d findings, want 3", len(odontogram.findings))
+	}
+}
+
+func TestChartAsOfTheEndOfALocalDate(t *testing.T) {
+	// 22:00 of 19/10 in Buenos Aires is 01:00 of 20/10 in UTC.
+	odontogram := &fakeOdontogramRepository{findings: []domain.ToothFinding{
+		{Id: 1, PatientId: 3, Tooth: 36, Surfaces: "MO", Condition: domain.ToothCaries, ExaminedAt: time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC)},
+		{Id: 2, PatientId: 3, Tooth: 36, Surfaces: "M", Condition: domain.ToothFilling, ExaminedAt: time.Date(2025, 10, 20, 1, 0, 0, 0, time.UTC)},
+		{Id: 3, PatientId: 3, Tooth: 36, Condition: domain.ToothCrown, ExaminedAt: time.Date(2025, 10, 20, 13, 0, 0, 0, time.UTC)},
+	}}
+	s := NewOdontogramService(odontogram, &fakeAppointmentRepository{}, &invoicePatientRepository{})
+
+	chart, err := s.Chart(1, 3, "19/10/2025", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chart.Teeth) != 1 {
+		t.Fatalf("chart has %d teeth, want 1", len(chart.Teeth))
+	}
+	tooth := chart.Teeth[0]
+	if tooth.Condition != "" || tooth.Surfaces["M"] != domain.ToothFilling || tooth.Surfaces["O"] != domain.ToothCaries {
+		t.Errorf("tooth 36 = %+v, want a filling on M and caries on O", tooth)
+	}
+
+	chart, err = s.Chart(1, 3, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tooth := chart.Teeth[0]; tooth.Condition != domain.ToothCrown || len(tooth.Surfaces) != 0 {
+		t.Errorf("tooth 36 = %+v, want a crown", tooth)
+	}
+
+	if _, err := s.Chart(1, 3, "2025-10-19", ""); err == nil {
+		t.Error("chart of an invalid date was built")
+	}
+}
@@ -1,17 +1,34 @@
 package service
 
 import (
+	"errors"
+	"net/mail"
 	"proyecto_final_go/internal/domain"
 	"proyecto_final_go/internal/repository"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// phonePattern accepts international (E.164) phone numbers.
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// Limits of a patient search.
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchTerms     = 6
 )
 
 type PatientService interface {
-	Create(patient domain.Patient) error
-	GetByID(id int) (domain.Patient, error)
-	GetAll() ([]domain.Patient, error)
-	Update(patient domain.Patient) error
-	PatchAddress(id int, address string) error
-	Delete(id int) error
+	Create(tenantID int, patient domain.Patient) error
+	GetByID(tenantID int, id int) (domain.Patient, error)
+	GetAll(tenantID int) ([]domain.Patient, error)
+	Each(tenantID int, fn func(domain.Patient) error) error
+	Search(tenantID int, q string, limit int) ([]domain.Patient, error)
+	Update(tenantID int, patient domain.Patient) error
+	PatchAddress(tenantID int, id int, address string) error
+	Delete(tenantID int, id int) error
 }
 
 // -------------------------------------------
@@ -25,24 +42,27 @@ func NewPatientService(r repository.PatientRepository) PatientService {
 
 //-------------------------------------------
 
-func (s *patientService) GetByID(id int) (domain.Patient, error) {
-	p, err := s.r.GetByID(id)
+func (s *patientService) GetByID(tenantID int, id int) (domain.Patient, error) {
+	p, err := s.r.GetByID(tenantID, id)
 	if err != nil {
 		return domain.Patient{}, err
 	}
 	return p, nil
 }
 
-func (s *patientService) Create(p domain.Patient) error {
-	err := s.r.Create(p)
+func (s *patientService) Create(tenantID int, p domain.Patient) error {
+	if err := validateContact(p); err != nil {
+		return err
+	}
+	err := s.r.Create(tenantID, p)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *patientService) Update(pa domain.Patient) error {
-	p, err := s.r.GetByID(pa.Id)
+func (s *patientService) Update(tenantID int, pa domain.Patient) error {
+	p, err := s.r.GetByID(tenantID, pa.Id)
 	if err != nil {
 		return err
 	}
@@ -61,33 +81,89 @@ func (s *patientService) Update(pa domain.Patient) error {
 	if pa.ReleaseDate != "" {
 		p.ReleaseDate = pa.ReleaseDate
 	}
-	err = s.r.Update(p)
+	if pa.Email != "" {
+		p.Email = pa.Email
+	}
+	if pa.Phone != "" {
+		p.Phone = pa.Phone
+	}
+	if err := validateContact(p); err != nil {
+		return err
+	}
+	err = s.r.Update(tenantID, p)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *patientService) Delete(id int) error {
-	err := s.r.Delete(id)
+func (s *patientService) Delete(tenantID int, id int) error {
+	err := s.r.Delete(tenantID, id)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *patientService) GetAll() ([]domain.Patient, error) {
-	patients, err := s.r.GetAll()
+func (s *patientService) GetAll(tenantID int) ([]domain.Patient, error) {
+	patients, err := s.r.GetAll(tenantID)
 	if err != nil {
 		return nil, err
 	}
 	return patients, nil
 }
 
-func (s *patientService) PatchAddress(id int, address string) error {
-	err := s.r.PatchAddress(id, address)
+// Each calls fn with every patient, streaming them from the store.
+func (s *patientService) Each(tenantID int, fn func(domain.Patient) error) error {
+	return s.r.Each(tenantID, fn)
+}
+
+// Search finds patients by the start of the words of their name, DNI or
+// address, ignoring case and accents. A DNI may be written with dots.
+func (s *patientService) Search(tenantID int, q string, limit int) ([]domain.Patient, error) {
+	terms := searchTerms(q)
+	if len(terms) == 0 {
+		return nil, errors.New("The search needs at least one letter or digit")
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	return s.r.Search(tenantID, terms, limit)
+}
+
+// searchTerms splits a query in words of letters and digits, which keeps
+// full-text operators out of the terms.
+func searchTerms(q string) []string {
+	q = strings.ReplaceAll(q, ".", "")
+	terms := strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+	return terms
+}
+
+func (s *patientService) PatchAddress(tenantID int, id int, address string) error {
+	err := s.r.PatchAddress(tenantID, id, address)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// validateContact checks the optional contact fields used for reminders.
+func validateContact(p domain.Patient) error {
+	if p.Email != "" {
+		if _, err := mail.ParseAddress(p.Email); err != nil {
+			return errors.New("Invalid email address")
+		}
+	}
+	if p.Phone != "" && !phonePattern.MatchString(p.Phone) {
+		return errors.New("Invalid phone number, expected international format like +5491155550000")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"proyecto_final_go/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// searchPatientRepository records the terms and limit of the last search.
+type searchPatientRepository struct {
+	fakePatientRepository
+	terms []string
+	limit int
+}
+
+func (r *searchPatientRepository) Search(tenantID int, terms []string, limit int) ([]domain.Patient, error) {
+	r.terms = terms
+	r.limit = limit
+	return nil, nil
+}
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{"Pérez", []string{"Pérez"}},
+		{"30.123.456", []string{"30123456"}},
+		{"  juan   pe ", []string{"juan", "pe"}},
+		{`+gomez -"san martin"* ~(x)`, []string{"gomez", "san", "martin", "x"}},
+		{"O'Connor", []string{"O", "Connor"}},
+		{"a b c d e f g h", []string{"a", "b", "c", "d", "e", "f"}},
+		{"+-*\"", []string{}},
+	}
+	for _, test := range tests {
+		if got := searchTerms(test.q); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("searchTerms(%q) = %q, want %q", test.q, got, test.want)
+		}
+	}
+}
+
+func TestSearchLimits(t *testing.T) {
+	r := &searchPatientRepository{}
+	s := NewPatientService(r)
+	tests := []struct {
+		limit, want int
+	}{
+		{0, defaultSearchLimit},
+		{-3, defaultSearchLimit},
+		{5, 5},
+		{maxSearchLimit + 1, maxSearchLimit},
+	}
+	for _, test := range tests {
+		if _, err := s.Search(1, "juan", test.limit); err != nil {
+			t.Fatal(err)
+		}
+		if r.limit != test.want {
+			t.Errorf("Search with limit %d searched %d, want %d", test.limit, r.limit, test.want)
+		}
+	}
+
+	r.terms = nil
+	if _, err := s.Search(1, "*** ", 10); err == nil {
+		t.Error("Search without letters or digits succeeded, want an error")
+	}
+	if r.terms != nil {
+		t.Errorf("Search without letters or digits reached the store with %q", r.terms)
+	}
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/payment"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PaymentService interface {
+	Record(ctx context.Context, tenantID int, invoiceID int, p domain.Payment) (domain.Payment, error)
+	Refund(ctx context.Context, tenantID int, paymentID int, refund domain.Payment) (domain.Payment, error)
+	GetByID(tenantID int, id int) (domain.Payment, error)
+	GetByInvoice(tenantID int, invoiceID int) ([]domain.Payment, error)
+	GetByPatient(tenantID int, patientID int) ([]domain.Payment, error)
+	CashClose(tenantID int, clinicID int, date string) (domain.CashClose, error)
+}
+
+// -------------------------------------------
+type paymentService struct {
+	paymentRepo     repository.PaymentRepository
+	invoiceRepo     repository.InvoiceRepository
+	patientRepo     repository.PatientRepository
+	appointmentRepo repository.AppointmentRepository
+	clinicRepo      repository.ClinicRepository
+	provider        payment.Provider
+}
+
+func NewPaymentService(paymentRepo repository.PaymentRepository, invoiceRepo repository.InvoiceRepository, patientRepo repository.PatientRepository, appointmentRepo repository.AppointmentRepository, clinicRepo repository.ClinicRepository, provider payment.Provider) PaymentService {
+	return &paymentService{paymentRepo, invoiceRepo, patientRepo, appointmentRepo, clinicRepo, provider}
+}
+
+//-------------------------------------------
+
+// Record records a payment of the invoice, for up to what is due. Card
+// payments are recorded as pending before they are charged through the
+// payment provider, so concurrent payments can not cover more than is due,
+// and completed or voided with its answer. A payment left pending, because
+// the answer could not be recorded, keeps its amount reserved until it is
+// reconciled with the provider.
+func (s *paymentService) Record(ctx context.Context, tenantID int, invoiceID int, p domain.Payment) (domain.Payment, error) {
+	invoice, err := s.invoiceRepo.GetByID(tenantID, invoiceID)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	if invoice.Status != domain.InvoiceIssued {
+		return domain.Payment{}, errors.New("Payments can only be recorded for issued invoices")
+	}
+	if p.Amount <= 0 {
+		return domain.Payment{}, errors.New("Amount must be positive")
+	}
+	if p.Amount > invoice.AmountDue {
+		return domain.Payment{}, errors.New("The payment exceeds the amount due of " + strconv.FormatInt(invoice.AmountDue, 10) + " cents")
+	}
+	if err := validatePayment(&p); err != nil {
+		return domain.Payment{}, err
+	}
+	if p.ClinicId, err = s.paymentClinic(tenantID, invoice, p.ClinicId); err != nil {
+		return domain.Payment{}, err
+	}
+	p.PatientId = invoice.PatientId
+	p.InvoiceId = invoice.Id
+	p.Kind = domain.PaymentKindPayment
+	p.RefundOf = 0
+	p.Provider = ""
+	p.Status = domain.PaymentCompleted
+	p.ReceivedAt = time.Now().UTC().Truncate(time.Second)
+
+	if p.Method != domain.PaymentCard {
+		id, err := s.paymentRepo.Create(tenantID, p)
+		if err != nil {
+			return domain.Payment{}, err
+		}
+		return s.paymentRepo.GetByID(tenantID, id)
+	}
+
+	p.Status = domain.PaymentPending
+	id, err := s.paymentRepo.Create(tenantID, p)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	request := payment.Request{
+		Amount:      p.Amount,
+		Description: "Invoice " + strconv.Itoa(invoice.Number),
+		Token:       p.Token,
+		Reference:   p.Reference,
+	}
+	reference, err := s.provider.Charge(ctx, request)
+	if err != nil {
+		if voidErr := s.paymentRepo.Void(tenantID, id); voidErr != nil {
+			return domain.Payment{}, errors.Join(errors.New("The card payment failed: "+err.Error()), voidErr)
+		}
+		return domain.Payment{}, errors.New("The card payment failed: " + err.Error())
+	}
+	if err := s.paymentRepo.Complete(tenantID, id, s.provider.Name(), reference); err != nil {
+		return domain.Payment{}, errors.New("The card payment " + reference + " was charged but is left pending: " + err.Error())
+	}
+	return s.paymentRepo.GetByID(tenantID, id)
+}
+
+// Refund gives back part or all of a payment, by default what is left of it,
+// with the method it was paid with. Card refunds are recorded as pending
+// before the provider is called, so concurrent refunds can not give back more
+// than was paid, and completed or voided with its answer. A refund left
+// pending, because the answer could not be recorded, keeps its amount
+// reserved until it is reconciled with the provider.
+func (s *paymentService) Refund(ctx context.Context, tenantID int, paymentID int, refund domain.Payment) (domain.Payment, error) {
+	original, err := s.paymentRepo.GetByID(tenantID, paymentID)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	if original.Kind != domain.PaymentKindPayment {
+		return domain.Payment{}, errors.New("Only payments can be refunded")
+	}
+	if original.Status != domain.PaymentCompleted {
+		return domain.Payment{}, errors.New("Only completed payments can be refunded")
+	}
+	entries, err := s.paymentRepo.GetByInvoice(tenantID, original.InvoiceId)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	left := original.Amount
+	for _, entry := range entries {
+		if entry.RefundOf == original.Id && entry.Status != domain.PaymentVoided {
+			left -= entry.Amount
+		}
+	}
+	if left == 0 {
+		return domain.Payment{}, errors.New("The payment was already refunded")
+	}
+	if refund.Amount == 0 {
+		refund.Amount = left
+	}
+	if refund.Amount < 0 || refund.Amount > left {
+		return domain.Payment{}, errors.New("Amount must be positive and at most the " + strconv.FormatInt(left, 10) + " cents left of the payment")
+	}
+	refund.Method = original.Method
+	if err := validatePayment(&refund); err != nil {
+		return domain.Payment{}, err
+	}
+	if refund.ClinicId == 0 {
+		refund.ClinicId = original.ClinicId
+	} else if _, err := s.clinicRepo.GetByID(tenantID, refund.ClinicId); err != nil {
+		return domain.Payment{}, err
+	}
+	refund.PatientId = original.PatientId
+	refund.InvoiceId = original.InvoiceId
+	refund.Kind = domain.PaymentKindRefund
+	refund.RefundOf = original.Id
+	refund.Provider = ""
+	refund.Status = domain.PaymentCompleted
+	refund.ReceivedAt = time.Now().UTC().Truncate(time.Second)
+
+	if original.Method != domain.PaymentCard {
+		id, err := s.paymentRepo.Create(tenantID, refund)
+		if err != nil {
+			return domain.Payment{}, err
+		}
+		return s.paymentRepo.GetByID(tenantID, id)
+	}
+
+	if original.Provider != s.provider.Name() {
+		return domain.Payment{}, errors.New("The payment was made with " + original.Provider + " and can not be refunded with " + s.provider.Name())
+	}
+	refund.Status = domain.PaymentPending
+	id, err := s.paymentRepo.Create(tenantID, refund)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	request := payment.Request{Amount: refund.Amount, Description: "Refund of payment " + strconv.Itoa(original.Id), Reference: refund.Reference}
+	reference, err := s.provider.Refund(ctx, original.Reference, request)
+	if err != nil {
+		if voidErr := s.paymentRepo.Void(tenantID, id); voidErr != nil {
+			return domain.Payment{}, errors.Join(errors.New("The card refund failed: "+err.Error()), voidErr)
+		}
+		return domain.Payment{}, errors.New("The card refund failed: " + err.Error())
+	}
+	if err := s.paymentRepo.Complete(tenantID, id, s.provider.Name(), reference); err != nil {
+		return domain.Payment{}, errors.New("The card refund " + reference + " was made but is left pending: " + err.Error())
+	}
+	return s.paymentRepo.GetByID(tenantID, id)
+}
+
+// paymentClinic returns the clinic the money is received at: the one given,
+// or else the clinic of the first appointment billed in the invoice.
+func (s *paymentService) paymentClinic(tenantID int, invoice domain.Invoice, clinicID int) (int, error) {
+	if clinicID != 0 {
+		if _, err := s.clinicRepo.GetByID(tenantID, clinicID); err != nil {
+			return 0, err
+		}
+		return clinicID, nil
+	}
+	for _, line := range invoice.Lines {
+		if line.AppointmentId == 0 {
+			continue
+		}
+		appointment, err := s.appointmentRepo.GetByID(tenantID, line.AppointmentId)
+		if err != nil {
+			return 0, err
+		}
+		if appointment.Clinic.Id != 0 {
+			return appointment.Clinic.Id, nil
+		}
+	}
+	return 0, errors.New("ClinicId is required, the invoice bills no appointment")
+}
+
+func validatePayment(p *domain.Payment) error {
+	switch p.Method {
+	case domain.PaymentCash, domain.PaymentCard, domain.PaymentTransfer:
+	default:
+		return errors.New("Invalid method, expected cash, card or transfer")
+	}
+	p.Reference = strings.TrimSpace(p.Reference)
+	if len(p.Reference) > 128 {
+		return errors.New("Reference can not be longer than 128 characters")
+	}
+	p.Notes = strings.TrimSpace(p.Notes)
+	if len(p.Notes) > 255 {
+		return errors.New("Notes can not be longer than 255 characters")
+	}
+	return nil
+}
+
+func (s *paymentService) GetByID(tenantID int, id int) (domain.Payment, error) {
+	p, err := s.paymentRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	return p, nil
+}
+
+func (s *paymentService) GetByInvoice(tenantID int, invoiceID int) ([]domain.Payment, error) {
+	if _, err := s.invoiceRepo.GetByID(tenantID, invoiceID); err != nil {
+		return nil, err
+	}
+	payments, err := s.paymentRepo.GetByInvoice(tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (s *paymentService) GetByPatient(tenantID int, patientID int) ([]domain.Payment, error) {
+	if _, err := s.patientRepo.GetByID(tenantID, patientID); err != nil {
+		return nil, err
+	}
+	payments, err := s.paymentRepo.GetByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// CashClose sums what the clinic took in on a local date, today when empty,
+// by method.
+func (s *paymentService) CashClose(tenantID int, clinicID int, date string) (domain.CashClose, error) {
+	clinic, err := s.clinicRepo.GetByID(tenantID, clinicID)
+	if err != nil {
+		return domain.CashClose{}, err
+	}
+	loc, err := clinic.Location()
+	if err != nil {
+		return domain.CashClose{}, errors.New("Invalid time zone: " + clinic.TimeZone)
+	}
+	if date == "" {
+		date = time.Now().In(loc).Format(domain.DateLayout)
+	}
+	day, err := time.ParseInLocation(domain.DateLayout, date, loc)
+	if err != nil {
+		return domain.CashClose{}, errors.New("Invalid date, expected dd/MM/yyyy")
+	}
+	payments, err := s.paymentRepo.GetByClinic(tenantID, clinic.Id, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return domain.CashClose{}, err
+	}
+	return domain.NewCashClose(clinic, date, payments), nil
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/payment"
+	"sync"
+	"testing"
+)
+
+// fakePaymentRepository keeps the ledger of one invoice in memory. Create
+// checks payments against what is due and refunds against what is left of
+// their payment under a lock, like the SQL store does with the invoice row.
+type fakePaymentRepository struct {
+	repository.PaymentRepository
+	mu      sync.Mutex
+	due     int64
+	entries []domain.Payment
+}
+
+func (r *fakePaymentRepository) GetByID(tenantID int, id int) (domain.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id < 1 || id > len(r.entries) {
+		return domain.Payment{}, errors.New("Payment not found")
+	}
+	return r.entries[id-1], nil
+}
+
+func (r *fakePaymentRepository) GetByInvoice(tenantID int, invoiceID int) ([]domain.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.Payment(nil), r.entries...), nil
+}
+
+func (r *fakePaymentRepository) Create(tenantID int, p domain.Payment) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p.Kind == domain.PaymentKindPayment {
+		left := r.due
+		for _, entry := range r.entries {
+			if entry.Kind == domain.PaymentKindPayment && entry.Status != domain.PaymentVoided {
+				left -= entry.Amount
+			}
+		}
+		if p.Amount > left {
+			return 0, errors.New("The payment exceeds the amount due")
+		}
+	}
+	if p.Kind == domain.PaymentKindRefund {
+		left := r.entries[p.RefundOf-1].Amount
+		for _, entry := range r.entries {
+			if entry.RefundOf == p.RefundOf && entry.Status != domain.PaymentVoided {
+				left -= entry.Amount
+			}
+		}
+		if p.Amount > left {
+			return 0, errors.New("The refund exceeds what is left of the payment")
+		}
+	}
+	p.Id = len(r.entries) + 1
+	r.entries = append(r.entries, p)
+	return p.Id, nil
+}
+
+func (r *fakePaymentRepository) Complete(tenantID int, id int, provider string, reference string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[id-1].Status = domain.PaymentCompleted
+	r.entries[id-1].Provider = provider
+	r.entries[id-1].Reference = reference
+	return nil
+}
+
+func (r *fakePaymentRepository) Void(tenantID int, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[id-1].Status = domain.PaymentVoided
+	return nil
+}
+
+// countingProvider counts the charges and refunds asked to the fake
+// provider. When entered is set, the first call signals it and waits for
+// release before reaching the provider, to hold it in flight while another
+// one is made.
+type countingProvider struct {
+	*payment.Fake
+	mu      sync.Mutex
+	charges int
+	refunds int
+	fail    error
+	entered chan struct{}
+	release chan struct{}
+}
+
+// hold waits for release on the first call when entered is set.
+func (p *countingProvider) hold(calls *int) {
+	p.mu.Lock()
+	*calls++
+	first := p.charges+p.refunds == 1
+	p.mu.Unlock()
+	if first && p.entered != nil {
+		close(p.entered)
+		<-p.release
+	}
+}
+
+func (p *countingProvider) Charge(ctx context.Context, request payment.Request) (string, error) {
+	p.hold(&p.charges)
+	if p.fail != nil {
+		return "", p.fail
+	}
+	return p.Fake.Charge(ctx, request)
+}
+
+func (p *countingProvider) Refund(ctx context.Context, charge string, request payment.Request) (string, error) {
+	p.hold(&p.refunds)
+	if p.fail != nil {
+		return "", p.fail
+	}
+	return p.Fake.Refund(ctx, charge, request)
+}
+
+// newRefundFixture records a card payment of 10000 cents charged to the fake
+// provider.
+func newRefundFixture(t *testing.T) (PaymentService, *fakePaymentRepository, *countingProvider, domain.Payment) {
+	provider := &countingProvider{Fake: payment.NewFake()}
+	charge, err := provider.Fake.Charge(context.Background(), payment.Request{Amount: 10000, Token: "tok_visa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := domain.Payment{Id: 1, PatientId: 1, InvoiceId: 1, ClinicId: 1, Kind: domain.PaymentKindPayment, Method: domain.PaymentCard,
+		Amount: 10000, Provider: provider.Name(), Reference: charge, Status: domain.PaymentCompleted}
+	r := &fakePaymentRepository{entries: []domain.Payment{original}}
+	s := NewPaymentService(r, nil, nil, nil, nil, provider)
+	return s, r, provider, original
+}
+
+func TestRefundCanNotExceedThePayment(t *testing.T) {
+	s, _, provider, original := newRefundFixture(t)
+	ctx := context.Background()
+
+	if _, err := s.Refund(ctx, 1, original.Id, domain.Payment{Amount: 10001}); err == nil {
+		t.Fatal("refunded more than the payment")
+	}
+	refund, err := s.Refund(ctx, 1, original.Id, domain.Payment{Amount: 4000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refund.Status != domain.PaymentCompleted || refund.Reference == "" {
+		t.Fatalf("refund = %+v, want it completed with the provider reference", refund)
+	}
+	if _, err := s.Refund(ctx, 1, original.Id, domain.Payment{Amount: 6001}); err == nil {
+		t.Fatal("refunded more than what was left of the payment")
+	}
+	rest, err := s.Refund(ctx, 1, original.Id, domain.Payment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rest.Amount != 6000 {
+		t.Fatalf("refunded %d by default, want the 6000 left", rest.Amount)
+	}
+	if _, err := s.Refund(ctx, 1, original.Id, domain.Payment{}); err == nil {
+		t.Fatal("refunded a payment already refunded")
+	}
+	if provider.refunds != 2 || provider.Balance(original.Reference) != 0 {
+		t.Fatalf("provider got %d refunds and has %d left, want 2 and 0", provider.refunds, provider.Balance(original.Reference))
+	}
+}
+
+func TestConcurrentRefundsReachTheProviderOnce(t *testing.T) {
+	s, r, provider, original := newRefundFixture(t)
+	provider.entered = make(chan struct{})
+	provider.release = make(chan struct{})
+	ctx := context.Background()
+
+	type result struct {
+		refund domain.Payment
+		err    error
+	}
+	first := make(chan result)
+	go func() {
+		refund, err := s.Refund(ctx, 1, original.Id, domain.Payment{Amount: 10000})
+		first <- result{refund, err}
+	}()
+
+	// The first refund is with the provider; the second one must not get
+	// there.
+	<-provider.entered
+	if _, err := s.Refund(ctx, 1, original.Id, domain.Payment{Amount: 10000}); err == nil {
+		t.Error("a second refund of the whole payment was accepted while the first was in flight")
+	}
+	close(provider.release)
+
+	got := <-first
+	if got.err != nil {
+		t.Fatal(got.err)
+	}
+	if got.refund.Status != domain.PaymentCompleted {
+		t.Fatalf("refund = %+v, want it completed", got.refund)
+	}
+	if provider.refunds != 1 {
+		t.Fatalf("provider got %d refunds, want 1", provider.refunds)
+	}
+	if len(r.entries) != 2 {
+		t.Fatalf("ledger has %d entries, want the payment and one refund", len(r.entries))
+	}
+}
+
+func TestRefundRejectedByTheProviderIsVoided(t *testing.T) {
+	s, r, provider, original := newRefundFixture(t)
+	provider.fail = errors.New("card expired")
+	ctx := context.Background()
+
+	if _, err := s.Refund(ctx, 1, original.Id, domain.Payment{Amount: 10000}); err == nil {
+		t.Fatal("refund succeeded though the provider failed")
+	}
+	if status := r.entries[1].Status; status != domain.PaymentVoided {
+		t.Fatalf("rejected refund is %s, want voided", status)
+	}
+
+	provider.fail = nil
+	if _, err := s.Refund(ctx, 1, original.Id, domain.Payment{Amount: 10000}); err != nil {
+		t.Fatalf("refund after a voided one = %v, want the whole amount available again", err)
+	}
+}
+
+type fakeClinicRepository struct {
+	repository.ClinicRepository
+}
+
+func (r *fakeClinicRepository) GetByID(tenantID int, id int) (domain.Clinic, error) {
+	return domain.Clinic{Id: id}, nil
+}
+
+// newChargeFixture has an issued invoice of 10000 cents to pay.
+func newChargeFixture() (PaymentService, *fakePaymentRepository, *countingProvider) {
+	provider := &countingProvider{Fake: payment.NewFake()}
+	invoices := &fakeInvoiceRepository{created: domain.Invoice{Id: 1, Number: 7, PatientId: 1, Status: domain.InvoiceIssued, AmountDue: 10000}}
+	r := &fakePaymentRepository{due: 10000}
+	s := NewPaymentService(r, invoices, nil, nil, &fakeClinicRepository{}, provider)
+	return s, r, provider
+}
+
+func TestConcurrentCardPaymentsAreChargedOnce(t *testing.T) {
+	s, r, provider := newChargeFixture()
+	provider.entered = make(chan struct{})
+	provider.release = make(chan struct{})
+	ctx := context.Background()
+	card := domain.Payment{Method: domain.PaymentCard, Amount: 10000, Token: "tok_visa", ClinicId: 1}
+
+	type result struct {
+		payment domain.Payment
+		err     error
+	}
+	first := make(chan result)
+	go func() {
+		p, err := s.Record(ctx, 1, 1, card)
+		first <- result{p, err}
+	}()
+
+	// The first payment is being charged; the second one must not be.
+	<-provider.entered
+	if _, err := s.Record(ctx, 1, 1, card); err == nil {
+		t.Error("a second payment of the whole invoice was accepted while the first was being charged")
+	}
+	close(provider.release)
+
+	got := <-first
+	if got.err != nil {
+		t.Fatal(got.err)
+	}
+	if got.payment.Status != domain.PaymentCompleted || got.payment.Reference == "" || got.payment.Provider != provider.Name() {
+		t.Fatalf("payment = %+v, want it completed with the provider reference", got.payment)
+	}
+	if provider.charges != 1 || len(r.entries) != 1 {
+		t.Fatalf("provider got %d charges and the ledger has %d entries, want 1 and 1", provider.charges, len(r.entries))
+	}
+}
+
+func TestCardPaymentDeclinedByTheProviderIsVoided(t *testing.T) {
+	s, r, provider := newChargeFixture()
+	provider.fail = errors.New("card declined")
+	ctx := context.Background()
+	card := domain.Payment{Method: domain.PaymentCard, Amount: 10000, Token: "tok_visa", ClinicId: 1}
+
+	if _, err := s.Record(ctx, 1, 1, card); err == nil {
+		t.Fatal("payment succeeded though the provider declined the card")
+	}
+	if status := r.entries[0].Status; status != domain.PaymentVoided {
+		t.Fatalf("declined payment is %s, want voided", status)
+	}
+	if _, err := s.Refund(ctx, 1, r.entries[0].Id, domain.Payment{}); err == nil {
+		t.Fatal("refunded a voided payment")
+	}
+
+	provider.fail = nil
+	if _, err := s.Record(ctx, 1, 1, card); err != nil {
+		t.Fatalf("payment after a declined one = %v, want the whole amount due again", err)
+	}
+}
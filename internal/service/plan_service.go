@@ -0,0 +1,160 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"strings"
+	"time"
+)
+
+type PlanService interface {
+	Create(tenantID int, plan domain.TreatmentPlan) (domain.TreatmentPlan, error)
+	GetByID(tenantID int, id int) (domain.TreatmentPlan, error)
+	GetByPatient(tenantID int, patientID int) ([]domain.TreatmentPlan, error)
+	SetStatus(tenantID int, id int, status string) (domain.TreatmentPlan, error)
+	AddStep(tenantID int, planID int, step domain.PlanStep) (domain.TreatmentPlan, error)
+	UpdateStep(tenantID int, planID int, step domain.PlanStep) (domain.TreatmentPlan, error)
+}
+
+// -------------------------------------------
+type planService struct {
+	planRepo      repository.PlanRepository
+	patientRepo   repository.PatientRepository
+	treatmentRepo repository.TreatmentRepository
+}
+
+func NewPlanService(planRepo repository.PlanRepository, patientRepo repository.PatientRepository, treatmentRepo repository.TreatmentRepository) PlanService {
+	return &planService{planRepo, patientRepo, treatmentRepo}
+}
+
+//-------------------------------------------
+
+func (s *planService) Create(tenantID int, plan domain.TreatmentPlan) (domain.TreatmentPlan, error) {
+	if _, err := s.patientRepo.GetByID(tenantID, plan.PatientId); err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	plan.Title = strings.TrimSpace(plan.Title)
+	plan.Notes = strings.TrimSpace(plan.Notes)
+	if plan.Title == "" {
+		return domain.TreatmentPlan{}, errors.New("Title is required")
+	}
+	if len(plan.Steps) == 0 {
+		return domain.TreatmentPlan{}, errors.New("A plan needs at least one step")
+	}
+	for i := range plan.Steps {
+		if err := s.validateStep(tenantID, &plan.Steps[i]); err != nil {
+			return domain.TreatmentPlan{}, err
+		}
+		plan.Steps[i].Position = i + 1
+		plan.Steps[i].Skipped = false
+	}
+	plan.Status = domain.PlanActive
+	plan.CreatedAt = time.Now().UTC().Truncate(time.Second)
+
+	id, err := s.planRepo.Create(tenantID, plan)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	return s.GetByID(tenantID, id)
+}
+
+// GetByID returns the plan with the status of its steps and its progress.
+func (s *planService) GetByID(tenantID int, id int) (domain.TreatmentPlan, error) {
+	plan, err := s.planRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	plan.Track()
+	return plan, nil
+}
+
+func (s *planService) GetByPatient(tenantID int, patientID int) ([]domain.TreatmentPlan, error) {
+	if _, err := s.patientRepo.GetByID(tenantID, patientID); err != nil {
+		return nil, err
+	}
+	plans, err := s.planRepo.GetByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range plans {
+		plans[i].Track()
+	}
+	return plans, nil
+}
+
+// SetStatus cancels or reactivates a plan. Plans are completed by completing
+// their steps.
+func (s *planService) SetStatus(tenantID int, id int, status string) (domain.TreatmentPlan, error) {
+	if status != domain.PlanActive && status != domain.PlanCancelled {
+		return domain.TreatmentPlan{}, errors.New("Invalid status, expected active or cancelled")
+	}
+	if _, err := s.GetByID(tenantID, id); err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	if err := s.planRepo.UpdateStatus(tenantID, id, status); err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	return s.GetByID(tenantID, id)
+}
+
+// AddStep appends a step to a plan that is not cancelled.
+func (s *planService) AddStep(tenantID int, planID int, step domain.PlanStep) (domain.TreatmentPlan, error) {
+	plan, err := s.GetByID(tenantID, planID)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	if plan.Status == domain.PlanCancelled {
+		return domain.TreatmentPlan{}, errors.New("The treatment plan is cancelled")
+	}
+	if err := s.validateStep(tenantID, &step); err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	step.PlanId = planID
+	step.Skipped = false
+	if _, err := s.planRepo.CreateStep(tenantID, step); err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	return s.GetByID(tenantID, planID)
+}
+
+// UpdateStep replaces the treatment, description, cost and skipped flag of a
+// step. Completed steps can not be skipped.
+func (s *planService) UpdateStep(tenantID int, planID int, step domain.PlanStep) (domain.TreatmentPlan, error) {
+	plan, err := s.GetByID(tenantID, planID)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	existing, ok := plan.Step(step.Id)
+	if !ok {
+		return domain.TreatmentPlan{}, errors.New("Plan step not found")
+	}
+	if step.Skipped && existing.Status == domain.StepCompleted {
+		return domain.TreatmentPlan{}, errors.New("Completed steps can not be skipped")
+	}
+	if err := s.validateStep(tenantID, &step); err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	if err := s.planRepo.UpdateStep(tenantID, step); err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	return s.GetByID(tenantID, planID)
+}
+
+func (s *planService) validateStep(tenantID int, step *domain.PlanStep) error {
+	step.Description = strings.TrimSpace(step.Description)
+	if step.Description == "" {
+		return errors.New("Description is required for every step")
+	}
+	if step.EstimatedCost < 0 {
+		return errors.New("EstimatedCost can not be negative")
+	}
+	if step.Treatment.Id != 0 {
+		treatment, err := s.treatmentRepo.GetByID(tenantID, step.Treatment.Id)
+		if err != nil {
+			return err
+		}
+		step.Treatment = treatment
+	}
+	return nil
+}
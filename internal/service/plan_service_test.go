@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"testing"
+	"time"
+)
+
+type fakePlanRepository struct {
+	repository.PlanRepository
+	plan    domain.TreatmentPlan
+	updated []domain.PlanStep
+}
+
+func (r *fakePlanRepository) GetByID(tenantID int, id int) (domain.TreatmentPlan, error) {
+	if id != r.plan.Id {
+		return domain.TreatmentPlan{}, errors.New("Treatment plan not found")
+	}
+	plan := r.plan
+	plan.Steps = append([]domain.PlanStep(nil), r.plan.Steps...)
+	return plan, nil
+}
+
+func (r *fakePlanRepository) UpdateStep(tenantID int, step domain.PlanStep) error {
+	r.updated = append(r.updated, step)
+	return nil
+}
+
+func TestTrackDerivesStepStatusAndProgress(t *testing.T) {
+	completedAt := time.Date(2025, 10, 1, 15, 0, 0, 0, time.UTC)
+	plan := domain.TreatmentPlan{
+		Status: domain.PlanActive,
+		Steps: []domain.PlanStep{
+			{Id: 1, EstimatedCost: 10000, Appointments: []domain.StepAppointment{{Id: 1}, {Id: 2, CompletedAt: &completedAt}}},
+			{Id: 2, EstimatedCost: 20000, Appointments: []domain.StepAppointment{{Id: 3}}},
+			{Id: 3, EstimatedCost: 30000},
+			{Id: 4, EstimatedCost: 40000, Skipped: true, Appointments: []domain.StepAppointment{{Id: 4}}},
+			{Id: 5, EstimatedCost: 50000, Skipped: true, Appointments: []domain.StepAppointment{{Id: 5, CompletedAt: &completedAt}}},
+		},
+	}
+	plan.Track()
+
+	want := []string{domain.StepCompleted, domain.StepScheduled, domain.StepPending, domain.StepSkipped, domain.StepCompleted}
+	for i, step := range plan.Steps {
+		if step.Status != want[i] {
+			t.Errorf("step %d is %s, want %s", step.Id, step.Status, want[i])
+		}
+	}
+	// Skipped steps are left out unless they were completed anyway.
+	if plan.EstimatedTotal != 110000 || plan.CompletedSteps != 2 || plan.Progress != 50 {
+		t.Errorf("plan totals %d with %d steps completed and %d%% progress, want 110000, 2 and 50%%", plan.EstimatedTotal, plan.CompletedSteps, plan.Progress)
+	}
+	if plan.Status != domain.PlanActive {
+		t.Errorf("plan is %s, want active", plan.Status)
+	}
+
+	plan.Steps[1].Appointments[0].CompletedAt = &completedAt
+	plan.Steps[2].Skipped = true
+	plan.Track()
+	if plan.Progress != 100 || plan.Status != domain.PlanCompleted {
+		t.Errorf("plan is %s at %d%%, want completed at 100%%", plan.Status, plan.Progress)
+	}
+
+	cancelled := domain.TreatmentPlan{Status: domain.PlanCancelled, Steps: []domain.PlanStep{{Appointments: []domain.StepAppointment{{CompletedAt: &completedAt}}}}}
+	cancelled.Track()
+	if cancelled.Status != domain.PlanCancelled {
+		t.Errorf("cancelled plan became %s", cancelled.Status)
+	}
+	empty := domain.TreatmentPlan{Status: domain.PlanActive, Steps: []domain.PlanStep{{Skipped: true}}}
+	empty.Track()
+	if empty.Status != domain.PlanActive || empty.Progress != 0 {
+		t.Errorf("plan with every step skipped is %s at %d%%, want active at 0%%", empty.Status, empty.Progress)
+	}
+}
+
+func TestUpdateStepDoesNotSkipCompletedSteps(t *testing.T) {
+	completedAt := time.Date(2025, 10, 1, 15, 0, 0, 0, time.UTC)
+	plans := &fakePlanRepository{plan: domain.TreatmentPlan{
+		Id:     1,
+		Status: domain.PlanActive,
+		Steps: []domain.PlanStep{
+			{Id: 1, Description: "Limpieza", Appointments: []domain.StepAppointment{{Id: 1, CompletedAt: &completedAt}}},
+			{Id: 2, Description: "Extracción 38"},
+		},
+	}}
+	s := NewPlanService(plans, &invoicePatientRepository{}, &fakeTreatmentRepository{})
+
+	if _, err := s.UpdateStep(1, 1, domain.PlanStep{Id: 1, Description: "Limpieza", Skipped: true}); err == nil {
+		t.Error("completed step was skipped")
+	}
+	if _, err := s.UpdateStep(1, 1, domain.PlanStep{Id: 3, Description: "Corona 36"}); err == nil {
+		t.Error("step of another plan was updated")
+	}
+	if _, err := s.UpdateStep(1, 1, domain.PlanStep{Id: 2, Description: " Extracción 38 ", Skipped: true, Treatment: domain.Treatment{Id: 4}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(plans.updated) != 1 {
+		t.Fatalf("updated %d steps, want 1", len(plans.updated))
+	}
+	if step := plans.updated[0]; !step.Skipped || step.Description != "Extracción 38" || step.Treatment.Name != "Limpieza" {
+		t.Errorf("updated %+v, want step 2 skipped with treatment 4 loaded", step)
+	}
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/pdf"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxPrescriptionItems = 10
+
+// codeAlphabet leaves out the letters and digits easily mistaken for one
+// another when a code is typed from paper: 0, 1, I, L, O and U.
+const codeAlphabet = "23456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+type PrescriptionService interface {
+	Issue(tenantID int, appointmentID int, prescription domain.Prescription) (domain.Prescription, error)
+	GetByID(tenantID int, id int) (domain.Prescription, error)
+	GetByPatient(tenantID int, patientID int) ([]domain.Prescription, error)
+	GetByAppointment(tenantID int, appointmentID int) ([]domain.Prescription, error)
+	Void(tenantID int, id int, reason string) (domain.Prescription, error)
+	Check(code string) (domain.PrescriptionCheck, error)
+	Document(tenantID int, id int, checkURL string) (*pdf.Document, error)
+}
+
+// -------------------------------------------
+type prescriptionService struct {
+	prescriptionRepo repository.PrescriptionRepository
+	appointmentRepo  repository.AppointmentRepository
+	patientRepo      repository.PatientRepository
+	tenantRepo       repository.TenantRepository
+}
+
+func NewPrescriptionService(prescriptionRepo repository.PrescriptionRepository, appointmentRepo repository.AppointmentRepository, patientRepo repository.PatientRepository, tenantRepo repository.TenantRepository) PrescriptionService {
+	return &prescriptionService{prescriptionRepo, appointmentRepo, patientRepo, tenantRepo}
+}
+
+//-------------------------------------------
+
+// Issue records a prescription written at an appointment the patient
+// attended, signed by the dentist of the appointment with their current
+// license, and gives it a new verification code.
+func (s *prescriptionService) Issue(tenantID int, appointmentID int, prescription domain.Prescription) (domain.Prescription, error) {
+	appointment, err := s.appointmentRepo.GetByID(tenantID, appointmentID)
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	if appointment.CheckedInAt == nil && appointment.CompletedAt == nil {
+		return domain.Prescription{}, errors.New("Prescriptions can only be issued once the patient checked in")
+	}
+	if strings.TrimSpace(appointment.Dentist.License) == "" {
+		return domain.Prescription{}, errors.New("The dentist of the appointment has no license to prescribe")
+	}
+	if err := validatePrescription(&prescription); err != nil {
+		return domain.Prescription{}, err
+	}
+	code, err := newPrescriptionCode()
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+
+	prescription.AppointmentId = appointment.Id
+	prescription.PatientId = appointment.Patient.Id
+	prescription.DentistId = appointment.Dentist.Id
+	prescription.DentistName = strings.TrimSpace(appointment.Dentist.FirstName + " " + appointment.Dentist.LastName)
+	prescription.License = strings.TrimSpace(appointment.Dentist.License)
+	prescription.Status = domain.PrescriptionIssued
+	prescription.Code = code
+	prescription.IssuedAt = time.Now().UTC().Truncate(time.Second)
+	prescription.VoidReason = ""
+	prescription.VoidedAt = nil
+	id, err := s.prescriptionRepo.Create(tenantID, prescription)
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	return s.prescriptionRepo.GetByID(tenantID, id)
+}
+
+func validatePrescription(prescription *domain.Prescription) error {
+	prescription.Diagnosis = strings.TrimSpace(prescription.Diagnosis)
+	prescription.Notes = strings.TrimSpace(prescription.Notes)
+	if len(prescription.Diagnosis) > 255 {
+		return errors.New("Diagnosis can not be longer than 255 characters")
+	}
+	if len(prescription.Notes) > 500 {
+		return errors.New("Notes can not be longer than 500 characters")
+	}
+	if len(prescription.Items) == 0 {
+		return errors.New("A prescription needs at least one medication")
+	}
+	if len(prescription.Items) > maxPrescriptionItems {
+		return errors.New("A prescription can not have more than " + strconv.Itoa(maxPrescriptionItems) + " medications")
+	}
+	for i := range prescription.Items {
+		item := &prescription.Items[i]
+		item.Id = 0
+		item.Medication = strings.TrimSpace(item.Medication)
+		item.Dose = strings.TrimSpace(item.Dose)
+		item.Instructions = strings.TrimSpace(item.Instructions)
+		if item.Medication == "" || item.Dose == "" {
+			return errors.New("Medication and Dose are required")
+		}
+		if len(item.Medication) > 255 || len(item.Dose) > 255 || len(item.Instructions) > 500 {
+			return errors.New("Medication, Dose or Instructions of " + item.Medication + " is too long")
+		}
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+		if item.Quantity < 0 || item.Quantity > 99 {
+			return errors.New("Quantity of " + item.Medication + " must be between 1 and 99")
+		}
+	}
+	return nil
+}
+
+// newPrescriptionCode generates a random code of 12 characters of
+// codeAlphabet, about 59 bits, in groups of four.
+func newPrescriptionCode() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	code := make([]byte, len(buf))
+	for i, b := range buf {
+		// 256 is not a multiple of the alphabet, the bias is negligible for
+		// a code that is not a secret.
+		code[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return domain.NormalizeCode(string(code)), nil
+}
+
+func (s *prescriptionService) GetByID(tenantID int, id int) (domain.Prescription, error) {
+	prescription, err := s.prescriptionRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	return prescription, nil
+}
+
+func (s *prescriptionService) GetByPatient(tenantID int, patientID int) ([]domain.Prescription, error) {
+	if _, err := s.patientRepo.GetByID(tenantID, patientID); err != nil {
+		return nil, err
+	}
+	prescriptions, err := s.prescriptionRepo.GetByPatient(tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return prescriptions, nil
+}
+
+func (s *prescriptionService) GetByAppointment(tenantID int, appointmentID int) ([]domain.Prescription, error) {
+	if _, err := s.appointmentRepo.GetByID(tenantID, appointmentID); err != nil {
+		return nil, err
+	}
+	prescriptions, err := s.prescriptionRepo.GetByAppointment(tenantID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	return prescriptions, nil
+}
+
+// Void cancels a prescription issued by mistake, so checking its code tells
+// it must not be dispensed.
+func (s *prescriptionService) Void(tenantID int, id int, reason string) (domain.Prescription, error) {
+	if _, err := s.prescriptionRepo.GetByID(tenantID, id); err != nil {
+		return domain.Prescription{}, err
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return domain.Prescription{}, errors.New("A reason is required to void a prescription")
+	}
+	if len(reason) > 255 {
+		return domain.Prescription{}, errors.New("Reason can not be longer than 255 characters")
+	}
+	if err := s.prescriptionRepo.Void(tenantID, id, time.Now().UTC().Truncate(time.Second), reason); err != nil {
+		return domain.Prescription{}, err
+	}
+	return s.prescriptionRepo.GetByID(tenantID, id)
+}
+
+// Check looks up the prescription printed with a code, whatever the case,
+// spaces and dashes it was typed with.
+func (s *prescriptionService) Check(code string) (domain.PrescriptionCheck, error) {
+	code = domain.NormalizeCode(code)
+	if len(code) != 14 {
+		return domain.PrescriptionCheck{}, errors.New("Prescription not found")
+	}
+	prescription, err := s.prescriptionRepo.GetByCode(code)
+	if err != nil {
+		return domain.PrescriptionCheck{}, err
+	}
+	return prescription.Check(), nil
+}
+
+// Document prints the prescription to hand to the patient, with its code and
+// checkURL, where the pharmacy can check it.
+func (s *prescriptionService) Document(tenantID int, id int, checkURL string) (*pdf.Document, error) {
+	prescription, err := s.prescriptionRepo.GetByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	appointment, err := s.appointmentRepo.GetByID(tenantID, prescription.AppointmentId)
+	if err != nil {
+		return nil, err
+	}
+	header := appointment.Clinic.Name
+	if header == "" {
+		tenant, err := s.tenantRepo.GetByID(tenantID)
+		if err != nil {
+			return nil, err
+		}
+		header = tenant.Name
+	}
+	return renderPrescription(header, appointment, prescription, checkURL), nil
+}
+
+// renderPrescription lays out the prescription on an A5 page, continued on
+// more pages when the medications do not fit. A voided prescription is
+// printed with the reason across its header.
+func renderPrescription(header string, appointment domain.Appointment, prescription domain.Prescription, checkURL string) *pdf.Document {
+	loc := appointmentLocation(appointment.TimeZone)
+	doc := pdf.New(pdf.A5)
+	doc.Title = "Prescription " + prescription.Code
+	doc.Author = prescription.DentistName
+	width := doc.Size().Width - 2*printMargin
+	bottom := doc.Size().Height - printMargin - 24
+
+	y := printMargin + 16
+	doc.Text(printMargin, y, 16, true, pdf.Truncate(header, 16, true, width))
+	if appointment.Clinic.Address != "" {
+		y += 14
+		doc.Text(printMargin, y, 9, false, pdf.Truncate(appointment.Clinic.Address, 9, false, width))
+	}
+	y += 10
+	doc.Line(printMargin, y, printMargin+width, y, 0.5)
+
+	y += 28
+	doc.Text(printMargin, y, 14, true, "Prescription")
+	doc.TextRight(printMargin+width, y, 10, false, prescription.IssuedAt.In(loc).Format(domain.DateLayout))
+	if prescription.Status == domain.PrescriptionVoided {
+		y += 8
+		doc.Rect(printMargin, y, width, 20, 0.85)
+		doc.Text(printMargin+6, y+14, 10, true, pdf.Truncate("VOIDED: "+prescription.VoidReason, 10, true, width-12))
+		y += 20
+	}
+	y += 6
+	details := []struct{ label, value string }{
+		{"Patient", appointment.Patient.FirstName + " " + appointment.Patient.LastName},
+		{"DNI", appointment.Patient.DNI},
+		{"Diagnosis", prescription.Diagnosis},
+	}
+	for _, detail := range details {
+		if strings.TrimSpace(detail.value) == "" {
+			continue
+		}
+		y += rowHeight
+		doc.Text(printMargin, y, 10, true, detail.label)
+		doc.Text(printMargin+80, y, 10, false, pdf.Truncate(detail.value, 10, false, width-80))
+	}
+	y += 10
+	doc.Line(printMargin, y, printMargin+width, y, 0.25)
+
+	y += 6
+	for i, item := range prescription.Items {
+		lines := pdf.Wrap(item.Dose, 10, false, width-16)
+		if item.Instructions != "" {
+			lines = append(lines, pdf.Wrap(item.Instructions, 10, false, width-16)...)
+		}
+		// A medication is not split across pages.
+		if y+18+13*float64(len(lines)) > bottom {
+			doc.AddPage()
+			y = printMargin
+		}
+		y += 18
+		medication := strconv.Itoa(i+1) + ". " + item.Medication
+		doc.Text(printMargin, y, 11, true, pdf.Truncate(medication, 11, true, width-50))
+		doc.TextRight(printMargin+width, y, 10, false, "x "+strconv.Itoa(item.Quantity))
+		for _, line := range lines {
+			y += 13
+			doc.Text(printMargin+16, y, 10, false, line)
+		}
+	}
+	if prescription.Notes != "" {
+		lines := pdf.Wrap(prescription.Notes, 9, false, width)
+		if y+14+12*float64(len(lines)) > bottom {
+			doc.AddPage()
+			y = printMargin
+		}
+		y += 14
+		for _, line := range lines {
+			y += 12
+			doc.Text(printMargin, y, 9, false, line)
+		}
+	}
+
+	// The dentist signs over the line, next to their name and license.
+	if y+96 > bottom {
+		doc.AddPage()
+		y = printMargin
+	}
+	y = bottom - 60
+	signature := printMargin + width - 180
+	doc.Line(signature, y, printMargin+width, y, 0.5)
+	doc.Text(signature, y+13, 10, true, pdf.Truncate(prescription.DentistName, 10, true, 180))
+	doc.Text(signature, y+26, 9, false, pdf.Truncate("License "+prescription.License, 9, false, 180))
+	doc.Text(printMargin, y+13, 9, true, "Code "+prescription.Code)
+	if checkURL != "" {
+		doc.Text(printMargin, y+26, 8, false, "Check it at")
+		doc.Text(printMargin, y+36, 8, false, pdf.Truncate(checkURL, 8, false, signature-printMargin-8))
+	}
+
+	pages := doc.PageCount()
+	for n := 1; n <= pages; n++ {
+		doc.SetPage(n)
+		footer := doc.Size().Height - printMargin
+		doc.Line(printMargin, footer-12, printMargin+width, footer-12, 0.5)
+		doc.Text(printMargin, footer, 8, false, "Prescription #"+strconv.Itoa(prescription.Id)+" - Appointment #"+strconv.Itoa(appointment.Id))
+		doc.TextRight(printMargin+width, footer, 8, false, "Page "+strconv.Itoa(n)+" of "+strconv.Itoa(pages))
+	}
+	return doc
+}
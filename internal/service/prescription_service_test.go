@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakePrescriptionRepository voids, like the store, only issued
+// prescriptions.
+type fakePrescriptionRepository struct {
+	repository.PrescriptionRepository
+	prescriptions []domain.Prescription
+	lookups       int
+}
+
+func (r *fakePrescriptionRepository) Create(tenantID int, prescription domain.Prescription) (int, error) {
+	prescription.Id = len(r.prescriptions) + 1
+	prescription.PatientName = "Juan Carlos Pérez"
+	r.prescriptions = append(r.prescriptions, prescription)
+	return prescription.Id, nil
+}
+
+func (r *fakePrescriptionRepository) GetByID(tenantID int, id int) (domain.Prescription, error) {
+	if id < 1 || id > len(r.prescriptions) {
+		return domain.Prescription{}, errors.New("Prescription not found")
+	}
+	return r.prescriptions[id-1], nil
+}
+
+func (r *fakePrescriptionRepository) GetByCode(code string) (domain.Prescription, error) {
+	r.lookups++
+	for _, prescription := range r.prescriptions {
+		if prescription.Code == code {
+			return prescription, nil
+		}
+	}
+	return domain.Prescription{}, errors.New("Prescription not found")
+}
+
+func (r *fakePrescriptionRepository) Void(tenantID int, id int, at time.Time, reason string) error {
+	prescription := &r.prescriptions[id-1]
+	if prescription.Status != domain.PrescriptionIssued {
+		return errors.New("Prescription already voided")
+	}
+	prescription.Status = domain.PrescriptionVoided
+	prescription.VoidedAt = &at
+	prescription.VoidReason = reason
+	return nil
+}
+
+var prescriptionCode = regexp.MustCompile(`^[` + codeAlphabet + `]{4}-[` + codeAlphabet + `]{4}-[` + codeAlphabet + `]{4}$`)
+
+func newPrescriptionFixture() (PrescriptionService, *fakePrescriptionRepository) {
+	checkedIn := time.Now().Add(-time.Hour)
+	dentist := domain.Dentist{Id: 2, FirstName: "Daniel", LastName: "Rodríguez", License: " MN 12345 "}
+	appointments := &fakeAppointmentRepository{appointments: []domain.Appointment{
+		{Id: 7, CheckedInAt: &checkedIn, Patient: domain.Patient{Id: 3}, Dentist: dentist},
+		{Id: 8, Patient: domain.Patient{Id: 3}, Dentist: dentist},
+		{Id: 9, CheckedInAt: &checkedIn, Patient: domain.Patient{Id: 3}, Dentist: domain.Dentist{Id: 4}},
+	}}
+	prescriptions := &fakePrescriptionRepository{}
+	return NewPrescriptionService(prescriptions, appointments, &invoicePatientRepository{}, &fakeTenantRepository{}), prescriptions
+}
+
+func amoxicillin() domain.Prescription {
+	return domain.Prescription{Diagnosis: " Absceso ", Items: []domain.PrescriptionItem{{Medication: " Amoxicilina 500 mg ", Dose: "1 cada 8 horas"}}}
+}
+
+func TestIssuePrescriptionSignedByTheDentistOfTheVisit(t *testing.T) {
+	s, _ := newPrescriptionFixture()
+
+	prescription, err := s.Issue(1, 7, amoxicillin())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prescription.PatientId != 3 || prescription.DentistId != 2 || prescription.DentistName != "Daniel Rodríguez" || prescription.License != "MN 12345" {
+		t.Errorf("issued %+v, want it signed by Daniel Rodríguez, MN 12345, for patient 3", prescription)
+	}
+	if prescription.Status != domain.PrescriptionIssued || !prescriptionCode.MatchString(prescription.Code) {
+		t.Errorf("issued a %s prescription with code %q, want an issued one with a code like XXXX-XXXX-XXXX", prescription.Status, prescription.Code)
+	}
+	if item := prescription.Items[0]; item.Medication != "Amoxicilina 500 mg" || item.Quantity != 1 || prescription.Diagnosis != "Absceso" {
+		t.Errorf("issued %+v, want the fields trimmed and a quantity of 1", prescription)
+	}
+
+	other, err := s.Issue(1, 7, amoxicillin())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Code == prescription.Code {
+		t.Errorf("both prescriptions have code %s", other.Code)
+	}
+
+	if _, err := s.Issue(1, 8, amoxicillin()); err == nil {
+		t.Error("prescription was issued before the patient checked in")
+	}
+	if _, err := s.Issue(1, 9, amoxicillin()); err == nil {
+		t.Error("prescription was issued by a dentist without license")
+	}
+	invalid := []domain.PrescriptionItem{
+		{Medication: "Amoxicilina 500 mg"},
+		{Medication: "Amoxicilina 500 mg", Dose: "1 cada 8 horas", Quantity: 100},
+		{Medication: strings.Repeat("a", 256), Dose: "1 cada 8 horas"},
+	}
+	for _, item := range invalid {
+		if _, err := s.Issue(1, 7, domain.Prescription{Items: []domain.PrescriptionItem{item}}); err == nil {
+			t.Errorf("prescription of %+v was issued", item)
+		}
+	}
+	if _, err := s.Issue(1, 7, domain.Prescription{}); err == nil {
+		t.Error("prescription without medications was issued")
+	}
+	if _, err := s.Issue(1, 7, domain.Prescription{Items: make([]domain.PrescriptionItem, maxPrescriptionItems+1)}); err == nil {
+		t.Error("prescription with too many medications was issued")
+	}
+}
+
+func TestVoidAndCheckPrescription(t *testing.T) {
+	s, prescriptions := newPrescriptionFixture()
+	prescription, err := s.Issue(1, 7, amoxicillin())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Codes are found whatever the case, spaces and dashes they are typed with.
+	typed := strings.ToLower(strings.ReplaceAll(prescription.Code, "-", " "))
+	check, err := s.Check(" " + typed + "- ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !check.Valid || check.Code != prescription.Code || check.Patient != "J. C. P." {
+		t.Errorf("check = %+v, want a valid prescription for J. C. P.", check)
+	}
+
+	if _, err := s.Void(1, prescription.Id, "  "); err == nil {
+		t.Error("prescription was voided without a reason")
+	}
+	voided, err := s.Void(1, prescription.Id, " Dosis equivocada ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if voided.Status != domain.PrescriptionVoided || voided.VoidReason != "Dosis equivocada" || voided.VoidedAt == nil {
+		t.Errorf("voided %+v, want it voided for Dosis equivocada", voided)
+	}
+	if _, err := s.Void(1, prescription.Id, "Dosis equivocada"); err == nil {
+		t.Error("prescription was voided twice")
+	}
+	if check, err := s.Check(prescription.Code); err != nil || check.Valid || check.Status != domain.PrescriptionVoided {
+		t.Errorf("check of a voided prescription = %+v, %v, want it not valid", check, err)
+	}
+
+	lookups := prescriptions.lookups
+	for _, code := range []string{"", "ABCD-EFGH", strings.Repeat("A", 13)} {
+		if _, err := s.Check(code); err == nil {
+			t.Errorf("Check(%q) found a prescription", code)
+		}
+	}
+	if prescriptions.lookups != lookups {
+		t.Errorf("codes of the wrong length were looked up %d times", prescriptions.lookups-lookups)
+	}
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/pdf"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	printMargin = 36.0
+	rowHeight   = 16.0
+)
+
+// scheduleColumns are the titles and widths of the columns of a printed
+// schedule; the last one takes the rest of the line.
+var scheduleColumns = []struct {
+	title string
+	width float64
+}{
+	{"Hour", 45}, {"Patient", 125}, {"DNI", 65}, {"Treatment", 95}, {"Clinic", 85}, {"Notes", 0},
+}
+
+type PrintService interface {
+	AppointmentSlip(tenantID int, id int, timeZone string) (*pdf.Document, error)
+	DentistSchedule(tenantID int, dentistID int, date string, period string, timeZone string) (*pdf.Document, error)
+}
+
+// -------------------------------------------
+type printService struct {
+	tenantRepo      repository.TenantRepository
+	appointmentRepo repository.AppointmentRepository
+	dentistRepo     repository.DentistRepository
+}
+
+func NewPrintService(tenantRepo repository.TenantRepository, appointmentRepo repository.AppointmentRepository, dentistRepo repository.DentistRepository) PrintService {
+	return &printService{tenantRepo, appointmentRepo, dentistRepo}
+}
+
+//-------------------------------------------
+
+// AppointmentSlip prints the confirmation handed to the patient. The
+// appointment is shown in timeZone, or in the clinic time zone when empty.
+func (s *printService) AppointmentSlip(tenantID int, id int, timeZone string) (*pdf.Document, error) {
+	appointment, err := s.appointmentRepo.GetByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if timeZone != "" {
+		loc, err := domain.LoadLocation(timeZone)
+		if err != nil {
+			return nil, errors.New("Invalid time zone: " + timeZone)
+		}
+		appointment.Localize(loc)
+	}
+	header := appointment.Clinic.Name
+	if header == "" {
+		tenant, err := s.tenantRepo.GetByID(tenantID)
+		if err != nil {
+			return nil, err
+		}
+		header = tenant.Name
+	}
+
+	doc := pdf.New(pdf.A5)
+	doc.Title = "Appointment " + strconv.Itoa(appointment.Id)
+	doc.Author = header
+	width := doc.Size().Width - 2*printMargin
+	y := printMargin + 16
+	doc.Text(printMargin, y, 16, true, pdf.Truncate(header, 16, true, width))
+	if appointment.Clinic.Address != "" {
+		y += 14
+		doc.Text(printMargin, y, 9, false, pdf.Truncate(appointment.Clinic.Address, 9, false, width))
+	}
+	y += 10
+	doc.Line(printMargin, y, printMargin+width, y, 0.5)
+
+	y += 28
+	doc.Text(printMargin, y, 14, true, "Appointment confirmation")
+	y += 14
+	doc.Rect(printMargin, y, width, 50, 0.92)
+	start := appointment.StartsAt.In(appointmentLocation(appointment.TimeZone))
+	doc.Text(printMargin+12, y+22, 13, true, start.Weekday().String()+" "+appointment.Date+", "+appointment.Hour)
+	doc.Text(printMargin+12, y+38, 9, false, "Time zone: "+appointment.TimeZone)
+	y += 50
+
+	var resources []string
+	for _, resource := range appointment.Resources {
+		resources = append(resources, resource.Name)
+	}
+	details := []struct{ label, value string }{
+		{"Patient", appointment.Patient.FirstName + " " + appointment.Patient.LastName},
+		{"DNI", appointment.Patient.DNI},
+		{"Dentist", appointment.Dentist.FirstName + " " + appointment.Dentist.LastName},
+		{"License", appointment.Dentist.License},
+		{"Treatment", appointment.Treatment.Name},
+		{"Reserved", strings.Join(resources, ", ")},
+		{"Notes", appointment.Description},
+	}
+	// Long notes are cut where the footer starts.
+	bottom := doc.Size().Height - printMargin
+	y += 12
+	for _, detail := range details {
+		if strings.TrimSpace(detail.value) == "" || y+rowHeight > bottom-56 {
+			continue
+		}
+		y += rowHeight
+		doc.Text(printMargin, y, 10, true, detail.label)
+		for i, line := range pdf.Wrap(detail.value, 10, false, width-80) {
+			if i > 0 {
+				y += 13
+			}
+			if y > bottom-56 {
+				break
+			}
+			doc.Text(printMargin+80, y, 10, false, line)
+		}
+	}
+
+	doc.Line(printMargin, bottom-44, printMargin+width, bottom-44, 0.5)
+	doc.Text(printMargin, bottom-32, 8, false, "Please arrive 10 minutes early.")
+	doc.Text(printMargin, bottom-22, 8, false, "To cancel or reschedule, contact the clinic at least 24 hours before.")
+	doc.Text(printMargin, bottom-8, 8, false, "Appointment #"+strconv.Itoa(appointment.Id))
+	doc.TextRight(printMargin+width, bottom-8, 8, false, "Printed "+time.Now().In(appointmentLocation(appointment.TimeZone)).Format(domain.DateLayout+" "+domain.HourLayout))
+	return doc, nil
+}
+
+// DentistSchedule prints the agenda of a dentist for the day, or the week
+// starting on Monday, that includes date. The date and the appointments are
+// read in timeZone, DefaultTimeZone when empty.
+func (s *printService) DentistSchedule(tenantID int, dentistID int, date string, period string, timeZone string) (*pdf.Document, error) {
+	dentist, err := s.dentistRepo.GetByID(tenantID, dentistID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := domain.LoadLocation(timeZone)
+	if err != nil {
+		return nil, errors.New("Invalid time zone: " + timeZone)
+	}
+	from, err := time.ParseInLocation(domain.DateLayout, date, loc)
+	if err != nil {
+		return nil, errors.New("Invalid date, expected dd/MM/yyyy")
+	}
+	days := 1
+	switch period {
+	case domain.PeriodDay, "":
+	case domain.PeriodWeek:
+		from = from.AddDate(0, 0, -(int(from.Weekday())+6)%7)
+		days = 7
+	default:
+		return nil, errors.New("Invalid period, expected day or week")
+	}
+	to := from.AddDate(0, 0, days)
+
+	appointments, err := s.appointmentRepo.Search(tenantID, domain.AppointmentFilter{DentistId: dentistID, From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[string][]domain.Appointment)
+	for _, appointment := range appointments {
+		appointment.Localize(loc)
+		byDay[appointment.Date] = append(byDay[appointment.Date], appointment)
+	}
+
+	name := strings.TrimSpace(dentist.FirstName + " " + dentist.LastName)
+	title := "Schedule of " + name
+	subtitle := "License " + dentist.License + " - " + from.Format(domain.DateLayout)
+	if days > 1 {
+		subtitle += " to " + to.AddDate(0, 0, -1).Format(domain.DateLayout)
+	}
+	subtitle += " (" + loc.String() + ")"
+
+	doc := pdf.New(pdf.A4)
+	doc.Title = title
+	width := doc.Size().Width - 2*printMargin
+	bottom := doc.Size().Height - printMargin - 24
+	y := 0.0
+	newPage := func() {
+		doc.AddPage()
+		y = printMargin + 16
+		doc.Text(printMargin, y, 16, true, pdf.Truncate(title, 16, true, width))
+		y += 16
+		doc.Text(printMargin, y, 10, false, subtitle)
+		y += 10
+		doc.Line(printMargin, y, printMargin+width, y, 0.5)
+		y += 8
+	}
+	columnHeader := func() {
+		y += rowHeight
+		x := printMargin
+		for _, column := range scheduleColumns {
+			doc.Text(x, y, 9, true, column.title)
+			x += column.width
+		}
+		doc.Line(printMargin, y+4, printMargin+width, y+4, 0.25)
+	}
+
+	newPage()
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		dayAppointments := byDay[day.Format(domain.DateLayout)]
+		// A day starts on a new page when its band, the column titles and
+		// the first row do not fit.
+		if y+24+3*rowHeight > bottom {
+			newPage()
+		}
+		y += 10
+		doc.Rect(printMargin, y, width, 20, 0.9)
+		doc.Text(printMargin+6, y+14, 11, true, day.Weekday().String()+" "+day.Format(domain.DateLayout))
+		count := strconv.Itoa(len(dayAppointments)) + " appointments"
+		if len(dayAppointments) == 1 {
+			count = "1 appointment"
+		}
+		doc.TextRight(printMargin+width-6, y+14, 9, false, count)
+		y += 20
+		if len(dayAppointments) == 0 {
+			y += rowHeight
+			doc.Text(printMargin+6, y, 9, false, "No appointments")
+			continue
+		}
+		columnHeader()
+		for _, appointment := range dayAppointments {
+			if y+rowHeight > bottom {
+				newPage()
+				columnHeader()
+			}
+			y += rowHeight
+			values := []string{
+				appointment.Hour,
+				appointment.Patient.LastName + ", " + appointment.Patient.FirstName,
+				appointment.Patient.DNI,
+				appointment.Treatment.Name,
+				appointment.Clinic.Name,
+				appointment.Description,
+			}
+			x := printMargin
+			for i, column := range scheduleColumns {
+				columnWidth := column.width
+				if columnWidth == 0 {
+					columnWidth = printMargin + width - x
+				}
+				doc.Text(x, y, 9, false, pdf.Truncate(values[i], 9, false, columnWidth-6))
+				x += column.width
+			}
+		}
+	}
+
+	printed := "Printed " + time.Now().In(loc).Format(domain.DateLayout+" "+domain.HourLayout)
+	for page := 1; page <= doc.PageCount(); page++ {
+		doc.SetPage(page)
+		footer := doc.Size().Height - printMargin
+		doc.Text(printMargin, footer, 8, false, printed)
+		doc.TextRight(printMargin+width, footer, 8, false, "Page "+strconv.Itoa(page)+" of "+strconv.Itoa(doc.PageCount()))
+	}
+	return doc, nil
+}
+
+// appointmentLocation loads the time zone of an appointment, which the store
+// already loaded once, falling back to UTC.
+func appointmentLocation(name string) *time.Location {
+	loc, err := domain.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"proyecto_final_go/internal/domain"
+	"testing"
+	"time"
+)
+
+// filterAppointmentRepository records the filter of the last search.
+type filterAppointmentRepository struct {
+	fakeAppointmentRepository
+	filter domain.AppointmentFilter
+}
+
+func (r *filterAppointmentRepository) Search(tenantID int, filter domain.AppointmentFilter) ([]domain.Appointment, error) {
+	r.filter = filter
+	return r.fakeAppointmentRepository.Search(tenantID, filter)
+}
+
+func TestDentistScheduleSearchesTheLocalWeekFromMonday(t *testing.T) {
+	appointments := &filterAppointmentRepository{}
+	s := NewPrintService(&fakeTenantRepository{}, appointments, &fakeDentistRepository{})
+
+	// Summer time ends in Madrid on Sunday 26/10/2025, so the week is one
+	// hour longer than seven days.
+	doc, err := s.DentistSchedule(1, 4, "24/10/2025", domain.PeriodWeek, "Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2025, 10, 19, 22, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 10, 26, 23, 0, 0, 0, time.UTC)
+	if !appointments.filter.From.Equal(from) || !appointments.filter.To.Equal(to) {
+		t.Errorf("searched from %v to %v, want from %v to %v", appointments.filter.From.UTC(), appointments.filter.To.UTC(), from, to)
+	}
+	if appointments.filter.DentistId != 4 {
+		t.Errorf("searched dentist %d, want 4", appointments.filter.DentistId)
+	}
+	if doc.PageCount() != 1 {
+		t.Errorf("printed %d pages, want 1", doc.PageCount())
+	}
+}
+
+func TestDentistScheduleSplitsLongDaysAcrossPages(t *testing.T) {
+	appointments := &filterAppointmentRepository{}
+	start := time.Date(2025, 10, 24, 11, 0, 0, 0, time.UTC)
+	for i := 0; i < 80; i++ {
+		appointments.appointments = append(appointments.appointments, domain.Appointment{Id: i + 1, StartsAt: start.Add(time.Duration(i) * 5 * time.Minute)})
+	}
+	s := NewPrintService(&fakeTenantRepository{}, appointments, &fakeDentistRepository{})
+
+	doc, err := s.DentistSchedule(1, 4, "24/10/2025", domain.PeriodDay, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.PageCount() < 2 {
+		t.Errorf("printed %d pages, want the day split across at least 2", doc.PageCount())
+	}
+}
+
+func TestDentistScheduleRejectsInvalidInput(t *testing.T) {
+	s := NewPrintService(&fakeTenantRepository{}, &filterAppointmentRepository{}, &fakeDentistRepository{})
+	tests := []struct {
+		date, period, timeZone string
+	}{
+		{"24/10/2025", "month", ""},
+		{"2025-10-24", domain.PeriodDay, ""},
+		{"24/10/2025", domain.PeriodDay, "Mars/Olympus"},
+	}
+	for _, test := range tests {
+		if _, err := s.DentistSchedule(1, 4, test.date, test.period, test.timeZone); err == nil {
+			t.Errorf("DentistSchedule(%q, %q, %q) succeeded, want an error", test.date, test.period, test.timeZone)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/notifier"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reminderClaimExpiry is how long a claimed reminder waits to be sent before
+// another worker may claim it again, in case the one holding it crashed.
+const reminderClaimExpiry = 10 * time.Minute
+
+type ReminderService interface {
+	SendDue(ctx context.Context, now time.Time) error
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// -------------------------------------------
+type reminderService struct {
+	tenantRepo      repository.TenantRepository
+	appointmentRepo repository.AppointmentRepository
+	reminderRepo    repository.ReminderRepository
+	notifier        notifier.Notifier
+	offsets         []time.Duration
+}
+
+// NewReminderService sends a reminder for every offset before an appointment,
+// e.g. 48h and 2h before it starts.
+func NewReminderService(tenantRepo repository.TenantRepository, appointmentRepo repository.AppointmentRepository, reminderRepo repository.ReminderRepository, n notifier.Notifier, offsets []time.Duration) ReminderService {
+	sorted := append([]time.Duration(nil), offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+	return &reminderService{tenantRepo, appointmentRepo, reminderRepo, n, sorted}
+}
+
+// ParseReminderOffsets parses a comma separated list of durations such as
+// "48h,2h".
+func ParseReminderOffsets(value string) ([]time.Duration, error) {
+	var offsets []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		offset, err := time.ParseDuration(part)
+		if err != nil || offset < time.Minute {
+			return nil, errors.New("Invalid reminder offset " + part + ", expected a duration like 48h or 90m")
+		}
+		offsets = append(offsets, offset)
+	}
+	return offsets, nil
+}
+
+// -------------------------------------------
+
+// SendDue sends the reminders of every tenant that are due at now. Each
+// reminder is claimed before being sent so it is delivered only once.
+func (s *reminderService) SendDue(ctx context.Context, now time.Time) error {
+	if len(s.offsets) == 0 {
+		return nil
+	}
+	tenants, err := s.tenantRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, tenant := range tenants {
+		if tenant.DisabledAt != "" {
+			continue
+		}
+		appointments, err := s.appointmentRepo.Search(tenant.Id, domain.AppointmentFilter{
+			From: now,
+			To:   now.Add(s.offsets[0] + time.Second),
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, appointment := range appointments {
+			offset, ok := s.dueOffset(appointment.StartsAt.Sub(now))
+			if !ok {
+				continue
+			}
+			if err := s.remind(ctx, tenant.Id, appointment, offset, now); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run sends the due reminders every interval until ctx is done.
+func (s *reminderService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.SendDue(ctx, time.Now()); err != nil {
+			log.Printf("reminders: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dueOffset returns the reminder whose window contains the time left before
+// the appointment. The window of an offset ends where the next, smaller offset
+// starts, so an appointment booked late only gets the closest reminder.
+func (s *reminderService) dueOffset(left time.Duration) (time.Duration, bool) {
+	for i, offset := range s.offsets {
+		var next time.Duration
+		if i+1 < len(s.offsets) {
+			next = s.offsets[i+1]
+		}
+		if left > next && left <= offset {
+			return offset, true
+		}
+	}
+	return 0, false
+}
+
+func (s *reminderService) remind(ctx context.Context, tenantID int, appointment domain.Appointment, offset time.Duration, now time.Time) error {
+	claimed, err := s.reminderRepo.Claim(tenantID, appointment.Id, offset, now, reminderClaimExpiry)
+	if err != nil || !claimed {
+		return err
+	}
+	err = s.notifier.Notify(ctx, reminderMessage(appointment))
+	if err != nil && !errors.Is(err, notifier.ErrNoRecipient) {
+		if releaseErr := s.reminderRepo.Release(tenantID, appointment.Id, offset); releaseErr != nil {
+			return errors.Join(err, releaseErr)
+		}
+		return err
+	}
+	// Patients without contact details are marked as reminded too, there is
+	// nothing to retry.
+	return s.reminderRepo.MarkSent(tenantID, appointment.Id, offset)
+}
+
+func reminderMessage(appointment domain.Appointment) notifier.Message {
+	body := "Hello " + appointment.Patient.FirstName + ", this is a reminder of your appointment with Dr. " +
+		appointment.Dentist.FirstName + " " + appointment.Dentist.LastName +
+		" on " + appointment.Date + " at " + appointment.Hour + " (" + appointment.TimeZone + ")"
+	if appointment.Clinic.Id != 0 {
+		body += " at " + appointment.Clinic.Name + ", " + appointment.Clinic.Address
+	}
+	body += "."
+	return notifier.Message{
+		Email:   appointment.Patient.Email,
+		Phone:   appointment.Patient.Phone,
+		Subject: "Appointment reminder",
+		Body:    body,
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/notifier"
+	"testing"
+	"time"
+)
+
+type fakeTenantRepository struct {
+	repository.TenantRepository
+	tenants []domain.Tenant
+}
+
+func (r *fakeTenantRepository) GetAll() ([]domain.Tenant, error) {
+	return r.tenants, nil
+}
+
+func (r *fakeTenantRepository) GetByID(id int) (domain.Tenant, error) {
+	for _, tenant := range r.tenants {
+		if tenant.Id == id {
+			return tenant, nil
+		}
+	}
+	return domain.Tenant{}, errors.New("Tenant not found")
+}
+
+type fakeAppointmentRepository struct {
+	repository.AppointmentRepository
+	appointments []domain.Appointment
+}
+
+func (r *fakeAppointmentRepository) Search(tenantID int, filter domain.AppointmentFilter) ([]domain.Appointment, error) {
+	return r.appointments, nil
+}
+
+func (r *fakeAppointmentRepository) GetByID(tenantID int, id int) (domain.Appointment, error) {
+	for _, appointment := range r.appointments {
+		if appointment.Id == id {
+			return appointment, nil
+		}
+	}
+	return domain.Appointment{}, errors.New("Appointment not found")
+}
+
+type reminderClaim struct {
+	claimedAt time.Time
+	sent      bool
+}
+
+// fakeReminderRepository claims reminders the way the SQL store does: a
+// pending claim can only be taken over once it is older than expiry.
+type fakeReminderRepository struct {
+	claims map[[3]int64]*reminderClaim
+}
+
+func reminderKey(tenantID int, appointmentID int, offset time.Duration) [3]int64 {
+	return [3]int64{int64(tenantID), int64(appointmentID), int64(offset)}
+}
+
+func (r *fakeReminderRepository) Claim(tenantID int, appointmentID int, offset time.Duration, now time.Time, expiry time.Duration) (bool, error) {
+	key := reminderKey(tenantID, appointmentID, offset)
+	claim, ok := r.claims[key]
+	if !ok {
+		r.claims[key] = &reminderClaim{claimedAt: now}
+		return true, nil
+	}
+	if claim.sent || !claim.claimedAt.Before(now.Add(-expiry)) {
+		return false, nil
+	}
+	claim.claimedAt = now
+	return true, nil
+}
+
+func (r *fakeReminderRepository) MarkSent(tenantID int, appointmentID int, offset time.Duration) error {
+	r.claims[reminderKey(tenantID, appointmentID, offset)].sent = true
+	return nil
+}
+
+func (r *fakeReminderRepository) Release(tenantID int, appointmentID int, offset time.Duration) error {
+	delete(r.claims, reminderKey(tenantID, appointmentID, offset))
+	return nil
+}
+
+type countingNotifier struct {
+	sent int
+}
+
+func (n *countingNotifier) Notify(ctx context.Context, message notifier.Message) error {
+	n.sent++
+	return nil
+}
+
+func TestReminderLeftClaimedByACrashIsSentAfterTheClaimExpires(t *testing.T) {
+	t0 := time.Date(2024, 3, 30, 10, 0, 0, 0, time.UTC)
+	appointment := domain.Appointment{Id: 7, StartsAt: t0.Add(90 * time.Minute)}
+	reminders := &fakeReminderRepository{claims: map[[3]int64]*reminderClaim{}}
+	n := &countingNotifier{}
+	s := NewReminderService(
+		&fakeTenantRepository{tenants: []domain.Tenant{{Id: 1}}},
+		&fakeAppointmentRepository{appointments: []domain.Appointment{appointment}},
+		reminders, n, []time.Duration{2 * time.Hour},
+	)
+
+	// A worker claims the reminder and dies before sending or releasing it.
+	if claimed, _ := reminders.Claim(1, appointment.Id, 2*time.Hour, t0, reminderClaimExpiry); !claimed {
+		t.Fatal("first claim was refused")
+	}
+
+	if err := s.SendDue(context.Background(), t0.Add(5*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if n.sent != 0 {
+		t.Fatalf("sent %d reminders while the claim was still held, want 0", n.sent)
+	}
+
+	if err := s.SendDue(context.Background(), t0.Add(reminderClaimExpiry+time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if n.sent != 1 {
+		t.Fatalf("sent %d reminders after the claim expired, want 1", n.sent)
+	}
+
+	if err := s.SendDue(context.Background(), t0.Add(2*reminderClaimExpiry+time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if n.sent != 1 {
+		t.Fatalf("sent %d reminders once it was marked sent, want 1", n.sent)
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+)
+
+type ResourceService interface {
+	Create(tenantID int, resource domain.Resource) error
+	GetByID(tenantID int, id int) (domain.Resource, error)
+	GetAll(tenantID int) ([]domain.Resource, error)
+	Search(tenantID int, filter domain.ResourceFilter) ([]domain.Resource, error)
+	Update(tenantID int, resource domain.Resource) error
+	Delete(tenantID int, id int) error
+}
+
+// -------------------------------------------
+type resourceService struct {
+	resourceRepo repository.ResourceRepository
+	clinicRepo   repository.ClinicRepository
+}
+
+func NewResourceService(resourceRepo repository.ResourceRepository, clinicRepo repository.ClinicRepository) ResourceService {
+	return &resourceService{resourceRepo, clinicRepo}
+}
+
+// -------------------------------------------
+func (s *resourceService) Create(tenantID int, resource domain.Resource) error {
+	if !domain.ValidResourceKind(resource.Kind) {
+		return errors.New("Invalid resource kind: " + resource.Kind)
+	}
+	if resource.Clinic.Id != 0 {
+		if _, err := s.clinicRepo.GetByID(tenantID, resource.Clinic.Id); err != nil {
+			return err
+		}
+	}
+	err := s.resourceRepo.Create(tenantID, resource)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *resourceService) GetByID(tenantID int, id int) (domain.Resource, error) {
+	resource, err := s.resourceRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Resource{}, err
+	}
+	return resource, nil
+}
+
+func (s *resourceService) GetAll(tenantID int) ([]domain.Resource, error) {
+	resources, err := s.resourceRepo.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+func (s *resourceService) Search(tenantID int, filter domain.ResourceFilter) ([]domain.Resource, error) {
+	resources, err := s.resourceRepo.Search(tenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+func (s *resourceService) Update(tenantID int, resource domain.Resource) error {
+	existingResource, err := s.resourceRepo.GetByID(tenantID, resource.Id)
+	if err != nil {
+		return err
+	}
+	if resource.Name != "" {
+		existingResource.Name = resource.Name
+	}
+	if resource.Kind != "" {
+		if !domain.ValidResourceKind(resource.Kind) {
+			return errors.New("Invalid resource kind: " + resource.Kind)
+		}
+		existingResource.Kind = resource.Kind
+	}
+	if resource.Clinic.Id != 0 {
+		if _, err := s.clinicRepo.GetByID(tenantID, resource.Clinic.Id); err != nil {
+			return err
+		}
+		existingResource.Clinic = resource.Clinic
+	}
+	err = s.resourceRepo.Update(tenantID, existingResource)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *resourceService) Delete(tenantID int, id int) error {
+	err := s.resourceRepo.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"time"
+)
+
+type ScheduleService interface {
+	Create(tenantID int, schedule domain.Schedule) error
+	GetByDentist(tenantID int, dentistID int) ([]domain.Schedule, error)
+	Delete(tenantID int, dentistID int, id int) error
+	Availability(tenantID int, dentistID int, date string, clinicID int) ([]domain.Slot, error)
+}
+
+// -------------------------------------------
+type scheduleService struct {
+	scheduleRepo    repository.ScheduleRepository
+	dentistRepo     repository.DentistRepository
+	clinicRepo      repository.ClinicRepository
+	appointmentRepo repository.AppointmentRepository
+}
+
+func NewScheduleService(scheduleRepo repository.ScheduleRepository, dentistRepo repository.DentistRepository, clinicRepo repository.ClinicRepository, appointmentRepo repository.AppointmentRepository) ScheduleService {
+	return &scheduleService{scheduleRepo, dentistRepo, clinicRepo, appointmentRepo}
+}
+
+// -------------------------------------------
+func (s *scheduleService) Create(tenantID int, schedule domain.Schedule) error {
+	if _, err := s.dentistRepo.GetByID(tenantID, schedule.DentistId); err != nil {
+		return err
+	}
+	if _, err := s.clinicRepo.GetByID(tenantID, schedule.Clinic.Id); err != nil {
+		return err
+	}
+	if schedule.Weekday < 0 || schedule.Weekday > 6 {
+		return errors.New("Invalid weekday, expected 0 (Sunday) to 6 (Saturday)")
+	}
+	start, err := time.Parse(domain.HourLayout, schedule.StartHour)
+	if err != nil {
+		return errors.New("Invalid start hour, expected HH:MM")
+	}
+	end, err := time.Parse(domain.HourLayout, schedule.EndHour)
+	if err != nil {
+		return errors.New("Invalid end hour, expected HH:MM")
+	}
+	if !start.Before(end) {
+		return errors.New("Start hour must be before end hour")
+	}
+	schedule.StartHour = start.Format(domain.HourLayout)
+	schedule.EndHour = end.Format(domain.HourLayout)
+
+	err = s.scheduleRepo.Create(tenantID, schedule)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *scheduleService) GetByDentist(tenantID int, dentistID int) ([]domain.Schedule, error) {
+	schedules, err := s.scheduleRepo.GetByDentist(tenantID, dentistID)
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (s *scheduleService) Delete(tenantID int, dentistID int, id int) error {
+	schedule, err := s.scheduleRepo.GetByID(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if schedule.DentistId != dentistID {
+		return errors.New("Schedule not found")
+	}
+	err = s.scheduleRepo.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Availability lists the free slots of a dentist on the given date, optionally
+// restricted to one clinic. Each schedule is expanded in the local time of its
+// clinic, hours skipped by a daylight saving change are left out. Slots that
+// overlap any appointment of the dentist, at whatever clinic, are not available.
+func (s *scheduleService) Availability(tenantID int, dentistID int, date string, clinicID int) ([]domain.Slot, error) {
+	day, err := time.Parse(domain.DateLayout, date)
+	if err != nil {
+		return nil, errors.New("Invalid date, expected dd/MM/yyyy")
+	}
+	schedules, err := s.scheduleRepo.GetByDentist(tenantID, dentistID)
+	if err != nil {
+		return nil, err
+	}
+	// The window covers the local date in any time zone.
+	appointments, err := s.appointmentRepo.Search(tenantID, domain.AppointmentFilter{
+		DentistId: dentistID,
+		From:      day.AddDate(0, 0, -1),
+		To:        day.AddDate(0, 0, 2),
+	})
+	if err != nil {
+		return nil, err
+	}
+	taken := func(start time.Time) bool {
+		for _, appointment := range appointments {
+			if domain.SlotsOverlap(appointment.StartsAt, start) {
+				return true
+			}
+		}
+		return false
+	}
+
+	slots := []domain.Slot{}
+	for _, schedule := range schedules {
+		if schedule.Weekday != int(day.Weekday()) || (clinicID != 0 && schedule.Clinic.Id != clinicID) {
+			continue
+		}
+		loc, err := schedule.Clinic.Location()
+		if err != nil {
+			return nil, err
+		}
+		start, err := time.Parse(domain.HourLayout, schedule.StartHour)
+		if err != nil {
+			return nil, err
+		}
+		end, err := time.Parse(domain.HourLayout, schedule.EndHour)
+		if err != nil {
+			return nil, err
+		}
+		for t := start; t.Before(end); t = t.Add(domain.SlotDuration) {
+			startsAt, err := domain.ParseLocal(date, t.Format(domain.HourLayout), loc)
+			if err != nil || taken(startsAt) {
+				continue
+			}
+			slot := domain.Slot{Clinic: schedule.Clinic, StartsAt: startsAt}
+			slot.Localize(loc)
+			slots = append(slots, slot)
+		}
+	}
+	return slots, nil
+}
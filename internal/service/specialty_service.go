@@ -0,0 +1,80 @@
+package service
+
+import (
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+)
+
+type SpecialtyService interface {
+	Create(tenantID int, specialty domain.Specialty) error
+	GetByID(tenantID int, id int) (domain.Specialty, error)
+	GetAll(tenantID int) ([]domain.Specialty, error)
+	Delete(tenantID int, id int) error
+	AssignToDentist(tenantID int, dentistID int, specialtyID int) error
+	RemoveFromDentist(tenantID int, dentistID int, specialtyID int) error
+}
+
+// -------------------------------------------
+type specialtyService struct {
+	specialtyRepo repository.SpecialtyRepository
+	dentistRepo   repository.DentistRepository
+}
+
+func NewSpecialtyService(specialtyRepo repository.SpecialtyRepository, dentistRepo repository.DentistRepository) SpecialtyService {
+	return &specialtyService{specialtyRepo, dentistRepo}
+}
+
+// -------------------------------------------
+func (s *specialtyService) Create(tenantID int, specialty domain.Specialty) error {
+	err := s.specialtyRepo.Create(tenantID, specialty)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *specialtyService) GetByID(tenantID int, id int) (domain.Specialty, error) {
+	specialty, err := s.specialtyRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Specialty{}, err
+	}
+	return specialty, nil
+}
+
+func (s *specialtyService) GetAll(tenantID int) ([]domain.Specialty, error) {
+	specialties, err := s.specialtyRepo.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return specialties, nil
+}
+
+func (s *specialtyService) Delete(tenantID int, id int) error {
+	err := s.specialtyRepo.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *specialtyService) AssignToDentist(tenantID int, dentistID int, specialtyID int) error {
+	if _, err := s.dentistRepo.GetByID(tenantID, dentistID); err != nil {
+		return err
+	}
+	if _, err := s.specialtyRepo.GetByID(tenantID, specialtyID); err != nil {
+		return err
+	}
+	err := s.specialtyRepo.AddToDentist(tenantID, dentistID, specialtyID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *specialtyService) RemoveFromDentist(tenantID int, dentistID int, specialtyID int) error {
+	err := s.specialtyRepo.RemoveFromDentist(tenantID, dentistID, specialtyID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"strings"
+)
+
+type TenantService interface {
+	Create(tenant domain.Tenant) (domain.TenantCredentials, error)
+	GetByID(id int) (domain.Tenant, error)
+	GetAll() ([]domain.Tenant, error)
+	RotateToken(id int) (domain.TenantCredentials, error)
+	Delete(id int) error
+	Authenticate(token string) (domain.Tenant, error)
+}
+
+// -------------------------------------------
+type tenantService struct {
+	tenantRepo repository.TenantRepository
+}
+
+func NewTenantService(tenantRepo repository.TenantRepository) TenantService {
+	return &tenantService{tenantRepo}
+}
+
+// -------------------------------------------
+func (s *tenantService) Create(tenant domain.Tenant) (domain.TenantCredentials, error) {
+	tenant.Name = strings.TrimSpace(tenant.Name)
+	if tenant.Name == "" {
+		return domain.TenantCredentials{}, errors.New("Tenant name is required")
+	}
+	token, err := newToken()
+	if err != nil {
+		return domain.TenantCredentials{}, err
+	}
+	id, err := s.tenantRepo.Create(tenant, hashToken(token))
+	if err != nil {
+		return domain.TenantCredentials{}, err
+	}
+	created, err := s.tenantRepo.GetByID(id)
+	if err != nil {
+		return domain.TenantCredentials{}, err
+	}
+	return domain.TenantCredentials{Tenant: created, Token: token}, nil
+}
+
+func (s *tenantService) GetByID(id int) (domain.Tenant, error) {
+	tenant, err := s.tenantRepo.GetByID(id)
+	if err != nil {
+		return domain.Tenant{}, err
+	}
+	return tenant, nil
+}
+
+func (s *tenantService) GetAll() ([]domain.Tenant, error) {
+	tenants, err := s.tenantRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// RotateToken issues a new token for the tenant; the previous one stops
+// working immediately.
+func (s *tenantService) RotateToken(id int) (domain.TenantCredentials, error) {
+	tenant, err := s.tenantRepo.GetByID(id)
+	if err != nil {
+		return domain.TenantCredentials{}, err
+	}
+	token, err := newToken()
+	if err != nil {
+		return domain.TenantCredentials{}, err
+	}
+	if err := s.tenantRepo.UpdateTokenHash(id, hashToken(token)); err != nil {
+		return domain.TenantCredentials{}, err
+	}
+	return domain.TenantCredentials{Tenant: tenant, Token: token}, nil
+}
+
+// Delete disables the tenant: its token, calendar feeds and reminders stop
+// working, while its records are kept.
+func (s *tenantService) Delete(id int) error {
+	err := s.tenantRepo.Disable(id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Authenticate resolves the tenant that owns the given token.
+func (s *tenantService) Authenticate(token string) (domain.Tenant, error) {
+	if token == "" {
+		return domain.Tenant{}, errors.New("token not found")
+	}
+	tenant, err := s.tenantRepo.GetByTokenHash(hashToken(token))
+	if err != nil {
+		return domain.Tenant{}, errors.New("invalid token")
+	}
+	return tenant, nil
+}
+
+// newToken generates a random 256 bit token encoded as hex.
+func newToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashToken returns the SHA-256 digest stored in place of the plain token.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
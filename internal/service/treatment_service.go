@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+)
+
+type TreatmentService interface {
+	Create(tenantID int, treatment domain.Treatment) error
+	GetByID(tenantID int, id int) (domain.Treatment, error)
+	GetAll(tenantID int) ([]domain.Treatment, error)
+	Update(tenantID int, treatment domain.Treatment) error
+	Delete(tenantID int, id int) error
+}
+
+// -------------------------------------------
+type treatmentService struct {
+	treatmentRepo repository.TreatmentRepository
+	specialtyRepo repository.SpecialtyRepository
+}
+
+func NewTreatmentService(treatmentRepo repository.TreatmentRepository, specialtyRepo repository.SpecialtyRepository) TreatmentService {
+	return &treatmentService{treatmentRepo, specialtyRepo}
+}
+
+// -------------------------------------------
+func (s *treatmentService) Create(tenantID int, treatment domain.Treatment) error {
+	if err := validatePricing(treatment); err != nil {
+		return err
+	}
+	if treatment.Specialty.Id != 0 {
+		if _, err := s.specialtyRepo.GetByID(tenantID, treatment.Specialty.Id); err != nil {
+			return err
+		}
+	}
+	err := s.treatmentRepo.Create(tenantID, treatment)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *treatmentService) GetByID(tenantID int, id int) (domain.Treatment, error) {
+	treatment, err := s.treatmentRepo.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Treatment{}, err
+	}
+	return treatment, nil
+}
+
+func (s *treatmentService) GetAll(tenantID int) ([]domain.Treatment, error) {
+	treatments, err := s.treatmentRepo.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return treatments, nil
+}
+
+func (s *treatmentService) Update(tenantID int, treatment domain.Treatment) error {
+	existingTreatment, err := s.treatmentRepo.GetByID(tenantID, treatment.Id)
+	if err != nil {
+		return err
+	}
+	if treatment.Name != "" {
+		existingTreatment.Name = treatment.Name
+	}
+	// The price is always replaced, so a treatment can be made free of charge.
+	if err := validatePricing(treatment); err != nil {
+		return err
+	}
+	existingTreatment.Price = treatment.Price
+	existingTreatment.TaxRate = treatment.TaxRate
+	if treatment.Specialty.Id != 0 {
+		if _, err := s.specialtyRepo.GetByID(tenantID, treatment.Specialty.Id); err != nil {
+			return err
+		}
+		existingTreatment.Specialty = treatment.Specialty
+	}
+	err = s.treatmentRepo.Update(tenantID, existingTreatment)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *treatmentService) Delete(tenantID int, id int) error {
+	err := s.treatmentRepo.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func validatePricing(treatment domain.Treatment) error {
+	if treatment.Price < 0 {
+		return errors.New("Price can not be negative")
+	}
+	if treatment.TaxRate < 0 || treatment.TaxRate > 10000 {
+		return errors.New("TaxRate must be between 0 and 10000 basis points")
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/webhook"
+	"time"
+)
+
+const (
+	webhookBatchSize   = 20
+	webhookLease       = 2 * time.Minute
+	webhookMaxAttempts = 8
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+)
+
+type WebhookService interface {
+	Create(tenantID int, webhook domain.Webhook) (domain.Webhook, error)
+	GetByID(tenantID int, id int) (domain.Webhook, error)
+	GetAll(tenantID int) ([]domain.Webhook, error)
+	Update(tenantID int, webhook domain.Webhook) error
+	Delete(tenantID int, id int) error
+	GetDeliveries(tenantID int, webhookID int) ([]domain.WebhookDelivery, error)
+	Replay(tenantID int, webhookID int, deliveryID int64) (domain.WebhookDelivery, error)
+	HandleEvent(ctx context.Context, event domain.Event) error
+	DeliverDue(ctx context.Context, now time.Time) error
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// -------------------------------------------
+type webhookService struct {
+	r          repository.WebhookRepository
+	tenantRepo repository.TenantRepository
+	sender     *webhook.Sender
+}
+
+// NewWebhookService queues the outbox events of every active tenant for its
+// webhooks and sends them with the given sender.
+func NewWebhookService(r repository.WebhookRepository, tenantRepo repository.TenantRepository, sender *webhook.Sender) WebhookService {
+	return &webhookService{r, tenantRepo, sender}
+}
+
+//-------------------------------------------
+
+func (s *webhookService) Create(tenantID int, w domain.Webhook) (domain.Webhook, error) {
+	if err := validateWebhook(w); err != nil {
+		return domain.Webhook{}, err
+	}
+	if w.Secret == "" {
+		secret, err := newToken()
+		if err != nil {
+			return domain.Webhook{}, err
+		}
+		w.Secret = "whsec_" + secret
+	}
+	w.Active = true
+	w.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	id, err := s.r.Create(tenantID, w)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	w.Id = id
+	return w, nil
+}
+
+func (s *webhookService) GetByID(tenantID int, id int) (domain.Webhook, error) {
+	w, err := s.r.GetByID(tenantID, id)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	w.Secret = ""
+	return w, nil
+}
+
+func (s *webhookService) GetAll(tenantID int) ([]domain.Webhook, error) {
+	webhooks, err := s.r.GetAll(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// Update replaces the URL, event types and active flag of a webhook. The
+// secret is kept unless a new one is given.
+func (s *webhookService) Update(tenantID int, w domain.Webhook) error {
+	current, err := s.r.GetByID(tenantID, w.Id)
+	if err != nil {
+		return err
+	}
+	if err := validateWebhook(w); err != nil {
+		return err
+	}
+	if w.Secret == "" {
+		w.Secret = current.Secret
+	}
+	return s.r.Update(tenantID, w)
+}
+
+func (s *webhookService) Delete(tenantID int, id int) error {
+	err := s.r.Delete(tenantID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *webhookService) GetDeliveries(tenantID int, webhookID int) ([]domain.WebhookDelivery, error) {
+	if _, err := s.r.GetByID(tenantID, webhookID); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.r.GetDeliveries(tenantID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Replay sends a delivery again, whatever its status, with a fresh retry
+// budget.
+func (s *webhookService) Replay(tenantID int, webhookID int, deliveryID int64) (domain.WebhookDelivery, error) {
+	if err := s.r.ResetDelivery(tenantID, webhookID, deliveryID, time.Now().UTC()); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return s.r.GetDelivery(tenantID, webhookID, deliveryID)
+}
+
+// HandleEvent is registered in the outbox dispatcher; it queues a delivery of
+// the event for every active webhook of the tenant subscribed to it. The
+// dispatcher calls it once its claim is committed, so the deliveries, which
+// reference the event, are not left waiting on the event row lock. Events of
+// disabled tenants are not sent anywhere.
+func (s *webhookService) HandleEvent(ctx context.Context, event domain.Event) error {
+	tenant, err := s.tenantRepo.GetByID(event.TenantId)
+	if err != nil {
+		return err
+	}
+	if tenant.DisabledAt != "" {
+		return nil
+	}
+	webhooks, err := s.r.GetAll(event.TenantId)
+	if err != nil {
+		return err
+	}
+	for _, w := range webhooks {
+		if !w.Active || !w.Subscribes(event.Type) {
+			continue
+		}
+		if err := s.r.CreateDelivery(event.TenantId, w.Id, event.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliverDue sends the deliveries of every tenant that are due at now.
+func (s *webhookService) DeliverDue(ctx context.Context, now time.Time) error {
+	deliveries, err := s.r.ClaimDue(now.UTC(), webhookLease, webhookBatchSize)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, delivery := range deliveries {
+		if err := s.deliver(ctx, delivery); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run sends the due deliveries every interval until ctx is done.
+func (s *webhookService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.DeliverDue(ctx, time.Now()); err != nil {
+			log.Printf("webhooks: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver makes one attempt and schedules the next one with exponential
+// backoff, giving up after webhookMaxAttempts. Deliveries of a disabled
+// webhook or tenant fail without being sent.
+func (s *webhookService) deliver(ctx context.Context, delivery domain.WebhookDelivery) error {
+	w, err := s.r.GetByID(delivery.TenantId, delivery.WebhookId)
+	if err != nil {
+		return err
+	}
+	tenant, err := s.tenantRepo.GetByID(delivery.TenantId)
+	if err != nil {
+		return err
+	}
+
+	started := time.Now()
+	attempt := domain.WebhookAttempt{CreatedAt: started.UTC()}
+	switch {
+	case tenant.DisabledAt != "":
+		attempt.Error = "tenant is disabled"
+	case !w.Active:
+		attempt.Error = "webhook is disabled"
+	default:
+		body, err := json.Marshal(delivery.Event)
+		if err != nil {
+			return err
+		}
+		attempt.StatusCode, err = s.sender.Send(ctx, webhook.Request{
+			URL:        w.URL,
+			Secret:     w.Secret,
+			DeliveryID: delivery.Id,
+			EventType:  delivery.Event.Type,
+			Body:       body,
+		})
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+	}
+	attempt.DurationMs = time.Since(started).Milliseconds()
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.LastStatusCode = attempt.StatusCode
+	delivery.LastError = attempt.Error
+	switch {
+	case attempt.Error == "":
+		delivery.Status = domain.DeliveryDelivered
+		delivery.DeliveredAt = &now
+	case !w.Active || tenant.DisabledAt != "" || delivery.Attempts >= webhookMaxAttempts:
+		delivery.Status = domain.DeliveryFailed
+	default:
+		delivery.NextAttemptAt = now.Add(webhookBackoff(delivery.Attempts))
+	}
+	return s.r.RecordAttempt(delivery, attempt)
+}
+
+// webhookBackoff doubles the wait after every failed attempt.
+func webhookBackoff(attempts int) time.Duration {
+	wait := webhookBaseBackoff
+	for i := 1; i < attempts && wait < webhookMaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > webhookMaxBackoff {
+		wait = webhookMaxBackoff
+	}
+	return wait
+}
+
+// validateWebhook only accepts URLs resolving to public addresses, so tenants
+// can not make the API post to the services of its own network.
+func validateWebhook(w domain.Webhook) error {
+	if err := webhook.CheckURL(context.Background(), w.URL); err != nil {
+		if errors.Is(err, webhook.ErrForbiddenAddress) {
+			return errors.New("Invalid webhook URL, it must not point to a loopback, private or link-local address")
+		}
+		return err
+	}
+	if len(w.EventTypes) == 0 {
+		return errors.New("At least one event type is required")
+	}
+	for _, eventType := range w.EventTypes {
+		if !validEventType(eventType) {
+			return errors.New("Unknown event type " + eventType)
+		}
+	}
+	return nil
+}
+
+func validEventType(eventType string) bool {
+	if eventType == "*" {
+		return true
+	}
+	for _, t := range domain.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/internal/repository"
+	"proyecto_final_go/pkg/webhook"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeWebhookRepository keeps the webhooks and deliveries of one tenant in
+// memory. Claimed deliveries are leased like in the SQL store.
+type fakeWebhookRepository struct {
+	repository.WebhookRepository
+	webhooks   []domain.Webhook
+	events     map[int64]domain.Event
+	deliveries []domain.WebhookDelivery
+}
+
+func (r *fakeWebhookRepository) GetByID(tenantID int, id int) (domain.Webhook, error) {
+	for _, w := range r.webhooks {
+		if w.Id == id {
+			return w, nil
+		}
+	}
+	panic("unknown webhook " + strconv.Itoa(id))
+}
+
+func (r *fakeWebhookRepository) GetAll(tenantID int) ([]domain.Webhook, error) {
+	return r.webhooks, nil
+}
+
+func (r *fakeWebhookRepository) CreateDelivery(tenantID int, webhookID int, eventID int64) error {
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookId == webhookID && delivery.Event.Id == eventID {
+			return nil
+		}
+	}
+	r.deliveries = append(r.deliveries, domain.WebhookDelivery{
+		Id:        int64(len(r.deliveries) + 1),
+		TenantId:  tenantID,
+		WebhookId: webhookID,
+		Event:     r.events[eventID],
+		Status:    domain.DeliveryPending,
+	})
+	return nil
+}
+
+func (r *fakeWebhookRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	var due []domain.WebhookDelivery
+	for i := range r.deliveries {
+		delivery := &r.deliveries[i]
+		if delivery.Status != domain.DeliveryPending || delivery.NextAttemptAt.After(now) || len(due) == limit {
+			continue
+		}
+		delivery.NextAttemptAt = now.Add(lease)
+		due = append(due, *delivery)
+	}
+	return due, nil
+}
+
+func (r *fakeWebhookRepository) RecordAttempt(delivery domain.WebhookDelivery, attempt domain.WebhookAttempt) error {
+	delivery.AttemptLog = append(r.deliveries[delivery.Id-1].AttemptLog, attempt)
+	r.deliveries[delivery.Id-1] = delivery
+	return nil
+}
+
+func TestWebhookIsSignedAndRetriedAfterAServerError(t *testing.T) {
+	const secret = "whsec_test"
+	var mu sync.Mutex
+	var received int
+	var signatureErrors []error
+	var bodies []string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		received++
+		if err := webhook.Verify(secret, r.Header.Get(webhook.SignatureHeader), body, 5*time.Minute, time.Now()); err != nil {
+			signatureErrors = append(signatureErrors, err)
+		}
+		if r.Header.Get(webhook.EventHeader) != domain.EventAppointmentCreated || r.Header.Get(webhook.DeliveryHeader) != "1" {
+			t.Errorf("headers = %v, want the event type and delivery id", r.Header)
+		}
+		bodies = append(bodies, string(body))
+		if received == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	event := domain.Event{Id: 10, TenantId: 1, AggregateType: "appointment", AggregateId: 3, Type: domain.EventAppointmentCreated, Payload: []byte(`{"Id":3}`)}
+	r := &fakeWebhookRepository{
+		webhooks: []domain.Webhook{
+			{Id: 1, URL: receiver.URL, Secret: secret, EventTypes: []string{domain.EventAppointmentCreated}, Active: true},
+			{Id: 2, URL: receiver.URL, Secret: secret, EventTypes: []string{domain.EventAppointmentCancelled}, Active: true},
+		},
+		events: map[int64]domain.Event{event.Id: event},
+	}
+	s := NewWebhookService(r, &fakeTenantRepository{tenants: []domain.Tenant{{Id: 1}}}, webhook.NewSenderWithClient(receiver.Client()))
+
+	if err := s.HandleEvent(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.deliveries) != 1 {
+		t.Fatalf("queued %d deliveries, want 1 for the subscribed webhook", len(r.deliveries))
+	}
+
+	now := time.Now().UTC()
+	if err := s.DeliverDue(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+	delivery := r.deliveries[0]
+	if delivery.Status != domain.DeliveryPending || delivery.Attempts != 1 || delivery.LastStatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("after a 503 delivery = %+v, want it pending with one attempt", delivery)
+	}
+	if !delivery.NextAttemptAt.After(now) {
+		t.Fatalf("next attempt at %v, want it after %v", delivery.NextAttemptAt, now)
+	}
+
+	// Nothing is sent again before the backoff is over.
+	if err := s.DeliverDue(context.Background(), now.Add(webhookBaseBackoff/2)); err != nil {
+		t.Fatal(err)
+	}
+	if received != 1 {
+		t.Fatalf("receiver got %d requests before the backoff was over, want 1", received)
+	}
+
+	if err := s.DeliverDue(context.Background(), delivery.NextAttemptAt); err != nil {
+		t.Fatal(err)
+	}
+	delivery = r.deliveries[0]
+	if delivery.Status != domain.DeliveryDelivered || delivery.Attempts != 2 || len(delivery.AttemptLog) != 2 {
+		t.Fatalf("after the retry delivery = %+v, want it delivered with two attempts", delivery)
+	}
+
+	if received != 2 {
+		t.Fatalf("receiver got %d requests, want 2", received)
+	}
+	if len(signatureErrors) != 0 {
+		t.Fatalf("signature errors: %v", signatureErrors)
+	}
+	if bodies[0] != bodies[1] {
+		t.Fatalf("the retry sent %s, want the same body as %s", bodies[1], bodies[0])
+	}
+}
+
+func TestWebhooksOfADisabledTenantAreNotSent(t *testing.T) {
+	var received int
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	event := domain.Event{Id: 10, TenantId: 1, Type: domain.EventAppointmentCreated, Payload: []byte(`{"Id":3}`)}
+	r := &fakeWebhookRepository{
+		webhooks: []domain.Webhook{{Id: 1, URL: receiver.URL, Secret: "whsec_test", EventTypes: []string{"*"}, Active: true}},
+		events:   map[int64]domain.Event{event.Id: event},
+	}
+	tenants := &fakeTenantRepository{tenants: []domain.Tenant{{Id: 1}}}
+	s := NewWebhookService(r, tenants, webhook.NewSenderWithClient(receiver.Client()))
+
+	// A delivery queued before the tenant was disabled fails without being
+	// sent, and new events are not queued.
+	if err := s.HandleEvent(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	tenants.tenants[0].DisabledAt = "2024-04-01 12:00:00"
+	if err := s.DeliverDue(context.Background(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if delivery := r.deliveries[0]; delivery.Status != domain.DeliveryFailed || delivery.LastError != "tenant is disabled" {
+		t.Fatalf("delivery = %+v, want it failed because the tenant is disabled", delivery)
+	}
+
+	event.Id = 11
+	r.events[event.Id] = event
+	if err := s.HandleEvent(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.deliveries) != 1 || received != 0 {
+		t.Fatalf("queued %d deliveries and sent %d, want nothing new for a disabled tenant", len(r.deliveries), received)
+	}
+}
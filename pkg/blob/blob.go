@@ -0,0 +1,135 @@
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrNotFound is returned when there is no blob stored under the key.
+var ErrNotFound = errors.New("blob not found")
+
+// ErrChecksum is returned when the content of a blob does not match its
+// SHA-256 checksum.
+var ErrChecksum = errors.New("blob checksum mismatch")
+
+// Store keeps blobs under slash separated keys, e.g. "1/42/3f9a...".
+type Store interface {
+	// Put stores size bytes read from r under key. checksum is the hex
+	// SHA-256 of the content; the blob is not stored when it does not match.
+	Put(ctx context.Context, key string, r io.Reader, size int64, checksum string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// FromEnv builds the store configured in BLOB_STORAGE: "local" (the default)
+// keeps blobs under BLOB_LOCAL_DIR, "attachments" by default, and "s3" in
+// the S3_BUCKET bucket of an S3-compatible service at S3_ENDPOINT.
+func FromEnv() (Store, error) {
+	switch storage := os.Getenv("BLOB_STORAGE"); storage {
+	case "", "local":
+		dir := os.Getenv("BLOB_LOCAL_DIR")
+		if dir == "" {
+			dir = "attachments"
+		}
+		return NewLocal(dir)
+	case "s3":
+		return NewS3(
+			os.Getenv("S3_ENDPOINT"),
+			os.Getenv("S3_REGION"),
+			os.Getenv("S3_BUCKET"),
+			os.Getenv("S3_ACCESS_KEY"),
+			os.Getenv("S3_SECRET_KEY"),
+		)
+	default:
+		return nil, fmt.Errorf("unknown blob storage %q", storage)
+	}
+}
+
+// Checksum reads r to the end and returns the hex SHA-256 of its content and
+// its size.
+func Checksum(r io.Reader) (string, int64, error) {
+	h := sha256.New()
+	size, err := io.Copy(h, r)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// Verify wraps r so that reading it to the end fails with ErrChecksum when
+// the content does not match checksum. The last byte is only returned once
+// the content is verified, so a corrupted blob streamed to a client never
+// arrives complete.
+func Verify(r io.ReadCloser, checksum string) io.ReadCloser {
+	return &verifier{r: r, h: sha256.New(), checksum: checksum}
+}
+
+type verifier struct {
+	r        io.ReadCloser
+	h        hash.Hash
+	checksum string
+	last     byte  // the last byte read, held back until the next read
+	held     bool  // whether last holds a byte
+	err      error // the error that ended the content, io.EOF when verified
+}
+
+func (v *verifier) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for v.err == nil {
+		n, err := v.r.Read(p)
+		v.h.Write(p[:n])
+		if n > 0 {
+			// Return the byte held back and all but the last one read.
+			next := p[n-1]
+			if v.held {
+				copy(p[1:n], p[:n-1])
+				p[0] = v.last
+			} else {
+				n--
+			}
+			v.last, v.held = next, true
+		}
+		if err == io.EOF && hex.EncodeToString(v.h.Sum(nil)) != v.checksum {
+			err = ErrChecksum
+		}
+		v.err = err
+		if err != nil && err != io.EOF {
+			return n, err
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+	if v.err == io.EOF && v.held {
+		p[0], v.held = v.last, false
+		return 1, nil
+	}
+	return 0, v.err
+}
+
+func (v *verifier) Close() error {
+	return v.r.Close()
+}
+
+// checkKey rejects keys that could escape the store, like "../x" or "/x".
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid blob key %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package blob
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestVerifyReturnsVerifiedContent(t *testing.T) {
+	checksum, _, err := Checksum(strings.NewReader("original"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range []io.Reader{strings.NewReader("original"), iotest.OneByteReader(strings.NewReader("original")), iotest.DataErrReader(strings.NewReader("original"))} {
+		got, err := io.ReadAll(Verify(io.NopCloser(r), checksum))
+		if err != nil || string(got) != "original" {
+			t.Errorf("ReadAll = %q, %v; want the original content", got, err)
+		}
+	}
+	if err := iotest.TestReader(Verify(io.NopCloser(strings.NewReader("original")), checksum), []byte("original")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyHoldsBackTheEndOfACorruptedBlob(t *testing.T) {
+	checksum, _, err := Checksum(strings.NewReader("original"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range []io.Reader{strings.NewReader("corrupte"), iotest.OneByteReader(strings.NewReader("corrupte"))} {
+		got, err := io.ReadAll(Verify(io.NopCloser(r), checksum))
+		if !errors.Is(err, ErrChecksum) {
+			t.Errorf("ReadAll = %v, want ErrChecksum", err)
+		}
+		if len(got) >= len("corrupte") {
+			t.Errorf("read %q before the error, want the last byte held back", got)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 answers the path-style object requests of the S3 API the way the
+// service does, keeping the objects in memory.
+type fakeS3 struct {
+	t       *testing.T
+	bucket  string
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/us-east-1/s3/aws4_request") ||
+		!strings.Contains(auth, "SignedHeaders=host;x-amz-content-sha256;x-amz-date") || r.Header.Get("X-Amz-Date") == "" {
+		f.t.Errorf("%s %s is not signed: %q", r.Method, r.URL.Path, auth)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, "<Error><Code>NoSuchBucket</Code></Error>")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if hex.EncodeToString(sum[:]) != r.Header.Get("X-Amz-Content-Sha256") {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, "<Error><Code>XAmzContentSHA256Mismatch</Code><Message>The provided 'x-amz-content-sha256' header does not match what was computed.</Message></Error>")
+			return
+		}
+		f.objects[key] = body
+	case http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newFakeS3Store(t *testing.T) (Store, *fakeS3) {
+	fake := &fakeS3{t: t, bucket: "clinic", objects: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	store, err := NewS3(server.URL, "", "clinic", "AKID", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, fake
+}
+
+func TestS3PutGetDelete(t *testing.T) {
+	store, fake := newFakeS3Store(t)
+	ctx := context.Background()
+	key := "1/42/informe final.pdf"
+	content := []byte("%PDF-1.4 radiografía panorámica")
+	checksum, size, err := Checksum(bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Put(ctx, key, bytes.NewReader(content), size, checksum); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fake.objects[key], content) {
+		t.Fatalf("stored %q under %q, want %q", fake.objects[key], key, content)
+	}
+
+	r, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(Verify(r, checksum))
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("Get = %q, want %q", got, content)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete of a missing blob = %v, want nil", err)
+	}
+}
+
+func TestS3PutRejectsAChecksumMismatch(t *testing.T) {
+	store, fake := newFakeS3Store(t)
+	ctx := context.Background()
+	checksum, size, err := Checksum(strings.NewReader("original"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Put(ctx, "1/7/tampered.png", strings.NewReader("tampered"), size, checksum)
+	if !errors.Is(err, ErrChecksum) {
+		t.Fatalf("Put = %v, want ErrChecksum", err)
+	}
+	if len(fake.objects) != 0 {
+		t.Fatalf("stored %d objects, want none", len(fake.objects))
+	}
+}
+
+func TestS3GetDetectsACorruptedBlob(t *testing.T) {
+	store, fake := newFakeS3Store(t)
+	ctx := context.Background()
+	checksum, _, err := Checksum(strings.NewReader("original"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.objects["1/7/scan.png"] = []byte("corrupted")
+
+	r, err := store.Get(ctx, "1/7/scan.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if _, err := io.ReadAll(Verify(r, checksum)); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("reading a corrupted blob = %v, want ErrChecksum", err)
+	}
+}
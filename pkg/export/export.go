@@ -0,0 +1,86 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Writer writes the rows of a spreadsheet as they come, so exports do not
+// hold the whole listing in memory. Close must be called to finish the file.
+type Writer interface {
+	Write(record []string) error
+	Close() error
+}
+
+// New returns a writer of the given format. The sheet name is only used by
+// XLSX files.
+func New(format string, w io.Writer, sheet string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w, sheet)
+	}
+	return nil, errors.New("export: unknown format " + format)
+}
+
+// ContentType returns the media type of the format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/octet-stream"
+}
+
+// asText keeps spreadsheet programs from evaluating a value as a formula,
+// e.g. a patient registered as "=HYPERLINK(...)", by prefixing the values that
+// start like one with a quote, which they show as text. Only CSV needs it:
+// XLSX cells are typed.
+func asText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+type csvWriter struct {
+	buf *bufio.Writer
+	w   *csv.Writer
+}
+
+// newCSVWriter starts the file with a byte order mark so spreadsheet
+// programs read the accents of names and addresses as UTF-8.
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	buf := bufio.NewWriter(w)
+	if _, err := buf.WriteString("\ufeff"); err != nil {
+		return nil, err
+	}
+	return &csvWriter{buf, csv.NewWriter(buf)}, nil
+}
+
+func (c *csvWriter) Write(record []string) error {
+	values := make([]string, len(record))
+	for i, value := range record {
+		values[i] = asText(value)
+	}
+	return c.w.Write(values)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return err
+	}
+	return c.buf.Flush()
+}
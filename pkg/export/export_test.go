@@ -0,0 +1,83 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+)
+
+var formulaRecord = []string{"=HYPERLINK(\"http://evil\",\"x\")", "+5491155550000", "-2+3", "@SUM(A1)", "\tTab", "\rReturn", "Juan Pérez", ""}
+
+var formulaWant = []string{"'=HYPERLINK(\"http://evil\",\"x\")", "'+5491155550000", "'-2+3", "'@SUM(A1)", "'\tTab", "'\rReturn", "Juan Pérez", ""}
+
+func TestCSVWriterQuotesFormulas(t *testing.T) {
+	var out bytes.Buffer
+	w, err := New(FormatCSV, &out, "patients")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(formulaRecord); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(out.String(), "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("read %d records, want 1", len(records))
+	}
+	for i, want := range formulaWant {
+		if records[0][i] != want {
+			t.Errorf("column %d = %q, want %q", i, records[0][i], want)
+		}
+	}
+	if formulaRecord[0][0] != '=' {
+		t.Errorf("Write changed the record passed in")
+	}
+}
+
+func TestXLSXWriterKeepsFormulasAsText(t *testing.T) {
+	var out bytes.Buffer
+	w, err := New(FormatXLSX, &out, "patients")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(formulaRecord); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := archive.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sheet, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range formulaRecord {
+		if want == "" {
+			continue
+		}
+		if !strings.Contains(string(sheet), `t="inlineStr"><is><t xml:space="preserve">`+escape(want)+`</t>`) {
+			t.Errorf("sheet does not contain %q unchanged as text: %s", want, sheet)
+		}
+	}
+	if strings.Contains(string(sheet), "<f>") {
+		t.Errorf("sheet contains a formula: %s", sheet)
+	}
+}
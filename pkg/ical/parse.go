@@ -0,0 +1,191 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attendee is an ATTENDEE of an imported event.
+type Attendee struct {
+	Name  string
+	Email string
+	// Params holds the property parameters, e.g. X-DNI.
+	Params map[string]string
+}
+
+// Parse reads the events of a calendar. Times without a zone (floating times
+// and all-day dates) are read in loc; TZID parameters naming an IANA zone are
+// honored. Properties the writer does not know about are kept in Extra.
+func Parse(r io.Reader, loc *time.Location) (Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	var calendar Calendar
+	var event *Event
+	for _, line := range lines {
+		name, params, value, ok := splitLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			event = &Event{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if event != nil {
+				calendar.Events = append(calendar.Events, *event)
+			}
+			event = nil
+		case event == nil:
+			switch name {
+			case "PRODID":
+				calendar.ProdID = unescape(value)
+			case "X-WR-CALNAME":
+				calendar.Name = unescape(value)
+			}
+		default:
+			if err := event.set(name, params, value, loc); err != nil {
+				return Calendar{}, err
+			}
+		}
+	}
+	if len(calendar.Events) == 0 && !containsCalendar(lines) {
+		return Calendar{}, errors.New("ical: not an iCalendar file")
+	}
+	return calendar, nil
+}
+
+func (e *Event) set(name string, params map[string]string, value string, loc *time.Location) error {
+	var err error
+	switch name {
+	case "UID":
+		e.UID = unescape(value)
+	case "SEQUENCE":
+		e.Sequence, _ = strconv.Atoi(value)
+	case "DTSTAMP":
+		e.Stamp, err = parseTime(value, params, loc)
+	case "DTSTART":
+		e.Start, err = parseTime(value, params, loc)
+		e.AllDay = params["VALUE"] == "DATE" || len(value) == 8
+	case "DTEND":
+		e.End, err = parseTime(value, params, loc)
+	case "SUMMARY":
+		e.Summary = unescape(value)
+	case "DESCRIPTION":
+		e.Description = unescape(value)
+	case "LOCATION":
+		e.Location = unescape(value)
+	case "STATUS":
+		e.Status = strings.ToUpper(value)
+	case "ATTENDEE":
+		attendee := Attendee{Name: params["CN"], Params: params}
+		if strings.HasPrefix(strings.ToLower(value), "mailto:") {
+			attendee.Email = value[len("mailto:"):]
+		}
+		e.Attendees = append(e.Attendees, attendee)
+	default:
+		if strings.HasPrefix(name, "X-") {
+			if e.Extra == nil {
+				e.Extra = make(map[string]string)
+			}
+			e.Extra[name] = unescape(value)
+		}
+	}
+	if err != nil {
+		return errors.New("ical: invalid " + name + " in event " + e.UID + ": " + value)
+	}
+	return nil
+}
+
+// unfold joins the continuation lines of the content.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// splitLine splits "NAME;PARAM=VALUE:value" honoring quoted parameter values.
+func splitLine(line string) (string, map[string]string, string, bool) {
+	inQuotes := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuotes = !inQuotes
+		} else if c == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", false
+	}
+	parts := splitParams(line[:colon])
+	params := make(map[string]string)
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(part, "=")
+		params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:], true
+}
+
+func splitParams(s string) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i, c := range s {
+		if c == '"' {
+			inQuotes = !inQuotes
+		} else if c == ';' && !inQuotes {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseTime(value string, params map[string]string, loc *time.Location) (time.Time, error) {
+	if tzid := params["TZID"]; tzid != "" {
+		if zone, err := time.LoadLocation(tzid); err == nil {
+			loc = zone
+		}
+	}
+	switch {
+	case params["VALUE"] == "DATE" || len(value) == 8:
+		return time.ParseInLocation("20060102", value, loc)
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse(utcLayout, value)
+	default:
+		return time.ParseInLocation("20060102T150405", value, loc)
+	}
+}
+
+var unescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func unescape(value string) string {
+	return unescaper.Replace(value)
+}
+
+func containsCalendar(lines []string) bool {
+	for _, line := range lines {
+		if strings.EqualFold(line, "BEGIN:VCALENDAR") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit lets each client IP make burst requests at once and then one
+// every interval, answering 429 with Retry-After beyond that. The limits are
+// kept in memory by each instance of the API. Client IPs are read as
+// gin.Engine.SetTrustedProxies allows; trusting every proxy would let
+// clients pick their IP in X-Forwarded-For.
+func RateLimit(interval time.Duration, burst int) gin.HandlerFunc {
+	l := newLimiter(interval, burst)
+	return func(c *gin.Context) {
+		if wait := l.wait(c.ClientIP(), time.Now()); wait > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// limiter keeps, for every client, the time at which all of its burst is
+// available again; a request is allowed while that is at most burst-1
+// intervals away, and pushes it one interval further.
+type limiter struct {
+	interval time.Duration
+	burst    int
+
+	mu        sync.Mutex
+	clients   map[string]time.Time
+	lastSweep time.Time
+}
+
+func newLimiter(interval time.Duration, burst int) *limiter {
+	return &limiter{interval: interval, burst: burst, clients: map[string]time.Time{}}
+}
+
+// wait records a request of the client at now and returns zero, or how long
+// it has to wait when the request is over the limit.
+func (l *limiter) wait(client string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	full := l.clients[client]
+	if full.Before(now) {
+		full = now
+	}
+	if over := full.Sub(now) - time.Duration(l.burst-1)*l.interval; over > 0 {
+		return over
+	}
+	l.clients[client] = full.Add(l.interval)
+	return 0
+}
+
+// sweep forgets, at most once a minute, the clients whose burst is available
+// again, as they are in the same state as a client never seen.
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for client, full := range l.clients {
+		if !full.After(now) {
+			delete(l.clients, client)
+		}
+	}
+}
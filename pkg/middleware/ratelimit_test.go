@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLimiterAllowsTheBurstAndThenOneEveryInterval(t *testing.T) {
+	l := newLimiter(10*time.Second, 3)
+	t0 := time.Date(2024, 4, 2, 9, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		if wait := l.wait("10.0.0.1", t0); wait != 0 {
+			t.Fatalf("request %d of the burst waits %v", i+1, wait)
+		}
+	}
+	if wait := l.wait("10.0.0.1", t0); wait != 10*time.Second {
+		t.Errorf("request over the burst waits %v, want 10s", wait)
+	}
+	if wait := l.wait("10.0.0.2", t0); wait != 0 {
+		t.Errorf("another client waits %v, want its own burst", wait)
+	}
+	if wait := l.wait("10.0.0.1", t0.Add(10*time.Second)); wait != 0 {
+		t.Errorf("request after an interval waits %v", wait)
+	}
+	if wait := l.wait("10.0.0.1", t0.Add(10*time.Second)); wait == 0 {
+		t.Error("second request after a single interval was allowed")
+	}
+	if wait := l.wait("10.0.0.1", t0.Add(time.Hour)); wait != 0 || len(l.clients) != 1 {
+		t.Errorf("after an hour the request waits %v with %d clients kept, want it allowed and the idle client forgotten", wait, len(l.clients))
+	}
+}
+
+func TestRateLimitAnswersTooManyRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/check", RateLimit(time.Minute, 2), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	want := []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests}
+	for i, code := range want {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/check", nil))
+		if w.Code != code {
+			t.Fatalf("request %d = %d, want %d", i+1, w.Code, code)
+		}
+		if code == http.StatusTooManyRequests && w.Header().Get("Retry-After") != "60" {
+			t.Errorf("Retry-After = %q, want 60", w.Header().Get("Retry-After"))
+		}
+	}
+}
@@ -1,26 +1,76 @@
 package middleware
 
 import (
-	"errors"
+	"crypto/subtle"
 	"net/http"
 	"os"
+	"proyecto_final_go/internal/domain"
 
 	"github.com/gin-gonic/gin"
 )
 
-func Authentication() gin.HandlerFunc {
+// tenantKey is the context key holding the id of the authenticated tenant.
+const tenantKey = "tenantID"
+
+// TenantResolver resolves the tenant owning an API token.
+type TenantResolver interface {
+	Authenticate(token string) (domain.Tenant, error)
+}
+
+// Authentication validates the TOKEN header and stores the tenant it belongs
+// to in the request context, so every query is scoped to that practice.
+func Authentication(resolver TenantResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticate(c, resolver, c.GetHeader("TOKEN"))
+	}
+}
+
+// StreamAuthentication also accepts the token in the token query parameter,
+// since browsers cannot set headers on EventSource connections.
+func StreamAuthentication(resolver TenantResolver) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.GetHeader("TOKEN")
 		if token == "" {
-			c.JSON(http.StatusUnauthorized, errors.New("token not found"))
-			c.Abort()
+			token = c.Query("token")
+		}
+		authenticate(c, resolver, token)
+	}
+}
+
+func authenticate(c *gin.Context, resolver TenantResolver, token string) {
+	if token == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token not found"})
+		return
+	}
+	tenant, err := resolver.Authenticate(token)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+	c.Set(tenantKey, tenant.Id)
+	c.Next()
+}
+
+// AdminAuthentication protects the tenant provisioning endpoints with the
+// ADMIN_TOKEN environment variable, sent in the X-Admin-Token header. Proxies
+// such as nginx drop headers with underscores by default.
+func AdminAuthentication() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader("X-Admin-Token")
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token not found"})
 			return
 		}
-		if token != os.Getenv("TOKEN") {
-			c.JSON(http.StatusUnauthorized, errors.New("invalid token"))
-			c.Abort()
+		expected := os.Getenv("ADMIN_TOKEN")
+		if expected == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 			return
 		}
 		c.Next()
 	}
 }
+
+// TenantID returns the tenant set by Authentication.
+func TenantID(c *gin.Context) int {
+	return c.GetInt(tenantKey)
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("ADMIN_TOKEN", "admin-secret")
+	r := gin.New()
+	r.GET("/admin", AdminAuthentication(), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := []struct {
+		header string
+		token  string
+		want   int
+	}{
+		{"X-Admin-Token", "admin-secret", http.StatusNoContent},
+		{"X-Admin-Token", "wrong", http.StatusUnauthorized},
+		{"X-Admin-Token", "", http.StatusUnauthorized},
+		// The old header name is no longer read.
+		{"ADMIN_TOKEN", "admin-secret", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		if tt.token != "" {
+			req.Header.Set(tt.header, tt.token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: %q = %d, want %d", tt.header, tt.token, w.Code, tt.want)
+		}
+	}
+}
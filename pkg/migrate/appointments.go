@@ -0,0 +1,126 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"proyecto_final_go/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// legacyLayouts are the forms the free-text Date and Hour columns were
+// written in: dd/MM/yyyy and HH:MM, with or without leading zeros or seconds.
+var legacyLayouts = []string{"2/1/2006 15:04", "2/1/2006 15:04:05"}
+
+// legacyStartsAt converts the Date and Hour of an appointment, given in the
+// time zone of its clinic, into the UTC instant stored in StartsAt.
+func legacyStartsAt(date string, hour string, loc *time.Location) (time.Time, error) {
+	value := strings.TrimSpace(date) + " " + strings.TrimSpace(hour)
+	for _, layout := range legacyLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("can not read " + strconv.Quote(value) + " as dd/MM/yyyy HH:MM")
+}
+
+// addStartsAt replaces the local Date and Hour of the appointments by the UTC
+// StartsAt. Every row is converted in the time zone of its clinic, or
+// DefaultTimeZone, before Date and Hour are dropped; if a row can not be read
+// nothing is dropped and the rows are reported.
+func addStartsAt(ctx context.Context, db *sql.DB) error {
+	timeZone, err := hasColumn(ctx, db, "clinics", "TimeZone")
+	if err != nil {
+		return err
+	}
+	if !timeZone {
+		if err := exec(ctx, db, "ALTER TABLE clinics ADD COLUMN TimeZone VARCHAR(64) NOT NULL DEFAULT '"+domain.DefaultTimeZone+"' AFTER Address;"); err != nil {
+			return err
+		}
+	}
+	legacy, err := hasColumn(ctx, db, "appointments", "Date")
+	if err != nil || !legacy {
+		return err
+	}
+	startsAt, err := hasColumn(ctx, db, "appointments", "StartsAt")
+	if err != nil {
+		return err
+	}
+	if !startsAt {
+		if err := exec(ctx, db, "ALTER TABLE appointments ADD COLUMN StartsAt DATETIME NULL COMMENT 'UTC' AFTER tenants_Id;"); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		SELECT a.Id, COALESCE(a.Date, ''), COALESCE(a.Hour, ''), COALESCE(c.TimeZone, '')
+		FROM appointments AS a
+		LEFT JOIN clinics AS c ON a.clinics_Id = c.Id
+		WHERE a.StartsAt IS NULL;
+	`
+	clinics, err := hasColumn(ctx, db, "appointments", "clinics_Id")
+	if err != nil {
+		return err
+	}
+	if !clinics {
+		query = "SELECT Id, COALESCE(Date, ''), COALESCE(Hour, ''), '' FROM appointments WHERE StartsAt IS NULL;"
+	}
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	type converted struct {
+		id       int
+		startsAt time.Time
+	}
+	var done []converted
+	var failed []string
+	for rows.Next() {
+		var id int
+		var date, hour, zone string
+		if err := rows.Scan(&id, &date, &hour, &zone); err != nil {
+			rows.Close()
+			return err
+		}
+		loc, err := domain.LoadLocation(zone)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("appointment %d: %v", id, err))
+			continue
+		}
+		t, err := legacyStartsAt(date, hour, loc)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("appointment %d: %v", id, err))
+			continue
+		}
+		done = append(done, converted{id, t})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, row := range done {
+		if _, err := tx.ExecContext(ctx, "UPDATE appointments SET StartsAt = ? WHERE Id = ?;", row.startsAt, row.id); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return errors.New("fix these appointments and run again, Date and Hour are kept until then:\n" + strings.Join(failed, "\n"))
+	}
+
+	return exec(ctx, db,
+		"ALTER TABLE appointments MODIFY StartsAt DATETIME NOT NULL COMMENT 'UTC', ADD INDEX idx_appointments_starts_at (tenants_Id ASC, StartsAt ASC);",
+		"ALTER TABLE appointments DROP COLUMN Date, DROP COLUMN Hour;",
+	)
+}
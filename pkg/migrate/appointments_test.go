@@ -0,0 +1,47 @@
+package migrate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLegacyStartsAtReadsTheClinicTimeZone(t *testing.T) {
+	buenosAires, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	if err != nil {
+		t.Fatal(err)
+	}
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		date, hour string
+		loc        *time.Location
+		want       time.Time
+	}{
+		{"30/03/2024", "09:00", buenosAires, time.Date(2024, time.March, 30, 12, 0, 0, 0, time.UTC)},
+		{"5/4/2024", "9:30", buenosAires, time.Date(2024, time.April, 5, 12, 30, 0, 0, time.UTC)},
+		{" 31/12/2024 ", "23:30:00", buenosAires, time.Date(2025, time.January, 1, 2, 30, 0, 0, time.UTC)},
+		// Summer time in Madrid starts on the last Sunday of March.
+		{"30/03/2024", "10:00", madrid, time.Date(2024, time.March, 30, 9, 0, 0, 0, time.UTC)},
+		{"01/04/2024", "10:00", madrid, time.Date(2024, time.April, 1, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		got, err := legacyStartsAt(test.date, test.hour, test.loc)
+		if err != nil {
+			t.Errorf("legacyStartsAt(%q, %q) = %v", test.date, test.hour, err)
+			continue
+		}
+		if !got.Equal(test.want) || got.Location() != time.UTC {
+			t.Errorf("legacyStartsAt(%q, %q, %s) = %v, want %v", test.date, test.hour, test.loc, got, test.want)
+		}
+	}
+}
+
+func TestLegacyStartsAtRejectsUnreadableValues(t *testing.T) {
+	for _, value := range [][2]string{{"", ""}, {"2024-03-30", "09:00"}, {"30/03/2024", "9am"}, {"31/02/2024", "09:00"}} {
+		if got, err := legacyStartsAt(value[0], value[1], time.UTC); err == nil {
+			t.Errorf("legacyStartsAt(%q, %q) = %v, want an error", value[0], value[1], got)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Step upgrades a database created by an earlier build-database.sql. Steps
+// check the schema before changing it, so a step interrupted halfway can be
+// run again.
+type Step struct {
+	Name string
+	Up   func(ctx context.Context, db *sql.DB) error
+}
+
+// Steps are run once each, in order, and recorded in schema_migrations.
+var Steps = []Step{
+	{Name: "0001_tenants", Up: addTenants},
+	{Name: "0002_appointments_starts_at", Up: addStartsAt},
+}
+
+// Run applies the steps not recorded yet and returns their names. New tables
+// are created by build-database.sql, which must be run first; Run only
+// changes the tables it left untouched because they already existed.
+func Run(ctx context.Context, db *sql.DB) ([]string, error) {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			Name VARCHAR(64) NOT NULL,
+			AppliedAt DATETIME NOT NULL,
+			PRIMARY KEY (Name)
+		);
+	`
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return nil, err
+	}
+	applied := []string{}
+	for _, step := range Steps {
+		var name string
+		err := db.QueryRowContext(ctx, "SELECT Name FROM schema_migrations WHERE Name = ?;", step.Name).Scan(&name)
+		if err == nil {
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return applied, err
+		}
+		if err := step.Up(ctx, db); err != nil {
+			return applied, fmt.Errorf("%s: %w", step.Name, err)
+		}
+		if _, err := db.ExecContext(ctx, "INSERT INTO schema_migrations (Name, AppliedAt) VALUES (?, ?);", step.Name, time.Now().UTC()); err != nil {
+			return applied, err
+		}
+		applied = append(applied, step.Name)
+	}
+	return applied, nil
+}
+
+// hasColumn reports whether the table of the current schema has the column,
+// false when the table does not exist.
+func hasColumn(ctx context.Context, db *sql.DB, table string, column string) (bool, error) {
+	var n int
+	query := "SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?;"
+	err := db.QueryRowContext(ctx, query, table, column).Scan(&n)
+	return n > 0, err
+}
+
+// hasTable reports whether the current schema has the table.
+func hasTable(ctx context.Context, db *sql.DB, table string) (bool, error) {
+	var n int
+	query := "SELECT COUNT(*) FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?;"
+	err := db.QueryRowContext(ctx, query, table).Scan(&n)
+	return n > 0, err
+}
+
+// exec runs the statements in order, stopping at the first error.
+func exec(ctx context.Context, db *sql.DB, statements ...string) error {
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
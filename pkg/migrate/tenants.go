@@ -0,0 +1,86 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// DefaultTenant owns the records created before the API was multi-tenant.
+const DefaultTenant = 1
+
+// tenantTables are the tables that existed before tenants were added.
+var tenantTables = []string{"clinics", "dentists", "dentist_schedules", "patients", "specialties", "treatments", "appointments", "resources"}
+
+// addTenants adds tenants_Id to the tables created before tenants existed,
+// assigns their rows to DefaultTenant and only then makes it required.
+// Specialty names become unique per tenant.
+func addTenants(ctx context.Context, db *sql.DB) error {
+	var id int
+	err := db.QueryRowContext(ctx, "SELECT Id FROM tenants WHERE Id = ?;", DefaultTenant).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errors.New("the default tenant is missing, run build-database.sql first")
+	}
+	if err != nil {
+		return err
+	}
+	disabled, err := hasColumn(ctx, db, "tenants", "DisabledAt")
+	if err != nil {
+		return err
+	}
+	if !disabled {
+		if err := exec(ctx, db, "ALTER TABLE tenants ADD COLUMN DisabledAt DATETIME NULL DEFAULT NULL AFTER CreatedAt;"); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range tenantTables {
+		exists, err := hasTable(ctx, db, table)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		done, err := hasColumn(ctx, db, table, "tenants_Id")
+		if err != nil {
+			return err
+		}
+		if !done {
+			if err := exec(ctx, db, "ALTER TABLE `"+table+"` ADD COLUMN tenants_Id INT NULL AFTER Id;"); err != nil {
+				return err
+			}
+		}
+		// Backfill and constrain also when a previous run stopped halfway.
+		if _, err := db.ExecContext(ctx, "UPDATE `"+table+"` SET tenants_Id = ? WHERE tenants_Id IS NULL;", DefaultTenant); err != nil {
+			return err
+		}
+		if err := exec(ctx, db, "ALTER TABLE `"+table+"` MODIFY tenants_Id INT NOT NULL;"); err != nil {
+			return err
+		}
+		if err := addTenantKey(ctx, db, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addTenantKey indexes tenants_Id and references tenants from it, as
+// build-database.sql does for new databases.
+func addTenantKey(ctx context.Context, db *sql.DB, table string) error {
+	var n int
+	query := "SELECT COUNT(*) FROM information_schema.TABLE_CONSTRAINTS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND CONSTRAINT_NAME = ?;"
+	if err := db.QueryRowContext(ctx, query, table, "fk_"+table+"_tenants").Scan(&n); err != nil || n > 0 {
+		return err
+	}
+	if table == "specialties" {
+		return exec(ctx, db,
+			"ALTER TABLE specialties DROP INDEX Name_UNIQUE, ADD UNIQUE INDEX Name_UNIQUE (tenants_Id ASC, Name ASC);",
+			"ALTER TABLE specialties ADD CONSTRAINT fk_specialties_tenants FOREIGN KEY (tenants_Id) REFERENCES tenants (Id);",
+		)
+	}
+	return exec(ctx, db,
+		"ALTER TABLE `"+table+"` ADD INDEX idx_"+table+"_tenants (tenants_Id ASC);",
+		"ALTER TABLE `"+table+"` ADD CONSTRAINT fk_"+table+"_tenants FOREIGN KEY (tenants_Id) REFERENCES tenants (Id);",
+	)
+}
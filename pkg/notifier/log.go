@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// logNotifier writes messages to a writer instead of delivering them. It is
+// meant for development.
+type logNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewLogNotifier writes every message to w.
+func NewLogNotifier(w io.Writer) Notifier {
+	return &logNotifier{w: w}
+}
+
+// NewFileNotifier appends every message to the file at path, or to stdout
+// when path is empty.
+func NewFileNotifier(path string) (Notifier, error) {
+	if path == "" {
+		return NewLogNotifier(os.Stdout), nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return NewLogNotifier(f), nil
+}
+
+func (n *logNotifier) Notify(ctx context.Context, message Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err := fmt.Fprintf(n.w, "\n[notification] %s\nemail: %s\nphone: %s\nsubject: %s\n%s\n",
+		time.Now().Format(time.RFC3339), message.Email, message.Phone, message.Subject, message.Body)
+	return err
+}
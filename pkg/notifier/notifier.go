@@ -0,0 +1,96 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrNoRecipient is returned when the message has no address the notifier can
+// deliver to, e.g. an SMS for a patient without a phone.
+var ErrNoRecipient = errors.New("no recipient for this notifier")
+
+// Message is a notification addressed to a single person.
+type Message struct {
+	Email   string
+	Phone   string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages through one channel.
+type Notifier interface {
+	Notify(ctx context.Context, message Message) error
+}
+
+// multi sends every message through all of its notifiers.
+type multi []Notifier
+
+// Multi combines several notifiers. A message counts as delivered when at
+// least one notifier delivers it.
+func Multi(notifiers ...Notifier) Notifier {
+	return multi(notifiers)
+}
+
+func (m multi) Notify(ctx context.Context, message Message) error {
+	var errs []error
+	delivered := false
+	for _, n := range m {
+		err := n.Notify(ctx, message)
+		switch {
+		case err == nil:
+			delivered = true
+		case !errors.Is(err, ErrNoRecipient):
+			errs = append(errs, err)
+		}
+	}
+	if delivered {
+		return nil
+	}
+	if len(errs) == 0 {
+		return ErrNoRecipient
+	}
+	return errors.Join(errs...)
+}
+
+// FromEnv builds the notifier configured in NOTIFIER, a comma separated list
+// of channels: "log", "smtp" and "sms". It defaults to "log".
+func FromEnv() (Notifier, error) {
+	channels := os.Getenv("NOTIFIER")
+	if channels == "" {
+		channels = "log"
+	}
+	var notifiers []Notifier
+	for _, channel := range strings.Split(channels, ",") {
+		switch strings.TrimSpace(channel) {
+		case "log":
+			n, err := NewFileNotifier(os.Getenv("NOTIFIER_LOG_FILE"))
+			if err != nil {
+				return nil, err
+			}
+			notifiers = append(notifiers, n)
+		case "smtp":
+			notifiers = append(notifiers, NewSMTPNotifier(
+				os.Getenv("SMTP_HOST"),
+				os.Getenv("SMTP_PORT"),
+				os.Getenv("SMTP_USERNAME"),
+				os.Getenv("SMTP_PASSWORD"),
+				os.Getenv("SMTP_FROM"),
+			))
+		case "sms":
+			notifiers = append(notifiers, NewSMSNotifier(
+				os.Getenv("SMS_GATEWAY_URL"),
+				os.Getenv("SMS_GATEWAY_TOKEN"),
+				os.Getenv("SMS_FROM"),
+			))
+		default:
+			return nil, fmt.Errorf("unknown notifier %q", channel)
+		}
+	}
+	if len(notifiers) == 1 {
+		return notifiers[0], nil
+	}
+	return Multi(notifiers...), nil
+}
@@ -0,0 +1,62 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// smsNotifier sends text messages through an HTTP gateway that accepts a JSON
+// body with "to", "from" and "body", authenticated with a bearer token.
+type smsNotifier struct {
+	url    string
+	token  string
+	from   string
+	client *http.Client
+}
+
+// NewSMSNotifier sends SMS through the gateway at url.
+func NewSMSNotifier(url string, token string, from string) Notifier {
+	return &smsNotifier{
+		url:    url,
+		token:  token,
+		from:   from,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *smsNotifier) Notify(ctx context.Context, message Message) error {
+	if message.Phone == "" {
+		return ErrNoRecipient
+	}
+	payload, err := json.Marshal(map[string]string{
+		"to":   message.Phone,
+		"from": n.from,
+		"body": message.Subject + "\n" + message.Body,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("sms gateway returned %s: %s", res.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
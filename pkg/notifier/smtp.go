@@ -0,0 +1,50 @@
+package notifier
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// smtpNotifier sends messages by email.
+type smtpNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPNotifier sends email through the given SMTP server. Authentication
+// is only used when a username is given.
+func NewSMTPNotifier(host string, port string, username string, password string, from string) Notifier {
+	if port == "" {
+		port = "587"
+	}
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpNotifier{
+		addr: net.JoinHostPort(host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (n *smtpNotifier) Notify(ctx context.Context, message Message) error {
+	if message.Email == "" {
+		return ErrNoRecipient
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var b strings.Builder
+	b.WriteString("From: " + n.from + "\r\n")
+	b.WriteString("To: " + message.Email + "\r\n")
+	b.WriteString("Subject: " + message.Subject + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return smtp.SendMail(n.addr, n.auth, n.from, []string{message.Email}, []byte(b.String()))
+}
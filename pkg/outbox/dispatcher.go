@@ -0,0 +1,268 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"proyecto_final_go/internal/domain"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AllEvents registers a handler for every event type.
+const AllEvents = "*"
+
+// Handler processes one event. Handlers may receive the same event more than
+// once and must be idempotent.
+type Handler func(ctx context.Context, event domain.Event) error
+
+type registration struct {
+	name      string
+	eventType string
+	handler   Handler
+}
+
+// Dispatcher delivers the outbox events to the registered handlers with
+// at-least-once semantics. Failed deliveries are retried with exponential
+// backoff; each handler is retried on its own, so handlers that already
+// succeeded are not called again.
+type Dispatcher struct {
+	db          *sql.DB
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	lease       time.Duration
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+
+	mu       sync.RWMutex
+	handlers []registration
+}
+
+// NewDispatcher polls the outbox every interval.
+func NewDispatcher(db *sql.DB, interval time.Duration) *Dispatcher {
+	return &Dispatcher{
+		db:          db,
+		interval:    interval,
+		batchSize:   50,
+		maxAttempts: 10,
+		lease:       5 * time.Minute,
+		baseBackoff: time.Second,
+		maxBackoff:  time.Hour,
+	}
+}
+
+// Register adds a handler for an event type, or for AllEvents. The name
+// identifies the handler in the delivery log and must be stable across
+// restarts.
+func (d *Dispatcher) Register(eventType string, name string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers = append(d.handlers, registration{name: name, eventType: eventType, handler: handler})
+}
+
+// Run dispatches events until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		n, err := d.DispatchOnce(ctx)
+		if err != nil {
+			log.Printf("outbox: %v", err)
+		}
+		if n == d.batchSize && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce delivers one batch of due events and returns how many were
+// processed. The batch is claimed with SKIP LOCKED, so several instances can
+// dispatch concurrently without picking the same events, and leased by
+// pushing NextAttemptAt forward; the claim is committed before any handler
+// runs, so handlers never hold the outbox rows locked. Events whose
+// dispatcher crashed are picked up again once the lease runs out. An event
+// that can not be delivered is retried later and does not stop the rest of
+// the batch; the errors are returned together.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	events, attempts, err := d.claim(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for i, event := range events {
+		if err := d.deliver(ctx, event, attempts[i]); err != nil {
+			// If the failure can not be recorded either, the event is
+			// retried once its lease runs out.
+			errs = append(errs, fmt.Errorf("event %d: %w", event.Id, errors.Join(err, d.fail(ctx, event, attempts[i], err.Error()))))
+		}
+	}
+	return len(events), errors.Join(errs...)
+}
+
+// claim leases a batch of due events with their attempts so far. Events of
+// disabled tenants are left pending and reach no handler.
+func (d *Dispatcher) claim(ctx context.Context, now time.Time) ([]domain.Event, []int, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT e.Id, e.tenants_Id, e.AggregateType, e.AggregateId, e.Type, e.Payload, e.CreatedAt, e.Attempts
+		FROM outbox_events AS e
+		INNER JOIN tenants AS t ON e.tenants_Id = t.Id
+		WHERE e.Status = 'pending' AND e.NextAttemptAt <= ? AND t.DisabledAt IS NULL
+		ORDER BY e.Id
+		LIMIT ?
+		FOR UPDATE OF e SKIP LOCKED;
+	`
+	rows, err := tx.QueryContext(ctx, query, now, d.batchSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	var events []domain.Event
+	var attempts []int
+	for rows.Next() {
+		var event domain.Event
+		var attempt int
+		if err := rows.Scan(&event.Id, &event.TenantId, &event.AggregateType, &event.AggregateId, &event.Type, &event.Payload, &event.CreatedAt, &attempt); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		events = append(events, event)
+		attempts = append(attempts, attempt)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	leasedUntil := now.Add(d.lease)
+	for _, event := range events {
+		_, err := tx.ExecContext(ctx, "UPDATE outbox_events SET NextAttemptAt = ? WHERE Id = ?;", leasedUntil, event.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	return events, attempts, nil
+}
+
+// deliver calls the pending handlers of a claimed event. Every successful
+// handler is recorded as soon as it returns, in its own statement, so a crash
+// halfway through does not call it again.
+func (d *Dispatcher) deliver(ctx context.Context, event domain.Event, attempts int) error {
+	delivered, err := d.deliveredHandlers(ctx, event.Id)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, reg := range d.handlersFor(event.Type) {
+		if delivered[reg.name] {
+			continue
+		}
+		if err := call(ctx, reg.handler, event); err != nil {
+			failures = append(failures, reg.name+": "+err.Error())
+			continue
+		}
+		_, err := d.db.ExecContext(ctx, "INSERT IGNORE INTO outbox_deliveries (events_Id, Handler, DeliveredAt) VALUES (?, ?, ?);", event.Id, reg.name, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	if len(failures) == 0 {
+		_, err := d.db.ExecContext(ctx, "UPDATE outbox_events SET Status = 'done', ProcessedAt = ?, LastError = NULL WHERE Id = ?;", now, event.Id)
+		return err
+	}
+
+	return d.fail(ctx, event, attempts, strings.Join(failures, "; "))
+}
+
+// fail records a failed attempt of the event, scheduling the next one after
+// the backoff or giving up after maxAttempts.
+func (d *Dispatcher) fail(ctx context.Context, event domain.Event, attempts int, lastError string) error {
+	now := time.Now().UTC()
+	attempts++
+	if attempts >= d.maxAttempts {
+		log.Printf("outbox: giving up on event %d (%s) after %d attempts: %s", event.Id, event.Type, attempts, lastError)
+		_, err := d.db.ExecContext(ctx, "UPDATE outbox_events SET Status = 'failed', Attempts = ?, ProcessedAt = ?, LastError = ? WHERE Id = ?;", attempts, now, lastError, event.Id)
+		return err
+	}
+	next := now.Add(d.backoff(attempts))
+	_, err := d.db.ExecContext(ctx, "UPDATE outbox_events SET Attempts = ?, NextAttemptAt = ?, LastError = ? WHERE Id = ?;", attempts, next, lastError, event.Id)
+	return err
+}
+
+func (d *Dispatcher) handlersFor(eventType string) []registration {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var matching []registration
+	for _, reg := range d.handlers {
+		if reg.eventType == AllEvents || reg.eventType == eventType {
+			matching = append(matching, reg)
+		}
+	}
+	return matching
+}
+
+// backoff doubles the wait after every failed attempt.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.baseBackoff
+	for i := 1; i < attempts && wait < d.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > d.maxBackoff {
+		wait = d.maxBackoff
+	}
+	return wait
+}
+
+func (d *Dispatcher) deliveredHandlers(ctx context.Context, eventID int64) (map[string]bool, error) {
+	rows, err := d.db.QueryContext(ctx, "SELECT Handler FROM outbox_deliveries WHERE events_Id = ?;", eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	delivered := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		delivered[name] = true
+	}
+	return delivered, rows.Err()
+}
+
+// call runs a handler, turning a panic into an error so one faulty handler
+// cannot stop the dispatcher.
+func call(ctx context.Context, handler Handler, event domain.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New(fmt.Sprint("panic: ", r))
+		}
+	}()
+	return handler(ctx, event)
+}
+
+// LogHandler prints every event, useful during development.
+func LogHandler(ctx context.Context, event domain.Event) error {
+	log.Printf("event %d %s %s/%d tenant %d: %s", event.Id, event.Type, event.AggregateType, event.AggregateId, event.TenantId, event.Payload)
+	return nil
+}
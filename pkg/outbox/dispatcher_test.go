@@ -0,0 +1,55 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackoffDoublesUpToTheMaximum(t *testing.T) {
+	d := NewDispatcher(nil, time.Second)
+	want := map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		3:  4 * time.Second,
+		12: 2048 * time.Second,
+		13: time.Hour,
+		40: time.Hour,
+	}
+	for attempts, wait := range want {
+		if got := d.backoff(attempts); got != wait {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, wait)
+		}
+	}
+}
+
+func TestHandlersForMatchesTheTypeAndAllEvents(t *testing.T) {
+	d := NewDispatcher(nil, time.Second)
+	noop := func(ctx context.Context, event domain.Event) error { return nil }
+	d.Register(domain.EventAppointmentCreated, "reminders", noop)
+	d.Register(AllEvents, "webhooks", noop)
+	d.Register(domain.EventPatientCreated, "welcome", noop)
+
+	var names []string
+	for _, reg := range d.handlersFor(domain.EventAppointmentCreated) {
+		names = append(names, reg.name)
+	}
+	if strings.Join(names, ",") != "reminders,webhooks" {
+		t.Errorf("handlers = %v, want reminders and webhooks", names)
+	}
+}
+
+func TestCallTurnsAPanicIntoAnError(t *testing.T) {
+	event := domain.Event{Id: 1, Type: domain.EventAppointmentCreated}
+	err := call(context.Background(), func(ctx context.Context, event domain.Event) error { panic("nil map") }, event)
+	if err == nil || err.Error() != "panic: nil map" {
+		t.Errorf("call = %v, want the panic as an error", err)
+	}
+	failed := errors.New("receiver down")
+	if err := call(context.Background(), func(ctx context.Context, event domain.Event) error { return failed }, event); err != failed {
+		t.Errorf("call = %v, want the handler error", err)
+	}
+}
@@ -0,0 +1,29 @@
+package outbox
+
+import (
+	"database/sql"
+	"encoding/json"
+	"proyecto_final_go/internal/domain"
+	"time"
+)
+
+// Record writes an event to the outbox inside the transaction that performs
+// the change, so the event exists if and only if the change is committed.
+func Record(tx *sql.Tx, tenantID int, aggregateType string, aggregateID int, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	query := `
+		INSERT INTO outbox_events (tenants_Id, AggregateType, AggregateId, Type, Payload, CreatedAt, NextAttemptAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?);
+	`
+	_, err = tx.Exec(query, tenantID, aggregateType, aggregateID, eventType, data, now, now)
+	return err
+}
+
+// RecordAppointment writes an appointment event to the outbox.
+func RecordAppointment(tx *sql.Tx, tenantID int, eventType string, payload domain.AppointmentEvent) error {
+	return Record(tx, tenantID, "appointment", payload.AppointmentId, eventType, payload)
+}
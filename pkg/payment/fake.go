@@ -0,0 +1,66 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// DeclinedToken is the card token the fake provider always declines.
+const DeclinedToken = "tok_declined"
+
+// Fake charges an in-memory ledger. It never moves money and forgets
+// everything on restart; it is meant for development and tests.
+type Fake struct {
+	mu       sync.Mutex
+	next     int
+	charges  map[string]int64
+	refunded map[string]int64
+}
+
+// NewFake returns an empty fake provider.
+func NewFake() *Fake {
+	return &Fake{charges: map[string]int64{}, refunded: map[string]int64{}}
+}
+
+func (f *Fake) Name() string {
+	return "fake"
+}
+
+func (f *Fake) Charge(ctx context.Context, request Request) (string, error) {
+	if request.Token == DeclinedToken {
+		return "", ErrDeclined
+	}
+	if request.Amount <= 0 {
+		return "", errors.New("the amount must be positive")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.next++
+	reference := "fake_ch_" + strconv.Itoa(f.next)
+	f.charges[reference] = request.Amount
+	return reference, nil
+}
+
+func (f *Fake) Refund(ctx context.Context, charge string, request Request) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	amount, ok := f.charges[charge]
+	if !ok {
+		return "", errors.New("unknown charge " + charge)
+	}
+	if request.Amount <= 0 || request.Amount > amount-f.refunded[charge] {
+		return "", errors.New("the refund exceeds what is left of the charge")
+	}
+	f.refunded[charge] += request.Amount
+	f.next++
+	return "fake_re_" + strconv.Itoa(f.next), nil
+}
+
+// Balance returns what is left of a charge after its refunds.
+func (f *Fake) Balance(charge string) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.charges[charge] - f.refunded[charge]
+}
@@ -0,0 +1,48 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrDeclined is returned when the provider refuses the charge.
+var ErrDeclined = errors.New("payment declined")
+
+// Request is an amount to charge or refund through a provider.
+type Request struct {
+	// Amount in cents.
+	Amount      int64
+	Description string
+	// Token identifies the card for providers charging it themselves.
+	Token string
+	// Reference is the voucher or transaction number of an operation already
+	// made outside, e.g. on a card terminal.
+	Reference string
+}
+
+// Provider collects card payments and gives them back.
+type Provider interface {
+	// Name identifies the provider the payments were made with.
+	Name() string
+	// Charge collects the amount and returns the reference of the charge.
+	Charge(ctx context.Context, request Request) (string, error)
+	// Refund gives back up to the amount of the charge with the reference
+	// given and returns the reference of the refund.
+	Refund(ctx context.Context, charge string, request Request) (string, error)
+}
+
+// FromEnv builds the provider configured in PAYMENT_PROVIDER: "terminal" (the
+// default) records card payments collected on a card terminal, and "fake"
+// charges an in-memory ledger, for development and tests.
+func FromEnv() (Provider, error) {
+	switch provider := os.Getenv("PAYMENT_PROVIDER"); provider {
+	case "", "terminal":
+		return NewTerminal(), nil
+	case "fake":
+		return NewFake(), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", provider)
+	}
+}
@@ -0,0 +1,217 @@
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Size is a page size in points (1/72 inch).
+type Size struct {
+	Width  float64
+	Height float64
+}
+
+var (
+	A4 = Size{595.28, 841.89}
+	A5 = Size{419.53, 595.28}
+)
+
+// Document is a PDF built in memory page by page. Coordinates are given in
+// points from the top left corner of the page. Text uses the standard
+// Helvetica fonts, so nothing has to be embedded, and is limited to the
+// Latin-1 characters and common punctuation; other characters are printed
+// as "?".
+type Document struct {
+	Title   string
+	Author  string
+	size    Size
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+}
+
+// New returns an empty document with pages of the given size.
+func New(size Size) *Document {
+	return &Document{size: size}
+}
+
+// Size returns the page size of the document.
+func (d *Document) Size() Size {
+	return d.size
+}
+
+// AddPage starts a new page, where the next drawing goes.
+func (d *Document) AddPage() {
+	d.current = &bytes.Buffer{}
+	d.pages = append(d.pages, d.current)
+}
+
+// PageCount returns the number of pages added so far.
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// SetPage makes the nth page (from 1) the one drawn on, e.g. to number the
+// pages once all of them are laid out.
+func (d *Document) SetPage(n int) {
+	d.current = d.pages[n-1]
+}
+
+// Text draws s with its baseline starting at x, y.
+func (d *Document) Text(x float64, y float64, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page(), "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, num(size), num(x), num(d.size.Height-y), encode(s))
+}
+
+// TextRight draws s with its baseline ending at x, y.
+func (d *Document) TextRight(x float64, y float64, size float64, bold bool, s string) {
+	d.Text(x-TextWidth(s, size, bold), y, size, bold, s)
+}
+
+// Line draws a line of the given width.
+func (d *Document) Line(x1 float64, y1 float64, x2 float64, y2 float64, width float64) {
+	fmt.Fprintf(d.page(), "%s w %s %s m %s %s l S\n", num(width), num(x1), num(d.size.Height-y1), num(x2), num(d.size.Height-y2))
+}
+
+// Point is a position in points from the top left corner of the page.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Polyline draws connected lines through the points with round joins and
+// ends, as a pen would, e.g. the strokes of a handwritten signature. A single
+// point draws a dot.
+func (d *Document) Polyline(points []Point, width float64) {
+	if len(points) == 0 {
+		return
+	}
+	page := d.page()
+	fmt.Fprintf(page, "q 1 J 1 j %s w %s %s m", num(width), num(points[0].X), num(d.size.Height-points[0].Y))
+	if len(points) == 1 {
+		points = append(points, points[0])
+	}
+	for _, p := range points[1:] {
+		fmt.Fprintf(page, " %s %s l", num(p.X), num(d.size.Height-p.Y))
+	}
+	page.WriteString(" S Q\n")
+}
+
+// Rect fills a rectangle in a shade of gray, from 0 (black) to 1 (white).
+func (d *Document) Rect(x float64, y float64, w float64, h float64, gray float64) {
+	fmt.Fprintf(d.page(), "%s g %s %s %s %s re f 0 g\n", num(gray), num(x), num(d.size.Height-y-h), num(w), num(h))
+}
+
+func (d *Document) page() *bytes.Buffer {
+	if d.current == nil {
+		d.AddPage()
+	}
+	return d.current
+}
+
+// WriteTo writes the document. Page contents are compressed.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	out := &countingWriter{w: bufio.NewWriter(w)}
+	var offsets []int64
+	object := func(body string) {
+		offsets = append(offsets, out.n)
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// Objects 1 to 4 are the catalog, the page tree, the fonts; the info
+	// dictionary is 5 and every page is followed by its content stream.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = strconv.Itoa(6+2*i) + " 0 R"
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(d.pages), num(d.size.Width), num(d.size.Height)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Author (%s) /Producer (proyecto_final_go) /CreationDate (D:%s) >>",
+		encode(d.Title), encode(d.Author), time.Now().UTC().Format("20060102150405Z")))
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", 7+2*i))
+		var compressed bytes.Buffer
+		z := zlib.NewWriter(&compressed)
+		z.Write(content.Bytes())
+		z.Close()
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.Bytes()))
+	}
+
+	xref := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	if err := out.w.Flush(); err != nil {
+		return out.n, err
+	}
+	return out.n, out.err
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	if err != nil && c.err == nil {
+		c.err = err
+	}
+	return n, err
+}
+
+func (c *countingWriter) WriteString(s string) (int, error) {
+	return c.Write([]byte(s))
+}
+
+func num(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// winAnsi maps the common characters outside Latin-1 to their WinAnsi code.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// encode converts s to the WinAnsi encoding of the fonts and escapes it as
+// the content of a PDF string.
+func encode(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\t':
+			b.WriteByte(' ')
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r >= 160 && r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		case winAnsi[r] != 0:
+			fmt.Fprintf(&b, "\\%03o", winAnsi[r])
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -2,16 +2,22 @@ package store
 
 import (
 	"proyecto_final_go/internal/domain"
+	"time"
 )
 
 type AppointmentStoreInterface interface {
-	Read(id int) (domain.Appointment, error)
-	ReadByPatientDNI(patientDNI string) ([]domain.Appointment, error)
-	Create(appointment domain.Appointment) error
-	CreateByPatientDNIAndDentistLicense(patientDNI string, license string, date string, hour string, description string) ([]domain.Appointment, error)
-	Update(appointment domain.Appointment) error
-	Delete(id int) error
-	GetAll() ([]domain.Appointment, error)
-	Exists(id int) (bool, error)
-	PatchDescription(id int, description string) error
+	Read(tenantID int, id int) (domain.Appointment, error)
+	ReadByPatientDNI(tenantID int, patientDNI string) ([]domain.Appointment, error)
+	Create(tenantID int, appointment domain.Appointment) error
+	CreateWithPatient(tenantID int, patient domain.Patient, appointment domain.Appointment) error
+	CreateByPatientDNIAndDentistLicense(tenantID int, patientDNI string, license string, appointment domain.Appointment) ([]domain.Appointment, error)
+	Update(tenantID int, appointment domain.Appointment) error
+	Delete(tenantID int, id int) error
+	GetAll(tenantID int) ([]domain.Appointment, error)
+	Search(tenantID int, filter domain.AppointmentFilter) ([]domain.Appointment, error)
+	Each(tenantID int, filter domain.AppointmentFilter, fn func(domain.Appointment) error) error
+	Exists(tenantID int, id int) (bool, error)
+	PatchDescription(tenantID int, id int, description string) error
+	CheckIn(tenantID int, id int, at time.Time) error
+	Complete(tenantID int, id int, at time.Time) error
 }
@@ -3,7 +3,11 @@ package store
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/pkg/outbox"
+	"strings"
+	"time"
 )
 
 type sqlAppointmentStore struct {
@@ -18,63 +22,84 @@ func NewSqlAppointmentStore(db *sql.DB) AppointmentStoreInterface {
 
 //-----------------------------------
 
-func (s *sqlAppointmentStore) Read(id int) (domain.Appointment, error) {
+const selectAppointments = `
+	SELECT 
+		a.Id, a.StartsAt, a.Description, a.plan_steps_Id, a.CompletedAt, a.CheckedInAt,
+		p.Id AS patient_id, p.FirstName AS patient_first_name, p.LastName AS patient_last_name, p.Address AS patient_address, p.DNI AS patient_dni, p.ReleaseDate AS patient_release_date, p.Email AS patient_email, p.Phone AS patient_phone,
+		d.Id AS dentist_id, d.FirstName AS dentist_first_name, d.LastName AS dentist_last_name, d.License AS dentist_license,
+		t.Id AS treatment_id, t.Name AS treatment_name, sp.Id AS specialty_id, sp.Name AS specialty_name,
+		c.Id AS clinic_id, c.Name AS clinic_name, c.Address AS clinic_address, c.TimeZone AS clinic_time_zone
+	FROM 
+		appointments AS a
+	INNER JOIN 
+		patients AS p ON a.patients_Id = p.Id
+	INNER JOIN 
+		dentists AS d ON a.dentists_Id = d.Id
+	LEFT JOIN 
+		treatments AS t ON a.treatments_Id = t.Id
+	LEFT JOIN 
+		specialties AS sp ON t.specialties_Id = sp.Id
+	LEFT JOIN 
+		clinics AS c ON a.clinics_Id = c.Id
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAppointment(row scanner) (domain.Appointment, error) {
 	var appointment domain.Appointment
-	query := `
-		SELECT 
-			a.Id, a.Date, a.Hour, a.Description,
-			p.Id AS patient_id, p.FirstName AS patient_first_name, p.LastName AS patient_last_name, p.Address AS patient_address, p.DNI AS patient_dni, p.ReleaseDate AS patient_release_date,
-			d.Id AS dentist_id, d.FirstName AS dentist_first_name, d.LastName AS dentist_last_name, d.License AS dentist_license
-		FROM 
-			appointments AS a
-		INNER JOIN 
-			patients AS p ON a.patients_Id = p.Id
-		INNER JOIN 
-			dentists AS d ON a.dentists_Id = d.Id
-		WHERE 
-			a.Id = ?;
-	`
-	row := s.db.QueryRow(query, id)
+	var treatmentID, specialtyID, clinicID, planStepID sql.NullInt64
+	var treatmentName, specialtyName, clinicName, clinicAddress, clinicTimeZone sql.NullString
+	var completedAt, checkedInAt sql.NullTime
 	err := row.Scan(
-		&appointment.Id, &appointment.Date, &appointment.Hour, &appointment.Description,
-		&appointment.Patient.Id, &appointment.Patient.FirstName, &appointment.Patient.LastName, &appointment.Patient.Address, &appointment.Patient.DNI, &appointment.Patient.ReleaseDate,
+		&appointment.Id, &appointment.StartsAt, &appointment.Description, &planStepID, &completedAt, &checkedInAt,
+		&appointment.Patient.Id, &appointment.Patient.FirstName, &appointment.Patient.LastName, &appointment.Patient.Address, &appointment.Patient.DNI, &appointment.Patient.ReleaseDate, &appointment.Patient.Email, &appointment.Patient.Phone,
 		&appointment.Dentist.Id, &appointment.Dentist.FirstName, &appointment.Dentist.LastName, &appointment.Dentist.License,
+		&treatmentID, &treatmentName, &specialtyID, &specialtyName,
+		&clinicID, &clinicName, &clinicAddress, &clinicTimeZone,
 	)
 	if err != nil {
 		return domain.Appointment{}, err
 	}
+	appointment.Treatment.Id = int(treatmentID.Int64)
+	appointment.Treatment.Name = treatmentName.String
+	appointment.Treatment.Specialty.Id = int(specialtyID.Int64)
+	appointment.Treatment.Specialty.Name = specialtyName.String
+	appointment.Clinic.Id = int(clinicID.Int64)
+	appointment.Clinic.Name = clinicName.String
+	appointment.Clinic.Address = clinicAddress.String
+	appointment.Clinic.TimeZone = clinicTimeZone.String
+	appointment.PlanStepId = int(planStepID.Int64)
+	if completedAt.Valid {
+		completed := completedAt.Time.UTC()
+		appointment.CompletedAt = &completed
+	}
+	if checkedInAt.Valid {
+		checkedIn := checkedInAt.Time.UTC()
+		appointment.CheckedInAt = &checkedIn
+	}
+
+	// Appointments are stored in UTC and rendered in the clinic time zone.
+	loc, err := appointment.Clinic.Location()
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	appointment.StartsAt = appointment.StartsAt.UTC()
+	appointment.Localize(loc)
 	return appointment, nil
 }
 
-func (s *sqlAppointmentStore) ReadByPatientDNI(patientDNI string) ([]domain.Appointment, error) {
+func (s *sqlAppointmentStore) queryAppointments(query string, args ...any) ([]domain.Appointment, error) {
 	var appointments []domain.Appointment
-	query := `
-	SELECT 
-		a.Id, a.Date, a.Hour, a.Description,
-		p.Id AS patient_id, p.FirstName AS patient_first_name, p.LastName AS patient_last_name, p.Address AS patient_address, p.DNI AS patient_dni, p.ReleaseDate AS patient_release_date,
-		d.Id AS dentist_id, d.FirstName AS dentist_first_name, d.LastName AS dentist_last_name, d.License AS dentist_license
-	FROM 
-		appointments AS a
-	INNER JOIN 
-		patients AS p ON a.patients_Id = p.Id
-	INNER JOIN 
-		dentists AS d ON a.dentists_Id = d.Id
-	WHERE 
-		p.DNI = ?;
-	`
-	rows, err := s.db.Query(query, patientDNI)
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var appointment domain.Appointment
-		err := rows.Scan(
-			&appointment.Id, &appointment.Date, &appointment.Hour, &appointment.Description,
-			&appointment.Patient.Id, &appointment.Patient.FirstName, &appointment.Patient.LastName, &appointment.Patient.Address, &appointment.Patient.DNI, &appointment.Patient.ReleaseDate,
-			&appointment.Dentist.Id, &appointment.Dentist.FirstName, &appointment.Dentist.LastName, &appointment.Dentist.License,
-		)
+		appointment, err := scanAppointment(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -85,35 +110,230 @@ func (s *sqlAppointmentStore) ReadByPatientDNI(patientDNI string) ([]domain.Appo
 		return nil, err
 	}
 
+	if err := s.attachResources(appointments); err != nil {
+		return nil, err
+	}
+
 	return appointments, nil
 }
 
-func (s *sqlAppointmentStore) Create(appointment domain.Appointment) error {
+// attachResources loads the resources reserved by each of the appointments.
+func (s *sqlAppointmentStore) attachResources(appointments []domain.Appointment) error {
+	if len(appointments) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(appointments))
+	ids := make([]any, 0, len(appointments))
+	for i := range appointments {
+		appointments[i].Resources = []domain.Resource{}
+		index[appointments[i].Id] = i
+		ids = append(ids, appointments[i].Id)
+	}
+
 	query := `
-		INSERT INTO appointments (Date, Hour, Description, patients_Id, dentists_Id) 
-		VALUES (?, ?, ?, ?, ?);
+		SELECT ar.appointments_Id, r.Id, r.Name, r.Kind
+		FROM appointments_resources AS ar
+		INNER JOIN resources AS r ON ar.resources_Id = r.Id
+		WHERE ar.appointments_Id IN (?` + strings.Repeat(", ?", len(ids)-1) + `)
+		ORDER BY r.Kind, r.Name;
 	`
-	stmt, err := s.db.Prepare(query)
+	rows, err := s.db.Query(query, ids...)
 	if err != nil {
 		return err
 	}
-	res, err := stmt.Exec(appointment.Date, appointment.Hour, appointment.Description, appointment.Patient.Id, appointment.Dentist.Id)
+	defer rows.Close()
+
+	for rows.Next() {
+		var appointmentID int
+		var resource domain.Resource
+		if err := rows.Scan(&appointmentID, &resource.Id, &resource.Name, &resource.Kind); err != nil {
+			return err
+		}
+		i := index[appointmentID]
+		appointments[i].Resources = append(appointments[i].Resources, resource)
+	}
+
+	return rows.Err()
+}
+
+// saveResources replaces the resources reserved by an appointment.
+func saveResources(tx *sql.Tx, appointmentID int, resources []domain.Resource) error {
+	_, err := tx.Exec("DELETE FROM appointments_resources WHERE appointments_Id = ?;", appointmentID)
 	if err != nil {
 		return err
 	}
-	_, err = res.RowsAffected()
+	for _, resource := range resources {
+		_, err := tx.Exec("INSERT INTO appointments_resources (appointments_Id, resources_Id) VALUES (?, ?);", appointmentID, resource.Id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPatient makes sure the patient belongs to the tenant booking the appointment.
+func checkPatient(tx *sql.Tx, tenantID int, patientID int) error {
+	var id int
+	err := tx.QueryRow("SELECT Id FROM patients WHERE tenants_Id = ? AND Id = ?;", tenantID, patientID).Scan(&id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("Patient not found")
+		}
 		return err
 	}
 	return nil
 }
 
-func (s *sqlAppointmentStore) CreateByPatientDNIAndDentistLicense(patientDNI string, license string, date string, hour string, description string) ([]domain.Appointment, error) {
-	var appointments []domain.Appointment
+// nullableID maps the zero id used by the domain to a NULL foreign key.
+func nullableID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
 
-	var patientID int
-	patientQuery := "SELECT Id FROM patients WHERE DNI = ?"
-	err := s.db.QueryRow(patientQuery, patientDNI).Scan(&patientID)
+func (s *sqlAppointmentStore) Read(tenantID int, id int) (domain.Appointment, error) {
+	query := selectAppointments + `
+		WHERE 
+			a.tenants_Id = ? AND a.Id = ?;
+	`
+	row := s.db.QueryRow(query, tenantID, id)
+	appointment, err := scanAppointment(row)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	appointments := []domain.Appointment{appointment}
+	if err := s.attachResources(appointments); err != nil {
+		return domain.Appointment{}, err
+	}
+	return appointments[0], nil
+}
+
+func (s *sqlAppointmentStore) ReadByPatientDNI(tenantID int, patientDNI string) ([]domain.Appointment, error) {
+	query := selectAppointments + `
+	WHERE 
+		a.tenants_Id = ? AND p.DNI = ?;
+	`
+	return s.queryAppointments(query, tenantID, patientDNI)
+}
+
+func (s *sqlAppointmentStore) Create(tenantID int, appointment domain.Appointment) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := insertAppointment(tx, tenantID, appointment); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreateWithPatient creates the patient and books the appointment for them in
+// one transaction, so a booking that fails leaves no patient behind.
+func (s *sqlAppointmentStore) CreateWithPatient(tenantID int, patient domain.Patient, appointment domain.Appointment) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO patients (tenants_Id, FirstName, LastName, Address, DNI, ReleaseDate, Email, Phone) VALUES (?, ?, ?, ?, ?, ?, ?, ?);"
+	res, err := tx.Exec(query, tenantID, patient.FirstName, patient.LastName, patient.Address, patient.DNI, patient.ReleaseDate, patient.Email, patient.Phone)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	patient.Id = int(id)
+	if err := outbox.Record(tx, tenantID, "patient", patient.Id, domain.EventPatientCreated, patient); err != nil {
+		return err
+	}
+	appointment.Patient = patient
+	if _, err := insertAppointment(tx, tenantID, appointment); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertAppointment stores the appointment and its reserved resources,
+// returning the new appointment id.
+func insertAppointment(tx *sql.Tx, tenantID int, appointment domain.Appointment) (int, error) {
+	if err := checkPatient(tx, tenantID, appointment.Patient.Id); err != nil {
+		return 0, err
+	}
+	query := `
+		INSERT INTO appointments (tenants_Id, StartsAt, Description, patients_Id, dentists_Id, treatments_Id, clinics_Id, plan_steps_Id) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	res, err := tx.Exec(query, tenantID, appointment.StartsAt.UTC(), appointment.Description, appointment.Patient.Id, appointment.Dentist.Id, nullableID(appointment.Treatment.Id), nullableID(appointment.Clinic.Id), nullableID(appointment.PlanStepId))
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if err := saveResources(tx, int(id), appointment.Resources); err != nil {
+		return 0, err
+	}
+	appointment.Id = int(id)
+	if err := prepareConsents(tx, appointment.Id); err != nil {
+		return 0, err
+	}
+	if err := outbox.RecordAppointment(tx, tenantID, domain.EventAppointmentCreated, domain.NewAppointmentEvent(appointment)); err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// prepareConsents adds the pending consents the treatment of an appointment
+// requires and it does not have yet, copying the text of their templates.
+// Completed appointments get none.
+func prepareConsents(tx *sql.Tx, appointmentID int) error {
+	query := `
+		INSERT INTO consents (tenants_Id, appointments_Id, patients_Id, consent_templates_Id, Title, Body, Status, CreatedAt)
+		SELECT a.tenants_Id, a.Id, a.patients_Id, t.Id, t.Title, t.Body, ?, ?
+		FROM appointments AS a
+		INNER JOIN consent_template_treatments AS tt ON tt.treatments_Id = a.treatments_Id
+		INNER JOIN consent_templates AS t ON t.Id = tt.consent_templates_Id AND t.tenants_Id = a.tenants_Id AND t.Active = 1
+		WHERE a.Id = ? AND a.CompletedAt IS NULL
+			AND NOT EXISTS (SELECT 1 FROM consents AS c WHERE c.appointments_Id = a.Id AND c.consent_templates_Id = t.Id);
+	`
+	_, err := tx.Exec(query, domain.ConsentPending, time.Now().UTC().Truncate(time.Second), appointmentID)
+	return err
+}
+
+// lockAppointment reads the stored state of an appointment and locks its row
+// until the transaction ends.
+func lockAppointment(tx *sql.Tx, tenantID int, id int) (domain.AppointmentEvent, error) {
+	var event domain.AppointmentEvent
+	var clinicID, treatmentID sql.NullInt64
+	query := `
+		SELECT Id, patients_Id, dentists_Id, clinics_Id, treatments_Id, StartsAt, Description
+		FROM appointments
+		WHERE tenants_Id = ? AND Id = ?
+		FOR UPDATE;
+	`
+	err := tx.QueryRow(query, tenantID, id).Scan(&event.AppointmentId, &event.PatientId, &event.DentistId, &clinicID, &treatmentID, &event.StartsAt, &event.Description)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.AppointmentEvent{}, errors.New("Appointment not found")
+		}
+		return domain.AppointmentEvent{}, err
+	}
+	event.ClinicId = int(clinicID.Int64)
+	event.TreatmentId = int(treatmentID.Int64)
+	event.StartsAt = event.StartsAt.UTC()
+	return event, nil
+}
+
+func (s *sqlAppointmentStore) CreateByPatientDNIAndDentistLicense(tenantID int, patientDNI string, license string, appointment domain.Appointment) ([]domain.Appointment, error) {
+	patientQuery := "SELECT Id FROM patients WHERE tenants_Id = ? AND DNI = ?"
+	err := s.db.QueryRow(patientQuery, tenantID, patientDNI).Scan(&appointment.Patient.Id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("Patient not found")
@@ -121,9 +341,8 @@ func (s *sqlAppointmentStore) CreateByPatientDNIAndDentistLicense(patientDNI str
 		return nil, err
 	}
 
-	var dentistID int
-	dentistQuery := "SELECT Id FROM dentists WHERE License = ?"
-	err = s.db.QueryRow(dentistQuery, license).Scan(&dentistID)
+	dentistQuery := "SELECT Id FROM dentists WHERE tenants_Id = ? AND License = ?"
+	err = s.db.QueryRow(dentistQuery, tenantID, license).Scan(&appointment.Dentist.Id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("Dentist not found")
@@ -131,59 +350,149 @@ func (s *sqlAppointmentStore) CreateByPatientDNIAndDentistLicense(patientDNI str
 		return nil, err
 	}
 
-	createQuery := "INSERT INTO appointments (patients_Id, dentists_Id, Date, Hour, Description) VALUES (?, ?, ?, ?, ?)"
-	_, err = s.db.Exec(createQuery, patientID, dentistID, date, hour, description)
+	tx, err := s.db.Begin()
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
+
+	if _, err := insertAppointment(tx, tenantID, appointment); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
-	appointmentsQuery := `
-		SELECT 
-			a.Id, a.Date, a.Hour, a.Description,
-			p.Id AS patient_id, p.FirstName AS patient_first_name, p.LastName AS patient_last_name, p.Address AS patient_address, p.DNI AS patient_dni, p.ReleaseDate AS patient_release_date,
-			d.Id AS dentist_id, d.FirstName AS dentist_first_name, d.LastName AS dentist_last_name, d.License AS dentist_license
-		FROM 
-			appointments AS a
-		INNER JOIN 
-			patients AS p ON a.patients_Id = p.Id
-		INNER JOIN 
-			dentists AS d ON a.dentists_Id = d.Id
+	appointmentsQuery := selectAppointments + `
 		WHERE 
-			a.patients_Id = ? AND a.dentists_Id = ?
+			a.tenants_Id = ? AND a.patients_Id = ? AND a.dentists_Id = ?
 	`
-	rows, err := s.db.Query(appointmentsQuery, patientID, dentistID)
+	return s.queryAppointments(appointmentsQuery, tenantID, appointment.Patient.Id, appointment.Dentist.Id)
+}
+
+func (s *sqlAppointmentStore) Update(tenantID int, appointment domain.Appointment) error {
+	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
+	defer tx.Rollback()
 
-	for rows.Next() {
-		var appointment domain.Appointment
-		err := rows.Scan(
-			&appointment.Id, &appointment.Date, &appointment.Hour, &appointment.Description,
-			&appointment.Patient.Id, &appointment.Patient.FirstName, &appointment.Patient.LastName, &appointment.Patient.Address, &appointment.Patient.DNI, &appointment.Patient.ReleaseDate,
-			&appointment.Dentist.Id, &appointment.Dentist.FirstName, &appointment.Dentist.LastName, &appointment.Dentist.License,
-		)
+	previous, err := lockAppointment(tx, tenantID, appointment.Id)
+	if err != nil {
+		return err
+	}
+	if err := checkPatient(tx, tenantID, appointment.Patient.Id); err != nil {
+		return err
+	}
+	rescheduled := !previous.StartsAt.Equal(appointment.StartsAt)
+	if rescheduled {
+		// A rescheduled appointment gets its reminders again.
+		_, err = tx.Exec("DELETE FROM appointment_reminders WHERE tenants_Id = ? AND appointments_Id = ?;", tenantID, appointment.Id)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		appointments = append(appointments, appointment)
+	}
+	query := `
+		UPDATE appointments 
+		SET StartsAt = ?, Description = ?, patients_Id = ?, dentists_Id = ?, treatments_Id = ?, clinics_Id = ?
+		WHERE tenants_Id = ? AND Id = ?;
+	`
+	_, err = tx.Exec(query, appointment.StartsAt.UTC(), appointment.Description, appointment.Patient.Id, appointment.Dentist.Id, nullableID(appointment.Treatment.Id), nullableID(appointment.Clinic.Id), tenantID, appointment.Id)
+	if err != nil {
+		return err
+	}
+	if err := saveResources(tx, appointment.Id, appointment.Resources); err != nil {
+		return err
+	}
+	if err := prepareConsents(tx, appointment.Id); err != nil {
+		return err
 	}
 
-	return appointments, nil
+	event := domain.NewAppointmentEvent(appointment)
+	eventType := domain.EventAppointmentUpdated
+	if rescheduled {
+		eventType = domain.EventAppointmentRescheduled
+		event.PreviousStartsAt = &previous.StartsAt
+	}
+	if err := outbox.RecordAppointment(tx, tenantID, eventType, event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// visitRecords are the records that keep an appointment from being
+// cancelled, with the name they are reported by.
+var visitRecords = []struct{ table, name string }{
+	{"clinical_notes", "clinical notes"},
+	{"tooth_findings", "odontogram findings"},
+	{"attachments", "attachments"},
+	{"consents", "signed consents"},
+	{"invoice_lines", "invoices"},
+	{"prescriptions", "prescriptions"},
 }
 
-func (s *sqlAppointmentStore) Update(appointment domain.Appointment) error {
+// Delete cancels the appointment, removing it and recording the
+// cancellation. Appointments with records of the visit are kept and
+// ErrAppointmentHasRecords is returned; consents still to be signed are
+// removed with the appointment.
+func (s *sqlAppointmentStore) Delete(tenantID int, id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	event, err := lockAppointment(tx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	var found []string
+	for _, record := range visitRecords {
+		query := "SELECT EXISTS (SELECT 1 FROM " + record.table + " WHERE appointments_Id = ?"
+		if record.table == "consents" {
+			query += " AND Status <> '" + domain.ConsentPending + "'"
+		}
+		var exists bool
+		if err := tx.QueryRow(query+");", id).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			found = append(found, record.name)
+		}
+	}
+	if len(found) > 0 {
+		return fmt.Errorf("%w: %s", domain.ErrAppointmentHasRecords, strings.Join(found, ", "))
+	}
+	if _, err := tx.Exec("DELETE FROM consents WHERE tenants_Id = ? AND appointments_Id = ?;", tenantID, id); err != nil {
+		return err
+	}
 	query := `
-		UPDATE appointments 
-		SET Date = ?, Hour = ?, Description = ?, patients_Id = ?, dentists_Id = ?
-		WHERE Id = ?;
+		DELETE FROM appointments 
+		WHERE tenants_Id = ? AND Id = ?;
 	`
-	stmt, err := s.db.Prepare(query)
+	if _, err := tx.Exec(query, tenantID, id); err != nil {
+		return err
+	}
+	if err := outbox.RecordAppointment(tx, tenantID, domain.EventAppointmentCancelled, event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CheckIn records the arrival of the patient at the given time.
+func (s *sqlAppointmentStore) CheckIn(tenantID int, id int, at time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	event, err := lockAppointment(tx, tenantID, id)
 	if err != nil {
 		return err
 	}
-	res, err := stmt.Exec(appointment.Date, appointment.Hour, appointment.Description, appointment.Patient.Id, appointment.Dentist.Id, appointment.Id)
+	query := "UPDATE appointments SET CheckedInAt = ? WHERE tenants_Id = ? AND Id = ? AND CheckedInAt IS NULL;"
+	res, err := tx.Exec(query, at.UTC(), tenantID, id)
 	if err != nil {
 		return err
 	}
@@ -192,21 +501,28 @@ func (s *sqlAppointmentStore) Update(appointment domain.Appointment) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.New("Appointment not found")
+		return errors.New("Appointment already checked in")
 	}
-	return nil
+	if err := outbox.RecordAppointment(tx, tenantID, domain.EventAppointmentCheckedIn, event); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (s *sqlAppointmentStore) Delete(id int) error {
-	query := `
-		DELETE FROM appointments 
-		WHERE Id = ?;
-	`
-	stmt, err := s.db.Prepare(query)
+// Complete marks the appointment as completed at the given time.
+func (s *sqlAppointmentStore) Complete(tenantID int, id int, at time.Time) error {
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
-	res, err := stmt.Exec(id)
+	defer tx.Rollback()
+
+	event, err := lockAppointment(tx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	query := "UPDATE appointments SET CompletedAt = ? WHERE tenants_Id = ? AND Id = ? AND CompletedAt IS NULL;"
+	res, err := tx.Exec(query, at.UTC(), tenantID, id)
 	if err != nil {
 		return err
 	}
@@ -215,56 +531,77 @@ func (s *sqlAppointmentStore) Delete(id int) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.New("Appointment not found")
+		return errors.New("Appointment already completed")
 	}
-	return nil
+	if err := outbox.RecordAppointment(tx, tenantID, domain.EventAppointmentCompleted, event); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (s *sqlAppointmentStore) GetAll() ([]domain.Appointment, error) {
-	var appointments []domain.Appointment
+func (s *sqlAppointmentStore) GetAll(tenantID int) ([]domain.Appointment, error) {
+	return s.queryAppointments(selectAppointments+"WHERE a.tenants_Id = ?", tenantID)
+}
 
-	query := `
-	SELECT 
-		a.Id, a.Date, a.Hour, a.Description,
-		p.Id AS patient_id, p.FirstName AS patient_first_name, p.LastName AS patient_last_name, p.Address AS patient_address, p.DNI AS patient_dni, p.ReleaseDate AS patient_release_date,
-		d.Id AS dentist_id, d.FirstName AS dentist_first_name, d.LastName AS dentist_last_name, d.License AS dentist_license
-	FROM 
-		appointments AS a
-	INNER JOIN 
-		patients AS p ON a.patients_Id = p.Id
-	INNER JOIN 
-		dentists AS d ON a.dentists_Id = d.Id
-	`
+func (s *sqlAppointmentStore) Search(tenantID int, filter domain.AppointmentFilter) ([]domain.Appointment, error) {
+	query, args := searchQuery(tenantID, filter)
+	return s.queryAppointments(query, args...)
+}
 
-	rows, err := s.db.Query(query)
+// Each calls fn with the appointments matching the filter as they are read
+// from the database, without loading them all in memory. Their resources are
+// not loaded.
+func (s *sqlAppointmentStore) Each(tenantID int, filter domain.AppointmentFilter, fn func(domain.Appointment) error) error {
+	query, args := searchQuery(tenantID, filter)
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var appointment domain.Appointment
-		if err := rows.Scan(
-			&appointment.Id, &appointment.Date, &appointment.Hour, &appointment.Description,
-			&appointment.Patient.Id, &appointment.Patient.FirstName, &appointment.Patient.LastName, &appointment.Patient.Address, &appointment.Patient.DNI, &appointment.Patient.ReleaseDate,
-			&appointment.Dentist.Id, &appointment.Dentist.FirstName, &appointment.Dentist.LastName, &appointment.Dentist.License,
-		); err != nil {
-			return nil, err
+		appointment, err := scanAppointment(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(appointment); err != nil {
+			return err
 		}
-		appointments = append(appointments, appointment)
 	}
+	return rows.Err()
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
+func searchQuery(tenantID int, filter domain.AppointmentFilter) (string, []any) {
+	query := selectAppointments + "WHERE a.tenants_Id = ?"
+	args := []any{tenantID}
+	if filter.ClinicId != 0 {
+		query += " AND a.clinics_Id = ?"
+		args = append(args, filter.ClinicId)
 	}
-
-	return appointments, nil
+	if filter.DentistId != 0 {
+		query += " AND a.dentists_Id = ?"
+		args = append(args, filter.DentistId)
+	}
+	if filter.PatientId != 0 {
+		query += " AND a.patients_Id = ?"
+		args = append(args, filter.PatientId)
+	}
+	if !filter.From.IsZero() {
+		query += " AND a.StartsAt >= ?"
+		args = append(args, filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query += " AND a.StartsAt < ?"
+		args = append(args, filter.To.UTC())
+	}
+	query += " ORDER BY a.StartsAt"
+	return query, args
 }
 
-func (s *sqlAppointmentStore) Exists(id int) (bool, error) {
+func (s *sqlAppointmentStore) Exists(tenantID int, id int) (bool, error) {
 	var appointmentId int
-	query := "SELECT Id FROM appointments WHERE Id = ?;"
-	row := s.db.QueryRow(query, appointmentId)
+	query := "SELECT Id FROM appointments WHERE tenants_Id = ? AND Id = ?;"
+	row := s.db.QueryRow(query, tenantID, appointmentId)
 	err := row.Scan(&appointmentId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -275,22 +612,24 @@ func (s *sqlAppointmentStore) Exists(id int) (bool, error) {
 	return appointmentId > 0, nil
 }
 
-func (s *sqlAppointmentStore) PatchDescription(id int, description string) error {
-	query := "UPDATE appointments SET Description = ? WHERE Id = ?;"
-	stmt, err := s.db.Prepare(query)
+func (s *sqlAppointmentStore) PatchDescription(tenantID int, id int, description string) error {
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
-	res, err := stmt.Exec(description, id)
+	defer tx.Rollback()
+
+	event, err := lockAppointment(tx, tenantID, id)
 	if err != nil {
 		return err
 	}
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
+	query := "UPDATE appointments SET Description = ? WHERE tenants_Id = ? AND Id = ?;"
+	if _, err := tx.Exec(query, description, tenantID, id); err != nil {
 		return err
 	}
-	if rowsAffected == 0 {
-		return errors.New("Appointment not found")
+	event.Description = description
+	if err := outbox.RecordAppointment(tx, tenantID, domain.EventAppointmentUpdated, event); err != nil {
+		return err
 	}
-	return nil
+	return tx.Commit()
 }
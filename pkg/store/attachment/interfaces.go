@@ -0,0 +1,11 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type AttachmentStoreInterface interface {
+	Read(tenantID int, id int) (domain.Attachment, error)
+	ReadByPatient(tenantID int, patientID int) ([]domain.Attachment, error)
+	ReadByAppointment(tenantID int, appointmentID int) ([]domain.Attachment, error)
+	Create(tenantID int, attachment domain.Attachment) (int, error)
+	Delete(tenantID int, id int) error
+}
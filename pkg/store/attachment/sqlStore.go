@@ -0,0 +1,115 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) AttachmentStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectAttachments = `
+	SELECT Id, patients_Id, appointments_Id, Kind, FileName, ContentType, Size, Checksum, Description, UploadedAt, StorageKey
+	FROM attachments
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAttachment(row scanner) (domain.Attachment, error) {
+	var attachment domain.Attachment
+	var appointmentID sql.NullInt64
+	err := row.Scan(&attachment.Id, &attachment.PatientId, &appointmentID, &attachment.Kind, &attachment.FileName, &attachment.ContentType,
+		&attachment.Size, &attachment.Checksum, &attachment.Description, &attachment.UploadedAt, &attachment.StorageKey)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	attachment.AppointmentId = int(appointmentID.Int64)
+	attachment.UploadedAt = attachment.UploadedAt.UTC()
+	return attachment, nil
+}
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.Attachment, error) {
+	row := s.db.QueryRow(selectAttachments+"WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	return scanAttachment(row)
+}
+
+// ReadByPatient lists every attachment of a patient, including those of its
+// appointments, the most recent first.
+func (s *sqlStore) ReadByPatient(tenantID int, patientID int) ([]domain.Attachment, error) {
+	return s.queryAttachments(selectAttachments+"WHERE tenants_Id = ? AND patients_Id = ? ORDER BY UploadedAt DESC, Id DESC;", tenantID, patientID)
+}
+
+func (s *sqlStore) ReadByAppointment(tenantID int, appointmentID int) ([]domain.Attachment, error) {
+	return s.queryAttachments(selectAttachments+"WHERE tenants_Id = ? AND appointments_Id = ? ORDER BY UploadedAt DESC, Id DESC;", tenantID, appointmentID)
+}
+
+func (s *sqlStore) queryAttachments(query string, args ...any) ([]domain.Attachment, error) {
+	attachments := []domain.Attachment{}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (s *sqlStore) Create(tenantID int, attachment domain.Attachment) (int, error) {
+	var appointmentID any
+	if attachment.AppointmentId != 0 {
+		appointmentID = attachment.AppointmentId
+	}
+	query := `
+		INSERT INTO attachments (tenants_Id, patients_Id, appointments_Id, Kind, FileName, ContentType, Size, Checksum, Description, UploadedAt, StorageKey)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	res, err := s.db.Exec(query, tenantID, attachment.PatientId, appointmentID, attachment.Kind, attachment.FileName, attachment.ContentType,
+		attachment.Size, attachment.Checksum, attachment.Description, attachment.UploadedAt.UTC(), attachment.StorageKey)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func (s *sqlStore) Delete(tenantID int, id int) error {
+	res, err := s.db.Exec("DELETE FROM attachments WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Attachment not found")
+	}
+	return nil
+}
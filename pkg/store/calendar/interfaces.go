@@ -0,0 +1,9 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type CalendarStoreInterface interface {
+	ReadByTokenHash(tokenHash string) (domain.CalendarToken, error)
+	Save(token domain.CalendarToken, tokenHash string) error
+	Delete(token domain.CalendarToken) error
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"database/sql"
+	"proyecto_final_go/internal/domain"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) CalendarStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+func (s *sqlStore) ReadByTokenHash(tokenHash string) (domain.CalendarToken, error) {
+	var token domain.CalendarToken
+	query := `
+		SELECT ct.tenants_Id, ct.OwnerType, ct.OwnerId
+		FROM calendar_tokens AS ct
+		INNER JOIN tenants AS t ON ct.tenants_Id = t.Id
+		WHERE ct.TokenHash = ? AND t.DisabledAt IS NULL;
+	`
+	row := s.db.QueryRow(query, tokenHash)
+	err := row.Scan(&token.TenantId, &token.OwnerType, &token.OwnerId)
+	if err != nil {
+		return domain.CalendarToken{}, err
+	}
+	return token, nil
+}
+
+// Save stores the token of a feed, replacing the previous one.
+func (s *sqlStore) Save(token domain.CalendarToken, tokenHash string) error {
+	query := `
+		INSERT INTO calendar_tokens (tenants_Id, OwnerType, OwnerId, TokenHash, CreatedAt)
+		VALUES (?, ?, ?, ?, UTC_TIMESTAMP())
+		ON DUPLICATE KEY UPDATE TokenHash = VALUES(TokenHash), CreatedAt = VALUES(CreatedAt);
+	`
+	_, err := s.db.Exec(query, token.TenantId, token.OwnerType, token.OwnerId, tokenHash)
+	return err
+}
+
+func (s *sqlStore) Delete(token domain.CalendarToken) error {
+	query := "DELETE FROM calendar_tokens WHERE tenants_Id = ? AND OwnerType = ? AND OwnerId = ?;"
+	_, err := s.db.Exec(query, token.TenantId, token.OwnerType, token.OwnerId)
+	return err
+}
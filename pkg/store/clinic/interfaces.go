@@ -0,0 +1,11 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type ClinicStoreInterface interface {
+	Read(tenantID int, id int) (domain.Clinic, error)
+	Create(tenantID int, clinic domain.Clinic) error
+	Update(tenantID int, clinic domain.Clinic) error
+	Delete(tenantID int, id int) error
+	GetAll(tenantID int) ([]domain.Clinic, error)
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) ClinicStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.Clinic, error) {
+	var clinic domain.Clinic
+	query := "SELECT Id, Name, Address, TimeZone FROM clinics WHERE tenants_Id = ? AND Id = ?;"
+	row := s.db.QueryRow(query, tenantID, id)
+	err := row.Scan(&clinic.Id, &clinic.Name, &clinic.Address, &clinic.TimeZone)
+	if err != nil {
+		return domain.Clinic{}, err
+	}
+	return clinic, nil
+}
+
+func (s *sqlStore) Create(tenantID int, clinic domain.Clinic) error {
+	query := "INSERT INTO clinics (tenants_Id, Name, Address, TimeZone) VALUES (?, ?, ?, ?);"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(tenantID, clinic.Name, clinic.Address, clinic.TimeZone)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("no rows affected")
+	}
+	return nil
+}
+
+func (s *sqlStore) Update(tenantID int, clinic domain.Clinic) error {
+	query := "UPDATE clinics SET Name = ?, Address = ?, TimeZone = ? WHERE tenants_Id = ? AND Id = ?;"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	res, err := stmt.Exec(clinic.Name, clinic.Address, clinic.TimeZone, tenantID, clinic.Id)
+	if err != nil {
+		return err
+	}
+	_, err = res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *sqlStore) Delete(tenantID int, id int) error {
+	query := "DELETE FROM clinics WHERE tenants_Id = ? AND Id = ?;"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	res, err := stmt.Exec(tenantID, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Clinic not found")
+	}
+	return nil
+}
+
+func (s *sqlStore) GetAll(tenantID int) ([]domain.Clinic, error) {
+	var clinics []domain.Clinic
+	query := "SELECT Id, Name, Address, TimeZone FROM clinics WHERE tenants_Id = ? ORDER BY Name;"
+	rows, err := s.db.Query(query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var clinic domain.Clinic
+		if err := rows.Scan(&clinic.Id, &clinic.Name, &clinic.Address, &clinic.TimeZone); err != nil {
+			return nil, err
+		}
+		clinics = append(clinics, clinic)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return clinics, nil
+}
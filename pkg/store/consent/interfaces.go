@@ -0,0 +1,15 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type ConsentStoreInterface interface {
+	ReadTemplate(tenantID int, id int) (domain.ConsentTemplate, error)
+	ReadTemplates(tenantID int) ([]domain.ConsentTemplate, error)
+	ReadTemplatesByTreatment(tenantID int, treatmentID int) ([]domain.ConsentTemplate, error)
+	CreateTemplate(tenantID int, template domain.ConsentTemplate) (int, error)
+	UpdateTemplate(tenantID int, template domain.ConsentTemplate) error
+	Read(tenantID int, id int) (domain.Consent, error)
+	ReadByAppointment(tenantID int, appointmentID int) ([]domain.Consent, error)
+	Create(tenantID int, consent domain.Consent) (int, error)
+	Sign(tenantID int, consent domain.Consent) error
+}
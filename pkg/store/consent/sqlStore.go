@@ -0,0 +1,273 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"strings"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) ConsentStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectTemplates = `
+	SELECT ct.Id, ct.Title, ct.Body, ct.Active, ct.UpdatedAt
+	FROM consent_templates AS ct
+`
+
+const selectConsents = `
+	SELECT Id, appointments_Id, patients_Id, consent_templates_Id, Title, Body, Status, SignerName, SignerRelationship, SignedAt, DocumentHash, StorageKey, CreatedAt
+	FROM consents
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTemplate(row scanner) (domain.ConsentTemplate, error) {
+	var template domain.ConsentTemplate
+	err := row.Scan(&template.Id, &template.Title, &template.Body, &template.Active, &template.UpdatedAt)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	template.UpdatedAt = template.UpdatedAt.UTC()
+	template.Treatments = []domain.Treatment{}
+	return template, nil
+}
+
+func scanConsent(row scanner) (domain.Consent, error) {
+	var consent domain.Consent
+	var signedAt sql.NullTime
+	err := row.Scan(&consent.Id, &consent.AppointmentId, &consent.PatientId, &consent.TemplateId, &consent.Title, &consent.Body, &consent.Status,
+		&consent.SignerName, &consent.SignerRelationship, &signedAt, &consent.DocumentHash, &consent.StorageKey, &consent.CreatedAt)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	if signedAt.Valid {
+		signed := signedAt.Time.UTC()
+		consent.SignedAt = &signed
+	}
+	consent.CreatedAt = consent.CreatedAt.UTC()
+	return consent, nil
+}
+
+func (s *sqlStore) ReadTemplate(tenantID int, id int) (domain.ConsentTemplate, error) {
+	templates, err := s.queryTemplates(tenantID, selectTemplates+"WHERE ct.tenants_Id = ? AND ct.Id = ?;", tenantID, id)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	if len(templates) == 0 {
+		return domain.ConsentTemplate{}, sql.ErrNoRows
+	}
+	return templates[0], nil
+}
+
+func (s *sqlStore) ReadTemplates(tenantID int) ([]domain.ConsentTemplate, error) {
+	return s.queryTemplates(tenantID, selectTemplates+"WHERE ct.tenants_Id = ? ORDER BY ct.Title, ct.Id;", tenantID)
+}
+
+// ReadTemplatesByTreatment lists the active templates the treatment requires.
+func (s *sqlStore) ReadTemplatesByTreatment(tenantID int, treatmentID int) ([]domain.ConsentTemplate, error) {
+	query := selectTemplates + `
+		INNER JOIN consent_template_treatments AS ctt ON ctt.consent_templates_Id = ct.Id
+		WHERE ct.tenants_Id = ? AND ctt.treatments_Id = ? AND ct.Active = 1
+		ORDER BY ct.Title, ct.Id;
+	`
+	return s.queryTemplates(tenantID, query, tenantID, treatmentID)
+}
+
+func (s *sqlStore) queryTemplates(tenantID int, query string, args ...any) ([]domain.ConsentTemplate, error) {
+	templates := []domain.ConsentTemplate{}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachTreatments(tenantID, templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// attachTreatments fills the treatments each template is required for.
+func (s *sqlStore) attachTreatments(tenantID int, templates []domain.ConsentTemplate) error {
+	if len(templates) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(templates))
+	ids := []any{tenantID}
+	for i := range templates {
+		index[templates[i].Id] = i
+		ids = append(ids, templates[i].Id)
+	}
+
+	query := `
+		SELECT ctt.consent_templates_Id, t.Id, t.Name
+		FROM consent_template_treatments AS ctt
+		INNER JOIN treatments AS t ON ctt.treatments_Id = t.Id
+		WHERE t.tenants_Id = ? AND ctt.consent_templates_Id IN (?` + strings.Repeat(", ?", len(ids)-2) + `)
+		ORDER BY t.Name, t.Id;
+	`
+	rows, err := s.db.Query(query, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var templateID int
+		var treatment domain.Treatment
+		if err := rows.Scan(&templateID, &treatment.Id, &treatment.Name); err != nil {
+			return err
+		}
+		template := &templates[index[templateID]]
+		template.Treatments = append(template.Treatments, treatment)
+	}
+	return rows.Err()
+}
+
+func (s *sqlStore) CreateTemplate(tenantID int, template domain.ConsentTemplate) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO consent_templates (tenants_Id, Title, Body, Active, UpdatedAt) VALUES (?, ?, ?, ?, ?);"
+	res, err := tx.Exec(query, tenantID, template.Title, template.Body, template.Active, template.UpdatedAt.UTC())
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if err := insertTreatments(tx, int(id), template.Treatments); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// UpdateTemplate replaces the template and the treatments it is required for.
+func (s *sqlStore) UpdateTemplate(tenantID int, template domain.ConsentTemplate) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE consent_templates SET Title = ?, Body = ?, Active = ?, UpdatedAt = ? WHERE tenants_Id = ? AND Id = ?;"
+	if _, err := tx.Exec(query, template.Title, template.Body, template.Active, template.UpdatedAt.UTC(), tenantID, template.Id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM consent_template_treatments WHERE consent_templates_Id = ?;", template.Id); err != nil {
+		return err
+	}
+	if err := insertTreatments(tx, template.Id, template.Treatments); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertTreatments(tx *sql.Tx, templateID int, treatments []domain.Treatment) error {
+	query := "INSERT INTO consent_template_treatments (consent_templates_Id, treatments_Id) VALUES (?, ?);"
+	for _, treatment := range treatments {
+		if _, err := tx.Exec(query, templateID, treatment.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.Consent, error) {
+	row := s.db.QueryRow(selectConsents+"WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	return scanConsent(row)
+}
+
+func (s *sqlStore) ReadByAppointment(tenantID int, appointmentID int) ([]domain.Consent, error) {
+	consents := []domain.Consent{}
+	rows, err := s.db.Query(selectConsents+"WHERE tenants_Id = ? AND appointments_Id = ? ORDER BY Id;", tenantID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		consent, err := scanConsent(rows)
+		if err != nil {
+			return nil, err
+		}
+		consents = append(consents, consent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return consents, nil
+}
+
+func (s *sqlStore) Create(tenantID int, consent domain.Consent) (int, error) {
+	query := `
+		INSERT INTO consents (tenants_Id, appointments_Id, patients_Id, consent_templates_Id, Title, Body, Status, CreatedAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	res, err := s.db.Exec(query, tenantID, consent.AppointmentId, consent.PatientId, consent.TemplateId, consent.Title, consent.Body, consent.Status, consent.CreatedAt.UTC())
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// Sign records the signature of a pending consent. Signed consents are never
+// changed.
+func (s *sqlStore) Sign(tenantID int, consent domain.Consent) error {
+	query := `
+		UPDATE consents
+		SET Status = ?, SignerName = ?, SignerRelationship = ?, SignedAt = ?, DocumentHash = ?, StorageKey = ?
+		WHERE tenants_Id = ? AND Id = ? AND Status = ?;
+	`
+	res, err := s.db.Exec(query, domain.ConsentSigned, consent.SignerName, consent.SignerRelationship, consent.SignedAt.UTC(), consent.DocumentHash, consent.StorageKey,
+		tenantID, consent.Id, domain.ConsentPending)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Consent already signed")
+	}
+	return nil
+}
@@ -3,11 +3,15 @@ package store
 import "proyecto_final_go/internal/domain"
 
 type DentistStoreInterface interface {
-	Read(id int) (domain.Dentist, error)
-	Create(product domain.Dentist) error
-	Update(product domain.Dentist) error
-	Delete(id int) error
-	GetAll() ([]domain.Dentist, error)
-	Exists(license string) (bool, error)
-	PatchLicense(id int, license string) error
+	Read(tenantID int, id int) (domain.Dentist, error)
+	ReadByLicense(tenantID int, license string) (domain.Dentist, error)
+	Create(tenantID int, product domain.Dentist) error
+	CreateBatch(tenantID int, dentists []domain.Dentist) error
+	Update(tenantID int, product domain.Dentist) error
+	Delete(tenantID int, id int) error
+	GetAll(tenantID int) ([]domain.Dentist, error)
+	Search(tenantID int, filter domain.DentistFilter) ([]domain.Dentist, error)
+	Each(tenantID int, filter domain.DentistFilter, fn func(domain.Dentist) error) error
+	Exists(tenantID int, license string) (bool, error)
+	PatchLicense(tenantID int, id int, license string) error
 }
@@ -4,7 +4,8 @@ import (
 	"database/sql"
 	"errors"
 	"proyecto_final_go/internal/domain"
-	"strconv"
+	"proyecto_final_go/pkg/outbox"
+	"strings"
 )
 
 type sqlStore struct {
@@ -19,75 +20,119 @@ func NewSqlStore(db *sql.DB) DentistStoreInterface {
 
 //-----------------------------------
 
-func (s *sqlStore) Read(id int) (domain.Dentist, error) {
+func (s *sqlStore) Read(tenantID int, id int) (domain.Dentist, error) {
 	var dentist domain.Dentist
-	query := "SELECT * FROM dentists WHERE Id = ?;"
-	row := s.db.QueryRow(query, id)
+	query := "SELECT Id, FirstName, LastName, License FROM dentists WHERE tenants_Id = ? AND Id = ?;"
+	row := s.db.QueryRow(query, tenantID, id)
 	err := row.Scan(&dentist.Id, &dentist.FirstName, &dentist.LastName, &dentist.License)
 	if err != nil {
 		return domain.Dentist{}, err
 	}
+	dentist.Specialties, err = s.readSpecialties(dentist.Id)
+	if err != nil {
+		return domain.Dentist{}, err
+	}
 	return dentist, nil
 }
 
-func (s *sqlStore) Create(dentist domain.Dentist) error {
-	query := "INSERT INTO dentists (FirstName, LastName, License) VALUES (?, ?, ?);"
-	stmt, err := s.db.Prepare(query)
+func (s *sqlStore) ReadByLicense(tenantID int, license string) (domain.Dentist, error) {
+	var dentist domain.Dentist
+	query := "SELECT Id, FirstName, LastName, License FROM dentists WHERE tenants_Id = ? AND License = ?;"
+	row := s.db.QueryRow(query, tenantID, license)
+	err := row.Scan(&dentist.Id, &dentist.FirstName, &dentist.LastName, &dentist.License)
 	if err != nil {
-		return err
+		return domain.Dentist{}, err
 	}
-	res, err := stmt.Exec(dentist.FirstName, dentist.LastName, dentist.License)
+	dentist.Specialties, err = s.readSpecialties(dentist.Id)
 	if err != nil {
-		return err
+		return domain.Dentist{}, err
 	}
-	rowsAffected, err := res.RowsAffected()
+	return dentist, nil
+}
+
+func (s *sqlStore) Create(tenantID int, dentist domain.Dentist) error {
+	return s.CreateBatch(tenantID, []domain.Dentist{dentist})
+}
+
+// CreateBatch inserts the dentists in a single transaction, either all of
+// them are created or none.
+func (s *sqlStore) CreateBatch(tenantID int, dentists []domain.Dentist) error {
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
-	if rowsAffected != 1 {
-		return errors.New("expected 1 row to be affected, but got " + strconv.FormatInt(rowsAffected, 10))
+	defer tx.Rollback()
+
+	query := "INSERT INTO dentists (tenants_Id, FirstName, LastName, License) VALUES (?, ?, ?, ?);"
+	for _, dentist := range dentists {
+		res, err := tx.Exec(query, tenantID, dentist.FirstName, dentist.LastName, dentist.License)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		dentist.Id = int(id)
+		if err := outbox.Record(tx, tenantID, "dentist", dentist.Id, domain.EventDentistCreated, dentist); err != nil {
+			return err
+		}
 	}
-	return nil
+	return tx.Commit()
 }
 
-func (s *sqlStore) Update(dentist domain.Dentist) error {
-	query := "UPDATE dentists SET FirstName = ?, LastName = ?, License = ? WHERE Id = ?;"
-	stmt, err := s.db.Prepare(query)
+func (s *sqlStore) Update(tenantID int, dentist domain.Dentist) error {
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
-	res, err := stmt.Exec(dentist.FirstName, dentist.LastName, dentist.License, dentist.Id)
+	defer tx.Rollback()
+
+	query := "UPDATE dentists SET FirstName = ?, LastName = ?, License = ? WHERE tenants_Id = ? AND Id = ?;"
+	res, err := tx.Exec(query, dentist.FirstName, dentist.LastName, dentist.License, tenantID, dentist.Id)
 	if err != nil {
 		return err
 	}
-	_, err = res.RowsAffected()
+	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
-	return nil
+	if rowsAffected > 0 {
+		if err := outbox.Record(tx, tenantID, "dentist", dentist.Id, domain.EventDentistUpdated, dentist); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-func (s *sqlStore) Delete(id int) error {
-	query := "DELETE FROM dentists WHERE Id = ?;"
-	stmt, err := s.db.Prepare(query)
+func (s *sqlStore) Delete(tenantID int, id int) error {
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
-	res, err := stmt.Exec(id)
+	defer tx.Rollback()
+
+	query := "DELETE FROM dentists WHERE tenants_Id = ? AND Id = ?;"
+	res, err := tx.Exec(query, tenantID, id)
 	if err != nil {
 		return err
 	}
-	_, err = res.RowsAffected()
+	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
-	return nil
+	if rowsAffected > 0 {
+		if err := outbox.Record(tx, tenantID, "dentist", id, domain.EventDentistDeleted, domain.Dentist{Id: id}); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-func (s *sqlStore) Exists(license string) (bool, error) {
+func (s *sqlStore) Exists(tenantID int, license string) (bool, error) {
 	var id int
-	query := "SELECT Id FROM dentists WHERE License = ?;"
-	row := s.db.QueryRow(query, license)
+	query := "SELECT Id FROM dentists WHERE tenants_Id = ? AND License = ?;"
+	row := s.db.QueryRow(query, tenantID, license)
 	err := row.Scan(&id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -98,27 +143,71 @@ func (s *sqlStore) Exists(license string) (bool, error) {
 	return id > 0, nil
 }
 
-func (s *sqlStore) PatchLicense(id int, license string) error {
-	query := "UPDATE dentists SET License = ? WHERE Id = ?;"
-	stmt, err := s.db.Prepare(query)
+func (s *sqlStore) PatchLicense(tenantID int, id int, license string) error {
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
-	res, err := stmt.Exec(license, id)
+	defer tx.Rollback()
+
+	query := "UPDATE dentists SET License = ? WHERE tenants_Id = ? AND Id = ?;"
+	res, err := tx.Exec(query, license, tenantID, id)
 	if err != nil {
 		return err
 	}
-	_, err = res.RowsAffected()
+	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
-	return nil
+	if rowsAffected > 0 {
+		var dentist domain.Dentist
+		query := "SELECT Id, FirstName, LastName, License FROM dentists WHERE tenants_Id = ? AND Id = ?;"
+		err := tx.QueryRow(query, tenantID, id).Scan(&dentist.Id, &dentist.FirstName, &dentist.LastName, &dentist.License)
+		if err != nil {
+			return err
+		}
+		if err := outbox.Record(tx, tenantID, "dentist", id, domain.EventDentistUpdated, dentist); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStore) GetAll(tenantID int) ([]domain.Dentist, error) {
+	var dentists []domain.Dentist
+	query := "SELECT Id, FirstName, LastName, License FROM dentists WHERE tenants_Id = ?;"
+	rows, err := s.db.Query(query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dentist domain.Dentist
+		if err := rows.Scan(&dentist.Id, &dentist.FirstName, &dentist.LastName, &dentist.License); err != nil {
+			return nil, err
+		}
+		dentists = append(dentists, dentist)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range dentists {
+		dentists[i].Specialties, err = s.readSpecialties(dentists[i].Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dentists, nil
 }
 
-func (s *sqlStore) GetAll() ([]domain.Dentist, error) {
+func (s *sqlStore) Search(tenantID int, filter domain.DentistFilter) ([]domain.Dentist, error) {
 	var dentists []domain.Dentist
-	query := "SELECT * FROM dentists;"
-	rows, err := s.db.Query(query)
+	query, args := searchQuery(tenantID, filter)
+	rows, err := s.db.Query(query+";", args...)
 	if err != nil {
 		return nil, err
 	}
@@ -136,5 +225,113 @@ func (s *sqlStore) GetAll() ([]domain.Dentist, error) {
 		return nil, err
 	}
 
+	for i := range dentists {
+		dentists[i].Specialties, err = s.readSpecialties(dentists[i].Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return dentists, nil
 }
+
+// Each calls fn with the dentists matching the filter as they are read from
+// the database, without loading them all in memory. The specialties are
+// joined in the same query, one row per specialty, and grouped by dentist so
+// no other query runs while the rows are read.
+func (s *sqlStore) Each(tenantID int, filter domain.DentistFilter, fn func(domain.Dentist) error) error {
+	matching, args := searchQuery(tenantID, filter)
+	query := `
+		SELECT d.Id, d.FirstName, d.LastName, d.License, sp.Id, sp.Name
+		FROM (` + matching + `) AS d
+		LEFT JOIN dentists_specialties AS ds ON ds.dentists_Id = d.Id
+		LEFT JOIN specialties AS sp ON ds.specialties_Id = sp.Id
+		ORDER BY d.Id, sp.Name;
+	`
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var dentist domain.Dentist
+	for rows.Next() {
+		var row domain.Dentist
+		var specialtyID sql.NullInt64
+		var specialtyName sql.NullString
+		if err := rows.Scan(&row.Id, &row.FirstName, &row.LastName, &row.License, &specialtyID, &specialtyName); err != nil {
+			return err
+		}
+		if row.Id != dentist.Id {
+			if dentist.Id != 0 {
+				if err := fn(dentist); err != nil {
+					return err
+				}
+			}
+			dentist = row
+			dentist.Specialties = []domain.Specialty{}
+		}
+		if specialtyID.Valid {
+			dentist.Specialties = append(dentist.Specialties, domain.Specialty{Id: int(specialtyID.Int64), Name: specialtyName.String})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if dentist.Id != 0 {
+		return fn(dentist)
+	}
+	return nil
+}
+
+// searchQuery selects the dentists matching the filter.
+func searchQuery(tenantID int, filter domain.DentistFilter) (string, []any) {
+	query := "SELECT DISTINCT d.Id, d.FirstName, d.LastName, d.License FROM dentists AS d"
+	conditions := []string{"d.tenants_Id = ?"}
+	args := []any{tenantID}
+	if filter.Specialty != "" {
+		query += `
+		INNER JOIN dentists_specialties AS ds ON ds.dentists_Id = d.Id
+		INNER JOIN specialties AS sp ON ds.specialties_Id = sp.Id`
+		conditions = append(conditions, "sp.Name = ?")
+		args = append(args, filter.Specialty)
+	}
+	if filter.ClinicId != 0 {
+		query += `
+		INNER JOIN dentist_schedules AS sc ON sc.dentists_Id = d.Id`
+		conditions = append(conditions, "sc.clinics_Id = ?")
+		args = append(args, filter.ClinicId)
+	}
+	query += " WHERE " + strings.Join(conditions, " AND ")
+	return query, args
+}
+
+func (s *sqlStore) readSpecialties(dentistID int) ([]domain.Specialty, error) {
+	specialties := []domain.Specialty{}
+	query := `
+		SELECT sp.Id, sp.Name
+		FROM specialties AS sp
+		INNER JOIN dentists_specialties AS ds ON ds.specialties_Id = sp.Id
+		WHERE ds.dentists_Id = ?
+		ORDER BY sp.Name;
+	`
+	rows, err := s.db.Query(query, dentistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var specialty domain.Specialty
+		if err := rows.Scan(&specialty.Id, &specialty.Name); err != nil {
+			return nil, err
+		}
+		specialties = append(specialties, specialty)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return specialties, nil
+}
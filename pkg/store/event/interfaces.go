@@ -0,0 +1,14 @@
+package store
+
+import (
+	"proyecto_final_go/internal/domain"
+	"time"
+)
+
+type EventStoreInterface interface {
+	LastID() (int64, error)
+	ReadAfter(afterID int64, limit int) ([]domain.Event, error)
+	ReadTenantAfter(tenantID int, afterID int64, aggregateType string, limit int) ([]domain.Event, error)
+	ReadByType(tenantID int, eventType string, since time.Time) ([]domain.Event, error)
+	CountByAggregate(tenantID int, aggregateType string, ids []int) (map[int]int, error)
+}
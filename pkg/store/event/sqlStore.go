@@ -0,0 +1,117 @@
+package store
+
+import (
+	"database/sql"
+	"proyecto_final_go/internal/domain"
+	"strings"
+	"time"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) EventStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectEvents = "SELECT Id, tenants_Id, AggregateType, AggregateId, Type, Payload, CreatedAt FROM outbox_events "
+
+// LastID returns the id of the newest event, 0 when there are none.
+func (s *sqlStore) LastID() (int64, error) {
+	var id sql.NullInt64
+	err := s.db.QueryRow("SELECT MAX(Id) FROM outbox_events;").Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id.Int64, nil
+}
+
+// ReadAfter returns the events of every tenant newer than afterID, oldest
+// first.
+func (s *sqlStore) ReadAfter(afterID int64, limit int) ([]domain.Event, error) {
+	query := selectEvents + "WHERE Id > ? ORDER BY Id LIMIT ?;"
+	return s.queryEvents(query, afterID, limit)
+}
+
+// ReadTenantAfter returns the events of a tenant newer than afterID, oldest
+// first, optionally only those of one aggregate type.
+func (s *sqlStore) ReadTenantAfter(tenantID int, afterID int64, aggregateType string, limit int) ([]domain.Event, error) {
+	query := selectEvents + "WHERE tenants_Id = ? AND Id > ?"
+	args := []any{tenantID, afterID}
+	if aggregateType != "" {
+		query += " AND AggregateType = ?"
+		args = append(args, aggregateType)
+	}
+	query += " ORDER BY Id LIMIT ?;"
+	args = append(args, limit)
+	return s.queryEvents(query, args...)
+}
+
+// ReadByType returns the events of a tenant of one type recorded since the
+// given instant, oldest first.
+func (s *sqlStore) ReadByType(tenantID int, eventType string, since time.Time) ([]domain.Event, error) {
+	query := selectEvents + "WHERE tenants_Id = ? AND Type = ? AND CreatedAt >= ? ORDER BY Id;"
+	return s.queryEvents(query, tenantID, eventType, since.UTC())
+}
+
+// CountByAggregate returns how many events were recorded for each of the
+// given entities.
+func (s *sqlStore) CountByAggregate(tenantID int, aggregateType string, ids []int) (map[int]int, error) {
+	counts := make(map[int]int)
+	if len(ids) == 0 {
+		return counts, nil
+	}
+	query := "SELECT AggregateId, COUNT(*) FROM outbox_events WHERE tenants_Id = ? AND AggregateType = ? AND AggregateId IN (?" +
+		strings.Repeat(", ?", len(ids)-1) + ") GROUP BY AggregateId;"
+	args := []any{tenantID, aggregateType}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		counts[id] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (s *sqlStore) queryEvents(query string, args ...any) ([]domain.Event, error) {
+	var events []domain.Event
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event domain.Event
+		if err := rows.Scan(&event.Id, &event.TenantId, &event.AggregateType, &event.AggregateId, &event.Type, &event.Payload, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
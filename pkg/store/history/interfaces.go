@@ -0,0 +1,11 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type HistoryStoreInterface interface {
+	Read(tenantID int, id int) (domain.HistoryEntry, error)
+	ReadByPatient(tenantID int, patientID int) ([]domain.HistoryEntry, error)
+	Create(tenantID int, entry domain.HistoryEntry) (int, error)
+	Update(tenantID int, entry domain.HistoryEntry) error
+	Delete(tenantID int, id int) error
+}
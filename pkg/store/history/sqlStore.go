@@ -0,0 +1,123 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) HistoryStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectHistory = `
+	SELECT Id, patients_Id, Kind, Name, Severity, Alert, Notes, StartDate, EndDate, RecordedBy, RecordedAt, UpdatedAt
+	FROM medical_history
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEntry(row scanner) (domain.HistoryEntry, error) {
+	var entry domain.HistoryEntry
+	err := row.Scan(&entry.Id, &entry.PatientId, &entry.Kind, &entry.Name, &entry.Severity, &entry.Alert, &entry.Notes,
+		&entry.StartDate, &entry.EndDate, &entry.RecordedBy, &entry.RecordedAt, &entry.UpdatedAt)
+	if err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	entry.RecordedAt = entry.RecordedAt.UTC()
+	entry.UpdatedAt = entry.UpdatedAt.UTC()
+	return entry, nil
+}
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.HistoryEntry, error) {
+	row := s.db.QueryRow(selectHistory+"WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	return scanEntry(row)
+}
+
+// ReadByPatient lists the entries of a patient by kind, the most recent
+// first.
+func (s *sqlStore) ReadByPatient(tenantID int, patientID int) ([]domain.HistoryEntry, error) {
+	entries := []domain.HistoryEntry{}
+	rows, err := s.db.Query(selectHistory+"WHERE tenants_Id = ? AND patients_Id = ? ORDER BY Kind, RecordedAt DESC;", tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *sqlStore) Create(tenantID int, entry domain.HistoryEntry) (int, error) {
+	query := `
+		INSERT INTO medical_history (tenants_Id, patients_Id, Kind, Name, Severity, Alert, Notes, StartDate, EndDate, RecordedBy, RecordedAt, UpdatedAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	res, err := s.db.Exec(query, tenantID, entry.PatientId, entry.Kind, entry.Name, entry.Severity, entry.Alert, entry.Notes,
+		entry.StartDate, entry.EndDate, entry.RecordedBy, entry.RecordedAt.UTC(), entry.UpdatedAt.UTC())
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func (s *sqlStore) Update(tenantID int, entry domain.HistoryEntry) error {
+	query := `
+		UPDATE medical_history
+		SET Kind = ?, Name = ?, Severity = ?, Alert = ?, Notes = ?, StartDate = ?, EndDate = ?, RecordedBy = ?, UpdatedAt = ?
+		WHERE tenants_Id = ? AND Id = ?;
+	`
+	res, err := s.db.Exec(query, entry.Kind, entry.Name, entry.Severity, entry.Alert, entry.Notes, entry.StartDate, entry.EndDate,
+		entry.RecordedBy, entry.UpdatedAt.UTC(), tenantID, entry.Id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("History entry not found")
+	}
+	return nil
+}
+
+func (s *sqlStore) Delete(tenantID int, id int) error {
+	res, err := s.db.Exec("DELETE FROM medical_history WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("History entry not found")
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type InsuranceStoreInterface interface {
+	ReadProvider(tenantID int, id int) (domain.InsuranceProvider, error)
+	ReadProviders(tenantID int) ([]domain.InsuranceProvider, error)
+	CreateProvider(tenantID int, provider domain.InsuranceProvider) (int, error)
+	UpdateProvider(tenantID int, provider domain.InsuranceProvider) error
+	ReadRule(tenantID int, id int) (domain.CoverageRule, error)
+	CreateRule(tenantID int, rule domain.CoverageRule) (int, error)
+	UpdateRule(tenantID int, rule domain.CoverageRule) error
+	DeleteRule(tenantID int, id int) error
+	ReadCoverage(tenantID int, id int) (domain.Coverage, error)
+	ReadCoverages(tenantID int, patientID int) ([]domain.Coverage, error)
+	CreateCoverage(tenantID int, coverage domain.Coverage) (int, error)
+	UpdateCoverage(tenantID int, coverage domain.Coverage) error
+	ReadClaim(tenantID int, id int) (domain.Claim, error)
+	ReadClaims(tenantID int, status string, providerID int) ([]domain.Claim, error)
+	ReadClaimsByInvoice(tenantID int, invoiceID int) ([]domain.Claim, error)
+	CreateClaim(tenantID int, claim domain.Claim) (int, error)
+	UpdateClaim(tenantID int, claim domain.Claim, from string) error
+}
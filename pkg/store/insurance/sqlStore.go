@@ -0,0 +1,397 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"strings"
+	"time"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) InsuranceStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectProviders = `
+	SELECT Id, Name, Code, Phone, Email, Active
+	FROM insurance_providers
+`
+
+const selectRules = `
+	SELECT Id, insurance_providers_Id, Plan, treatments_Id, Rate, MaxAmount
+	FROM coverage_rules
+`
+
+const selectCoverages = `
+	SELECT pc.Id, pc.patients_Id, pc.insurance_providers_Id, ip.Name, pc.Plan, pc.MemberNumber, pc.ValidFrom, pc.ValidTo
+	FROM patient_coverages AS pc
+	INNER JOIN insurance_providers AS ip ON pc.insurance_providers_Id = ip.Id
+`
+
+const selectClaims = `
+	SELECT Id, invoices_Id, patient_coverages_Id, insurance_providers_Id, patients_Id, Amount, Status, Reference, Reason, PaidAmount, SubmittedAt, ResolvedAt, PaidAt
+	FROM insurance_claims
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProvider(row scanner) (domain.InsuranceProvider, error) {
+	var provider domain.InsuranceProvider
+	err := row.Scan(&provider.Id, &provider.Name, &provider.Code, &provider.Phone, &provider.Email, &provider.Active)
+	if err != nil {
+		return domain.InsuranceProvider{}, err
+	}
+	provider.Rules = []domain.CoverageRule{}
+	return provider, nil
+}
+
+func scanRule(row scanner) (domain.CoverageRule, error) {
+	var rule domain.CoverageRule
+	err := row.Scan(&rule.Id, &rule.ProviderId, &rule.Plan, &rule.TreatmentId, &rule.Rate, &rule.MaxAmount)
+	if err != nil {
+		return domain.CoverageRule{}, err
+	}
+	return rule, nil
+}
+
+func scanCoverage(row scanner) (domain.Coverage, error) {
+	var coverage domain.Coverage
+	err := row.Scan(&coverage.Id, &coverage.PatientId, &coverage.ProviderId, &coverage.ProviderName, &coverage.Plan, &coverage.MemberNumber,
+		&coverage.ValidFrom, &coverage.ValidTo)
+	if err != nil {
+		return domain.Coverage{}, err
+	}
+	return coverage, nil
+}
+
+func scanClaim(row scanner) (domain.Claim, error) {
+	var claim domain.Claim
+	var resolvedAt, paidAt sql.NullTime
+	err := row.Scan(&claim.Id, &claim.InvoiceId, &claim.CoverageId, &claim.ProviderId, &claim.PatientId, &claim.Amount, &claim.Status,
+		&claim.Reference, &claim.Reason, &claim.PaidAmount, &claim.SubmittedAt, &resolvedAt, &paidAt)
+	if err != nil {
+		return domain.Claim{}, err
+	}
+	claim.SubmittedAt = claim.SubmittedAt.UTC()
+	claim.ResolvedAt = nullableTime(resolvedAt)
+	claim.PaidAt = nullableTime(paidAt)
+	return claim, nil
+}
+
+func nullableTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	utc := t.Time.UTC()
+	return &utc
+}
+
+func (s *sqlStore) ReadProvider(tenantID int, id int) (domain.InsuranceProvider, error) {
+	providers, err := s.queryProviders(tenantID, selectProviders+"WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	if err != nil {
+		return domain.InsuranceProvider{}, err
+	}
+	if len(providers) == 0 {
+		return domain.InsuranceProvider{}, sql.ErrNoRows
+	}
+	return providers[0], nil
+}
+
+func (s *sqlStore) ReadProviders(tenantID int) ([]domain.InsuranceProvider, error) {
+	return s.queryProviders(tenantID, selectProviders+"WHERE tenants_Id = ? ORDER BY Name, Id;", tenantID)
+}
+
+func (s *sqlStore) queryProviders(tenantID int, query string, args ...any) ([]domain.InsuranceProvider, error) {
+	providers := []domain.InsuranceProvider{}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		provider, err := scanProvider(rows)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachRules(tenantID, providers); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+// attachRules fills the coverage rules of each provider.
+func (s *sqlStore) attachRules(tenantID int, providers []domain.InsuranceProvider) error {
+	if len(providers) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(providers))
+	ids := []any{tenantID}
+	for i := range providers {
+		index[providers[i].Id] = i
+		ids = append(ids, providers[i].Id)
+	}
+
+	query := selectRules + "WHERE tenants_Id = ? AND insurance_providers_Id IN (?" + strings.Repeat(", ?", len(ids)-2) + ") ORDER BY Plan, treatments_Id, Id;"
+	rows, err := s.db.Query(query, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return err
+		}
+		provider := &providers[index[rule.ProviderId]]
+		provider.Rules = append(provider.Rules, rule)
+	}
+	return rows.Err()
+}
+
+func (s *sqlStore) CreateProvider(tenantID int, provider domain.InsuranceProvider) (int, error) {
+	query := "INSERT INTO insurance_providers (tenants_Id, Name, Code, Phone, Email, Active) VALUES (?, ?, ?, ?, ?, ?);"
+	res, err := s.db.Exec(query, tenantID, provider.Name, provider.Code, provider.Phone, provider.Email, provider.Active)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func (s *sqlStore) UpdateProvider(tenantID int, provider domain.InsuranceProvider) error {
+	query := "UPDATE insurance_providers SET Name = ?, Code = ?, Phone = ?, Email = ?, Active = ? WHERE tenants_Id = ? AND Id = ?;"
+	_, err := s.db.Exec(query, provider.Name, provider.Code, provider.Phone, provider.Email, provider.Active, tenantID, provider.Id)
+	return err
+}
+
+func (s *sqlStore) ReadRule(tenantID int, id int) (domain.CoverageRule, error) {
+	row := s.db.QueryRow(selectRules+"WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	return scanRule(row)
+}
+
+func (s *sqlStore) CreateRule(tenantID int, rule domain.CoverageRule) (int, error) {
+	query := "INSERT INTO coverage_rules (tenants_Id, insurance_providers_Id, Plan, treatments_Id, Rate, MaxAmount) VALUES (?, ?, ?, ?, ?, ?);"
+	res, err := s.db.Exec(query, tenantID, rule.ProviderId, rule.Plan, rule.TreatmentId, rule.Rate, rule.MaxAmount)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func (s *sqlStore) UpdateRule(tenantID int, rule domain.CoverageRule) error {
+	query := "UPDATE coverage_rules SET Plan = ?, treatments_Id = ?, Rate = ?, MaxAmount = ? WHERE tenants_Id = ? AND Id = ?;"
+	_, err := s.db.Exec(query, rule.Plan, rule.TreatmentId, rule.Rate, rule.MaxAmount, tenantID, rule.Id)
+	return err
+}
+
+func (s *sqlStore) DeleteRule(tenantID int, id int) error {
+	res, err := s.db.Exec("DELETE FROM coverage_rules WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *sqlStore) ReadCoverage(tenantID int, id int) (domain.Coverage, error) {
+	row := s.db.QueryRow(selectCoverages+"WHERE pc.tenants_Id = ? AND pc.Id = ?;", tenantID, id)
+	return scanCoverage(row)
+}
+
+// ReadCoverages lists the coverages of a patient in the order they were
+// added.
+func (s *sqlStore) ReadCoverages(tenantID int, patientID int) ([]domain.Coverage, error) {
+	coverages := []domain.Coverage{}
+	rows, err := s.db.Query(selectCoverages+"WHERE pc.tenants_Id = ? AND pc.patients_Id = ? ORDER BY pc.Id;", tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		coverage, err := scanCoverage(rows)
+		if err != nil {
+			return nil, err
+		}
+		coverages = append(coverages, coverage)
+	}
+	return coverages, rows.Err()
+}
+
+func (s *sqlStore) CreateCoverage(tenantID int, coverage domain.Coverage) (int, error) {
+	query := `
+		INSERT INTO patient_coverages (tenants_Id, patients_Id, insurance_providers_Id, Plan, MemberNumber, ValidFrom, ValidTo)
+		VALUES (?, ?, ?, ?, ?, ?, ?);
+	`
+	res, err := s.db.Exec(query, tenantID, coverage.PatientId, coverage.ProviderId, coverage.Plan, coverage.MemberNumber, coverage.ValidFrom, coverage.ValidTo)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func (s *sqlStore) UpdateCoverage(tenantID int, coverage domain.Coverage) error {
+	query := `
+		UPDATE patient_coverages SET insurance_providers_Id = ?, Plan = ?, MemberNumber = ?, ValidFrom = ?, ValidTo = ?
+		WHERE tenants_Id = ? AND Id = ?;
+	`
+	_, err := s.db.Exec(query, coverage.ProviderId, coverage.Plan, coverage.MemberNumber, coverage.ValidFrom, coverage.ValidTo, tenantID, coverage.Id)
+	return err
+}
+
+func (s *sqlStore) ReadClaim(tenantID int, id int) (domain.Claim, error) {
+	row := s.db.QueryRow(selectClaims+"WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	return scanClaim(row)
+}
+
+// ReadClaims lists the claims with the given status and provider, or all of
+// them when empty, the most recent first.
+func (s *sqlStore) ReadClaims(tenantID int, status string, providerID int) ([]domain.Claim, error) {
+	query := selectClaims + "WHERE tenants_Id = ?"
+	args := []any{tenantID}
+	if status != "" {
+		query += " AND Status = ?"
+		args = append(args, status)
+	}
+	if providerID != 0 {
+		query += " AND insurance_providers_Id = ?"
+		args = append(args, providerID)
+	}
+	return s.queryClaims(query+" ORDER BY SubmittedAt DESC, Id DESC;", args...)
+}
+
+// ReadClaimsByInvoice lists the claims of an invoice in the order they were
+// submitted.
+func (s *sqlStore) ReadClaimsByInvoice(tenantID int, invoiceID int) ([]domain.Claim, error) {
+	return s.queryClaims(selectClaims+"WHERE tenants_Id = ? AND invoices_Id = ? ORDER BY SubmittedAt, Id;", tenantID, invoiceID)
+}
+
+func (s *sqlStore) queryClaims(query string, args ...any) ([]domain.Claim, error) {
+	claims := []domain.Claim{}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		claim, err := scanClaim(rows)
+		if err != nil {
+			return nil, err
+		}
+		claims = append(claims, claim)
+	}
+	return claims, rows.Err()
+}
+
+// CreateClaim records the submission of a claim. The invoice is locked while
+// its other claims are checked, so it is never claimed twice unless the
+// previous claims were rejected.
+func (s *sqlStore) CreateClaim(tenantID int, claim domain.Claim) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow("SELECT Id FROM invoices WHERE tenants_Id = ? AND Id = ? FOR UPDATE;", tenantID, claim.InvoiceId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New("Invoice not found")
+		}
+		return 0, err
+	}
+	var open int
+	query := "SELECT COUNT(*) FROM insurance_claims WHERE tenants_Id = ? AND invoices_Id = ? AND Status <> ?;"
+	if err := tx.QueryRow(query, tenantID, claim.InvoiceId, domain.ClaimRejected).Scan(&open); err != nil {
+		return 0, err
+	}
+	if open > 0 {
+		return 0, errors.New("The invoice was already claimed")
+	}
+
+	query = `
+		INSERT INTO insurance_claims (tenants_Id, invoices_Id, patient_coverages_Id, insurance_providers_Id, patients_Id, Amount, Status, Reference, SubmittedAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	res, err := tx.Exec(query, tenantID, claim.InvoiceId, claim.CoverageId, claim.ProviderId, claim.PatientId, claim.Amount, claim.Status, claim.Reference,
+		claim.SubmittedAt.UTC())
+	if err != nil {
+		return 0, err
+	}
+	claimID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(claimID), nil
+}
+
+// UpdateClaim saves the claim if it is still in the status from, so two
+// answers of the provider are never recorded over each other.
+func (s *sqlStore) UpdateClaim(tenantID int, claim domain.Claim, from string) error {
+	query := `
+		UPDATE insurance_claims SET Status = ?, Reference = ?, Reason = ?, PaidAmount = ?, ResolvedAt = ?, PaidAt = ?
+		WHERE tenants_Id = ? AND Id = ? AND Status = ?;
+	`
+	res, err := s.db.Exec(query, claim.Status, claim.Reference, claim.Reason, claim.PaidAmount, nullableTimePtr(claim.ResolvedAt), nullableTimePtr(claim.PaidAt),
+		tenantID, claim.Id, from)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("The claim was changed meanwhile, try again")
+	}
+	return nil
+}
+
+// nullableTimePtr maps a missing time to NULL.
+func nullableTimePtr(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
@@ -0,0 +1,16 @@
+package store
+
+import (
+	"proyecto_final_go/internal/domain"
+	"time"
+)
+
+type InvoiceStoreInterface interface {
+	Read(tenantID int, id int) (domain.Invoice, error)
+	ReadByPatient(tenantID int, patientID int) ([]domain.Invoice, error)
+	ReadAll(tenantID int, status string) ([]domain.Invoice, error)
+	ReadBilledAppointments(tenantID int, patientID int) ([]int, error)
+	Create(tenantID int, invoice domain.Invoice) (int, error)
+	Issue(tenantID int, id int, at time.Time) error
+	Void(tenantID int, id int, at time.Time, reason string) error
+}
@@ -0,0 +1,326 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) InvoiceStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectInvoices = `
+	SELECT Id, Number, patients_Id, Status, Subtotal, Tax, Total, patient_coverages_Id, Covered, Notes, VoidReason, CreatedAt, IssuedAt, PaidAt, VoidedAt,
+		(SELECT COALESCE(SUM(CASE WHEN p.Kind = 'refund' THEN -p.Amount ELSE p.Amount END), 0) FROM payments AS p WHERE p.invoices_Id = invoices.Id AND p.Status = 'completed')
+	FROM invoices
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanInvoice(row scanner) (domain.Invoice, error) {
+	var invoice domain.Invoice
+	var number, coverageID sql.NullInt64
+	var issuedAt, paidAt, voidedAt sql.NullTime
+	var amountPaid int64
+	err := row.Scan(&invoice.Id, &number, &invoice.PatientId, &invoice.Status, &invoice.Subtotal, &invoice.Tax, &invoice.Total,
+		&coverageID, &invoice.Covered, &invoice.Notes, &invoice.VoidReason, &invoice.CreatedAt, &issuedAt, &paidAt, &voidedAt, &amountPaid)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	invoice.Settle(amountPaid)
+	invoice.Number = int(number.Int64)
+	invoice.CoverageId = int(coverageID.Int64)
+	invoice.CreatedAt = invoice.CreatedAt.UTC()
+	invoice.IssuedAt = nullableTime(issuedAt)
+	invoice.PaidAt = nullableTime(paidAt)
+	invoice.VoidedAt = nullableTime(voidedAt)
+	invoice.Lines = []domain.InvoiceLine{}
+	return invoice, nil
+}
+
+func nullableTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	utc := t.Time.UTC()
+	return &utc
+}
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.Invoice, error) {
+	invoices, err := s.queryInvoices(tenantID, selectInvoices+"WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	if len(invoices) == 0 {
+		return domain.Invoice{}, sql.ErrNoRows
+	}
+	return invoices[0], nil
+}
+
+// ReadByPatient lists the invoices of a patient, the most recent first.
+func (s *sqlStore) ReadByPatient(tenantID int, patientID int) ([]domain.Invoice, error) {
+	return s.queryInvoices(tenantID, selectInvoices+"WHERE tenants_Id = ? AND patients_Id = ? ORDER BY CreatedAt DESC, Id DESC;", tenantID, patientID)
+}
+
+// ReadAll lists the invoices with the given status, or all of them when
+// empty, the most recent first.
+func (s *sqlStore) ReadAll(tenantID int, status string) ([]domain.Invoice, error) {
+	query := selectInvoices + "WHERE tenants_Id = ?"
+	args := []any{tenantID}
+	if status != "" {
+		query += " AND Status = ?"
+		args = append(args, status)
+	}
+	return s.queryInvoices(tenantID, query+" ORDER BY CreatedAt DESC, Id DESC;", args...)
+}
+
+func (s *sqlStore) queryInvoices(tenantID int, query string, args ...any) ([]domain.Invoice, error) {
+	invoices := []domain.Invoice{}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachLines(tenantID, invoices); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (s *sqlStore) attachLines(tenantID int, invoices []domain.Invoice) error {
+	if len(invoices) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(invoices))
+	ids := []any{tenantID}
+	for i := range invoices {
+		index[invoices[i].Id] = i
+		ids = append(ids, invoices[i].Id)
+	}
+
+	query := `
+		SELECT Id, invoices_Id, appointments_Id, treatments_Id, Description, Quantity, UnitPrice, TaxRate, Subtotal, Tax, Total, Covered
+		FROM invoice_lines
+		WHERE tenants_Id = ? AND invoices_Id IN (?` + strings.Repeat(", ?", len(ids)-2) + `)
+		ORDER BY Id;
+	`
+	rows, err := s.db.Query(query, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line domain.InvoiceLine
+		var appointmentID, treatmentID sql.NullInt64
+		err := rows.Scan(&line.Id, &line.InvoiceId, &appointmentID, &treatmentID, &line.Description, &line.Quantity, &line.UnitPrice, &line.TaxRate,
+			&line.Subtotal, &line.Tax, &line.Total, &line.Covered)
+		if err != nil {
+			return err
+		}
+		line.AppointmentId = int(appointmentID.Int64)
+		line.TreatmentId = int(treatmentID.Int64)
+		invoice := &invoices[index[line.InvoiceId]]
+		invoice.Lines = append(invoice.Lines, line)
+	}
+	return rows.Err()
+}
+
+// ReadBilledAppointments lists the appointments of the patient billed in an
+// invoice that is not void.
+func (s *sqlStore) ReadBilledAppointments(tenantID int, patientID int) ([]int, error) {
+	query := `
+		SELECT DISTINCT l.appointments_Id
+		FROM invoice_lines AS l
+		INNER JOIN invoices AS i ON l.invoices_Id = i.Id
+		WHERE i.tenants_Id = ? AND i.patients_Id = ? AND i.Status <> ? AND l.appointments_Id IS NOT NULL;
+	`
+	rows, err := s.db.Query(query, tenantID, patientID, domain.InvoiceVoid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Create inserts the invoice and its lines in a single transaction. The
+// appointments billed are locked, so the same appointment is never billed
+// twice.
+func (s *sqlStore) Create(tenantID int, invoice domain.Invoice) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for _, line := range invoice.Lines {
+		if line.AppointmentId == 0 {
+			continue
+		}
+		if err := lockUnbilled(tx, tenantID, line.AppointmentId); err != nil {
+			return 0, err
+		}
+	}
+
+	query := `
+		INSERT INTO invoices (tenants_Id, patients_Id, Status, Subtotal, Tax, Total, patient_coverages_Id, Covered, Notes, CreatedAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	res, err := tx.Exec(query, tenantID, invoice.PatientId, invoice.Status, invoice.Subtotal, invoice.Tax, invoice.Total,
+		nullableID(invoice.CoverageId), invoice.Covered, invoice.Notes, invoice.CreatedAt.UTC())
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	query = `
+		INSERT INTO invoice_lines (tenants_Id, invoices_Id, appointments_Id, treatments_Id, Description, Quantity, UnitPrice, TaxRate, Subtotal, Tax, Total, Covered)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	for _, line := range invoice.Lines {
+		_, err := tx.Exec(query, tenantID, id, nullableID(line.AppointmentId), nullableID(line.TreatmentId), line.Description, line.Quantity, line.UnitPrice, line.TaxRate,
+			line.Subtotal, line.Tax, line.Total, line.Covered)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func lockUnbilled(tx *sql.Tx, tenantID int, appointmentID int) error {
+	var id int
+	err := tx.QueryRow("SELECT Id FROM appointments WHERE tenants_Id = ? AND Id = ? FOR UPDATE;", tenantID, appointmentID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("Appointment not found")
+		}
+		return err
+	}
+	query := `
+		SELECT COUNT(*)
+		FROM invoice_lines AS l
+		INNER JOIN invoices AS i ON l.invoices_Id = i.Id
+		WHERE l.tenants_Id = ? AND l.appointments_Id = ? AND i.Status <> ?;
+	`
+	var billed int
+	if err := tx.QueryRow(query, tenantID, appointmentID, domain.InvoiceVoid).Scan(&billed); err != nil {
+		return err
+	}
+	if billed > 0 {
+		return errors.New("Appointment " + strconv.Itoa(appointmentID) + " is already invoiced")
+	}
+	return nil
+}
+
+// Issue numbers a draft with the next number of the practice. Numbers are
+// taken inside the transaction, so they have no gaps. Invoices with nothing
+// for the patient to pay are paid as soon as they are issued.
+func (s *sqlStore) Issue(tenantID int, id int, at time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var owed int64
+	err = tx.QueryRow("SELECT Status, Total - Covered FROM invoices WHERE tenants_Id = ? AND Id = ? FOR UPDATE;", tenantID, id).Scan(&status, &owed)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("Invoice not found")
+		}
+		return err
+	}
+	if status != domain.InvoiceDraft {
+		return errors.New("Only draft invoices can be issued")
+	}
+	query := "INSERT INTO invoice_sequences (tenants_Id, LastNumber) VALUES (?, 1) ON DUPLICATE KEY UPDATE LastNumber = LastNumber + 1;"
+	if _, err := tx.Exec(query, tenantID); err != nil {
+		return err
+	}
+	var number int
+	if err := tx.QueryRow("SELECT LastNumber FROM invoice_sequences WHERE tenants_Id = ?;", tenantID).Scan(&number); err != nil {
+		return err
+	}
+	status, paidAt := domain.InvoiceIssued, any(nil)
+	if owed == 0 {
+		status, paidAt = domain.InvoicePaid, at.UTC()
+	}
+	query = "UPDATE invoices SET Status = ?, Number = ?, IssuedAt = ?, PaidAt = ? WHERE tenants_Id = ? AND Id = ?;"
+	if _, err := tx.Exec(query, status, number, at.UTC(), paidAt, tenantID, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Void cancels a draft or issued invoice, releasing its appointments to be
+// billed again. Issued invoices keep their number, and those with payments
+// left must be refunded first.
+func (s *sqlStore) Void(tenantID int, id int, at time.Time, reason string) error {
+	query := `
+		UPDATE invoices SET Status = ?, VoidedAt = ?, VoidReason = ?
+		WHERE tenants_Id = ? AND Id = ? AND Status IN (?, ?)
+		AND (SELECT COALESCE(SUM(CASE WHEN p.Kind = 'refund' THEN -p.Amount ELSE p.Amount END), 0) FROM payments AS p WHERE p.invoices_Id = invoices.Id AND p.Status = 'completed') = 0;
+	`
+	res, err := s.db.Exec(query, domain.InvoiceVoid, at.UTC(), reason, tenantID, id, domain.InvoiceDraft, domain.InvoiceIssued)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Only draft or issued invoices with no payments can be voided")
+	}
+	return nil
+}
+
+// nullableID maps the zero id used by the domain to a NULL foreign key.
+func nullableID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
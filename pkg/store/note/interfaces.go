@@ -0,0 +1,10 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type NoteStoreInterface interface {
+	Read(tenantID int, id int) (domain.ClinicalNote, error)
+	ReadByAppointment(tenantID int, appointmentID int) ([]domain.ClinicalNote, error)
+	ReadByPatient(tenantID int, patientID int) ([]domain.ClinicalNote, error)
+	Create(tenantID int, note domain.ClinicalNote) (int, error)
+}
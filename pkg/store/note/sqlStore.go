@@ -0,0 +1,97 @@
+package store
+
+import (
+	"database/sql"
+	"proyecto_final_go/internal/domain"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+// NewSqlStore returns the store of the clinical notes. Notes are never
+// updated nor deleted: corrections are stored as amendments.
+func NewSqlStore(db *sql.DB) NoteStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectNotes = `
+	SELECT Id, appointments_Id, patients_Id, dentists_Id, AmendsId, Text, Reason, CreatedAt
+	FROM clinical_notes
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanNote(row scanner) (domain.ClinicalNote, error) {
+	var note domain.ClinicalNote
+	var amendsID sql.NullInt64
+	err := row.Scan(&note.Id, &note.AppointmentId, &note.PatientId, &note.DentistId, &amendsID, &note.Text, &note.Reason, &note.CreatedAt)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	note.AmendsId = int(amendsID.Int64)
+	note.CreatedAt = note.CreatedAt.UTC()
+	return note, nil
+}
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.ClinicalNote, error) {
+	row := s.db.QueryRow(selectNotes+"WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	return scanNote(row)
+}
+
+func (s *sqlStore) ReadByAppointment(tenantID int, appointmentID int) ([]domain.ClinicalNote, error) {
+	return s.queryNotes(selectNotes+"WHERE tenants_Id = ? AND appointments_Id = ? ORDER BY Id;", tenantID, appointmentID)
+}
+
+func (s *sqlStore) ReadByPatient(tenantID int, patientID int) ([]domain.ClinicalNote, error) {
+	return s.queryNotes(selectNotes+"WHERE tenants_Id = ? AND patients_Id = ? ORDER BY Id;", tenantID, patientID)
+}
+
+func (s *sqlStore) queryNotes(query string, args ...any) ([]domain.ClinicalNote, error) {
+	notes := []domain.ClinicalNote{}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+func (s *sqlStore) Create(tenantID int, note domain.ClinicalNote) (int, error) {
+	var amendsID sql.NullInt64
+	if note.AmendsId != 0 {
+		amendsID = sql.NullInt64{Int64: int64(note.AmendsId), Valid: true}
+	}
+	query := `
+		INSERT INTO clinical_notes (tenants_Id, appointments_Id, patients_Id, dentists_Id, AmendsId, Text, Reason, CreatedAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	res, err := s.db.Exec(query, tenantID, note.AppointmentId, note.PatientId, note.DentistId, amendsID, note.Text, note.Reason, note.CreatedAt.UTC())
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
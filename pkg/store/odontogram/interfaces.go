@@ -0,0 +1,8 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type OdontogramStoreInterface interface {
+	ReadByPatient(tenantID int, patientID int) ([]domain.ToothFinding, error)
+	CreateBatch(tenantID int, findings []domain.ToothFinding) ([]int, error)
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"database/sql"
+	"proyecto_final_go/internal/domain"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) OdontogramStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+// ReadByPatient lists the findings of a patient in the order they were made.
+func (s *sqlStore) ReadByPatient(tenantID int, patientID int) ([]domain.ToothFinding, error) {
+	findings := []domain.ToothFinding{}
+	query := `
+		SELECT Id, patients_Id, appointments_Id, dentists_Id, Tooth, Surfaces, ToothCondition, Notes, ExaminedAt
+		FROM tooth_findings
+		WHERE tenants_Id = ? AND patients_Id = ?
+		ORDER BY ExaminedAt, Id;
+	`
+	rows, err := s.db.Query(query, tenantID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var finding domain.ToothFinding
+		err := rows.Scan(&finding.Id, &finding.PatientId, &finding.AppointmentId, &finding.DentistId, &finding.Tooth,
+			&finding.Surfaces, &finding.Condition, &finding.Notes, &finding.ExaminedAt)
+		if err != nil {
+			return nil, err
+		}
+		finding.ExaminedAt = finding.ExaminedAt.UTC()
+		findings = append(findings, finding)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return findings, nil
+}
+
+// CreateBatch inserts the findings of a visit in a single transaction and
+// returns their ids.
+func (s *sqlStore) CreateBatch(tenantID int, findings []domain.ToothFinding) ([]int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO tooth_findings (tenants_Id, patients_Id, appointments_Id, dentists_Id, Tooth, Surfaces, ToothCondition, Notes, ExaminedAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	ids := make([]int, 0, len(findings))
+	for _, finding := range findings {
+		res, err := tx.Exec(query, tenantID, finding.PatientId, finding.AppointmentId, finding.DentistId, finding.Tooth,
+			finding.Surfaces, finding.Condition, finding.Notes, finding.ExaminedAt.UTC())
+		if err != nil {
+			return nil, err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, int(id))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
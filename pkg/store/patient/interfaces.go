@@ -5,11 +5,15 @@ import (
 )
 
 type PatientStoreInterface interface {
-	Read(id int) (domain.Patient, error)
-	Create(product domain.Patient) error
-	Update(product domain.Patient) error
-	Delete(id int) error
-	GetAll() ([]domain.Patient, error)
-	Exists(dni string) (bool, error)
-	PatchAddress(id int, address string) error
+	Read(tenantID int, id int) (domain.Patient, error)
+	ReadByDNI(tenantID int, dni string) (domain.Patient, error)
+	Create(tenantID int, product domain.Patient) error
+	CreateBatch(tenantID int, patients []domain.Patient) error
+	Update(tenantID int, product domain.Patient) error
+	Delete(tenantID int, id int) error
+	GetAll(tenantID int) ([]domain.Patient, error)
+	Each(tenantID int, fn func(domain.Patient) error) error
+	Search(tenantID int, terms []string, limit int) ([]domain.Patient, error)
+	Exists(tenantID int, dni string) (bool, error)
+	PatchAddress(tenantID int, id int, address string) error
 }
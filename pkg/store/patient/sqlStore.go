@@ -4,6 +4,9 @@ import (
 	"database/sql"
 	"errors"
 	"proyecto_final_go/internal/domain"
+	"proyecto_final_go/pkg/outbox"
+	"strings"
+	"unicode/utf8"
 )
 
 type sqlStore struct {
@@ -18,79 +21,121 @@ func NewSqlStore(db *sql.DB) PatientStoreInterface {
 
 //-----------------------------------
 
-func (s *sqlStore) Read(id int) (domain.Patient, error) {
+// queryRower is implemented by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func readPatient(q queryRower, tenantID int, id int) (domain.Patient, error) {
 	var patient domain.Patient
-	query := "SELECT * FROM patients WHERE Id = ?;"
-	row := s.db.QueryRow(query, id)
-	err := row.Scan(&patient.Id, &patient.FirstName, &patient.LastName, &patient.Address, &patient.DNI, &patient.ReleaseDate)
+	query := "SELECT Id, FirstName, LastName, Address, DNI, ReleaseDate, Email, Phone FROM patients WHERE tenants_Id = ? AND Id = ?;"
+	row := q.QueryRow(query, tenantID, id)
+	err := row.Scan(&patient.Id, &patient.FirstName, &patient.LastName, &patient.Address, &patient.DNI, &patient.ReleaseDate, &patient.Email, &patient.Phone)
 	if err != nil {
 		return domain.Patient{}, err
 	}
 	return patient, nil
 }
 
-func (s *sqlStore) Create(patient domain.Patient) error {
-	query := "INSERT INTO patients (FirstName, LastName, Address, DNI, ReleaseDate) VALUES (?, ?, ?, ?, ?);"
-	stmt, err := s.db.Prepare(query)
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
+func (s *sqlStore) Read(tenantID int, id int) (domain.Patient, error) {
+	return readPatient(s.db, tenantID, id)
+}
 
-	res, err := stmt.Exec(patient.FirstName, patient.LastName, patient.Address, patient.DNI, patient.ReleaseDate)
+func (s *sqlStore) ReadByDNI(tenantID int, dni string) (domain.Patient, error) {
+	var patient domain.Patient
+	query := "SELECT Id, FirstName, LastName, Address, DNI, ReleaseDate, Email, Phone FROM patients WHERE tenants_Id = ? AND DNI = ?;"
+	row := s.db.QueryRow(query, tenantID, dni)
+	err := row.Scan(&patient.Id, &patient.FirstName, &patient.LastName, &patient.Address, &patient.DNI, &patient.ReleaseDate, &patient.Email, &patient.Phone)
 	if err != nil {
-		return err
+		return domain.Patient{}, err
 	}
+	return patient, nil
+}
 
-	rowsAffected, err := res.RowsAffected()
+func (s *sqlStore) Create(tenantID int, patient domain.Patient) error {
+	return s.CreateBatch(tenantID, []domain.Patient{patient})
+}
+
+// CreateBatch inserts the patients in a single transaction, either all of
+// them are created or none.
+func (s *sqlStore) CreateBatch(tenantID int, patients []domain.Patient) error {
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
-	if rowsAffected == 0 {
-		return errors.New("no rows affected")
+	defer tx.Rollback()
+
+	query := "INSERT INTO patients (tenants_Id, FirstName, LastName, Address, DNI, ReleaseDate, Email, Phone) VALUES (?, ?, ?, ?, ?, ?, ?, ?);"
+	for _, patient := range patients {
+		res, err := tx.Exec(query, tenantID, patient.FirstName, patient.LastName, patient.Address, patient.DNI, patient.ReleaseDate, patient.Email, patient.Phone)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		patient.Id = int(id)
+		if err := outbox.Record(tx, tenantID, "patient", patient.Id, domain.EventPatientCreated, patient); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return tx.Commit()
 }
 
-func (s *sqlStore) Update(patient domain.Patient) error {
-	query := "UPDATE patients SET FirstName = ?, LastName = ?, Address = ?, DNI = ?, ReleaseDate = ? WHERE Id = ?;"
-	stmt, err := s.db.Prepare(query)
+func (s *sqlStore) Update(tenantID int, patient domain.Patient) error {
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
-	res, err := stmt.Exec(patient.FirstName, patient.LastName, patient.Address, patient.DNI, patient.ReleaseDate, patient.Id)
+	defer tx.Rollback()
+
+	query := "UPDATE patients SET FirstName = ?, LastName = ?, Address = ?, DNI = ?, ReleaseDate = ?, Email = ?, Phone = ? WHERE tenants_Id = ? AND Id = ?;"
+	res, err := tx.Exec(query, patient.FirstName, patient.LastName, patient.Address, patient.DNI, patient.ReleaseDate, patient.Email, patient.Phone, tenantID, patient.Id)
 	if err != nil {
 		return err
 	}
-	_, err = res.RowsAffected()
+	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
-	return nil
+	if rowsAffected > 0 {
+		if err := outbox.Record(tx, tenantID, "patient", patient.Id, domain.EventPatientUpdated, patient); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-func (s *sqlStore) Delete(id int) error {
-	query := "DELETE FROM patients WHERE Id = ?;"
-	stmt, err := s.db.Prepare(query)
+func (s *sqlStore) Delete(tenantID int, id int) error {
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
-	res, err := stmt.Exec(id)
+	defer tx.Rollback()
+
+	query := "DELETE FROM patients WHERE tenants_Id = ? AND Id = ?;"
+	res, err := tx.Exec(query, tenantID, id)
 	if err != nil {
 		return err
 	}
-	_, err = res.RowsAffected()
+	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
-	return nil
+	if rowsAffected > 0 {
+		if err := outbox.Record(tx, tenantID, "patient", id, domain.EventPatientDeleted, domain.Patient{Id: id}); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-func (s *sqlStore) Exists(dni string) (bool, error) {
+func (s *sqlStore) Exists(tenantID int, dni string) (bool, error) {
 	var id int
-	query := "SELECT Id FROM patients WHERE DNI = ?;"
-	row := s.db.QueryRow(query, dni)
+	query := "SELECT Id FROM patients WHERE tenants_Id = ? AND DNI = ?;"
+	row := s.db.QueryRow(query, tenantID, dni)
 	err := row.Scan(&id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -101,27 +146,38 @@ func (s *sqlStore) Exists(dni string) (bool, error) {
 	return id > 0, nil
 }
 
-func (s *sqlStore) PatchAddress(id int, address string) error {
-	query := "UPDATE patients SET Address = ? WHERE Id = ?;"
-	stmt, err := s.db.Prepare(query)
+func (s *sqlStore) PatchAddress(tenantID int, id int, address string) error {
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
-	res, err := stmt.Exec(address, id)
+	defer tx.Rollback()
+
+	query := "UPDATE patients SET Address = ? WHERE tenants_Id = ? AND Id = ?;"
+	res, err := tx.Exec(query, address, tenantID, id)
 	if err != nil {
 		return err
 	}
-	_, err = res.RowsAffected()
+	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
-	return nil
+	if rowsAffected > 0 {
+		patient, err := readPatient(tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+		if err := outbox.Record(tx, tenantID, "patient", id, domain.EventPatientUpdated, patient); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-func (s *sqlStore) GetAll() ([]domain.Patient, error) {
+func (s *sqlStore) GetAll(tenantID int) ([]domain.Patient, error) {
 	var patients []domain.Patient
-	query := "SELECT * FROM patients;"
-	rows, err := s.db.Query(query)
+	query := "SELECT Id, FirstName, LastName, Address, DNI, ReleaseDate, Email, Phone FROM patients WHERE tenants_Id = ?;"
+	rows, err := s.db.Query(query, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +185,7 @@ func (s *sqlStore) GetAll() ([]domain.Patient, error) {
 
 	for rows.Next() {
 		var patient domain.Patient
-		if err := rows.Scan(&patient.Id, &patient.FirstName, &patient.LastName, &patient.Address, &patient.DNI, &patient.ReleaseDate); err != nil {
+		if err := rows.Scan(&patient.Id, &patient.FirstName, &patient.LastName, &patient.Address, &patient.DNI, &patient.ReleaseDate, &patient.Email, &patient.Phone); err != nil {
 			return nil, err
 		}
 		patients = append(patients, patient)
@@ -141,3 +197,77 @@ func (s *sqlStore) GetAll() ([]domain.Patient, error) {
 
 	return patients, nil
 }
+
+// Each calls fn with the patients as they are read from the database,
+// without loading them all in memory.
+func (s *sqlStore) Each(tenantID int, fn func(domain.Patient) error) error {
+	query := "SELECT Id, FirstName, LastName, Address, DNI, ReleaseDate, Email, Phone FROM patients WHERE tenants_Id = ? ORDER BY LastName, FirstName;"
+	rows, err := s.db.Query(query, tenantID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var patient domain.Patient
+		if err := rows.Scan(&patient.Id, &patient.FirstName, &patient.LastName, &patient.Address, &patient.DNI, &patient.ReleaseDate, &patient.Email, &patient.Phone); err != nil {
+			return err
+		}
+		if err := fn(patient); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Search finds the patients whose first name, last name, DNI or address have
+// words starting with every term, best matches first. The columns use an
+// accent and case insensitive collation, so "rodriguez" finds "Rodríguez".
+// Terms of three or more characters go through the full-text index; shorter
+// ones, below the index token size, are matched with LIKE.
+func (s *sqlStore) Search(tenantID int, terms []string, limit int) ([]domain.Patient, error) {
+	var fulltext []string
+	conditions := []string{"tenants_Id = ?"}
+	var args []any
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) >= 3 {
+			fulltext = append(fulltext, "+"+term+"*")
+			continue
+		}
+		var words []string
+		for _, column := range []string{"FirstName", "LastName", "DNI", "Address"} {
+			words = append(words, column+" LIKE ?", column+" LIKE ?")
+			args = append(args, term+"%", "% "+term+"%")
+		}
+		conditions = append(conditions, "("+strings.Join(words, " OR ")+")")
+	}
+
+	// Exact DNI matches come first, then names starting with the first term.
+	score := "10 * (DNI = ?) + 5 * (LastName LIKE ?) + 3 * (FirstName LIKE ?)"
+	scoreArgs := []any{strings.Join(terms, ""), terms[0] + "%", terms[0] + "%"}
+	whereArgs := append([]any{tenantID}, args...)
+	if len(fulltext) > 0 {
+		score = "MATCH(FirstName, LastName, DNI, Address) AGAINST (? IN BOOLEAN MODE) + " + score
+		scoreArgs = append([]any{strings.Join(fulltext, " ")}, scoreArgs...)
+		conditions = append(conditions, "MATCH(FirstName, LastName, DNI, Address) AGAINST (? IN BOOLEAN MODE)")
+		whereArgs = append(whereArgs, strings.Join(fulltext, " "))
+	}
+	query := "SELECT Id, FirstName, LastName, Address, DNI, ReleaseDate, Email, Phone, " + score + " AS Score FROM patients WHERE " +
+		strings.Join(conditions, " AND ") + " ORDER BY Score DESC, LastName, FirstName LIMIT ?;"
+	rows, err := s.db.Query(query, append(append(scoreArgs, whereArgs...), limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	patients := []domain.Patient{}
+	for rows.Next() {
+		var patient domain.Patient
+		var score float64
+		if err := rows.Scan(&patient.Id, &patient.FirstName, &patient.LastName, &patient.Address, &patient.DNI, &patient.ReleaseDate, &patient.Email, &patient.Phone, &score); err != nil {
+			return nil, err
+		}
+		patients = append(patients, patient)
+	}
+	return patients, rows.Err()
+}
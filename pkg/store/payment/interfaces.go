@@ -0,0 +1,16 @@
+package store
+
+import (
+	"proyecto_final_go/internal/domain"
+	"time"
+)
+
+type PaymentStoreInterface interface {
+	Read(tenantID int, id int) (domain.Payment, error)
+	ReadByInvoice(tenantID int, invoiceID int) ([]domain.Payment, error)
+	ReadByPatient(tenantID int, patientID int) ([]domain.Payment, error)
+	ReadByClinic(tenantID int, clinicID int, from time.Time, to time.Time) ([]domain.Payment, error)
+	Create(tenantID int, payment domain.Payment) (int, error)
+	Complete(tenantID int, id int, provider string, reference string) error
+	Void(tenantID int, id int) error
+}
@@ -0,0 +1,286 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"time"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) PaymentStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectPayments = `
+	SELECT Id, patients_Id, invoices_Id, clinics_Id, Kind, Method, Amount, Provider, Reference, payments_Id, Notes, ReceivedAt, Status
+	FROM payments
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPayment(row scanner) (domain.Payment, error) {
+	var payment domain.Payment
+	var refundOf sql.NullInt64
+	err := row.Scan(&payment.Id, &payment.PatientId, &payment.InvoiceId, &payment.ClinicId, &payment.Kind, &payment.Method, &payment.Amount,
+		&payment.Provider, &payment.Reference, &refundOf, &payment.Notes, &payment.ReceivedAt, &payment.Status)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	payment.RefundOf = int(refundOf.Int64)
+	payment.ReceivedAt = payment.ReceivedAt.UTC()
+	return payment, nil
+}
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.Payment, error) {
+	row := s.db.QueryRow(selectPayments+"WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	return scanPayment(row)
+}
+
+// ReadByInvoice lists the entries of an invoice in the order they were
+// recorded.
+func (s *sqlStore) ReadByInvoice(tenantID int, invoiceID int) ([]domain.Payment, error) {
+	return s.queryPayments(selectPayments+"WHERE tenants_Id = ? AND invoices_Id = ? ORDER BY ReceivedAt, Id;", tenantID, invoiceID)
+}
+
+// ReadByPatient lists the entries of a patient, the most recent first.
+func (s *sqlStore) ReadByPatient(tenantID int, patientID int) ([]domain.Payment, error) {
+	return s.queryPayments(selectPayments+"WHERE tenants_Id = ? AND patients_Id = ? ORDER BY ReceivedAt DESC, Id DESC;", tenantID, patientID)
+}
+
+// ReadByClinic lists the completed entries of a clinic received in
+// [from, to), in the order they were recorded.
+func (s *sqlStore) ReadByClinic(tenantID int, clinicID int, from time.Time, to time.Time) ([]domain.Payment, error) {
+	query := selectPayments + "WHERE tenants_Id = ? AND clinics_Id = ? AND Status = ? AND ReceivedAt >= ? AND ReceivedAt < ? ORDER BY ReceivedAt, Id;"
+	return s.queryPayments(query, tenantID, clinicID, domain.PaymentCompleted, from.UTC(), to.UTC())
+}
+
+func (s *sqlStore) queryPayments(query string, args ...any) ([]domain.Payment, error) {
+	payments := []domain.Payment{}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+// Create records a payment or a refund. The invoice is locked while a
+// payment is checked against what the patient owes, pending payments
+// included, or a refund against what is left of its payment, pending refunds
+// included, and its status follows: it is paid once nothing is due and
+// issued again when a refund leaves something due. Pending entries only
+// reserve their amount, the invoice follows them once they are completed.
+func (s *sqlStore) Create(tenantID int, payment domain.Payment) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	status, owed, paid, err := lockInvoice(tx, tenantID, payment.InvoiceId)
+	if err != nil {
+		return 0, err
+	}
+
+	switch payment.Kind {
+	case domain.PaymentKindPayment:
+		if status != domain.InvoiceIssued {
+			return 0, errors.New("Payments can only be recorded for issued invoices")
+		}
+		pending, err := pendingPayments(tx, tenantID, payment.InvoiceId)
+		if err != nil {
+			return 0, err
+		}
+		if payment.Amount > owed-paid-pending {
+			return 0, errors.New("The payment exceeds the amount due")
+		}
+	case domain.PaymentKindRefund:
+		left, err := refundable(tx, tenantID, payment.RefundOf)
+		if err != nil {
+			return 0, err
+		}
+		if payment.Amount > left {
+			return 0, errors.New("The refund exceeds what is left of the payment")
+		}
+	default:
+		return 0, errors.New("Invalid kind")
+	}
+
+	query := `
+		INSERT INTO payments (tenants_Id, patients_Id, invoices_Id, clinics_Id, Kind, Method, Amount, Provider, Reference, payments_Id, Notes, ReceivedAt, Status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	res, err := tx.Exec(query, tenantID, payment.PatientId, payment.InvoiceId, payment.ClinicId, payment.Kind, payment.Method, payment.Amount,
+		payment.Provider, payment.Reference, nullableID(payment.RefundOf), payment.Notes, payment.ReceivedAt.UTC(), payment.Status)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if payment.Status == domain.PaymentCompleted {
+		if err := settleInvoice(tx, tenantID, payment.InvoiceId, status, owed, paid+payment.Signed(), payment.ReceivedAt); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// Complete records the answer of the provider to a pending payment or refund
+// and updates the status of its invoice.
+func (s *sqlStore) Complete(tenantID int, id int, provider string, reference string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var entry domain.Payment
+	query := "SELECT invoices_Id, Kind, Amount, ReceivedAt FROM payments WHERE tenants_Id = ? AND Id = ? AND Status = ?;"
+	err = tx.QueryRow(query, tenantID, id, domain.PaymentPending).Scan(&entry.InvoiceId, &entry.Kind, &entry.Amount, &entry.ReceivedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("Pending payment not found")
+		}
+		return err
+	}
+	status, owed, paid, err := lockInvoice(tx, tenantID, entry.InvoiceId)
+	if err != nil {
+		return err
+	}
+
+	query = "UPDATE payments SET Status = ?, Provider = ?, Reference = ? WHERE tenants_Id = ? AND Id = ? AND Status = ?;"
+	res, err := tx.Exec(query, domain.PaymentCompleted, provider, reference, tenantID, id, domain.PaymentPending)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Pending payment not found")
+	}
+	if err := settleInvoice(tx, tenantID, entry.InvoiceId, status, owed, paid+entry.Signed(), entry.ReceivedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Void cancels a pending payment or refund the provider did not make, so its
+// amount can be paid or refunded again.
+func (s *sqlStore) Void(tenantID int, id int) error {
+	query := "UPDATE payments SET Status = ? WHERE tenants_Id = ? AND Id = ? AND Status = ?;"
+	res, err := s.db.Exec(query, domain.PaymentVoided, tenantID, id, domain.PaymentPending)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Pending payment not found")
+	}
+	return nil
+}
+
+// lockInvoice locks the invoice until the end of the transaction and returns
+// its status, what the patient owes of it and what the completed entries paid.
+func lockInvoice(tx *sql.Tx, tenantID int, invoiceID int) (string, int64, int64, error) {
+	var status string
+	var owed int64
+	err := tx.QueryRow("SELECT Status, Total - Covered FROM invoices WHERE tenants_Id = ? AND Id = ? FOR UPDATE;", tenantID, invoiceID).Scan(&status, &owed)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", 0, 0, errors.New("Invoice not found")
+		}
+		return "", 0, 0, err
+	}
+	query := `
+		SELECT COALESCE(SUM(CASE WHEN Kind = ? THEN -Amount ELSE Amount END), 0)
+		FROM payments
+		WHERE tenants_Id = ? AND invoices_Id = ? AND Status = ?;
+	`
+	var paid int64
+	if err := tx.QueryRow(query, domain.PaymentKindRefund, tenantID, invoiceID, domain.PaymentCompleted).Scan(&paid); err != nil {
+		return "", 0, 0, err
+	}
+	return status, owed, paid, nil
+}
+
+// settleInvoice marks the invoice paid once nothing is due, and issued again
+// when a refund leaves something due.
+func settleInvoice(tx *sql.Tx, tenantID int, invoiceID int, status string, owed int64, paid int64, at time.Time) error {
+	var err error
+	switch {
+	case status == domain.InvoiceIssued && paid >= owed:
+		query := "UPDATE invoices SET Status = ?, PaidAt = ? WHERE tenants_Id = ? AND Id = ?;"
+		_, err = tx.Exec(query, domain.InvoicePaid, at.UTC(), tenantID, invoiceID)
+	case status == domain.InvoicePaid && paid < owed:
+		query := "UPDATE invoices SET Status = ?, PaidAt = NULL WHERE tenants_Id = ? AND Id = ?;"
+		_, err = tx.Exec(query, domain.InvoiceIssued, tenantID, invoiceID)
+	}
+	return err
+}
+
+// pendingPayments returns the amount of the payments of the invoice waiting
+// for the provider.
+func pendingPayments(tx *sql.Tx, tenantID int, invoiceID int) (int64, error) {
+	var pending int64
+	query := "SELECT COALESCE(SUM(Amount), 0) FROM payments WHERE tenants_Id = ? AND invoices_Id = ? AND Kind = ? AND Status = ?;"
+	err := tx.QueryRow(query, tenantID, invoiceID, domain.PaymentKindPayment, domain.PaymentPending).Scan(&pending)
+	return pending, err
+}
+
+// refundable returns what is left to give back of a payment after its
+// completed and pending refunds.
+func refundable(tx *sql.Tx, tenantID int, paymentID int) (int64, error) {
+	var amount int64
+	query := "SELECT Amount FROM payments WHERE tenants_Id = ? AND Id = ? AND Kind = ? AND Status = ?;"
+	err := tx.QueryRow(query, tenantID, paymentID, domain.PaymentKindPayment, domain.PaymentCompleted).Scan(&amount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New("Payment not found")
+		}
+		return 0, err
+	}
+	var refunded int64
+	query = "SELECT COALESCE(SUM(Amount), 0) FROM payments WHERE tenants_Id = ? AND payments_Id = ? AND Status <> ?;"
+	if err := tx.QueryRow(query, tenantID, paymentID, domain.PaymentVoided).Scan(&refunded); err != nil {
+		return 0, err
+	}
+	return amount - refunded, nil
+}
+
+// nullableID maps the zero id used by the domain to a NULL foreign key.
+func nullableID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
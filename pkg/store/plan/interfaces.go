@@ -0,0 +1,13 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type PlanStoreInterface interface {
+	Read(tenantID int, id int) (domain.TreatmentPlan, error)
+	ReadByPatient(tenantID int, patientID int) ([]domain.TreatmentPlan, error)
+	ReadByStep(tenantID int, stepID int) (domain.TreatmentPlan, error)
+	Create(tenantID int, plan domain.TreatmentPlan) (int, error)
+	UpdateStatus(tenantID int, id int, status string) error
+	CreateStep(tenantID int, step domain.PlanStep) (int, error)
+	UpdateStep(tenantID int, step domain.PlanStep) error
+}
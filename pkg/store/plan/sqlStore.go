@@ -0,0 +1,263 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"strings"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) PlanStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectPlans = `
+	SELECT Id, patients_Id, Title, Notes, Status, CreatedAt
+	FROM treatment_plans
+`
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.TreatmentPlan, error) {
+	plans, err := s.queryPlans(tenantID, selectPlans+"WHERE tenants_Id = ? AND Id = ?;", tenantID, id)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	if len(plans) == 0 {
+		return domain.TreatmentPlan{}, sql.ErrNoRows
+	}
+	return plans[0], nil
+}
+
+// ReadByPatient lists the plans of a patient, the most recent first.
+func (s *sqlStore) ReadByPatient(tenantID int, patientID int) ([]domain.TreatmentPlan, error) {
+	return s.queryPlans(tenantID, selectPlans+"WHERE tenants_Id = ? AND patients_Id = ? ORDER BY CreatedAt DESC, Id DESC;", tenantID, patientID)
+}
+
+// ReadByStep reads the plan a step belongs to.
+func (s *sqlStore) ReadByStep(tenantID int, stepID int) (domain.TreatmentPlan, error) {
+	var planID int
+	err := s.db.QueryRow("SELECT treatment_plans_Id FROM plan_steps WHERE tenants_Id = ? AND Id = ?;", tenantID, stepID).Scan(&planID)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	return s.Read(tenantID, planID)
+}
+
+// queryPlans reads the plans with their steps and the appointments booked
+// for each step.
+func (s *sqlStore) queryPlans(tenantID int, query string, args ...any) ([]domain.TreatmentPlan, error) {
+	plans := []domain.TreatmentPlan{}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var plan domain.TreatmentPlan
+		if err := rows.Scan(&plan.Id, &plan.PatientId, &plan.Title, &plan.Notes, &plan.Status, &plan.CreatedAt); err != nil {
+			return nil, err
+		}
+		plan.CreatedAt = plan.CreatedAt.UTC()
+		plan.Steps = []domain.PlanStep{}
+		plans = append(plans, plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachSteps(tenantID, plans); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+func (s *sqlStore) attachSteps(tenantID int, plans []domain.TreatmentPlan) error {
+	if len(plans) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(plans))
+	ids := []any{tenantID}
+	for i := range plans {
+		index[plans[i].Id] = i
+		ids = append(ids, plans[i].Id)
+	}
+
+	query := `
+		SELECT ps.Id, ps.treatment_plans_Id, ps.Position, ps.Description, ps.EstimatedCost, ps.Skipped, t.Id, t.Name
+		FROM plan_steps AS ps
+		LEFT JOIN treatments AS t ON ps.treatments_Id = t.Id
+		WHERE ps.tenants_Id = ? AND ps.treatment_plans_Id IN (?` + strings.Repeat(", ?", len(ids)-2) + `)
+		ORDER BY ps.Position;
+	`
+	rows, err := s.db.Query(query, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var stepIDs []any
+	for rows.Next() {
+		var step domain.PlanStep
+		var treatmentID sql.NullInt64
+		var treatmentName sql.NullString
+		err := rows.Scan(&step.Id, &step.PlanId, &step.Position, &step.Description, &step.EstimatedCost, &step.Skipped, &treatmentID, &treatmentName)
+		if err != nil {
+			return err
+		}
+		step.Treatment.Id = int(treatmentID.Int64)
+		step.Treatment.Name = treatmentName.String
+		step.Appointments = []domain.StepAppointment{}
+		plan := &plans[index[step.PlanId]]
+		plan.Steps = append(plan.Steps, step)
+		stepIDs = append(stepIDs, step.Id)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(stepIDs) == 0 {
+		return nil
+	}
+	steps := map[int]*domain.PlanStep{}
+	for i := range plans {
+		for j := range plans[i].Steps {
+			steps[plans[i].Steps[j].Id] = &plans[i].Steps[j]
+		}
+	}
+
+	query = `
+		SELECT Id, plan_steps_Id, StartsAt, CompletedAt
+		FROM appointments
+		WHERE tenants_Id = ? AND plan_steps_Id IN (?` + strings.Repeat(", ?", len(stepIDs)-1) + `)
+		ORDER BY StartsAt;
+	`
+	appointmentRows, err := s.db.Query(query, append([]any{tenantID}, stepIDs...)...)
+	if err != nil {
+		return err
+	}
+	defer appointmentRows.Close()
+
+	for appointmentRows.Next() {
+		var appointment domain.StepAppointment
+		var stepID int
+		var completedAt sql.NullTime
+		if err := appointmentRows.Scan(&appointment.Id, &stepID, &appointment.StartsAt, &completedAt); err != nil {
+			return err
+		}
+		appointment.StartsAt = appointment.StartsAt.UTC()
+		if completedAt.Valid {
+			completed := completedAt.Time.UTC()
+			appointment.CompletedAt = &completed
+		}
+		steps[stepID].Appointments = append(steps[stepID].Appointments, appointment)
+	}
+	return appointmentRows.Err()
+}
+
+// Create inserts the plan and its steps in a single transaction.
+func (s *sqlStore) Create(tenantID int, plan domain.TreatmentPlan) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO treatment_plans (tenants_Id, patients_Id, Title, Notes, Status, CreatedAt) VALUES (?, ?, ?, ?, ?, ?);"
+	res, err := tx.Exec(query, tenantID, plan.PatientId, plan.Title, plan.Notes, plan.Status, plan.CreatedAt.UTC())
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	for _, step := range plan.Steps {
+		step.PlanId = int(id)
+		if _, err := insertStep(tx, tenantID, step); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func insertStep(tx *sql.Tx, tenantID int, step domain.PlanStep) (int, error) {
+	query := `
+		INSERT INTO plan_steps (tenants_Id, treatment_plans_Id, Position, treatments_Id, Description, EstimatedCost, Skipped)
+		VALUES (?, ?, ?, ?, ?, ?, ?);
+	`
+	res, err := tx.Exec(query, tenantID, step.PlanId, step.Position, nullableID(step.Treatment.Id), step.Description, step.EstimatedCost, step.Skipped)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// nullableID maps the zero id used by the domain to a NULL foreign key.
+func nullableID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+func (s *sqlStore) UpdateStatus(tenantID int, id int, status string) error {
+	_, err := s.db.Exec("UPDATE treatment_plans SET Status = ? WHERE tenants_Id = ? AND Id = ?;", status, tenantID, id)
+	return err
+}
+
+// CreateStep appends a step at the end of its plan.
+func (s *sqlStore) CreateStep(tenantID int, step domain.PlanStep) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Locking the plan keeps concurrent appends from taking the same position.
+	var planID int
+	err = tx.QueryRow("SELECT Id FROM treatment_plans WHERE tenants_Id = ? AND Id = ? FOR UPDATE;", tenantID, step.PlanId).Scan(&planID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New("Treatment plan not found")
+		}
+		return 0, err
+	}
+	err = tx.QueryRow("SELECT COALESCE(MAX(Position), 0) + 1 FROM plan_steps WHERE treatment_plans_Id = ?;", planID).Scan(&step.Position)
+	if err != nil {
+		return 0, err
+	}
+	id, err := insertStep(tx, tenantID, step)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *sqlStore) UpdateStep(tenantID int, step domain.PlanStep) error {
+	query := `
+		UPDATE plan_steps
+		SET treatments_Id = ?, Description = ?, EstimatedCost = ?, Skipped = ?
+		WHERE tenants_Id = ? AND Id = ?;
+	`
+	_, err := s.db.Exec(query, nullableID(step.Treatment.Id), step.Description, step.EstimatedCost, step.Skipped, tenantID, step.Id)
+	return err
+}
@@ -0,0 +1,15 @@
+package store
+
+import (
+	"proyecto_final_go/internal/domain"
+	"time"
+)
+
+type PrescriptionStoreInterface interface {
+	Read(tenantID int, id int) (domain.Prescription, error)
+	ReadByCode(code string) (domain.Prescription, error)
+	ReadByPatient(tenantID int, patientID int) ([]domain.Prescription, error)
+	ReadByAppointment(tenantID int, appointmentID int) ([]domain.Prescription, error)
+	Create(tenantID int, prescription domain.Prescription) (int, error)
+	Void(tenantID int, id int, at time.Time, reason string) error
+}
@@ -0,0 +1,184 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"strings"
+	"time"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) PrescriptionStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectPrescriptions = `
+	SELECT pr.Id, pr.appointments_Id, pr.patients_Id, p.FirstName, p.LastName, pr.dentists_Id, pr.DentistName, pr.License,
+		pr.Diagnosis, pr.Notes, pr.Status, pr.Code, pr.IssuedAt, pr.VoidReason, pr.VoidedAt
+	FROM prescriptions AS pr
+	INNER JOIN patients AS p ON pr.patients_Id = p.Id
+`
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.Prescription, error) {
+	prescriptions, err := s.queryPrescriptions(tenantID, selectPrescriptions+"WHERE pr.tenants_Id = ? AND pr.Id = ?;", tenantID, id)
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	if len(prescriptions) == 0 {
+		return domain.Prescription{}, sql.ErrNoRows
+	}
+	return prescriptions[0], nil
+}
+
+// ReadByCode reads the prescription printed with a verification code, in
+// whichever practice issued it.
+func (s *sqlStore) ReadByCode(code string) (domain.Prescription, error) {
+	var tenantID, id int
+	err := s.db.QueryRow("SELECT tenants_Id, Id FROM prescriptions WHERE Code = ?;", code).Scan(&tenantID, &id)
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	return s.Read(tenantID, id)
+}
+
+// ReadByPatient lists the prescriptions of a patient, the most recent first.
+func (s *sqlStore) ReadByPatient(tenantID int, patientID int) ([]domain.Prescription, error) {
+	return s.queryPrescriptions(tenantID, selectPrescriptions+"WHERE pr.tenants_Id = ? AND pr.patients_Id = ? ORDER BY pr.IssuedAt DESC, pr.Id DESC;", tenantID, patientID)
+}
+
+func (s *sqlStore) ReadByAppointment(tenantID int, appointmentID int) ([]domain.Prescription, error) {
+	return s.queryPrescriptions(tenantID, selectPrescriptions+"WHERE pr.tenants_Id = ? AND pr.appointments_Id = ? ORDER BY pr.Id;", tenantID, appointmentID)
+}
+
+// queryPrescriptions reads the prescriptions with their items.
+func (s *sqlStore) queryPrescriptions(tenantID int, query string, args ...any) ([]domain.Prescription, error) {
+	prescriptions := []domain.Prescription{}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prescription domain.Prescription
+		var firstName, lastName string
+		var voidedAt sql.NullTime
+		err := rows.Scan(&prescription.Id, &prescription.AppointmentId, &prescription.PatientId, &firstName, &lastName, &prescription.DentistId,
+			&prescription.DentistName, &prescription.License, &prescription.Diagnosis, &prescription.Notes, &prescription.Status, &prescription.Code,
+			&prescription.IssuedAt, &prescription.VoidReason, &voidedAt)
+		if err != nil {
+			return nil, err
+		}
+		prescription.PatientName = strings.TrimSpace(firstName + " " + lastName)
+		prescription.IssuedAt = prescription.IssuedAt.UTC()
+		if voidedAt.Valid {
+			voided := voidedAt.Time.UTC()
+			prescription.VoidedAt = &voided
+		}
+		prescription.Items = []domain.PrescriptionItem{}
+		prescriptions = append(prescriptions, prescription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachItems(tenantID, prescriptions); err != nil {
+		return nil, err
+	}
+	return prescriptions, nil
+}
+
+func (s *sqlStore) attachItems(tenantID int, prescriptions []domain.Prescription) error {
+	if len(prescriptions) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(prescriptions))
+	ids := []any{tenantID}
+	for i := range prescriptions {
+		index[prescriptions[i].Id] = i
+		ids = append(ids, prescriptions[i].Id)
+	}
+
+	query := `
+		SELECT Id, prescriptions_Id, Medication, Dose, Quantity, Instructions
+		FROM prescription_items
+		WHERE tenants_Id = ? AND prescriptions_Id IN (?` + strings.Repeat(", ?", len(ids)-2) + `)
+		ORDER BY Id;
+	`
+	rows, err := s.db.Query(query, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.PrescriptionItem
+		if err := rows.Scan(&item.Id, &item.PrescriptionId, &item.Medication, &item.Dose, &item.Quantity, &item.Instructions); err != nil {
+			return err
+		}
+		prescription := &prescriptions[index[item.PrescriptionId]]
+		prescription.Items = append(prescription.Items, item)
+	}
+	return rows.Err()
+}
+
+// Create inserts the prescription and its items in a single transaction.
+func (s *sqlStore) Create(tenantID int, prescription domain.Prescription) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO prescriptions (tenants_Id, appointments_Id, patients_Id, dentists_Id, DentistName, License, Diagnosis, Notes, Status, Code, IssuedAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	res, err := tx.Exec(query, tenantID, prescription.AppointmentId, prescription.PatientId, prescription.DentistId, prescription.DentistName,
+		prescription.License, prescription.Diagnosis, prescription.Notes, prescription.Status, prescription.Code, prescription.IssuedAt.UTC())
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	for _, item := range prescription.Items {
+		query := `
+			INSERT INTO prescription_items (tenants_Id, prescriptions_Id, Medication, Dose, Quantity, Instructions)
+			VALUES (?, ?, ?, ?, ?, ?);
+		`
+		if _, err := tx.Exec(query, tenantID, id, item.Medication, item.Dose, item.Quantity, item.Instructions); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func (s *sqlStore) Void(tenantID int, id int, at time.Time, reason string) error {
+	query := "UPDATE prescriptions SET Status = ?, VoidedAt = ?, VoidReason = ? WHERE tenants_Id = ? AND Id = ? AND Status = ?;"
+	res, err := s.db.Exec(query, domain.PrescriptionVoided, at.UTC(), reason, tenantID, id, domain.PrescriptionIssued)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Prescription already voided")
+	}
+	return nil
+}
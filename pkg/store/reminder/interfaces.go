@@ -0,0 +1,9 @@
+package store
+
+import "time"
+
+type ReminderStoreInterface interface {
+	Claim(tenantID int, appointmentID int, offset time.Duration, now time.Time, expiry time.Duration) (bool, error)
+	MarkSent(tenantID int, appointmentID int, offset time.Duration) error
+	Release(tenantID int, appointmentID int, offset time.Duration) error
+}
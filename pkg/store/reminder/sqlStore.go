@@ -0,0 +1,74 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) ReminderStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+// Claim reserves the reminder of an appointment for the given offset. Only
+// one caller can claim it, which keeps reminders from being sent twice even
+// with several instances running the scheduler. A pending claim older than
+// expiry was left by a worker that crashed before sending, and is taken over.
+func (s *sqlStore) Claim(tenantID int, appointmentID int, offset time.Duration, now time.Time, expiry time.Duration) (bool, error) {
+	query := `
+		INSERT INTO appointment_reminders (tenants_Id, appointments_Id, OffsetMinutes, Status, ClaimedAt)
+		SELECT a.tenants_Id, a.Id, ?, 'pending', ?
+		FROM appointments AS a
+		WHERE a.tenants_Id = ? AND a.Id = ?
+		ON DUPLICATE KEY UPDATE ClaimedAt = IF(Status = 'pending' AND ClaimedAt < ?, VALUES(ClaimedAt), ClaimedAt);
+	`
+	now = now.UTC().Truncate(time.Second)
+	res, err := s.db.Exec(query, int(offset.Minutes()), now, tenantID, appointmentID, now.Add(-expiry))
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	// MySQL reports 1 for an inserted row, 2 for an updated one and 0 when
+	// the claim was left as it was.
+	return rowsAffected == 1 || rowsAffected == 2, nil
+}
+
+func (s *sqlStore) MarkSent(tenantID int, appointmentID int, offset time.Duration) error {
+	query := `
+		UPDATE appointment_reminders SET Status = 'sent', SentAt = UTC_TIMESTAMP()
+		WHERE tenants_Id = ? AND appointments_Id = ? AND OffsetMinutes = ?;
+	`
+	res, err := s.db.Exec(query, tenantID, appointmentID, int(offset.Minutes()))
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Reminder not found")
+	}
+	return nil
+}
+
+// Release drops a pending claim so the reminder is tried again later.
+func (s *sqlStore) Release(tenantID int, appointmentID int, offset time.Duration) error {
+	query := `
+		DELETE FROM appointment_reminders
+		WHERE tenants_Id = ? AND appointments_Id = ? AND OffsetMinutes = ? AND Status = 'pending';
+	`
+	_, err := s.db.Exec(query, tenantID, appointmentID, int(offset.Minutes()))
+	return err
+}
@@ -0,0 +1,12 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type ResourceStoreInterface interface {
+	Read(tenantID int, id int) (domain.Resource, error)
+	Create(tenantID int, resource domain.Resource) error
+	Update(tenantID int, resource domain.Resource) error
+	Delete(tenantID int, id int) error
+	GetAll(tenantID int) ([]domain.Resource, error)
+	Search(tenantID int, filter domain.ResourceFilter) ([]domain.Resource, error)
+}
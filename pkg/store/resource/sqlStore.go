@@ -0,0 +1,164 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) ResourceStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectResources = `
+	SELECT r.Id, r.Name, r.Kind, c.Id, c.Name, c.Address, c.TimeZone
+	FROM resources AS r
+	LEFT JOIN clinics AS c ON r.clinics_Id = c.Id
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanResource(row scanner) (domain.Resource, error) {
+	var resource domain.Resource
+	var clinicID sql.NullInt64
+	var clinicName, clinicAddress, clinicTimeZone sql.NullString
+	err := row.Scan(&resource.Id, &resource.Name, &resource.Kind, &clinicID, &clinicName, &clinicAddress, &clinicTimeZone)
+	if err != nil {
+		return domain.Resource{}, err
+	}
+	resource.Clinic.Id = int(clinicID.Int64)
+	resource.Clinic.Name = clinicName.String
+	resource.Clinic.Address = clinicAddress.String
+	resource.Clinic.TimeZone = clinicTimeZone.String
+	return resource, nil
+}
+
+// nullableID maps the zero id used by the domain to a NULL foreign key.
+func nullableID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.Resource, error) {
+	query := selectResources + "WHERE r.tenants_Id = ? AND r.Id = ?;"
+	row := s.db.QueryRow(query, tenantID, id)
+	resource, err := scanResource(row)
+	if err != nil {
+		return domain.Resource{}, err
+	}
+	return resource, nil
+}
+
+func (s *sqlStore) Create(tenantID int, resource domain.Resource) error {
+	query := "INSERT INTO resources (tenants_Id, Name, Kind, clinics_Id) VALUES (?, ?, ?, ?);"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(tenantID, resource.Name, resource.Kind, nullableID(resource.Clinic.Id))
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("no rows affected")
+	}
+	return nil
+}
+
+func (s *sqlStore) Update(tenantID int, resource domain.Resource) error {
+	query := "UPDATE resources SET Name = ?, Kind = ?, clinics_Id = ? WHERE tenants_Id = ? AND Id = ?;"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	res, err := stmt.Exec(resource.Name, resource.Kind, nullableID(resource.Clinic.Id), tenantID, resource.Id)
+	if err != nil {
+		return err
+	}
+	_, err = res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *sqlStore) Delete(tenantID int, id int) error {
+	query := "DELETE FROM resources WHERE tenants_Id = ? AND Id = ?;"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	res, err := stmt.Exec(tenantID, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Resource not found")
+	}
+	return nil
+}
+
+func (s *sqlStore) GetAll(tenantID int) ([]domain.Resource, error) {
+	query := selectResources + "WHERE r.tenants_Id = ? ORDER BY r.Kind, r.Name;"
+	return s.queryResources(query, tenantID)
+}
+
+func (s *sqlStore) Search(tenantID int, filter domain.ResourceFilter) ([]domain.Resource, error) {
+	query := selectResources + "WHERE r.tenants_Id = ?"
+	args := []any{tenantID}
+	if filter.Kind != "" {
+		query += " AND r.Kind = ?"
+		args = append(args, filter.Kind)
+	}
+	if filter.ClinicId != 0 {
+		query += " AND r.clinics_Id = ?"
+		args = append(args, filter.ClinicId)
+	}
+	query += " ORDER BY r.Kind, r.Name;"
+	return s.queryResources(query, args...)
+}
+
+func (s *sqlStore) queryResources(query string, args ...any) ([]domain.Resource, error) {
+	var resources []domain.Resource
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		resource, err := scanResource(rows)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,10 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type ScheduleStoreInterface interface {
+	Read(tenantID int, id int) (domain.Schedule, error)
+	Create(tenantID int, schedule domain.Schedule) error
+	Delete(tenantID int, id int) error
+	GetByDentist(tenantID int, dentistID int) ([]domain.Schedule, error)
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) ScheduleStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectSchedules = `
+	SELECT s.Id, s.dentists_Id, s.Weekday, s.StartHour, s.EndHour, c.Id, c.Name, c.Address, c.TimeZone
+	FROM dentist_schedules AS s
+	INNER JOIN clinics AS c ON s.clinics_Id = c.Id
+`
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.Schedule, error) {
+	var schedule domain.Schedule
+	query := selectSchedules + "WHERE s.tenants_Id = ? AND s.Id = ?;"
+	row := s.db.QueryRow(query, tenantID, id)
+	err := row.Scan(&schedule.Id, &schedule.DentistId, &schedule.Weekday, &schedule.StartHour, &schedule.EndHour,
+		&schedule.Clinic.Id, &schedule.Clinic.Name, &schedule.Clinic.Address, &schedule.Clinic.TimeZone)
+	if err != nil {
+		return domain.Schedule{}, err
+	}
+	return schedule, nil
+}
+
+func (s *sqlStore) Create(tenantID int, schedule domain.Schedule) error {
+	query := "INSERT INTO dentist_schedules (tenants_Id, dentists_Id, clinics_Id, Weekday, StartHour, EndHour) VALUES (?, ?, ?, ?, ?, ?);"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(tenantID, schedule.DentistId, schedule.Clinic.Id, schedule.Weekday, schedule.StartHour, schedule.EndHour)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("no rows affected")
+	}
+	return nil
+}
+
+func (s *sqlStore) Delete(tenantID int, id int) error {
+	query := "DELETE FROM dentist_schedules WHERE tenants_Id = ? AND Id = ?;"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	res, err := stmt.Exec(tenantID, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Schedule not found")
+	}
+	return nil
+}
+
+func (s *sqlStore) GetByDentist(tenantID int, dentistID int) ([]domain.Schedule, error) {
+	var schedules []domain.Schedule
+	query := selectSchedules + "WHERE s.tenants_Id = ? AND s.dentists_Id = ? ORDER BY s.Weekday, s.StartHour;"
+	rows, err := s.db.Query(query, tenantID, dentistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schedule domain.Schedule
+		if err := rows.Scan(&schedule.Id, &schedule.DentistId, &schedule.Weekday, &schedule.StartHour, &schedule.EndHour,
+			&schedule.Clinic.Id, &schedule.Clinic.Name, &schedule.Clinic.Address, &schedule.Clinic.TimeZone); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
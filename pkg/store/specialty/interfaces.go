@@ -0,0 +1,14 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type SpecialtyStoreInterface interface {
+	Read(tenantID int, id int) (domain.Specialty, error)
+	ReadByName(tenantID int, name string) (domain.Specialty, error)
+	Create(tenantID int, specialty domain.Specialty) error
+	Delete(tenantID int, id int) error
+	GetAll(tenantID int) ([]domain.Specialty, error)
+	Exists(tenantID int, name string) (bool, error)
+	AddToDentist(tenantID int, dentistID int, specialtyID int) error
+	RemoveFromDentist(tenantID int, dentistID int, specialtyID int) error
+}
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) SpecialtyStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.Specialty, error) {
+	var specialty domain.Specialty
+	query := "SELECT Id, Name FROM specialties WHERE tenants_Id = ? AND Id = ?;"
+	row := s.db.QueryRow(query, tenantID, id)
+	err := row.Scan(&specialty.Id, &specialty.Name)
+	if err != nil {
+		return domain.Specialty{}, err
+	}
+	return specialty, nil
+}
+
+func (s *sqlStore) ReadByName(tenantID int, name string) (domain.Specialty, error) {
+	var specialty domain.Specialty
+	query := "SELECT Id, Name FROM specialties WHERE tenants_Id = ? AND Name = ?;"
+	row := s.db.QueryRow(query, tenantID, name)
+	err := row.Scan(&specialty.Id, &specialty.Name)
+	if err != nil {
+		return domain.Specialty{}, err
+	}
+	return specialty, nil
+}
+
+func (s *sqlStore) Create(tenantID int, specialty domain.Specialty) error {
+	query := "INSERT INTO specialties (tenants_Id, Name) VALUES (?, ?);"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(tenantID, specialty.Name)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("no rows affected")
+	}
+	return nil
+}
+
+func (s *sqlStore) Delete(tenantID int, id int) error {
+	query := "DELETE FROM specialties WHERE tenants_Id = ? AND Id = ?;"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	res, err := stmt.Exec(tenantID, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Specialty not found")
+	}
+	return nil
+}
+
+func (s *sqlStore) GetAll(tenantID int) ([]domain.Specialty, error) {
+	var specialties []domain.Specialty
+	query := "SELECT Id, Name FROM specialties WHERE tenants_Id = ? ORDER BY Name;"
+	rows, err := s.db.Query(query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var specialty domain.Specialty
+		if err := rows.Scan(&specialty.Id, &specialty.Name); err != nil {
+			return nil, err
+		}
+		specialties = append(specialties, specialty)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return specialties, nil
+}
+
+func (s *sqlStore) Exists(tenantID int, name string) (bool, error) {
+	var id int
+	query := "SELECT Id FROM specialties WHERE tenants_Id = ? AND Name = ?;"
+	row := s.db.QueryRow(query, tenantID, name)
+	err := row.Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return id > 0, nil
+}
+
+func (s *sqlStore) AddToDentist(tenantID int, dentistID int, specialtyID int) error {
+	query := `
+		INSERT IGNORE INTO dentists_specialties (dentists_Id, specialties_Id)
+		SELECT d.Id, sp.Id
+		FROM dentists AS d, specialties AS sp
+		WHERE d.tenants_Id = ? AND d.Id = ? AND sp.tenants_Id = ? AND sp.Id = ?;
+	`
+	_, err := s.db.Exec(query, tenantID, dentistID, tenantID, specialtyID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *sqlStore) RemoveFromDentist(tenantID int, dentistID int, specialtyID int) error {
+	query := `
+		DELETE ds FROM dentists_specialties AS ds
+		INNER JOIN dentists AS d ON ds.dentists_Id = d.Id
+		WHERE d.tenants_Id = ? AND ds.dentists_Id = ? AND ds.specialties_Id = ?;
+	`
+	res, err := s.db.Exec(query, tenantID, dentistID, specialtyID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Dentist does not hold this specialty")
+	}
+	return nil
+}
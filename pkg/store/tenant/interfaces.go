@@ -0,0 +1,12 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type TenantStoreInterface interface {
+	Read(id int) (domain.Tenant, error)
+	ReadByTokenHash(tokenHash string) (domain.Tenant, error)
+	Create(tenant domain.Tenant, tokenHash string) (int, error)
+	UpdateTokenHash(id int, tokenHash string) error
+	Disable(id int) error
+	GetAll() ([]domain.Tenant, error)
+}
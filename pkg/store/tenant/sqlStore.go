@@ -0,0 +1,115 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) TenantStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectTenants = "SELECT Id, Name, CreatedAt, DisabledAt FROM tenants "
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTenant(row scanner) (domain.Tenant, error) {
+	var tenant domain.Tenant
+	var disabledAt sql.NullString
+	if err := row.Scan(&tenant.Id, &tenant.Name, &tenant.CreatedAt, &disabledAt); err != nil {
+		return domain.Tenant{}, err
+	}
+	tenant.DisabledAt = disabledAt.String
+	return tenant, nil
+}
+
+func (s *sqlStore) Read(id int) (domain.Tenant, error) {
+	return scanTenant(s.db.QueryRow(selectTenants+"WHERE Id = ?;", id))
+}
+
+// ReadByTokenHash only resolves active tenants, so the token of a disabled
+// tenant is rejected.
+func (s *sqlStore) ReadByTokenHash(tokenHash string) (domain.Tenant, error) {
+	return scanTenant(s.db.QueryRow(selectTenants+"WHERE TokenHash = ? AND DisabledAt IS NULL;", tokenHash))
+}
+
+func (s *sqlStore) Create(tenant domain.Tenant, tokenHash string) (int, error) {
+	query := "INSERT INTO tenants (Name, TokenHash) VALUES (?, ?);"
+	res, err := s.db.Exec(query, tenant.Name, tokenHash)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func (s *sqlStore) UpdateTokenHash(id int, tokenHash string) error {
+	query := "UPDATE tenants SET TokenHash = ? WHERE Id = ? AND DisabledAt IS NULL;"
+	res, err := s.db.Exec(query, tokenHash, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Tenant not found")
+	}
+	return nil
+}
+
+// Disable keeps the tenant and its records, which may have to be retained,
+// but stops its token from working. Deleting the rows is left to the DBA.
+func (s *sqlStore) Disable(id int) error {
+	query := "UPDATE tenants SET DisabledAt = UTC_TIMESTAMP() WHERE Id = ? AND DisabledAt IS NULL;"
+	res, err := s.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Tenant not found")
+	}
+	return nil
+}
+
+func (s *sqlStore) GetAll() ([]domain.Tenant, error) {
+	var tenants []domain.Tenant
+	rows, err := s.db.Query(selectTenants + "ORDER BY Id;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tenant, err := scanTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tenants, nil
+}
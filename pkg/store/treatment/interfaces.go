@@ -0,0 +1,11 @@
+package store
+
+import "proyecto_final_go/internal/domain"
+
+type TreatmentStoreInterface interface {
+	Read(tenantID int, id int) (domain.Treatment, error)
+	Create(tenantID int, treatment domain.Treatment) error
+	Update(tenantID int, treatment domain.Treatment) error
+	Delete(tenantID int, id int) error
+	GetAll(tenantID int) ([]domain.Treatment, error)
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) TreatmentStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.Treatment, error) {
+	query := `
+		SELECT t.Id, t.Name, t.Price, t.TaxRate, s.Id, s.Name
+		FROM treatments AS t
+		LEFT JOIN specialties AS s ON t.specialties_Id = s.Id
+		WHERE t.tenants_Id = ? AND t.Id = ?;
+	`
+	row := s.db.QueryRow(query, tenantID, id)
+	treatment, err := scanTreatment(row)
+	if err != nil {
+		return domain.Treatment{}, err
+	}
+	return treatment, nil
+}
+
+func (s *sqlStore) Create(tenantID int, treatment domain.Treatment) error {
+	query := "INSERT INTO treatments (tenants_Id, Name, specialties_Id, Price, TaxRate) VALUES (?, ?, ?, ?, ?);"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(tenantID, treatment.Name, nullableID(treatment.Specialty.Id), treatment.Price, treatment.TaxRate)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("no rows affected")
+	}
+	return nil
+}
+
+func (s *sqlStore) Update(tenantID int, treatment domain.Treatment) error {
+	query := "UPDATE treatments SET Name = ?, specialties_Id = ?, Price = ?, TaxRate = ? WHERE tenants_Id = ? AND Id = ?;"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	res, err := stmt.Exec(treatment.Name, nullableID(treatment.Specialty.Id), treatment.Price, treatment.TaxRate, tenantID, treatment.Id)
+	if err != nil {
+		return err
+	}
+	_, err = res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *sqlStore) Delete(tenantID int, id int) error {
+	query := "DELETE FROM treatments WHERE tenants_Id = ? AND Id = ?;"
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	res, err := stmt.Exec(tenantID, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Treatment not found")
+	}
+	return nil
+}
+
+func (s *sqlStore) GetAll(tenantID int) ([]domain.Treatment, error) {
+	var treatments []domain.Treatment
+	query := `
+		SELECT t.Id, t.Name, t.Price, t.TaxRate, s.Id, s.Name
+		FROM treatments AS t
+		LEFT JOIN specialties AS s ON t.specialties_Id = s.Id
+		WHERE t.tenants_Id = ?
+		ORDER BY t.Name;
+	`
+	rows, err := s.db.Query(query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		treatment, err := scanTreatment(rows)
+		if err != nil {
+			return nil, err
+		}
+		treatments = append(treatments, treatment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return treatments, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTreatment(row scanner) (domain.Treatment, error) {
+	var treatment domain.Treatment
+	var specialtyID sql.NullInt64
+	var specialtyName sql.NullString
+	err := row.Scan(&treatment.Id, &treatment.Name, &treatment.Price, &treatment.TaxRate, &specialtyID, &specialtyName)
+	if err != nil {
+		return domain.Treatment{}, err
+	}
+	treatment.Specialty.Id = int(specialtyID.Int64)
+	treatment.Specialty.Name = specialtyName.String
+	return treatment, nil
+}
+
+// nullableID maps the zero id used by the domain to a NULL foreign key.
+func nullableID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
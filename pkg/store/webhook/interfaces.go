@@ -0,0 +1,20 @@
+package store
+
+import (
+	"proyecto_final_go/internal/domain"
+	"time"
+)
+
+type WebhookStoreInterface interface {
+	Read(tenantID int, id int) (domain.Webhook, error)
+	Create(tenantID int, webhook domain.Webhook) (int, error)
+	Update(tenantID int, webhook domain.Webhook) error
+	Delete(tenantID int, id int) error
+	GetAll(tenantID int) ([]domain.Webhook, error)
+	CreateDelivery(tenantID int, webhookID int, eventID int64) error
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error)
+	RecordAttempt(delivery domain.WebhookDelivery, attempt domain.WebhookAttempt) error
+	ReadDelivery(tenantID int, webhookID int, id int64) (domain.WebhookDelivery, error)
+	GetDeliveries(tenantID int, webhookID int) ([]domain.WebhookDelivery, error)
+	ResetDelivery(tenantID int, webhookID int, id int64, now time.Time) error
+}
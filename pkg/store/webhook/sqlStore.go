@@ -0,0 +1,322 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"proyecto_final_go/internal/domain"
+	"strings"
+	"time"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+func NewSqlStore(db *sql.DB) WebhookStoreInterface {
+	return &sqlStore{
+		db: db,
+	}
+}
+
+//-----------------------------------
+
+const selectWebhooks = "SELECT Id, URL, Secret, EventTypes, Active, CreatedAt FROM webhooks "
+
+const selectDeliveries = `
+	SELECT d.Id, d.tenants_Id, d.webhooks_Id, d.Status, d.Attempts, d.NextAttemptAt, d.LastStatusCode, d.LastError, d.DeliveredAt,
+		e.Id, e.tenants_Id, e.AggregateType, e.AggregateId, e.Type, e.Payload, e.CreatedAt
+	FROM webhook_deliveries AS d
+	INNER JOIN outbox_events AS e ON d.events_Id = e.Id
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhook(row scanner) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	var eventTypes string
+	err := row.Scan(&webhook.Id, &webhook.URL, &webhook.Secret, &eventTypes, &webhook.Active, &webhook.CreatedAt)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	webhook.EventTypes = strings.Split(eventTypes, ",")
+	return webhook, nil
+}
+
+func scanDelivery(row scanner) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	var lastError sql.NullString
+	var deliveredAt sql.NullTime
+	event := &delivery.Event
+	err := row.Scan(&delivery.Id, &delivery.TenantId, &delivery.WebhookId, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastStatusCode, &lastError, &deliveredAt,
+		&event.Id, &event.TenantId, &event.AggregateType, &event.AggregateId, &event.Type, &event.Payload, &event.CreatedAt)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	delivery.LastError = lastError.String
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	delivery.AttemptLog = []domain.WebhookAttempt{}
+	return delivery, nil
+}
+
+func (s *sqlStore) Read(tenantID int, id int) (domain.Webhook, error) {
+	query := selectWebhooks + "WHERE tenants_Id = ? AND Id = ?;"
+	row := s.db.QueryRow(query, tenantID, id)
+	webhook, err := scanWebhook(row)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (s *sqlStore) Create(tenantID int, webhook domain.Webhook) (int, error) {
+	query := "INSERT INTO webhooks (tenants_Id, URL, Secret, EventTypes, Active, CreatedAt) VALUES (?, ?, ?, ?, ?, ?);"
+	res, err := s.db.Exec(query, tenantID, webhook.URL, webhook.Secret, strings.Join(webhook.EventTypes, ","), webhook.Active, webhook.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func (s *sqlStore) Update(tenantID int, webhook domain.Webhook) error {
+	query := "UPDATE webhooks SET URL = ?, Secret = ?, EventTypes = ?, Active = ? WHERE tenants_Id = ? AND Id = ?;"
+	_, err := s.db.Exec(query, webhook.URL, webhook.Secret, strings.Join(webhook.EventTypes, ","), webhook.Active, tenantID, webhook.Id)
+	return err
+}
+
+func (s *sqlStore) Delete(tenantID int, id int) error {
+	query := "DELETE FROM webhooks WHERE tenants_Id = ? AND Id = ?;"
+	res, err := s.db.Exec(query, tenantID, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Webhook not found")
+	}
+	return nil
+}
+
+func (s *sqlStore) GetAll(tenantID int) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	query := selectWebhooks + "WHERE tenants_Id = ? ORDER BY Id;"
+	rows, err := s.db.Query(query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// CreateDelivery queues an event for a webhook. Queueing the same event twice
+// is a no-op, so the outbox can safely redeliver it.
+func (s *sqlStore) CreateDelivery(tenantID int, webhookID int, eventID int64) error {
+	now := time.Now().UTC()
+	query := `
+		INSERT IGNORE INTO webhook_deliveries (tenants_Id, webhooks_Id, events_Id, Status, NextAttemptAt, CreatedAt)
+		SELECT w.tenants_Id, w.Id, ?, 'pending', ?, ?
+		FROM webhooks AS w
+		WHERE w.tenants_Id = ? AND w.Id = ?;
+	`
+	_, err := s.db.Exec(query, eventID, now, now, tenantID, webhookID)
+	return err
+}
+
+// ClaimDue leases up to limit pending deliveries of every tenant that are due
+// at now. Their next attempt is pushed back by lease, so other workers skip
+// them while they are being sent, and a crashed worker's deliveries are
+// retried once the lease expires.
+func (s *sqlStore) ClaimDue(now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := selectDeliveries + `
+		WHERE d.Status = 'pending' AND d.NextAttemptAt <= ?
+		ORDER BY d.Id
+		LIMIT ?
+		FOR UPDATE OF d SKIP LOCKED;
+	`
+	rows, err := tx.Query(query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	leasedUntil := now.Add(lease)
+	for i := range deliveries {
+		_, err := tx.Exec("UPDATE webhook_deliveries SET NextAttemptAt = ? WHERE Id = ?;", leasedUntil, deliveries[i].Id)
+		if err != nil {
+			return nil, err
+		}
+		deliveries[i].NextAttemptAt = leasedUntil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt appends an attempt to the delivery log and stores the new
+// state of the delivery.
+func (s *sqlStore) RecordAttempt(delivery domain.WebhookDelivery, attempt domain.WebhookAttempt) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO webhook_attempts (deliveries_Id, StatusCode, Error, DurationMs, CreatedAt) VALUES (?, ?, ?, ?, ?);"
+	_, err = tx.Exec(query, delivery.Id, attempt.StatusCode, nullableString(attempt.Error), attempt.DurationMs, attempt.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		UPDATE webhook_deliveries
+		SET Status = ?, Attempts = ?, NextAttemptAt = ?, LastStatusCode = ?, LastError = ?, DeliveredAt = ?
+		WHERE tenants_Id = ? AND Id = ?;
+	`
+	_, err = tx.Exec(query, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, nullableString(delivery.LastError), delivery.DeliveredAt, delivery.TenantId, delivery.Id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStore) ReadDelivery(tenantID int, webhookID int, id int64) (domain.WebhookDelivery, error) {
+	query := selectDeliveries + "WHERE d.tenants_Id = ? AND d.webhooks_Id = ? AND d.Id = ?;"
+	row := s.db.QueryRow(query, tenantID, webhookID, id)
+	delivery, err := scanDelivery(row)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	delivery.AttemptLog, err = s.readAttempts(delivery.Id)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+// GetDeliveries returns the latest deliveries of a webhook, newest first.
+func (s *sqlStore) GetDeliveries(tenantID int, webhookID int) ([]domain.WebhookDelivery, error) {
+	deliveries := []domain.WebhookDelivery{}
+	query := selectDeliveries + "WHERE d.tenants_Id = ? AND d.webhooks_Id = ? ORDER BY d.Id DESC LIMIT 100;"
+	rows, err := s.db.Query(query, tenantID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range deliveries {
+		deliveries[i].AttemptLog, err = s.readAttempts(deliveries[i].Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return deliveries, nil
+}
+
+// ResetDelivery queues a delivery again with a fresh retry budget. The log of
+// the previous attempts is kept.
+func (s *sqlStore) ResetDelivery(tenantID int, webhookID int, id int64, now time.Time) error {
+	query := `
+		UPDATE webhook_deliveries SET Status = 'pending', Attempts = 0, NextAttemptAt = ?
+		WHERE tenants_Id = ? AND webhooks_Id = ? AND Id = ?;
+	`
+	res, err := s.db.Exec(query, now, tenantID, webhookID, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("Delivery not found")
+	}
+	return nil
+}
+
+func (s *sqlStore) readAttempts(deliveryID int64) ([]domain.WebhookAttempt, error) {
+	attempts := []domain.WebhookAttempt{}
+	query := "SELECT StatusCode, Error, DurationMs, CreatedAt FROM webhook_attempts WHERE deliveries_Id = ? ORDER BY Id;"
+	rows, err := s.db.Query(query, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attempt domain.WebhookAttempt
+		var attemptError sql.NullString
+		if err := rows.Scan(&attempt.StatusCode, &attemptError, &attempt.DurationMs, &attempt.CreatedAt); err != nil {
+			return nil, err
+		}
+		attempt.Error = attemptError.String
+		attempts = append(attempts, attempt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func nullableString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,190 @@
+package stream
+
+import (
+	"context"
+	"log"
+	"proyecto_final_go/internal/domain"
+	"sync"
+	"time"
+)
+
+// GapTimeout is how long the broker waits for a missing outbox id. Ids are
+// allocated when an event is inserted, so a transaction, e.g. a bulk import,
+// may commit its events after one with a higher id was already read. Events
+// are published in id order: those after a missing id are held back until it
+// is committed, or until GapTimeout passes, as the ids of rolled back
+// transactions never show up.
+const GapTimeout = time.Minute
+
+// Source reads the committed events in id order.
+type Source interface {
+	LastID() (int64, error)
+	GetAfter(afterID int64, limit int) ([]domain.Event, error)
+}
+
+// Broker tails the outbox and fans every new event out to the subscribers of
+// its tenant. Reading the outbox table, instead of being an outbox handler,
+// lets every instance serve live streams whichever instance dispatched the
+// event.
+type Broker struct {
+	source    Source
+	interval  time.Duration
+	batchSize int
+	buffer    int
+	now       func() time.Time
+	missing   map[int64]time.Time
+
+	mu          sync.Mutex
+	published   int64
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	tenantID int
+	events   chan domain.Event
+}
+
+// NewBroker polls the source every interval.
+func NewBroker(source Source, interval time.Duration) *Broker {
+	return &Broker{
+		source:      source,
+		interval:    interval,
+		batchSize:   500,
+		buffer:      256,
+		now:         time.Now,
+		missing:     make(map[int64]time.Time),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Subscribe returns the live events of a tenant, the id up to which every
+// event was already published, and a function that ends the subscription.
+// Events are published in id order, so the events after that id all reach
+// the channel. The channel is closed when the subscriber falls too far
+// behind; clients are expected to reconnect and resume from the last event
+// they received.
+func (b *Broker) Subscribe(tenantID int) (<-chan domain.Event, int64, func()) {
+	sub := &subscriber{tenantID: tenantID, events: make(chan domain.Event, b.buffer)}
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	published := b.published
+	b.mu.Unlock()
+	return sub.events, published, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(sub)
+	}
+}
+
+// Run tails the source until ctx is done. Only events committed after Run
+// starts are published.
+func (b *Broker) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	started := false
+	for {
+		var err error
+		if !started {
+			var lastID int64
+			lastID, err = b.source.LastID()
+			if err == nil {
+				b.mu.Lock()
+				b.published = lastID
+				b.mu.Unlock()
+				started = true
+			}
+		} else {
+			err = b.poll()
+		}
+		if err != nil {
+			log.Printf("stream: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			b.closeAll()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll publishes the events after the last one published, in id order, up
+// to the first missing id that is still within GapTimeout; that id and the
+// events after it are read again on the next poll.
+func (b *Broker) poll() error {
+	b.mu.Lock()
+	cursor := b.published
+	b.mu.Unlock()
+	for {
+		events, err := b.source.GetAfter(cursor, b.batchSize)
+		if err != nil {
+			return err
+		}
+		now := b.now()
+		for _, event := range events {
+			if event.Id > cursor+1 && !b.gapExpired(cursor+1, event.Id, now) {
+				return nil
+			}
+			b.publish(event)
+			cursor = event.Id
+		}
+		for id := range b.missing {
+			if id <= cursor {
+				delete(b.missing, id)
+			}
+		}
+		if len(events) < b.batchSize {
+			return nil
+		}
+	}
+}
+
+// gapExpired notes when the ids from first up to before were found missing
+// and reports whether all of them have been missing for GapTimeout.
+func (b *Broker) gapExpired(first int64, before int64, now time.Time) bool {
+	expired := true
+	for id := first; id < before; id++ {
+		since, ok := b.missing[id]
+		if !ok {
+			b.missing[id] = now
+			since = now
+		}
+		if now.Sub(since) < GapTimeout {
+			expired = false
+		}
+	}
+	return expired
+}
+
+func (b *Broker) publish(event domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = event.Id
+	for sub := range b.subscribers {
+		if sub.tenantID != event.TenantId {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			b.remove(sub)
+		}
+	}
+}
+
+// remove must be called with mu held.
+func (b *Broker) remove(sub *subscriber) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
+
+func (b *Broker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
@@ -0,0 +1,105 @@
+package stream
+
+import (
+	"proyecto_final_go/internal/domain"
+	"sort"
+	"testing"
+	"time"
+)
+
+// fakeSource holds the committed events; events are added out of id order to
+// mimic transactions committing in a different order than they got their ids.
+type fakeSource struct {
+	events []domain.Event
+}
+
+func (s *fakeSource) LastID() (int64, error) {
+	return 0, nil
+}
+
+func (s *fakeSource) GetAfter(afterID int64, limit int) ([]domain.Event, error) {
+	sort.Slice(s.events, func(i, j int) bool { return s.events[i].Id < s.events[j].Id })
+	var events []domain.Event
+	for _, event := range s.events {
+		if event.Id > afterID && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// received drains the events already published to a subscriber.
+func received(events <-chan domain.Event) []int64 {
+	var ids []int64
+	for len(events) > 0 {
+		ids = append(ids, (<-events).Id)
+	}
+	return ids
+}
+
+func TestBrokerWaitsForEventsCommittedOutOfOrder(t *testing.T) {
+	t0 := time.Date(2024, 3, 30, 10, 0, 0, 0, time.UTC)
+	now := t0
+	source := &fakeSource{}
+	b := NewBroker(source, time.Second)
+	b.now = func() time.Time { return now }
+	b.published = 5
+	events, published, unsubscribe := b.Subscribe(1)
+	defer unsubscribe()
+	if published != 5 {
+		t.Fatalf("Subscribe returned %d as published, want 5", published)
+	}
+
+	// Event 6 got its id first but its transaction, a long import, commits
+	// after event 7 and long after it was recorded.
+	source.events = append(source.events, domain.Event{Id: 7, TenantId: 1, CreatedAt: t0})
+	for _, now = range []time.Time{t0, t0.Add(GapTimeout / 2)} {
+		if err := b.poll(); err != nil {
+			t.Fatal(err)
+		}
+		if ids := received(events); len(ids) != 0 || b.published != 5 {
+			t.Fatalf("published %v up to %d while event 6 was missing, want nothing", ids, b.published)
+		}
+	}
+
+	source.events = append(source.events, domain.Event{Id: 6, TenantId: 1, CreatedAt: t0.Add(-time.Minute)})
+	if err := b.poll(); err != nil {
+		t.Fatal(err)
+	}
+	if ids := received(events); len(ids) != 2 || ids[0] != 6 || ids[1] != 7 {
+		t.Fatalf("published %v, want [6 7]", ids)
+	}
+	if _, published, unsubscribe := b.Subscribe(1); published != 7 {
+		t.Fatalf("Subscribe returned %d as published, want 7", published)
+	} else {
+		unsubscribe()
+	}
+}
+
+func TestBrokerGivesUpOnIdsOfRolledBackTransactions(t *testing.T) {
+	t0 := time.Date(2024, 3, 30, 10, 0, 0, 0, time.UTC)
+	now := t0
+	source := &fakeSource{events: []domain.Event{{Id: 1, TenantId: 1}, {Id: 4, TenantId: 1}, {Id: 5, TenantId: 2}}}
+	b := NewBroker(source, time.Second)
+	b.now = func() time.Time { return now }
+	events, _, unsubscribe := b.Subscribe(1)
+	defer unsubscribe()
+
+	if err := b.poll(); err != nil {
+		t.Fatal(err)
+	}
+	if ids := received(events); len(ids) != 1 || ids[0] != 1 {
+		t.Fatalf("published %v while 2 and 3 were missing, want [1]", ids)
+	}
+
+	now = t0.Add(GapTimeout)
+	if err := b.poll(); err != nil {
+		t.Fatal(err)
+	}
+	if ids := received(events); len(ids) != 1 || ids[0] != 4 {
+		t.Fatalf("published %v once 2 and 3 timed out, want [4] for the tenant", ids)
+	}
+	if b.published != 5 || len(b.missing) != 0 {
+		t.Fatalf("published up to %d with %d ids still missing, want 5 and none", b.published, len(b.missing))
+	}
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for webhook URLs that resolve to an address
+// inside the network of the API, which tenants must not reach through it.
+var ErrForbiddenAddress = errors.New("webhook: the URL resolves to a loopback, private or link-local address")
+
+// Forbidden reports whether deliveries must not be sent to the address:
+// loopback, private, link-local (cloud metadata services live there), shared
+// carrier-grade NAT, unspecified and multicast addresses.
+func Forbidden(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() ||
+		sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range of RFC 6598.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// CheckURL validates a webhook URL when it is registered: it must be an
+// absolute http or https URL whose host resolves only to public addresses.
+// Sender checks the address again when it connects, as DNS may change.
+func CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("Invalid webhook URL, expected an absolute http or https URL")
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return errors.New("Invalid webhook URL, " + u.Hostname() + " can not be resolved")
+	}
+	for _, addr := range addrs {
+		if Forbidden(addr) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+// newClient returns a client that refuses to connect to the addresses
+// forbidden reports, checked on the resolved address at dial time, and that
+// does not follow redirects, so a receiver can not send the delivery
+// elsewhere.
+func newClient(timeout time.Duration, forbidden func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, c syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || forbidden(addrPort.Addr()) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 2,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
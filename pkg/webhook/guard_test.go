@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestForbidden(t *testing.T) {
+	for _, address := range []string{
+		"127.0.0.1", "::1", "10.1.2.3", "172.16.0.1", "192.168.1.10", "169.254.169.254", "fe80::1",
+		"fd00::1", "0.0.0.0", "::", "100.64.0.1", "224.0.0.1", "::ffff:127.0.0.1", "::ffff:169.254.169.254",
+	} {
+		if !Forbidden(netip.MustParseAddr(address)) {
+			t.Errorf("Forbidden(%s) = false, want true", address)
+		}
+	}
+	for _, address := range []string{"8.8.8.8", "200.45.10.1", "2001:4860:4860::8888", "172.32.0.1"} {
+		if Forbidden(netip.MustParseAddr(address)) {
+			t.Errorf("Forbidden(%s) = true, want false", address)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	for _, rawURL := range []string{"http://127.0.0.1:8080/hook", "http://169.254.169.254/latest/meta-data/", "https://[::1]/hook", "http://10.0.0.5/hook", "http://localhost/hook"} {
+		if err := CheckURL(context.Background(), rawURL); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("CheckURL(%s) = %v, want ErrForbiddenAddress", rawURL, err)
+		}
+	}
+	for _, rawURL := range []string{"ftp://8.8.8.8/hook", "/hook", "http:///hook"} {
+		if err := CheckURL(context.Background(), rawURL); err == nil || errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("CheckURL(%s) = %v, want an invalid URL error", rawURL, err)
+		}
+	}
+	if err := CheckURL(context.Background(), "https://8.8.8.8/hook"); err != nil {
+		t.Errorf("CheckURL of a public address = %v, want nil", err)
+	}
+}
+
+func TestSenderDoesNotConnectToForbiddenAddresses(t *testing.T) {
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer server.Close()
+
+	_, err := NewSender(time.Second).Send(context.Background(), Request{URL: server.URL, Secret: "whsec_test", Body: []byte("{}")})
+	if !errors.Is(err, ErrForbiddenAddress) || received != 0 {
+		t.Fatalf("Send to %s = %v with %d requests received, want ErrForbiddenAddress", server.URL, err, received)
+	}
+}
+
+func TestSenderDoesNotFollowRedirects(t *testing.T) {
+	var redirected int
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected++
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	// Loopback is allowed here to reach the test servers.
+	sender := NewSenderWithClient(newClient(time.Second, func(netip.Addr) bool { return false }))
+	code, err := sender.Send(context.Background(), Request{URL: server.URL, Secret: "whsec_test", Body: []byte("{}")})
+	if err == nil || code != http.StatusTemporaryRedirect || redirected != 0 {
+		t.Fatalf("Send = %d, %v with %d redirected requests, want the 307 as an error and no redirect", code, err, redirected)
+	}
+}
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers sent with every delivery.
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// ErrInvalidSignature is returned by Verify when the signature does not match.
+var ErrInvalidSignature = errors.New("webhook: invalid signature")
+
+// Sign returns the signature header of a body sent at t. The signature is an
+// HMAC-SHA256 of "<unix timestamp>.<body>" keyed with the webhook secret, so
+// receivers can reject both tampered and replayed requests.
+func Sign(secret string, t time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + digest(secret, timestamp, body)
+}
+
+// Verify checks a signature header produced by Sign. Signatures older than
+// tolerance are rejected, a zero tolerance disables the check.
+func Verify(secret string, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || signature == "" {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 && now.Sub(time.Unix(unix, 0)) > tolerance {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(digest(secret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func digest(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Request is one signed delivery.
+type Request struct {
+	URL        string
+	Secret     string
+	DeliveryID int64
+	EventType  string
+	Body       []byte
+}
+
+// Sender posts deliveries to the webhook URLs.
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender gives up on requests that take longer than timeout. It never
+// connects to the addresses Forbidden reports and does not follow redirects.
+func NewSender(timeout time.Duration) *Sender {
+	return NewSenderWithClient(newClient(timeout, Forbidden))
+}
+
+// NewSenderWithClient sends the deliveries with the given client, e.g. the
+// client of an httptest server.
+func NewSenderWithClient(client *http.Client) *Sender {
+	return &Sender{client: client}
+}
+
+// Send posts the request and returns the response status code. Any status
+// outside 2xx is returned as an error along with the code.
+func (s *Sender) Send(ctx context.Context, r Request) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "proyecto-final-go-webhooks")
+	req.Header.Set(SignatureHeader, Sign(r.Secret, time.Now(), r.Body))
+	req.Header.Set(EventHeader, r.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(r.DeliveryID, 10))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}